@@ -0,0 +1,668 @@
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: admin-api-ts-client
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Admin API TypeScript Client
+ *
+ * The API types are generated into types.ts from the daemon's Go types, as
+ * are those of the Go client in pkg/client; regenerate them with go generate
+ * ./pkg/client after changing the API.
+ */
+
+import type {
+  AgentBeacon,
+  AgentStatus,
+  AuditEntry,
+  Autoscaling,
+  BackfillState,
+  BackupManifest,
+  BandwidthReport,
+  BulkResult,
+  CompatibilityReport,
+  Confirmation,
+  ConfirmationState,
+  Connection,
+  CutoverState,
+  DDLChange,
+  DeadLetter,
+  Digest,
+  DigestStatus,
+  ErasureReport,
+  ErasureRequest,
+  Explanation,
+  Fixture,
+  Flag,
+  FleetPipeline,
+  FleetStatus,
+  Handoff,
+  Holdback,
+  ImportReport,
+  IncrementalSnapshot,
+  Info,
+  KeyRange,
+  LogLevelOverride,
+  LogLevels,
+  LookupCacheState,
+  MemoryStatus,
+  OrphanReport,
+  PauseState,
+  Pipeline,
+  PipelineEvent,
+  PipelineHealth,
+  PipelineStatus,
+  PreflightReport,
+  Promotion,
+  ReloadReport,
+  Rollout,
+  Run,
+  RunDiff,
+  Simulation,
+  SimulationRequest,
+  StandbyState,
+  TableState,
+  TeardownReport,
+  TemplateFunctions,
+  Trace,
+  TriggerStatus,
+} from "./types";
+
+export * from "./types";
+
+export type LogLevel = "debug" | "info" | "warn" | "error";
+
+export interface PipelineQuery {
+  selector?: string;
+  /** key:value terms that must all match; a bare key requires the label */
+  labels?: string[];
+  sourceType?: string;
+  targetType?: string;
+  text?: string;
+  sort?: "id" | "priority" | "source.type" | "target.type" | "mode" | `-${string}`;
+  offset?: number;
+  limit?: number;
+}
+
+export interface PipelinePage {
+  pipelines: Pipeline[];
+  /** matching pipelines across all pages */
+  total: number;
+}
+
+export interface CloneOptions {
+  id: string;
+  description?: string;
+  sourceConnection?: string;
+  targetConnection?: string;
+  /** set on the clone; an empty value removes the label */
+  labels?: Record<string, string>;
+}
+
+/** @deprecated use PipelineHealth */
+export type Health = PipelineHealth;
+
+/** @deprecated use LookupCacheState */
+export type LookupCache = LookupCacheState;
+
+export type BulkAction = "pause" | "resume" | "trigger";
+
+/**
+ * EsyncError is a non-success response of the admin API. code is the stable
+ * error code of the problem details, such as not_found or conflict, and
+ * correlationId identifies the request in the daemon's logs and traces.
+ */
+export class EsyncError extends Error {
+  constructor(
+    public readonly status: number,
+    message: string,
+    public readonly code?: string,
+    public readonly correlationId?: string,
+  ) {
+    super(`esync API: ${status} ${message}${correlationId ? ` (correlation ${correlationId})` : ""}`);
+  }
+}
+
+/** EsyncClient calls the admin API of an esync daemon. */
+export class EsyncClient {
+  private readonly baseURL: string;
+  private requestId?: string;
+
+  constructor(baseURL: string, private readonly fetchImpl: typeof fetch = fetch) {
+    this.baseURL = baseURL.replace(/\/+$/, "");
+  }
+
+  /**
+   * withRequestId returns a client whose calls send id as their X-Request-ID;
+   * the daemon logs it and records it with the runs the calls trigger.
+   */
+  withRequestId(id: string): EsyncClient {
+    const client = new EsyncClient(this.baseURL, this.fetchImpl);
+    client.requestId = id;
+    return client;
+  }
+
+  listPipelines(selector?: string): Promise<Pipeline[]> {
+    return this.request("GET", "/pipelines", { selector });
+  }
+
+  async searchPipelines(query: PipelineQuery = {}): Promise<PipelinePage> {
+    const resp = await this.send("GET", "/pipelines", {
+      selector: query.selector,
+      label: query.labels?.join(","),
+      "source.type": query.sourceType,
+      "target.type": query.targetType,
+      text: query.text,
+      sort: query.sort,
+      offset: query.offset === undefined ? undefined : String(query.offset),
+      limit: query.limit === undefined ? undefined : String(query.limit),
+    });
+    const pipelines = JSON.parse(await resp.text()) as Pipeline[];
+    const total = Number(resp.headers.get("Esync-Total-Count") ?? pipelines.length);
+    return { pipelines, total };
+  }
+
+  listConnections(): Promise<Connection[]> {
+    return this.request("GET", "/connections");
+  }
+
+  listAuditEntries(pipeline?: string, limit?: number): Promise<AuditEntry[]> {
+    return this.request("GET", "/audit", { pipeline, limit: limit === undefined ? undefined : String(limit) });
+  }
+
+  orphanReport(): Promise<OrphanReport> {
+    return this.request("GET", "/orphans");
+  }
+
+  checkOrphans(): Promise<OrphanReport> {
+    return this.request("POST", "/orphans");
+  }
+
+  listErasures(): Promise<ErasureRequest[]> {
+    return this.request("GET", "/erasures");
+  }
+
+  startErasure(subject: string, reason?: string, selector?: string): Promise<ErasureRequest> {
+    return this.request("POST", "/erasures", { subject, reason, selector });
+  }
+
+  getErasure(id: string): Promise<ErasureRequest> {
+    return this.request("GET", `/erasures/${encodeURIComponent(id)}`);
+  }
+
+  retryErasure(id: string): Promise<ErasureRequest> {
+    return this.request("POST", `/erasures/${encodeURIComponent(id)}/retry`);
+  }
+
+  getErasureReport(id: string): Promise<ErasureReport> {
+    return this.request("GET", `/erasures/${encodeURIComponent(id)}/report`);
+  }
+
+  async backup(): Promise<ArrayBuffer> {
+    const resp = await this.send("GET", "/backup");
+    return resp.arrayBuffer();
+  }
+
+  async restore(archive: ArrayBuffer | Blob | Uint8Array): Promise<BackupManifest> {
+    const resp = await this.send("POST", "/restore", {}, [], archive);
+    return (await resp.json()) as BackupManifest;
+  }
+
+  async exportPipelines(selector?: string): Promise<ArrayBuffer> {
+    const resp = await this.send("GET", "/pipelines:export", { selector });
+    return resp.arrayBuffer();
+  }
+
+  async importPipelines(
+    bundle: ArrayBuffer | Blob | Uint8Array,
+    onConflict: "skip" | "overwrite" | "rename" = "skip",
+    dryRun = false,
+  ): Promise<ImportReport> {
+    const resp = await this.send("POST", "/pipelines:import", { on_conflict: onConflict, dry_run: dryRun ? "true" : undefined }, [], bundle);
+    return (await resp.json()) as ImportReport;
+  }
+
+  getHandoff(): Promise<Handoff> {
+    return this.request("GET", "/handoff");
+  }
+
+  async beginHandoff(lease?: string): Promise<{ id: string; leaseExpires: string; archive: ArrayBuffer }> {
+    const resp = await this.send("POST", "/handoff", { lease });
+    return {
+      id: resp.headers.get("Esync-Handoff-Id") ?? "",
+      leaseExpires: resp.headers.get("Esync-Handoff-Lease-Expires") ?? "",
+      archive: await resp.arrayBuffer(),
+    };
+  }
+
+  completeHandoff(id: string): Promise<Handoff> {
+    return this.request("POST", `/handoff/${encodeURIComponent(id)}/complete`);
+  }
+
+  abortHandoff(id: string): Promise<Handoff> {
+    return this.request("POST", `/handoff/${encodeURIComponent(id)}/abort`);
+  }
+
+  agentBeacon(): Promise<AgentBeacon> {
+    return this.request("GET", "/agent");
+  }
+
+  listAgents(): Promise<AgentStatus[]> {
+    return this.request("GET", "/agents");
+  }
+
+  getAgent(id: string): Promise<AgentStatus> {
+    return this.request("GET", `/agents/${encodeURIComponent(id)}`);
+  }
+
+  fleetStatus(): Promise<FleetStatus> {
+    return this.request("GET", "/fleet");
+  }
+
+  fleetPipelines(): Promise<FleetPipeline[]> {
+    return this.request("GET", "/fleet/pipelines");
+  }
+
+  reloadFleet(): Promise<FleetStatus> {
+    return this.request("POST", "/fleet:reload");
+  }
+
+  /** days defaults to 7 */
+  bandwidth(days?: number): Promise<BandwidthReport> {
+    return this.request("GET", days ? `/bandwidth?days=${days}` : "/bandwidth");
+  }
+
+  info(): Promise<Info> {
+    return this.request("GET", "/info");
+  }
+
+  /** reloadConfig reloads the daemon config as SIGHUP does */
+  reloadConfig(): Promise<ReloadReport> {
+    return this.request("POST", "/config:reload");
+  }
+
+  connectorSchema(connectorType: string): Promise<Record<string, unknown>> {
+    return this.request("GET", `/schemas/connectors/${encodeURIComponent(connectorType)}.json`);
+  }
+
+  listFlags(): Promise<Flag[]> {
+    return this.request("GET", "/flags");
+  }
+
+  logLevels(): Promise<LogLevels> {
+    return this.request("GET", "/log-levels");
+  }
+
+  /** expires is a duration such as 30m; overrides without one last until removed */
+  setLogLevel(scope: string, level: LogLevel, expires?: string): Promise<LogLevelOverride> {
+    return this.request("POST", `/log-levels/${encodeURIComponent(scope)}`, { level, expires });
+  }
+
+  removeLogLevel(scope: string): Promise<LogLevelOverride> {
+    return this.request("DELETE", `/log-levels/${encodeURIComponent(scope)}`);
+  }
+
+  health(id: string): Promise<PipelineHealth> {
+    return this.request("GET", pipelinePath(id, "health"));
+  }
+
+  /** overview returns the health of every pipeline, worst first. */
+  overview(opts: { level?: string[]; limit?: number } = {}): Promise<PipelineHealth[]> {
+    return this.request("GET", "/overview", {
+      level: opts.level?.join(","),
+      limit: opts.limit === undefined ? undefined : String(opts.limit),
+    });
+  }
+
+  autoscaling(): Promise<Autoscaling> {
+    return this.request("GET", "/autoscaling");
+  }
+
+  /** templateFunctions documents a version of the template function library, the latest by default. */
+  templateFunctions(version?: number): Promise<TemplateFunctions> {
+    return this.request("GET", "/template-functions", {
+      version: version === undefined ? undefined : String(version),
+    });
+  }
+
+  memory(): Promise<MemoryStatus> {
+    return this.request("GET", "/memory");
+  }
+
+  digests(): Promise<DigestStatus[]> {
+    return this.request("GET", "/digests");
+  }
+
+  previewDigest(name: string): Promise<Digest> {
+    return this.request("GET", `/digests/${encodeURIComponent(name)}`);
+  }
+
+  /** Sends a digest now without starting a new period. */
+  sendDigest(name: string): Promise<Digest> {
+    return this.request("POST", `/digests/${encodeURIComponent(name)}/send`);
+  }
+
+  setFlag(name: string, value: boolean | number): Promise<Flag> {
+    return this.request("POST", `/flags/${encodeURIComponent(name)}`, { value: String(value) });
+  }
+
+  /** watch yields status transitions and run events of the pipelines matching selector, starting with a status event per pipeline, until the loop exits or the daemon ends the stream. */
+  async *watch(selector?: string): AsyncGenerator<PipelineEvent> {
+    const resp = await this.send("GET", "/pipelines/watch", { selector });
+    if (!resp.body) return;
+    const reader = resp.body.pipeThrough(new TextDecoderStream()).getReader();
+    let buffer = "";
+    try {
+      for (;;) {
+        const { value, done } = await reader.read();
+        if (done) return;
+        buffer += value;
+        let end: number;
+        while ((end = buffer.indexOf("\n\n")) >= 0) {
+          const data = buffer
+            .slice(0, end)
+            .split("\n")
+            .filter((line) => line.startsWith("data: "))
+            .map((line) => line.slice("data: ".length))
+            .join("\n");
+          buffer = buffer.slice(end + 2);
+          if (data) yield JSON.parse(data) as PipelineEvent;
+        }
+      }
+    } finally {
+      await reader.cancel();
+    }
+  }
+
+  getPipeline(id: string): Promise<Pipeline> {
+    return this.request("GET", pipelinePath(id));
+  }
+
+  clonePipeline(id: string, opts: CloneOptions): Promise<Pipeline> {
+    return this.request("POST", `${pipelinePath(id)}:clone`, {
+      id: opts.id,
+      description: opts.description,
+      source_connection: opts.sourceConnection,
+      target_connection: opts.targetConnection,
+      label: Object.entries(opts.labels ?? {})
+        .map(([key, value]) => `${key}:${value}`)
+        .join(","),
+    });
+  }
+
+  /** promotePipeline carries a pipeline from `from`, the daemon's environment by default, into the overlay of `to`. */
+  promotePipeline(id: string, to: string, from?: string, dryRun = false): Promise<Promotion> {
+    return this.request("POST", `${pipelinePath(id)}:promote`, { from, to, dry_run: dryRun ? "true" : undefined });
+  }
+
+  /** simulatePipeline replays recorded runs through the pipeline, or a candidate definition, against an in-memory target. */
+  async simulatePipeline(id: string, request?: SimulationRequest, speed?: number): Promise<Simulation> {
+    const body = request ? await gzip(JSON.stringify(request)) : undefined;
+    const resp = await this.send("POST", `${pipelinePath(id)}:simulate`, { speed: speed ? String(speed) : undefined }, [], body);
+    return (await resp.json()) as Simulation;
+  }
+
+  explain(id: string): Promise<Explanation> {
+    return this.request("GET", pipelinePath(id, "explain"));
+  }
+
+  status(id: string): Promise<PipelineStatus> {
+    return this.request("GET", pipelinePath(id, "status"));
+  }
+
+  listTables(id: string): Promise<TableState[]> {
+    return this.request("GET", pipelinePath(id, "tables"));
+  }
+
+  checkSchemas(id: string): Promise<CompatibilityReport> {
+    return this.request("GET", pipelinePath(id, "compatibility"));
+  }
+
+  listDDLChanges(id: string): Promise<DDLChange[]> {
+    return this.request("GET", pipelinePath(id, "ddl"));
+  }
+
+  approveDDLChange(id: string, change: string): Promise<DDLChange> {
+    return this.request("POST", pipelinePath(id, `ddl/${encodeURIComponent(change)}/approve`));
+  }
+
+  rejectDDLChange(id: string, change: string): Promise<DDLChange> {
+    return this.request("POST", pipelinePath(id, `ddl/${encodeURIComponent(change)}/reject`));
+  }
+
+  listRuns(id: string): Promise<Run[]> {
+    return this.request("GET", pipelinePath(id, "runs"));
+  }
+
+  /** diffRuns compares run b against run a, each a run ID, "latest" or "last-good". */
+  diffRuns(id: string, a = "last-good", b = "latest"): Promise<RunDiff> {
+    return this.request("GET", pipelinePath(id, `runs/${encodeURIComponent(a)}/diff/${encodeURIComponent(b)}`));
+  }
+
+  triggerRun(id: string): Promise<Run> {
+    return this.request("POST", pipelinePath(id, "runs"));
+  }
+
+  /**
+   * startTrigger runs a pipeline once per idempotency key, waiting up to wait
+   * (e.g. "30s") for the run to finish. Retries with the same key return the
+   * state of the first run.
+   */
+  startTrigger(id: string, key?: string, wait = "10m"): Promise<TriggerStatus> {
+    return this.request("POST", pipelinePath(id, "triggers"), { idempotency_key: key, wait }, [409]);
+  }
+
+  getTrigger(id: string, key: string, wait = "0s"): Promise<TriggerStatus> {
+    return this.request("GET", pipelinePath(id, `triggers/${encodeURIComponent(key)}`), { wait }, [409]);
+  }
+
+  /** recordRun runs a sync pass, saving its input as the replay fixture of the pipeline. */
+  recordRun(id: string): Promise<Run> {
+    return this.request("POST", pipelinePath(id, "runs"), { record: "true" });
+  }
+
+  fixture(id: string): Promise<Fixture> {
+    return this.request("GET", pipelinePath(id, "fixture"));
+  }
+
+  deadLetters(id: string): Promise<DeadLetter[]> {
+    return this.request("GET", pipelinePath(id, "dead-letters"));
+  }
+
+  /** drainDeadLetters empties the dead-letter queue, returning the letters removed. */
+  drainDeadLetters(id: string): Promise<DeadLetter[]> {
+    return this.request("DELETE", pipelinePath(id, "dead-letters"));
+  }
+
+  pause(id: string): Promise<PauseState> {
+    return this.request("POST", pipelinePath(id, "pause"));
+  }
+
+  resume(id: string): Promise<PauseState> {
+    return this.request("POST", pipelinePath(id, "resume"));
+  }
+
+  preflight(id: string): Promise<PreflightReport> {
+    return this.request("GET", pipelinePath(id, "preflight"));
+  }
+
+  /** runPreflight reports failed checks through the report, not as an error. */
+  runPreflight(id: string): Promise<PreflightReport> {
+    return this.request("POST", pipelinePath(id, "preflight"), {}, [422]);
+  }
+
+  backfill(id: string): Promise<BackfillState> {
+    return this.request("GET", pipelinePath(id, "backfill"));
+  }
+
+  startBackfill(id: string): Promise<BackfillState> {
+    return this.request("POST", pipelinePath(id, "backfill"));
+  }
+
+  snapshot(id: string): Promise<IncrementalSnapshot> {
+    return this.request("GET", pipelinePath(id, "snapshot"));
+  }
+
+  /** requestSnapshot re-snapshots the given tables and key range, everything by default, alongside the incremental stream. */
+  requestSnapshot(id: string, tables: string[] = [], range: KeyRange = {}): Promise<IncrementalSnapshot> {
+    return this.request("POST", pipelinePath(id, "snapshot"), {
+      table: tables.join(","),
+      start: range.start,
+      end: range.end,
+    });
+  }
+
+  cancelSnapshot(id: string): Promise<IncrementalSnapshot> {
+    return this.request("DELETE", pipelinePath(id, "snapshot"));
+  }
+
+  cutover(id: string): Promise<CutoverState> {
+    return this.request("GET", pipelinePath(id, "cutover"));
+  }
+
+  startCutover(id: string): Promise<CutoverState> {
+    return this.request("POST", pipelinePath(id, "cutover"));
+  }
+
+  /** teardown releases the source and target resources of a paused pipeline; dryRun only lists them. */
+  teardown(id: string, dryRun = false): Promise<TeardownReport> {
+    return this.request("POST", pipelinePath(id, "teardown"), { dry_run: dryRun ? "true" : undefined });
+  }
+
+  standby(id: string): Promise<StandbyState> {
+    return this.request("GET", pipelinePath(id, "standby"));
+  }
+
+  /** failover releases the standby barrier; force promotes the target even if the final delta fails. */
+  failover(id: string, force = false): Promise<StandbyState> {
+    return this.request("POST", pipelinePath(id, "standby"), { force: force ? "true" : undefined });
+  }
+
+  rearmStandby(id: string): Promise<StandbyState> {
+    return this.request("DELETE", pipelinePath(id, "standby"));
+  }
+
+  rollout(id: string): Promise<Rollout> {
+    return this.request("GET", pipelinePath(id, "rollout"));
+  }
+
+  promoteRollout(id: string): Promise<Rollout> {
+    return this.request("POST", pipelinePath(id, "rollout"));
+  }
+
+  rollBack(id: string): Promise<Rollout> {
+    return this.request("DELETE", pipelinePath(id, "rollout"));
+  }
+
+  lookupCaches(id: string): Promise<LookupCacheState[]> {
+    return this.request("GET", pipelinePath(id, "lookup-cache"));
+  }
+
+  clearLookupCaches(id: string): Promise<LookupCacheState[]> {
+    return this.request("DELETE", pipelinePath(id, "lookup-cache"));
+  }
+
+  holdback(id: string): Promise<Holdback> {
+    return this.request("GET", pipelinePath(id, "holdback"));
+  }
+
+  /** setHoldback holds back writes to the target until released, until a time or for a duration such as 2h. */
+  setHoldback(id: string, opts: { reason?: string; by?: string; until?: string; for?: string } = {}): Promise<Holdback> {
+    return this.request("POST", pipelinePath(id, "holdback"), {
+      reason: opts.reason,
+      by: opts.by,
+      until: opts.until,
+      for: opts.for,
+    });
+  }
+
+  releaseHoldback(id: string): Promise<Holdback> {
+    return this.request("DELETE", pipelinePath(id, "holdback"));
+  }
+
+  confirmation(id: string): Promise<ConfirmationState> {
+    return this.request("GET", pipelinePath(id, "confirmation"));
+  }
+
+  /** confirm confirms the reviewed definition digest of a destructive pipeline and resumes it. */
+  confirm(id: string, definition: string, ticket?: string, by?: string): Promise<Confirmation> {
+    return this.request("POST", pipelinePath(id, "confirmation"), { definition, ticket, by });
+  }
+
+  trace(id: string): Promise<Trace> {
+    return this.request("GET", pipelinePath(id, "trace"));
+  }
+
+  /** startTrace takes a Go duration such as "10m"; it defaults to ten minutes. */
+  startTrace(id: string, duration?: string): Promise<Trace> {
+    return this.request("POST", pipelinePath(id, "trace"), { duration });
+  }
+
+  stopTrace(id: string): Promise<Trace> {
+    return this.request("DELETE", pipelinePath(id, "trace"));
+  }
+
+  bulk(action: BulkAction, selector: string): Promise<BulkResult[]> {
+    return this.request("POST", `/bulk/${action}`, { selector });
+  }
+
+  private async request<T>(
+    method: string,
+    path: string,
+    query: Record<string, string | undefined> = {},
+    accept: number[] = [],
+  ): Promise<T> {
+    const resp = await this.send(method, path, query, accept);
+    return JSON.parse(await resp.text()) as T;
+  }
+
+  private async send(
+    method: string,
+    path: string,
+    query: Record<string, string | undefined> = {},
+    accept: number[] = [],
+    body?: ArrayBuffer | Blob | Uint8Array,
+  ): Promise<Response> {
+    const params = new URLSearchParams();
+    for (const [key, value] of Object.entries(query)) {
+      if (value) params.set(key, value);
+    }
+    const qs = params.toString();
+    const headers: Record<string, string> = { Accept: "application/json" };
+    if (this.requestId) headers["X-Request-ID"] = this.requestId;
+    if (body !== undefined) headers["Content-Type"] = "application/gzip";
+    const resp = await this.fetchImpl(`${this.baseURL}${path}${qs ? `?${qs}` : ""}`, {
+      method,
+      headers,
+      body,
+    });
+
+    if (!resp.ok && !accept.includes(resp.status)) {
+      const text = await resp.text();
+      let message = text.trim();
+      let code: string | undefined;
+      let correlationId = resp.headers.get("X-Correlation-ID") ?? undefined;
+      try {
+        const problem = JSON.parse(text);
+        message = problem.detail ?? problem.error ?? message;
+        code = problem.code;
+        correlationId = problem.correlation_id ?? correlationId;
+      } catch {
+        // non-JSON error body
+      }
+      throw new EsyncError(resp.status, message, code, correlationId);
+    }
+    return resp;
+  }
+}
+
+async function gzip(text: string): Promise<ArrayBuffer> {
+  const stream = new Blob([text]).stream().pipeThrough(new CompressionStream("gzip"));
+  return new Response(stream).arrayBuffer();
+}
+
+function pipelinePath(id: string, resource?: string): string {
+  const path = `/pipelines/${encodeURIComponent(id)}`;
+  return resource ? `${path}/${resource}` : path;
+}
@@ -0,0 +1,49 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: client-generator
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Admin API Client Type Generator
+ */
+
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/machine-native-ops/esync-platform/internal/api"
+)
+
+func main() {
+	root := flag.String("root", ".", "Module root the API types are read from")
+	goOutput := flag.String("go", "", "Output file of the Go client types")
+	tsOutput := flag.String("ts", "", "Output file of the TypeScript client types")
+	flag.Parse()
+
+	goTypes, tsTypes, err := api.GenerateClients(*root)
+	if err != nil {
+		log.Fatalf("Failed to generate client types: %v", err)
+	}
+
+	for _, out := range []struct {
+		path   string
+		source []byte
+	}{{*goOutput, goTypes}, {*tsOutput, tsTypes}} {
+		if out.path == "" {
+			continue
+		}
+		if err := os.WriteFile(out.path, out.source, 0o644); err != nil {
+			log.Fatalf("Failed to write client types: %v", err)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: schema-generator
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Pipeline JSON Schema Generator
+ */
+
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+func main() {
+	output := flag.String("o", "", "Output file (default stdout)")
+	flag.Parse()
+
+	schema, err := registry.GenerateSchema()
+	if err != nil {
+		log.Fatalf("Failed to generate schema: %v", err)
+	}
+	schema = append(schema, '\n')
+
+	if *output == "" {
+		os.Stdout.Write(schema)
+		return
+	}
+	if err := os.WriteFile(*output, schema, 0o644); err != nil {
+		log.Fatalf("Failed to write schema: %v", err)
+	}
+}
@@ -18,42 +18,251 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
+	// Active hours name IANA time zones; hosts may lack a zoneinfo database
+	_ "time/tzdata"
+
+	"github.com/machine-native-ops/esync-platform/internal/buildinfo"
+	"github.com/machine-native-ops/esync-platform/internal/esync"
+	"github.com/machine-native-ops/esync-platform/internal/logging"
 )
 
 var (
-	version = flag.Bool("version", false, "Show version information")
+	version      = flag.Bool("version", false, "Show version information")
+	selfTest     = flag.Bool("self-test", false, "Validate the config, pre-flight check every pipeline and dry-run a batch from each source, print a JSON report and exit 0 when all passed, 1 otherwise")
+	selfTestWait = flag.Duration("self-test-timeout", 5*time.Minute, "Time the self-test may take")
+	configFile   = flag.String("config", "", "YAML daemon config file overriding the flags of the same name; SIGHUP reloads it")
+	logLevel     = flag.String("log-level", "info", "Log level: debug, info, warn or error; the admin API overrides it per module, connector type or pipeline")
+	pipelinesDir = flag.String("pipelines", "pipelines", "Directory containing pipeline definitions")
+	stateDir     = flag.String("state-dir", "data", "Directory for checkpoints and workflow state")
+	metricsAddr  = flag.String("metrics-addr", ":9090", "Address of the metrics and health server")
+	apiAddr      = flag.String("api-addr", ":8080", "Address of the admin API server")
+	webhookAddr  = flag.String("webhook-addr", "", "Address of a separate webhook receiver; webhooks are served by the admin API when empty")
+	environment  = flag.String("env", os.Getenv("ESYNC_ENV"), "Deployment environment selecting pipeline overlays (dev, staging, prod)")
+	metricLabels = flag.String("metric-labels", "", "Comma-separated pipeline label keys exported for metric aggregation")
+	metricsPush  = flag.String("metrics-push", "", "Pushgateway or OTLP/HTTP metrics URL receiving the metrics periodically")
+	pushFormat   = flag.String("metrics-push-format", "pushgateway", "Format of -metrics-push: pushgateway or otlp")
+	pushEvery    = flag.Duration("metrics-push-interval", 30*time.Second, "Interval of metric pushes")
+	pushLabels   = flag.String("metrics-push-labels", "", "Comma-separated key=value grouping labels or resource attributes of pushed metrics")
+	metricsSink  = flag.String("metrics-backend", "prometheus", "Metrics backend besides the metrics endpoint: prometheus, statsd or dogstatsd")
+	statsdAddr   = flag.String("statsd-addr", "127.0.0.1:8125", "host:port of the StatsD daemon or Datadog agent")
+	statsdPrefix = flag.String("statsd-prefix", "", "Prefix of metric names sent to StatsD")
+	statsdTags   = flag.String("statsd-tags", "", "Comma-separated key=value tags added to every DogStatsD metric")
+	flushEvery   = flag.Duration("metrics-flush-interval", 10*time.Second, "Interval of metric flushes to the StatsD backend")
+	secretsDir   = flag.String("secrets-dir", "/run/secrets", "Directory resolving ${secret:NAME} references in connector configs")
+	sentryDSN    = flag.String("sentry-dsn", os.Getenv("SENTRY_DSN"), "Sentry DSN receiving run failures and panics")
+	errorWebhook = flag.String("error-webhook", "", "URL receiving run failures and panics as JSON")
+	alertRoutes  = flag.String("alert-routes", "", "YAML file routing run failures to webhooks by pipeline owner or labels; unmatched failures go to its default route or -error-webhook")
+	nsQuotas     = flag.String("namespace-quotas", "", "YAML file bounding the concurrent runs and apply workers of each pipeline namespace")
+	credRefresh  = flag.Duration("credential-refresh", 30*time.Second, "Interval re-reading referenced secrets to rotate connector credentials (0 disables)")
+	proxy        = flag.String("proxy", os.Getenv("ESYNC_PROXY"), "http, https or socks5 proxy URL for outbound traffic")
+	noProxy      = flag.String("no-proxy", "", "Comma-separated hosts, *.domain wildcards or CIDR ranges reached without the proxy")
+	egressAllow  = flag.String("egress-allow", "", "Comma-separated hosts, *.domain wildcards or CIDR ranges outbound connections may reach")
+	fipsMode     = flag.Bool("fips", os.Getenv("ESYNC_FIPS") == "1", "Restrict TLS, SSH tunnels and cryptography to FIPS approved primitives and reject non-compliant config")
+	pluginCgroup = flag.String("plugin-cgroup", os.Getenv("ESYNC_PLUGIN_CGROUP"), "cgroup v2 group delegated to the daemon for bounding plugin processes, as listed in /proc/self/cgroup")
+	digests      = flag.String("digests", "", "YAML file sending daily or weekly digests of the pipelines of a namespace or team to notify targets")
+	runLedger    = flag.String("run-ledger", "", "YAML file exporting every finished run as a record to a target connector, e.g. a warehouse table")
+	runReports   = flag.String("run-reports", "", "Directory or http(s) object store prefix archiving a report of every run")
+	retainRuns   = flag.Int("retain-runs", 20, "Finished runs kept in memory per pipeline")
+	retainRunAge = flag.Duration("retain-run-age", 0, "Drop finished runs older than this from the run history (0 keeps them)")
+	retainAudit  = flag.Duration("retain-audit", 0, "Prune audit log entries older than this (0 keeps them)")
+	auditMaxSize = flag.Int64("audit-max-bytes", 0, "Prune the oldest audit log entries beyond this size (0 is unbounded)")
+	retainErased = flag.Duration("retain-erasures", 0, "Remove finished erasure requests older than this (0 keeps them)")
+	fixtureAge   = flag.Duration("retain-fixtures", 0, "Remove recorded run fixtures older than this (0 keeps them)")
+	retainReport = flag.Duration("retain-run-reports", 0, "Remove run reports older than this from a local -run-reports directory (0 keeps them)")
+	pruneEvery   = flag.Duration("prune-interval", time.Hour, "Interval of the background retention pruning")
+	backups      = flag.String("backups", "", "Directory or http(s) object store prefix receiving scheduled state backups")
+	backupEvery  = flag.Duration("backup-interval", 24*time.Hour, "Interval of scheduled state backups")
+	retainBackup = flag.Duration("retain-backups", 0, "Remove backups older than this from a local -backups directory (0 keeps them)")
+	orphanCheck  = flag.Duration("orphan-check-interval", time.Hour, "Interval asking connectors for resources no registered pipeline owns (0 disables)")
+	memoryLimit  = flag.Int64("memory-limit", 0, "Soft memory limit in bytes as GOMEMLIMIT sets it; batches shrink and bulk pipelines pause near it (0 keeps GOMEMLIMIT)")
+	errorBurst   = flag.Int("error-log-burst", esync.DefaultErrorLogBurst, "Error lines each pipeline and error type may log per -error-log-window before further ones are only counted; pipelines override it with error_log")
+	errorWindow  = flag.Duration("error-log-window", esync.DefaultErrorLogWindow, "Window of -error-log-burst")
+	handoffFrom  = flag.String("handoff-from", "", "Admin API URL of a running daemon to take the pipelines over from")
+	handoffLease = flag.Duration("handoff-lease", esync.DefaultHandoffLease, "Time the old daemon waits for the handoff to complete before resuming its pipelines")
+	agentMode    = flag.Bool("agent", false, "Run as an edge agent: spool records locally and upload them when the target is reachable")
+	agentID      = flag.String("agent-id", "", "Name of the agent in its beacons (default the host name)")
+	agentCentral = flag.String("agent-central", "", "Admin API URL of the central daemon receiving the agent status beacon")
+	beaconEvery  = flag.Duration("agent-beacon-interval", esync.DefaultBeaconInterval, "Interval of agent status beacons")
+	spoolMax     = flag.Int64("agent-spool-max-bytes", 0, "Size of the spool of each pipeline beyond which sources are not read (0 is unbounded)")
+	clockSkew    = flag.Duration("agent-clock-skew", esync.DefaultAgentClockSkew, "How far the agent clock may be off; time checkpoints further ahead are rewound")
+	agentLabels  = flag.String("agent-labels", "", "Comma-separated key=value labels by which a fleet manager assigns pipelines to the agent")
+	agentBytes   = flag.String("agent-bandwidth", "", "YAML file capping the bytes per second the agent writes, by time of day")
+	fleetFile    = flag.String("fleet", "", "Fleet file assigning pipelines to the edge agents reporting to this daemon")
+	fleetKey     = flag.String("fleet-key", os.Getenv("ESYNC_FLEET_KEY"), "Key signing fleet pipeline deltas on the central daemon and verifying them on agents")
 )
 
-const (
-	appName    = "esync-platform-syncd"
-	appVersion = "1.0.0"
-)
+const appName = "esync-platform-syncd"
 
 func main() {
 	flag.Parse()
+	logging.Install(os.Stderr)
 
 	if *version {
-		log.Printf("%s v%s", appName, appVersion)
+		log.Printf("%s v%s", appName, buildinfo.Get())
 		os.Exit(0)
 	}
 
-	log.Printf("Starting %s v%s", appName, appVersion)
+	log.Printf("Starting %s v%s", appName, buildinfo.Get())
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
-	<-sigChan
+	hupChan := make(chan os.Signal, 1)
+	signal.Notify(hupChan, syscall.SIGHUP)
+
+	labels := splitList(*metricLabels)
+	grouping := splitPairs("metrics-push-labels", *pushLabels)
+
+	eng := esync.New(esync.Options{
+		Config:            *configFile,
+		LogLevel:          *logLevel,
+		StateDir:          *stateDir,
+		Version:           buildinfo.Version,
+		PipelinesDir:      *pipelinesDir,
+		Environment:       *environment,
+		SecretsDir:        *secretsDir,
+		APIAddr:           *apiAddr,
+		MetricsAddr:       *metricsAddr,
+		WebhookAddr:       *webhookAddr,
+		MetricLabels:      labels,
+		SentryDSN:         *sentryDSN,
+		ErrorWebhookURL:   *errorWebhook,
+		AlertRoutes:       *alertRoutes,
+		NamespaceQuotas:   *nsQuotas,
+		Digests:           *digests,
+		RunLedger:         *runLedger,
+		RunReports:        *runReports,
+		CredentialRefresh: *credRefresh,
+		Proxy:             *proxy,
+		NoProxy:           splitList(*noProxy),
+		EgressAllow:       splitList(*egressAllow),
+		FIPS:              *fipsMode,
+		PluginCgroup:      *pluginCgroup,
+		RunReportMaxAge:   *retainReport,
+		Retention: esync.Retention{
+			RunHistory:    *retainRuns,
+			RunMaxAge:     *retainRunAge,
+			AuditMaxAge:   *retainAudit,
+			AuditMaxBytes: *auditMaxSize,
+			ErasureMaxAge: *retainErased,
+			FixtureMaxAge: *fixtureAge,
+			Interval:      *pruneEvery,
+		},
+		MetricsPush: esync.MetricsPush{
+			URL:      *metricsPush,
+			Format:   *pushFormat,
+			Interval: *pushEvery,
+			Labels:   grouping,
+		},
+		MetricsBackend: esync.MetricsBackend{
+			Kind:     *metricsSink,
+			Addr:     *statsdAddr,
+			Prefix:   *statsdPrefix,
+			Tags:     splitPairs("statsd-tags", *statsdTags),
+			Interval: *flushEvery,
+		},
+		Backups:        *backups,
+		BackupInterval: *backupEvery,
+		BackupMaxAge:   *retainBackup,
+		OrphanCheck:    *orphanCheck,
+		MemoryLimit:    *memoryLimit,
+		ErrorLogBurst:  *errorBurst,
+		ErrorLogWindow: *errorWindow,
+		HandoffFrom:    *handoffFrom,
+		HandoffLease:   *handoffLease,
+		Fleet:          *fleetFile,
+		FleetKey:       *fleetKey,
+		Agent: esync.AgentOptions{
+			Enabled:        *agentMode,
+			ID:             *agentID,
+			Central:        *agentCentral,
+			BeaconInterval: *beaconEvery,
+			SpoolMaxBytes:  *spoolMax,
+			ClockSkew:      *clockSkew,
+			Labels:         splitPairs("agent-labels", *agentLabels),
+			Bandwidth:      *agentBytes,
+		},
+	})
+	if *selfTest {
+		os.Exit(runSelfTest(ctx, eng))
+	}
+	if err := eng.Start(ctx); err != nil {
+		log.Fatalf("Failed to start: %v", err)
+	}
+
+wait:
+	for {
+		select {
+		case <-hupChan:
+			if _, err := eng.Reload(ctx); err != nil {
+				log.Printf("Failed to reload config: %v", err)
+			}
+		case <-sigChan:
+			break wait
+		case <-eng.HandedOff():
+			log.Println("Pipelines handed off to the new daemon")
+			break wait
+		}
+	}
 
 	log.Println("Shutting down gracefully...")
 	cancel()
 	time.Sleep(2 * time.Second)
 	log.Println("Shutdown complete")
 }
+
+// runSelfTest prints the self-test report of eng to stdout and returns the
+// exit code: 0 when it passed, 1 otherwise
+func runSelfTest(ctx context.Context, eng *esync.Engine) int {
+	ctx, cancel := context.WithTimeout(ctx, *selfTestWait)
+	defer cancel()
+
+	report, err := eng.SelfTest(ctx)
+	if err != nil {
+		log.Printf("Self-test failed: %v", err)
+		return 1
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		log.Printf("Failed to write self-test report: %v", err)
+		return 1
+	}
+	if !report.Passed {
+		log.Printf("Self-test failed")
+		return 1
+	}
+	log.Printf("Self-test passed")
+	return 0
+}
+
+// splitList splits a comma-separated flag value, returning nil when empty
+func splitList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// splitPairs parses a comma-separated key=value flag value
+func splitPairs(name, value string) map[string]string {
+	pairs := make(map[string]string)
+	for _, pair := range splitList(value) {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok || k == "" {
+			log.Fatalf("Invalid -%s entry %q: want key=value", name, pair)
+		}
+		pairs[k] = v
+	}
+	return pairs
+}
@@ -0,0 +1,1233 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: synctl-cli
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * SyncTL - Admin CLI for the Sync Daemon
+ */
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// command is a synctl subcommand
+type command struct {
+	usage string
+	run   func(c *client, args []string) error
+}
+
+var commands = map[string]command{
+	"list":        {"list [-l selector] [-label k:v] [-source type] [-target type] [-q text] [-sort key] [-offset n] [-n limit]", listPipelines},
+	"connections": {"connections", listConnections},
+	"audit":       {"audit [-n limit] [pipeline-id]", listAudit},
+	"orphans":     {"orphans [check]", orphans},
+	"erase":       {"erase [-l selector] [-reason text] <subject>", startErasure},
+	"erasures":    {"erasures [<request-id> [report|retry]]", listErasures},
+	"backup":      {"backup [-o file]", backupState},
+	"restore":     {"restore <file>", restoreState},
+	"export":      {"export [-l selector] [-o file]", exportPipelines},
+	"import":      {"import [-on-conflict skip|overwrite|rename] [-dry-run] <file>", importPipelines},
+	"handoff":     {"handoff [<handoff-id> complete|abort]", handoff},
+	"agents":      {"agents [self|<agent-id>]", listAgents},
+	"fleet":       {"fleet [pipelines|reload]", fleetStatus},
+	"bandwidth":   {"bandwidth [-days n]", bandwidthReport},
+	"info":        {"info", showInfo},
+	"reload":      {"reload", reloadConfig},
+	"log-level":   {"log-level [-expires duration] [<scope> (<level>|clear)]", logLevel},
+	"memory":      {"memory", showMemory},
+	"autoscaling": {"autoscaling", showAutoscaling},
+	"overview":    {"overview [-level unhealthy,degraded] [-limit n]", showOverview},
+	"digests":     {"digests [<name> [send]]", digests},
+	"health":      {"health <pipeline-id>", showHealth},
+	"functions":   {"functions [-version n]", templateFunctions},
+	"flags":       {"flags [<name> <value>]", runtimeFlags},
+	"connector":   {"connector describe <type>", describeConnector},
+	"watch":       {"watch [-l selector]", watchPipelines},
+	"get":         {"get <pipeline-id>", getPipeline},
+	"explain":     {"explain <pipeline-id>", explainPipeline},
+	"runs":        {"runs <pipeline-id>", listRuns},
+	"rundiff":     {"rundiff <pipeline-id> [<run-a> <run-b>]", diffRuns},
+	"tables":      {"tables <pipeline-id>", listTables},
+	"compat":      {"compat <pipeline-id>", checkSchemas},
+	"ddl":         {"ddl <pipeline-id> [approve|reject <change-id>]", ddlChanges},
+	"dlq":         {"dlq <pipeline-id> [drain]", deadLetters},
+	"trace":       {"trace [-for duration] <pipeline-id> [stop]", tracePipeline},
+	"record":      {"record [-o file] <pipeline-id>", recordRun},
+	"simulate":    {"simulate [-f definition] [-speed n] <pipeline-id> [fixture...]", simulatePipeline},
+	"teardown":    {"teardown [-dry-run] <pipeline-id>", teardownPipeline},
+	"standby":     {"standby [-force] <pipeline-id> [failover|rearm]", standbyPipeline},
+	"confirm":     {"confirm [-ticket id] [-by name] <pipeline-id> [definition]", confirmPipeline},
+	"rollout":     {"rollout <pipeline-id> [promote|rollback]", rolloutPipeline},
+	"lookups":     {"lookups <pipeline-id> [clear]", lookupCaches},
+	"holdback":    {"holdback [-reason text] [-by name] [-for d | -until time] <pipeline-id> [set|release]", holdback},
+	"snapshot":    {"snapshot [-table t1,t2] [-start key] [-end key] <pipeline-id> [status|cancel]", snapshotPipeline},
+	"clone":       {"clone [-source conn] [-target conn] [-d text] [-label k:v] <pipeline-id> <new-id>", clonePipeline},
+	"promote":     {"promote [-from env] [-dry-run] <pipeline-id> <env>", promotePipeline},
+	"trigger":     {"trigger (<pipeline-id> | -l selector)", pipelineAction("runs", "trigger")},
+	"pause":       {"pause (<pipeline-id> | -l selector)", pipelineAction("pause", "pause")},
+	"resume":      {"resume (<pipeline-id> | -l selector)", pipelineAction("resume", "resume")},
+}
+
+func main() {
+	apiURL := flag.String("api", envOr("SYNCTL_API", "http://localhost:8080"), "Admin API base URL, or unix:///path for a unix socket")
+	requestID := flag.String("request-id", os.Getenv("SYNCTL_REQUEST_ID"), "X-Request-ID sent with every request, to find them in the daemon's logs, runs and audit log")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[flag.Arg(0)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n", flag.Arg(0))
+		usage()
+		os.Exit(2)
+	}
+
+	c := newClient(*apiURL)
+	c.requestID = *requestID
+	if err := cmd.run(c, flag.Args()[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// usage prints the available commands
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: synctl [-api URL] [-request-id ID] <command>\n\nCommands:\n")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %s\n", commands[name].usage)
+	}
+}
+
+// envOr returns the environment variable or a fallback
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// listPipelines prints pipelines matching an optional selector and search
+// terms
+func listPipelines(c *client, args []string) error {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	selector := fs.String("l", "", "Label selector, e.g. team=payments,env!=prod")
+	label := fs.String("label", "", "Comma-separated key:value labels that must all match")
+	source := fs.String("source", "", "Source connector type")
+	target := fs.String("target", "", "Target connector type")
+	text := fs.String("q", "", "Text to find in IDs, descriptions and labels")
+	sortKey := fs.String("sort", "", "Sort key: id, priority, source.type, target.type or mode, prefixed with - to reverse")
+	offset := fs.Int("offset", 0, "Matches to skip")
+	limit := fs.Int("n", 0, "Maximum number of pipelines; 0 lists all")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	q := url.Values{}
+	for key, value := range map[string]string{
+		"selector": *selector, "label": *label, "source.type": *source,
+		"target.type": *target, "text": *text, "sort": *sortKey,
+	} {
+		if value != "" {
+			q.Set(key, value)
+		}
+	}
+	if *offset > 0 {
+		q.Set("offset", strconv.Itoa(*offset))
+	}
+	if *limit > 0 {
+		q.Set("limit", strconv.Itoa(*limit))
+	}
+	return c.do(http.MethodGet, "/pipelines?"+q.Encode())
+}
+
+// listConnections prints the connection profiles
+func listConnections(c *client, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("usage: synctl connections")
+	}
+
+	return c.do(http.MethodGet, "/connections")
+}
+
+// orphans prints the last orphaned resource report, or runs a new check
+func orphans(c *client, args []string) error {
+	switch {
+	case len(args) == 0:
+		return c.do(http.MethodGet, "/orphans")
+	case len(args) == 1 && args[0] == "check":
+		return c.do(http.MethodPost, "/orphans")
+	default:
+		return fmt.Errorf("usage: synctl orphans [check]")
+	}
+}
+
+// listAudit prints policy decisions, newest first
+func listAudit(c *client, args []string) error {
+	fs := flag.NewFlagSet("audit", flag.ExitOnError)
+	limit := fs.Int("n", 100, "Maximum number of entries")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return fmt.Errorf("usage: synctl audit [-n limit] [pipeline-id]")
+	}
+
+	q := url.Values{"limit": {strconv.Itoa(*limit)}}
+	if fs.NArg() == 1 {
+		q.Set("pipeline", fs.Arg(0))
+	}
+	return c.do(http.MethodGet, "/audit?"+q.Encode())
+}
+
+// startErasure requests the erasure of a data subject from every pipeline
+// declaring erasure
+func startErasure(c *client, args []string) error {
+	fs := flag.NewFlagSet("erase", flag.ExitOnError)
+	selector := fs.String("l", "", "Only erase from pipelines matching this label selector")
+	reason := fs.String("reason", "", "Reason recorded with the request, e.g. a ticket reference")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: synctl erase [-l selector] [-reason text] <subject>")
+	}
+
+	q := url.Values{"subject": {fs.Arg(0)}}
+	if *selector != "" {
+		q.Set("selector", *selector)
+	}
+	if *reason != "" {
+		q.Set("reason", *reason)
+	}
+	return c.do(http.MethodPost, "/erasures?"+q.Encode())
+}
+
+// listErasures prints erasure requests, one request or its compliance
+// report, or retries the failed targets of a request
+func listErasures(c *client, args []string) error {
+	switch {
+	case len(args) == 0:
+		return c.do(http.MethodGet, "/erasures")
+	case len(args) == 1:
+		return c.do(http.MethodGet, "/erasures/"+url.PathEscape(args[0]))
+	case len(args) == 2 && args[1] == "report":
+		return c.do(http.MethodGet, "/erasures/"+url.PathEscape(args[0])+"/report")
+	case len(args) == 2 && args[1] == "retry":
+		return c.do(http.MethodPost, "/erasures/"+url.PathEscape(args[0])+"/retry")
+	default:
+		return fmt.Errorf("usage: synctl erasures [<request-id> [report|retry]]")
+	}
+}
+
+// backupState downloads an archive of the daemon state to a file
+func backupState(c *client, args []string) error {
+	fs := flag.NewFlagSet("backup", flag.ExitOnError)
+	out := fs.String("o", "", "Archive file to write, - for stdout (default esync-backup-<time>.tar.gz)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return fmt.Errorf("usage: synctl backup [-o file]")
+	}
+
+	resp, err := c.send(http.MethodGet, "/backup", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if *out == "-" {
+		_, err := io.Copy(os.Stdout, resp.Body)
+		return err
+	}
+	if *out == "" {
+		*out = "esync-backup-" + time.Now().UTC().Format("20060102T150405Z") + ".tar.gz"
+	}
+	f, err := os.OpenFile(*out, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "wrote %s\n", *out)
+	return nil
+}
+
+// restoreState replaces the daemon state with a backup archive
+func restoreState(c *client, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: synctl restore <file>")
+	}
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	resp, err := c.send(http.MethodPost, "/restore", f)
+	if err != nil {
+		return err
+	}
+	return c.print(resp)
+}
+
+// exportPipelines writes a bundle of pipelines to a file
+func exportPipelines(c *client, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	selector := fs.String("l", "", "Label selector, e.g. team=payments,env!=prod")
+	out := fs.String("o", "", "Bundle file to write, - for stdout (default esync-pipelines-<time>.tar.gz)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return fmt.Errorf("usage: synctl export [-l selector] [-o file]")
+	}
+
+	path := "/pipelines:export"
+	if *selector != "" {
+		path += "?selector=" + url.QueryEscape(*selector)
+	}
+	resp, err := c.send(http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if *out == "-" {
+		_, err := io.Copy(os.Stdout, resp.Body)
+		return err
+	}
+	if *out == "" {
+		*out = "esync-pipelines-" + time.Now().UTC().Format("20060102T150405Z") + ".tar.gz"
+	}
+	f, err := os.OpenFile(*out, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "wrote %s\n", *out)
+	return nil
+}
+
+// importPipelines registers the pipelines of a bundle
+func importPipelines(c *client, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	onConflict := fs.String("on-conflict", "skip", "What to do with pipelines whose ID is taken: skip, overwrite or rename")
+	dryRun := fs.Bool("dry-run", false, "Report what would be imported without writing anything")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: synctl import [-on-conflict skip|overwrite|rename] [-dry-run] <file>")
+	}
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	q := url.Values{"on_conflict": {*onConflict}}
+	if *dryRun {
+		q.Set("dry_run", "true")
+	}
+	resp, err := c.send(http.MethodPost, "/pipelines:import?"+q.Encode(), f)
+	if err != nil {
+		return err
+	}
+	return c.print(resp)
+}
+
+// handoff prints the last handoff of the daemon, or completes or aborts a
+// leased one
+func handoff(c *client, args []string) error {
+	switch {
+	case len(args) == 0:
+		return c.do(http.MethodGet, "/handoff")
+	case len(args) == 2 && (args[1] == "complete" || args[1] == "abort"):
+		return c.do(http.MethodPost, "/handoff/"+url.PathEscape(args[0])+"/"+args[1])
+	default:
+		return fmt.Errorf("usage: synctl handoff [<handoff-id> complete|abort]")
+	}
+}
+
+// listAgents prints the edge agents reporting to the daemon, the last
+// beacon of one, or the beacon of the daemon itself when it is an agent
+func listAgents(c *client, args []string) error {
+	switch {
+	case len(args) == 0:
+		return c.do(http.MethodGet, "/agents")
+	case len(args) == 1 && args[0] == "self":
+		return c.do(http.MethodGet, "/agent")
+	case len(args) == 1:
+		return c.do(http.MethodGet, "/agents/"+url.PathEscape(args[0]))
+	default:
+		return fmt.Errorf("usage: synctl agents [self|<agent-id>]")
+	}
+}
+
+// fleetStatus prints the status of the managed fleet or its pipelines, or
+// reloads the fleet file
+func fleetStatus(c *client, args []string) error {
+	switch {
+	case len(args) == 0:
+		return c.do(http.MethodGet, "/fleet")
+	case len(args) == 1 && args[0] == "pipelines":
+		return c.do(http.MethodGet, "/fleet/pipelines")
+	case len(args) == 1 && args[0] == "reload":
+		return c.do(http.MethodPost, "/fleet:reload")
+	default:
+		return fmt.Errorf("usage: synctl fleet [pipelines|reload]")
+	}
+}
+
+// bandwidthReport prints the bandwidth caps and the traffic metered over
+// the last days
+func bandwidthReport(c *client, args []string) error {
+	fs := flag.NewFlagSet("bandwidth", flag.ExitOnError)
+	days := fs.Int("days", 7, "Number of days of traffic, today included")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("usage: synctl bandwidth [-days n]")
+	}
+	return c.do(http.MethodGet, "/bandwidth?days="+strconv.Itoa(*days))
+}
+
+// showInfo prints the build, enabled features and runtime flags of the
+// daemon
+func showInfo(c *client, args []string) error {
+	return c.do(http.MethodGet, "/info")
+}
+
+// reloadConfig makes the daemon reload its config as SIGHUP does
+func reloadConfig(c *client, args []string) error {
+	return c.do(http.MethodPost, "/config:reload")
+}
+
+// logLevel lists the log levels, or overrides or clears the level of a
+// module, connector:<type> or pipeline:<id>
+func logLevel(c *client, args []string) error {
+	fs := flag.NewFlagSet("log-level", flag.ExitOnError)
+	expires := fs.Duration("expires", 0, "How long the override lasts (default until cleared)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	switch {
+	case fs.NArg() == 0:
+		return c.do(http.MethodGet, "/log-levels")
+	case fs.NArg() == 2 && fs.Arg(1) == "clear":
+		return c.do(http.MethodDelete, "/log-levels/"+url.PathEscape(fs.Arg(0)))
+	case fs.NArg() == 2:
+		q := url.Values{"level": {fs.Arg(1)}}
+		if *expires > 0 {
+			q.Set("expires", expires.String())
+		}
+		return c.do(http.MethodPost, "/log-levels/"+url.PathEscape(fs.Arg(0))+"?"+q.Encode())
+	default:
+		return fmt.Errorf("usage: synctl log-level [-expires duration] [<scope> (<level>|clear)]")
+	}
+}
+
+// showMemory prints the memory use of the daemon and its memory pressure
+func showMemory(c *client, args []string) error {
+	return c.do(http.MethodGet, "/memory")
+}
+
+// digests lists the scheduled digests, previews one or sends it now
+func digests(c *client, args []string) error {
+	switch {
+	case len(args) == 0:
+		return c.do(http.MethodGet, "/digests")
+	case len(args) == 1:
+		return c.do(http.MethodGet, "/digests/"+url.PathEscape(args[0]))
+	case len(args) == 2 && args[1] == "send":
+		return c.do(http.MethodPost, "/digests/"+url.PathEscape(args[0])+"/send")
+	default:
+		return fmt.Errorf("usage: synctl digests [<name> [send]]")
+	}
+}
+
+// showOverview prints the health of every pipeline, worst first
+func showOverview(c *client, args []string) error {
+	fs := flag.NewFlagSet("overview", flag.ExitOnError)
+	level := fs.String("level", "", "Only pipelines at these levels, comma-separated: healthy, degraded, unhealthy or paused")
+	limit := fs.Int("limit", 0, "Only the n worst pipelines")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	q := url.Values{}
+	if *level != "" {
+		q.Set("level", *level)
+	}
+	if *limit > 0 {
+		q.Set("limit", strconv.Itoa(*limit))
+	}
+	path := "/overview"
+	if len(q) > 0 {
+		path += "?" + q.Encode()
+	}
+	return c.do(http.MethodGet, path)
+}
+
+// showHealth prints the health score of a pipeline and what lowered it
+func showHealth(c *client, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: synctl health <pipeline-id>")
+	}
+
+	return c.do(http.MethodGet, "/pipelines/"+url.PathEscape(args[0])+"/health")
+}
+
+// showAutoscaling prints the load of the daemon as external scalers see it
+func showAutoscaling(c *client, args []string) error {
+	return c.do(http.MethodGet, "/autoscaling")
+}
+
+// templateFunctions prints the functions templates can use
+func templateFunctions(c *client, args []string) error {
+	fs := flag.NewFlagSet("functions", flag.ExitOnError)
+	version := fs.Int("version", 0, "Version of the function library (default the latest)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	path := "/template-functions"
+	if *version > 0 {
+		path += "?version=" + strconv.Itoa(*version)
+	}
+	return c.do(http.MethodGet, path)
+}
+
+// runtimeFlags lists the runtime flags or sets one
+func runtimeFlags(c *client, args []string) error {
+	switch len(args) {
+	case 0:
+		return c.do(http.MethodGet, "/flags")
+	case 2:
+		return c.do(http.MethodPost, "/flags/"+url.PathEscape(args[0])+"?"+url.Values{"value": {args[1]}}.Encode())
+	default:
+		return fmt.Errorf("usage: synctl flags [<name> <value>]")
+	}
+}
+
+// describeConnector prints the documentation of a connector type's config
+// block from its schema
+func describeConnector(c *client, args []string) error {
+	if len(args) != 2 || args[0] != "describe" {
+		return fmt.Errorf("usage: synctl connector describe <type>")
+	}
+
+	resp, err := c.send(http.MethodGet, "/schemas/connectors/"+url.PathEscape(args[1])+".json", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var schema map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&schema); err != nil {
+		return fmt.Errorf("failed to decode schema: %w", err)
+	}
+	title, _ := schema["title"].(string)
+	if title == "" {
+		title = args[1]
+	}
+	fmt.Println(title)
+	if description, _ := schema["description"].(string); description != "" {
+		fmt.Printf("\n%s\n", description)
+	}
+	if properties, _ := schema["properties"].(map[string]interface{}); len(properties) > 0 {
+		fmt.Printf("\nSettings:\n")
+		describeProperties(schema, "  ")
+	}
+	return nil
+}
+
+// describeProperties prints the properties of an object schema with their
+// type, constraints and description, nesting those of object properties
+func describeProperties(schema map[string]interface{}, indent string) {
+	properties, _ := schema["properties"].(map[string]interface{})
+	required := map[string]bool{}
+	if names, ok := schema["required"].([]interface{}); ok {
+		for _, name := range names {
+			required[fmt.Sprint(name)] = true
+		}
+	}
+
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		property, _ := properties[name].(map[string]interface{})
+		var details []string
+		if t := schemaType(property); t != "" {
+			details = append(details, t)
+		}
+		if required[name] {
+			details = append(details, "required")
+		}
+		if enum, ok := property["enum"].([]interface{}); ok {
+			values := make([]string, len(enum))
+			for i, v := range enum {
+				values[i] = fmt.Sprint(v)
+			}
+			details = append(details, "one of "+strings.Join(values, ", "))
+		}
+		if min, ok := property["minimum"]; ok {
+			details = append(details, fmt.Sprintf("min %v", min))
+		}
+		if max, ok := property["maximum"]; ok {
+			details = append(details, fmt.Sprintf("max %v", max))
+		}
+
+		line := indent + name
+		if len(details) > 0 {
+			line += " (" + strings.Join(details, ", ") + ")"
+		}
+		fmt.Println(line)
+		if description, _ := property["description"].(string); description != "" {
+			fmt.Printf("%s    %s\n", indent, description)
+		}
+		describeProperties(property, indent+"  ")
+	}
+}
+
+// schemaType renders the type keyword of a schema, including the item type
+// of arrays
+func schemaType(schema map[string]interface{}) string {
+	var t string
+	switch v := schema["type"].(type) {
+	case string:
+		t = v
+	case []interface{}:
+		types := make([]string, len(v))
+		for i, name := range v {
+			types[i] = fmt.Sprint(name)
+		}
+		t = strings.Join(types, " or ")
+	}
+	if items, ok := schema["items"].(map[string]interface{}); ok && t == "array" {
+		if item := schemaType(items); item != "" {
+			t = "array of " + item
+		}
+	}
+	return t
+}
+
+// getPipeline prints a single pipeline
+func getPipeline(c *client, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: synctl get <pipeline-id>")
+	}
+
+	return c.do(http.MethodGet, "/pipelines/"+url.PathEscape(args[0]))
+}
+
+// explainPipeline prints the fully resolved form of a pipeline
+func explainPipeline(c *client, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: synctl explain <pipeline-id>")
+	}
+
+	return c.do(http.MethodGet, "/pipelines/"+url.PathEscape(args[0])+"/explain")
+}
+
+// listRuns prints the recent runs of a pipeline with their grouped errors
+func listRuns(c *client, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: synctl runs <pipeline-id>")
+	}
+
+	return c.do(http.MethodGet, "/pipelines/"+url.PathEscape(args[0])+"/runs")
+}
+
+// diffRuns compares two runs of a pipeline, by default the latest against
+// the last good one
+func diffRuns(c *client, args []string) error {
+	a, b := "last-good", "latest"
+	switch len(args) {
+	case 1:
+	case 3:
+		a, b = args[1], args[2]
+	default:
+		return fmt.Errorf("usage: synctl rundiff <pipeline-id> [<run-a> <run-b>]")
+	}
+
+	return c.do(http.MethodGet, "/pipelines/"+url.PathEscape(args[0])+"/runs/"+url.PathEscape(a)+"/diff/"+url.PathEscape(b))
+}
+
+// listTables prints the per-table progress of a multi-table pipeline
+func listTables(c *client, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: synctl tables <pipeline-id>")
+	}
+
+	return c.do(http.MethodGet, "/pipelines/"+url.PathEscape(args[0])+"/tables")
+}
+
+// checkSchemas prints the schema compatibility report of a pipeline
+func checkSchemas(c *client, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: synctl compat <pipeline-id>")
+	}
+
+	return c.do(http.MethodGet, "/pipelines/"+url.PathEscape(args[0])+"/compatibility")
+}
+
+// ddlChanges lists the captured schema changes of a pipeline, or approves
+// or rejects one
+func ddlChanges(c *client, args []string) error {
+	switch {
+	case len(args) == 1:
+		return c.do(http.MethodGet, "/pipelines/"+url.PathEscape(args[0])+"/ddl")
+	case len(args) == 3 && (args[1] == "approve" || args[1] == "reject"):
+		return c.do(http.MethodPost, "/pipelines/"+url.PathEscape(args[0])+"/ddl/"+url.PathEscape(args[2])+"/"+args[1])
+	default:
+		return fmt.Errorf("usage: synctl ddl <pipeline-id> [approve|reject <change-id>]")
+	}
+}
+
+// deadLetters lists the dead-letter queue of a pipeline, the records set
+// aside for exceeding its record limits, or drains it
+func deadLetters(c *client, args []string) error {
+	switch {
+	case len(args) == 1:
+		return c.do(http.MethodGet, "/pipelines/"+url.PathEscape(args[0])+"/dead-letters")
+	case len(args) == 2 && args[1] == "drain":
+		return c.do(http.MethodDelete, "/pipelines/"+url.PathEscape(args[0])+"/dead-letters")
+	default:
+		return fmt.Errorf("usage: synctl dlq <pipeline-id> [drain]")
+	}
+}
+
+// tracePipeline traces a pipeline verbosely for a while, or stops its trace
+func tracePipeline(c *client, args []string) error {
+	fs := flag.NewFlagSet("trace", flag.ExitOnError)
+	duration := fs.Duration("for", 0, "How long to trace (default 10m, at most 1h)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	switch {
+	case fs.NArg() == 1:
+		path := "/pipelines/" + url.PathEscape(fs.Arg(0)) + "/trace"
+		if *duration > 0 {
+			path += "?duration=" + url.QueryEscape(duration.String())
+		}
+		return c.do(http.MethodPost, path)
+	case fs.NArg() == 2 && fs.Arg(1) == "stop":
+		return c.do(http.MethodDelete, "/pipelines/"+url.PathEscape(fs.Arg(0))+"/trace")
+	default:
+		return fmt.Errorf("usage: synctl trace [-for duration] <pipeline-id> [stop]")
+	}
+}
+
+// teardownPipeline releases the connector resources of a paused pipeline,
+// or lists them with -dry-run
+func teardownPipeline(c *client, args []string) error {
+	fs := flag.NewFlagSet("teardown", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "List the resources without removing them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: synctl teardown [-dry-run] <pipeline-id>")
+	}
+
+	path := "/pipelines/" + url.PathEscape(fs.Arg(0)) + "/teardown"
+	if *dryRun {
+		path += "?dry_run=true"
+	}
+	return c.do(http.MethodPost, path)
+}
+
+// standbyPipeline prints the failover barrier of a standby pipeline,
+// releases it or holds it again
+func standbyPipeline(c *client, args []string) error {
+	fs := flag.NewFlagSet("standby", flag.ExitOnError)
+	force := fs.Bool("force", false, "Promote the target even if the final delta fails")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	path := "/pipelines/" + url.PathEscape(fs.Arg(0)) + "/standby"
+	switch {
+	case fs.NArg() == 1:
+		return c.do(http.MethodGet, path)
+	case fs.NArg() == 2 && fs.Arg(1) == "failover":
+		if *force {
+			path += "?force=true"
+		}
+		return c.do(http.MethodPost, path)
+	case fs.NArg() == 2 && fs.Arg(1) == "rearm":
+		return c.do(http.MethodDelete, path)
+	default:
+		return fmt.Errorf("usage: synctl standby [-force] <pipeline-id> [failover|rearm]")
+	}
+}
+
+// rolloutPipeline prints, promotes or rolls back the staged rollout of a
+// pipeline's definition
+func rolloutPipeline(c *client, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: synctl rollout <pipeline-id> [promote|rollback]")
+	}
+	path := "/pipelines/" + url.PathEscape(args[0]) + "/rollout"
+	switch {
+	case len(args) == 1:
+		return c.do(http.MethodGet, path)
+	case len(args) == 2 && args[1] == "promote":
+		return c.do(http.MethodPost, path)
+	case len(args) == 2 && args[1] == "rollback":
+		return c.do(http.MethodDelete, path)
+	default:
+		return fmt.Errorf("usage: synctl rollout <pipeline-id> [promote|rollback]")
+	}
+}
+
+// lookupCaches prints or clears the lookup caches of a pipeline's connectors
+func lookupCaches(c *client, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: synctl lookups <pipeline-id> [clear]")
+	}
+	path := "/pipelines/" + url.PathEscape(args[0]) + "/lookup-cache"
+	switch {
+	case len(args) == 1:
+		return c.do(http.MethodGet, path)
+	case len(args) == 2 && args[1] == "clear":
+		return c.do(http.MethodDelete, path)
+	default:
+		return fmt.Errorf("usage: synctl lookups <pipeline-id> [clear]")
+	}
+}
+
+// confirmPipeline prints the definition digest of a destructive pipeline
+// and whether it is confirmed, or confirms the reviewed digest
+func confirmPipeline(c *client, args []string) error {
+	fs := flag.NewFlagSet("confirm", flag.ExitOnError)
+	ticket := fs.String("ticket", "", "Change ticket the confirmation refers to")
+	by := fs.String("by", "", "Name of the person confirming, recorded in the audit log")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	path := "/pipelines/" + url.PathEscape(fs.Arg(0)) + "/confirmation"
+	switch fs.NArg() {
+	case 1:
+		return c.do(http.MethodGet, path)
+	case 2:
+		q := url.Values{"definition": {fs.Arg(1)}}
+		if *ticket != "" {
+			q.Set("ticket", *ticket)
+		}
+		if *by != "" {
+			q.Set("by", *by)
+		}
+		return c.do(http.MethodPost, path+"?"+q.Encode())
+	default:
+		return fmt.Errorf("usage: synctl confirm [-ticket id] [-by name] <pipeline-id> [definition]")
+	}
+}
+
+// watchPipelines prints the status transitions and run events of the
+// matching pipelines, one line each, until interrupted
+func watchPipelines(c *client, args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	selector := fs.String("l", "", "Label selector of the pipelines to watch")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return fmt.Errorf("usage: synctl watch [-l selector]")
+	}
+
+	path := "/pipelines/watch"
+	if *selector != "" {
+		path += "?selector=" + url.QueryEscape(*selector)
+	}
+	stream := *c
+	hc := *c.http
+	hc.Timeout = 0
+	stream.http = &hc
+	resp, err := stream.send(http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64<<10), 4<<20)
+	for scanner.Scan() {
+		if data, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+			fmt.Println(data)
+		}
+	}
+	return scanner.Err()
+}
+
+// snapshotPipeline requests an incremental snapshot of a pipeline, or shows
+// or cancels the last one
+func snapshotPipeline(c *client, args []string) error {
+	fs := flag.NewFlagSet("snapshot", flag.ExitOnError)
+	tables := fs.String("table", "", "Comma-separated tables to re-snapshot (default all)")
+	start := fs.String("start", "", "First key of the range to re-snapshot")
+	end := fs.String("end", "", "Key the range to re-snapshot ends before")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	path := "/pipelines/" + url.PathEscape(fs.Arg(0)) + "/snapshot"
+	switch {
+	case fs.NArg() == 1:
+		q := url.Values{}
+		for key, value := range map[string]string{"table": *tables, "start": *start, "end": *end} {
+			if value != "" {
+				q.Set(key, value)
+			}
+		}
+		return c.do(http.MethodPost, path+"?"+q.Encode())
+	case fs.NArg() == 2 && fs.Arg(1) == "status":
+		return c.do(http.MethodGet, path)
+	case fs.NArg() == 2 && fs.Arg(1) == "cancel":
+		return c.do(http.MethodDelete, path)
+	default:
+		return fmt.Errorf("usage: synctl snapshot [-table t1,t2] [-start key] [-end key] <pipeline-id> [status|cancel]")
+	}
+}
+
+// holdback shows, sets or releases the holdback of a pipeline's target
+func holdback(c *client, args []string) error {
+	fs := flag.NewFlagSet("holdback", flag.ExitOnError)
+	reason := fs.String("reason", "", "Why writes are held back, such as an ETL window")
+	by := fs.String("by", "", "System or person setting the holdback")
+	duration := fs.String("for", "", "Release the holdback after this duration, such as 2h")
+	until := fs.String("until", "", "Release the holdback at this RFC 3339 time")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	path := "/pipelines/" + url.PathEscape(fs.Arg(0)) + "/holdback"
+	switch {
+	case fs.NArg() == 1:
+		return c.do(http.MethodGet, path)
+	case fs.NArg() == 2 && fs.Arg(1) == "set":
+		q := url.Values{}
+		for key, value := range map[string]string{"reason": *reason, "by": *by, "for": *duration, "until": *until} {
+			if value != "" {
+				q.Set(key, value)
+			}
+		}
+		return c.do(http.MethodPost, path+"?"+q.Encode())
+	case fs.NArg() == 2 && fs.Arg(1) == "release":
+		return c.do(http.MethodDelete, path)
+	default:
+		return fmt.Errorf("usage: synctl holdback [-reason text] [-by name] [-for d | -until time] <pipeline-id> [set|release]")
+	}
+}
+
+// clonePipeline copies a pipeline under a new ID, optionally pointing it at
+// other connection profiles
+func clonePipeline(c *client, args []string) error {
+	fs := flag.NewFlagSet("clone", flag.ExitOnError)
+	source := fs.String("source", "", "Connection profile of the clone's source")
+	target := fs.String("target", "", "Connection profile of the clone's target")
+	description := fs.String("d", "", "Description of the clone")
+	label := fs.String("label", "", "Comma-separated key:value labels to set; key: removes a label")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return fmt.Errorf("usage: synctl clone [-source conn] [-target conn] [-d text] [-label k:v] <pipeline-id> <new-id>")
+	}
+
+	q := url.Values{"id": {fs.Arg(1)}}
+	for key, value := range map[string]string{
+		"source_connection": *source, "target_connection": *target,
+		"description": *description, "label": *label,
+	} {
+		if value != "" {
+			q.Set(key, value)
+		}
+	}
+	return c.do(http.MethodPost, "/pipelines/"+url.PathEscape(fs.Arg(0))+":clone?"+q.Encode())
+}
+
+// promotePipeline carries a pipeline's settings into another environment's
+// overlay, keeping that environment's connections
+func promotePipeline(c *client, args []string) error {
+	fs := flag.NewFlagSet("promote", flag.ExitOnError)
+	from := fs.String("from", "", "Environment to promote from (default the daemon's)")
+	dryRun := fs.Bool("dry-run", false, "Report the changes without writing the overlay")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return fmt.Errorf("usage: synctl promote [-from env] [-dry-run] <pipeline-id> <env>")
+	}
+
+	q := url.Values{"to": {fs.Arg(1)}}
+	if *from != "" {
+		q.Set("from", *from)
+	}
+	if *dryRun {
+		q.Set("dry_run", "true")
+	}
+	return c.do(http.MethodPost, "/pipelines/"+url.PathEscape(fs.Arg(0))+":promote?"+q.Encode())
+}
+
+// recordRun runs a sync pass recording its input and writes the replay
+// fixture to a file
+func recordRun(c *client, args []string) error {
+	fs := flag.NewFlagSet("record", flag.ExitOnError)
+	out := fs.String("o", "", "Fixture file to write, - for stdout (default <pipeline-id>-fixture.json)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: synctl record [-o file] <pipeline-id>")
+	}
+
+	path := "/pipelines/" + url.PathEscape(fs.Arg(0))
+	if err := c.do(http.MethodPost, path+"/runs?record=true"); err != nil {
+		return err
+	}
+	resp, err := c.send(http.MethodGet, path+"/fixture", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if *out == "-" {
+		_, err := io.Copy(os.Stdout, resp.Body)
+		return err
+	}
+	if *out == "" {
+		*out = fs.Arg(0) + "-fixture.json"
+	}
+	f, err := os.OpenFile(*out, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write fixture: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "wrote %s\n", *out)
+	return nil
+}
+
+// simulatePipeline replays fixture files written by synctl record, or the
+// fixture last recorded for the pipeline, through the pipeline or a
+// candidate definition and prints what it would have written
+func simulatePipeline(c *client, args []string) error {
+	fs := flag.NewFlagSet("simulate", flag.ExitOnError)
+	definition := fs.String("f", "", "Pipeline definition file to simulate instead of the registered one, e.g. with changed transforms")
+	speed := fs.Float64("speed", 0, "Replay the time between recorded runs this many times faster (default back to back)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		return fmt.Errorf("usage: synctl simulate [-f definition] [-speed n] <pipeline-id> [fixture...]")
+	}
+
+	req := struct {
+		Definition string            `json:"definition,omitempty"`
+		Fixtures   []json.RawMessage `json:"fixtures,omitempty"`
+	}{}
+	if *definition != "" {
+		data, err := os.ReadFile(*definition)
+		if err != nil {
+			return err
+		}
+		req.Definition = string(data)
+	}
+	for _, file := range fs.Args()[1:] {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if !json.Valid(data) {
+			return fmt.Errorf("%s is not a JSON fixture", file)
+		}
+		req.Fixtures = append(req.Fixtures, data)
+	}
+	var body bytes.Buffer
+	zw := gzip.NewWriter(&body)
+	if err := json.NewEncoder(zw).Encode(req); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	path := "/pipelines/" + url.PathEscape(fs.Arg(0)) + ":simulate"
+	if *speed > 0 {
+		path += "?speed=" + strconv.FormatFloat(*speed, 'f', -1, 64)
+	}
+	resp, err := c.send(http.MethodPost, path, &body)
+	if err != nil {
+		return err
+	}
+	return c.print(resp)
+}
+
+// pipelineAction builds a command acting on one pipeline by ID or on many
+// by selector through the bulk API
+func pipelineAction(resource, bulkAction string) func(c *client, args []string) error {
+	return func(c *client, args []string) error {
+		fs := flag.NewFlagSet(bulkAction, flag.ExitOnError)
+		selector := fs.String("l", "", "Label selector, e.g. team=payments")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+
+		switch {
+		case *selector != "" && fs.NArg() == 0:
+			return c.do(http.MethodPost, "/bulk/"+bulkAction+"?selector="+url.QueryEscape(*selector))
+		case *selector == "" && fs.NArg() == 1:
+			return c.do(http.MethodPost, "/pipelines/"+url.PathEscape(fs.Arg(0))+"/"+resource)
+		default:
+			return fmt.Errorf("specify either a pipeline id or -l selector")
+		}
+	}
+}
+
+// client talks to the daemon admin API
+type client struct {
+	base      string
+	http      *http.Client
+	requestID string
+}
+
+// newClient creates a client for an http(s) base URL or a unix:///path
+// socket
+func newClient(apiURL string) *client {
+	hc := &http.Client{Timeout: 60 * time.Second}
+	if socket, ok := strings.CutPrefix(apiURL, "unix://"); ok {
+		hc.Transport = &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socket)
+			},
+		}
+		apiURL = "http://unix"
+	}
+	return &client{base: strings.TrimRight(apiURL, "/"), http: hc}
+}
+
+// identify sets the request ID given with -request-id on a request
+func (c *client) identify(req *http.Request) {
+	if c.requestID != "" {
+		req.Header.Set("X-Request-ID", c.requestID)
+	}
+}
+
+// do sends a request and pretty-prints the JSON response
+func (c *client) do(method, path string) error {
+	req, err := http.NewRequest(method, c.base+path, nil)
+	if err != nil {
+		return err
+	}
+	c.identify(req)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	return c.print(resp)
+}
+
+// send sends a request with an optional gzip body, returning the response
+// when it succeeded and printing it otherwise
+func (c *client) send(method, path string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequest(method, c.base+path, body)
+	if err != nil {
+		return nil, err
+	}
+	c.identify(req)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/gzip")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		return nil, c.print(resp)
+	}
+	return resp, nil
+}
+
+// print pretty-prints a JSON response, failing on non-success statuses
+func (c *client) print(resp *http.Response) error {
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var pretty interface{}
+	if err := json.Unmarshal(body, &pretty); err == nil {
+		body, _ = json.MarshalIndent(pretty, "", "  ")
+	}
+	fmt.Println(string(body))
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("request failed with status %d", resp.StatusCode)
+	}
+	return nil
+}
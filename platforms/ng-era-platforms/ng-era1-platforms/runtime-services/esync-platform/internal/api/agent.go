@@ -0,0 +1,120 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: api-agents
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Edge Agent Beacon API
+ */
+
+package api
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/machine-native-ops/esync-platform/internal/engine"
+)
+
+// maxBeaconSize bounds the compressed beacon accepted by PUT /agents/{id}
+const maxBeaconSize = 1 << 20
+
+// handleAgent returns the beacon this daemon posts when running as an
+// edge agent
+func (s *Server) handleAgent(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if s.engine.Agent() == nil {
+		writeError(w, http.StatusNotFound, "daemon is not running as an agent")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.engine.Beacon())
+}
+
+// handleAgents lists the agents reporting to this daemon
+func (s *Server) handleAgents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	agents, err := s.engine.Agents()
+	if err == nil {
+		err = s.annotate(agents...)
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, agents)
+}
+
+// handleAgentBeacon returns the last beacon of an agent, or records the
+// gzipped JSON beacon an agent puts. With a fleet, the response carries
+// the revision of the pipelines assigned to the agent.
+func (s *Server) handleAgentBeacon(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/agents/")
+	switch r.Method {
+	case http.MethodGet:
+		status, err := s.engine.AgentStatus(id)
+		if err == nil && status != nil {
+			err = s.annotate(status)
+		}
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if status == nil {
+			writeError(w, http.StatusNotFound, "agent "+id+" not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, status)
+	case http.MethodPut:
+		zr, err := gzip.NewReader(http.MaxBytesReader(w, r.Body, maxBeaconSize))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "beacon must be gzipped JSON")
+			return
+		}
+		var beacon engine.AgentBeacon
+		if err := json.NewDecoder(zr).Decode(&beacon); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid beacon: "+err.Error())
+			return
+		}
+		beacon.AgentID = id
+		status, err := s.engine.RecordBeacon(&beacon)
+		if errors.Is(err, engine.ErrInvalidAgentID) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err == nil {
+			err = s.annotate(status)
+		}
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, status)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// annotate sets the fleet revision assigned to each agent when the daemon
+// manages a fleet
+func (s *Server) annotate(agents ...*engine.AgentStatus) error {
+	if s.fleet == nil {
+		return nil
+	}
+	return s.fleet.Annotate(agents...)
+}
@@ -0,0 +1,73 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: state-backup
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Backup and Restore API
+ */
+
+package api
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/machine-native-ops/esync-platform/internal/engine"
+	"github.com/machine-native-ops/esync-platform/internal/state"
+)
+
+// maxRestoreSize bounds the archive accepted by POST /restore
+const maxRestoreSize = 1 << 30
+
+// handleBackup streams a gzipped tar archive of the daemon state
+func (s *Server) handleBackup(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var buf bytes.Buffer
+	manifest, err := s.engine.Backup(&buf)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	name := fmt.Sprintf("esync-backup-%s.tar.gz", manifest.CreatedAt.Format("20060102T150405Z"))
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+name+`"`)
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// handleRestore replaces the daemon state with an uploaded backup archive
+func (s *Server) handleRestore(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	manifest, err := s.engine.Restore(http.MaxBytesReader(w, r.Body, maxRestoreSize))
+	switch {
+	case errors.Is(err, state.ErrArchiveFormat), errors.Is(err, state.ErrSchemaVersion):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, engine.ErrRunInProgress):
+		writeError(w, http.StatusConflict, err.Error())
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	default:
+		writeJSON(w, http.StatusOK, manifest)
+	}
+}
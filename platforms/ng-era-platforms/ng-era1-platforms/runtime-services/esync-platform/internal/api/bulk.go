@@ -0,0 +1,104 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: admin-api-bulk
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Bulk Pipeline Operations
+ */
+
+package api
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/machine-native-ops/esync-platform/internal/engine"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// BulkResult reports the outcome of a bulk action for one pipeline
+type BulkResult struct {
+	PipelineID string `json:"pipeline_id"`
+	OK         bool   `json:"ok"`
+	Error      string `json:"error,omitempty"`
+}
+
+// handleBulk applies an action to every pipeline matching ?selector=.
+// Supported actions are pause, resume and trigger; triggered runs execute in
+// the background.
+func (s *Server) handleBulk(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	selector := r.URL.Query().Get("selector")
+	if selector == "" {
+		writeError(w, http.StatusBadRequest, "selector required for bulk operations")
+		return
+	}
+	sel, err := registry.ParseSelector(selector)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var apply func(id string) error
+	switch action := strings.TrimPrefix(r.URL.Path, "/bulk/"); action {
+	case "pause":
+		apply = s.engine.Pause
+	case "resume":
+		apply = s.engine.Resume
+	case "trigger":
+		apply = func(id string) error { return s.triggerBackground(id, requestMetadata(r)) }
+	default:
+		writeError(w, http.StatusNotFound, "unknown bulk action "+action)
+		return
+	}
+
+	pipelines := s.registry.Select(sel)
+	results := make([]BulkResult, 0, len(pipelines))
+	for _, p := range pipelines {
+		result := BulkResult{PipelineID: p.ID, OK: true}
+		if err := apply(p.ID); err != nil {
+			result.OK = false
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+
+	writeJSON(w, http.StatusOK, results)
+}
+
+// triggerBackground starts a run without waiting for it to finish; the run
+// carries the metadata of the request. Paused pipelines fail with
+// engine.ErrPaused rather than report a run that never starts.
+func (s *Server) triggerBackground(id string, metadata map[string]string) error {
+	paused, err := s.engine.Paused(id)
+	if err != nil {
+		return err
+	}
+	if paused {
+		return fmt.Errorf("cannot run %s: %w", id, engine.ErrPaused)
+	}
+
+	go func() {
+		metadata["bulk"] = "true"
+		trigger := engine.Trigger{Type: engine.TriggerManual, Metadata: metadata}
+		if _, err := s.engine.RunOnce(s.ctx, id, trigger); err != nil {
+			log.Printf("[API] Triggered run of pipeline %s failed: %v", id, err)
+		}
+	}()
+
+	return nil
+}
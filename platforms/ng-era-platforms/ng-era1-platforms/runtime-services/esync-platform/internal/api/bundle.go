@@ -0,0 +1,107 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: pipeline-bundles
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Pipeline Bundle Export and Import API
+ */
+
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// maxBundleSize bounds the bundle accepted by POST /pipelines:import
+const maxBundleSize = 64 << 20
+
+// handleExport streams a bundle of the pipelines matching ?selector=
+func (s *Server) handleExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if _, err := registry.ParseSelector(r.URL.Query().Get("selector")); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var buf bytes.Buffer
+	manifest, err := s.registry.Export(&buf, r.URL.Query().Get("selector"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	name := fmt.Sprintf("esync-pipelines-%s.tar.gz", manifest.CreatedAt.Format("20060102T150405Z"))
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+name+`"`)
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// handleImport registers the pipelines of an uploaded bundle, resolving
+// taken IDs by ?on_conflict=skip|overwrite|rename, or only reports what
+// would happen with ?dry_run=true
+func (s *Server) handleImport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	dryRun := false
+	if v := r.URL.Query().Get("dry_run"); v != "" {
+		on, err := strconv.ParseBool(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "dry_run must be true or false")
+			return
+		}
+		dryRun = on
+	}
+
+	report, err := s.registry.Import(http.MaxBytesReader(w, r.Body, maxBundleSize), r.URL.Query().Get("on_conflict"), dryRun)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if dryRun {
+		writeJSON(w, http.StatusOK, report)
+		return
+	}
+
+	for _, p := range report.Pipelines {
+		if p.Action == "skipped" {
+			continue
+		}
+		log.Printf("[API] Imported pipeline %s (%s, %s)", p.ID, p.Action, p.File)
+		if p.Action == "overwritten" || s.added == nil {
+			continue
+		}
+		id := p.ID
+		if p.ImportedAs != "" {
+			id = p.ImportedAs
+		}
+		imported, err := s.registry.GetByID(id)
+		if err == nil {
+			err = s.added(imported)
+		}
+		if err != nil {
+			log.Printf("[API] Failed to schedule pipeline %s: %v", id, err)
+		}
+	}
+	writeJSON(w, http.StatusOK, report)
+}
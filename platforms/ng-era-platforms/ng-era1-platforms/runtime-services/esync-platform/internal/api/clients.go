@@ -0,0 +1,353 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: admin-api-client-generator
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Admin API Client Type Generator
+ */
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// goClientHeader starts the generated types of the Go client
+const goClientHeader = `// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: admin-api-client-types
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Admin API Client Types
+ */
+
+// Code generated by clientgen from the admin API types. DO NOT EDIT.
+
+package client
+
+`
+
+// tsClientHeader starts the generated types of the TypeScript client
+const tsClientHeader = `/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: admin-api-ts-client-types
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Admin API TypeScript Client Types
+ *
+ * Generated by clientgen from the admin API types. DO NOT EDIT.
+ */
+
+`
+
+// GenerateClients generates the types of the Go client in pkg/client and
+// of the TypeScript client in clients/typescript from the component
+// schemas of the OpenAPI document, so neither can drift from the daemon.
+// root is the module root the doc comments of the Go types are read from.
+func GenerateClients(root string) (goTypes, tsTypes []byte, err error) {
+	types := Schemas()
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	docs, err := loadDocs(root, types)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	g := &clientGenerator{docs: docs}
+	for _, name := range names {
+		g.declare(name, types[name])
+	}
+
+	var goFile bytes.Buffer
+	goFile.WriteString(goClientHeader)
+	switch {
+	case g.usesTime && g.usesJSON:
+		goFile.WriteString("import (\n\t\"encoding/json\"\n\t\"time\"\n)\n\n")
+	case g.usesTime:
+		goFile.WriteString("import \"time\"\n\n")
+	case g.usesJSON:
+		goFile.WriteString("import \"encoding/json\"\n\n")
+	}
+	goFile.Write(g.golang.Bytes())
+	if goTypes, err = format.Source(goFile.Bytes()); err != nil {
+		return nil, nil, fmt.Errorf("failed to format Go client types: %w", err)
+	}
+
+	tsFile := append([]byte(tsClientHeader), bytes.TrimRight(g.ts.Bytes(), "\n")...)
+	return goTypes, append(tsFile, '\n'), nil
+}
+
+// docKey identifies a type, or a field of it, in the doc comments
+type docKey struct {
+	pkg, name string
+}
+
+// loadDocs reads the doc comments of the packages declaring types, keyed
+// by type name and by "Type.Field" for fields
+func loadDocs(root string, types map[string]reflect.Type) (map[docKey]string, error) {
+	module := strings.TrimSuffix(reflect.TypeOf(Server{}).PkgPath(), "/internal/api")
+	pkgs := make(map[string]bool)
+	for _, t := range types {
+		pkgs[t.PkgPath()] = true
+		for _, f := range jsonFields(t) {
+			pkgs[f.owner.PkgPath()] = true
+		}
+	}
+
+	docs := make(map[docKey]string)
+	for pkg := range pkgs {
+		rel, ok := strings.CutPrefix(pkg, module+"/")
+		if !ok {
+			continue
+		}
+		fset := token.NewFileSet()
+		parsed, err := parser.ParseDir(fset, filepath.Join(root, filepath.FromSlash(rel)), func(fi os.FileInfo) bool {
+			return !strings.HasSuffix(fi.Name(), "_test.go")
+		}, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", pkg, err)
+		}
+		for _, p := range parsed {
+			for _, file := range p.Files {
+				collectDocs(file, pkg, docs)
+			}
+		}
+	}
+	return docs, nil
+}
+
+// collectDocs records the doc comments of the struct types of a file
+func collectDocs(file *ast.File, pkg string, docs map[docKey]string) {
+	for _, decl := range file.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts := spec.(*ast.TypeSpec)
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			doc := ts.Doc
+			if doc == nil && len(gd.Specs) == 1 {
+				doc = gd.Doc
+			}
+			docs[docKey{pkg, ts.Name.Name}] = withoutFrozen(doc.Text())
+			for _, f := range st.Fields.List {
+				doc := f.Doc
+				if doc == nil {
+					doc = f.Comment
+				}
+				for _, name := range f.Names {
+					docs[docKey{pkg, ts.Name.Name + "." + name.Name}] = doc.Text()
+				}
+			}
+		}
+	}
+}
+
+// withoutFrozen drops the Frozen paragraph of a doc comment, which marks
+// types of the public Go API rather than the admin API
+func withoutFrozen(doc string) string {
+	if i := strings.Index(doc, "Frozen: "); i == 0 || i > 0 && strings.HasSuffix(doc[:i], "\n\n") {
+		return doc[:i]
+	}
+	return doc
+}
+
+// clientGenerator writes the Go and TypeScript declarations of the
+// component schemas
+type clientGenerator struct {
+	docs   map[docKey]string
+	golang bytes.Buffer
+	ts     bytes.Buffer
+	// usesTime and usesJSON tell which imports the Go types need
+	usesTime, usesJSON bool
+}
+
+// declare writes the declarations of a component schema
+func (g *clientGenerator) declare(name string, t reflect.Type) {
+	doc := g.docs[docKey{t.PkgPath(), t.Name()}]
+	if rest, ok := strings.CutPrefix(doc, t.Name()+" "); ok {
+		doc = name + " " + rest
+	}
+	if doc == "" {
+		doc = name + " is the " + name + " schema of the admin API\n"
+	}
+
+	writeGoDoc(&g.golang, "", doc)
+	fmt.Fprintf(&g.golang, "type %s struct {\n", name)
+	writeTSDoc(&g.ts, "", doc)
+	fmt.Fprintf(&g.ts, "export interface %s {\n", name)
+	for _, f := range jsonFields(t) {
+		doc := g.docs[docKey{f.owner.PkgPath(), f.owner.Name() + "." + f.Name}]
+		writeGoDoc(&g.golang, "\t", doc)
+		tag := f.name
+		if f.omitEmpty {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(&g.golang, "\t%s %s `json:%q`\n", f.Name, g.goType(f.Type), tag)
+
+		writeTSDoc(&g.ts, "  ", doc)
+		key := f.name
+		if !tsIdentifier.MatchString(key) {
+			key = fmt.Sprintf("%q", key)
+		}
+		optional := ""
+		if f.omitEmpty {
+			optional = "?"
+		}
+		typ := tsType(f.Type)
+		if f.Type.Kind() == reflect.Ptr && !f.omitEmpty {
+			typ += " | null"
+		}
+		fmt.Fprintf(&g.ts, "  %s%s: %s;\n", key, optional, typ)
+	}
+	g.golang.WriteString("}\n\n")
+	g.ts.WriteString("}\n\n")
+}
+
+// goType returns the Go client type of a field type
+func (g *clientGenerator) goType(t reflect.Type) string {
+	switch t {
+	case reflect.TypeOf(time.Time{}):
+		g.usesTime = true
+		return "time.Time"
+	case reflect.TypeOf(time.Duration(0)):
+		g.usesTime = true
+		return "time.Duration"
+	case reflect.TypeOf(json.RawMessage{}):
+		g.usesJSON = true
+		return "json.RawMessage"
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return "*" + g.goType(t.Elem())
+	case reflect.Slice:
+		return "[]" + g.goType(t.Elem())
+	case reflect.Array:
+		return fmt.Sprintf("[%d]%s", t.Len(), g.goType(t.Elem()))
+	case reflect.Map:
+		return "map[" + g.goType(t.Key()) + "]" + g.goType(t.Elem())
+	case reflect.Interface:
+		return "interface{}"
+	case reflect.Struct:
+		return SchemaName(t)
+	default:
+		return t.Kind().String()
+	}
+}
+
+// tsType returns the TypeScript client type of a field type
+func tsType(t reflect.Type) string {
+	switch t {
+	case reflect.TypeOf(time.Time{}):
+		return "string"
+	case reflect.TypeOf(json.RawMessage{}):
+		return "unknown"
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return tsType(t.Elem())
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// Base64, as encoding/json marshals byte slices
+			return "string"
+		}
+		elem := tsType(t.Elem())
+		if strings.Contains(elem, " ") {
+			elem = "(" + elem + ")"
+		}
+		return elem + "[]"
+	case reflect.Map:
+		return "Record<string, " + tsType(t.Elem()) + ">"
+	case reflect.Interface:
+		return "unknown"
+	case reflect.Struct:
+		return SchemaName(t)
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	default:
+		return "number"
+	}
+}
+
+// tsIdentifier matches property names TypeScript takes unquoted
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// writeGoDoc writes a doc comment as Go line comments
+func writeGoDoc(b *bytes.Buffer, indent, doc string) {
+	for _, line := range docLines(doc) {
+		if line == "" {
+			fmt.Fprintf(b, "%s//\n", indent)
+			continue
+		}
+		fmt.Fprintf(b, "%s// %s\n", indent, line)
+	}
+}
+
+// writeTSDoc writes a doc comment as a JSDoc block
+func writeTSDoc(b *bytes.Buffer, indent, doc string) {
+	lines := docLines(doc)
+	switch len(lines) {
+	case 0:
+		return
+	case 1:
+		fmt.Fprintf(b, "%s/** %s */\n", indent, lines[0])
+		return
+	}
+	fmt.Fprintf(b, "%s/**\n", indent)
+	for _, line := range lines {
+		fmt.Fprintf(b, "%s *%s\n", indent, strings.TrimRight(" "+line, " "))
+	}
+	fmt.Fprintf(b, "%s */\n", indent)
+}
+
+// docLines splits a doc comment into lines
+func docLines(doc string) []string {
+	doc = strings.TrimSpace(doc)
+	if doc == "" {
+		return nil
+	}
+	return strings.Split(doc, "\n")
+}
@@ -0,0 +1,107 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: admin-api-contract-tests
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Admin API Contract Tests
+ */
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// TestClientTypes fails when the generated client types differ from the
+// admin API types they are generated from
+func TestClientTypes(t *testing.T) {
+	root := filepath.Join("..", "..")
+	goTypes, tsTypes, err := GenerateClients(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, generated := range []struct {
+		path   string
+		source []byte
+	}{
+		{filepath.Join(root, "pkg", "client", "types_gen.go"), goTypes},
+		{filepath.Join(root, "clients", "typescript", "src", "types.ts"), tsTypes},
+	} {
+		current, err := os.ReadFile(generated.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(current, generated.source) {
+			t.Errorf("%s is out of date with the admin API types; run go generate ./pkg/client", generated.path)
+		}
+	}
+}
+
+// TestOperationsRouted fails when an operation of the OpenAPI document has
+// no route in Handler. The engine is left nil: a handler reaching it has
+// been routed, so its panic counts as a match.
+func TestOperationsRouted(t *testing.T) {
+	s := NewServer(context.Background(), registry.NewService(t.TempDir()), nil, nil)
+	handler := s.Handler()
+
+	for _, op := range operations {
+		path := op.path
+		for name, value := range map[string]string{"{action}": "pause", "{scope}": "engine"} {
+			path = strings.ReplaceAll(path, name, value)
+		}
+		for strings.Contains(path, "{") {
+			start := strings.Index(path, "{")
+			end := strings.Index(path[start:], "}")
+			path = path[:start] + "x" + path[start+end+1:]
+		}
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(strings.ToUpper(op.method), path, nil)
+		if !served(handler, rec, req) {
+			continue
+		}
+
+		var details struct {
+			Detail string `json:"detail"`
+		}
+		json.Unmarshal(rec.Body.Bytes(), &details)
+		switch {
+		case rec.Code == http.StatusMethodNotAllowed,
+			rec.Code == http.StatusNotFound && (details.Detail == "not found" ||
+				strings.HasPrefix(details.Detail, "no route for ") ||
+				strings.HasPrefix(details.Detail, "unknown bulk action") ||
+				strings.HasPrefix(details.Detail, "unknown digest action")):
+			t.Errorf("%s %s (%s) is not routed: %d %s", op.method, op.path, op.id, rec.Code, details.Detail)
+		}
+	}
+}
+
+// served serves a request, reporting false when the handler panicked
+func served(handler http.Handler, w http.ResponseWriter, r *http.Request) (ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+	handler.ServeHTTP(w, r)
+	return true
+}
@@ -0,0 +1,59 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: api-config
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Config Reload API
+ */
+
+package api
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ReloadReport reports what a config reload changed
+type ReloadReport struct {
+	// Applied lists the settings applied without a restart
+	Applied []string `json:"applied"`
+	// RestartRequired lists the changed settings that only take effect
+	// on the next start
+	RestartRequired []string  `json:"restart_required"`
+	ReloadedAt      time.Time `json:"reloaded_at"`
+}
+
+// OnReload sets the hook reloading the daemon config
+func (s *Server) OnReload(fn func(ctx context.Context) (*ReloadReport, error)) {
+	s.reload = fn
+}
+
+// handleConfigReload reloads the daemon config, as SIGHUP does
+func (s *Server) handleConfigReload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if s.reload == nil {
+		writeError(w, http.StatusNotFound, "daemon config cannot be reloaded")
+		return
+	}
+	report, err := s.reload(r.Context())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	log.Printf("[API] Reloaded config, applied: %s", strings.Join(report.Applied, ", "))
+	writeJSON(w, http.StatusOK, report)
+}
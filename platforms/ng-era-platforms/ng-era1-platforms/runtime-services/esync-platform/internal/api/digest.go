@@ -0,0 +1,66 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: api-digests
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Digest API
+ */
+
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/machine-native-ops/esync-platform/internal/engine"
+)
+
+// handleDigests lists the configured digests and when they are sent
+func (s *Server) handleDigests(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.engine.Digests())
+}
+
+// handleDigest previews /digests/{name} as it would be sent now, or sends
+// it now on POST /digests/{name}/send
+func (s *Server) handleDigest(w http.ResponseWriter, r *http.Request) {
+	name, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/digests/"), "/")
+
+	var digest *engine.Digest
+	var err error
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		digest, err = s.engine.PreviewDigest(name)
+	case action == "send" && r.Method == http.MethodPost:
+		digest, err = s.engine.SendDigest(r.Context(), name)
+	case action == "" || action == "send":
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	default:
+		writeError(w, http.StatusNotFound, "unknown digest action "+action)
+		return
+	}
+	switch {
+	case errors.Is(err, engine.ErrUnknownDigest):
+		writeError(w, http.StatusNotFound, err.Error())
+	case err != nil && action == "send":
+		writeError(w, http.StatusBadGateway, err.Error())
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	default:
+		writeJSON(w, http.StatusOK, digest)
+	}
+}
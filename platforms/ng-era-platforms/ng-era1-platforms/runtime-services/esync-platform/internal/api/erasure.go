@@ -0,0 +1,109 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: data-erasure
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Erasure Request API
+ */
+
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/machine-native-ops/esync-platform/internal/engine"
+)
+
+// handleErasures lists erasure requests, or starts one for ?subject= with
+// an optional ?reason= and ?selector=
+func (s *Server) handleErasures(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		requests, err := s.engine.Erasures()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, requests)
+	case http.MethodPost:
+		q := r.URL.Query()
+		if q.Get("subject") == "" {
+			writeError(w, http.StatusBadRequest, "subject required for erasure requests")
+			return
+		}
+		req, err := s.engine.StartErasure(s.ctx, q.Get("subject"), q.Get("reason"), q.Get("selector"))
+		if errors.Is(err, engine.ErrNoErasurePipelines) {
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusAccepted, req)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// handleErasure routes /erasures/{id}[/report|/retry] requests
+func (s *Server) handleErasure(w http.ResponseWriter, r *http.Request) {
+	id, resource, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/erasures/"), "/")
+	method, status := http.MethodGet, http.StatusOK
+	if resource == "retry" {
+		method, status = http.MethodPost, http.StatusAccepted
+	}
+	if r.Method != method {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var (
+		out interface{}
+		err error
+	)
+	switch resource {
+	case "retry":
+		var req *engine.ErasureRequest
+		req, err = s.engine.RetryErasure(s.ctx, id)
+		if errors.Is(err, engine.ErrErasureNotFailed) {
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		}
+		if req != nil {
+			out = req
+		}
+	case "":
+		var req *engine.ErasureRequest
+		if req, err = s.engine.Erasure(id); req != nil {
+			out = req
+		}
+	case "report":
+		var report *engine.ErasureReport
+		if report, err = s.engine.ErasureReport(id); report != nil {
+			out = report
+		}
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if out == nil {
+		writeError(w, http.StatusNotFound, "erasure request "+id+" not found")
+		return
+	}
+	writeJSON(w, status, out)
+}
@@ -0,0 +1,185 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: api-fleet
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Fleet API
+ */
+
+package api
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/machine-native-ops/esync-platform/internal/fleet"
+)
+
+// HeaderFleetRevision carries the revision of a fleet pipeline bundle
+const HeaderFleetRevision = "Esync-Fleet-Revision"
+
+// FleetPipeline is a fleet pipeline and the agents it is assigned to
+type FleetPipeline struct {
+	ID     string            `json:"id"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Agents []string          `json:"agents"`
+}
+
+// SetFleet makes the daemon manage a fleet of edge agents
+func (s *Server) SetFleet(m *fleet.Manager) {
+	s.fleet = m
+}
+
+// fleetEnabled writes a 404 when the daemon manages no fleet
+func (s *Server) fleetEnabled(w http.ResponseWriter) bool {
+	if s.fleet == nil {
+		writeError(w, http.StatusNotFound, "daemon manages no fleet")
+		return false
+	}
+	return true
+}
+
+// handleFleet returns the status of the fleet aggregated from the beacons
+// of its agents
+func (s *Server) handleFleet(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if !s.fleetEnabled(w) {
+		return
+	}
+	status, err := s.fleetStatus()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+// fleetStatus aggregates the beacons of the agents
+func (s *Server) fleetStatus() (*fleet.Status, error) {
+	agents, err := s.engine.Agents()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.fleet.Annotate(agents...); err != nil {
+		return nil, err
+	}
+	return s.fleet.Status(agents), nil
+}
+
+// handleFleetPipelines lists the fleet pipelines and the agents they are
+// assigned to
+func (s *Server) handleFleetPipelines(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if !s.fleetEnabled(w) {
+		return
+	}
+	agents, err := s.engine.Agents()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	assigned := make(map[string][]string)
+	for _, agent := range agents {
+		for _, id := range s.fleet.Assigned(agent.Labels) {
+			assigned[id] = append(assigned[id], agent.AgentID)
+		}
+	}
+	pipelines := []FleetPipeline{}
+	for _, p := range s.fleet.Pipelines() {
+		fp := FleetPipeline{ID: p.ID, Labels: p.Labels, Agents: assigned[p.ID]}
+		if fp.Agents == nil {
+			fp.Agents = []string{}
+		}
+		pipelines = append(pipelines, fp)
+	}
+	writeJSON(w, http.StatusOK, pipelines)
+}
+
+// handleFleetAgent streams the bundle of the pipelines assigned to an
+// agent, or returns the delta taking the agent to them, by the labels and
+// versions of its last beacon
+func (s *Server) handleFleetAgent(w http.ResponseWriter, r *http.Request) {
+	id, resource, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/fleet/agents/"), "/")
+	if id == "" || (resource != "pipelines" && resource != "delta") {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if !s.fleetEnabled(w) {
+		return
+	}
+	agent, err := s.engine.AgentStatus(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if agent == nil {
+		writeError(w, http.StatusNotFound, "agent "+id+" not found")
+		return
+	}
+
+	if resource == "delta" {
+		delta, err := s.fleet.Delta(id, agent.Labels, agent.Versions)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, delta)
+		return
+	}
+
+	var buf bytes.Buffer
+	manifest, err := s.fleet.Export(&buf, id, agent.Labels)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set(HeaderFleetRevision, manifest.Digest)
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// handleFleetReload reads the fleet file and pipelines again
+func (s *Server) handleFleetReload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if !s.fleetEnabled(w) {
+		return
+	}
+	if err := s.fleet.Reload(r.Context()); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	log.Printf("[API] Reloaded fleet with %d pipelines", len(s.fleet.Pipelines()))
+	status, err := s.fleetStatus()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, status)
+}
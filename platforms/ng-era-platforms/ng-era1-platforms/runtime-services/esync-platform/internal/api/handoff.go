@@ -0,0 +1,110 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: daemon-handoff
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Daemon Handoff API
+ */
+
+package api
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/engine"
+)
+
+// Headers carrying the handoff of a POST /handoff state archive
+const (
+	HeaderHandoffID    = "Esync-Handoff-Id"
+	HeaderHandoffLease = "Esync-Handoff-Lease-Expires"
+)
+
+// handleHandoff returns the last handoff, or begins one with an optional
+// ?lease= and streams the drained state archive
+func (s *Server) handleHandoff(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h := s.engine.CurrentHandoff()
+		if h == nil {
+			writeError(w, http.StatusNotFound, "no handoff")
+			return
+		}
+		writeJSON(w, http.StatusOK, h)
+	case http.MethodPost:
+		var lease time.Duration
+		if v := r.URL.Query().Get("lease"); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil || d <= 0 {
+				writeError(w, http.StatusBadRequest, "lease must be a positive duration")
+				return
+			}
+			lease = d
+		}
+
+		var buf bytes.Buffer
+		h, err := s.engine.BeginHandoff(r.Context(), lease, &buf)
+		if errors.Is(err, engine.ErrHandoff) {
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		w.Header().Set(HeaderHandoffID, h.ID)
+		w.Header().Set(HeaderHandoffLease, h.LeaseExpires.Format(time.RFC3339))
+		w.Header().Set("Content-Type", "application/gzip")
+		w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+		w.WriteHeader(http.StatusOK)
+		w.Write(buf.Bytes())
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// handleHandoffAction routes /handoff/{id}/complete and /handoff/{id}/abort
+func (s *Server) handleHandoffAction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	id, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/handoff/"), "/")
+	var (
+		h   *engine.Handoff
+		err error
+	)
+	switch action {
+	case "complete":
+		h, err = s.engine.CompleteHandoff(id)
+	case "abort":
+		h, err = s.engine.AbortHandoff(id)
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if errors.Is(err, engine.ErrNoHandoff) {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, h)
+}
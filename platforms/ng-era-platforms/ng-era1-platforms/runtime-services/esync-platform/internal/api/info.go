@@ -0,0 +1,192 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: build-info
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Build Info and Runtime Flags API
+ */
+
+package api
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/machine-native-ops/esync-platform/internal/buildinfo"
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/engine"
+	"github.com/machine-native-ops/esync-platform/internal/templatefuncs"
+)
+
+// Info describes the running daemon
+type Info struct {
+	Build      buildinfo.Info `json:"build"`
+	APIVersion string         `json:"api_version"`
+	// Features lists the optional features enabled at startup
+	Features []string `json:"features"`
+	// Connectors lists the registered connector types
+	Connectors []string      `json:"connectors"`
+	Flags      []engine.Flag `json:"flags"`
+}
+
+// SetFeatures records the optional features enabled at startup
+func (s *Server) SetFeatures(features []string) {
+	s.features = features
+}
+
+// handleInfo returns the build, enabled features and runtime flags
+func (s *Server) handleInfo(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	features := s.features
+	if features == nil {
+		features = []string{}
+	}
+	writeJSON(w, http.StatusOK, Info{
+		Build:      buildinfo.Get(),
+		APIVersion: APIVersion,
+		Features:   features,
+		Connectors: connectors.Types(),
+		Flags:      s.engine.Flags(),
+	})
+}
+
+// handleFlags lists the runtime flags
+func (s *Server) handleFlags(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.engine.Flags())
+}
+
+// handleFlag sets /flags/{name} to ?value=
+func (s *Server) handleFlag(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	value := r.URL.Query().Get("value")
+	if value == "" {
+		writeError(w, http.StatusBadRequest, "value required")
+		return
+	}
+	flag, err := s.engine.SetFlag(strings.TrimPrefix(r.URL.Path, "/flags/"), value)
+	if errors.Is(err, engine.ErrUnknownFlag) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, flag)
+}
+
+// handleMemory returns the memory use of the daemon against its limit and
+// the actions taken for memory pressure
+func (s *Server) handleMemory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.engine.Memory())
+}
+
+// TemplateFunctions documents a version of the template function library
+type TemplateFunctions struct {
+	Version   int                      `json:"version"`
+	Latest    int                      `json:"latest"`
+	Functions []templatefuncs.Function `json:"functions"`
+}
+
+// handleTemplateFunctions documents the template function library, the
+// latest version unless ?version= names another
+func (s *Server) handleTemplateFunctions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	version := templatefuncs.Latest
+	if v := r.URL.Query().Get("version"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "version must be an integer")
+			return
+		}
+		version = n
+	}
+	functions, err := templatefuncs.Docs(version)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, TemplateFunctions{Version: version, Latest: templatefuncs.Latest, Functions: functions})
+}
+
+// handleAutoscaling returns the load of the daemon for external scalers
+func (s *Server) handleAutoscaling(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	signal, err := s.engine.Autoscaling()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, signal)
+}
+
+// handleOverview returns the health of every pipeline, worst first, so
+// operators triage the right pipelines first. ?level= keeps the pipelines
+// at the given levels, comma-separated, and ?limit= the worst ones.
+func (s *Server) handleOverview(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	limit := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "limit must be a non-negative integer")
+			return
+		}
+		limit = n
+	}
+
+	pipelines, err := s.engine.FleetHealth()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if v := r.URL.Query().Get("level"); v != "" {
+		levels := strings.Split(v, ",")
+		pipelines = slices.DeleteFunc(pipelines, func(h engine.PipelineHealth) bool { return !slices.Contains(levels, h.Level) })
+	}
+	if limit > 0 && len(pipelines) > limit {
+		pipelines = pipelines[:limit]
+	}
+	writeJSON(w, http.StatusOK, pipelines)
+}
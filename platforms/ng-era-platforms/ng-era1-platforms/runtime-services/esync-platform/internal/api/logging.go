@@ -0,0 +1,99 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: api-log-levels
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Log Level API
+ */
+
+package api
+
+import (
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/logging"
+)
+
+// handleLogLevels returns the log level of the daemon and its overrides
+func (s *Server) handleLogLevels(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, logging.Get())
+}
+
+// handleLogLevel sets the log level of /log-levels/{scope} to ?level=, for
+// ?expires=30m when given, or removes the override with DELETE. Scopes are
+// module names such as engine or registry, connector:<type> and
+// pipeline:<id>.
+func (s *Server) handleLogLevel(w http.ResponseWriter, r *http.Request) {
+	scope := strings.TrimPrefix(r.URL.Path, "/log-levels/")
+	switch r.Method {
+	case http.MethodPost:
+	case http.MethodDelete:
+		override := logging.RemoveOverride(scope)
+		if override == nil {
+			writeError(w, http.StatusNotFound, "no log level override for "+scope)
+			return
+		}
+		log.Printf("[API] Removed log level override of %s", scope)
+		writeJSON(w, http.StatusOK, override)
+		return
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	q := r.URL.Query()
+	level, err := logging.ParseLevel(q.Get("level"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var ttl time.Duration
+	if v := q.Get("expires"); v != "" {
+		if ttl, err = time.ParseDuration(v); err != nil || ttl <= 0 {
+			writeError(w, http.StatusBadRequest, "expires must be a positive duration")
+			return
+		}
+	}
+	if kind, name, ok := strings.Cut(scope, ":"); ok {
+		switch {
+		case kind == "pipeline":
+			if _, err := s.registry.GetByID(name); err != nil {
+				writeError(w, http.StatusNotFound, err.Error())
+				return
+			}
+		case kind == "connector" && !slices.Contains(connectors.Types(), name):
+			writeError(w, http.StatusNotFound, "unknown connector type "+name)
+			return
+		}
+	}
+
+	override, err := logging.SetOverride(scope, level, ttl)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if ttl > 0 {
+		log.Printf("[API] Log level of %s set to %s for %s", scope, level, ttl)
+	} else {
+		log.Printf("[API] Log level of %s set to %s", scope, level)
+	}
+	writeJSON(w, http.StatusOK, override)
+}
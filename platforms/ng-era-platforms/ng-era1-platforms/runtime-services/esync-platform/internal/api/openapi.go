@@ -0,0 +1,455 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: admin-api-openapi
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * OpenAPI Document for the Admin API
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/buildinfo"
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/cutover"
+	"github.com/machine-native-ops/esync-platform/internal/engine"
+	"github.com/machine-native-ops/esync-platform/internal/fleet"
+	"github.com/machine-native-ops/esync-platform/internal/logging"
+	"github.com/machine-native-ops/esync-platform/internal/problem"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+	"github.com/machine-native-ops/esync-platform/internal/replay"
+	"github.com/machine-native-ops/esync-platform/internal/secrets"
+	"github.com/machine-native-ops/esync-platform/internal/state"
+	"github.com/machine-native-ops/esync-platform/internal/templatefuncs"
+)
+
+// APIVersion is the version of the admin API contract
+const APIVersion = "1.0.0"
+
+// operation documents one route of the admin API
+type operation struct {
+	method, path string
+	id, summary  string
+	query        []string
+	response     interface{}
+	status       int
+	errors       []int
+	// produces and consumes name non-JSON response and request bodies; a
+	// response given with produces is the schema of each streamed event
+	produces, consumes string
+	// request is the type of a gzipped JSON request body
+	request interface{}
+}
+
+// operations is the route table the OpenAPI document is generated from.
+// Keep it in step with Handler and handlePipeline; TestOperationsRouted
+// fails for operations they do not route.
+var operations = []operation{
+	{method: "get", path: "/audit", id: "listAuditEntries", summary: "List policy decisions, newest first", query: []string{"pipeline", "limit"}, response: []engine.AuditEntry{}, errors: []int{400, 500}},
+	{method: "get", path: "/orphans", id: "getOrphanReport", summary: "Get the last report of connector resources no registered pipeline owns", response: engine.OrphanReport{}, errors: []int{404, 500}},
+	{method: "post", path: "/orphans", id: "checkOrphans", summary: "Ask the connectors for their resources and report those no registered pipeline owns", response: engine.OrphanReport{}, errors: []int{500}},
+	{method: "get", path: "/erasures", id: "listErasures", summary: "List erasure requests, newest first", response: []engine.ErasureRequest{}, errors: []int{500}},
+	{method: "post", path: "/erasures", id: "startErasure", summary: "Erase a data subject from every pipeline declaring erasure", query: []string{"subject", "reason", "selector"}, response: engine.ErasureRequest{}, status: http.StatusAccepted, errors: []int{400, 409}},
+	{method: "get", path: "/erasures/{id}", id: "getErasure", summary: "Get an erasure request and its per-target progress", response: engine.ErasureRequest{}, errors: []int{404, 500}},
+	{method: "post", path: "/erasures/{id}/retry", id: "retryErasure", summary: "Retry the failed targets of an erasure request", response: engine.ErasureRequest{}, status: http.StatusAccepted, errors: []int{404, 409, 500}},
+	{method: "get", path: "/erasures/{id}/report", id: "getErasureReport", summary: "Get the compliance report of an erasure request", response: engine.ErasureReport{}, errors: []int{404, 500}},
+	{method: "get", path: "/backup", id: "backupState", summary: "Download a gzipped tar archive of checkpoints, workflow state, audit log and run history", produces: "application/gzip", errors: []int{500}},
+	{method: "post", path: "/restore", id: "restoreState", summary: "Replace the daemon state with a backup archive", consumes: "application/gzip", response: state.Manifest{}, errors: []int{400, 409, 500}},
+	{method: "get", path: "/handoff", id: "getHandoff", summary: "Get the last handoff of this daemon", response: engine.Handoff{}, errors: []int{404}},
+	{method: "post", path: "/handoff", id: "beginHandoff", summary: "Drain every pipeline and lease them to a new daemon, returning the state archive", query: []string{"lease"}, produces: "application/gzip", errors: []int{400, 409, 500}},
+	{method: "post", path: "/handoff/{id}/complete", id: "completeHandoff", summary: "Confirm that the new daemon has taken over the pipelines", response: engine.Handoff{}, errors: []int{409}},
+	{method: "post", path: "/handoff/{id}/abort", id: "abortHandoff", summary: "Release a leased handoff and resume the pipelines here", response: engine.Handoff{}, errors: []int{409}},
+	{method: "get", path: "/agent", id: "getAgentBeacon", summary: "Get the beacon this daemon posts to its central daemon when running as an edge agent", response: engine.AgentBeacon{}, errors: []int{404}},
+	{method: "get", path: "/agents", id: "listAgents", summary: "List the edge agents reporting to this daemon and whether they are online", response: []engine.AgentStatus{}, errors: []int{500}},
+	{method: "get", path: "/agents/{id}", id: "getAgent", summary: "Get the last beacon of an edge agent", response: engine.AgentStatus{}, errors: []int{404, 500}},
+	{method: "put", path: "/agents/{id}", id: "putAgentBeacon", summary: "Record the gzipped JSON status beacon of an edge agent", consumes: "application/gzip", request: engine.AgentBeacon{}, response: engine.AgentStatus{}, errors: []int{400, 500}},
+	{method: "get", path: "/fleet", id: "getFleet", summary: "Get the status of the managed fleet aggregated from the beacons of its agents", response: fleet.Status{}, errors: []int{404, 500}},
+	{method: "get", path: "/fleet/pipelines", id: "listFleetPipelines", summary: "List the pipelines distributed to the fleet and the agents they are assigned to", response: []FleetPipeline{}, errors: []int{404, 500}},
+	{method: "get", path: "/fleet/agents/{id}/pipelines", id: "getFleetAgentPipelines", summary: "Download the bundle of the pipelines assigned to an agent; the Esync-Fleet-Revision header carries its revision", produces: "application/gzip", errors: []int{404, 500}},
+	{method: "get", path: "/fleet/agents/{id}/delta", id: "getFleetAgentDelta", summary: "Get the signed delta taking an agent from the pipeline versions of its last beacon to the pipelines assigned to it", response: fleet.Delta{}, errors: []int{404, 500}},
+	{method: "post", path: "/fleet:reload", id: "reloadFleet", summary: "Read the fleet file and pipelines again", response: fleet.Status{}, errors: []int{400, 404, 500}},
+	{method: "post", path: "/config:reload", id: "reloadConfig", summary: "Reload the daemon config as SIGHUP does, applying the log level, alert routes, rate limits and connection profiles and reporting the changed settings that require a restart", response: ReloadReport{}, errors: []int{400, 404}},
+	{method: "get", path: "/bandwidth", id: "getBandwidth", summary: "Get the bandwidth caps of the agent and pipelines and the bytes metered over the last days (default 7)", query: []string{"days"}, response: engine.BandwidthReport{}, errors: []int{400, 500}},
+	{method: "get", path: "/connections", id: "listConnections", summary: "List connection profiles", response: []registry.Connection{}},
+	{method: "get", path: "/pipelines", id: "listPipelines", summary: "Search pipelines by labels, connector types and text; Esync-Total-Count holds the number of matches", query: []string{"selector", "label", "source.type", "target.type", "text", "sort", "offset", "limit"}, response: []registry.Pipeline{}, errors: []int{400}},
+	{method: "get", path: "/pipelines:export", id: "exportPipelines", summary: "Download a bundle of the pipelines matching a selector, their overlays and the connection profiles they reference", query: []string{"selector"}, produces: "application/gzip", errors: []int{400, 500}},
+	{method: "post", path: "/pipelines:import", id: "importPipelines", summary: "Register the pipelines of a bundle, skipping, overwriting or renaming those whose ID is taken", query: []string{"on_conflict", "dry_run"}, consumes: "application/gzip", response: registry.ImportReport{}, errors: []int{400}},
+	{method: "get", path: "/pipelines/watch", id: "watchPipelines", summary: "Stream status transitions and run events of the pipelines matching a selector as server-sent events, starting with the current status of each", query: []string{"selector"}, produces: "text/event-stream", response: engine.Event{}, errors: []int{400}},
+	{method: "get", path: "/pipelines/{id}", id: "getPipeline", summary: "Get a pipeline definition", response: registry.Pipeline{}, errors: []int{404}},
+	{method: "post", path: "/pipelines/{id}:clone", id: "clonePipeline", summary: "Copy a pipeline under a new ID into a definition file of its own, optionally with other connections, description and labels", query: []string{"id", "description", "source_connection", "target_connection", "label"}, response: registry.Pipeline{}, status: http.StatusCreated, errors: []int{400, 404, 409}},
+	{method: "post", path: "/pipelines/{id}:simulate", id: "simulatePipeline", summary: "Replay recorded runs, the last fixture by default, through the pipeline or a gzipped candidate definition against an in-memory target and report what it would have written", query: []string{"speed"}, consumes: "application/gzip", request: SimulationRequest{}, response: replay.Simulation{}, errors: []int{400, 404, 500}},
+	{method: "post", path: "/pipelines/{id}:promote", id: "promotePipeline", summary: "Carry a pipeline's settings from one environment into another's overlay, keeping the target environment's connections", query: []string{"from", "to", "dry_run"}, response: registry.Promotion{}, errors: []int{400, 404}},
+	{method: "get", path: "/pipelines/{id}/explain", id: "explainPipeline", summary: "Explain the fully resolved pipeline", response: engine.Explanation{}, errors: []int{404, 500}},
+	{method: "get", path: "/pipelines/{id}/health", id: "getPipelineHealth", summary: "Get the health score of the pipeline from its recent failures, lag against its objective, what holds it and dead-letter growth", response: engine.PipelineHealth{}, errors: []int{404, 500}},
+	{method: "get", path: "/pipelines/{id}/status", id: "getPipelineStatus", summary: "Get runtime state and progress", response: PipelineStatus{}, errors: []int{404, 500}},
+	{method: "get", path: "/pipelines/{id}/tables", id: "listTables", summary: "List per-table progress of a multi-table pipeline", response: []engine.TableState{}, errors: []int{404, 500}},
+	{method: "get", path: "/pipelines/{id}/compatibility", id: "checkSchemas", summary: "Compare source and target schemas without running the pipeline", response: engine.CompatibilityReport{}, errors: []int{404, 500}},
+	{method: "get", path: "/pipelines/{id}/ddl", id: "listDDLChanges", summary: "List captured schema changes", response: []engine.DDLChange{}, errors: []int{404, 500}},
+	{method: "post", path: "/pipelines/{id}/ddl/{change}/approve", id: "approveDDLChange", summary: "Apply a pending schema change to the target", response: engine.DDLChange{}, errors: []int{404, 409, 502}},
+	{method: "post", path: "/pipelines/{id}/ddl/{change}/reject", id: "rejectDDLChange", summary: "Skip a pending schema change", response: engine.DDLChange{}, errors: []int{404, 409}},
+	{method: "get", path: "/pipelines/{id}/runs", id: "listRuns", summary: "List recent runs, newest first", response: []engine.Run{}, errors: []int{404}},
+	{method: "get", path: "/pipelines/{id}/runs/{a}/diff/{b}", id: "diffRuns", summary: "Compare the counts, durations, error groups and schema of run b against run a, each a run ID, latest or last-good", response: engine.RunDiff{}, errors: []int{404, 500}},
+	{method: "post", path: "/pipelines/{id}/triggers", id: "startTrigger", summary: "Run a sync pass once per idempotency key (Idempotency-Key header or query) under a run ID derived from it, long-polling for its completion and posting the final state to an optional callback URL", query: []string{"idempotency_key", "wait", "callback"}, response: TriggerStatus{}, errors: []int{202, 400, 404, 409}},
+	{method: "get", path: "/pipelines/{id}/triggers/{key}", id: "getTrigger", summary: "Get the state of a trigger, long-polling for its completion", query: []string{"wait"}, response: TriggerStatus{}, errors: []int{202, 400, 404}},
+	{method: "post", path: "/pipelines/{id}/runs", id: "triggerRun", summary: "Run a sync pass, optionally recording its input as a replay fixture; requests repeating an Idempotency-Key header return the run of the first", query: []string{"record"}, response: engine.Run{}, errors: []int{400, 404, 409, 500}},
+	{method: "post", path: "/pipelines/{id}/pause", id: "pausePipeline", summary: "Pause a pipeline", response: PauseState{}, errors: []int{404}},
+	{method: "post", path: "/pipelines/{id}/resume", id: "resumePipeline", summary: "Resume a pipeline", response: PauseState{}, errors: []int{404}},
+	{method: "get", path: "/pipelines/{id}/preflight", id: "getPreflight", summary: "Get the last pre-flight report", response: engine.PreflightReport{}, errors: []int{404}},
+	{method: "post", path: "/pipelines/{id}/preflight", id: "runPreflight", summary: "Re-run pre-flight checks", response: engine.PreflightReport{}, errors: []int{404, 422}},
+	{method: "get", path: "/pipelines/{id}/backfill", id: "getBackfill", summary: "Get backfill state", response: engine.BackfillState{}, errors: []int{404, 500}},
+	{method: "post", path: "/pipelines/{id}/backfill", id: "startBackfill", summary: "Start or resume a chunked backfill", response: engine.BackfillState{}, status: http.StatusAccepted, errors: []int{409}},
+	{method: "get", path: "/pipelines/{id}/snapshot", id: "getSnapshot", summary: "Get the state of the last incremental snapshot", response: engine.IncrementalSnapshot{}, errors: []int{404, 500}},
+	{method: "post", path: "/pipelines/{id}/snapshot", id: "requestSnapshot", summary: "Re-snapshot selected tables or a key range in chunks interleaved with the incremental stream", query: []string{"table", "start", "end"}, response: engine.IncrementalSnapshot{}, status: http.StatusAccepted, errors: []int{400, 404, 409}},
+	{method: "delete", path: "/pipelines/{id}/snapshot", id: "cancelSnapshot", summary: "Cancel the unfinished incremental snapshot of a pipeline", response: engine.IncrementalSnapshot{}, errors: []int{404, 500}},
+	{method: "get", path: "/pipelines/{id}/cutover", id: "getCutover", summary: "Get cutover state", response: cutover.State{}, errors: []int{404, 500}},
+	{method: "post", path: "/pipelines/{id}/cutover", id: "startCutover", summary: "Start or resume the cutover workflow", response: cutover.State{}, status: http.StatusAccepted, errors: []int{409}},
+	{method: "get", path: "/pipelines/{id}/standby", id: "getStandby", summary: "Get the failover barrier of a standby pipeline", response: engine.StandbyState{}, errors: []int{404, 409}},
+	{method: "post", path: "/pipelines/{id}/standby", id: "failover", summary: "Release the barrier of a standby pipeline: stop priming, apply the final delta and promote the target, even if the delta fails with force", query: []string{"force"}, response: engine.StandbyState{}, status: http.StatusAccepted, errors: []int{400, 404, 409}},
+	{method: "delete", path: "/pipelines/{id}/standby", id: "rearmStandby", summary: "Hold the barrier of a standby pipeline again and resume priming", response: engine.StandbyState{}, errors: []int{404, 409}},
+	{method: "get", path: "/pipelines/{id}/confirmation", id: "getConfirmation", summary: "Get the digest of the current definition of a destructive pipeline, such as a cleanup pipeline, and whether it is confirmed", response: engine.ConfirmationState{}, errors: []int{400, 404, 500}},
+	{method: "post", path: "/pipelines/{id}/confirmation", id: "confirmDefinition", summary: "Confirm the reviewed definition of a destructive pipeline, as a second person or for a change ticket, and resume it", query: []string{"definition", "ticket", "by"}, response: engine.Confirmation{}, errors: []int{400, 404, 409, 500}},
+	{method: "get", path: "/pipelines/{id}/rollout", id: "getRollout", summary: "Get the staged rollout of the pipeline definition", response: engine.Rollout{}, errors: []int{404, 500}},
+	{method: "post", path: "/pipelines/{id}/rollout", id: "promoteRollout", summary: "Promote the changed definition of a staged rollout to all keys", response: engine.Rollout{}, errors: []int{404, 409, 500}},
+	{method: "delete", path: "/pipelines/{id}/rollout", id: "rollBackRollout", summary: "Roll back a staged rollout, applying the stable definition to all keys until the definition changes again", response: engine.Rollout{}, errors: []int{404, 409, 500}},
+	{method: "get", path: "/pipelines/{id}/holdback", id: "getHoldback", summary: "Get whether writes of a pipeline to its target are held back and how many records are spooled", response: engine.Holdback{}, errors: []int{404, 500}},
+	{method: "post", path: "/pipelines/{id}/holdback", id: "setHoldback", summary: "Hold back writes of a pipeline to its target until released, or until a time or for a duration; changes keep being read and are spooled", query: []string{"reason", "by", "until", "for"}, response: engine.Holdback{}, errors: []int{400, 404}},
+	{method: "delete", path: "/pipelines/{id}/holdback", id: "releaseHoldback", summary: "Release the holdback of a pipeline's target; the spooled records are applied in the background", response: engine.Holdback{}, errors: []int{404, 500}},
+	{method: "get", path: "/pipelines/{id}/lookup-cache", id: "getLookupCaches", summary: "Get the hits, misses and size of the lookup caches of the pipeline's connectors", response: []engine.LookupCacheState{}, errors: []int{404, 500}},
+	{method: "delete", path: "/pipelines/{id}/lookup-cache", id: "clearLookupCaches", summary: "Clear the lookup caches of the pipeline's connectors, in Redis too when shared", response: []engine.LookupCacheState{}, errors: []int{404, 500}},
+	{method: "get", path: "/pipelines/{id}/trace", id: "getTrace", summary: "Get the active verbose trace of a pipeline", response: engine.Trace{}, errors: []int{404}},
+	{method: "post", path: "/pipelines/{id}/trace", id: "startTrace", summary: "Trace a pipeline verbosely for a while, extending an active trace", query: []string{"duration"}, response: engine.Trace{}, errors: []int{400, 404}},
+	{method: "delete", path: "/pipelines/{id}/trace", id: "stopTrace", summary: "Stop the verbose trace of a pipeline", response: engine.Trace{}, errors: []int{404}},
+	{method: "post", path: "/pipelines/{id}/teardown", id: "teardownPipeline", summary: "Release the source and target resources of a paused pipeline, or list them on a dry run", query: []string{"dry_run"}, response: engine.TeardownReport{}, errors: []int{400, 404, 409, 502}},
+	{method: "get", path: "/pipelines/{id}/fixture", id: "getFixture", summary: "Get the replay fixture last recorded for a pipeline", response: engine.Fixture{}, errors: []int{404, 500}},
+	{method: "get", path: "/pipelines/{id}/dead-letters", id: "listDeadLetters", summary: "List the records set aside for exceeding the record limits of a pipeline, oldest first", response: []engine.DeadLetter{}, errors: []int{404, 500}},
+	{method: "delete", path: "/pipelines/{id}/dead-letters", id: "drainDeadLetters", summary: "Empty the dead-letter queue of a pipeline, returning the letters removed", response: []engine.DeadLetter{}, errors: []int{404, 500}},
+	{method: "get", path: "/info", id: "getInfo", summary: "Get the build, enabled features, connector types and runtime flags", response: Info{}},
+	{method: "get", path: "/schemas/connectors/{type}.json", id: "getConnectorSchema", summary: "Get the JSON Schema of the config block of a connector type", response: map[string]interface{}{}, produces: "application/schema+json", errors: []int{404}},
+	{method: "get", path: "/flags", id: "listFlags", summary: "List the runtime diagnostic flags", response: []engine.Flag{}},
+	{method: "post", path: "/flags/{name}", id: "setFlag", summary: "Change a runtime diagnostic flag until restart", query: []string{"value"}, response: engine.Flag{}, errors: []int{400, 404}},
+	{method: "get", path: "/log-levels", id: "getLogLevels", summary: "Get the log level of the daemon and the overrides of modules, connector types and pipelines", response: logging.Levels{}},
+	{method: "post", path: "/log-levels/{scope}", id: "setLogLevel", summary: "Override the log level of a module such as engine or registry, connector:<type> or pipeline:<id>, optionally until it expires", query: []string{"level", "expires"}, response: logging.Override{}, errors: []int{400, 404}},
+	{method: "delete", path: "/log-levels/{scope}", id: "removeLogLevel", summary: "Remove the log level override of a scope", response: logging.Override{}, errors: []int{404}},
+	{method: "get", path: "/overview", id: "getOverview", summary: "Get the health of every pipeline, worst first, with operator-paused pipelines last", query: []string{"level", "limit"}, response: []engine.PipelineHealth{}, errors: []int{400, 500}},
+	{method: "get", path: "/autoscaling", id: "getAutoscaling", summary: "Get the lag and queue depth of the pipelines normalized to their lag targets, for KEDA or HPA external scalers", response: engine.Autoscaling{}, errors: []int{500}},
+	{method: "get", path: "/digests", id: "listDigests", summary: "List the scheduled digests and when they are next sent", response: []engine.DigestStatus{}},
+	{method: "get", path: "/digests/{name}", id: "previewDigest", summary: "Preview a digest as it would be sent now, covering the period since it was last sent", response: engine.Digest{}, errors: []int{404, 500}},
+	{method: "post", path: "/digests/{name}/send", id: "sendDigest", summary: "Send a digest to its target now without starting a new period, to try out the target", response: engine.Digest{}, errors: []int{404, 502}},
+	{method: "get", path: "/template-functions", id: "getTemplateFunctions", summary: "Document a version of the function library of templated transforms and notify templates, the latest by default", query: []string{"version"}, response: TemplateFunctions{}, errors: []int{400}},
+	{method: "get", path: "/memory", id: "getMemory", summary: "Get the memory use against the GOMEMLIMIT or cgroup limit, the memory pressure level and the actions taken for it", response: engine.MemoryStatus{}},
+	{method: "post", path: "/bulk/{action}", id: "bulkAction", summary: "Pause, resume or trigger every pipeline matching a selector", query: []string{"selector"}, response: []BulkResult{}, errors: []int{400, 404}},
+}
+
+// binary is the schema of non-JSON bodies
+var binary = map[string]interface{}{"type": "string", "format": "binary"}
+
+// schemaNames renames component schemas whose Go names are ambiguous or
+// taken by another type
+var schemaNames = map[reflect.Type]string{
+	reflect.TypeOf(cutover.State{}):          "CutoverState",
+	reflect.TypeOf(state.Manifest{}):         "BackupManifest",
+	reflect.TypeOf(problem.Details{}):        "Problem",
+	reflect.TypeOf(fleet.Status{}):           "FleetStatus",
+	reflect.TypeOf(fleet.Delta{}):            "FleetDelta",
+	reflect.TypeOf(fleet.PipelineStatus{}):   "FleetPipelineStatus",
+	reflect.TypeOf(logging.Levels{}):         "LogLevels",
+	reflect.TypeOf(logging.Override{}):       "LogLevelOverride",
+	reflect.TypeOf(buildinfo.Info{}):         "BuildInfo",
+	reflect.TypeOf(templatefuncs.Function{}): "TemplateFunction",
+	reflect.TypeOf(secrets.Reference{}):      "SecretReference",
+	reflect.TypeOf(connectors.Field{}):       "SchemaField",
+	// Record and Event are taken by TypeScript built-ins
+	reflect.TypeOf(connectors.Record{}): "SyncRecord",
+	reflect.TypeOf(engine.Event{}):      "PipelineEvent",
+}
+
+// handleOpenAPI serves the OpenAPI 3 document of the admin API
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, OpenAPI())
+}
+
+// OpenAPI generates the OpenAPI 3 document of the admin API
+func OpenAPI() map[string]interface{} {
+	doc, _ := document()
+	return doc
+}
+
+// Schemas returns the Go types of the component schemas of the OpenAPI
+// document by name, which the clients are generated from
+func Schemas() map[string]reflect.Type {
+	_, components := document()
+	return components.types
+}
+
+// document generates the OpenAPI document and collects its components
+func document() (map[string]interface{}, *components) {
+	components := newComponents()
+	components.schema(reflect.TypeOf(problem.Details{}))
+
+	paths := map[string]interface{}{}
+	for _, op := range operations {
+		status := op.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		content := map[string]interface{}{"schema": binary}
+		contentType := "application/json"
+		if op.produces != "" {
+			contentType = op.produces
+		}
+		if op.produces == "" || op.response != nil {
+			content = map[string]interface{}{"schema": components.schema(reflect.TypeOf(op.response))}
+		}
+		responses := map[string]interface{}{
+			strconv.Itoa(status): map[string]interface{}{
+				"description": http.StatusText(status),
+				"content":     map[string]interface{}{contentType: content},
+			},
+		}
+		for _, code := range op.errors {
+			responses[strconv.Itoa(code)] = map[string]interface{}{
+				"description": http.StatusText(code),
+				"content": map[string]interface{}{
+					problem.ContentType: map[string]interface{}{"schema": ref("Problem")},
+				},
+			}
+		}
+
+		var params []interface{}
+		for _, segment := range strings.Split(op.path, "/") {
+			// Segments may end in a custom method, as in {id}:clone
+			if name, ok := strings.CutPrefix(segment, "{"); ok {
+				name, _, _ = strings.Cut(name, "}")
+				params = append(params, parameter(name, "path", true))
+			}
+		}
+		for _, q := range op.query {
+			params = append(params, parameter(q, "query", false))
+		}
+
+		entry := map[string]interface{}{
+			"operationId": op.id,
+			"summary":     op.summary,
+			"responses":   responses,
+		}
+		if len(params) > 0 {
+			entry["parameters"] = params
+		}
+		if op.consumes != "" {
+			body := map[string]interface{}{
+				"required": true,
+				"content":  map[string]interface{}{op.consumes: map[string]interface{}{"schema": binary}},
+			}
+			if op.request != nil {
+				ref := components.schema(reflect.TypeOf(op.request))
+				body["description"] = "Gzipped JSON of " + strings.TrimPrefix(ref["$ref"].(string), "#/components/schemas/")
+			}
+			entry["requestBody"] = body
+		}
+
+		item, _ := paths[op.path].(map[string]interface{})
+		if item == nil {
+			item = map[string]interface{}{}
+			paths[op.path] = item
+		}
+		item[op.method] = entry
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   "esync admin API",
+			"version": APIVersion,
+		},
+		"paths":      paths,
+		"components": map[string]interface{}{"schemas": components.schemas},
+	}, components
+}
+
+// components collects the component schemas of the OpenAPI document and
+// the Go types they are generated from
+type components struct {
+	schemas map[string]interface{}
+	types   map[string]reflect.Type
+}
+
+// newComponents creates an empty set of component schemas
+func newComponents() *components {
+	return &components{
+		schemas: make(map[string]interface{}),
+		types:   make(map[string]reflect.Type),
+	}
+}
+
+// schema returns the schema of a Go type, registering named structs as
+// components. Two structs of the same name panic until one is renamed in
+// schemaNames, so a schema cannot silently describe the wrong type.
+func (c *components) schema(t reflect.Type) map[string]interface{} {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t {
+	case reflect.TypeOf(time.Time{}):
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case reflect.TypeOf(time.Duration(0)):
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.TypeOf(json.RawMessage{}):
+		return map[string]interface{}{}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// encoding/json marshals byte slices as base64 strings
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": c.schema(t.Elem())}
+	case reflect.Map:
+		s := map[string]interface{}{"type": "object"}
+		if t.Elem().Kind() != reflect.Interface {
+			s["additionalProperties"] = c.schema(t.Elem())
+		}
+		return s
+	case reflect.Struct:
+		name := SchemaName(t)
+		if other, exists := c.types[name]; exists {
+			if other != t {
+				panic(fmt.Sprintf("api: schema %s names both %s and %s; rename one in schemaNames", name, other, t))
+			}
+			return ref(name)
+		}
+		c.types[name] = t
+		properties := map[string]interface{}{}
+		c.schemas[name] = map[string]interface{}{"type": "object", "properties": properties}
+		for _, f := range jsonFields(t) {
+			properties[f.name] = c.schema(f.Type)
+		}
+		return ref(name)
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// SchemaName returns the name of the component schema of a Go struct type
+func SchemaName(t reflect.Type) string {
+	if name := schemaNames[t]; name != "" {
+		return name
+	}
+	if t.Name() == "" {
+		panic(fmt.Sprintf("api: anonymous struct %s has no schema name", t))
+	}
+	return t.Name()
+}
+
+// jsonField is a struct field as encoding/json marshals it
+type jsonField struct {
+	reflect.StructField
+	// name is the JSON key of the field
+	name      string
+	omitEmpty bool
+	// owner is the struct declaring the field, an embedded one for promoted
+	// fields
+	owner reflect.Type
+	depth int
+}
+
+// jsonFields returns the fields of a struct encoding/json marshals, in
+// order, with the fields of untagged embedded structs promoted in place.
+// Of fields sharing a name, the least deeply embedded one wins.
+func jsonFields(t reflect.Type) []jsonField {
+	var fields []jsonField
+	var collect func(t reflect.Type, depth int)
+	collect = func(t reflect.Type, depth int) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if tag == "-" && opts == "" {
+				continue
+			}
+			embedded := f.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if f.Anonymous && tag == "" && embedded.Kind() == reflect.Struct {
+				collect(embedded, depth+1)
+				continue
+			}
+			if !f.IsExported() {
+				continue
+			}
+			if tag == "" {
+				tag = f.Name
+			}
+			fields = append(fields, jsonField{
+				StructField: f,
+				name:        tag,
+				omitEmpty:   strings.Contains(","+opts+",", ",omitempty,"),
+				owner:       t,
+				depth:       depth,
+			})
+		}
+	}
+	collect(t, 0)
+
+	depth := make(map[string]int)
+	for _, f := range fields {
+		if d, seen := depth[f.name]; !seen || f.depth < d {
+			depth[f.name] = f.depth
+		}
+	}
+	out := fields[:0]
+	for _, f := range fields {
+		if f.depth == depth[f.name] {
+			out = append(out, f)
+			depth[f.name] = -1
+		}
+	}
+	return out
+}
+
+// ref references a component schema
+func ref(name string) map[string]interface{} {
+	return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+}
+
+// parameter documents a path or query parameter
+func parameter(name, in string, required bool) map[string]interface{} {
+	return map[string]interface{}{
+		"name":     name,
+		"in":       in,
+		"required": required,
+		"schema":   map[string]interface{}{"type": "string"},
+	}
+}
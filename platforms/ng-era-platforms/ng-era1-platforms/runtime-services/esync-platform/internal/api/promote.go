@@ -0,0 +1,133 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: pipeline-promotion
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Pipeline Cloning and Promotion API
+ */
+
+package api
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// OnPipelineAdded sets the hook that schedules pipelines registered through
+// the API, such as clones
+func (s *Server) OnPipelineAdded(fn func(*registry.Pipeline) error) {
+	s.added = fn
+}
+
+// handlePipelineAction serves the custom methods /pipelines/{id}:clone,
+// /pipelines/{id}:promote and /pipelines/{id}:simulate
+func (s *Server) handlePipelineAction(w http.ResponseWriter, r *http.Request, id, action string) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if _, err := s.registry.GetByID(id); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	switch action {
+	case "clone":
+		s.clonePipeline(w, r, id)
+	case "promote":
+		s.promotePipeline(w, r, id)
+	case "simulate":
+		s.simulatePipeline(w, r, id)
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+// clonePipeline copies a pipeline under ?id=, optionally changing its
+// ?description=, ?source_connection=, ?target_connection= and ?label=key:value
+// labels; an empty value removes a label
+func (s *Server) clonePipeline(w http.ResponseWriter, r *http.Request, id string) {
+	query := r.URL.Query()
+	opts := registry.CloneOptions{
+		ID:               query.Get("id"),
+		Description:      query.Get("description"),
+		SourceConnection: query.Get("source_connection"),
+		TargetConnection: query.Get("target_connection"),
+		Labels:           make(map[string]string),
+	}
+	if opts.ID == "" {
+		writeError(w, http.StatusBadRequest, "id is required")
+		return
+	}
+	for _, v := range query["label"] {
+		for _, term := range strings.Split(v, ",") {
+			key, value, _ := strings.Cut(strings.TrimSpace(term), ":")
+			if key == "" {
+				writeError(w, http.StatusBadRequest, "invalid label "+strconv.Quote(term)+", expected key:value or key:")
+				return
+			}
+			opts.Labels[key] = value
+		}
+	}
+
+	clone, err := s.registry.Clone(id, opts)
+	if errors.Is(err, registry.ErrDuplicateID) {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	log.Printf("[API] Cloned pipeline %s as %s (%s)", id, clone.ID, clone.File)
+
+	if s.added != nil {
+		if err := s.added(clone); err != nil {
+			log.Printf("[API] Failed to schedule pipeline %s: %v", clone.ID, err)
+		}
+	}
+	writeJSON(w, http.StatusCreated, clone)
+}
+
+// promotePipeline carries a pipeline from the ?from= environment, the
+// daemon's by default, into the ?to= environment's overlay, or only reports
+// the outcome with ?dry_run=true
+func (s *Server) promotePipeline(w http.ResponseWriter, r *http.Request, id string) {
+	query := r.URL.Query()
+	dryRun := false
+	if v := query.Get("dry_run"); v != "" {
+		on, err := strconv.ParseBool(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "dry_run must be true or false")
+			return
+		}
+		dryRun = on
+	}
+	if query.Get("to") == "" {
+		writeError(w, http.StatusBadRequest, "to is required")
+		return
+	}
+
+	promotion, err := s.registry.Promote(r.Context(), id, query.Get("from"), query.Get("to"), dryRun)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !dryRun {
+		log.Printf("[API] Promoted pipeline %s from %q to %q (%s)", id, promotion.From, promotion.To, promotion.Overlay)
+	}
+	writeJSON(w, http.StatusOK, promotion)
+}
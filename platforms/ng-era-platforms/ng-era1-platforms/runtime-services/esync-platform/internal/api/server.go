@@ -0,0 +1,1212 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: admin-api
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Admin REST API
+ */
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/cutover"
+	"github.com/machine-native-ops/esync-platform/internal/engine"
+	"github.com/machine-native-ops/esync-platform/internal/fleet"
+	"github.com/machine-native-ops/esync-platform/internal/listener"
+	"github.com/machine-native-ops/esync-platform/internal/problem"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// Server exposes the admin REST API of the daemon
+type Server struct {
+	// ctx scopes background work started through the API, such as cutovers
+	ctx      context.Context
+	registry *registry.Service
+	engine   *engine.Engine
+	cutover  *cutover.Orchestrator
+	mounts   map[string]http.Handler
+	features []string
+	// added schedules pipelines registered through the API
+	added func(*registry.Pipeline) error
+	// nextRun returns the next scheduled run of a pipeline
+	nextRun func(pipelineID string) (time.Time, bool)
+	// fleet distributes pipelines to the agents reporting to the daemon
+	fleet *fleet.Manager
+	// reload reloads the daemon config
+	reload func(ctx context.Context) (*ReloadReport, error)
+}
+
+// NewServer creates a new admin API server
+func NewServer(ctx context.Context, reg *registry.Service, eng *engine.Engine, co *cutover.Orchestrator) *Server {
+	return &Server{
+		ctx:      ctx,
+		registry: reg,
+		engine:   eng,
+		cutover:  co,
+		mounts:   make(map[string]http.Handler),
+	}
+}
+
+// OnNextRun sets the hook reporting the next scheduled run of a pipeline
+// in its status
+func (s *Server) OnNextRun(fn func(pipelineID string) (time.Time, bool)) {
+	s.nextRun = fn
+}
+
+// Mount serves an additional handler under the API listener
+func (s *Server) Mount(pattern string, handler http.Handler) {
+	s.mounts[pattern] = handler
+}
+
+// Handler returns the HTTP handler serving the API
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/pipelines", s.handlePipelines)
+	mux.HandleFunc("/pipelines/", s.handlePipeline)
+	mux.HandleFunc("/pipelines/watch", s.handleWatch)
+	mux.HandleFunc("/pipelines:export", s.handleExport)
+	mux.HandleFunc("/pipelines:import", s.handleImport)
+	mux.HandleFunc("/connections", s.handleConnections)
+	mux.HandleFunc("/audit", s.handleAudit)
+	mux.HandleFunc("/orphans", s.handleOrphans)
+	mux.HandleFunc("/erasures", s.handleErasures)
+	mux.HandleFunc("/erasures/", s.handleErasure)
+	mux.HandleFunc("/backup", s.handleBackup)
+	mux.HandleFunc("/restore", s.handleRestore)
+	mux.HandleFunc("/handoff", s.handleHandoff)
+	mux.HandleFunc("/handoff/", s.handleHandoffAction)
+	mux.HandleFunc("/bulk/", s.handleBulk)
+	mux.HandleFunc("/agent", s.handleAgent)
+	mux.HandleFunc("/agents", s.handleAgents)
+	mux.HandleFunc("/agents/", s.handleAgentBeacon)
+	mux.HandleFunc("/fleet", s.handleFleet)
+	mux.HandleFunc("/fleet/pipelines", s.handleFleetPipelines)
+	mux.HandleFunc("/fleet/agents/", s.handleFleetAgent)
+	mux.HandleFunc("/fleet:reload", s.handleFleetReload)
+	mux.HandleFunc("/bandwidth", s.handleBandwidth)
+	mux.HandleFunc("/digests", s.handleDigests)
+	mux.HandleFunc("/digests/", s.handleDigest)
+	mux.HandleFunc("/info", s.handleInfo)
+	mux.HandleFunc("/config:reload", s.handleConfigReload)
+	mux.HandleFunc("/flags", s.handleFlags)
+	mux.HandleFunc("/flags/", s.handleFlag)
+	mux.HandleFunc("/log-levels", s.handleLogLevels)
+	mux.HandleFunc("/log-levels/", s.handleLogLevel)
+	mux.HandleFunc("/memory", s.handleMemory)
+	mux.HandleFunc("/autoscaling", s.handleAutoscaling)
+	mux.HandleFunc("/overview", s.handleOverview)
+	mux.HandleFunc("/template-functions", s.handleTemplateFunctions)
+	mux.HandleFunc("/schemas/pipeline.json", s.handlePipelineSchema)
+	mux.HandleFunc("/schemas/connectors/", s.handleConnectorSchema)
+	mux.HandleFunc("/openapi.json", s.handleOpenAPI)
+	for pattern, handler := range s.mounts {
+		mux.Handle(pattern, handler)
+	}
+	if s.mounts["/"] == nil {
+		mux.HandleFunc("/", problem.NotFound)
+	}
+	return problem.Middleware(mux)
+}
+
+// Start starts the admin API server on a listener address
+func (s *Server) Start(addr string) error {
+	listeners, err := listener.Listen(addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(listeners)
+}
+
+// Serve serves the admin API on bound listeners
+func (s *Server) Serve(listeners []net.Listener) error {
+	log.Printf("[API] Starting admin API on %s", listener.Addrs(listeners))
+	return listener.Serve(listeners, s.Handler())
+}
+
+// HeaderTotalCount carries the number of pipelines a search matched across
+// all pages
+const HeaderTotalCount = "Esync-Total-Count"
+
+// handlePipelines searches pipelines by ?selector=, ?label=key:value,
+// ?source.type=, ?target.type= and ?text=, ordered by ?sort= and paged by
+// ?offset= and ?limit=. The number of matches across all pages is returned
+// in the Esync-Total-Count header.
+func (s *Server) handlePipelines(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	sel, err := registry.ParseSelector(query.Get("selector"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	q := registry.Query{
+		Labels:     make(map[string]string),
+		Selector:   sel,
+		SourceType: query.Get("source.type"),
+		TargetType: query.Get("target.type"),
+		Text:       query.Get("text"),
+		Sort:       query.Get("sort"),
+	}
+	for _, v := range query["label"] {
+		for _, term := range strings.Split(v, ",") {
+			key, value, _ := strings.Cut(strings.TrimSpace(term), ":")
+			if key == "" {
+				writeError(w, http.StatusBadRequest, "invalid label "+strconv.Quote(term)+", expected key:value or key")
+				return
+			}
+			q.Labels[key] = value
+		}
+	}
+	for _, param := range []struct {
+		name string
+		dst  *int
+	}{{"offset", &q.Offset}, {"limit", &q.Limit}} {
+		if v := query.Get(param.name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				writeError(w, http.StatusBadRequest, param.name+" must be a non-negative integer")
+				return
+			}
+			*param.dst = n
+		}
+	}
+
+	result, err := s.registry.Search(q)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	w.Header().Set(HeaderTotalCount, strconv.Itoa(result.Total))
+	writeJSON(w, http.StatusOK, result.Pipelines)
+}
+
+// handleConnections lists the connection profiles with literal passwords
+// redacted
+func (s *Server) handleConnections(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	connections := s.registry.Connections()
+	out := make([]*registry.Connection, 0, len(connections))
+	for _, c := range connections {
+		out = append(out, c.Redacted())
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// handleOrphans returns the last orphaned resource report on GET and runs
+// a new check on POST
+func (s *Server) handleOrphans(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		report, err := s.engine.OrphanReport()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if report == nil {
+			writeError(w, http.StatusNotFound, "no orphan check has run yet")
+			return
+		}
+		writeJSON(w, http.StatusOK, report)
+	case http.MethodPost:
+		report, err := s.engine.CheckOrphans(r.Context())
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, report)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// handleAudit lists policy decisions, newest first, filtered by ?pipeline=
+// and bounded by ?limit=
+func (s *Server) handleAudit(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	limit := 100
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "limit must be a non-negative integer")
+			return
+		}
+		limit = n
+	}
+
+	entries, err := s.engine.AuditLog(r.URL.Query().Get("pipeline"), limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, entries)
+}
+
+// handlePipelineSchema serves the JSON Schema of the pipeline YAML format
+func (s *Server) handlePipelineSchema(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/schema+json")
+	w.Write(registry.PipelineSchema)
+}
+
+// handleConnectorSchema serves the config schema of a connector type at
+// /schemas/connectors/{type}.json
+func (s *Server) handleConnectorSchema(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	name, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/schemas/connectors/"), ".json")
+	schema, exists := connectors.ConfigSchema(name)
+	if !ok || !exists {
+		writeError(w, http.StatusNotFound, "unknown connector type")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/schema+json")
+	w.Write(schema)
+}
+
+// handlePipeline routes /pipelines/{id}[/{resource}] requests
+func (s *Server) handlePipeline(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/pipelines/"), "/")
+	id := parts[0]
+	if id == "" {
+		writeError(w, http.StatusNotFound, "pipeline id required")
+		return
+	}
+
+	if id, action, ok := strings.Cut(id, ":"); ok && len(parts) == 1 {
+		s.handlePipelineAction(w, r, id, action)
+		return
+	}
+
+	resource := ""
+	if len(parts) > 1 {
+		resource = strings.Join(parts[1:], "/")
+	}
+
+	switch {
+	case resource == "" && r.Method == http.MethodGet:
+		s.getPipeline(w, id)
+	case resource == "explain" && r.Method == http.MethodGet:
+		s.explainPipeline(w, id)
+	case resource == "status" && r.Method == http.MethodGet:
+		s.getStatus(w, id)
+	case resource == "tables" && r.Method == http.MethodGet:
+		s.listTables(w, id)
+	case resource == "health" && r.Method == http.MethodGet:
+		s.getHealth(w, id)
+	case resource == "compatibility" && r.Method == http.MethodGet:
+		s.checkSchemas(w, r, id)
+	case resource == "ddl" && r.Method == http.MethodGet:
+		s.listDDL(w, id)
+	case strings.HasPrefix(resource, "ddl/") && r.Method == http.MethodPost:
+		s.decideDDL(w, r, id, strings.TrimPrefix(resource, "ddl/"))
+	case resource == "runs" && r.Method == http.MethodGet:
+		s.listRuns(w, id)
+	case resource == "runs" && r.Method == http.MethodPost:
+		s.triggerRun(w, r, id)
+	case strings.HasPrefix(resource, "runs/") && r.Method == http.MethodGet:
+		s.diffRuns(w, id, strings.TrimPrefix(resource, "runs/"))
+	case resource == "triggers" && r.Method == http.MethodPost:
+		s.startTrigger(w, r, id)
+	case strings.HasPrefix(resource, "triggers/") && r.Method == http.MethodGet:
+		s.getTrigger(w, r, id, strings.TrimPrefix(resource, "triggers/"))
+	case resource == "pause" && r.Method == http.MethodPost:
+		s.setPaused(w, id, true)
+	case resource == "resume" && r.Method == http.MethodPost:
+		s.setPaused(w, id, false)
+	case resource == "preflight" && r.Method == http.MethodGet:
+		s.getPreflight(w, id)
+	case resource == "preflight" && r.Method == http.MethodPost:
+		s.runPreflight(w, r, id)
+	case resource == "backfill" && r.Method == http.MethodGet:
+		s.getBackfill(w, id)
+	case resource == "backfill" && r.Method == http.MethodPost:
+		s.startBackfill(w, id)
+	case resource == "snapshot" && r.Method == http.MethodGet:
+		s.getSnapshot(w, id)
+	case resource == "snapshot" && r.Method == http.MethodPost:
+		s.requestSnapshot(w, r, id)
+	case resource == "snapshot" && r.Method == http.MethodDelete:
+		s.cancelSnapshot(w, id)
+	case resource == "cutover" && r.Method == http.MethodGet:
+		s.getCutover(w, id)
+	case resource == "cutover" && r.Method == http.MethodPost:
+		s.startCutover(w, id)
+	case resource == "standby":
+		s.handleStandby(w, r, id)
+	case resource == "confirmation":
+		s.handleConfirmation(w, r, id)
+	case resource == "rollout":
+		s.handleRollout(w, r, id)
+	case resource == "lookup-cache":
+		s.handleLookupCache(w, r, id)
+	case resource == "holdback":
+		s.handleHoldback(w, r, id)
+	case resource == "trace":
+		s.handleTrace(w, r, id)
+	case resource == "fixture" && r.Method == http.MethodGet:
+		s.getFixture(w, id)
+	case resource == "dead-letters" && (r.Method == http.MethodGet || r.Method == http.MethodDelete):
+		s.handleDeadLetters(w, r, id)
+	case resource == "teardown" && r.Method == http.MethodPost:
+		s.teardown(w, r, id)
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+// getPipeline returns a single pipeline definition
+func (s *Server) getPipeline(w http.ResponseWriter, id string) {
+	p, err := s.registry.GetByID(id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, p)
+}
+
+// explainPipeline returns the fully resolved pipeline: overlays, defaults,
+// expanded templates, redacted secrets, transform order and the delivery
+// guarantee
+func (s *Server) explainPipeline(w http.ResponseWriter, id string) {
+	x, err := s.engine.Explain(id)
+	if err != nil {
+		if _, lookupErr := s.registry.GetByID(id); lookupErr != nil {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, x)
+}
+
+// PipelineStatus is the runtime state of a pipeline
+type PipelineStatus struct {
+	PipelineID string      `json:"pipeline_id"`
+	Paused     bool        `json:"paused"`
+	Running    bool        `json:"running"`
+	CurrentRun *engine.Run `json:"current_run,omitempty"`
+	LastRun    *engine.Run `json:"last_run,omitempty"`
+	// ErrorGroups aggregates the errors of the pipeline since startup
+	ErrorGroups []engine.ErrorGroup `json:"error_groups,omitempty"`
+	// Bootstrap is the last target bootstrap, for pipelines with bootstrap
+	// enabled
+	Bootstrap *engine.BootstrapState `json:"bootstrap,omitempty"`
+	// PII lists the fields classify_pii transforms sampled
+	PII []engine.PIIFinding `json:"pii,omitempty"`
+	// Trace is the active verbose trace of the pipeline
+	Trace *engine.Trace `json:"trace,omitempty"`
+	// Canary is the last canary record sent through the pipeline
+	Canary *engine.Canary `json:"canary,omitempty"`
+	// Source is what the last sync pass saw of the source: whether it is
+	// active, idle or stuck and how far it lags
+	Source *engine.SourceActivity `json:"source,omitempty"`
+	// SLO is the freshness of the pipeline against its objective
+	SLO *engine.SLOStatus `json:"slo,omitempty"`
+	// Shed is set while automatic triggers of a bulk pipeline are deferred
+	// for pipelines burning their freshness objective
+	Shed bool `json:"shed,omitempty"`
+	// Owner, Runbook, Tier and Docs are the pipeline's documentation, so
+	// on-call knows who to page
+	Owner   string `json:"owner,omitempty"`
+	Runbook string `json:"runbook,omitempty"`
+	Tier    string `json:"tier,omitempty"`
+	Docs    string `json:"docs,omitempty"`
+	// ActiveHours tells whether a pipeline limited to active hours is
+	// inside them and when that changes
+	ActiveHours *engine.ActiveHoursStatus `json:"active_hours,omitempty"`
+	// Schedule is the next scheduled run of a scheduled pipeline
+	Schedule *ScheduleStatus `json:"schedule,omitempty"`
+	// Health is the composite health score of the pipeline
+	Health *engine.PipelineHealth `json:"health,omitempty"`
+	// Holdback is set while writes to the target are held back
+	Holdback *engine.Holdback `json:"holdback,omitempty"`
+	// SourceLoad tells how the source load limits hold back reads
+	SourceLoad *engine.SourceLoadStatus `json:"source_load,omitempty"`
+}
+
+// ScheduleStatus reports the next scheduled run in UTC and in the time zone
+// of the schedule
+type ScheduleStatus struct {
+	Timezone     string    `json:"timezone"`
+	NextRun      time.Time `json:"next_run"`
+	NextRunLocal time.Time `json:"next_run_local"`
+}
+
+// scheduleStatus returns the next scheduled run of a pipeline, nil when
+// none is scheduled
+func (s *Server) scheduleStatus(p *registry.Pipeline) *ScheduleStatus {
+	if p.Schedule == nil || s.nextRun == nil {
+		return nil
+	}
+	at, ok := s.nextRun(p.ID)
+	if !ok {
+		return nil
+	}
+	loc, err := p.Schedule.Location()
+	if err != nil {
+		return nil
+	}
+	return &ScheduleStatus{Timezone: loc.String(), NextRun: at.UTC(), NextRunLocal: at.In(loc)}
+}
+
+// getStatus returns the runtime state of a pipeline, including progress of
+// the in-flight run
+func (s *Server) getStatus(w http.ResponseWriter, id string) {
+	p, err := s.registry.GetByID(id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	paused, err := s.engine.Paused(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	bootstrap, err := s.engine.BootstrapStatus(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	pii, err := s.engine.PIIFindings(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	health, err := s.engine.Health(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	holdback, err := s.engine.Holdback(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !holdback.Held {
+		holdback = nil
+	}
+
+	current := s.engine.CurrentRun(id)
+	writeJSON(w, http.StatusOK, PipelineStatus{
+		PipelineID:  id,
+		Paused:      paused,
+		Running:     current != nil,
+		CurrentRun:  current,
+		LastRun:     s.engine.LastRun(id),
+		ErrorGroups: s.engine.ErrorGroups(id),
+		Bootstrap:   bootstrap,
+		PII:         pii,
+		Trace:       s.engine.ActiveTrace(id),
+		Canary:      s.engine.LastCanary(id),
+		Source:      s.engine.SourceActivity(id),
+		SLO:         s.engine.SLOStatus(id),
+		Shed:        s.engine.Shed(id),
+		Owner:       p.Owner,
+		Runbook:     p.Runbook,
+		Tier:        p.Tier,
+		Docs:        p.Docs,
+		ActiveHours: s.engine.ActiveHours(p),
+		Schedule:    s.scheduleStatus(p),
+		Health:      health,
+		Holdback:    holdback,
+		SourceLoad:  s.engine.SourceLoad(p),
+	})
+}
+
+// getHealth returns the health score of a pipeline and what lowered it
+func (s *Server) getHealth(w http.ResponseWriter, id string) {
+	if _, err := s.registry.GetByID(id); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	health, err := s.engine.Health(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, health)
+}
+
+// listTables returns the per-table progress of a multi-table pipeline
+func (s *Server) listTables(w http.ResponseWriter, id string) {
+	if _, err := s.registry.GetByID(id); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	tables, err := s.engine.Tables(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, tables)
+}
+
+// checkSchemas compares the source and target schemas of a pipeline
+// without running it
+func (s *Server) checkSchemas(w http.ResponseWriter, r *http.Request, id string) {
+	if _, err := s.registry.GetByID(id); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	report, err := s.engine.CheckSchemas(r.Context(), id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+// listDDL returns the captured schema changes of a pipeline
+func (s *Server) listDDL(w http.ResponseWriter, id string) {
+	if _, err := s.registry.GetByID(id); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	changes, err := s.engine.DDLChanges(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, changes)
+}
+
+// decideDDL approves or rejects a pending schema change; the path is
+// {change}/approve or {change}/reject
+func (s *Server) decideDDL(w http.ResponseWriter, r *http.Request, id, path string) {
+	parts := strings.Split(path, "/")
+	if len(parts) != 2 || (parts[1] != "approve" && parts[1] != "reject") {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if _, err := s.registry.GetByID(id); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	change, err := s.engine.DecideDDL(r.Context(), id, parts[0], parts[1] == "approve")
+	switch {
+	case errors.Is(err, engine.ErrDDLNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, engine.ErrDDLDecided):
+		writeError(w, http.StatusConflict, err.Error())
+	case err != nil:
+		writeError(w, http.StatusBadGateway, err.Error())
+	default:
+		writeJSON(w, http.StatusOK, change)
+	}
+}
+
+// listRuns returns the recent runs of a pipeline, newest first
+func (s *Server) listRuns(w http.ResponseWriter, id string) {
+	if _, err := s.registry.GetByID(id); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.engine.Runs(id))
+}
+
+// diffRuns compares run b of a pipeline against run a for
+// /pipelines/{id}/runs/{a}/diff/{b}; runs are given by ID, latest or
+// last-good
+func (s *Server) diffRuns(w http.ResponseWriter, id, path string) {
+	a, b, ok := strings.Cut(path, "/diff/")
+	if !ok || a == "" || b == "" || strings.Contains(b, "/") {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if _, err := s.registry.GetByID(id); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	diff, err := s.engine.DiffRuns(id, a, b)
+	switch {
+	case errors.Is(err, engine.ErrRunNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	default:
+		writeJSON(w, http.StatusOK, diff)
+	}
+}
+
+// triggerRun executes a sync pass and returns the run result; with
+// ?record=true its input is saved as the replay fixture of the pipeline.
+// Requests repeating an Idempotency-Key header return the run of the first.
+func (s *Server) triggerRun(w http.ResponseWriter, r *http.Request, id string) {
+	if _, err := s.registry.GetByID(id); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	metadata := requestMetadata(r)
+	if key := r.Header.Get("Idempotency-Key"); key != "" {
+		metadata[engine.IdempotencyMetadata] = key
+	}
+	if record := r.URL.Query().Get("record"); record != "" {
+		on, err := strconv.ParseBool(record)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "record must be true or false")
+			return
+		}
+		if on {
+			metadata[engine.RecordMetadata] = "true"
+		}
+	}
+	trigger := engine.Trigger{Type: engine.TriggerManual, Metadata: metadata}
+	var run *engine.Run
+	var err error
+	if metadata[engine.IdempotencyMetadata] != "" {
+		run, err = s.runIdempotent(r, id, trigger)
+	} else {
+		run, err = s.engine.RunOnce(r.Context(), id, trigger)
+	}
+	if errors.Is(err, engine.ErrPaused) || errors.Is(err, engine.ErrHandoff) || errors.Is(err, engine.ErrRunInProgress) || errors.Is(err, engine.ErrPreflightFailed) || errors.Is(err, engine.ErrResidency) {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil && run == nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, run)
+}
+
+// runIdempotent starts a trigger carrying an idempotency key detached from
+// the request, as startTrigger does, and waits for its run. A client giving
+// up then leaves the run going, and its retry gets that run back rather
+// than one cancelled with the request.
+func (s *Server) runIdempotent(r *http.Request, id string, trigger engine.Trigger) (*engine.Run, error) {
+	call, err := s.engine.TriggerIdempotent(s.ctx, id, trigger)
+	if err != nil {
+		return nil, err
+	}
+	select {
+	case <-call.Done():
+		return call.Result()
+	case <-r.Context().Done():
+		return nil, r.Context().Err()
+	}
+}
+
+// getFixture returns the replay fixture last recorded for a pipeline
+func (s *Server) getFixture(w http.ResponseWriter, id string) {
+	if _, err := s.registry.GetByID(id); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	f, err := s.engine.Fixture(id)
+	if errors.Is(err, engine.ErrNoFixture) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, f)
+}
+
+// handleDeadLetters lists the dead-letter queue of a pipeline or, on
+// DELETE, drains it, returning the letters removed
+func (s *Server) handleDeadLetters(w http.ResponseWriter, r *http.Request, id string) {
+	if _, err := s.registry.GetByID(id); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		letters, err := s.engine.DeadLetters(id)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, letters)
+		return
+	}
+
+	letters, err := s.engine.ClearDeadLetters(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	log.Printf("[API] Drained %d dead letters of pipeline %s", len(letters), id)
+	writeJSON(w, http.StatusOK, letters)
+}
+
+// PauseState reports whether a pipeline is paused
+type PauseState struct {
+	PipelineID string `json:"pipeline_id"`
+	Paused     bool   `json:"paused"`
+}
+
+// setPaused pauses or resumes a pipeline
+func (s *Server) setPaused(w http.ResponseWriter, id string, paused bool) {
+	action := s.engine.Resume
+	if paused {
+		action = s.engine.Pause
+	}
+
+	if err := action(id); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, PauseState{PipelineID: id, Paused: paused})
+}
+
+// getPreflight returns the last pre-flight report of a pipeline
+func (s *Server) getPreflight(w http.ResponseWriter, id string) {
+	report := s.engine.PreflightReport(id)
+	if report == nil {
+		writeError(w, http.StatusNotFound, "no pre-flight report for pipeline "+id)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
+
+// runPreflight re-runs the pre-flight checks of a pipeline
+func (s *Server) runPreflight(w http.ResponseWriter, r *http.Request, id string) {
+	report, err := s.engine.Preflight(r.Context(), id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	status := http.StatusOK
+	if !report.Passed {
+		status = http.StatusUnprocessableEntity
+	}
+	writeJSON(w, status, report)
+}
+
+// getBackfill returns the chunk-level state of the pipeline backfill
+func (s *Server) getBackfill(w http.ResponseWriter, id string) {
+	st, err := s.engine.BackfillStatus(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if st == nil {
+		writeError(w, http.StatusNotFound, "no backfill for pipeline "+id)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, st)
+}
+
+// startBackfill starts or resumes a chunked backfill in the background
+func (s *Server) startBackfill(w http.ResponseWriter, id string) {
+	st, err := s.engine.StartBackfill(s.ctx, id)
+	if err != nil {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, st)
+}
+
+// getSnapshot returns the chunk-level state of the last incremental snapshot
+func (s *Server) getSnapshot(w http.ResponseWriter, id string) {
+	snap, err := s.engine.SnapshotStatus(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if snap == nil {
+		writeError(w, http.StatusNotFound, "no incremental snapshot for pipeline "+id)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, snap)
+}
+
+// requestSnapshot queues an incremental snapshot of the comma-separated
+// ?table= and the key range [?start=, ?end=); its chunks are applied by the
+// following sync passes
+func (s *Server) requestSnapshot(w http.ResponseWriter, r *http.Request, id string) {
+	if _, err := s.registry.GetByID(id); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	q := r.URL.Query()
+	var tables []string
+	for _, table := range strings.Split(q.Get("table"), ",") {
+		if table = strings.TrimSpace(table); table != "" {
+			tables = append(tables, table)
+		}
+	}
+	keys := connectors.KeyRange{Start: q.Get("start"), End: q.Get("end")}
+
+	snap, err := s.engine.RequestSnapshot(r.Context(), id, tables, keys)
+	if errors.Is(err, engine.ErrSnapshotInProgress) {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, snap)
+}
+
+// cancelSnapshot stops the unfinished incremental snapshot of a pipeline
+func (s *Server) cancelSnapshot(w http.ResponseWriter, id string) {
+	snap, err := s.engine.CancelSnapshot(id)
+	if errors.Is(err, engine.ErrNoSnapshot) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, snap)
+}
+
+// teardown releases the connector resources of a paused pipeline about to
+// be removed; with ?dry_run=true it only lists them
+func (s *Server) teardown(w http.ResponseWriter, r *http.Request, id string) {
+	if _, err := s.registry.GetByID(id); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	dryRun := false
+	if v := r.URL.Query().Get("dry_run"); v != "" {
+		on, err := strconv.ParseBool(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "dry_run must be true or false")
+			return
+		}
+		dryRun = on
+	}
+	report, err := s.engine.Teardown(r.Context(), id, dryRun)
+	if errors.Is(err, engine.ErrNotPaused) {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
+
+// getCutover returns the cutover state; ready=true signals the application
+// may switch over to the target
+func (s *Server) getCutover(w http.ResponseWriter, id string) {
+	st, err := s.cutover.Status(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if st == nil {
+		writeError(w, http.StatusNotFound, "no cutover for pipeline "+id)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, st)
+}
+
+// startCutover begins or resumes the cutover workflow in the background
+func (s *Server) startCutover(w http.ResponseWriter, id string) {
+	st, err := s.cutover.Start(s.ctx, id)
+	if err != nil {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, st)
+}
+
+// handleStandby returns the failover barrier of a standby pipeline,
+// releases it on POST or holds it again on DELETE
+func (s *Server) handleStandby(w http.ResponseWriter, r *http.Request, id string) {
+	if _, err := s.registry.GetByID(id); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	var (
+		st     *engine.StandbyState
+		err    error
+		status = http.StatusOK
+	)
+	switch r.Method {
+	case http.MethodGet:
+		st, err = s.engine.Standby(id)
+	case http.MethodPost:
+		force := false
+		if v := r.URL.Query().Get("force"); v != "" {
+			if force, err = strconv.ParseBool(v); err != nil {
+				writeError(w, http.StatusBadRequest, "force must be true or false")
+				return
+			}
+		}
+		st, err = s.engine.Failover(s.ctx, id, force)
+		status = http.StatusAccepted
+	case http.MethodDelete:
+		st, err = s.engine.Rearm(id)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+
+	writeJSON(w, status, st)
+}
+
+// handleConfirmation returns whether the definition of a destructive
+// pipeline is confirmed or, on POST, confirms the definition named by
+// ?definition= for the optional ?ticket= and ?by=
+func (s *Server) handleConfirmation(w http.ResponseWriter, r *http.Request, id string) {
+	if _, err := s.registry.GetByID(id); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		st, err := s.engine.Confirmation(id)
+		if errors.Is(err, engine.ErrNotDestructive) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, st)
+	case http.MethodPost:
+		q := r.URL.Query()
+		if q.Get("definition") == "" {
+			writeError(w, http.StatusBadRequest, "definition required: the digest of the reviewed definition from GET /pipelines/"+id+"/confirmation")
+			return
+		}
+		c, err := s.engine.Confirm(id, q.Get("definition"), q.Get("ticket"), q.Get("by"))
+		switch {
+		case errors.Is(err, engine.ErrNotDestructive):
+			writeError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, engine.ErrDefinitionChanged):
+			writeError(w, http.StatusConflict, err.Error())
+		case err != nil && c == nil:
+			writeError(w, http.StatusInternalServerError, err.Error())
+		default:
+			if err != nil {
+				log.Printf("[API] Failed to resume pipeline %s after confirmation: %v", id, err)
+			}
+			writeJSON(w, http.StatusOK, c)
+		}
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// handleRollout reports the staged rollout of a pipeline's definition on
+// GET, promotes it to all keys on POST and rolls it back on DELETE
+func (s *Server) handleRollout(w http.ResponseWriter, r *http.Request, id string) {
+	if _, err := s.registry.GetByID(id); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	var (
+		rollout *engine.Rollout
+		err     error
+	)
+	switch r.Method {
+	case http.MethodGet:
+		rollout, err = s.engine.Rollout(id)
+	case http.MethodPost:
+		rollout, err = s.engine.PromoteRollout(id)
+	case http.MethodDelete:
+		rollout, err = s.engine.RollBack(id)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	switch {
+	case errors.Is(err, engine.ErrNoRollout):
+		writeError(w, http.StatusConflict, err.Error())
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	default:
+		writeJSON(w, http.StatusOK, rollout)
+	}
+}
+
+// handleLookupCache reports the lookup caches of a pipeline's connectors on
+// GET and clears them on DELETE
+func (s *Server) handleLookupCache(w http.ResponseWriter, r *http.Request, id string) {
+	if _, err := s.registry.GetByID(id); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	var (
+		states []engine.LookupCacheState
+		err    error
+	)
+	switch r.Method {
+	case http.MethodGet:
+		states, err = s.engine.LookupCaches(id)
+	case http.MethodDelete:
+		states, err = s.engine.ClearLookupCaches(r.Context(), id)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if states == nil {
+		states = []engine.LookupCacheState{}
+	}
+	writeJSON(w, http.StatusOK, states)
+}
+
+// handleHoldback returns, sets or releases the holdback of a pipeline's
+// target. POST takes ?reason=, ?by= and either ?until= as an RFC 3339 time
+// or ?for= as a duration; without them the holdback lasts until released.
+func (s *Server) handleHoldback(w http.ResponseWriter, r *http.Request, id string) {
+	if _, err := s.registry.GetByID(id); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	var (
+		holdback *engine.Holdback
+		err      error
+	)
+	switch r.Method {
+	case http.MethodGet:
+		holdback, err = s.engine.Holdback(id)
+	case http.MethodPost:
+		query := r.URL.Query()
+		var until time.Time
+		switch {
+		case query.Get("until") != "" && query.Get("for") != "":
+			writeError(w, http.StatusBadRequest, "until and for are exclusive")
+			return
+		case query.Get("until") != "":
+			if until, err = time.Parse(time.RFC3339, query.Get("until")); err != nil {
+				writeError(w, http.StatusBadRequest, "until must be an RFC 3339 time")
+				return
+			}
+		case query.Get("for") != "":
+			d, err := time.ParseDuration(query.Get("for"))
+			if err != nil || d <= 0 {
+				writeError(w, http.StatusBadRequest, "for must be a positive duration such as 2h")
+				return
+			}
+			until = time.Now().Add(d)
+		}
+		holdback, err = s.engine.SetHoldback(id, query.Get("reason"), query.Get("by"), until)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	case http.MethodDelete:
+		holdback, err = s.engine.ReleaseHoldback(id)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, holdback)
+}
+
+// requestMetadata returns the trigger metadata of runs a request starts:
+// where it came from, its correlation ID and its trace context
+func requestMetadata(r *http.Request) map[string]string {
+	metadata := map[string]string{"remote_addr": r.RemoteAddr}
+	if id := problem.CorrelationID(r.Context()); id != "" {
+		metadata[engine.CorrelationMetadata] = id
+	}
+	if tp := problem.Traceparent(r.Context()); tp != "" {
+		metadata[engine.TraceparentMetadata] = tp
+	}
+	return metadata
+}
+
+// writeJSON writes v as a JSON response
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("[API] Failed to encode response: %v", err)
+	}
+}
+
+// writeError writes an error response as problem details
+func writeError(w http.ResponseWriter, status int, message string) {
+	problem.Write(w, status, message)
+}
@@ -0,0 +1,117 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: pipeline-simulation-api
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Pipeline Simulation API
+ */
+
+package api
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/machine-native-ops/esync-platform/internal/engine"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+	"github.com/machine-native-ops/esync-platform/internal/replay"
+)
+
+// maxSimulationSize bounds the compressed request of a simulation
+const maxSimulationSize = 64 << 20
+
+// SimulationRequest selects the definition and the recorded runs a
+// simulation replays
+type SimulationRequest struct {
+	// Definition is a pipeline definition in YAML or JSON replacing the
+	// registered one, such as one with changed transforms
+	Definition string `json:"definition,omitempty"`
+	// Fixtures are recorded runs, such as those synctl record wrote over a
+	// day; the fixture last recorded for the pipeline when empty
+	Fixtures []*engine.Fixture `json:"fixtures,omitempty"`
+}
+
+// simulatePipeline replays recorded runs through a pipeline against an
+// in-memory target and reports what it would have written. The optional
+// body is a gzipped SimulationRequest; ?speed= replays the time between
+// the runs that many times faster.
+func (s *Server) simulatePipeline(w http.ResponseWriter, r *http.Request, id string) {
+	speed := 0.0
+	if v := r.URL.Query().Get("speed"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f < 0 {
+			writeError(w, http.StatusBadRequest, "speed must be a non-negative number")
+			return
+		}
+		speed = f
+	}
+
+	var req SimulationRequest
+	if r.ContentLength != 0 {
+		zr, err := gzip.NewReader(http.MaxBytesReader(w, r.Body, maxSimulationSize))
+		if err != nil && !errors.Is(err, io.EOF) {
+			writeError(w, http.StatusBadRequest, "simulation request must be gzipped JSON")
+			return
+		}
+		if err == nil {
+			if err := json.NewDecoder(zr).Decode(&req); err != nil {
+				writeError(w, http.StatusBadRequest, "invalid simulation request: "+err.Error())
+				return
+			}
+		}
+	}
+
+	p, err := s.registry.GetByID(id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if req.Definition != "" {
+		if p, err = registry.Parse([]byte(req.Definition)); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid definition: "+err.Error())
+			return
+		}
+		if p.ID == "" {
+			p.ID = id
+		}
+		if p.ID != id {
+			writeError(w, http.StatusBadRequest, "definition is of pipeline "+p.ID+", not "+id)
+			return
+		}
+	}
+	fixtures := req.Fixtures
+	if len(fixtures) == 0 {
+		f, err := s.engine.Fixture(id)
+		if errors.Is(err, engine.ErrNoFixture) {
+			writeError(w, http.StatusNotFound, err.Error()+"; record one with POST /pipelines/"+id+"/runs?record=true")
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		fixtures = []*engine.Fixture{f}
+	}
+
+	sim, err := replay.Simulate(r.Context(), fixtures, p, speed)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	log.Printf("[API] Simulated pipeline %s over %d recorded runs: %d of %d records written, %d runs diverged",
+		id, len(sim.Batches), sim.Written, sim.Input, sim.Diverged)
+	writeJSON(w, http.StatusOK, sim)
+}
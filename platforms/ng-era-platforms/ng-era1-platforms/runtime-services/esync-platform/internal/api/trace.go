@@ -0,0 +1,66 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: pipeline-trace
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Pipeline Trace Endpoints
+ */
+
+package api
+
+import (
+	"net/http"
+	"time"
+)
+
+// handleTrace returns, starts (with an optional ?duration=) or stops the
+// verbose trace of a pipeline
+func (s *Server) handleTrace(w http.ResponseWriter, r *http.Request, id string) {
+	if _, err := s.registry.GetByID(id); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		t := s.engine.ActiveTrace(id)
+		if t == nil {
+			writeError(w, http.StatusNotFound, "pipeline "+id+" is not traced")
+			return
+		}
+		writeJSON(w, http.StatusOK, t)
+	case http.MethodPost:
+		var d time.Duration
+		if v := r.URL.Query().Get("duration"); v != "" {
+			parsed, err := time.ParseDuration(v)
+			if err != nil || parsed <= 0 {
+				writeError(w, http.StatusBadRequest, "duration must be a positive duration")
+				return
+			}
+			d = parsed
+		}
+		t, err := s.engine.StartTrace(id, d)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, t)
+	case http.MethodDelete:
+		t := s.engine.StopTrace(id)
+		if t == nil {
+			writeError(w, http.StatusNotFound, "pipeline "+id+" is not traced")
+			return
+		}
+		writeJSON(w, http.StatusOK, t)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
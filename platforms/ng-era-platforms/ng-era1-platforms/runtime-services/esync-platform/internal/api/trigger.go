@@ -0,0 +1,260 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: api-triggers
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Idempotent Run Triggers
+ */
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/egress"
+	"github.com/machine-native-ops/esync-platform/internal/engine"
+)
+
+// Trigger states
+const (
+	TriggerRunning   = "running"
+	TriggerSucceeded = "succeeded"
+	TriggerFailed    = "failed"
+	// TriggerRejected triggers started no run, e.g. because the pipeline
+	// was paused or its run policy rejected the trigger
+	TriggerRejected = "rejected"
+)
+
+const (
+	// maxTriggerWait bounds the long-poll of a trigger request
+	maxTriggerWait = 10 * time.Minute
+	// callbackAttempts bounds the deliveries of a completion callback
+	callbackAttempts = 3
+)
+
+// TriggerStatus is the state of a run triggered through the triggers
+// endpoint. Run is the finished run, or the in-flight run once it started;
+// RunID is known before either.
+type TriggerStatus struct {
+	PipelineID     string      `json:"pipeline_id"`
+	IdempotencyKey string      `json:"idempotency_key"`
+	RunID          string      `json:"run_id"`
+	State          string      `json:"state"`
+	Run            *engine.Run `json:"run,omitempty"`
+	Error          string      `json:"error,omitempty"`
+}
+
+// startTrigger runs a pipeline once per idempotency key, so orchestrators
+// retrying a trigger never start a duplicate run; the engine's idempotency
+// store remembers the run under its key across restarts. The run is
+// detached from the request; ?wait=30s long-polls for its completion (up
+// to 10m, the default) and ?callback=URL receives the final status as a
+// POST.
+func (s *Server) startTrigger(w http.ResponseWriter, r *http.Request, id string) {
+	if _, err := s.registry.GetByID(id); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	q := r.URL.Query()
+	wait, err := triggerWait(q.Get("wait"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	callback := q.Get("callback")
+	if callback != "" {
+		if u, err := url.Parse(callback); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			writeError(w, http.StatusBadRequest, "callback must be an http(s) URL")
+			return
+		}
+	}
+	key := r.Header.Get("Idempotency-Key")
+	if key == "" {
+		key = q.Get("idempotency_key")
+	}
+	if key == "" {
+		key = newTriggerKey()
+	}
+
+	metadata := requestMetadata(r)
+	metadata[engine.IdempotencyMetadata] = key
+	call, err := s.engine.TriggerIdempotent(s.ctx, id, engine.Trigger{Type: engine.TriggerManual, Metadata: metadata})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if callback != "" {
+		go func() {
+			select {
+			case <-call.Done():
+				s.sendCallback(callback, s.triggerStatus(id, key, call))
+			case <-s.ctx.Done():
+			}
+		}()
+	}
+	s.waitTrigger(w, r, id, key, call, wait)
+}
+
+// getTrigger returns the state of a trigger, long-polling with ?wait=30s.
+// The engine answers with the run in flight or recorded under the key;
+// triggers that started no run are not remembered.
+func (s *Server) getTrigger(w http.ResponseWriter, r *http.Request, id, key string) {
+	var wait time.Duration
+	if value := r.URL.Query().Get("wait"); value != "" {
+		var err error
+		if wait, err = triggerWait(value); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	call, err := s.engine.IdempotentTrigger(id, engine.TriggerManual, key)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if call == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("no trigger %s of pipeline %s", key, id))
+		return
+	}
+	s.waitTrigger(w, r, id, key, call, wait)
+}
+
+// waitTrigger answers with the trigger state once the run finished or wait
+// elapsed: 200 for a finished run, 202 for one in progress and 409 for a
+// rejected trigger
+func (s *Server) waitTrigger(w http.ResponseWriter, r *http.Request, id, key string, call *engine.IdempotentCall, wait time.Duration) {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-call.Done():
+	case <-timer.C:
+	case <-r.Context().Done():
+		return
+	}
+
+	status := s.triggerStatus(id, key, call)
+	w.Header().Set("Location", fmt.Sprintf("/pipelines/%s/triggers/%s", url.PathEscape(id), url.PathEscape(key)))
+	switch status.State {
+	case TriggerRunning:
+		writeJSON(w, http.StatusAccepted, status)
+	case TriggerRejected:
+		writeJSON(w, http.StatusConflict, status)
+	default:
+		writeJSON(w, http.StatusOK, status)
+	}
+}
+
+// triggerStatus reports the state of a trigger under the run ID the
+// engine records its run under
+func (s *Server) triggerStatus(id, key string, call *engine.IdempotentCall) TriggerStatus {
+	status := TriggerStatus{PipelineID: id, IdempotencyKey: key, RunID: call.RunID}
+	select {
+	case <-call.Done():
+	default:
+		status.State = TriggerRunning
+		if run := s.engine.CurrentRun(id); run != nil && run.ID == status.RunID {
+			status.Run = run
+		}
+		return status
+	}
+
+	run, err := call.Result()
+	status.Run = run
+	switch {
+	case run != nil && run.Status == engine.StatusSucceeded:
+		status.State = TriggerSucceeded
+	case run != nil:
+		status.State = TriggerFailed
+		status.Error = run.Error
+	case errors.Is(err, engine.ErrPaused) || errors.Is(err, engine.ErrHandoff) || errors.Is(err, engine.ErrRunInProgress) || errors.Is(err, engine.ErrPreflightFailed) || errors.Is(err, engine.ErrResidency):
+		status.State = TriggerRejected
+		status.Error = err.Error()
+	default:
+		status.State = TriggerFailed
+		if err != nil {
+			status.Error = err.Error()
+		}
+	}
+	return status
+}
+
+// sendCallback posts the final state of a trigger, retrying failed
+// deliveries
+func (s *Server) sendCallback(callback string, status TriggerStatus) {
+	body, err := json.Marshal(status)
+	if err != nil {
+		log.Printf("[API] Failed to encode trigger callback: %v", err)
+		return
+	}
+	client := egress.Client(30 * time.Second)
+	for attempt := 1; attempt <= callbackAttempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(time.Duration(attempt-1) * 2 * time.Second)
+		}
+		err = postCallback(s.ctx, client, callback, status, body)
+		if err == nil {
+			return
+		}
+	}
+	log.Printf("[API] Failed to deliver trigger callback of pipeline %s to %s: %v", status.PipelineID, callback, err)
+}
+
+// postCallback delivers one callback
+func postCallback(ctx context.Context, client *http.Client, callback string, status TriggerStatus, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, callback, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", status.IdempotencyKey)
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("callback returned %s", resp.Status)
+	}
+	return nil
+}
+
+// triggerWait parses the long-poll duration of a trigger request
+func triggerWait(value string) (time.Duration, error) {
+	if value == "" {
+		return maxTriggerWait, nil
+	}
+	wait, err := time.ParseDuration(value)
+	if err != nil || wait < 0 {
+		return 0, fmt.Errorf("wait must be a duration such as 30s")
+	}
+	if wait > maxTriggerWait {
+		wait = maxTriggerWait
+	}
+	return wait, nil
+}
+
+// newTriggerKey returns a random key for triggers sent without one
+func newTriggerKey() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
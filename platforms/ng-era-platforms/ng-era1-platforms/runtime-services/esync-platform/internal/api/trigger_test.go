@@ -0,0 +1,146 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: admin-api-trigger-tests
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Trigger Endpoint Tests
+ */
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/engine"
+	"github.com/machine-native-ops/esync-platform/internal/monitoring"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+	"github.com/machine-native-ops/esync-platform/internal/secrets"
+	"github.com/machine-native-ops/esync-platform/internal/state"
+)
+
+// heldSource holds the first ListChanges call until released, so a test
+// can act while a run is in flight
+type heldSource struct {
+	once     sync.Once
+	started  chan struct{}
+	released chan struct{}
+}
+
+func (c *heldSource) ListChanges(ctx context.Context, _ *connectors.Checkpoint) ([]connectors.Record, error) {
+	c.once.Do(func() { close(c.started) })
+	select {
+	case <-c.released:
+		return nil, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (c *heldSource) ApplyChanges(context.Context, []connectors.Record) error { return nil }
+
+func (c *heldSource) Validate(context.Context, connectors.Record) connectors.ValidationResult {
+	return connectors.ValidationResult{IsValid: true}
+}
+
+func (c *heldSource) ResolveConflict(_ context.Context, _ connectors.Record, newSource connectors.Record) (connectors.Record, error) {
+	return newSource, nil
+}
+
+func (c *heldSource) GetLatestCheckpoint(context.Context) (*connectors.Checkpoint, error) {
+	return &connectors.Checkpoint{}, nil
+}
+
+// heldSources holds the sources of the api-held connector type by the
+// name in their config; other names get a target that holds nothing
+var heldSources sync.Map
+
+func init() {
+	connectors.Register("api-held", connectors.WithSchema(func(config map[string]interface{}) (connectors.Connector, error) {
+		if source, ok := heldSources.Load(config["name"]); ok {
+			return source.(*heldSource), nil
+		}
+		released := make(chan struct{})
+		close(released)
+		return &heldSource{started: make(chan struct{}), released: released}, nil
+	}, []byte(`{"type":"object"}`)))
+}
+
+// TestTriggerRunSurvivesDisconnect cancels a keyed run request mid-run and
+// retries it with the same key: the retry must get the first run, finished,
+// rather than one cancelled with the first request
+func TestTriggerRunSurvivesDisconnect(t *testing.T) {
+	held := &heldSource{started: make(chan struct{}), released: make(chan struct{})}
+	heldSources.Store(t.Name(), held)
+	t.Cleanup(func() { heldSources.Delete(t.Name()) })
+
+	dir := t.TempDir()
+	store, err := state.NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reg := registry.NewService(t.TempDir())
+	if err := reg.Add(&registry.Pipeline{
+		ID:     "orders",
+		Source: registry.ConnectorSpec{Type: "api-held", Config: map[string]interface{}{"name": t.Name()}},
+		Target: registry.ConnectorSpec{Type: "api-held", Config: map[string]interface{}{"name": t.Name() + "/target"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	eng := engine.New(reg, store, monitoring.NewMonitor(), secrets.NewResolver(dir))
+	t.Cleanup(eng.Stop)
+	handler := NewServer(context.Background(), reg, eng, nil).Handler()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := httptest.NewRequest(http.MethodPost, "/pipelines/orders/runs", nil).WithContext(ctx)
+	first.Header.Set("Idempotency-Key", "nightly")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handler.ServeHTTP(httptest.NewRecorder(), first)
+	}()
+	select {
+	case <-held.started:
+	case <-time.After(10 * time.Second):
+		t.Fatal("run did not start")
+	}
+	cancel()
+	<-done
+
+	retry := httptest.NewRequest(http.MethodPost, "/pipelines/orders/runs", nil)
+	retry.Header.Set("Idempotency-Key", "nightly")
+	rec := httptest.NewRecorder()
+	time.AfterFunc(50*time.Millisecond, func() { close(held.released) })
+	handler.ServeHTTP(rec, retry)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("retry returned %d: %s", rec.Code, rec.Body)
+	}
+	var run engine.Run
+	if err := json.Unmarshal(rec.Body.Bytes(), &run); err != nil {
+		t.Fatal(err)
+	}
+	if want := engine.RunID("orders", engine.TriggerManual, "nightly"); run.ID != want {
+		t.Errorf("retry returned run %s, want %s", run.ID, want)
+	}
+	if run.Status != engine.StatusSucceeded {
+		t.Errorf("retry returned a %s run: %s", run.Status, run.Error)
+	}
+	if runs := eng.Runs("orders"); len(runs) != 1 {
+		t.Errorf("pipeline ran %d times, want once", len(runs))
+	}
+}
@@ -0,0 +1,139 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: pipeline-events
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Pipeline Watch API
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/engine"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// EventStatus is the type of the events describing the current state of
+// each watched pipeline when a watch starts
+const EventStatus = "status"
+
+// watchKeepalive is how often an idle event stream sends a comment so
+// proxies keep the connection open
+const watchKeepalive = 15 * time.Second
+
+// handleWatch streams the status transitions and run events of the
+// pipelines matching ?selector= as server-sent events. The stream opens
+// with a status event per pipeline; clients that reconnect get them again
+// instead of the events they missed.
+func (s *Server) handleWatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	sel, err := registry.ParseSelector(r.URL.Query().Get("selector"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+
+	events, unsubscribe := s.engine.Subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	for _, p := range s.registry.Select(sel) {
+		paused, err := s.engine.Paused(p.ID)
+		if err != nil {
+			log.Printf("[API] Failed to read pause state of pipeline %s: %v", p.ID, err)
+		}
+		current := s.engine.CurrentRun(p.ID)
+		run := current
+		if run == nil {
+			run = s.engine.LastRun(p.ID)
+		}
+		status := engine.Event{
+			Type:       EventStatus,
+			PipelineID: p.ID,
+			Time:       time.Now().UTC(),
+			Paused:     paused,
+			Running:    current != nil,
+			Run:        run,
+		}
+		if err := writeEvent(w, status); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	keepalive := time.NewTicker(watchKeepalive)
+	defer keepalive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-s.ctx.Done():
+			return
+		case <-keepalive.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if !s.watched(sel, event.PipelineID) {
+				continue
+			}
+			if err := writeEvent(w, event); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// watched reports whether events of a pipeline match the watch selector;
+// pipelines no longer registered only match the empty selector
+func (s *Server) watched(sel registry.Selector, pipelineID string) bool {
+	if len(sel) == 0 {
+		return true
+	}
+	p, err := s.registry.GetByID(pipelineID)
+	return err == nil && sel.Matches(p.Labels)
+}
+
+// writeEvent writes an event in the server-sent events format
+func writeEvent(w http.ResponseWriter, event engine.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if event.Seq > 0 {
+		if _, err := fmt.Fprintf(w, "id: %d\n", event.Seq); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+	return err
+}
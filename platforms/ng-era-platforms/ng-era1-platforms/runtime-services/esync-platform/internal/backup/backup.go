@@ -0,0 +1,155 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: state-backup
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Scheduled State Backups
+ */
+
+// Package backup writes state backup archives on a schedule, to a local
+// directory or an object store
+package backup
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/egress"
+	"github.com/machine-native-ops/esync-platform/internal/engine"
+)
+
+// filePrefix and fileSuffix name every archive the writer stores
+const (
+	filePrefix = "esync-backup-"
+	fileSuffix = ".tar.gz"
+)
+
+// Writer stores backup archives under a destination: a local directory, or
+// an http(s) URL prefix of an object store accepting PUT uploads. Archives
+// are named esync-backup-<time>.tar.gz.
+type Writer struct {
+	dest   string
+	remote bool
+	client *http.Client
+}
+
+// NewWriter creates a backup writer for dest
+func NewWriter(dest string) *Writer {
+	remote := strings.HasPrefix(dest, "http://") || strings.HasPrefix(dest, "https://")
+	return &Writer{
+		dest:   strings.TrimRight(dest, "/"),
+		remote: remote,
+		client: egress.Client(5 * time.Minute),
+	}
+}
+
+// Schedule backs up the engine state every interval until ctx is cancelled
+func (w *Writer) Schedule(ctx context.Context, eng *engine.Engine, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		name, err := w.Write(ctx, eng)
+		if err != nil {
+			log.Printf("Failed to back up state: %v", err)
+			continue
+		}
+		log.Printf("Backed up state to %s", name)
+	}
+}
+
+// Write stores one backup archive of the engine state, returning its name
+func (w *Writer) Write(ctx context.Context, eng *engine.Engine) (string, error) {
+	var buf bytes.Buffer
+	manifest, err := eng.Backup(&buf)
+	if err != nil {
+		return "", err
+	}
+
+	name := filePrefix + manifest.CreatedAt.Format("20060102T150405Z") + fileSuffix
+	if !w.remote {
+		if err := os.MkdirAll(w.dest, 0o750); err != nil {
+			return "", fmt.Errorf("failed to create backup directory: %w", err)
+		}
+		file := filepath.Join(w.dest, name)
+		if err := os.WriteFile(file, buf.Bytes(), 0o600); err != nil {
+			return "", fmt.Errorf("failed to write backup: %w", err)
+		}
+		return file, nil
+	}
+
+	u := w.dest + "/" + url.PathEscape(name)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u, &buf)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/gzip")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload backup: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("failed to upload backup: status %d", resp.StatusCode)
+	}
+	return u, nil
+}
+
+// Prune removes archives written more than maxAge ago from a local
+// directory, returning the number of files removed and bytes reclaimed.
+// Object stores expire archives with their own lifecycle rules.
+func (w *Writer) Prune(now time.Time, maxAge time.Duration) (int, int64, error) {
+	if w.remote || maxAge <= 0 {
+		return 0, 0, nil
+	}
+
+	entries, err := os.ReadDir(w.dest)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to prune backups: %w", err)
+	}
+
+	items, reclaimed := 0, int64(0)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, filePrefix) || !strings.HasSuffix(name, fileSuffix) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || now.Sub(info.ModTime()) <= maxAge {
+			continue
+		}
+		if err := os.Remove(filepath.Join(w.dest, name)); err != nil {
+			return items, reclaimed, fmt.Errorf("failed to prune backups: %w", err)
+		}
+		items++
+		reclaimed += info.Size()
+	}
+	return items, reclaimed, nil
+}
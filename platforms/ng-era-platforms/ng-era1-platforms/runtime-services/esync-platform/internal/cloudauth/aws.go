@@ -0,0 +1,419 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: cloud-auth
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * AWS SigV4 Signing and Workload Credentials
+ */
+
+package cloudauth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/egress"
+)
+
+// AWS endpoints of the container and instance metadata services
+const (
+	awsContainerHost = "http://169.254.170.2"
+	awsInstanceHost  = "http://169.254.169.254"
+)
+
+// AWSCredentials are temporary or long-lived AWS credentials
+//
+// Frozen: public as connectors.AWSCredentials
+type AWSCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	// Expiry is zero for credentials that do not expire
+	Expiry time.Time
+}
+
+// awsAuth signs requests with SigV4 using the workload credentials
+type awsAuth struct {
+	spec   Spec
+	region string
+	sts    *http.Client
+
+	mu    sync.Mutex
+	creds *AWSCredentials
+}
+
+// newAWS creates the SigV4 authenticator of a spec
+func newAWS(spec *Spec) *awsAuth {
+	region := spec.Region
+	if region == "" {
+		region = os.Getenv("AWS_REGION")
+	}
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	return &awsAuth{spec: *spec, region: region, sts: egress.Client(30 * time.Second)}
+}
+
+// Authorize implements Authenticator
+func (a *awsAuth) Authorize(req *http.Request) error {
+	if a.region == "" {
+		return fmt.Errorf("aws cloud_auth requires region or AWS_REGION")
+	}
+	creds, err := a.credentials(req.Context())
+	if err != nil {
+		return err
+	}
+	body, err := readBody(req)
+	if err != nil {
+		return err
+	}
+	SignV4(req, body, creds, a.region, a.spec.Service, time.Now())
+	return nil
+}
+
+// credentials returns the cached credentials, renewing them when they are
+// about to expire
+func (a *awsAuth) credentials(ctx context.Context) (*AWSCredentials, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.creds != nil && (a.creds.Expiry.IsZero() || time.Until(a.creds.Expiry) > renewBefore) {
+		return a.creds, nil
+	}
+	creds, err := a.resolve(ctx)
+	if err != nil {
+		if a.creds != nil && time.Now().Before(a.creds.Expiry) {
+			log.Printf("[CloudAuth] Failed to renew aws credentials, using the current ones: %v", err)
+			return a.creds, nil
+		}
+		return nil, fmt.Errorf("failed to get aws credentials: %w", err)
+	}
+	a.creds = creds
+	return creds, nil
+}
+
+// resolve finds the workload credentials in the environment, a web
+// identity token, the container credentials endpoint or the instance
+// metadata, in this order, and assumes the spec's role with them
+func (a *awsAuth) resolve(ctx context.Context) (*AWSCredentials, error) {
+	var creds *AWSCredentials
+	var err error
+	tokenFile, role := os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE"), os.Getenv("AWS_ROLE_ARN")
+	switch {
+	case os.Getenv("AWS_ACCESS_KEY_ID") != "":
+		creds = &AWSCredentials{
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		}
+	case tokenFile != "" && role != "":
+		creds, err = a.webIdentity(ctx, tokenFile, role)
+	case os.Getenv("AWS_CONTAINER_CREDENTIALS_FULL_URI") != "" || os.Getenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI") != "":
+		creds, err = containerCredentials(ctx)
+	default:
+		creds, err = instanceCredentials(ctx)
+	}
+	if err != nil || a.spec.RoleARN == "" || a.spec.RoleARN == role {
+		return creds, err
+	}
+	return a.assumeRole(ctx, creds)
+}
+
+// stsCredentials is the credentials element of STS responses
+type stsCredentials struct {
+	AccessKeyID     string    `xml:"AccessKeyId"`
+	SecretAccessKey string    `xml:"SecretAccessKey"`
+	SessionToken    string    `xml:"SessionToken"`
+	Expiration      time.Time `xml:"Expiration"`
+}
+
+// webIdentity exchanges the projected web identity token of the workload,
+// such as an EKS service account token, for role credentials
+func (a *awsAuth) webIdentity(ctx context.Context, tokenFile, role string) (*AWSCredentials, error) {
+	token, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read web identity token: %w", err)
+	}
+	q := url.Values{
+		"Action":           {"AssumeRoleWithWebIdentity"},
+		"Version":          {"2011-06-15"},
+		"RoleArn":          {role},
+		"RoleSessionName":  {sessionName()},
+		"WebIdentityToken": {strings.TrimSpace(string(token))},
+	}
+	var out struct {
+		Credentials stsCredentials `xml:"AssumeRoleWithWebIdentityResult>Credentials"`
+	}
+	if err := a.callSTS(ctx, q, nil, &out); err != nil {
+		return nil, err
+	}
+	return out.Credentials.credentials(), nil
+}
+
+// assumeRole assumes the spec's role with the workload credentials
+func (a *awsAuth) assumeRole(ctx context.Context, creds *AWSCredentials) (*AWSCredentials, error) {
+	q := url.Values{
+		"Action":          {"AssumeRole"},
+		"Version":         {"2011-06-15"},
+		"RoleArn":         {a.spec.RoleARN},
+		"RoleSessionName": {sessionName()},
+	}
+	var out struct {
+		Credentials stsCredentials `xml:"AssumeRoleResult>Credentials"`
+	}
+	if err := a.callSTS(ctx, q, creds, &out); err != nil {
+		return nil, fmt.Errorf("failed to assume role %s: %w", a.spec.RoleARN, err)
+	}
+	return out.Credentials.credentials(), nil
+}
+
+// callSTS sends a query to the regional STS endpoint, signed when creds
+// are given, and decodes the XML response
+func (a *awsAuth) callSTS(ctx context.Context, q url.Values, creds *AWSCredentials, out interface{}) error {
+	endpoint := fmt.Sprintf("https://sts.%s.amazonaws.com/?%s", a.region, q.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	if creds != nil {
+		SignV4(req, nil, creds, a.region, "sts", time.Now())
+	}
+	resp, err := a.sts.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("sts returned %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if err := xml.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode sts response: %w", err)
+	}
+	return nil
+}
+
+// credentials converts STS credentials
+func (c stsCredentials) credentials() *AWSCredentials {
+	return &AWSCredentials{
+		AccessKeyID:     c.AccessKeyID,
+		SecretAccessKey: c.SecretAccessKey,
+		SessionToken:    c.SessionToken,
+		Expiry:          c.Expiration,
+	}
+}
+
+// metadataCredentials is the JSON credentials document of the container
+// and instance metadata services
+type metadataCredentials struct {
+	AccessKeyID     string    `json:"AccessKeyId"`
+	SecretAccessKey string    `json:"SecretAccessKey"`
+	Token           string    `json:"Token"`
+	Expiration      time.Time `json:"Expiration"`
+}
+
+// credentials converts metadata credentials
+func (c *metadataCredentials) credentials() *AWSCredentials {
+	return &AWSCredentials{
+		AccessKeyID:     c.AccessKeyID,
+		SecretAccessKey: c.SecretAccessKey,
+		SessionToken:    c.Token,
+		Expiry:          c.Expiration,
+	}
+}
+
+// containerCredentials reads the task role credentials of ECS tasks and
+// EKS pod identities
+func containerCredentials(ctx context.Context) (*AWSCredentials, error) {
+	endpoint := os.Getenv("AWS_CONTAINER_CREDENTIALS_FULL_URI")
+	if endpoint == "" {
+		endpoint = awsContainerHost + os.Getenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI")
+	}
+	headers := map[string]string{}
+	token := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN")
+	if file := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE"); file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read container authorization token: %w", err)
+		}
+		token = strings.TrimSpace(string(data))
+	}
+	if token != "" {
+		headers["Authorization"] = token
+	}
+
+	var out metadataCredentials
+	if err := fetchJSON(ctx, metadataClient, http.MethodGet, endpoint, headers, &out); err != nil {
+		return nil, fmt.Errorf("failed to read container credentials: %w", err)
+	}
+	return out.credentials(), nil
+}
+
+// instanceCredentials reads the instance profile credentials of EC2 with
+// an IMDSv2 session token
+func instanceCredentials(ctx context.Context) (*AWSCredentials, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, awsInstanceHost+"/latest/api/token", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-aws-ec2-metadata-token-ttl-seconds", "21600")
+	resp, err := metadataClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("no aws credentials found and instance metadata is unreachable: %w", err)
+	}
+	token, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	resp.Body.Close()
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get instance metadata token: status %d", resp.StatusCode)
+	}
+	headers := map[string]string{"X-aws-ec2-metadata-token": string(token)}
+
+	path := awsInstanceHost + "/latest/meta-data/iam/security-credentials/"
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-aws-ec2-metadata-token", string(token))
+	resp, err = metadataClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	resp.Body.Close()
+	role := strings.TrimSpace(strings.SplitN(string(roles), "\n", 2)[0])
+	if err != nil || resp.StatusCode != http.StatusOK || role == "" {
+		return nil, fmt.Errorf("instance has no IAM role: status %d", resp.StatusCode)
+	}
+
+	var out metadataCredentials
+	if err := fetchJSON(ctx, metadataClient, http.MethodGet, path+role, headers, &out); err != nil {
+		return nil, fmt.Errorf("failed to read instance credentials: %w", err)
+	}
+	return out.credentials(), nil
+}
+
+// sessionName names the sessions of assumed roles after the host
+func sessionName() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "esync"
+	}
+	if len(host) > 64 {
+		host = host[:64]
+	}
+	return host
+}
+
+// SignV4 signs req with AWS Signature Version 4. body is the request
+// payload, nil for requests without one.
+func SignV4(req *http.Request, body []byte, creds *AWSCredentials, region, service string, now time.Time) {
+	now = now.UTC()
+	date := now.Format("20060102")
+	stamp := now.Format("20060102T150405Z")
+	payload := sha256.Sum256(body)
+	payloadHash := hex.EncodeToString(payload[:])
+
+	req.Header.Set("X-Amz-Date", stamp)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "x-amz-") || lower == "content-type" {
+			headers[lower] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonical := strings.Join([]string{
+		req.Method,
+		path,
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	digest := sha256.Sum256([]byte(canonical))
+	toSign := "AWS4-HMAC-SHA256\n" + stamp + "\n" + scope + "\n" + hex.EncodeToString(digest[:])
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, toSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		creds.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// canonicalQuery encodes query parameters sorted by key and value with
+// RFC 3986 escaping
+func canonicalQuery(q url.Values) string {
+	pairs := make([]string, 0, len(q))
+	for key, values := range q {
+		for _, value := range values {
+			pairs = append(pairs, awsEscape(key)+"="+awsEscape(value))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// awsEscape percent-encodes everything but unreserved characters
+func awsEscape(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(url.QueryEscape(s), "+", "%20"), "%7E", "~")
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of data under key
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
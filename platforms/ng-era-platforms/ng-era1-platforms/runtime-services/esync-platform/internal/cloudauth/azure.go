@@ -0,0 +1,142 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: cloud-auth
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Azure Managed and Workload Identity Tokens
+ */
+
+package cloudauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/egress"
+)
+
+// azureIMDS is the managed identity endpoint of Azure VMs
+const azureIMDS = "http://169.254.169.254/metadata/identity/oauth2/token"
+
+// azureAuthority is the Microsoft Entra ID host workload identity tokens
+// are exchanged at; AZURE_AUTHORITY_HOST overrides it
+const azureAuthority = "https://login.microsoftonline.com/"
+
+// azureToken returns the fetch func of the tokens of a spec's resource.
+// AKS workload identity is used when its federated token is mounted, then
+// the App Service identity endpoint, then the VM managed identity.
+func azureToken(spec *Spec) func(ctx context.Context) (string, time.Time, error) {
+	resource := spec.Resource
+	return func(ctx context.Context) (string, time.Time, error) {
+		clientID := spec.ClientID
+		if clientID == "" {
+			clientID = os.Getenv("AZURE_CLIENT_ID")
+		}
+		if file := os.Getenv("AZURE_FEDERATED_TOKEN_FILE"); file != "" && clientID != "" {
+			return azureWorkloadIdentity(ctx, file, clientID, resource)
+		}
+
+		var endpoint string
+		headers := map[string]string{}
+		q := url.Values{"resource": {resource}}
+		if spec.ClientID != "" {
+			q.Set("client_id", spec.ClientID)
+		}
+		if identity := os.Getenv("IDENTITY_ENDPOINT"); identity != "" {
+			q.Set("api-version", "2019-08-01")
+			endpoint = identity + "?" + q.Encode()
+			headers["X-IDENTITY-HEADER"] = os.Getenv("IDENTITY_HEADER")
+		} else {
+			q.Set("api-version", "2018-02-01")
+			endpoint = azureIMDS + "?" + q.Encode()
+			headers["Metadata"] = "true"
+		}
+
+		var out azureTokenResponse
+		if err := fetchJSON(ctx, metadataClient, http.MethodGet, endpoint, headers, &out); err != nil {
+			return "", time.Time{}, err
+		}
+		return out.AccessToken, out.expiry(), nil
+	}
+}
+
+// azureTokenResponse is the token response of the identity endpoints,
+// which encode numbers as strings
+type azureTokenResponse struct {
+	AccessToken string      `json:"access_token"`
+	ExpiresIn   json.Number `json:"expires_in"`
+	ExpiresOn   json.Number `json:"expires_on"`
+}
+
+// expiry returns when the token expires
+func (r *azureTokenResponse) expiry() time.Time {
+	if on, err := r.ExpiresOn.Int64(); err == nil && on > 0 {
+		return time.Unix(on, 0)
+	}
+	in, _ := r.ExpiresIn.Int64()
+	return time.Now().Add(time.Duration(in) * time.Second)
+}
+
+// azureWorkloadIdentity exchanges the federated service account token of
+// an AKS pod for a token of resource
+func azureWorkloadIdentity(ctx context.Context, file, clientID, resource string) (string, time.Time, error) {
+	assertion, err := os.ReadFile(file)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to read federated token: %w", err)
+	}
+	tenant := os.Getenv("AZURE_TENANT_ID")
+	if tenant == "" {
+		return "", time.Time{}, fmt.Errorf("workload identity requires AZURE_TENANT_ID")
+	}
+	authority := os.Getenv("AZURE_AUTHORITY_HOST")
+	if authority == "" {
+		authority = azureAuthority
+	}
+
+	form := url.Values{
+		"grant_type":            {"client_credentials"},
+		"client_id":             {clientID},
+		"scope":                 {strings.TrimSuffix(resource, "/") + "/.default"},
+		"client_assertion_type": {"urn:ietf:params:oauth:client-assertion-type:jwt-bearer"},
+		"client_assertion":      {strings.TrimSpace(string(assertion))},
+	}
+	endpoint := strings.TrimSuffix(authority, "/") + "/" + url.PathEscape(tenant) + "/oauth2/v2.0/token"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := egress.Client(30 * time.Second).Do(req)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", time.Time{}, fmt.Errorf("token endpoint returned %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	var out azureTokenResponse
+	if err := json.Unmarshal(data, &out); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	return out.AccessToken, out.expiry(), nil
+}
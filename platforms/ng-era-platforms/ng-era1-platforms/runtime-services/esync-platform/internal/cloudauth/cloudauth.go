@@ -0,0 +1,272 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: cloud-auth
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Cloud Provider Authentication
+ */
+
+// Package cloudauth authenticates connector requests to cloud APIs with
+// the credentials of the workload instead of static keys: AWS requests are
+// signed with SigV4 using web identity, container or instance metadata
+// credentials, GCP and Azure requests carry tokens of the metadata server,
+// workload identity or managed identity. Credentials are cached and
+// renewed shortly before they expire.
+package cloudauth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Providers
+const (
+	ProviderAWS   = "aws"
+	ProviderGCP   = "gcp"
+	ProviderAzure = "azure"
+)
+
+// renewBefore is how long before expiry credentials are renewed
+const renewBefore = 5 * time.Minute
+
+// metadataTimeout bounds one request to a metadata endpoint
+const metadataTimeout = 5 * time.Second
+
+// metadataClient reaches link-local metadata endpoints. It never goes
+// through the egress proxy, which could not reach them.
+var metadataClient = &http.Client{Timeout: metadataTimeout}
+
+// Spec selects a provider and what to request credentials for
+//
+// Frozen: public as connectors.CloudAuthSpec
+type Spec struct {
+	Provider string
+	// Region and Service scope AWS signatures, such as eu-west-1 and
+	// execute-api; the region defaults to AWS_REGION
+	Region  string
+	Service string
+	// RoleARN is an AWS role assumed with the workload credentials
+	RoleARN string
+	// Scopes are the GCP OAuth scopes of access tokens; Audience requests
+	// a GCP identity token for that audience instead
+	Scopes   []string
+	Audience string
+	// Account is the GCP service account, default the one of the workload
+	Account string
+	// Resource is the Azure resource tokens are issued for, such as
+	// https://storage.azure.com
+	Resource string
+	// ClientID selects a user-assigned Azure managed identity
+	ClientID string
+}
+
+// FromConfig reads a spec from the "cloud_auth" block of a connector
+// config
+func FromConfig(config map[string]interface{}) (*Spec, error) {
+	str := func(key string) string {
+		s, _ := config[key].(string)
+		return s
+	}
+
+	spec := &Spec{
+		Provider: str("provider"),
+		Region:   str("region"),
+		Service:  str("service"),
+		RoleARN:  str("role_arn"),
+		Audience: str("audience"),
+		Account:  str("account"),
+		Resource: str("resource"),
+		ClientID: str("client_id"),
+	}
+	switch scopes := config["scopes"].(type) {
+	case string:
+		spec.Scopes = strings.Fields(strings.ReplaceAll(scopes, ",", " "))
+	case []interface{}:
+		for _, scope := range scopes {
+			spec.Scopes = append(spec.Scopes, fmt.Sprint(scope))
+		}
+	}
+	return spec, spec.Validate()
+}
+
+// Validate checks the spec names a known provider and what it needs
+func (s *Spec) Validate() error {
+	switch s.Provider {
+	case ProviderAWS:
+		if s.Service == "" {
+			return fmt.Errorf("aws cloud_auth requires service")
+		}
+	case ProviderGCP:
+	case ProviderAzure:
+		if s.Resource == "" {
+			return fmt.Errorf("azure cloud_auth requires resource")
+		}
+	default:
+		return fmt.Errorf("unknown cloud_auth provider %q, expected %s, %s or %s", s.Provider, ProviderAWS, ProviderGCP, ProviderAzure)
+	}
+	return nil
+}
+
+// Authenticator adds workload credentials to requests
+//
+// Frozen: public as connectors.CloudAuthenticator
+type Authenticator interface {
+	// Authorize signs req or sets its Authorization header. Requests with
+	// a body need GetBody so the body can be hashed.
+	Authorize(req *http.Request) error
+}
+
+// New creates the authenticator of a spec; credentials are first fetched
+// by the first request
+func New(spec *Spec) (Authenticator, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	switch spec.Provider {
+	case ProviderAWS:
+		return newAWS(spec), nil
+	case ProviderGCP:
+		return &bearer{tokens: &tokenCache{name: "gcp", fetch: gcpToken(spec)}}, nil
+	default:
+		return &bearer{tokens: &tokenCache{name: "azure", fetch: azureToken(spec)}}, nil
+	}
+}
+
+// Transport authorizes requests with Auth before sending them with Base
+// (default http.DefaultTransport)
+//
+// Frozen: public as connectors.CloudAuthTransport
+type Transport struct {
+	Auth Authenticator
+	Base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	out := req.Clone(req.Context())
+	if err := t.Auth.Authorize(out); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	return base.RoundTrip(out)
+}
+
+// bearer authorizes requests with a cached bearer token
+type bearer struct {
+	tokens *tokenCache
+}
+
+// Authorize implements Authenticator
+func (b *bearer) Authorize(req *http.Request) error {
+	token, err := b.tokens.get(req.Context())
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// tokenCache keeps a token until shortly before it expires. A failed
+// renewal keeps serving the current token while it is valid.
+type tokenCache struct {
+	name  string
+	fetch func(ctx context.Context) (string, time.Time, error)
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// get returns the cached token, renewing it when it is about to expire
+func (c *tokenCache) get(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && time.Until(c.expiry) > renewBefore {
+		return c.token, nil
+	}
+	token, expiry, err := c.fetch(ctx)
+	if err != nil {
+		if c.token != "" && time.Now().Before(c.expiry) {
+			log.Printf("[CloudAuth] Failed to renew %s token, using the current one: %v", c.name, err)
+			return c.token, nil
+		}
+		return "", fmt.Errorf("failed to get %s token: %w", c.name, err)
+	}
+	c.token, c.expiry = token, expiry
+	return token, nil
+}
+
+// readBody returns the body of a request without consuming it
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody == nil {
+		return nil, fmt.Errorf("request body cannot be replayed for signing")
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+// fetchJSON requests url with the given headers and decodes the JSON
+// response
+func fetchJSON(ctx context.Context, client *http.Client, method, url string, headers map[string]string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %d: %s", url, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", url, err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: cloud-auth
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * GCP Metadata Server Tokens
+ */
+
+package cloudauth
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// gcpMetadataHost is the metadata server of GCE, GKE workload identity,
+// Cloud Run and Cloud Functions; GCE_METADATA_HOST overrides it
+const gcpMetadataHost = "metadata.google.internal"
+
+// gcpIdentityLifetime is how long identity tokens are cached; they are
+// valid for an hour
+const gcpIdentityLifetime = 55 * time.Minute
+
+// gcpToken returns the fetch func of the access or identity tokens of a
+// spec's service account
+func gcpToken(spec *Spec) func(ctx context.Context) (string, time.Time, error) {
+	host := os.Getenv("GCE_METADATA_HOST")
+	if host == "" {
+		host = gcpMetadataHost
+	}
+	account := spec.Account
+	if account == "" {
+		account = "default"
+	}
+	base := "http://" + host + "/computeMetadata/v1/instance/service-accounts/" + url.PathEscape(account)
+	headers := map[string]string{"Metadata-Flavor": "Google"}
+
+	if spec.Audience != "" {
+		endpoint := base + "/identity?format=full&audience=" + url.QueryEscape(spec.Audience)
+		return func(ctx context.Context) (string, time.Time, error) {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+			if err != nil {
+				return "", time.Time{}, err
+			}
+			req.Header.Set("Metadata-Flavor", "Google")
+			resp, err := metadataClient.Do(req)
+			if err != nil {
+				return "", time.Time{}, err
+			}
+			defer resp.Body.Close()
+
+			token, err := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+			if err != nil {
+				return "", time.Time{}, err
+			}
+			if resp.StatusCode != http.StatusOK {
+				return "", time.Time{}, fmt.Errorf("metadata server returned %d: %s", resp.StatusCode, strings.TrimSpace(string(token)))
+			}
+			return strings.TrimSpace(string(token)), time.Now().Add(gcpIdentityLifetime), nil
+		}
+	}
+
+	endpoint := base + "/token"
+	if len(spec.Scopes) > 0 {
+		endpoint += "?scopes=" + url.QueryEscape(strings.Join(spec.Scopes, ","))
+	}
+	return func(ctx context.Context) (string, time.Time, error) {
+		var out struct {
+			AccessToken string `json:"access_token"`
+			ExpiresIn   int64  `json:"expires_in"`
+		}
+		if err := fetchJSON(ctx, metadataClient, http.MethodGet, endpoint, headers, &out); err != nil {
+			return "", time.Time{}, err
+		}
+		return out.AccessToken, time.Now().Add(time.Duration(out.ExpiresIn) * time.Second), nil
+	}
+}
@@ -0,0 +1,265 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: type-coercion
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Type Coercion Policy
+ */
+
+// Package coercion reconciles record values with the types a target expects
+package coercion
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// Canonical value types
+const (
+	TypeString    = "string"
+	TypeInteger   = "integer"
+	TypeNumber    = "number"
+	TypeBoolean   = "boolean"
+	TypeTimestamp = "timestamp"
+)
+
+// typeAliases maps common connector schema type names to canonical types
+var typeAliases = map[string]string{
+	"string": TypeString, "text": TypeString, "varchar": TypeString, "char": TypeString, "uuid": TypeString,
+	"integer": TypeInteger, "int": TypeInteger, "int2": TypeInteger, "int4": TypeInteger, "int8": TypeInteger,
+	"smallint": TypeInteger, "bigint": TypeInteger, "long": TypeInteger,
+	"number": TypeNumber, "float": TypeNumber, "float4": TypeNumber, "float8": TypeNumber, "double": TypeNumber,
+	"real": TypeNumber, "numeric": TypeNumber, "decimal": TypeNumber,
+	"boolean": TypeBoolean, "bool": TypeBoolean,
+	"timestamp": TypeTimestamp, "timestamptz": TypeTimestamp, "datetime": TypeTimestamp, "date": TypeTimestamp,
+}
+
+// NormalizeType maps a schema type name such as "varchar(255)" or "int8" to
+// a canonical type, or "" when unknown
+func NormalizeType(t string) string {
+	t = strings.ToLower(strings.TrimSpace(t))
+	if i := strings.IndexAny(t, "( "); i >= 0 {
+		t = t[:i]
+	}
+	return typeAliases[t]
+}
+
+// Policy applies a pipeline's coercion settings against a target schema
+type Policy struct {
+	mode  string
+	types map[string]string
+	modes map[string]string
+}
+
+// NewPolicy builds the policy of a pipeline. Schema may be nil when the
+// target cannot describe itself; only overridden fields are checked then.
+func NewPolicy(spec *registry.CoercionSpec, schema *connectors.Schema) (*Policy, error) {
+	p := &Policy{mode: registry.CoercionOff, types: make(map[string]string), modes: make(map[string]string)}
+	if spec == nil {
+		return p, nil
+	}
+	if spec.Mode != "" {
+		if err := validMode(spec.Mode); err != nil {
+			return nil, err
+		}
+		p.mode = spec.Mode
+	}
+
+	if schema != nil {
+		for _, f := range schema.Fields {
+			if t := NormalizeType(f.Type); t != "" {
+				p.types[f.Name] = t
+			}
+		}
+	}
+	for name, f := range spec.Fields {
+		if f.Type != "" {
+			t := NormalizeType(f.Type)
+			if t == "" {
+				return nil, fmt.Errorf("field %s: unknown type %q", name, f.Type)
+			}
+			p.types[name] = t
+		}
+		if f.Mode != "" {
+			if err := validMode(f.Mode); err != nil {
+				return nil, fmt.Errorf("field %s: %w", name, err)
+			}
+			p.modes[name] = f.Mode
+		}
+	}
+	return p, nil
+}
+
+// validMode checks a coercion mode
+func validMode(mode string) error {
+	switch mode {
+	case registry.CoercionStrict, registry.CoercionLenient, registry.CoercionOff:
+		return nil
+	default:
+		return fmt.Errorf("unknown coercion mode %q", mode)
+	}
+}
+
+// Apply checks every typed field of a record. Strict fields reject the
+// record with an error; lenient fields are converted where possible, with
+// one warning per converted or unconvertible value.
+func (p *Policy) Apply(r connectors.Record) (connectors.Record, []string, error) {
+	var warnings []string
+	var data map[string]interface{}
+
+	fields := make([]string, 0, len(r.Data))
+	for name := range r.Data {
+		fields = append(fields, name)
+	}
+	sort.Strings(fields)
+
+	for _, name := range fields {
+		value := r.Data[name]
+		typ, typed := p.types[name]
+		if !typed || value == nil || Matches(value, typ) {
+			continue
+		}
+
+		mode := p.mode
+		if m, exists := p.modes[name]; exists {
+			mode = m
+		}
+
+		switch mode {
+		case registry.CoercionStrict:
+			return r, warnings, fmt.Errorf("field %s: %T value %v is not %s", name, value, value, typ)
+		case registry.CoercionLenient:
+			converted, err := Coerce(value, typ)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("field %s: left unchanged: %v", name, err))
+				continue
+			}
+			if data == nil {
+				data = make(map[string]interface{}, len(r.Data))
+				for k, v := range r.Data {
+					data[k] = v
+				}
+			}
+			data[name] = converted
+			warnings = append(warnings, fmt.Sprintf("field %s: coerced %T to %s", name, value, typ))
+		}
+	}
+
+	if data != nil {
+		r.Data = data
+	}
+	return r, warnings, nil
+}
+
+// Matches reports whether a value already has the given type. Integral
+// float64 values count as integers since JSON decoding yields float64.
+func Matches(value interface{}, typ string) bool {
+	switch typ {
+	case TypeString:
+		_, ok := value.(string)
+		return ok
+	case TypeInteger:
+		switch v := value.(type) {
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+			return true
+		case float64:
+			return v == math.Trunc(v)
+		}
+		return false
+	case TypeNumber:
+		switch value.(type) {
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+			return true
+		}
+		return false
+	case TypeBoolean:
+		_, ok := value.(bool)
+		return ok
+	case TypeTimestamp:
+		switch v := value.(type) {
+		case time.Time:
+			return true
+		case string:
+			_, err := time.Parse(time.RFC3339Nano, v)
+			return err == nil
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+// timestampLayouts are accepted when coercing strings to timestamps
+var timestampLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02"}
+
+// Coerce converts a value to the given type
+func Coerce(value interface{}, typ string) (interface{}, error) {
+	s := fmt.Sprint(value)
+	if t, ok := value.(time.Time); ok {
+		s = t.UTC().Format(time.RFC3339Nano)
+	}
+
+	switch typ {
+	case TypeString:
+		return s, nil
+	case TypeInteger:
+		if f, ok := value.(float64); ok {
+			return nil, fmt.Errorf("%v has a fractional part", f)
+		}
+		if b, ok := value.(bool); ok {
+			if b {
+				return int64(1), nil
+			}
+			return int64(0), nil
+		}
+		n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert %q to integer", s)
+		}
+		return n, nil
+	case TypeNumber:
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert %q to number", s)
+		}
+		return f, nil
+	case TypeBoolean:
+		switch strings.ToLower(strings.TrimSpace(s)) {
+		case "true", "t", "yes", "y", "1":
+			return true, nil
+		case "false", "f", "no", "n", "0":
+			return false, nil
+		}
+		return nil, fmt.Errorf("cannot convert %q to boolean", s)
+	case TypeTimestamp:
+		switch v := value.(type) {
+		case int, int64, float64:
+			secs, _ := strconv.ParseFloat(s, 64)
+			return time.Unix(0, int64(secs*float64(time.Second))).UTC().Format(time.RFC3339Nano), nil
+		case string:
+			for _, layout := range timestampLayouts {
+				if t, err := time.Parse(layout, strings.TrimSpace(v)); err == nil {
+					return t.UTC().Format(time.RFC3339Nano), nil
+				}
+			}
+		}
+		return nil, fmt.Errorf("cannot convert %q to timestamp", s)
+	default:
+		return value, nil
+	}
+}
@@ -0,0 +1,72 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: conflict-resolution-engine
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Conflict Resolution Engine
+ */
+
+package conflict
+
+import (
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+)
+
+// Outcome describes how a conflict was resolved
+type Outcome string
+
+const (
+	// OutcomeApplied means the incoming record causally supersedes the existing one
+	OutcomeApplied Outcome = "applied"
+	// OutcomeStale means the incoming record is a replay of an older version
+	OutcomeStale Outcome = "stale"
+	// OutcomeConcurrent means both versions were written concurrently and LWW picked a winner
+	OutcomeConcurrent Outcome = "concurrent"
+	// OutcomeTimestamp means no causality metadata was available and plain LWW was used
+	OutcomeTimestamp Outcome = "timestamp"
+)
+
+// Resolver implements last-write-wins resolution with causality awareness
+type Resolver struct{}
+
+// NewResolver creates a new conflict resolver
+func NewResolver() *Resolver {
+	return &Resolver{}
+}
+
+// Resolve picks the winning record between the existing and incoming versions.
+// When both records carry vector clocks, causal order decides and timestamps
+// are only consulted for truly concurrent updates.
+func (r *Resolver) Resolve(existing, incoming connectors.Record) (connectors.Record, Outcome) {
+	if len(existing.Clock) == 0 || len(incoming.Clock) == 0 {
+		return lastWriteWins(existing, incoming), OutcomeTimestamp
+	}
+
+	switch incoming.Clock.Compare(existing.Clock) {
+	case connectors.OrderAfter:
+		return incoming, OutcomeApplied
+	case connectors.OrderBefore, connectors.OrderEqual:
+		return existing, OutcomeStale
+	}
+
+	winner := lastWriteWins(existing, incoming)
+	winner.Clock = existing.Clock.Merge(incoming.Clock)
+	return winner, OutcomeConcurrent
+}
+
+// lastWriteWins picks the record with the latest timestamp, keeping the
+// existing record on ties
+func lastWriteWins(existing, incoming connectors.Record) connectors.Record {
+	if incoming.Timestamp.After(existing.Timestamp) {
+		return incoming
+	}
+	return existing
+}
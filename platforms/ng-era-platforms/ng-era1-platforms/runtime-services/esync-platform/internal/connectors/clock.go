@@ -0,0 +1,122 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: connector-vector-clock
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Vector Clocks - Causality Tracking for Records
+ */
+
+package connectors
+
+// Ordering describes the causal relationship between two vector clocks
+//
+// Frozen: public as connectors.Ordering
+type Ordering int
+
+const (
+	// OrderEqual means both clocks observed exactly the same events
+	OrderEqual Ordering = iota
+	// OrderBefore means the receiver happened before the other clock
+	OrderBefore
+	// OrderAfter means the receiver happened after the other clock
+	OrderAfter
+	// OrderConcurrent means neither clock observed all events of the other
+	OrderConcurrent
+)
+
+// String returns the ordering name
+func (o Ordering) String() string {
+	switch o {
+	case OrderEqual:
+		return "equal"
+	case OrderBefore:
+		return "before"
+	case OrderAfter:
+		return "after"
+	default:
+		return "concurrent"
+	}
+}
+
+// VectorClock holds per-source logical counters
+//
+// Frozen: public as connectors.VectorClock
+type VectorClock map[string]uint64
+
+// Copy returns an independent copy of the clock
+func (vc VectorClock) Copy() VectorClock {
+	if vc == nil {
+		return nil
+	}
+	out := make(VectorClock, len(vc))
+	for k, v := range vc {
+		out[k] = v
+	}
+	return out
+}
+
+// Tick returns a copy of the clock with the counter for source incremented
+func (vc VectorClock) Tick(source string) VectorClock {
+	out := vc.Copy()
+	if out == nil {
+		out = make(VectorClock, 1)
+	}
+	out[source]++
+	return out
+}
+
+// Merge returns the element-wise maximum of both clocks
+func (vc VectorClock) Merge(other VectorClock) VectorClock {
+	out := vc.Copy()
+	if out == nil {
+		out = make(VectorClock, len(other))
+	}
+	for k, v := range other {
+		if v > out[k] {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// Compare reports how the receiver is ordered relative to other
+func (vc VectorClock) Compare(other VectorClock) Ordering {
+	less, greater := false, false
+	for k, v := range vc {
+		switch o := other[k]; {
+		case v < o:
+			less = true
+		case v > o:
+			greater = true
+		}
+	}
+	for k, o := range other {
+		if _, seen := vc[k]; !seen && o > 0 {
+			less = true
+		}
+	}
+
+	switch {
+	case less && greater:
+		return OrderConcurrent
+	case less:
+		return OrderBefore
+	case greater:
+		return OrderAfter
+	default:
+		return OrderEqual
+	}
+}
+
+// Stamp advances the record clock for the given source
+func (r *Record) Stamp(source string) {
+	r.Clock = r.Clock.Tick(source)
+}
@@ -0,0 +1,162 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: composite-keys
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Composite Record Keys
+ */
+
+package connectors
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// EncodeKey encodes the ordered values of a primary key as a record ID. A
+// single value encodes as itself, strings verbatim and other values as
+// JSON; several values encode as a JSON array, such as ["acme",42].
+// Numbers encode alike whatever their Go type.
+func EncodeKey(values []interface{}) (string, error) {
+	if len(values) == 0 {
+		return "", fmt.Errorf("empty key")
+	}
+	for i, v := range values {
+		if v == nil {
+			return "", fmt.Errorf("key value %d is null", i)
+		}
+	}
+
+	if len(values) == 1 {
+		return encodeKeyValue(values[0])
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode key: %w", err)
+	}
+	return string(data), nil
+}
+
+// encodeKeyValue encodes the value of a single-field key
+func encodeKeyValue(v interface{}) (string, error) {
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode key: %w", err)
+	}
+	// Values encoding as JSON strings, such as times, are used unquoted
+	var s string
+	if json.Unmarshal(data, &s) == nil {
+		return s, nil
+	}
+	return string(data), nil
+}
+
+// DecodeKey returns the n values an ID encodes; a single value is returned
+// as the ID itself
+func DecodeKey(id string, n int) ([]interface{}, error) {
+	if n <= 1 {
+		return []interface{}{id}, nil
+	}
+
+	var values []interface{}
+	if !strings.HasPrefix(id, "[") || json.Unmarshal([]byte(id), &values) != nil {
+		return nil, fmt.Errorf("ID %q is not a composite key", id)
+	}
+	if len(values) != n {
+		return nil, fmt.Errorf("ID %q has %d key values, expected %d", id, len(values), n)
+	}
+	return values, nil
+}
+
+// Key returns the key of the record within its connector
+func (r Record) Key() Key {
+	return Key{Table: r.Table, ID: r.ID}
+}
+
+// CompositeID encodes the values of the record's key fields, read from
+// its data
+func (r Record) CompositeID() (string, error) {
+	values := make([]interface{}, len(r.KeyFields))
+	for i, field := range r.KeyFields {
+		v, exists := r.Data[field]
+		if !exists {
+			return "", fmt.Errorf("key field %s is missing", field)
+		}
+		values[i] = v
+	}
+	id, err := EncodeKey(values)
+	if err != nil {
+		return "", fmt.Errorf("invalid key %s: %w", strings.Join(r.KeyFields, ", "), err)
+	}
+	return id, nil
+}
+
+// KeyData returns the key field values of the record by field name, from
+// its data or, for records such as deletes that carry none, decoded from
+// its ID. Targets with composite primary keys address records by it.
+func (r Record) KeyData() (map[string]interface{}, error) {
+	if len(r.KeyFields) == 0 {
+		return nil, fmt.Errorf("record %s has no key fields", r.ID)
+	}
+
+	out := make(map[string]interface{}, len(r.KeyFields))
+	var decoded []interface{}
+	for i, field := range r.KeyFields {
+		if v, exists := r.Data[field]; exists {
+			out[field] = v
+			continue
+		}
+		if decoded == nil {
+			var err error
+			if decoded, err = DecodeKey(r.ID, len(r.KeyFields)); err != nil {
+				return nil, err
+			}
+		}
+		out[field] = decoded[i]
+	}
+	return out, nil
+}
+
+// WithKeyData returns the records with the values of their key fields set
+// in their data, so targets can address composite-key records that carry
+// none, such as deletes. Records without key fields are unchanged and the
+// input is not modified.
+func WithKeyData(records []Record) ([]Record, error) {
+	var out []Record
+	for i, r := range records {
+		if len(r.KeyFields) == 0 {
+			continue
+		}
+		keys, err := r.KeyData()
+		if err != nil {
+			return nil, err
+		}
+		if out == nil {
+			out = append([]Record(nil), records...)
+		}
+		data := make(map[string]interface{}, len(r.Data)+len(keys))
+		for k, v := range r.Data {
+			data[k] = v
+		}
+		for k, v := range keys {
+			data[k] = v
+		}
+		out[i].Data = data
+	}
+	if out == nil {
+		return records, nil
+	}
+	return out, nil
+}
@@ -0,0 +1,82 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: connector-factory
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Connector Factory Registry
+ */
+
+package connectors
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Factory builds a connector from its pipeline configuration block
+//
+// Frozen: public as connectors.Factory
+type Factory func(config map[string]interface{}) (Connector, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = make(map[string]Type)
+)
+
+// Register makes a connector type available to pipelines. Every type must
+// document its config with a JSON Schema object.
+func Register(connectorType string, t Type) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	if _, exists := factories[connectorType]; exists {
+		panic(fmt.Sprintf("connector type %s registered twice", connectorType))
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal(t.ConfigSchema(), &schema); err != nil || schema == nil {
+		panic(fmt.Sprintf("connector type %s has no valid config schema: %v", connectorType, err))
+	}
+	factories[connectorType] = t
+}
+
+// New creates a connector of the given type
+func New(connectorType string, config map[string]interface{}) (Connector, error) {
+	factoriesMu.RLock()
+	t, exists := factories[connectorType]
+	factoriesMu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("unknown connector type %s", connectorType)
+	}
+
+	connector, err := t.New(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s connector: %w", connectorType, err)
+	}
+
+	return connector, nil
+}
+
+// Types returns all registered connector types
+func Types() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	types := make([]string, 0, len(factories))
+	for t := range factories {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	return types
+}
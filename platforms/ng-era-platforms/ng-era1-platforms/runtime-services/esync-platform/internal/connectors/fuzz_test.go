@@ -0,0 +1,66 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: record-fuzzing
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Record Decode Fuzz Targets
+ */
+
+package connectors
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+// FuzzDecode decodes arbitrary JSON as the records and checkpoints
+// connectors exchange, and checks decoded values survive a round trip and
+// their key and clock helpers
+func FuzzDecode(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var r Record
+		if json.Unmarshal(data, &r) == nil {
+			roundTrip(t, "record", &r, &Record{})
+			if id, err := r.CompositeID(); err == nil && len(r.KeyFields) > 1 {
+				if _, err := DecodeKey(id, len(r.KeyFields)); err != nil {
+					t.Fatalf("composite ID %q does not decode: %v", id, err)
+				}
+			}
+			_, _ = r.KeyData()
+			r.Clock.Compare(r.Clock.Merge(nil))
+		}
+
+		var c Checkpoint
+		if json.Unmarshal(data, &c) == nil {
+			roundTrip(t, "checkpoint", &c, &Checkpoint{})
+		}
+	})
+}
+
+// roundTrip encodes v, decodes the result into fresh and fails unless
+// encoding fresh gives the same bytes
+func roundTrip(t *testing.T, kind string, v, fresh interface{}) {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("failed to encode decoded %s: %v", kind, err)
+	}
+	if err := json.Unmarshal(encoded, fresh); err != nil {
+		t.Fatalf("failed to decode encoded %s: %v", kind, err)
+	}
+	reencoded, err := json.Marshal(fresh)
+	if err != nil {
+		t.Fatalf("failed to encode redecoded %s: %v", kind, err)
+	}
+	if !bytes.Equal(encoded, reencoded) {
+		t.Fatalf("%s round trip changed %s into %s", kind, encoded, reencoded)
+	}
+}
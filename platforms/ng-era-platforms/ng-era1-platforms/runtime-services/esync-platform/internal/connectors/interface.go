@@ -21,27 +21,78 @@ import (
 	"time"
 )
 
-// Record represents a data record
+// Record operations
+const (
+	OperationInsert = "insert"
+	OperationUpdate = "update"
+	OperationDelete = "delete"
+	// OperationPatch carries only the changed fields of an existing record
+	OperationPatch = "patch"
+	// OperationHeartbeat carries no data; CDC sources emit it while idle so
+	// the engine can tell an idle source from a stuck one. Its Timestamp is
+	// the source time of the heartbeat.
+	OperationHeartbeat = "heartbeat"
+)
+
+// Record represents a data record. A field present in Data with a nil value
+// is explicitly null; a field absent from Data was not provided, which for
+// partial updates means "leave unchanged" unless the pipeline nulls absent
+// fields. JSON encoding preserves the distinction.
+//
+// Frozen: public as connectors.Record
 type Record struct {
 	ID        string                 `json:"id"`
 	Operation string                 `json:"operation"`
 	Data      map[string]interface{} `json:"data"`
 	Timestamp time.Time              `json:"timestamp"`
+	Clock     VectorClock            `json:"clock,omitempty"`
+
+	// Table is the table or collection of the record; empty for
+	// single-table pipelines
+	Table string `json:"table,omitempty"`
+	// KeyFields names, in order, the Data fields forming a composite
+	// primary key. ID is then the encoding of their values; see EncodeKey.
+	KeyFields []string `json:"key_fields,omitempty"`
+
+	// OrderingKey keeps records sharing it in source order when changes are
+	// applied in parallel; empty means the record ID
+	OrderingKey string `json:"ordering_key,omitempty"`
+	// DependsOn lists IDs of records that must be applied before this one,
+	// e.g. the parent row of a child row
+	DependsOn []string `json:"depends_on,omitempty"`
+}
+
+// Has reports whether the record carries a field, including explicit nulls
+func (r Record) Has(field string) bool {
+	_, exists := r.Data[field]
+	return exists
+}
+
+// IsNull reports whether the record carries the field as an explicit null
+func (r Record) IsNull(field string) bool {
+	v, exists := r.Data[field]
+	return exists && v == nil
 }
 
 // Checkpoint marks sync progress
+//
+// Frozen: public as connectors.Checkpoint
 type Checkpoint struct {
 	Position string                 `json:"position"`
 	Metadata map[string]interface{} `json:"metadata"`
 }
 
 // ValidationResult holds validation results
+//
+// Frozen: public as connectors.ValidationResult
 type ValidationResult struct {
 	IsValid bool     `json:"is_valid"`
 	Errors  []string `json:"errors"`
 }
 
 // Connector is the base interface for all source and target connectors
+//
+// Frozen: public as connectors.Connector
 type Connector interface {
 	ListChanges(ctx context.Context, checkpoint *Checkpoint) ([]Record, error)
 	ApplyChanges(ctx context.Context, changes []Record) error
@@ -0,0 +1,112 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: key-digests
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Key Set Digests
+ */
+
+package connectors
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// Key identifies a record across the tables of a connector
+//
+// Frozen: public as connectors.Key
+type Key struct {
+	Table string `json:"table,omitempty"`
+	ID    string `json:"id"`
+}
+
+// String renders the key as its ID, prefixed with table/ when set
+func (k Key) String() string {
+	if k.Table == "" {
+		return k.ID
+	}
+	return k.Table + "/" + k.ID
+}
+
+// KeyDigest summarizes the live keys whose hash starts with Prefix.
+// Digest is the hex XOR of their hashes, the zero hash when Count is 0.
+//
+// Frozen: public as connectors.KeyDigest
+type KeyDigest struct {
+	Prefix string `json:"prefix"`
+	Count  int64  `json:"count"`
+	Digest string `json:"digest"`
+}
+
+// KeyDigester is implemented by connectors that can summarize their live
+// keys by hash prefix, so key sets can be compared by exchanging digests
+// of the ranges that differ instead of full key dumps. A key hash is the
+// lowercase hex SHA-256 of the table, a zero byte and the record ID; see
+// KeyHash.
+//
+// Frozen: public as connectors.KeyDigester
+type KeyDigester interface {
+	// KeyDigests returns the digest of each hash prefix, in order
+	KeyDigests(ctx context.Context, prefixes []string) ([]KeyDigest, error)
+	// HashKeys lists the live keys whose hash starts with prefix
+	HashKeys(ctx context.Context, prefix string) ([]Key, error)
+}
+
+// KeyHash returns the hash of a key that key digests are built from
+func KeyHash(k Key) string {
+	sum := sha256.Sum256([]byte(k.Table + "\x00" + k.ID))
+	return hex.EncodeToString(sum[:])
+}
+
+// DigestKeys computes the digests of the given prefixes over a key set.
+// Connectors keeping their keys in memory or able to list them cheaply
+// can implement KeyDigester with it; databases should aggregate hashes in
+// queries instead.
+func DigestKeys(keys []Key, prefixes []string) []KeyDigest {
+	hashes := make([][sha256.Size]byte, len(keys))
+	encoded := make([]string, len(keys))
+	for i, k := range keys {
+		hashes[i] = sha256.Sum256([]byte(k.Table + "\x00" + k.ID))
+		encoded[i] = hex.EncodeToString(hashes[i][:])
+	}
+
+	out := make([]KeyDigest, len(prefixes))
+	for i, prefix := range prefixes {
+		var digest [sha256.Size]byte
+		var count int64
+		for j, h := range hashes {
+			if !strings.HasPrefix(encoded[j], prefix) {
+				continue
+			}
+			count++
+			for b := range digest {
+				digest[b] ^= h[b]
+			}
+		}
+		out[i] = KeyDigest{Prefix: prefix, Count: count, Digest: hex.EncodeToString(digest[:])}
+	}
+	return out
+}
+
+// KeysWithPrefix returns the keys whose hash starts with prefix, for
+// implementing HashKeys over a key set
+func KeysWithPrefix(keys []Key, prefix string) []Key {
+	var out []Key
+	for _, k := range keys {
+		if strings.HasPrefix(KeyHash(k), prefix) {
+			out = append(out, k)
+		}
+	}
+	return out
+}
@@ -0,0 +1,178 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: ldap-ber
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * BER Encoding of LDAP Messages
+ */
+
+package ldap
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// Universal BER tags used by LDAP
+const (
+	tagBoolean     = 0x01
+	tagInteger     = 0x02
+	tagOctetString = 0x04
+	tagEnumerated  = 0x0a
+	tagSequence    = 0x30
+	tagSet         = 0x31
+)
+
+// maxMessage bounds the size of one LDAP message read from a server
+const maxMessage = 64 << 20
+
+// element is one decoded BER element
+type element struct {
+	tag  byte
+	data []byte
+}
+
+// tlv encodes a BER element with the given tag and contents
+func tlv(tag byte, content []byte) []byte {
+	out := []byte{tag}
+	n := len(content)
+	switch {
+	case n < 0x80:
+		out = append(out, byte(n))
+	default:
+		var length []byte
+		for ; n > 0; n >>= 8 {
+			length = append([]byte{byte(n)}, length...)
+		}
+		out = append(out, 0x80|byte(len(length)))
+		out = append(out, length...)
+	}
+	return append(out, content...)
+}
+
+// constructed encodes a constructed element holding parts
+func constructed(tag byte, parts ...[]byte) []byte {
+	var content []byte
+	for _, part := range parts {
+		content = append(content, part...)
+	}
+	return tlv(tag, content)
+}
+
+// integer encodes n in two's complement under tag
+func integer(tag byte, n int64) []byte {
+	var content []byte
+	for {
+		content = append([]byte{byte(n)}, content...)
+		if (n >= -128 && n < 128) || len(content) == 8 {
+			break
+		}
+		n >>= 8
+	}
+	return tlv(tag, content)
+}
+
+// octets encodes a string under tag
+func octets(tag byte, s string) []byte {
+	return tlv(tag, []byte(s))
+}
+
+// boolean encodes b under tag
+func boolean(tag byte, b bool) []byte {
+	if b {
+		return tlv(tag, []byte{0xff})
+	}
+	return tlv(tag, []byte{0})
+}
+
+// readElement splits the first element off b
+func readElement(b []byte) (element, []byte, error) {
+	if len(b) < 2 {
+		return element{}, nil, fmt.Errorf("truncated BER element")
+	}
+	tag := b[0]
+	n, header := int(b[1]), 2
+	if n&0x80 != 0 {
+		size := n & 0x7f
+		if size == 0 || size > 4 || len(b) < 2+size {
+			return element{}, nil, fmt.Errorf("unsupported BER length")
+		}
+		n = 0
+		for _, c := range b[2 : 2+size] {
+			n = n<<8 | int(c)
+		}
+		header += size
+	}
+	if n < 0 || len(b) < header+n {
+		return element{}, nil, fmt.Errorf("truncated BER element")
+	}
+	return element{tag: tag, data: b[header : header+n]}, b[header+n:], nil
+}
+
+// children decodes the elements inside a constructed element
+func (e element) children() ([]element, error) {
+	var out []element
+	for rest := e.data; len(rest) > 0; {
+		child, next, err := readElement(rest)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, child)
+		rest = next
+	}
+	return out, nil
+}
+
+// int decodes an integer or enumerated element
+func (e element) int() (int64, error) {
+	if len(e.data) == 0 || len(e.data) > 8 {
+		return 0, fmt.Errorf("invalid BER integer of %d bytes", len(e.data))
+	}
+	n := int64(int8(e.data[0]))
+	for _, c := range e.data[1:] {
+		n = n<<8 | int64(c)
+	}
+	return n, nil
+}
+
+// readMessage reads one complete BER element from a stream
+func readMessage(r *bufio.Reader) ([]byte, error) {
+	header := make([]byte, 2, 6)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	n := int(header[1])
+	if n&0x80 != 0 {
+		size := n & 0x7f
+		if size == 0 || size > 4 {
+			return nil, fmt.Errorf("unsupported BER length")
+		}
+		length := make([]byte, size)
+		if _, err := io.ReadFull(r, length); err != nil {
+			return nil, err
+		}
+		header = append(header, length...)
+		n = 0
+		for _, c := range length {
+			n = n<<8 | int(c)
+		}
+	}
+	if n > maxMessage {
+		return nil, fmt.Errorf("LDAP message of %d bytes exceeds the %d byte limit", n, maxMessage)
+	}
+	msg := make([]byte, len(header)+n)
+	copy(msg, header)
+	if _, err := io.ReadFull(r, msg[len(header):]); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
@@ -0,0 +1,398 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: ldap-client
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * LDAPv3 Client
+ */
+
+package ldap
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/egress"
+)
+
+// Application tags of the protocol operations used (RFC 4511 section 4)
+const (
+	opBindRequest      = 0x60
+	opBindResponse     = 0x61
+	opUnbindRequest    = 0x42
+	opSearchRequest    = 0x63
+	opSearchEntry      = 0x64
+	opSearchDone       = 0x65
+	opSearchReference  = 0x73
+	opExtendedRequest  = 0x77
+	opExtendedResponse = 0x78
+)
+
+// controlsTag marks the controls of an LDAP message
+const controlsTag = 0xa0
+
+// oidStartTLS is the extended operation upgrading a connection to TLS
+const oidStartTLS = "1.3.6.1.4.1.1466.20037"
+
+// oidPagedResults is the simple paged results control (RFC 2696)
+const oidPagedResults = "1.2.840.113556.1.4.319"
+
+// Search scopes
+const (
+	scopeBase = 0
+	scopeOne  = 1
+	scopeSub  = 2
+)
+
+// ResultError is a non-success result of an LDAP operation
+type ResultError struct {
+	Code    int64
+	Message string
+}
+
+func (e *ResultError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("LDAP result code %d", e.Code)
+	}
+	return fmt.Sprintf("LDAP result code %d: %s", e.Code, e.Message)
+}
+
+// entry is one search result
+type entry struct {
+	dn         string
+	attributes map[string][]string
+}
+
+// searchRequest describes one search
+type searchRequest struct {
+	baseDN     string
+	scope      int
+	filter     []byte
+	attributes []string
+	pageSize   int
+}
+
+// conn is a connection to a directory server. Operations run one at a
+// time, each bounded by the timeout.
+type conn struct {
+	netConn net.Conn
+	r       *bufio.Reader
+	nextID  int64
+	timeout time.Duration
+}
+
+// dial connects to an ldap:// or ldaps:// URL through the egress policy,
+// upgrading ldap:// connections with StartTLS when startTLS is set
+func dial(ctx context.Context, rawURL string, tlsConfig *tls.Config, startTLS bool, timeout time.Duration) (*conn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid LDAP url: %w", err)
+	}
+	host, port := u.Hostname(), u.Port()
+	switch {
+	case u.Scheme == "ldaps" && port == "":
+		port = "636"
+	case u.Scheme == "ldap" && port == "":
+		port = "389"
+	case u.Scheme != "ldap" && u.Scheme != "ldaps":
+		return nil, fmt.Errorf("LDAP url %s must use ldap or ldaps", rawURL)
+	}
+
+	dialCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	netConn, err := egress.Default().DialContext(dialCtx, "tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", u.Host, err)
+	}
+
+	c := &conn{netConn: netConn, r: bufio.NewReader(netConn), timeout: timeout}
+	if tlsConfig.ServerName == "" {
+		tlsConfig = tlsConfig.Clone()
+		tlsConfig.ServerName = host
+	}
+	switch {
+	case u.Scheme == "ldaps":
+		err = c.upgrade(ctx, tlsConfig)
+	case startTLS:
+		err = c.startTLS(ctx, tlsConfig)
+	}
+	if err != nil {
+		netConn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// upgrade runs the TLS handshake on the connection
+func (c *conn) upgrade(ctx context.Context, tlsConfig *tls.Config) error {
+	tlsConn := tls.Client(c.netConn, tlsConfig)
+	hsCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	if err := tlsConn.HandshakeContext(hsCtx); err != nil {
+		return fmt.Errorf("TLS handshake failed: %w", err)
+	}
+	c.netConn = tlsConn
+	c.r = bufio.NewReader(tlsConn)
+	return nil
+}
+
+// startTLS asks the server to switch to TLS and upgrades the connection
+func (c *conn) startTLS(ctx context.Context, tlsConfig *tls.Config) error {
+	id, err := c.send(ctx, constructed(opExtendedRequest, octets(0x80, oidStartTLS)), nil)
+	if err != nil {
+		return err
+	}
+	op, err := c.receive(ctx, id)
+	if err != nil {
+		return err
+	}
+	if op.tag != opExtendedResponse {
+		return fmt.Errorf("unexpected LDAP response 0x%x to StartTLS", op.tag)
+	}
+	if err := result(op); err != nil {
+		return fmt.Errorf("StartTLS refused: %w", err)
+	}
+	return c.upgrade(ctx, tlsConfig)
+}
+
+// bind authenticates with a simple bind; an empty DN binds anonymously
+func (c *conn) bind(ctx context.Context, dn, password string) error {
+	req := constructed(opBindRequest, integer(tagInteger, 3), octets(tagOctetString, dn), octets(0x80, password))
+	id, err := c.send(ctx, req, nil)
+	if err != nil {
+		return err
+	}
+	op, err := c.receive(ctx, id)
+	if err != nil {
+		return err
+	}
+	if op.tag != opBindResponse {
+		return fmt.Errorf("unexpected LDAP response 0x%x to bind", op.tag)
+	}
+	if err := result(op); err != nil {
+		return fmt.Errorf("bind as %q failed: %w", dn, err)
+	}
+	return nil
+}
+
+// search runs a search, following the paged results cookie when pageSize
+// is set, and calls fn with each entry
+func (c *conn) search(ctx context.Context, req searchRequest, fn func(entry)) error {
+	attrs := make([][]byte, len(req.attributes))
+	for i, attr := range req.attributes {
+		attrs[i] = octets(tagOctetString, attr)
+	}
+	cookie := ""
+	for {
+		op := constructed(opSearchRequest,
+			octets(tagOctetString, req.baseDN),
+			integer(tagEnumerated, int64(req.scope)),
+			integer(tagEnumerated, 0),
+			integer(tagInteger, 0),
+			integer(tagInteger, 0),
+			boolean(tagBoolean, false),
+			req.filter,
+			constructed(tagSequence, attrs...),
+		)
+		var controls []byte
+		if req.pageSize > 0 {
+			value := constructed(tagSequence, integer(tagInteger, int64(req.pageSize)), octets(tagOctetString, cookie))
+			controls = constructed(controlsTag, constructed(tagSequence, octets(tagOctetString, oidPagedResults), octets(tagOctetString, string(value))))
+		}
+		id, err := c.send(ctx, op, controls)
+		if err != nil {
+			return err
+		}
+		if cookie, err = c.readPage(ctx, id, req.baseDN, fn); err != nil {
+			return err
+		}
+		if req.pageSize == 0 || cookie == "" {
+			return nil
+		}
+	}
+}
+
+// readPage reads the entries of one search response and returns the
+// paged results cookie of the next page
+func (c *conn) readPage(ctx context.Context, id int64, baseDN string, fn func(entry)) (string, error) {
+	for {
+		msg, err := c.receiveMessage(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		switch msg.op.tag {
+		case opSearchEntry:
+			e, err := parseEntry(msg.op)
+			if err != nil {
+				return "", err
+			}
+			fn(e)
+		case opSearchReference:
+		case opSearchDone:
+			if err := result(msg.op); err != nil {
+				return "", fmt.Errorf("search of %q failed: %w", baseDN, err)
+			}
+			return pagedCookie(msg.controls), nil
+		default:
+			return "", fmt.Errorf("unexpected LDAP response 0x%x to search", msg.op.tag)
+		}
+	}
+}
+
+// close unbinds and closes the connection
+func (c *conn) close() {
+	c.netConn.SetWriteDeadline(time.Now().Add(time.Second))
+	c.nextID++
+	c.netConn.Write(constructed(tagSequence, integer(tagInteger, c.nextID), tlv(opUnbindRequest, nil)))
+	c.netConn.Close()
+}
+
+// send writes an LDAP message and returns its ID
+func (c *conn) send(ctx context.Context, op, controls []byte) (int64, error) {
+	c.nextID++
+	msg := constructed(tagSequence, integer(tagInteger, c.nextID), op, controls)
+	c.netConn.SetWriteDeadline(c.deadline(ctx))
+	if _, err := c.netConn.Write(msg); err != nil {
+		return 0, fmt.Errorf("failed to send LDAP request: %w", err)
+	}
+	return c.nextID, nil
+}
+
+// message is a decoded LDAP response
+type message struct {
+	op       element
+	controls []element
+}
+
+// receive reads the next response to message id
+func (c *conn) receive(ctx context.Context, id int64) (element, error) {
+	msg, err := c.receiveMessage(ctx, id)
+	return msg.op, err
+}
+
+// receiveMessage reads the next response to message id with its controls,
+// skipping unsolicited notifications
+func (c *conn) receiveMessage(ctx context.Context, id int64) (message, error) {
+	for {
+		c.netConn.SetReadDeadline(c.deadline(ctx))
+		raw, err := readMessage(c.r)
+		if err != nil {
+			return message{}, fmt.Errorf("failed to read LDAP response: %w", err)
+		}
+		outer, _, err := readElement(raw)
+		if err != nil {
+			return message{}, err
+		}
+		parts, err := outer.children()
+		if err != nil || len(parts) < 2 {
+			return message{}, fmt.Errorf("malformed LDAP message")
+		}
+		msgID, err := parts[0].int()
+		if err != nil {
+			return message{}, err
+		}
+		if msgID == 0 && parts[1].tag == opExtendedResponse {
+			if err := result(parts[1]); err != nil {
+				return message{}, fmt.Errorf("server closed the connection: %w", err)
+			}
+			continue
+		}
+		if msgID != id {
+			continue
+		}
+
+		msg := message{op: parts[1]}
+		if len(parts) > 2 && parts[2].tag == controlsTag {
+			msg.controls, _ = parts[2].children()
+		}
+		return msg, nil
+	}
+}
+
+// deadline is the deadline of one operation
+func (c *conn) deadline(ctx context.Context) time.Time {
+	deadline := time.Now().Add(c.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		return d
+	}
+	return deadline
+}
+
+// result returns the error of a non-success LDAPResult
+func result(op element) error {
+	parts, err := op.children()
+	if err != nil || len(parts) < 3 {
+		return fmt.Errorf("malformed LDAP result")
+	}
+	code, err := parts[0].int()
+	if err != nil {
+		return err
+	}
+	if code == 0 {
+		return nil
+	}
+	return &ResultError{Code: code, Message: string(parts[2].data)}
+}
+
+// parseEntry decodes a SearchResultEntry
+func parseEntry(op element) (entry, error) {
+	parts, err := op.children()
+	if err != nil || len(parts) < 2 {
+		return entry{}, fmt.Errorf("malformed LDAP search entry")
+	}
+	e := entry{dn: string(parts[0].data), attributes: make(map[string][]string)}
+	attrs, err := parts[1].children()
+	if err != nil {
+		return entry{}, err
+	}
+	for _, attr := range attrs {
+		pair, err := attr.children()
+		if err != nil || len(pair) < 2 {
+			return entry{}, fmt.Errorf("malformed attribute of %s", e.dn)
+		}
+		values, err := pair[1].children()
+		if err != nil {
+			return entry{}, err
+		}
+		name := string(pair[0].data)
+		for _, v := range values {
+			e.attributes[name] = append(e.attributes[name], string(v.data))
+		}
+	}
+	return e, nil
+}
+
+// pagedCookie returns the cookie of the paged results control, empty once
+// the last page was read
+func pagedCookie(controls []element) string {
+	for _, control := range controls {
+		parts, err := control.children()
+		if err != nil || len(parts) < 2 || string(parts[0].data) != oidPagedResults {
+			continue
+		}
+		value := parts[len(parts)-1]
+		inner, _, err := readElement(value.data)
+		if err != nil {
+			return ""
+		}
+		fields, err := inner.children()
+		if err != nil || len(fields) < 2 {
+			return ""
+		}
+		return string(fields[1].data)
+	}
+	return ""
+}
@@ -0,0 +1,192 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: ldap-filter
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * LDAP Search Filters
+ */
+
+package ldap
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Context tags of search filter choices (RFC 4511 section 4.5.1)
+const (
+	filterAnd          = 0xa0
+	filterOr           = 0xa1
+	filterNot          = 0xa2
+	filterEquality     = 0xa3
+	filterSubstrings   = 0xa4
+	filterGreaterEqual = 0xa5
+	filterLessEqual    = 0xa6
+	filterPresent      = 0x87
+	filterApprox       = 0xa8
+)
+
+// Context tags of substring parts
+const (
+	substringInitial = 0x80
+	substringAny     = 0x81
+	substringFinal   = 0x82
+)
+
+// compileFilter encodes a string filter in RFC 4515 syntax. The outer
+// parentheses may be left out of a single item such as objectClass=person.
+func compileFilter(filter string) ([]byte, error) {
+	filter = strings.TrimSpace(filter)
+	if !strings.HasPrefix(filter, "(") {
+		filter = "(" + filter + ")"
+	}
+	encoded, rest, err := parseFilter(filter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid LDAP filter %q: %w", filter, err)
+	}
+	if rest != "" {
+		return nil, fmt.Errorf("invalid LDAP filter %q: unexpected %q", filter, rest)
+	}
+	return encoded, nil
+}
+
+// parseFilter encodes the parenthesized filter at the start of s
+func parseFilter(s string) ([]byte, string, error) {
+	if !strings.HasPrefix(s, "(") {
+		return nil, "", fmt.Errorf("expected ( at %q", s)
+	}
+	s = s[1:]
+	if s == "" {
+		return nil, "", fmt.Errorf("unterminated filter")
+	}
+
+	var encoded []byte
+	switch s[0] {
+	case '&', '|':
+		tag := byte(filterAnd)
+		if s[0] == '|' {
+			tag = filterOr
+		}
+		s = s[1:]
+		var parts [][]byte
+		for strings.HasPrefix(s, "(") {
+			part, rest, err := parseFilter(s)
+			if err != nil {
+				return nil, "", err
+			}
+			parts = append(parts, part)
+			s = rest
+		}
+		encoded = constructed(tag, parts...)
+	case '!':
+		part, rest, err := parseFilter(s[1:])
+		if err != nil {
+			return nil, "", err
+		}
+		encoded, s = constructed(filterNot, part), rest
+	default:
+		end := strings.IndexByte(s, ')')
+		if end < 0 {
+			return nil, "", fmt.Errorf("unterminated filter")
+		}
+		item, err := parseItem(s[:end])
+		if err != nil {
+			return nil, "", err
+		}
+		encoded, s = item, s[end:]
+	}
+
+	if !strings.HasPrefix(s, ")") {
+		return nil, "", fmt.Errorf("expected ) at %q", s)
+	}
+	return encoded, s[1:], nil
+}
+
+// parseItem encodes a simple, presence or substring filter item
+func parseItem(item string) ([]byte, error) {
+	eq := strings.IndexByte(item, '=')
+	if eq <= 0 {
+		return nil, fmt.Errorf("expected attribute=value in %q", item)
+	}
+	attr, value := item[:eq], item[eq+1:]
+
+	tag := byte(filterEquality)
+	switch attr[len(attr)-1] {
+	case '~':
+		tag, attr = filterApprox, attr[:len(attr)-1]
+	case '>':
+		tag, attr = filterGreaterEqual, attr[:len(attr)-1]
+	case '<':
+		tag, attr = filterLessEqual, attr[:len(attr)-1]
+	case ':':
+		return nil, fmt.Errorf("extensible match filters are not supported")
+	}
+	if attr == "" {
+		return nil, fmt.Errorf("missing attribute in %q", item)
+	}
+
+	if tag == filterEquality && value == "*" {
+		return octets(filterPresent, attr), nil
+	}
+	if tag == filterEquality && strings.Contains(value, "*") {
+		pieces := strings.Split(value, "*")
+		var parts [][]byte
+		for i, piece := range pieces {
+			if piece == "" {
+				continue
+			}
+			unescaped, err := unescapeValue(piece)
+			if err != nil {
+				return nil, err
+			}
+			partTag := byte(substringAny)
+			switch i {
+			case 0:
+				partTag = substringInitial
+			case len(pieces) - 1:
+				partTag = substringFinal
+			}
+			parts = append(parts, octets(partTag, unescaped))
+		}
+		return constructed(filterSubstrings, octets(tagOctetString, attr), constructed(tagSequence, parts...)), nil
+	}
+
+	unescaped, err := unescapeValue(value)
+	if err != nil {
+		return nil, err
+	}
+	return constructed(tag, octets(tagOctetString, attr), octets(tagOctetString, unescaped)), nil
+}
+
+// unescapeValue decodes the \XX escapes of a filter value
+func unescapeValue(value string) (string, error) {
+	if !strings.Contains(value, `\`) {
+		return value, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' {
+			b.WriteByte(value[i])
+			continue
+		}
+		if i+3 > len(value) {
+			return "", fmt.Errorf("truncated escape in %q", value)
+		}
+		c, err := hex.DecodeString(value[i+1 : i+3])
+		if err != nil {
+			return "", fmt.Errorf("invalid escape in %q", value)
+		}
+		b.Write(c)
+		i += 2
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,39 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: ldap-fuzzing
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * LDAP Filter Fuzz Targets
+ */
+
+package ldap
+
+import "testing"
+
+// FuzzCompileFilter checks search filters compile or fail cleanly and that
+// compiled filters are one well-formed BER element
+func FuzzCompileFilter(f *testing.F) {
+	f.Fuzz(func(t *testing.T, filter string) {
+		encoded, err := compileFilter(filter)
+		if err != nil {
+			return
+		}
+		e, rest, err := readElement(encoded)
+		if err != nil || len(rest) > 0 {
+			t.Fatalf("%q: compiled filter is not one BER element: %v", filter, err)
+		}
+		if e.tag&0x20 != 0 {
+			if _, err := e.children(); err != nil {
+				t.Fatalf("%q: compiled filter has malformed parts: %v", filter, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,691 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: connector-ldap
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * LDAP Directory Source
+ */
+
+// Package ldap reads users and groups from an LDAP directory such as
+// OpenLDAP or Active Directory. Pipelines use it as:
+//
+//	source:
+//	  type: ldap
+//	  config:
+//	    url: ldaps://ldap.example.com
+//	    bind_dn: cn=esync,ou=services,dc=example,dc=com
+//	    password: ${secret:LDAP_PASSWORD}
+//	    base_dn: ou=people,dc=example,dc=com
+//	    filter: (objectClass=inetOrgPerson)
+//	    id_attribute: entryUUID
+//	    groups:
+//	      base_dn: ou=groups,dc=example,dc=com
+//
+// Directories offer no portable change feed, so each pass searches the
+// whole scope and diffs it against the previous pass: the checkpoint keeps
+// a digest of every entry and the members of every group. New entries are
+// emitted as inserts, changed ones as updates and vanished ones as deletes.
+// Group records carry their members as the IDs of the users and groups in
+// scope; updates also carry members_added and members_removed, so targets
+// can patch memberships instead of rewriting large groups.
+package ldap
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	_ "embed"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/machine-native-ops/esync-platform/internal/conflict"
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/fips"
+)
+
+// Defaults of the connector config
+const (
+	DefaultFilter          = "(objectClass=person)"
+	DefaultGroupFilter     = "(|(objectClass=groupOfNames)(objectClass=groupOfUniqueNames)(objectClass=group))"
+	DefaultMemberAttribute = "member"
+	DefaultUsersTable      = "users"
+	DefaultGroupsTable     = "groups"
+	DefaultPageSize        = 500
+	DefaultTimeout         = 30 * time.Second
+)
+
+// Fields of group records
+const (
+	FieldDN             = "dn"
+	FieldMembers        = "members"
+	FieldMembersAdded   = "members_added"
+	FieldMembersRemoved = "members_removed"
+)
+
+// modifyTimestamp is the operational attribute dating entries
+const modifyTimestamp = "modifyTimestamp"
+
+// configSchema documents the ldap block of pipelines
+//
+//go:embed config.schema.json
+var configSchema []byte
+
+func init() {
+	connectors.Register("ldap", connectors.WithSchema(New, configSchema))
+}
+
+// search is the scope of the users or groups search
+type search struct {
+	table       string
+	baseDN      string
+	scope       int
+	filter      []byte
+	attributes  []string
+	idAttribute string
+	// memberAttribute is set for groups
+	memberAttribute string
+}
+
+// Connector reads a directory as a source
+type Connector struct {
+	url         string
+	bindDN      string
+	password    string
+	startTLS    bool
+	tlsConfig   *tls.Config
+	timeout     time.Duration
+	pageSize    int
+	multiValued map[string]bool
+	users       search
+	groups      *search
+	resolver    *conflict.Resolver
+	limiter     connectors.Limiter
+
+	// pending is the snapshot taken by GetLatestCheckpoint, which the
+	// following ListChanges diffs so both agree on the position
+	mu      sync.Mutex
+	pending *snapshot
+}
+
+// snapshot is the state of the directory at one pass
+type snapshot struct {
+	position string
+	users    []connectors.Record
+	groups   []connectors.Record
+	// hashes maps table and record ID to the digest of the record data
+	hashes map[string]map[string]string
+	// members maps group IDs to their sorted member IDs
+	members map[string][]string
+}
+
+// New creates an ldap connector from its config block
+func New(config map[string]interface{}) (connectors.Connector, error) {
+	str := func(m map[string]interface{}, key string) string {
+		s, _ := m[key].(string)
+		return s
+	}
+
+	c := &Connector{
+		url:         str(config, "url"),
+		bindDN:      str(config, "bind_dn"),
+		password:    str(config, "password"),
+		startTLS:    config["start_tls"] == true,
+		timeout:     DefaultTimeout,
+		pageSize:    DefaultPageSize,
+		multiValued: make(map[string]bool),
+		resolver:    conflict.NewResolver(),
+	}
+	if c.bindDN == "" {
+		c.bindDN = str(config, "username")
+	}
+
+	tlsBlock, _ := config["tls"].(map[string]interface{})
+	if c.url == "" {
+		host := str(config, "host")
+		if host == "" {
+			return nil, fmt.Errorf("ldap url or host is required")
+		}
+		scheme, port := "ldap", "389"
+		if tlsBlock["enabled"] == true {
+			scheme, port = "ldaps", "636"
+		}
+		if v, ok := config["port"]; ok {
+			port = fmt.Sprint(v)
+		}
+		c.url = scheme + "://" + net.JoinHostPort(host, port)
+	}
+	if !strings.HasPrefix(c.url, "ldap://") && !strings.HasPrefix(c.url, "ldaps://") {
+		return nil, fmt.Errorf("ldap url %s must start with ldap:// or ldaps://", c.url)
+	}
+	tlsConfig, err := tlsFromConfig(tlsBlock)
+	if err != nil {
+		return nil, err
+	}
+	c.tlsConfig = tlsConfig
+
+	if v, ok := config["timeout"]; ok {
+		seconds, err := strconv.ParseFloat(fmt.Sprint(v), 64)
+		if err != nil || seconds <= 0 {
+			return nil, fmt.Errorf("invalid ldap timeout %v", v)
+		}
+		c.timeout = time.Duration(seconds * float64(time.Second))
+	}
+	if v, ok := config["page_size"]; ok {
+		n, err := strconv.Atoi(fmt.Sprint(v))
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid ldap page_size %v", v)
+		}
+		c.pageSize = n
+	}
+	for _, attr := range connectors.StringList(config["multi_valued"]) {
+		c.multiValued[strings.ToLower(attr)] = true
+	}
+
+	baseDN := str(config, "base_dn")
+	if baseDN == "" {
+		return nil, fmt.Errorf("ldap base_dn is required")
+	}
+	users, err := newSearch(config, search{
+		table:       DefaultUsersTable,
+		baseDN:      baseDN,
+		scope:       scopeSub,
+		idAttribute: str(config, "id_attribute"),
+	}, DefaultFilter)
+	if err != nil {
+		return nil, err
+	}
+	c.users = users
+
+	if block, ok := config["groups"].(map[string]interface{}); ok {
+		groups, err := newSearch(block, search{
+			table:           DefaultGroupsTable,
+			baseDN:          baseDN,
+			scope:           scopeSub,
+			idAttribute:     users.idAttribute,
+			memberAttribute: DefaultMemberAttribute,
+		}, DefaultGroupFilter)
+		if err != nil {
+			return nil, fmt.Errorf("ldap groups: %w", err)
+		}
+		if groups.table == users.table {
+			return nil, fmt.Errorf("ldap users and groups must use different tables, both use %s", users.table)
+		}
+		c.groups = &groups
+	}
+	return c, nil
+}
+
+// newSearch reads the search settings of a config block over defaults
+func newSearch(config map[string]interface{}, s search, defaultFilter string) (search, error) {
+	if v, _ := config["table"].(string); v != "" {
+		s.table = v
+	}
+	if v, _ := config["base_dn"].(string); v != "" {
+		s.baseDN = v
+	}
+	if v, _ := config["id_attribute"].(string); v != "" {
+		s.idAttribute = v
+	}
+	if v, _ := config["member_attribute"].(string); v != "" && s.memberAttribute != "" {
+		s.memberAttribute = v
+	}
+	switch config["scope"] {
+	case nil, "sub":
+	case "one":
+		s.scope = scopeOne
+	case "base":
+		s.scope = scopeBase
+	default:
+		return s, fmt.Errorf("unknown ldap scope %v, expected base, one or sub", config["scope"])
+	}
+
+	filter, _ := config["filter"].(string)
+	if filter == "" {
+		filter = defaultFilter
+	}
+	compiled, err := compileFilter(filter)
+	if err != nil {
+		return s, err
+	}
+	s.filter = compiled
+
+	s.attributes = connectors.StringList(config["attributes"])
+	if len(s.attributes) == 0 {
+		s.attributes = []string{"*"}
+	}
+	s.attributes = append(s.attributes, modifyTimestamp)
+	for _, attr := range []string{s.idAttribute, s.memberAttribute} {
+		if attr != "" {
+			s.attributes = append(s.attributes, attr)
+		}
+	}
+	return s, nil
+}
+
+// tlsFromConfig builds the TLS config of the "tls" block of a connector
+// config, restricted to approved settings in FIPS mode
+func tlsFromConfig(block map[string]interface{}) (*tls.Config, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	str := func(key string) string {
+		s, _ := block[key].(string)
+		return s
+	}
+	config.ServerName = str("server_name")
+	config.InsecureSkipVerify = block["insecure_skip_verify"] == true
+	switch str("min_version") {
+	case "", "1.2":
+	case "1.3":
+		config.MinVersion = tls.VersionTLS13
+	default:
+		return nil, fmt.Errorf("unsupported ldap tls min_version %s, expected 1.2 or 1.3", str("min_version"))
+	}
+	for _, name := range connectors.StringList(block["cipher_suites"]) {
+		id, ok := cipherSuite(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown ldap tls cipher suite %s", name)
+		}
+		config.CipherSuites = append(config.CipherSuites, id)
+	}
+	if path := str("ca_file"); path != "" {
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ldap tls ca_file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("ldap tls ca_file %s holds no certificates", path)
+		}
+		config.RootCAs = pool
+	}
+	if cert, key := str("cert_file"), str("key_file"); cert != "" || key != "" {
+		pair, err := tls.LoadX509KeyPair(cert, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load ldap tls client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{pair}
+	}
+	return fips.TLSConfig(config), nil
+}
+
+// cipherSuite looks a secure TLS 1.2 suite up by its IANA name
+func cipherSuite(name string) (uint16, bool) {
+	for _, suite := range tls.CipherSuites() {
+		if suite.Name == name {
+			return suite.ID, true
+		}
+	}
+	return 0, false
+}
+
+// Limit implements connectors.Limited
+func (c *Connector) Limit(limiter connectors.Limiter) {
+	c.limiter = limiter
+}
+
+// GetLatestCheckpoint implements connectors.Connector. It searches the
+// directory; the position is a digest of the entries found.
+func (c *Connector) GetLatestCheckpoint(ctx context.Context) (*connectors.Checkpoint, error) {
+	snap, err := c.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.pending = snap
+	c.mu.Unlock()
+
+	// Metadata holds JSON types so it reads the same after being stored
+	hashes := make(map[string]interface{}, len(snap.hashes))
+	for table, ids := range snap.hashes {
+		m := make(map[string]interface{}, len(ids))
+		for id, hash := range ids {
+			m[id] = hash
+		}
+		hashes[table] = m
+	}
+	members := make(map[string]interface{}, len(snap.members))
+	for id, list := range snap.members {
+		m := make([]interface{}, len(list))
+		for i, member := range list {
+			m[i] = member
+		}
+		members[id] = m
+	}
+	return &connectors.Checkpoint{
+		Position: snap.position,
+		Metadata: map[string]interface{}{"entries": hashes, "members": members},
+	}, nil
+}
+
+// ListChanges implements connectors.Connector. It diffs the directory
+// against checkpoint, listing every entry as an insert without one.
+func (c *Connector) ListChanges(ctx context.Context, checkpoint *connectors.Checkpoint) ([]connectors.Record, error) {
+	c.mu.Lock()
+	snap := c.pending
+	c.pending = nil
+	c.mu.Unlock()
+
+	if snap == nil {
+		var err error
+		if snap, err = c.snapshot(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if checkpoint != nil && checkpoint.Position == snap.position {
+		return nil, nil
+	}
+	groupsTable := ""
+	if c.groups != nil {
+		groupsTable = c.groups.table
+	}
+	return diff(snap, checkpoint, c.users.table, groupsTable), nil
+}
+
+// diff lists the changes from the state recorded in checkpoint to snap.
+// Upserts of users come first and their deletes last, so targets can
+// resolve group members.
+func diff(snap *snapshot, checkpoint *connectors.Checkpoint, usersTable, groupsTable string) []connectors.Record {
+	var previous, previousMembers map[string]interface{}
+	if checkpoint != nil {
+		previous, _ = checkpoint.Metadata["entries"].(map[string]interface{})
+		previousMembers, _ = checkpoint.Metadata["members"].(map[string]interface{})
+	}
+	hashesOf := func(table string) map[string]interface{} {
+		m, _ := previous[table].(map[string]interface{})
+		return m
+	}
+
+	now := time.Now().UTC()
+	var upserts, groupDeletes, userDeletes []connectors.Record
+	upsert := func(records []connectors.Record, table string) {
+		before := hashesOf(table)
+		for _, r := range records {
+			old, seen := before[r.ID].(string)
+			switch {
+			case !seen:
+				r.Operation = connectors.OperationInsert
+			case old != snap.hashes[table][r.ID]:
+				r.Operation = connectors.OperationUpdate
+			default:
+				continue
+			}
+			if seen && table == groupsTable {
+				r.Data = withMemberDiff(r.Data, previousMembers[r.ID], snap.members[r.ID])
+			}
+			upserts = append(upserts, r)
+		}
+	}
+	deletes := func(table string) []connectors.Record {
+		var out []connectors.Record
+		for id := range hashesOf(table) {
+			if _, exists := snap.hashes[table][id]; !exists {
+				out = append(out, connectors.Record{ID: id, Table: table, Operation: connectors.OperationDelete, Data: map[string]interface{}{}, Timestamp: now})
+			}
+		}
+		sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+		return out
+	}
+
+	upsert(snap.users, usersTable)
+	userDeletes = deletes(usersTable)
+	if groupsTable != "" {
+		upsert(snap.groups, groupsTable)
+		groupDeletes = deletes(groupsTable)
+	}
+	return append(append(upserts, groupDeletes...), userDeletes...)
+}
+
+// withMemberDiff adds the members added and removed since the previous
+// pass to the data of a group record
+func withMemberDiff(data map[string]interface{}, previous interface{}, current []string) map[string]interface{} {
+	before := make(map[string]bool)
+	list, _ := previous.([]interface{})
+	for _, id := range list {
+		before[fmt.Sprint(id)] = true
+	}
+
+	added, removed := []interface{}{}, []interface{}{}
+	for _, id := range current {
+		if !before[id] {
+			added = append(added, id)
+		}
+		delete(before, id)
+	}
+	gone := make([]string, 0, len(before))
+	for id := range before {
+		gone = append(gone, id)
+	}
+	sort.Strings(gone)
+	for _, id := range gone {
+		removed = append(removed, id)
+	}
+
+	out := make(map[string]interface{}, len(data)+2)
+	for k, v := range data {
+		out[k] = v
+	}
+	out[FieldMembersAdded] = added
+	out[FieldMembersRemoved] = removed
+	return out
+}
+
+// snapshot searches the users and groups in scope
+func (c *Connector) snapshot(ctx context.Context) (*snapshot, error) {
+	release, err := connectors.Acquire(ctx, c.limiter)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	conn, err := dial(ctx, c.url, c.tlsConfig, c.startTLS, c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.close()
+	if err := conn.bind(ctx, c.bindDN, c.password); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	snap := &snapshot{hashes: make(map[string]map[string]string), members: make(map[string][]string)}
+	// ids maps the normalized DNs of entries in scope to their record IDs
+	ids := make(map[string]string)
+
+	users, _, err := c.searchRecords(ctx, conn, c.users, now, ids)
+	if err != nil {
+		return nil, err
+	}
+	snap.users = users
+	if c.groups != nil {
+		groups, entries, err := c.searchRecords(ctx, conn, *c.groups, now, ids)
+		if err != nil {
+			return nil, err
+		}
+		for i, r := range groups {
+			members := c.members(entries[i], ids)
+			snap.members[r.ID] = members
+			list := make([]interface{}, len(members))
+			for j, id := range members {
+				list[j] = id
+			}
+			r.Data[FieldMembers] = list
+		}
+		snap.groups = groups
+	}
+
+	digest := sha256.New()
+	for _, records := range [][]connectors.Record{snap.users, snap.groups} {
+		for _, r := range records {
+			hash := hashData(r.Data)
+			if snap.hashes[r.Table] == nil {
+				snap.hashes[r.Table] = make(map[string]string)
+			}
+			snap.hashes[r.Table][r.ID] = hash
+			fmt.Fprintf(digest, "%s\x00%s\x00%s\n", r.Table, r.ID, hash)
+		}
+	}
+	snap.position = hex.EncodeToString(digest.Sum(nil))
+	return snap, nil
+}
+
+// searchRecords searches one scope and returns its records with their
+// entries, recording the ID of each entry DN in ids. Entries repeating an
+// ID are skipped.
+func (c *Connector) searchRecords(ctx context.Context, conn *conn, s search, now time.Time, ids map[string]string) ([]connectors.Record, []entry, error) {
+	var records []connectors.Record
+	var entries []entry
+	seen := make(map[string]bool)
+	err := conn.search(ctx, c.searchRequest(s), func(e entry) {
+		r, ok := c.record(e, s, now)
+		if !ok {
+			return
+		}
+		if seen[r.ID] {
+			log.Printf("[LDAP] Skipping %s, another entry has ID %s", e.dn, r.ID)
+			return
+		}
+		seen[r.ID] = true
+		ids[normalizeDN(e.dn)] = r.ID
+		records = append(records, r)
+		entries = append(entries, e)
+	})
+	return records, entries, err
+}
+
+// searchRequest is the request of one search scope
+func (c *Connector) searchRequest(s search) searchRequest {
+	return searchRequest{baseDN: s.baseDN, scope: s.scope, filter: s.filter, attributes: s.attributes, pageSize: c.pageSize}
+}
+
+// record converts an entry to a record, reporting false for entries
+// without an ID
+func (c *Connector) record(e entry, s search, now time.Time) (connectors.Record, bool) {
+	r := connectors.Record{ID: e.dn, Table: s.table, Timestamp: now, Data: map[string]interface{}{FieldDN: e.dn}}
+	for name, values := range e.attributes {
+		switch {
+		case strings.EqualFold(name, s.memberAttribute):
+			continue
+		case strings.EqualFold(name, modifyTimestamp) && len(values) > 0:
+			if t, err := parseGeneralizedTime(values[0]); err == nil {
+				r.Timestamp = t
+			}
+		}
+		if len(values) == 1 && !c.multiValued[strings.ToLower(name)] {
+			r.Data[name] = attributeValue(values[0])
+			continue
+		}
+		list := make([]interface{}, len(values))
+		for i, v := range values {
+			list[i] = attributeValue(v)
+		}
+		r.Data[name] = list
+	}
+
+	if s.idAttribute != "" {
+		id := ""
+		for name, values := range e.attributes {
+			if strings.EqualFold(name, s.idAttribute) && len(values) > 0 {
+				id = fmt.Sprint(attributeValue(values[0]))
+			}
+		}
+		if id == "" {
+			log.Printf("[LDAP] Skipping %s without %s", e.dn, s.idAttribute)
+			return r, false
+		}
+		r.ID = id
+	}
+	return r, true
+}
+
+// members resolves the member DNs of a group entry to the IDs of the
+// users and groups in scope; members out of scope are left out
+func (c *Connector) members(e entry, ids map[string]string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for name, values := range e.attributes {
+		if !strings.EqualFold(name, c.groups.memberAttribute) {
+			continue
+		}
+		for _, member := range values {
+			if id, ok := ids[normalizeDN(member)]; ok && !seen[id] {
+				seen[id] = true
+				out = append(out, id)
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// attributeValue returns a value as text, or base64 for binary values
+// such as objectGUID
+func attributeValue(v string) interface{} {
+	if utf8.ValidString(v) {
+		return v
+	}
+	return base64.StdEncoding.EncodeToString([]byte(v))
+}
+
+// normalizeDN folds the case and spacing of a DN for comparison
+func normalizeDN(dn string) string {
+	parts := strings.Split(dn, ",")
+	for i, part := range parts {
+		parts[i] = strings.ToLower(strings.TrimSpace(part))
+	}
+	return strings.Join(parts, ",")
+}
+
+// parseGeneralizedTime parses LDAP timestamps such as 20240101120000Z or
+// 20240101120000.0Z
+func parseGeneralizedTime(v string) (time.Time, error) {
+	for _, layout := range []string{"20060102150405Z0700", "20060102150405.999999999Z0700"} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid generalized time %q", v)
+}
+
+// hashData digests record data; JSON encoding sorts map keys
+func hashData(data map[string]interface{}) string {
+	b, _ := json.Marshal(data)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:8])
+}
+
+// ApplyChanges implements connectors.Connector; directories are read-only
+// sources
+func (c *Connector) ApplyChanges(ctx context.Context, changes []connectors.Record) error {
+	return fmt.Errorf("ldap connector cannot be a target: %w", connectors.ErrUnsupported)
+}
+
+// Validate implements connectors.Connector
+func (c *Connector) Validate(ctx context.Context, record connectors.Record) connectors.ValidationResult {
+	return connectors.ValidationResult{IsValid: true}
+}
+
+// ResolveConflict implements connectors.Connector
+func (c *Connector) ResolveConflict(ctx context.Context, existing, incoming connectors.Record) (connectors.Record, error) {
+	winner, _ := c.resolver.Resolve(existing, incoming)
+	return winner, nil
+}
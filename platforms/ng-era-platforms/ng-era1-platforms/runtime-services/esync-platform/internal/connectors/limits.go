@@ -0,0 +1,74 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: connector-call-limits
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Connector Call Limits
+ */
+
+package connectors
+
+import "context"
+
+// Limiter bounds the rate and concurrency of calls to an endpoint
+//
+// Frozen: public as connectors.Limiter
+type Limiter interface {
+	// Acquire blocks until a call may start and returns the func to call
+	// once it ends
+	Acquire(ctx context.Context) (release func(), err error)
+}
+
+// Limited is implemented by connectors whose calls can be bounded. The
+// engine hands them the limiter of their connection profile, shared by
+// every pipeline using it.
+//
+// Frozen: public as connectors.Limited
+type Limited interface {
+	Limit(limiter Limiter)
+}
+
+// limitersKey is the context key of the limiters attached by WithLimiter
+type limitersKey struct{}
+
+// WithLimiter returns a context whose connector calls are also bounded by
+// limiter, such as the call limits of one pipeline
+func WithLimiter(ctx context.Context, limiter Limiter) context.Context {
+	outer, _ := ctx.Value(limitersKey{}).([]Limiter)
+	limiters := append(append([]Limiter(nil), outer...), limiter)
+	return context.WithValue(ctx, limitersKey{}, limiters)
+}
+
+// Acquire waits for the limiters attached to ctx and then for own, which
+// may be nil. Connectors call it before each call to their endpoint and the
+// returned func once the call ends.
+func Acquire(ctx context.Context, own Limiter) (func(), error) {
+	limiters, _ := ctx.Value(limitersKey{}).([]Limiter)
+	if own != nil {
+		limiters = append(append([]Limiter(nil), limiters...), own)
+	}
+
+	releases := make([]func(), 0, len(limiters))
+	release := func() {
+		for i := len(releases) - 1; i >= 0; i-- {
+			releases[i]()
+		}
+	}
+	for _, limiter := range limiters {
+		r, err := limiter.Acquire(ctx)
+		if err != nil {
+			release()
+			return nil, err
+		}
+		releases = append(releases, r)
+	}
+	return release, nil
+}
@@ -0,0 +1,272 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: lookup-cache
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Connector Lookup Cache
+ */
+
+// Package lookupcache is the read-through cache connectors put in front of
+// per-record lookups against rate-limited endpoints, such as the SCIM ID
+// of the resource a record maps to. Connectors supporting it accept a
+// lookup_cache block in their config:
+//
+//	lookup_cache:
+//	  ttl: 3600
+//	  max_entries: 100000
+//	  redis: rediss://:${secret:REDIS_PASSWORD}@redis.example.com:6380/0
+//
+// Entries are kept in memory, least recently used first out, and, with a
+// Redis URL, in Redis too, so daemons and restarts share them. Redis
+// failures are logged and answered as misses.
+package lookupcache
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+)
+
+// Defaults applied to a lookup_cache block
+const (
+	DefaultTTL        = time.Hour
+	DefaultMaxEntries = 100000
+)
+
+// errorLogInterval bounds how often Redis failures are logged
+const errorLogInterval = time.Minute
+
+// Options configures a cache. Zero TTL keeps entries until they are
+// evicted and zero MaxEntries leaves the memory tier unbounded.
+type Options struct {
+	TTL        time.Duration
+	MaxEntries int
+	// Redis is a redis:// or rediss:// URL of the shared tier
+	Redis string
+	// Namespace separates the entries of one endpoint in Redis, such as
+	// its URL
+	Namespace string
+}
+
+// Cache is a two-tier lookup cache; it implements connectors.LookupCache
+type Cache struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+
+	redis    *redisClient
+	hits     atomic.Int64
+	misses   atomic.Int64
+	errors   atomic.Int64
+	logMu    sync.Mutex
+	loggedAt time.Time
+}
+
+// entry is a cached value and when it expires
+type entry struct {
+	key       string
+	value     string
+	expiresAt time.Time
+}
+
+// New creates a cache
+func New(opts Options) (*Cache, error) {
+	c := &Cache{
+		ttl:        opts.TTL,
+		maxEntries: opts.MaxEntries,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+	if opts.Redis != "" {
+		client, err := newRedisClient(opts.Redis, "esync:lookup:"+opts.Namespace+":")
+		if err != nil {
+			return nil, err
+		}
+		c.redis = client
+	}
+	return c, nil
+}
+
+// FromConfig creates the cache of a connector from its lookup_cache block.
+// Without a block the cache is unbounded and never expires, which suits
+// lookups the connector invalidates itself.
+func FromConfig(v interface{}, namespace string) (*Cache, error) {
+	if v == nil {
+		return New(Options{})
+	}
+	block, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("lookup_cache must be an object")
+	}
+
+	opts := Options{TTL: DefaultTTL, MaxEntries: DefaultMaxEntries, Namespace: namespace}
+	if v, ok := block["ttl"]; ok {
+		seconds, err := strconv.ParseFloat(fmt.Sprint(v), 64)
+		if err != nil || seconds <= 0 {
+			return nil, fmt.Errorf("invalid lookup_cache ttl %v", v)
+		}
+		opts.TTL = time.Duration(seconds * float64(time.Second))
+	}
+	if v, ok := block["max_entries"]; ok {
+		n, err := strconv.Atoi(fmt.Sprint(v))
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid lookup_cache max_entries %v", v)
+		}
+		opts.MaxEntries = n
+	}
+	opts.Redis, _ = block["redis"].(string)
+	return New(opts)
+}
+
+// Get returns the cached value of key from memory or else Redis
+func (c *Cache) Get(ctx context.Context, key string) (string, bool) {
+	if value, ok := c.getMemory(key, time.Now()); ok {
+		c.hits.Add(1)
+		return value, true
+	}
+	if c.redis != nil {
+		value, ok, err := c.redis.get(ctx, key)
+		if err != nil {
+			c.failed("read", err)
+		}
+		if ok {
+			c.setMemory(key, value, time.Now())
+			c.hits.Add(1)
+			return value, true
+		}
+	}
+	c.misses.Add(1)
+	return "", false
+}
+
+// Set caches the value of key
+func (c *Cache) Set(ctx context.Context, key, value string) {
+	c.setMemory(key, value, time.Now())
+	if c.redis != nil {
+		if err := c.redis.set(ctx, key, value, c.ttl); err != nil {
+			c.failed("write", err)
+		}
+	}
+}
+
+// Delete drops the entry of key
+func (c *Cache) Delete(ctx context.Context, key string) {
+	c.mu.Lock()
+	if el, ok := c.entries[key]; ok {
+		c.lru.Remove(el)
+		delete(c.entries, key)
+	}
+	c.mu.Unlock()
+
+	if c.redis != nil {
+		if err := c.redis.del(ctx, key); err != nil {
+			c.failed("delete", err)
+		}
+	}
+}
+
+// Clear drops every entry, in Redis those of the cache's namespace
+func (c *Cache) Clear(ctx context.Context) error {
+	c.mu.Lock()
+	c.entries = make(map[string]*list.Element)
+	c.lru.Init()
+	c.mu.Unlock()
+
+	if c.redis != nil {
+		if err := c.redis.clear(ctx); err != nil {
+			c.errors.Add(1)
+			return fmt.Errorf("failed to clear lookup cache in Redis: %w", err)
+		}
+	}
+	return nil
+}
+
+// Stats counts the lookups the cache answered
+func (c *Cache) Stats() connectors.LookupCacheStats {
+	c.mu.Lock()
+	entries := len(c.entries)
+	c.mu.Unlock()
+
+	return connectors.LookupCacheStats{
+		Hits:    c.hits.Load(),
+		Misses:  c.misses.Load(),
+		Entries: entries,
+		Shared:  c.redis != nil,
+		Errors:  c.errors.Load(),
+	}
+}
+
+// getMemory returns the unexpired value of key in memory
+func (c *Cache) getMemory(key string, now time.Time) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	e := el.Value.(*entry)
+	if !e.expiresAt.IsZero() && now.After(e.expiresAt) {
+		c.lru.Remove(el)
+		delete(c.entries, key)
+		return "", false
+	}
+	c.lru.MoveToFront(el)
+	return e.value, true
+}
+
+// setMemory caches a value in memory, evicting the least recently used
+// entries beyond the bound
+func (c *Cache) setMemory(key, value string, now time.Time) {
+	var expiresAt time.Time
+	if c.ttl > 0 {
+		expiresAt = now.Add(c.ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		e := el.Value.(*entry)
+		e.value, e.expiresAt = value, expiresAt
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(&entry{key: key, value: value, expiresAt: expiresAt})
+	for c.maxEntries > 0 && c.lru.Len() > c.maxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*entry).key)
+	}
+}
+
+// failed counts a Redis failure and logs it at most once a minute
+func (c *Cache) failed(op string, err error) {
+	c.errors.Add(1)
+
+	c.logMu.Lock()
+	defer c.logMu.Unlock()
+	if time.Since(c.loggedAt) < errorLogInterval {
+		return
+	}
+	c.loggedAt = time.Now()
+	log.Printf("[LookupCache] Failed to %s Redis, answering from memory: %v", op, err)
+}
@@ -0,0 +1,528 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: connector-neo4j
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Neo4j Graph Target
+ */
+
+// Package neo4j writes records to a Neo4j graph as MERGE operations on
+// nodes and relationships, declared by a mapping in the connector config:
+//
+//	target:
+//	  type: neo4j
+//	  config:
+//	    url: http://neo4j:7474
+//	    username: neo4j
+//	    password: ${secret:NEO4J_PASSWORD}
+//	    nodes:
+//	      - {table: people, label: Person, properties: [name, email]}
+//	      - {table: companies, label: Company}
+//	    relationships:
+//	      - table: people
+//	        type: WORKS_AT
+//	        to: {label: Company, field: company_id}
+//	      - table: memberships
+//	        type: MEMBER_OF
+//	        from: {label: Person, field: person_id}
+//	        to: {label: Team, field: team_id}
+//
+// Node mappings merge one node per record on its key property, the record
+// ID. Relationship mappings merge a relationship from the node of from to
+// each node listed in the to field, merging missing endpoints; from
+// defaults to the node of the record itself. Relationships carry the
+// record ID in their key property, so relationships a record no longer
+// lists are removed on update and all of them on delete. Keys are compared
+// as strings. Each batch is applied in one transaction over the HTTP API.
+package neo4j
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/conflict"
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/egress"
+)
+
+// Defaults of the connector config
+const (
+	DefaultDatabase = "neo4j"
+	DefaultKey      = "id"
+	DefaultTimeout  = 60 * time.Second
+)
+
+// configSchema documents the neo4j block of pipelines
+//
+//go:embed config.schema.json
+var configSchema []byte
+
+func init() {
+	connectors.Register("neo4j", connectors.WithSchema(New, configSchema))
+}
+
+// nodeMapping maps the records of a table to nodes
+type nodeMapping struct {
+	table      string
+	label      string
+	key        string
+	properties []string
+}
+
+// endpoint is one end of a relationship
+type endpoint struct {
+	label string
+	key   string
+	// field holds the key of the node; empty means the record ID
+	field string
+}
+
+// relationshipMapping maps the records of a table to relationships
+type relationshipMapping struct {
+	table      string
+	relType    string
+	key        string
+	from       endpoint
+	to         endpoint
+	properties []string
+}
+
+// Connector writes records to a Neo4j database
+type Connector struct {
+	endpoint      string
+	http          *http.Client
+	token         string
+	username      string
+	password      string
+	nodes         []nodeMapping
+	relationships []relationshipMapping
+	resolver      *conflict.Resolver
+	limiter       connectors.Limiter
+}
+
+// New creates a neo4j connector from its config block
+func New(config map[string]interface{}) (connectors.Connector, error) {
+	str := func(m map[string]interface{}, key string) string {
+		s, _ := m[key].(string)
+		return s
+	}
+
+	base := strings.TrimRight(str(config, "url"), "/")
+	if base == "" {
+		host := str(config, "host")
+		if host == "" {
+			return nil, fmt.Errorf("neo4j url or host is required")
+		}
+		scheme, port := "http", "7474"
+		if config["tls"] == true {
+			scheme, port = "https", "7473"
+		}
+		if v, ok := config["port"]; ok {
+			port = fmt.Sprint(v)
+		}
+		base = scheme + "://" + net.JoinHostPort(host, port)
+	}
+	if u, err := url.Parse(base); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("neo4j url %s must be an http or https URL", base)
+	}
+	database := str(config, "database")
+	if database == "" {
+		database = DefaultDatabase
+	}
+
+	timeout := DefaultTimeout
+	if v, ok := config["timeout"]; ok {
+		seconds, err := strconv.ParseFloat(fmt.Sprint(v), 64)
+		if err != nil || seconds <= 0 {
+			return nil, fmt.Errorf("invalid neo4j timeout %v", v)
+		}
+		timeout = time.Duration(seconds * float64(time.Second))
+	}
+
+	c := &Connector{
+		endpoint: base + "/db/" + url.PathEscape(database) + "/tx/commit",
+		http:     egress.Client(timeout),
+		token:    str(config, "token"),
+		username: str(config, "username"),
+		password: str(config, "password"),
+		resolver: conflict.NewResolver(),
+	}
+
+	for i, v := range list(config["nodes"]) {
+		m, _ := v.(map[string]interface{})
+		n := nodeMapping{table: str(m, "table"), label: str(m, "label"), key: str(m, "key"), properties: connectors.StringList(m["properties"])}
+		if n.label == "" {
+			return nil, fmt.Errorf("neo4j nodes[%d] needs a label", i)
+		}
+		if n.key == "" {
+			n.key = DefaultKey
+		}
+		c.nodes = append(c.nodes, n)
+	}
+	for i, v := range list(config["relationships"]) {
+		m, _ := v.(map[string]interface{})
+		r := relationshipMapping{table: str(m, "table"), relType: str(m, "type"), key: str(m, "key"), properties: connectors.StringList(m["properties"])}
+		if r.relType == "" {
+			return nil, fmt.Errorf("neo4j relationships[%d] needs a type", i)
+		}
+		if r.key == "" {
+			r.key = DefaultKey
+		}
+		from, _ := m["from"].(map[string]interface{})
+		to, _ := m["to"].(map[string]interface{})
+		r.from = endpoint{label: str(from, "label"), key: str(from, "key"), field: str(from, "field")}
+		r.to = endpoint{label: str(to, "label"), key: str(to, "key"), field: str(to, "field")}
+		if r.from.label == "" {
+			// The start node is the node of the record itself
+			for _, n := range c.nodes {
+				if n.table == r.table {
+					r.from.label, r.from.key = n.label, n.key
+					break
+				}
+			}
+		}
+		if r.from.label == "" || r.to.label == "" || r.to.field == "" {
+			return nil, fmt.Errorf("neo4j relationships[%d] needs from.label, unless a node mapping covers its table, and to.label and to.field", i)
+		}
+		for _, e := range []*endpoint{&r.from, &r.to} {
+			if e.key == "" {
+				e.key = DefaultKey
+			}
+		}
+		c.relationships = append(c.relationships, r)
+	}
+	if len(c.nodes) == 0 && len(c.relationships) == 0 {
+		return nil, fmt.Errorf("neo4j needs nodes or relationships mappings")
+	}
+	return c, nil
+}
+
+// list reads a list from a config value
+func list(v interface{}) []interface{} {
+	l, _ := v.([]interface{})
+	return l
+}
+
+// Limit implements connectors.Limited
+func (c *Connector) Limit(limiter connectors.Limiter) {
+	c.limiter = limiter
+}
+
+// Idempotent implements connectors.IdempotentWriter; nodes and
+// relationships are merged on their keys
+func (c *Connector) Idempotent() bool {
+	return true
+}
+
+// SupportsPatch implements connectors.PatchWriter; properties absent from
+// a record are left unchanged
+func (c *Connector) SupportsPatch() bool {
+	return true
+}
+
+// statement is a Cypher statement run for a list of rows
+type statement struct {
+	cypher string
+	rows   []interface{}
+}
+
+// ApplyChanges implements connectors.Connector. The statements of the
+// batch run in one transaction. Within each run of upserts or deletes,
+// records sharing a statement are sent together as its rows; a record
+// repeating one of the run starts a new run so changes apply in order.
+func (c *Connector) ApplyChanges(ctx context.Context, changes []connectors.Record) error {
+	var statements []statement
+	segment := make(map[string]int)
+	seen := make(map[string]bool)
+	add := func(cypher string, row map[string]interface{}) {
+		if i, ok := segment[cypher]; ok {
+			statements[i].rows = append(statements[i].rows, row)
+			return
+		}
+		segment[cypher] = len(statements)
+		statements = append(statements, statement{cypher: cypher, rows: []interface{}{row}})
+	}
+
+	deleting := false
+	for i, r := range changes {
+		key := r.Table + "\x00" + r.ID
+		if seen[key] || (i > 0 && deleting != (r.Operation == connectors.OperationDelete)) {
+			segment = make(map[string]int)
+			seen = make(map[string]bool)
+		}
+		seen[key] = true
+		deleting = r.Operation == connectors.OperationDelete
+
+		if deleting {
+			for _, m := range c.relationships {
+				if matches(m.table, r.Table) {
+					add(m.deleteCypher(), map[string]interface{}{"id": r.ID})
+				}
+			}
+			for _, n := range c.nodes {
+				if matches(n.table, r.Table) {
+					add(n.deleteCypher(), map[string]interface{}{"id": r.ID})
+				}
+			}
+			continue
+		}
+
+		for _, n := range c.nodes {
+			if matches(n.table, r.Table) {
+				add(n.mergeCypher(), map[string]interface{}{"id": r.ID, "props": properties(r.Data, n.properties)})
+			}
+		}
+		for _, m := range c.relationships {
+			if !matches(m.table, r.Table) {
+				continue
+			}
+			if r.Operation == connectors.OperationPatch && !r.Has(m.to.field) {
+				continue
+			}
+			from := r.ID
+			if m.from.field != "" {
+				if from = keyString(r.Data[m.from.field]); from == "" {
+					continue
+				}
+			}
+			props := map[string]interface{}{}
+			if len(m.properties) > 0 {
+				props = properties(r.Data, m.properties)
+			}
+			row := map[string]interface{}{"id": r.ID, "from": from, "to": keys(r.Data[m.to.field]), "props": props}
+			add(m.pruneCypher(), row)
+			add(m.mergeCypher(), row)
+		}
+	}
+	if len(statements) == 0 {
+		return nil
+	}
+	return c.run(ctx, statements)
+}
+
+// matches reports whether a mapping of table covers records of
+// recordTable; mappings without a table cover every record
+func matches(table, recordTable string) bool {
+	return table == "" || table == recordTable
+}
+
+// mergeCypher merges the node of each row and sets its properties
+func (n nodeMapping) mergeCypher() string {
+	return fmt.Sprintf("UNWIND $rows AS row MERGE (n:%s {%s: row.id}) SET n += row.props", quote(n.label), quote(n.key))
+}
+
+// deleteCypher deletes the node of each row with its relationships
+func (n nodeMapping) deleteCypher() string {
+	return fmt.Sprintf("UNWIND $rows AS row MATCH (n:%s {%s: row.id}) DETACH DELETE n", quote(n.label), quote(n.key))
+}
+
+// pruneCypher deletes the relationships of each row that no longer lead
+// from its start node to one of its end nodes
+func (m relationshipMapping) pruneCypher() string {
+	return fmt.Sprintf("UNWIND $rows AS row MATCH (a)-[r:%s {%s: row.id}]->(b) WHERE NOT (a:%s AND a.%s = row.from AND b:%s AND b.%s IN row.to) DELETE r",
+		quote(m.relType), quote(m.key), quote(m.from.label), quote(m.from.key), quote(m.to.label), quote(m.to.key))
+}
+
+// mergeCypher merges the endpoints and relationships of each row
+func (m relationshipMapping) mergeCypher() string {
+	return fmt.Sprintf("UNWIND $rows AS row MERGE (a:%s {%s: row.from}) WITH a, row UNWIND row.to AS to MERGE (b:%s {%s: to}) MERGE (a)-[r:%s {%s: row.id}]->(b) SET r += row.props",
+		quote(m.from.label), quote(m.from.key), quote(m.to.label), quote(m.to.key), quote(m.relType), quote(m.key))
+}
+
+// deleteCypher deletes the relationships of each row
+func (m relationshipMapping) deleteCypher() string {
+	return fmt.Sprintf("UNWIND $rows AS row MATCH ()-[r:%s {%s: row.id}]->() DELETE r", quote(m.relType), quote(m.key))
+}
+
+// quote escapes a label, type or property name for Cypher
+func quote(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// properties returns the named fields of data, or all of them, as
+// property values. Explicit nulls remove properties.
+func properties(data map[string]interface{}, names []string) map[string]interface{} {
+	out := make(map[string]interface{})
+	if len(names) == 0 {
+		for k, v := range data {
+			out[k] = propertyValue(v)
+		}
+		return out
+	}
+	for _, name := range names {
+		if v, ok := data[name]; ok {
+			out[name] = propertyValue(v)
+		}
+	}
+	return out
+}
+
+// propertyValue converts a value to a property: objects and lists of
+// objects, which properties cannot hold, become JSON text
+func propertyValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		b, _ := json.Marshal(v)
+		return string(b)
+	case []interface{}:
+		for _, item := range v {
+			switch item.(type) {
+			case map[string]interface{}, []interface{}:
+				b, _ := json.Marshal(v)
+				return string(b)
+			}
+		}
+	}
+	return v
+}
+
+// keyString returns a node key as text, or empty for null
+func keyString(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+// keys returns the node keys of a scalar or list field
+func keys(v interface{}) []string {
+	out := []string{}
+	values, ok := v.([]interface{})
+	if !ok {
+		values = []interface{}{v}
+	}
+	for _, item := range values {
+		if k := keyString(item); k != "" {
+			out = append(out, k)
+		}
+	}
+	return out
+}
+
+// run sends statements to the transactional endpoint, which commits them
+// as one transaction
+func (c *Connector) run(ctx context.Context, statements []statement) error {
+	release, err := connectors.Acquire(ctx, c.limiter)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	payload := make([]interface{}, len(statements))
+	for i, s := range statements {
+		payload[i] = map[string]interface{}{"statement": s.cypher, "parameters": map[string]interface{}{"rows": s.rows}}
+	}
+	body, err := json.Marshal(map[string]interface{}{"statements": payload})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	switch {
+	case c.token != "":
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	case c.username != "":
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("neo4j request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("neo4j returned %s: %s", resp.Status, strings.TrimSpace(string(raw)))
+	}
+	var result struct {
+		Errors []struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return fmt.Errorf("failed to decode neo4j response: %w", err)
+	}
+	if len(result.Errors) > 0 {
+		e := result.Errors[0]
+		return fmt.Errorf("neo4j rolled back the batch: %s: %s", e.Code, e.Message)
+	}
+	return nil
+}
+
+// Preflight implements connectors.Preflighter by running a trivial query
+func (c *Connector) Preflight(ctx context.Context, role string) []connectors.CheckResult {
+	check := connectors.CheckResult{Name: "neo4j_access", Status: connectors.CheckPassed}
+	if role != "target" {
+		check.Status = connectors.CheckFailed
+		check.Message = "the neo4j connector can only be a target"
+		check.Remedy = "use neo4j as the pipeline target"
+		return []connectors.CheckResult{check}
+	}
+	if err := c.run(ctx, []statement{{cypher: "RETURN 1"}}); err != nil {
+		check.Status = connectors.CheckFailed
+		check.Message = err.Error()
+		check.Remedy = "verify the neo4j url, database and credentials"
+	}
+	return []connectors.CheckResult{check}
+}
+
+// ListChanges implements connectors.Connector; the connector is a target
+// only
+func (c *Connector) ListChanges(ctx context.Context, checkpoint *connectors.Checkpoint) ([]connectors.Record, error) {
+	return nil, fmt.Errorf("neo4j connector cannot be a source: %w", connectors.ErrUnsupported)
+}
+
+// GetLatestCheckpoint implements connectors.Connector
+func (c *Connector) GetLatestCheckpoint(ctx context.Context) (*connectors.Checkpoint, error) {
+	return nil, nil
+}
+
+// Validate implements connectors.Connector; records of tables no mapping
+// covers are invalid
+func (c *Connector) Validate(ctx context.Context, record connectors.Record) connectors.ValidationResult {
+	for _, n := range c.nodes {
+		if matches(n.table, record.Table) {
+			return connectors.ValidationResult{IsValid: true}
+		}
+	}
+	for _, m := range c.relationships {
+		if matches(m.table, record.Table) {
+			return connectors.ValidationResult{IsValid: true}
+		}
+	}
+	return connectors.ValidationResult{Errors: []string{fmt.Sprintf("no neo4j node or relationship mapping covers table %q", record.Table)}}
+}
+
+// ResolveConflict implements connectors.Connector
+func (c *Connector) ResolveConflict(ctx context.Context, existing, incoming connectors.Record) (connectors.Record, error) {
+	winner, _ := c.resolver.Resolve(existing, incoming)
+	return winner, nil
+}
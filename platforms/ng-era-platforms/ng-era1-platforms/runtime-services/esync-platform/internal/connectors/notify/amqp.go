@@ -0,0 +1,505 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: notify-amqp
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * AMQP 0-9-1 Publisher
+ */
+
+package notify
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/machine-native-ops/esync-platform/internal/egress"
+	"github.com/machine-native-ops/esync-platform/internal/fips"
+)
+
+// AMQP frame types
+const (
+	frameMethod = 1
+	frameHeader = 2
+	frameBody   = 3
+	frameEnd    = 0xCE
+)
+
+// AMQP classes and methods used by the publisher, as class<<16 | method
+const (
+	methodConnectionStart   = 10<<16 | 10
+	methodConnectionStartOk = 10<<16 | 11
+	methodConnectionTune    = 10<<16 | 30
+	methodConnectionTuneOk  = 10<<16 | 31
+	methodConnectionOpen    = 10<<16 | 40
+	methodConnectionOpenOk  = 10<<16 | 41
+	methodConnectionClose   = 10<<16 | 50
+	methodConnectionCloseOk = 10<<16 | 51
+	methodChannelOpen       = 20<<16 | 10
+	methodChannelOpenOk     = 20<<16 | 11
+	methodChannelClose      = 20<<16 | 40
+	methodChannelCloseOk    = 20<<16 | 41
+	methodBasicPublish      = 60<<16 | 40
+	methodBasicAck          = 60<<16 | 80
+	methodBasicNack         = 60<<16 | 120
+	methodConfirmSelect     = 85<<16 | 10
+	methodConfirmSelectOk   = 85<<16 | 11
+)
+
+// classBasic is the class of content headers
+const classBasic = 60
+
+// amqpWindow is how many messages are published before awaiting their
+// confirms
+const amqpWindow = 100
+
+// amqpSender publishes messages to an exchange with publisher confirms,
+// one connection per batch
+type amqpSender struct {
+	url         *url.URL
+	exchange    string
+	contentType string
+	persistent  bool
+}
+
+// newAMQPSender creates the sender of the amqp block
+func newAMQPSender(block map[string]interface{}) (*amqpSender, error) {
+	str := func(key string) string {
+		s, _ := block[key].(string)
+		return s
+	}
+
+	u, err := url.Parse(str("url"))
+	if err != nil || (u.Scheme != "amqp" && u.Scheme != "amqps") || u.Hostname() == "" {
+		return nil, fmt.Errorf("notify amqp url must be an amqp:// or amqps:// URL")
+	}
+	s := &amqpSender{
+		url:         u,
+		exchange:    str("exchange"),
+		contentType: str("content_type"),
+		persistent:  block["persistent"] != false,
+	}
+	if s.contentType == "" {
+		s.contentType = "application/json"
+	}
+	return s, nil
+}
+
+// amqpConn is an open connection with channel 1 in confirm mode
+type amqpConn struct {
+	conn     net.Conn
+	r        *bufio.Reader
+	frameMax int
+}
+
+// amqpError is a connection or channel close sent by the server
+type amqpError struct {
+	code int
+	text string
+}
+
+func (e *amqpError) Error() string {
+	return fmt.Sprintf("AMQP server closed the session: %d %s", e.code, e.text)
+}
+
+// dial connects, authenticates with PLAIN, opens the virtual host and a
+// channel and selects publisher confirms
+func (s *amqpSender) dial(ctx context.Context) (*amqpConn, error) {
+	port := s.url.Port()
+	if port == "" {
+		port = "5672"
+		if s.url.Scheme == "amqps" {
+			port = "5671"
+		}
+	}
+	addr := net.JoinHostPort(s.url.Hostname(), port)
+	conn, err := egress.Default().DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	if s.url.Scheme == "amqps" {
+		conn = tls.Client(conn, fips.TLSConfig(&tls.Config{ServerName: s.url.Hostname(), MinVersion: tls.VersionTLS12}))
+	}
+
+	c := &amqpConn{conn: conn, r: bufio.NewReader(conn), frameMax: 131072}
+	if err := c.open(s.url); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// open runs the connection handshake
+func (c *amqpConn) open(u *url.URL) error {
+	if _, err := c.conn.Write([]byte("AMQP\x00\x00\x09\x01")); err != nil {
+		return err
+	}
+	args, err := c.expect(0, methodConnectionStart)
+	if err != nil {
+		return err
+	}
+	if len(args) < 2 {
+		return fmt.Errorf("malformed AMQP connection start")
+	}
+	// version-major, version-minor and server-properties precede the
+	// mechanisms
+	d := decoder{b: args[2:]}
+	d.table()
+	if mechanisms := d.longString(); !strings.Contains(" "+mechanisms+" ", " PLAIN ") {
+		return fmt.Errorf("AMQP server does not offer PLAIN authentication: %s", mechanisms)
+	}
+
+	user, password := "guest", "guest"
+	if u.User != nil {
+		user = u.User.Username()
+		password, _ = u.User.Password()
+	}
+	var e encoder
+	e.table(map[string]string{"product": "esync-platform"})
+	e.shortString("PLAIN")
+	e.longString("\x00" + user + "\x00" + password)
+	e.shortString("en_US")
+	if err := c.method(0, methodConnectionStartOk, e.b.Bytes()); err != nil {
+		return err
+	}
+
+	args, err = c.expect(0, methodConnectionTune)
+	if err != nil {
+		return err
+	}
+	if len(args) < 8 {
+		return fmt.Errorf("malformed AMQP connection tune")
+	}
+	channelMax := binary.BigEndian.Uint16(args[0:])
+	if frameMax := int(binary.BigEndian.Uint32(args[2:])); frameMax > 0 && frameMax < c.frameMax {
+		c.frameMax = frameMax
+	}
+	e = encoder{}
+	e.short(channelMax)
+	e.long(uint32(c.frameMax))
+	// Heartbeats are disabled; connections live for one batch
+	e.short(0)
+	if err := c.method(0, methodConnectionTuneOk, e.b.Bytes()); err != nil {
+		return err
+	}
+
+	vhost := "/"
+	if p := strings.TrimPrefix(u.Path, "/"); p != "" {
+		vhost, _ = url.PathUnescape(p)
+	}
+	e = encoder{}
+	e.shortString(vhost)
+	e.shortString("")
+	e.b.WriteByte(0)
+	if err := c.method(0, methodConnectionOpen, e.b.Bytes()); err != nil {
+		return err
+	}
+	if _, err := c.expect(0, methodConnectionOpenOk); err != nil {
+		return err
+	}
+
+	if err := c.method(1, methodChannelOpen, []byte{0}); err != nil {
+		return err
+	}
+	if _, err := c.expect(1, methodChannelOpenOk); err != nil {
+		return err
+	}
+	if err := c.method(1, methodConfirmSelect, []byte{0}); err != nil {
+		return err
+	}
+	_, err = c.expect(1, methodConfirmSelectOk)
+	return err
+}
+
+// send implements sender. Messages are published in order, in windows
+// awaiting the confirms of the broker, and the batch succeeds once all of
+// them are confirmed.
+func (s *amqpSender) send(ctx context.Context, messages []message) error {
+	c, err := s.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.close()
+
+	for start := 0; start < len(messages); start += amqpWindow {
+		window := messages[start:]
+		if len(window) > amqpWindow {
+			window = window[:amqpWindow]
+		}
+		// Delivery tags count the messages of the channel from 1
+		pending := make(map[uint64]bool, len(window))
+		for i, m := range window {
+			if err := c.publish(s, m); err != nil {
+				return err
+			}
+			pending[uint64(start+i+1)] = true
+		}
+		if err := c.awaitConfirms(pending); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// awaitConfirms reads acks until the pending delivery tags are confirmed;
+// acks and nacks with the multiple bit cover all tags up to their own
+func (c *amqpConn) awaitConfirms(pending map[uint64]bool) error {
+	for len(pending) > 0 {
+		channel, method, args, err := c.readMethod()
+		if err != nil {
+			return err
+		}
+		if channel != 1 || (method != methodBasicAck && method != methodBasicNack) || len(args) < 9 {
+			continue
+		}
+		tag, multiple := binary.BigEndian.Uint64(args), args[8]&1 == 1
+		if method == methodBasicNack {
+			return fmt.Errorf("AMQP broker rejected message %d of the batch", tag)
+		}
+		for t := range pending {
+			if t == tag || (multiple && t < tag) {
+				delete(pending, t)
+			}
+		}
+	}
+	return nil
+}
+
+// publish sends a message as a publish method, a content header and body
+// frames
+func (c *amqpConn) publish(s *amqpSender, m message) error {
+	var e encoder
+	e.short(0)
+	e.shortString(s.exchange)
+	e.shortString(m.key)
+	// mandatory and immediate unset
+	e.b.WriteByte(0)
+	if err := c.method(1, methodBasicPublish, e.b.Bytes()); err != nil {
+		return err
+	}
+
+	// content-type, delivery-mode and message-id properties
+	e = encoder{}
+	e.short(classBasic)
+	e.short(0)
+	binary.Write(&e.b, binary.BigEndian, uint64(len(m.body)))
+	e.short(1<<15 | 1<<12 | 1<<7)
+	e.shortString(s.contentType)
+	if s.persistent {
+		e.b.WriteByte(2)
+	} else {
+		e.b.WriteByte(1)
+	}
+	e.shortString(m.id)
+	if err := c.frame(frameHeader, 1, e.b.Bytes()); err != nil {
+		return err
+	}
+
+	chunk := c.frameMax - 8
+	for body := m.body; len(body) > 0; {
+		n := len(body)
+		if n > chunk {
+			n = chunk
+		}
+		if err := c.frame(frameBody, 1, body[:n]); err != nil {
+			return err
+		}
+		body = body[n:]
+	}
+	return nil
+}
+
+// close closes the connection, politely when it is still usable
+func (c *amqpConn) close() {
+	var e encoder
+	e.short(200)
+	e.shortString("")
+	e.short(0)
+	e.short(0)
+	if c.method(0, methodConnectionClose, e.b.Bytes()) == nil {
+		c.expect(0, methodConnectionCloseOk)
+	}
+	c.conn.Close()
+}
+
+// frame writes one frame
+func (c *amqpConn) frame(kind byte, channel uint16, payload []byte) error {
+	header := make([]byte, 7, 8+len(payload))
+	header[0] = kind
+	binary.BigEndian.PutUint16(header[1:], channel)
+	binary.BigEndian.PutUint32(header[3:], uint32(len(payload)))
+	_, err := c.conn.Write(append(append(header, payload...), frameEnd))
+	return err
+}
+
+// method writes a method frame
+func (c *amqpConn) method(channel uint16, method uint32, args []byte) error {
+	payload := binary.BigEndian.AppendUint32(nil, method)
+	return c.frame(frameMethod, channel, append(payload, args...))
+}
+
+// readMethod reads the next method frame, skipping heartbeats. Close
+// methods of the server are answered and returned as errors.
+func (c *amqpConn) readMethod() (uint16, uint32, []byte, error) {
+	for {
+		var header [7]byte
+		if _, err := io.ReadFull(c.r, header[:]); err != nil {
+			return 0, 0, nil, fmt.Errorf("failed to read AMQP frame: %w", err)
+		}
+		size := binary.BigEndian.Uint32(header[3:])
+		if size > 1<<24 {
+			return 0, 0, nil, fmt.Errorf("AMQP frame of %d bytes is too large", size)
+		}
+		payload := make([]byte, size+1)
+		if _, err := io.ReadFull(c.r, payload); err != nil {
+			return 0, 0, nil, fmt.Errorf("failed to read AMQP frame: %w", err)
+		}
+		if payload[size] != frameEnd {
+			return 0, 0, nil, fmt.Errorf("malformed AMQP frame")
+		}
+		channel := binary.BigEndian.Uint16(header[1:])
+		if header[0] != frameMethod || size < 4 {
+			continue
+		}
+		method := binary.BigEndian.Uint32(payload)
+		args := payload[4:size]
+		switch method {
+		case methodConnectionClose, methodChannelClose:
+			d := decoder{b: args}
+			code := int(d.short())
+			text := d.shortString()
+			if method == methodConnectionClose {
+				c.method(0, methodConnectionCloseOk, nil)
+			} else {
+				c.method(channel, methodChannelCloseOk, nil)
+			}
+			return 0, 0, nil, &amqpError{code: code, text: text}
+		}
+		return channel, method, args, nil
+	}
+}
+
+// expect reads the next method and fails unless it is the wanted one
+func (c *amqpConn) expect(channel uint16, method uint32) ([]byte, error) {
+	ch, got, args, err := c.readMethod()
+	if err != nil {
+		return nil, err
+	}
+	if ch != channel || got != method {
+		return nil, fmt.Errorf("unexpected AMQP method %d.%d, expected %d.%d", got>>16, got&0xffff, method>>16, method&0xffff)
+	}
+	return args, nil
+}
+
+// check implements sender
+func (s *amqpSender) check(ctx context.Context) error {
+	c, err := s.dial(ctx)
+	if err != nil {
+		return err
+	}
+	c.close()
+	return nil
+}
+
+// encoder writes AMQP argument types
+type encoder struct {
+	b bytes.Buffer
+}
+
+func (e *encoder) short(v uint16) {
+	binary.Write(&e.b, binary.BigEndian, v)
+}
+
+func (e *encoder) long(v uint32) {
+	binary.Write(&e.b, binary.BigEndian, v)
+}
+
+func (e *encoder) shortString(s string) {
+	if len(s) > 255 {
+		s = s[:255]
+	}
+	e.b.WriteByte(byte(len(s)))
+	e.b.WriteString(s)
+}
+
+func (e *encoder) longString(s string) {
+	e.long(uint32(len(s)))
+	e.b.WriteString(s)
+}
+
+// table writes a field table of long string values
+func (e *encoder) table(fields map[string]string) {
+	var t encoder
+	for k, v := range fields {
+		t.shortString(k)
+		t.b.WriteByte('S')
+		t.longString(v)
+	}
+	e.long(uint32(t.b.Len()))
+	e.b.Write(t.b.Bytes())
+}
+
+// decoder reads AMQP argument types; reads past the end yield zero values
+type decoder struct {
+	b []byte
+}
+
+func (d *decoder) take(n int) []byte {
+	if n > len(d.b) {
+		n = len(d.b)
+	}
+	v := d.b[:n]
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *decoder) short() uint16 {
+	v := d.take(2)
+	if len(v) < 2 {
+		return 0
+	}
+	return binary.BigEndian.Uint16(v)
+}
+
+func (d *decoder) long() uint32 {
+	v := d.take(4)
+	if len(v) < 4 {
+		return 0
+	}
+	return binary.BigEndian.Uint32(v)
+}
+
+func (d *decoder) shortString() string {
+	n := d.take(1)
+	if len(n) == 0 {
+		return ""
+	}
+	return string(d.take(int(n[0])))
+}
+
+func (d *decoder) longString() string {
+	return string(d.take(int(d.long())))
+}
+
+// table skips a field table
+func (d *decoder) table() {
+	d.take(int(d.long()))
+}
@@ -0,0 +1,217 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: notify-email
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * SMTP Email Sender
+ */
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/egress"
+	"github.com/machine-native-ops/esync-platform/internal/fips"
+)
+
+// TLS modes of SMTP connections
+const (
+	// SMTPStartTLS upgrades plain connections, failing when the server
+	// does not offer STARTTLS
+	SMTPStartTLS = "starttls"
+	// SMTPImplicitTLS connects with TLS, as on port 465
+	SMTPImplicitTLS = "implicit"
+	// SMTPNoTLS sends in the clear, for local relays only
+	SMTPNoTLS = "none"
+)
+
+// smtpSender sends messages as emails through an SMTP server, one
+// connection per batch
+type smtpSender struct {
+	host        string
+	port        string
+	tlsMode     string
+	username    string
+	password    string
+	from        string
+	to          []string
+	contentType string
+	// sender is the bare address of from
+	sender string
+}
+
+// newSMTPSender creates the sender of the email block
+func newSMTPSender(block map[string]interface{}) (*smtpSender, error) {
+	str := func(key string) string {
+		s, _ := block[key].(string)
+		return s
+	}
+
+	s := &smtpSender{
+		host:        str("host"),
+		port:        "587",
+		tlsMode:     str("tls"),
+		username:    str("username"),
+		password:    str("password"),
+		from:        str("from"),
+		contentType: "text/plain",
+	}
+	if s.host == "" || s.from == "" {
+		return nil, fmt.Errorf("notify email requires host and from")
+	}
+	from, err := mail.ParseAddress(s.from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid notify email from address: %w", err)
+	}
+	s.sender = from.Address
+	if v, ok := block["port"]; ok {
+		s.port = fmt.Sprint(v)
+	}
+	switch {
+	case s.tlsMode == "" && s.port == "465":
+		s.tlsMode = SMTPImplicitTLS
+	case s.tlsMode == "":
+		s.tlsMode = SMTPStartTLS
+	case s.tlsMode != SMTPStartTLS && s.tlsMode != SMTPImplicitTLS && s.tlsMode != SMTPNoTLS:
+		return nil, fmt.Errorf("unknown notify email tls %q, expected %s, %s or %s", s.tlsMode, SMTPStartTLS, SMTPImplicitTLS, SMTPNoTLS)
+	}
+	if str("content_type") == "html" {
+		s.contentType = "text/html"
+	}
+	to, _ := block["to"].([]interface{})
+	for _, addr := range to {
+		s.to = append(s.to, fmt.Sprint(addr))
+	}
+	if len(s.to) == 0 && str("to_field") == "" {
+		return nil, fmt.Errorf("notify email requires to or to_field")
+	}
+	return s, nil
+}
+
+// dial connects and authenticates to the server
+func (s *smtpSender) dial(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(s.host, s.port)
+	conn, err := egress.Default().DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	tlsConfig := fips.TLSConfig(&tls.Config{ServerName: s.host, MinVersion: tls.VersionTLS12})
+	if s.tlsMode == SMTPImplicitTLS {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("SMTP greeting failed: %w", err)
+	}
+	if s.tlsMode == SMTPStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			client.Close()
+			return nil, fmt.Errorf("SMTP server %s does not offer STARTTLS", addr)
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("STARTTLS failed: %w", err)
+		}
+	}
+	if s.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.username, s.password, s.host)); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+	return client, nil
+}
+
+// send implements sender
+func (s *smtpSender) send(ctx context.Context, messages []message) error {
+	client, err := s.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	for _, m := range messages {
+		to := m.to
+		if len(to) == 0 {
+			to = s.to
+		}
+		if len(to) == 0 {
+			continue
+		}
+		if err := client.Mail(s.sender); err != nil {
+			return fmt.Errorf("SMTP MAIL FROM failed: %w", err)
+		}
+		for _, addr := range to {
+			rcpt, err := mail.ParseAddress(addr)
+			if err != nil {
+				return fmt.Errorf("invalid recipient %q: %w", addr, err)
+			}
+			if err := client.Rcpt(rcpt.Address); err != nil {
+				return fmt.Errorf("SMTP server refused recipient %s: %w", rcpt.Address, err)
+			}
+		}
+		w, err := client.Data()
+		if err != nil {
+			return fmt.Errorf("SMTP DATA failed: %w", err)
+		}
+		if _, err := w.Write(s.compose(m, to)); err != nil {
+			return err
+		}
+		if err := w.Close(); err != nil {
+			return fmt.Errorf("SMTP server refused message: %w", err)
+		}
+	}
+	return client.Quit()
+}
+
+// compose formats a message as a MIME email with a quoted-printable body
+func (s *smtpSender) compose(m message, to []string) []byte {
+	var b bytes.Buffer
+	domain := s.sender[strings.LastIndex(s.sender, "@")+1:]
+	fmt.Fprintf(&b, "From: %s\r\n", s.from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&b, "Message-ID: <%s@%s>\r\n", m.id, domain)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&b, "Content-Type: %s; charset=utf-8\r\n", s.contentType)
+	b.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+	qp := quotedprintable.NewWriter(&b)
+	qp.Write(m.body)
+	qp.Close()
+	return b.Bytes()
+}
+
+// check implements sender
+func (s *smtpSender) check(ctx context.Context) error {
+	client, err := s.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	return client.Quit()
+}
@@ -0,0 +1,368 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: connector-notify
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Notification Target
+ */
+
+// Package notify renders records through templates into notifications,
+// sent as emails over SMTP or as messages to an AMQP exchange or an SQS
+// queue. Pipelines use it as:
+//
+//	target:
+//	  type: notify
+//	  config:
+//	    channel: email
+//	    operations: [insert]
+//	    subject: "New order {{.ID}}"
+//	    body: "{{.Data.customer}} ordered {{.Data.total}}"
+//	    email:
+//	      host: smtp.example.com
+//	      username: alerts
+//	      password: ${secret:SMTP_PASSWORD}
+//	      from: alerts@example.com
+//	      to: [sales@example.com]
+//
+// Templates use Go text/template syntax over the record, with .Table, .ID,
+// .Operation, .Timestamp and .Data; html email bodies use html/template
+// escaping. functions picks the version of the template function library
+// (see package templatefuncs), the json function only when absent.
+// Without a body the record is sent as JSON.
+//
+// Notifications are sent at least once: a batch failing part way is sent
+// again in full on retry. SQS FIFO queues deduplicate repeated messages of
+// a record change by their deduplication ID.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"net/mail"
+	"strconv"
+	"text/template"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/conflict"
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/templatefuncs"
+)
+
+// Channels notifications are sent to
+const (
+	ChannelEmail = "email"
+	ChannelAMQP  = "amqp"
+	ChannelSQS   = "sqs"
+)
+
+// DefaultTimeout bounds sending one batch
+const DefaultTimeout = 30 * time.Second
+
+// configSchema documents the notify block of pipelines
+//
+//go:embed config.schema.json
+var configSchema []byte
+
+func init() {
+	connectors.Register("notify", connectors.WithSchema(New, configSchema))
+}
+
+// executor is a parsed text or html template
+type executor interface {
+	Execute(w io.Writer, data interface{}) error
+}
+
+// message is one rendered notification
+type message struct {
+	subject string
+	body    []byte
+	// to are the email recipients
+	to []string
+	// key is the AMQP routing key or the SQS message group
+	key string
+	// id identifies the record change, stable across retries
+	id string
+}
+
+// sender delivers the messages of a batch in order
+type sender interface {
+	send(ctx context.Context, messages []message) error
+	// check verifies the channel can be reached
+	check(ctx context.Context) error
+}
+
+// Connector sends notifications of record changes
+type Connector struct {
+	channel    string
+	operations map[string]bool
+	subject    executor
+	body       executor
+	key        executor
+	toField    string
+	sender     sender
+	timeout    time.Duration
+	resolver   *conflict.Resolver
+	limiter    connectors.Limiter
+	metrics    connectors.MetricsReporter
+}
+
+// New creates a notify connector from its config block
+func New(config map[string]interface{}) (connectors.Connector, error) {
+	str := func(m map[string]interface{}, key string) string {
+		s, _ := m[key].(string)
+		return s
+	}
+
+	timeout := DefaultTimeout
+	if v, ok := config["timeout"]; ok {
+		seconds, err := strconv.ParseFloat(fmt.Sprint(v), 64)
+		if err != nil || seconds <= 0 {
+			return nil, fmt.Errorf("invalid notify timeout %v", v)
+		}
+		timeout = time.Duration(seconds * float64(time.Second))
+	}
+
+	c := &Connector{
+		channel:  str(config, "channel"),
+		timeout:  timeout,
+		resolver: conflict.NewResolver(),
+	}
+	if ops, _ := config["operations"].([]interface{}); len(ops) > 0 {
+		c.operations = make(map[string]bool)
+		for _, op := range ops {
+			c.operations[fmt.Sprint(op)] = true
+		}
+	}
+
+	block, _ := config[c.channel].(map[string]interface{})
+	if block == nil {
+		return nil, fmt.Errorf("notify channel %s needs a %s block", c.channel, c.channel)
+	}
+	version, err := templatefuncs.Version(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid notify config: %w", err)
+	}
+	funcs, err := templatefuncs.Funcs(version)
+	if err != nil {
+		return nil, err
+	}
+	html := false
+	switch c.channel {
+	case ChannelEmail:
+		html = str(block, "content_type") == "html"
+		c.toField = str(block, "to_field")
+		c.sender, err = newSMTPSender(block)
+	case ChannelAMQP:
+		c.key, err = parse("routing_key", str(block, "routing_key"), false, funcs)
+		if err == nil {
+			c.sender, err = newAMQPSender(block)
+		}
+	case ChannelSQS:
+		c.key, err = parse("group_id", str(block, "group_id"), false, funcs)
+		if err == nil {
+			c.sender, err = newSQSSender(block, timeout)
+		}
+	default:
+		return nil, fmt.Errorf("unknown notify channel %q, expected %s, %s or %s", c.channel, ChannelEmail, ChannelAMQP, ChannelSQS)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	subject := str(config, "subject")
+	if subject == "" {
+		subject = "{{.Table}} {{.ID}} {{.Operation}}"
+	}
+	if c.subject, err = parse("subject", subject, false, funcs); err != nil {
+		return nil, err
+	}
+	if c.body, err = parse("body", str(config, "body"), html, funcs); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// parse compiles a template with the given functions; empty text yields nil
+func parse(name, text string, html bool, funcs map[string]interface{}) (executor, error) {
+	if text == "" {
+		return nil, nil
+	}
+	var t executor
+	var err error
+	if html {
+		t, err = htmltemplate.New(name).Funcs(funcs).Option("missingkey=zero").Parse(text)
+	} else {
+		t, err = template.New(name).Funcs(funcs).Option("missingkey=zero").Parse(text)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid notify %s template: %w", name, err)
+	}
+	return t, nil
+}
+
+// render executes a template over a record
+func render(t executor, r connectors.Record) ([]byte, error) {
+	var b bytes.Buffer
+	if err := t.Execute(&b, r); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// Limit implements connectors.Limited
+func (c *Connector) Limit(limiter connectors.Limiter) {
+	c.limiter = limiter
+}
+
+// Instrument implements connectors.Instrumented
+func (c *Connector) Instrument(reporter connectors.MetricsReporter) {
+	c.metrics = reporter
+}
+
+// ApplyChanges implements connectors.Connector by rendering a message per
+// record of the selected operations and sending the batch
+func (c *Connector) ApplyChanges(ctx context.Context, changes []connectors.Record) error {
+	var messages []message
+	for _, r := range changes {
+		if c.operations != nil && !c.operations[r.Operation] {
+			continue
+		}
+		m, err := c.message(r)
+		if err != nil {
+			return fmt.Errorf("failed to render notification of %s: %w", r.ID, err)
+		}
+		messages = append(messages, m)
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+
+	release, err := connectors.Acquire(ctx, c.limiter)
+	if err != nil {
+		return err
+	}
+	defer release()
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	if err := c.sender.send(ctx, messages); err != nil {
+		return fmt.Errorf("failed to send %s notifications: %w", c.channel, err)
+	}
+	if c.metrics != nil {
+		c.metrics.Count("notifications_sent", float64(len(messages)))
+	}
+	return nil
+}
+
+// message renders the notification of a record
+func (c *Connector) message(r connectors.Record) (message, error) {
+	subject, err := render(c.subject, r)
+	if err != nil {
+		return message{}, err
+	}
+	m := message{subject: string(subject), id: changeID(r)}
+	if c.body != nil {
+		if m.body, err = render(c.body, r); err != nil {
+			return message{}, err
+		}
+	} else if m.body, err = json.Marshal(r); err != nil {
+		return message{}, err
+	}
+	if c.key != nil {
+		key, err := render(c.key, r)
+		if err != nil {
+			return message{}, err
+		}
+		m.key = string(key)
+	}
+	if c.toField != "" {
+		switch to := r.Data[c.toField].(type) {
+		case string:
+			m.to = []string{to}
+		case []interface{}:
+			for _, addr := range to {
+				m.to = append(m.to, fmt.Sprint(addr))
+			}
+		}
+	}
+	return m, nil
+}
+
+// changeID identifies a record change by its table, ID, operation and
+// timestamp
+func changeID(r connectors.Record) string {
+	sum := sha256.Sum256([]byte(r.Table + "\x00" + r.ID + "\x00" + r.Operation + "\x00" + r.Timestamp.UTC().Format(time.RFC3339Nano)))
+	return hex.EncodeToString(sum[:16])
+}
+
+// Preflight implements connectors.Preflighter by reaching the channel
+func (c *Connector) Preflight(ctx context.Context, role string) []connectors.CheckResult {
+	check := connectors.CheckResult{Name: "notify_" + c.channel, Status: connectors.CheckPassed}
+	if role != "target" {
+		check.Status = connectors.CheckFailed
+		check.Message = "the notify connector can only be a target"
+		check.Remedy = "use notify as the pipeline target"
+		return []connectors.CheckResult{check}
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	if err := c.sender.check(ctx); err != nil {
+		check.Status = connectors.CheckFailed
+		check.Message = err.Error()
+		check.Remedy = fmt.Sprintf("verify the %s address and credentials", c.channel)
+	}
+	return []connectors.CheckResult{check}
+}
+
+// ListChanges implements connectors.Connector; the connector is a target
+// only
+func (c *Connector) ListChanges(ctx context.Context, checkpoint *connectors.Checkpoint) ([]connectors.Record, error) {
+	return nil, fmt.Errorf("notify connector cannot be a source: %w", connectors.ErrUnsupported)
+}
+
+// GetLatestCheckpoint implements connectors.Connector
+func (c *Connector) GetLatestCheckpoint(ctx context.Context) (*connectors.Checkpoint, error) {
+	return nil, nil
+}
+
+// Validate implements connectors.Connector by rendering the notification
+func (c *Connector) Validate(ctx context.Context, record connectors.Record) connectors.ValidationResult {
+	if c.operations != nil && !c.operations[record.Operation] {
+		return connectors.ValidationResult{IsValid: true}
+	}
+	m, err := c.message(record)
+	if err != nil {
+		return connectors.ValidationResult{Errors: []string{err.Error()}}
+	}
+	if s, ok := c.sender.(*smtpSender); ok {
+		if len(m.to) == 0 && len(s.to) == 0 {
+			return connectors.ValidationResult{Errors: []string{fmt.Sprintf("record has no recipients in %s", c.toField)}}
+		}
+		for _, addr := range m.to {
+			if _, err := mail.ParseAddress(addr); err != nil {
+				return connectors.ValidationResult{Errors: []string{fmt.Sprintf("invalid recipient %q in %s", addr, c.toField)}}
+			}
+		}
+	}
+	return connectors.ValidationResult{IsValid: true}
+}
+
+// ResolveConflict implements connectors.Connector
+func (c *Connector) ResolveConflict(ctx context.Context, existing, incoming connectors.Record) (connectors.Record, error) {
+	winner, _ := c.resolver.Resolve(existing, incoming)
+	return winner, nil
+}
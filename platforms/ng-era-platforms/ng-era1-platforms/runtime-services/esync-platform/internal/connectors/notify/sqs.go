@@ -0,0 +1,175 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: notify-sqs
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Amazon SQS Sender
+ */
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/cloudauth"
+	"github.com/machine-native-ops/esync-platform/internal/egress"
+)
+
+// sqsBatchSize is the most entries SendMessageBatch takes
+const sqsBatchSize = 10
+
+// sqsSender sends messages to an SQS queue with SendMessageBatch, signed
+// with static keys or the workload credentials
+type sqsSender struct {
+	queueURL string
+	region   string
+	creds    *cloudauth.AWSCredentials
+	auth     cloudauth.Authenticator
+	http     *http.Client
+}
+
+// newSQSSender creates the sender of the sqs block
+func newSQSSender(block map[string]interface{}, timeout time.Duration) (*sqsSender, error) {
+	str := func(key string) string {
+		s, _ := block[key].(string)
+		return s
+	}
+
+	s := &sqsSender{queueURL: str("queue_url"), region: str("region"), http: egress.Client(timeout)}
+	u, err := url.Parse(s.queueURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return nil, fmt.Errorf("notify sqs queue_url must be an http or https URL")
+	}
+	if s.region == "" {
+		// sqs.<region>.amazonaws.com
+		if parts := strings.Split(u.Hostname(), "."); len(parts) == 4 && parts[0] == "sqs" {
+			s.region = parts[1]
+		}
+	}
+
+	switch {
+	case str("access_key_id") != "":
+		if s.region == "" {
+			return nil, fmt.Errorf("notify sqs requires region with access keys")
+		}
+		s.creds = &cloudauth.AWSCredentials{
+			AccessKeyID:     str("access_key_id"),
+			SecretAccessKey: str("secret_access_key"),
+			SessionToken:    str("session_token"),
+		}
+	default:
+		authBlock, _ := block["cloud_auth"].(map[string]interface{})
+		spec := &cloudauth.Spec{Provider: cloudauth.ProviderAWS, Service: "sqs", Region: s.region}
+		if authBlock != nil {
+			if spec, err = cloudauth.FromConfig(authBlock); err != nil {
+				return nil, err
+			}
+		}
+		if s.auth, err = cloudauth.New(spec); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// sqsBatchResult is the result element of SendMessageBatch responses
+type sqsBatchResult struct {
+	Failed []struct {
+		ID      string `xml:"Id"`
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	} `xml:"SendMessageBatchResult>BatchResultErrorEntry"`
+}
+
+// sqsError is the error element of SQS responses
+type sqsError struct {
+	Code    string `xml:"Error>Code"`
+	Message string `xml:"Error>Message"`
+}
+
+// send implements sender. Messages keyed with a group ID go to FIFO
+// queues with the change ID as deduplication ID.
+func (s *sqsSender) send(ctx context.Context, messages []message) error {
+	for start := 0; start < len(messages); start += sqsBatchSize {
+		batch := messages[start:]
+		if len(batch) > sqsBatchSize {
+			batch = batch[:sqsBatchSize]
+		}
+		form := url.Values{"Action": {"SendMessageBatch"}, "Version": {"2012-11-05"}}
+		for i, m := range batch {
+			prefix := "SendMessageBatchRequestEntry." + strconv.Itoa(i+1) + "."
+			form.Set(prefix+"Id", strconv.Itoa(i))
+			form.Set(prefix+"MessageBody", string(m.body))
+			if m.key != "" {
+				form.Set(prefix+"MessageGroupId", m.key)
+				form.Set(prefix+"MessageDeduplicationId", m.id)
+			}
+		}
+
+		var result sqsBatchResult
+		if err := s.call(ctx, form, &result); err != nil {
+			return err
+		}
+		if len(result.Failed) > 0 {
+			f := result.Failed[0]
+			return fmt.Errorf("SQS refused %d of %d messages: %s: %s", len(result.Failed), len(batch), f.Code, f.Message)
+		}
+	}
+	return nil
+}
+
+// call posts a query API action to the queue and decodes the response
+func (s *sqsSender) call(ctx context.Context, form url.Values, out interface{}) error {
+	body := []byte(form.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.queueURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if s.creds != nil {
+		cloudauth.SignV4(req, body, s.creds, s.region, "sqs", time.Now())
+	} else if err := s.auth.Authorize(req); err != nil {
+		return err
+	}
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("SQS request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode/100 != 2 {
+		var e sqsError
+		if xml.Unmarshal(raw, &e) == nil && e.Code != "" {
+			return fmt.Errorf("SQS returned %s: %s: %s", resp.Status, e.Code, e.Message)
+		}
+		return fmt.Errorf("SQS returned %s: %s", resp.Status, strings.TrimSpace(string(raw)))
+	}
+	if err := xml.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("failed to decode SQS response: %w", err)
+	}
+	return nil
+}
+
+// check implements sender by reading an attribute of the queue
+func (s *sqsSender) check(ctx context.Context) error {
+	var out struct{}
+	return s.call(ctx, url.Values{"Action": {"GetQueueAttributes"}, "Version": {"2012-11-05"}, "AttributeName.1": {"QueueArn"}}, &out)
+}
@@ -0,0 +1,380 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: connector-optional-capabilities
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Optional Connector Capabilities
+ */
+
+package connectors
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrUnsupported is returned by optional methods a connector implements only
+// conditionally, such as plugins lacking a capability
+var ErrUnsupported = errors.New("operation not supported by connector")
+
+// ErrRejected is wrapped by the errors of targets that rejected a batch of
+// changes without writing any of it, so retrying it unchanged cannot succeed
+var ErrRejected = errors.New("changes rejected by target")
+
+// Estimator is implemented by sources that can estimate how many records a
+// full run will produce, enabling progress and ETA reporting
+//
+// Frozen: public as connectors.Estimator
+type Estimator interface {
+	EstimateTotal(ctx context.Context) (int64, error)
+}
+
+// KeyRange is a half-open range of record keys [Start, End). Empty bounds
+// are unbounded.
+//
+// Frozen: public as connectors.KeyRange
+type KeyRange struct {
+	Start string `json:"start,omitempty"`
+	End   string `json:"end,omitempty"`
+}
+
+// RangeReader is implemented by sources that support chunked backfills by
+// reading their full contents one key range at a time
+//
+// Frozen: public as connectors.RangeReader
+type RangeReader interface {
+	// KeyRanges splits the source key space into roughly n ranges
+	KeyRanges(ctx context.Context, n int) ([]KeyRange, error)
+	// ReadRange returns all current records whose keys fall in r
+	ReadRange(ctx context.Context, r KeyRange) ([]Record, error)
+}
+
+// Check statuses reported by pre-flight checks
+const (
+	CheckPassed  = "passed"
+	CheckFailed  = "failed"
+	CheckSkipped = "skipped"
+)
+
+// CheckResult is the outcome of one pre-flight check
+//
+// Frozen: public as connectors.CheckResult
+type CheckResult struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+	// Remedy tells the operator how to fix a failed check
+	Remedy string `json:"remedy,omitempty"`
+}
+
+// Preflighter is implemented by connectors that can verify connectivity and
+// permissions (e.g. replication slot access or table write grants) before a
+// pipeline starts. Role is "source" or "target".
+//
+// Frozen: public as connectors.Preflighter
+type Preflighter interface {
+	Preflight(ctx context.Context, role string) []CheckResult
+}
+
+// Field describes one field of a record schema
+//
+// Frozen: public as connectors.Field
+type Field struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Nullable bool   `json:"nullable"`
+}
+
+// Schema describes the record layout of a source or target
+//
+// Frozen: public as connectors.Schema
+type Schema struct {
+	Fields []Field `json:"fields"`
+}
+
+// Field returns the named field, if present
+func (s *Schema) Field(name string) (Field, bool) {
+	for _, f := range s.Fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+// SchemaProvider is implemented by connectors that can introspect their schema
+//
+// Frozen: public as connectors.SchemaProvider
+type SchemaProvider interface {
+	Schema(ctx context.Context) (*Schema, error)
+}
+
+// IdempotentWriter is implemented by targets whose ApplyChanges upserts by
+// record ID, so re-applying a batch after a crash leaves no duplicates
+//
+// Frozen: public as connectors.IdempotentWriter
+type IdempotentWriter interface {
+	Idempotent() bool
+}
+
+// IDAssigner is implemented by targets that assign the IDs of inserted
+// records themselves, such as tables with identity columns
+//
+// Frozen: public as connectors.IDAssigner
+type IDAssigner interface {
+	// ApplyReturningIDs applies records like ApplyChanges; records with an
+	// empty ID are inserted under an ID the target assigns. It returns the
+	// target ID of each record, in order.
+	ApplyReturningIDs(ctx context.Context, records []Record) ([]string, error)
+}
+
+// RecordReader is implemented by targets that can read records back by ID,
+// enabling verification of what was written
+//
+// Frozen: public as connectors.RecordReader
+type RecordReader interface {
+	// ReadRecords returns the current records with the given IDs; missing
+	// records are omitted
+	ReadRecords(ctx context.Context, ids []string) ([]Record, error)
+}
+
+// PatchWriter is implemented by targets that merge patch records into the
+// stored record themselves. Patches for other targets are merged by the
+// engine, which requires a RecordReader target.
+//
+// Frozen: public as connectors.PatchWriter
+type PatchWriter interface {
+	SupportsPatch() bool
+}
+
+// TableReferencer is implemented by relational targets that can report
+// their foreign keys, letting the engine apply parent rows before children
+//
+// Frozen: public as connectors.TableReferencer
+type TableReferencer interface {
+	// TableReferences maps each table to the tables it references
+	TableReferences(ctx context.Context) (map[string][]string, error)
+}
+
+// Kinds of captured schema changes
+const (
+	DDLAddColumn   = "add_column"
+	DDLAlterColumn = "alter_column"
+	DDLDropColumn  = "drop_column"
+	DDLOther       = "other"
+)
+
+// DDLEvent is a schema change captured from a CDC source
+//
+// Frozen: public as connectors.DDLEvent
+type DDLEvent struct {
+	Table  string `json:"table"`
+	Kind   string `json:"kind"`
+	Column string `json:"column,omitempty"`
+	// Type and Nullable describe the new column definition
+	Type     string `json:"type,omitempty"`
+	Nullable bool   `json:"nullable,omitempty"`
+	// Statement is the source DDL as captured
+	Statement string    `json:"statement"`
+	Position  string    `json:"position"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// DDLSource is implemented by CDC sources that capture schema changes
+//
+// Frozen: public as connectors.DDLSource
+type DDLSource interface {
+	// ListDDL returns the schema changes after checkpoint, oldest first
+	ListDDL(ctx context.Context, checkpoint *Checkpoint) ([]DDLEvent, error)
+}
+
+// DDLApplier is implemented by SQL targets that can replay captured schema
+// changes, typically as ALTER TABLE statements
+//
+// Frozen: public as connectors.DDLApplier
+type DDLApplier interface {
+	ApplyDDL(ctx context.Context, event DDLEvent) error
+}
+
+// BootstrapRequest describes the destination objects a pipeline needs
+//
+// Frozen: public as connectors.BootstrapRequest
+type BootstrapRequest struct {
+	// Schema is the source schema, from which SQL targets infer table DDL
+	// and search targets infer mappings; nil when the source cannot
+	// describe itself
+	Schema *Schema `json:"schema,omitempty"`
+	// Tables lists the tables routed to the target, empty for single-table
+	// pipelines
+	Tables []string `json:"tables,omitempty"`
+}
+
+// Bootstrapper is implemented by targets that can create missing
+// destination objects such as tables, indexes, topics or indices. Bootstrap
+// must leave existing objects untouched and returns a description of each
+// object it created.
+//
+// Frozen: public as connectors.Bootstrapper
+type Bootstrapper interface {
+	Bootstrap(ctx context.Context, req BootstrapRequest) ([]string, error)
+}
+
+// TeardownRequest describes the release of a pipeline's connector
+// resources. Role is "source" or "target".
+//
+// Frozen: public as connectors.TeardownRequest
+type TeardownRequest struct {
+	Role string `json:"role"`
+	// DryRun lists the resources without removing them
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// Teardowner is implemented by connectors holding resources on behalf of a
+// pipeline, such as replication slots, consumer groups or temporary tables.
+// Teardown releases them and returns a description of each, or only lists
+// them on a dry run; resources already gone are skipped.
+//
+// Frozen: public as connectors.Teardowner
+type Teardowner interface {
+	Teardown(ctx context.Context, req TeardownRequest) ([]string, error)
+}
+
+// Resource is a sync-related resource a connector holds in its system, such
+// as a replication slot, consumer group or staging table
+//
+// Frozen: public as connectors.Resource
+type Resource struct {
+	// Kind is the resource type, e.g. replication_slot or consumer_group
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// Pipeline is the ID of the pipeline the resource was created for, as
+	// recorded in its name or tags; empty when the connector cannot tell
+	Pipeline  string    `json:"pipeline,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+}
+
+// ResourceLister is implemented by connectors that can enumerate the
+// sync-related resources in their system, including those created for
+// pipelines that no longer exist
+//
+// Frozen: public as connectors.ResourceLister
+type ResourceLister interface {
+	ListResources(ctx context.Context) ([]Resource, error)
+}
+
+// Credentials is the configuration of a connector after referenced secrets
+// changed
+//
+// Frozen: public as connectors.Credentials
+type Credentials struct {
+	// Config is the full connector config with the new secret values
+	Config map[string]interface{}
+	// Changed lists the config paths whose secrets changed, e.g. password
+	Changed []string
+}
+
+// Reconfigurer is implemented by connectors that can swap credentials on
+// open connections. Connectors without it are reconnected when a secret
+// they reference changes, and closed first if they implement io.Closer.
+//
+// Frozen: public as connectors.Reconfigurer
+type Reconfigurer interface {
+	Reconfigure(ctx context.Context, creds Credentials) error
+}
+
+// CanaryWriter is implemented by sources that can insert a synthetic canary
+// record, so canaries travel the whole path from the source. The engine
+// injects the canaries of other sources into its own input.
+//
+// Frozen: public as connectors.CanaryWriter
+type CanaryWriter interface {
+	WriteCanary(ctx context.Context, record Record) error
+}
+
+// Acknowledger is implemented by CDC sources that hold changes until they
+// are confirmed, such as Postgres replication slots. The engine acknowledges
+// every checkpoint it saves, including those of passes without changes, so
+// the source can release what it retained while the pipeline was idle.
+//
+// Frozen: public as connectors.Acknowledger
+type Acknowledger interface {
+	Acknowledge(ctx context.Context, checkpoint *Checkpoint) error
+}
+
+// SourceClock is implemented by sources that can read their own clock,
+// such as SELECT now() on a database, letting the engine measure how far
+// it is off from the daemon clock
+//
+// Frozen: public as connectors.SourceClock
+type SourceClock interface {
+	SourceTime(ctx context.Context) (time.Time, error)
+}
+
+// MetricsReporter receives internal stats of a connector such as HTTP
+// retries, pool usage or round-trip latency. The engine exports them as
+// esync_connector_<type>_<name> metrics labelled with the connection
+// profile, so connectors need not register collectors of their own.
+//
+// Frozen: public as connectors.MetricsReporter
+type MetricsReporter interface {
+	// Count adds delta to a counter, exported with a _total suffix
+	Count(name string, delta float64)
+	// Gauge sets a gauge
+	Gauge(name string, value float64)
+	// Observe records a value, e.g. a latency in seconds, in a histogram
+	Observe(name string, value float64)
+}
+
+// Instrumented is implemented by connectors that report internal stats.
+// The engine calls Instrument once after creating the connector.
+//
+// Frozen: public as connectors.Instrumented
+type Instrumented interface {
+	Instrument(reporter MetricsReporter)
+}
+
+// LookupCacheStats counts the lookups a lookup cache answered. Entries is
+// the size of its in-memory tier; Shared reports a Redis tier, whose
+// failures count as Errors and are answered as misses.
+//
+// Frozen: public as connectors.LookupCacheStats
+type LookupCacheStats struct {
+	Hits    int64 `json:"hits"`
+	Misses  int64 `json:"misses"`
+	Entries int   `json:"entries"`
+	Shared  bool  `json:"shared,omitempty"`
+	Errors  int64 `json:"errors,omitempty"`
+}
+
+// LookupCache caches the results of the per-record lookups a connector
+// makes against its endpoint, such as the ID of the resource a record maps
+// to. Entries expire after the TTL the cache is configured with.
+//
+// Frozen: public as connectors.LookupCache
+type LookupCache interface {
+	Get(ctx context.Context, key string) (string, bool)
+	Set(ctx context.Context, key, value string)
+	// Delete drops an entry the connector knows to be stale, such as that
+	// of a resource it deleted
+	Delete(ctx context.Context, key string)
+	// Clear drops every entry of the connector
+	Clear(ctx context.Context) error
+	Stats() LookupCacheStats
+}
+
+// LookupCacher is implemented by connectors that cache per-record lookups,
+// configured by the lookup_cache block of their config
+//
+// Frozen: public as connectors.LookupCacher
+type LookupCacher interface {
+	LookupCache() LookupCache
+}
@@ -0,0 +1,189 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: connector-plugin-limits
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Plugin Resource Limits
+ */
+
+package plugin
+
+import (
+	"fmt"
+	"log"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// Limits bounds the resources of a plugin process so a leaking plugin
+// cannot starve the host. Zero fields leave that resource unbounded.
+//
+// On Linux the process starts in a cgroup v2 group of its own, bounding its
+// CPU, memory and processes, below the cgroup delegated with SetCgroup; the open files
+// are bounded by RLIMIT_NOFILE and, without a cgroup, the memory by
+// RLIMIT_DATA. On Windows the process runs in a job object; Windows cannot
+// bound open handles. Limits that cannot be enforced are logged when the
+// plugin starts and reported by its pre-flight check.
+type Limits struct {
+	// CPU is the number of CPUs the plugin may use, such as 0.5
+	CPU float64 `json:"cpu,omitempty"`
+	// MemoryMB is the memory the plugin may use in MiB
+	MemoryMB int64 `json:"memory_mb,omitempty"`
+	// MaxOpenFiles bounds the file descriptors the plugin holds open
+	MaxOpenFiles int64 `json:"max_open_files,omitempty"`
+	// MaxProcesses bounds the processes and threads of the plugin
+	MaxProcesses int64 `json:"max_processes,omitempty"`
+}
+
+var (
+	cgroupMu   sync.Mutex
+	cgroupPath string
+)
+
+// SetCgroup names the cgroup v2 group, as listed in /proc/self/cgroup,
+// delegated to the daemon for the groups of plugin processes, such as
+// /system.slice/esync.service/plugins. The group must have the cpu, memory
+// and pids controllers and hold no processes; the daemon never moves
+// processes out of a group it was not given. Without one, the limits a
+// cgroup bounds are not enforced. Only Linux uses it.
+func SetCgroup(path string) {
+	cgroupMu.Lock()
+	defer cgroupMu.Unlock()
+
+	cgroupPath = path
+}
+
+// set reports whether any limit is set
+func (l Limits) set() bool {
+	return l != Limits{}
+}
+
+// memoryBytes returns the memory limit in bytes
+func (l Limits) memoryBytes() int64 {
+	return l.MemoryMB << 20
+}
+
+// parseLimits reads the limits block of a plugin config
+func parseLimits(config map[string]interface{}) (Limits, error) {
+	raw, ok := config["limits"].(map[string]interface{})
+	if !ok {
+		return Limits{}, nil
+	}
+
+	var l Limits
+	if v, ok := raw["cpu"]; ok {
+		cpu, ok := v.(float64)
+		if !ok || cpu < 0 {
+			return Limits{}, fmt.Errorf("plugin limits.cpu must be a non-negative number")
+		}
+		l.CPU = cpu
+	}
+	for _, field := range []struct {
+		name string
+		dst  *int64
+	}{{"memory_mb", &l.MemoryMB}, {"max_open_files", &l.MaxOpenFiles}, {"max_processes", &l.MaxProcesses}} {
+		v, ok := raw[field.name]
+		if !ok {
+			continue
+		}
+		n, ok := v.(float64)
+		if !ok || n < 0 || n != float64(int64(n)) {
+			return Limits{}, fmt.Errorf("plugin limits.%s must be a non-negative integer", field.name)
+		}
+		*field.dst = int64(n)
+	}
+	return l, nil
+}
+
+// usage is what a plugin process uses of the host; fields a platform cannot
+// measure are negative
+type usage struct {
+	CPUSeconds  float64
+	MemoryBytes int64
+	OpenFiles   int64
+	Processes   int64
+	// Killed counts the kills of the process for exceeding its memory limit
+	Killed int64
+}
+
+// sandbox confines a started plugin process to its limits and measures its
+// usage
+type sandbox interface {
+	// usage measures the resources the process uses
+	usage() (usage, error)
+	// close releases the sandbox once the process has exited
+	close()
+}
+
+// limitNames lists the limits of l that are set, as named in the config
+func limitNames(l Limits) []string {
+	var names []string
+	if l.CPU > 0 {
+		names = append(names, "cpu")
+	}
+	if l.MemoryMB > 0 {
+		names = append(names, "memory_mb")
+	}
+	if l.MaxOpenFiles > 0 {
+		names = append(names, "max_open_files")
+	}
+	if l.MaxProcesses > 0 {
+		names = append(names, "max_processes")
+	}
+	return names
+}
+
+// sample reports the resource usage of the plugin when it is instrumented,
+// counting kills for exceeding its limits. Callers hold p.mu.
+func (p *process) sample() {
+	if p.metrics == nil || p.sandbox == nil {
+		return
+	}
+	u, err := p.sandbox.usage()
+	if err != nil {
+		return
+	}
+
+	for _, g := range []struct {
+		name  string
+		value float64
+	}{
+		{"process_cpu_seconds", u.CPUSeconds},
+		{"process_memory_bytes", float64(u.MemoryBytes)},
+		{"process_open_files", float64(u.OpenFiles)},
+		{"process_count", float64(u.Processes)},
+	} {
+		if g.value >= 0 {
+			p.metrics.Gauge(g.name, g.value)
+		}
+	}
+	if u.Killed > p.killed {
+		p.metrics.Count("limit_kills", float64(u.Killed-p.killed))
+		p.killed = u.Killed
+	}
+}
+
+// startConfined starts the plugin confined to its limits, logging the
+// limits that cannot be enforced. Plugins without limits are only
+// measured. Callers hold p.mu.
+func (p *process) startConfined(cmd *exec.Cmd) error {
+	sb, unenforced, err := startConfined(cmd, p.command, p.limits)
+	if err != nil {
+		return err
+	}
+	p.sandbox, p.unenforced = sb, unenforced
+	p.killed = 0
+	if len(p.unenforced) > 0 {
+		log.Printf("[Plugin] %s runs without some of its limits: %s", p.command, strings.Join(p.unenforced, "; "))
+	}
+	return nil
+}
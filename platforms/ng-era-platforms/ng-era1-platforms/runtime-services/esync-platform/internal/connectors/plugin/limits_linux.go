@@ -0,0 +1,324 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+
+//go:build linux
+
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: connector-plugin-limits
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Plugin Resource Limits on Linux
+ */
+
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"unsafe"
+)
+
+// cgroupRoot is where the cgroup v2 hierarchy is mounted
+const cgroupRoot = "/sys/fs/cgroup"
+
+// cgroupControllers are the controllers plugin groups bound
+const cgroupControllers = "+cpu +memory +pids"
+
+// cgroupPeriod is the cpu.max period in microseconds
+const cgroupPeriod = 100000
+
+// userHZ is the clock tick of the CPU times in /proc
+const userHZ = 100
+
+var (
+	// cgroupParent and cgroupErr are the outcome of preparing the
+	// delegated cgroup cgroupPrepared; cgroupMu guards them
+	cgroupPrepared *string
+	cgroupParent   string
+	cgroupErr      error
+	cgroupSeq      atomic.Uint64
+)
+
+// pluginCgroups returns the cgroup plugin groups are created in, preparing
+// the configured one on first use
+func pluginCgroups() (string, error) {
+	cgroupMu.Lock()
+	defer cgroupMu.Unlock()
+
+	if cgroupPrepared == nil || *cgroupPrepared != cgroupPath {
+		path := cgroupPath
+		cgroupPrepared = &path
+		cgroupParent, cgroupErr = setupCgroups(path)
+	}
+	return cgroupParent, cgroupErr
+}
+
+// setupCgroups checks the delegated cgroup and enables the cpu, memory and
+// pids controllers for the plugin groups below it. It only ever changes the
+// delegated group; the daemon's own group is left as it is.
+func setupCgroups(path string) (string, error) {
+	if path == "" {
+		return "", errors.New("no delegated cgroup is configured (plugin_cgroup)")
+	}
+	if _, err := os.Stat(filepath.Join(cgroupRoot, "cgroup.controllers")); err != nil {
+		return "", errors.New("cgroup v2 is not mounted")
+	}
+	dir := filepath.Join(cgroupRoot, filepath.Clean("/"+path))
+	available, err := os.ReadFile(filepath.Join(dir, "cgroup.controllers"))
+	if err != nil {
+		return "", fmt.Errorf("cannot use delegated cgroup %s: %w", dir, err)
+	}
+	var missing []string
+	for _, c := range strings.Fields(cgroupControllers) {
+		if !slices.Contains(strings.Fields(string(available)), strings.TrimPrefix(c, "+")) {
+			missing = append(missing, strings.TrimPrefix(c, "+"))
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("controllers %s are not delegated to cgroup %s", strings.Join(missing, ", "), dir)
+	}
+	procs, err := os.ReadFile(filepath.Join(dir, "cgroup.procs"))
+	if err != nil {
+		return "", fmt.Errorf("cannot use delegated cgroup %s: %w", dir, err)
+	}
+	if len(strings.TrimSpace(string(procs))) > 0 {
+		return "", fmt.Errorf("delegated cgroup %s holds processes; plugins need a group of their own", dir)
+	}
+	if err := writeCgroup(dir, "cgroup.subtree_control", cgroupControllers); err != nil {
+		return "", fmt.Errorf("cannot enable controllers of cgroup %s: %w", dir, err)
+	}
+	return dir, nil
+}
+
+// writeCgroup writes a cgroup interface file
+func writeCgroup(dir, file, value string) error {
+	return os.WriteFile(filepath.Join(dir, file), []byte(value), 0o644)
+}
+
+// linuxSandbox measures a plugin process and, when it has one, its cgroup
+type linuxSandbox struct {
+	pid int
+	dir string
+}
+
+// startConfined starts a plugin process bounded by l. When any limit a
+// cgroup bounds is set, the process starts inside a cgroup of its own, so
+// the limits apply from its first instruction. The open files and, without
+// a cgroup, the memory are bounded by resource limits set right after the
+// start. It returns the limits it could not enforce.
+func startConfined(cmd *exec.Cmd, command string, l Limits) (sandbox, []string, error) {
+	var (
+		unenforced []string
+		dir        string
+		err        error
+	)
+	if l.CPU > 0 || l.MemoryMB > 0 || l.MaxProcesses > 0 {
+		dir, err = createCgroup(command, l)
+	}
+	if dir != "" {
+		fd, openErr := syscall.Open(dir, syscall.O_RDONLY|syscall.O_DIRECTORY|syscall.O_CLOEXEC, 0)
+		if openErr != nil {
+			os.Remove(dir)
+			return nil, nil, fmt.Errorf("failed to open cgroup %s: %w", dir, openErr)
+		}
+		defer syscall.Close(fd)
+		if cmd.SysProcAttr == nil {
+			cmd.SysProcAttr = &syscall.SysProcAttr{}
+		}
+		cmd.SysProcAttr.UseCgroupFD = true
+		cmd.SysProcAttr.CgroupFD = fd
+	}
+	if startErr := cmd.Start(); startErr != nil {
+		if dir != "" {
+			os.Remove(dir)
+			return nil, nil, fmt.Errorf("failed to start plugin %s in cgroup %s: %w", command, dir, startErr)
+		}
+		return nil, nil, fmt.Errorf("failed to start plugin %s: %w", command, startErr)
+	}
+
+	sb := &linuxSandbox{pid: cmd.Process.Pid, dir: dir}
+	if l.MaxOpenFiles > 0 {
+		if err := prlimit(sb.pid, syscall.RLIMIT_NOFILE, uint64(l.MaxOpenFiles)); err != nil {
+			unenforced = append(unenforced, "max_open_files: "+err.Error())
+		}
+	}
+	if err == nil {
+		return sb, unenforced, nil
+	}
+	if l.MemoryMB > 0 {
+		// Without a cgroup RLIMIT_DATA bounds the heap the process maps
+		if err := prlimit(sb.pid, syscall.RLIMIT_DATA, uint64(l.memoryBytes())); err != nil {
+			unenforced = append(unenforced, "memory_mb: "+err.Error())
+		}
+	}
+	if l.CPU > 0 {
+		unenforced = append(unenforced, "cpu: "+err.Error())
+	}
+	if l.MaxProcesses > 0 {
+		unenforced = append(unenforced, "max_processes: "+err.Error())
+	}
+	return sb, unenforced, nil
+}
+
+// createCgroup creates the cgroup a plugin process starts in, bounded by l
+func createCgroup(command string, l Limits) (string, error) {
+	parent, err := pluginCgroups()
+	if err != nil {
+		return "", err
+	}
+	name := strings.Map(func(r rune) rune {
+		if r == '-' || r == '_' || r == '.' || '0' <= r && r <= '9' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' {
+			return r
+		}
+		return '_'
+	}, filepath.Base(command))
+	dir := filepath.Join(parent, fmt.Sprintf("%s-%d-%d", name, os.Getpid(), cgroupSeq.Add(1)))
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create cgroup %s: %w", dir, err)
+	}
+
+	var settings [][2]string
+	if l.MemoryMB > 0 {
+		settings = append(settings, [2]string{"memory.max", strconv.FormatInt(l.memoryBytes(), 10)})
+	}
+	if l.CPU > 0 {
+		quota := max(int64(l.CPU*cgroupPeriod), 1000)
+		settings = append(settings, [2]string{"cpu.max", fmt.Sprintf("%d %d", quota, cgroupPeriod)})
+	}
+	if l.MaxProcesses > 0 {
+		settings = append(settings, [2]string{"pids.max", strconv.FormatInt(l.MaxProcesses, 10)})
+	}
+	for _, s := range settings {
+		if err := writeCgroup(dir, s[0], s[1]); err != nil {
+			os.Remove(dir)
+			return "", fmt.Errorf("failed to set %s of cgroup %s: %w", s[0], dir, err)
+		}
+	}
+	if l.MemoryMB > 0 {
+		// Swapping out would let the process exceed its memory limit;
+		// kernels without swap accounting lack the file
+		writeCgroup(dir, "memory.swap.max", "0")
+	}
+	return dir, nil
+}
+
+// rlimit64 is the struct rlimit64 of prlimit64
+type rlimit64 struct {
+	cur, max uint64
+}
+
+// prlimit sets the soft and hard limit of a resource of another process
+func prlimit(pid, resource int, limit uint64) error {
+	lim := rlimit64{cur: limit, max: limit}
+	_, _, errno := syscall.RawSyscall6(syscall.SYS_PRLIMIT64, uintptr(pid), uintptr(resource), uintptr(unsafe.Pointer(&lim)), 0, 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// usage implements sandbox, reading the cgroup of the process when it has
+// one and /proc otherwise
+func (s *linuxSandbox) usage() (usage, error) {
+	u := usage{CPUSeconds: -1, MemoryBytes: -1, OpenFiles: -1, Processes: -1}
+	if fds, err := os.ReadDir(fmt.Sprintf("/proc/%d/fd", s.pid)); err == nil {
+		u.OpenFiles = int64(len(fds))
+	}
+
+	if s.dir != "" {
+		if usec, ok := cgroupField(s.dir, "cpu.stat", "usage_usec"); ok {
+			u.CPUSeconds = float64(usec) / 1e6
+		}
+		if n, ok := cgroupValue(s.dir, "memory.current"); ok {
+			u.MemoryBytes = n
+		}
+		if n, ok := cgroupValue(s.dir, "pids.current"); ok {
+			u.Processes = n
+		}
+		if n, ok := cgroupField(s.dir, "memory.events", "oom_kill"); ok {
+			u.Killed = n
+		}
+		return u, nil
+	}
+
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", s.pid))
+	if err != nil {
+		return u, err
+	}
+	// Fields follow the parenthesized command, which may contain spaces
+	if i := strings.LastIndexByte(string(stat), ')'); i >= 0 {
+		fields := strings.Fields(string(stat[i+1:]))
+		if len(fields) > 17 {
+			utime, _ := strconv.ParseInt(fields[11], 10, 64)
+			stime, _ := strconv.ParseInt(fields[12], 10, 64)
+			u.CPUSeconds = float64(utime+stime) / userHZ
+			u.Processes, _ = strconv.ParseInt(fields[17], 10, 64)
+		}
+	}
+	if status, err := os.ReadFile(fmt.Sprintf("/proc/%d/status", s.pid)); err == nil {
+		for _, line := range strings.Split(string(status), "\n") {
+			if rss, ok := strings.CutPrefix(line, "VmRSS:"); ok {
+				kb, _ := strconv.ParseInt(strings.TrimSuffix(strings.TrimSpace(rss), " kB"), 10, 64)
+				u.MemoryBytes = kb << 10
+			}
+		}
+	}
+	return u, nil
+}
+
+// cgroupValue reads a single-value cgroup file
+func cgroupValue(dir, file string) (int64, bool) {
+	data, err := os.ReadFile(filepath.Join(dir, file))
+	if err != nil {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	return n, err == nil
+}
+
+// cgroupField reads a key of a flat-keyed cgroup file
+func cgroupField(dir, file, key string) (int64, bool) {
+	data, err := os.ReadFile(filepath.Join(dir, file))
+	if err != nil {
+		return 0, false
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if value, ok := strings.CutPrefix(line, key+" "); ok {
+			n, err := strconv.ParseInt(value, 10, 64)
+			return n, err == nil
+		}
+	}
+	return 0, false
+}
+
+// close implements sandbox, killing what the plugin left in its cgroup and
+// removing it
+func (s *linuxSandbox) close() {
+	if s.dir == "" {
+		return
+	}
+	if err := os.Remove(s.dir); err == nil || errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	// Children of the plugin outlived it; cgroup.kill needs Linux 5.14
+	writeCgroup(s.dir, "cgroup.kill", "1")
+	if err := os.Remove(s.dir); err != nil {
+		log.Printf("[Plugin] Failed to remove cgroup %s: %v", s.dir, err)
+	}
+}
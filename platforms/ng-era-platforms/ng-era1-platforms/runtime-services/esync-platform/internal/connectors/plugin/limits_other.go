@@ -0,0 +1,38 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+
+//go:build !linux && !windows
+
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: connector-plugin-limits
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Plugin Resource Limits on Other Platforms
+ */
+
+package plugin
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// startConfined starts a plugin process; its limits cannot be enforced on
+// this platform
+func startConfined(cmd *exec.Cmd, command string, l Limits) (sandbox, []string, error) {
+	if err := cmd.Start(); err != nil {
+		return nil, nil, fmt.Errorf("failed to start plugin %s: %w", command, err)
+	}
+	if l.set() {
+		return nil, []string{"not supported on " + runtime.GOOS}, nil
+	}
+	return nil, nil, nil
+}
@@ -0,0 +1,227 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+
+//go:build windows
+
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: connector-plugin-limits
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Plugin Resource Limits on Windows
+ */
+
+package plugin
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32                      = syscall.NewLazyDLL("kernel32.dll")
+	procCreateJobObject           = kernel32.NewProc("CreateJobObjectW")
+	procSetInformationJobObject   = kernel32.NewProc("SetInformationJobObject")
+	procQueryInformationJobObject = kernel32.NewProc("QueryInformationJobObject")
+	procAssignProcessToJobObject  = kernel32.NewProc("AssignProcessToJobObject")
+	procGetProcessMemoryInfo      = kernel32.NewProc("K32GetProcessMemoryInfo")
+	procGetProcessHandleCount     = kernel32.NewProc("GetProcessHandleCount")
+)
+
+// Job object information classes, limit flags and access rights
+const (
+	jobObjectBasicAccountingInformation = 1
+	jobObjectExtendedLimitInformation   = 9
+	jobObjectCPURateControlInformation  = 15
+
+	jobObjectLimitActiveProcess = 0x8
+	jobObjectLimitJobMemory     = 0x200
+	jobObjectLimitKillOnClose   = 0x2000
+
+	jobObjectCPURateControlEnable  = 0x1
+	jobObjectCPURateControlHardCap = 0x4
+
+	processSetQuota         = 0x0100
+	processTerminate        = 0x0001
+	processQueryInformation = 0x0400
+	processVMRead           = 0x0010
+)
+
+// jobBasicLimits is JOBOBJECT_BASIC_LIMIT_INFORMATION
+type jobBasicLimits struct {
+	PerProcessUserTimeLimit int64
+	PerJobUserTimeLimit     int64
+	LimitFlags              uint32
+	MinimumWorkingSetSize   uintptr
+	MaximumWorkingSetSize   uintptr
+	ActiveProcessLimit      uint32
+	Affinity                uintptr
+	PriorityClass           uint32
+	SchedulingClass         uint32
+}
+
+// jobExtendedLimits is JOBOBJECT_EXTENDED_LIMIT_INFORMATION
+type jobExtendedLimits struct {
+	BasicLimitInformation jobBasicLimits
+	IoInfo                [6]uint64
+	ProcessMemoryLimit    uintptr
+	JobMemoryLimit        uintptr
+	PeakProcessMemoryUsed uintptr
+	PeakJobMemoryUsed     uintptr
+}
+
+// jobCPURate is JOBOBJECT_CPU_RATE_CONTROL_INFORMATION
+type jobCPURate struct {
+	ControlFlags uint32
+	CPURate      uint32
+}
+
+// jobAccounting is JOBOBJECT_BASIC_ACCOUNTING_INFORMATION
+type jobAccounting struct {
+	TotalUserTime             int64
+	TotalKernelTime           int64
+	ThisPeriodTotalUserTime   int64
+	ThisPeriodTotalKernelTime int64
+	TotalPageFaultCount       uint32
+	TotalProcesses            uint32
+	ActiveProcesses           uint32
+	TotalTerminatedProcesses  uint32
+}
+
+// processMemoryCounters is PROCESS_MEMORY_COUNTERS
+type processMemoryCounters struct {
+	CB                         uint32
+	PageFaultCount             uint32
+	PeakWorkingSetSize         uintptr
+	WorkingSetSize             uintptr
+	QuotaPeakPagedPoolUsage    uintptr
+	QuotaPagedPoolUsage        uintptr
+	QuotaPeakNonPagedPoolUsage uintptr
+	QuotaNonPagedPoolUsage     uintptr
+	PagefileUsage              uintptr
+	PeakPagefileUsage          uintptr
+}
+
+// windowsSandbox holds the job object of a plugin process
+type windowsSandbox struct {
+	process syscall.Handle
+	job     syscall.Handle
+}
+
+// startConfined starts a plugin process and assigns it to a job object
+// bounded by l, returning the limits it could not enforce. Closing the job
+// kills what is left of the plugin.
+func startConfined(cmd *exec.Cmd, command string, l Limits) (sandbox, []string, error) {
+	if err := cmd.Start(); err != nil {
+		return nil, nil, fmt.Errorf("failed to start plugin %s: %w", command, err)
+	}
+	sb, unenforced := confine(cmd, l)
+	return sb, unenforced, nil
+}
+
+// confine assigns a started plugin process to a job object bounded by l
+func confine(cmd *exec.Cmd, l Limits) (sandbox, []string) {
+	var unenforced []string
+	if l.MaxOpenFiles > 0 {
+		unenforced = append(unenforced, "max_open_files: Windows cannot bound open handles")
+	}
+	// failed reports a failure to bound the limits in names that are set
+	failed := func(err error, names ...string) {
+		var set []string
+		for _, name := range limitNames(l) {
+			if name != "max_open_files" && (len(names) == 0 || slices.Contains(names, name)) {
+				set = append(set, name)
+			}
+		}
+		if len(set) > 0 {
+			unenforced = append(unenforced, fmt.Sprintf("%s: %v", strings.Join(set, ", "), err))
+		}
+	}
+
+	process, err := syscall.OpenProcess(processSetQuota|processTerminate|processQueryInformation|processVMRead, false, uint32(cmd.Process.Pid))
+	if err != nil {
+		failed(fmt.Errorf("failed to open process: %w", err))
+		return nil, unenforced
+	}
+	sb := &windowsSandbox{process: process}
+	job, _, err := procCreateJobObject.Call(0, 0)
+	if job == 0 {
+		failed(fmt.Errorf("failed to create job object: %w", err))
+		return sb, unenforced
+	}
+	sb.job = syscall.Handle(job)
+
+	limits := jobExtendedLimits{}
+	limits.BasicLimitInformation.LimitFlags = jobObjectLimitKillOnClose
+	if l.MemoryMB > 0 {
+		limits.BasicLimitInformation.LimitFlags |= jobObjectLimitJobMemory
+		limits.JobMemoryLimit = uintptr(l.memoryBytes())
+	}
+	if l.MaxProcesses > 0 {
+		limits.BasicLimitInformation.LimitFlags |= jobObjectLimitActiveProcess
+		limits.BasicLimitInformation.ActiveProcessLimit = uint32(l.MaxProcesses)
+	}
+	if ok, _, err := procSetInformationJobObject.Call(job, jobObjectExtendedLimitInformation, uintptr(unsafe.Pointer(&limits)), unsafe.Sizeof(limits)); ok == 0 {
+		failed(err, "memory_mb", "max_processes")
+	}
+	if l.CPU > 0 {
+		// The rate is in hundredths of a percent of all processors
+		rate := jobCPURate{
+			ControlFlags: jobObjectCPURateControlEnable | jobObjectCPURateControlHardCap,
+			CPURate:      uint32(min(max(l.CPU/float64(runtime.NumCPU())*10000, 1), 10000)),
+		}
+		if ok, _, err := procSetInformationJobObject.Call(job, jobObjectCPURateControlInformation, uintptr(unsafe.Pointer(&rate)), unsafe.Sizeof(rate)); ok == 0 {
+			failed(err, "cpu")
+		}
+	}
+	if ok, _, err := procAssignProcessToJobObject.Call(job, uintptr(process)); ok == 0 {
+		failed(fmt.Errorf("failed to assign the process to its job object: %w", err))
+		return sb, unenforced
+	}
+	return sb, unenforced
+}
+
+// usage implements sandbox
+func (s *windowsSandbox) usage() (usage, error) {
+	u := usage{CPUSeconds: -1, MemoryBytes: -1, OpenFiles: -1, Processes: -1}
+	var counters processMemoryCounters
+	counters.CB = uint32(unsafe.Sizeof(counters))
+	if ok, _, _ := procGetProcessMemoryInfo.Call(uintptr(s.process), uintptr(unsafe.Pointer(&counters)), unsafe.Sizeof(counters)); ok != 0 {
+		u.MemoryBytes = int64(counters.WorkingSetSize)
+	}
+	var handles uint32
+	if ok, _, _ := procGetProcessHandleCount.Call(uintptr(s.process), uintptr(unsafe.Pointer(&handles))); ok != 0 {
+		u.OpenFiles = int64(handles)
+	}
+	if s.job == 0 {
+		return u, nil
+	}
+
+	var accounting jobAccounting
+	if ok, _, err := procQueryInformationJobObject.Call(uintptr(s.job), jobObjectBasicAccountingInformation, uintptr(unsafe.Pointer(&accounting)), unsafe.Sizeof(accounting), 0); ok == 0 {
+		return u, err
+	}
+	// Job times count 100ns intervals
+	u.CPUSeconds = float64(accounting.TotalUserTime+accounting.TotalKernelTime) / 1e7
+	u.Processes = int64(accounting.ActiveProcesses)
+	return u, nil
+}
+
+// close implements sandbox
+func (s *windowsSandbox) close() {
+	if s.job != 0 {
+		syscall.CloseHandle(s.job)
+	}
+	syscall.CloseHandle(s.process)
+}
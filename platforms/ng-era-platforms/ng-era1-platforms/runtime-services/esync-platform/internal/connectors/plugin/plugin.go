@@ -0,0 +1,772 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: connector-plugin
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Out-of-Process Connector Plugins
+ */
+
+// Package plugin runs connectors as separate processes speaking
+// line-delimited JSON over stdin and stdout. Pipelines use it as:
+//
+//	source:
+//	  type: plugin
+//	  config:
+//	    command: /opt/esync/plugins/salesforce
+//	    args: [--sandbox]
+//	    config: {org: acme}
+//	    proxy: socks5://egress.internal:1080
+//	    limits: {cpu: 0.5, memory_mb: 512, max_open_files: 256}
+//
+// Limits bounds the resources of the plugin process; see Limits. Plugin
+// processes receive the daemon's egress policy, with the
+// connector's proxy taking precedence, as HTTP_PROXY, HTTPS_PROXY,
+// ALL_PROXY, NO_PROXY and ESYNC_EGRESS_ALLOW environment variables, and
+// ESYNC_FIPS=1 when the daemon runs in FIPS mode.
+package plugin
+
+import (
+	"bufio"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/conflict"
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/egress"
+	"github.com/machine-native-ops/esync-platform/internal/fips"
+)
+
+// handshakeTimeout bounds plugin startup
+const handshakeTimeout = 10 * time.Second
+
+// configSchema documents the plugin block of pipelines
+//
+//go:embed config.schema.json
+var configSchema []byte
+
+func init() {
+	connectors.Register("plugin", connectors.WithSchema(New, configSchema))
+}
+
+// process is a running plugin shared by every connector instance with the
+// same command and config. Calls are serialized; a crashed plugin is
+// restarted on the next call.
+type process struct {
+	command string
+	args    []string
+	proxy   string
+	config  map[string]interface{}
+	limits  Limits
+
+	mu      sync.Mutex
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	stdout  *bufio.Reader
+	nextID  uint64
+	session *Session
+	// metrics receives call latencies, restarts and the stats the plugin
+	// reports with its responses; starts counts successful starts
+	metrics connectors.MetricsReporter
+	starts  int
+	// sandbox confines the running plugin to its limits, unenforced lists
+	// the limits it could not enforce and killed counts the kills for
+	// exceeding them
+	sandbox    sandbox
+	unenforced []string
+	killed     int64
+}
+
+var (
+	processesMu sync.Mutex
+	processes   = make(map[string]*process)
+)
+
+// Connector forwards connector calls to a plugin process
+type Connector struct {
+	proc     *process
+	resolver *conflict.Resolver
+	limiter  connectors.Limiter
+}
+
+// New starts, or reuses, the plugin process described by config and
+// negotiates the protocol version
+func New(config map[string]interface{}) (connectors.Connector, error) {
+	command, _ := config["command"].(string)
+	if command == "" {
+		return nil, fmt.Errorf("plugin command is required")
+	}
+
+	var args []string
+	if raw, ok := config["args"].([]interface{}); ok {
+		for _, a := range raw {
+			args = append(args, fmt.Sprint(a))
+		}
+	}
+	pluginConfig, _ := config["config"].(map[string]interface{})
+	proxy, _ := config["proxy"].(string)
+	limits, err := parseLimits(config)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := processKey(command, args, proxy, limits, pluginConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	processesMu.Lock()
+	proc, exists := processes[key]
+	if !exists {
+		proc = &process{command: command, args: args, proxy: proxy, limits: limits, config: pluginConfig}
+		processes[key] = proc
+	}
+	processesMu.Unlock()
+
+	proc.mu.Lock()
+	defer proc.mu.Unlock()
+	if err := proc.ensureStarted(); err != nil {
+		return nil, err
+	}
+
+	return &Connector{proc: proc, resolver: conflict.NewResolver()}, nil
+}
+
+// processKey identifies the plugin process shared by connectors with the
+// same command, limits and config
+func processKey(command string, args []string, proxy string, limits Limits, config map[string]interface{}) (string, error) {
+	key, err := json.Marshal([]interface{}{command, args, proxy, limits, config})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode plugin config: %w", err)
+	}
+	return string(key), nil
+}
+
+// key returns the current process key of p
+func (p *process) key() (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return processKey(p.command, p.args, p.proxy, p.limits, p.config)
+}
+
+// Session returns the negotiated protocol version and capabilities
+func (c *Connector) Session() *Session {
+	c.proc.mu.Lock()
+	defer c.proc.mu.Unlock()
+
+	return c.proc.session
+}
+
+// has reports whether the running plugin negotiated a capability
+func (c *Connector) has(capability string) bool {
+	s := c.Session()
+	return s != nil && s.Has(capability)
+}
+
+// call forwards a call to the plugin once the call limits of the pipeline
+// and the connection allow it
+func (c *Connector) call(ctx context.Context, method string, params, out interface{}) error {
+	release, err := connectors.Acquire(ctx, c.limiter)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	return c.proc.call(ctx, method, params, out)
+}
+
+// Limit implements connectors.Limited
+func (c *Connector) Limit(limiter connectors.Limiter) {
+	c.limiter = limiter
+}
+
+// ListChanges implements connectors.Connector
+func (c *Connector) ListChanges(ctx context.Context, checkpoint *connectors.Checkpoint) ([]connectors.Record, error) {
+	var records []connectors.Record
+	err := c.call(ctx, "list_changes", map[string]interface{}{"checkpoint": checkpoint}, &records)
+	return records, err
+}
+
+// ApplyChanges implements connectors.Connector. Records with composite keys
+// are sent with their key field values in their data.
+func (c *Connector) ApplyChanges(ctx context.Context, changes []connectors.Record) error {
+	changes, err := connectors.WithKeyData(changes)
+	if err != nil {
+		return err
+	}
+	return c.call(ctx, "apply_changes", map[string]interface{}{"records": changes}, nil)
+}
+
+// Validate implements connectors.Connector. Plugins without the validate
+// capability accept every record.
+func (c *Connector) Validate(ctx context.Context, record connectors.Record) connectors.ValidationResult {
+	if !c.has(CapValidate) {
+		return connectors.ValidationResult{IsValid: true}
+	}
+
+	var result connectors.ValidationResult
+	if err := c.call(ctx, "validate", map[string]interface{}{"record": record}, &result); err != nil {
+		return connectors.ValidationResult{Errors: []string{err.Error()}}
+	}
+	return result
+}
+
+// ResolveConflict implements connectors.Connector. Plugins without the
+// resolve_conflict capability fall back to the built-in resolver.
+func (c *Connector) ResolveConflict(ctx context.Context, existing, incoming connectors.Record) (connectors.Record, error) {
+	if !c.has(CapResolveConflict) {
+		winner, _ := c.resolver.Resolve(existing, incoming)
+		return winner, nil
+	}
+
+	var winner connectors.Record
+	err := c.call(ctx, "resolve_conflict", map[string]interface{}{"existing": existing, "incoming": incoming}, &winner)
+	return winner, err
+}
+
+// GetLatestCheckpoint implements connectors.Connector
+func (c *Connector) GetLatestCheckpoint(ctx context.Context) (*connectors.Checkpoint, error) {
+	var checkpoint *connectors.Checkpoint
+	err := c.call(ctx, "checkpoint", nil, &checkpoint)
+	return checkpoint, err
+}
+
+// Schema implements connectors.SchemaProvider; plugins without the schema
+// capability report no schema
+func (c *Connector) Schema(ctx context.Context) (*connectors.Schema, error) {
+	if !c.has(CapSchema) {
+		return nil, nil
+	}
+
+	var schema *connectors.Schema
+	err := c.call(ctx, "schema", nil, &schema)
+	return schema, err
+}
+
+// EstimateTotal implements connectors.Estimator; plugins without the
+// estimate capability report an unknown total
+func (c *Connector) EstimateTotal(ctx context.Context) (int64, error) {
+	if !c.has(CapEstimate) {
+		return 0, nil
+	}
+
+	var total int64
+	err := c.call(ctx, "estimate", nil, &total)
+	return total, err
+}
+
+// ReadRecords implements connectors.RecordReader; plugins without the
+// read_records capability return connectors.ErrUnsupported
+func (c *Connector) ReadRecords(ctx context.Context, ids []string) ([]connectors.Record, error) {
+	if !c.has(CapReadRecords) {
+		return nil, connectors.ErrUnsupported
+	}
+
+	var records []connectors.Record
+	err := c.call(ctx, "read_records", map[string]interface{}{"ids": ids}, &records)
+	return records, err
+}
+
+// KeyRanges implements connectors.RangeReader; plugins without the
+// read_range capability return connectors.ErrUnsupported
+func (c *Connector) KeyRanges(ctx context.Context, n int) ([]connectors.KeyRange, error) {
+	if !c.has(CapReadRange) {
+		return nil, connectors.ErrUnsupported
+	}
+
+	var ranges []connectors.KeyRange
+	err := c.call(ctx, "key_ranges", map[string]interface{}{"n": n}, &ranges)
+	return ranges, err
+}
+
+// ReadRange implements connectors.RangeReader; plugins without the
+// read_range capability return connectors.ErrUnsupported
+func (c *Connector) ReadRange(ctx context.Context, r connectors.KeyRange) ([]connectors.Record, error) {
+	if !c.has(CapReadRange) {
+		return nil, connectors.ErrUnsupported
+	}
+
+	var records []connectors.Record
+	err := c.call(ctx, "read_range", map[string]interface{}{"range": r}, &records)
+	return records, err
+}
+
+// TableReferences implements connectors.TableReferencer; plugins without
+// the table_references capability return connectors.ErrUnsupported
+func (c *Connector) TableReferences(ctx context.Context) (map[string][]string, error) {
+	if !c.has(CapTableReferences) {
+		return nil, connectors.ErrUnsupported
+	}
+
+	var refs map[string][]string
+	err := c.call(ctx, "table_references", nil, &refs)
+	return refs, err
+}
+
+// ListDDL implements connectors.DDLSource; plugins without the list_ddl
+// capability return connectors.ErrUnsupported
+func (c *Connector) ListDDL(ctx context.Context, checkpoint *connectors.Checkpoint) ([]connectors.DDLEvent, error) {
+	if !c.has(CapListDDL) {
+		return nil, connectors.ErrUnsupported
+	}
+
+	var events []connectors.DDLEvent
+	err := c.call(ctx, "list_ddl", map[string]interface{}{"checkpoint": checkpoint}, &events)
+	return events, err
+}
+
+// ApplyDDL implements connectors.DDLApplier; plugins without the apply_ddl
+// capability return connectors.ErrUnsupported
+func (c *Connector) ApplyDDL(ctx context.Context, event connectors.DDLEvent) error {
+	if !c.has(CapApplyDDL) {
+		return connectors.ErrUnsupported
+	}
+
+	return c.call(ctx, "apply_ddl", map[string]interface{}{"event": event}, nil)
+}
+
+// Bootstrap implements connectors.Bootstrapper; plugins without the
+// bootstrap capability return connectors.ErrUnsupported
+func (c *Connector) Bootstrap(ctx context.Context, req connectors.BootstrapRequest) ([]string, error) {
+	if !c.has(CapBootstrap) {
+		return nil, connectors.ErrUnsupported
+	}
+
+	var created []string
+	err := c.call(ctx, "bootstrap", req, &created)
+	return created, err
+}
+
+// Teardown implements connectors.Teardowner; plugins without the teardown
+// capability return connectors.ErrUnsupported
+func (c *Connector) Teardown(ctx context.Context, req connectors.TeardownRequest) ([]string, error) {
+	if !c.has(CapTeardown) {
+		return nil, connectors.ErrUnsupported
+	}
+
+	var removed []string
+	err := c.call(ctx, "teardown", req, &removed)
+	return removed, err
+}
+
+// ListResources implements connectors.ResourceLister; plugins without the
+// list_resources capability return connectors.ErrUnsupported
+func (c *Connector) ListResources(ctx context.Context) ([]connectors.Resource, error) {
+	if !c.has(CapListResources) {
+		return nil, connectors.ErrUnsupported
+	}
+
+	var resources []connectors.Resource
+	err := c.call(ctx, "list_resources", nil, &resources)
+	return resources, err
+}
+
+// ApplyReturningIDs implements connectors.IDAssigner; plugins without the
+// apply_returning_ids capability return connectors.ErrUnsupported
+func (c *Connector) ApplyReturningIDs(ctx context.Context, records []connectors.Record) ([]string, error) {
+	if !c.has(CapReturningIDs) {
+		return nil, connectors.ErrUnsupported
+	}
+
+	records, err := connectors.WithKeyData(records)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	err = c.call(ctx, "apply_returning_ids", map[string]interface{}{"records": records}, &ids)
+	return ids, err
+}
+
+// KeyDigests implements connectors.KeyDigester; plugins without the
+// key_digests capability return connectors.ErrUnsupported
+func (c *Connector) KeyDigests(ctx context.Context, prefixes []string) ([]connectors.KeyDigest, error) {
+	if !c.has(CapKeyDigests) {
+		return nil, connectors.ErrUnsupported
+	}
+
+	var digests []connectors.KeyDigest
+	err := c.call(ctx, "key_digests", map[string]interface{}{"prefixes": prefixes}, &digests)
+	return digests, err
+}
+
+// HashKeys implements connectors.KeyDigester; plugins without the
+// key_digests capability return connectors.ErrUnsupported
+func (c *Connector) HashKeys(ctx context.Context, prefix string) ([]connectors.Key, error) {
+	if !c.has(CapKeyDigests) {
+		return nil, connectors.ErrUnsupported
+	}
+
+	var keys []connectors.Key
+	err := c.call(ctx, "hash_keys", map[string]interface{}{"prefix": prefix}, &keys)
+	return keys, err
+}
+
+// SourceTime implements connectors.SourceClock; plugins without the
+// source_time capability return connectors.ErrUnsupported
+func (c *Connector) SourceTime(ctx context.Context) (time.Time, error) {
+	if !c.has(CapSourceTime) {
+		return time.Time{}, connectors.ErrUnsupported
+	}
+
+	var t time.Time
+	err := c.call(ctx, "source_time", nil, &t)
+	return t, err
+}
+
+// WriteCanary implements connectors.CanaryWriter; plugins without the
+// write_canary capability return connectors.ErrUnsupported
+func (c *Connector) WriteCanary(ctx context.Context, record connectors.Record) error {
+	if !c.has(CapWriteCanary) {
+		return connectors.ErrUnsupported
+	}
+
+	return c.call(ctx, "write_canary", map[string]interface{}{"record": record}, nil)
+}
+
+// Acknowledge implements connectors.Acknowledger; plugins without the
+// acknowledge capability return connectors.ErrUnsupported
+func (c *Connector) Acknowledge(ctx context.Context, checkpoint *connectors.Checkpoint) error {
+	if !c.has(CapAcknowledge) {
+		return connectors.ErrUnsupported
+	}
+
+	return c.call(ctx, "acknowledge", map[string]interface{}{"checkpoint": checkpoint}, nil)
+}
+
+// Instrument implements connectors.Instrumented. The plugin process reports
+// the latency of each call as <method>_seconds, failed calls as call_errors
+// and restarts, plus the stats the plugin sends with its responses.
+func (c *Connector) Instrument(reporter connectors.MetricsReporter) {
+	c.proc.mu.Lock()
+	defer c.proc.mu.Unlock()
+
+	c.proc.metrics = reporter
+}
+
+// Reconfigure implements connectors.Reconfigurer, handing new credentials to
+// the running plugin. Plugins without the reconfigure capability return
+// connectors.ErrUnsupported and are restarted by the engine instead.
+func (c *Connector) Reconfigure(ctx context.Context, creds connectors.Credentials) error {
+	if !c.has(CapReconfigure) {
+		return connectors.ErrUnsupported
+	}
+
+	pluginConfig, _ := creds.Config["config"].(map[string]interface{})
+	params := map[string]interface{}{"config": pluginConfig, "changed": creds.Changed}
+	if err := c.proc.call(ctx, "reconfigure", params, nil); err != nil {
+		return err
+	}
+
+	oldKey, err := c.proc.key()
+	if err != nil {
+		return err
+	}
+	newKey, err := processKey(c.proc.command, c.proc.args, c.proc.proxy, c.proc.limits, pluginConfig)
+	if err != nil {
+		return err
+	}
+
+	processesMu.Lock()
+	defer processesMu.Unlock()
+	c.proc.mu.Lock()
+	c.proc.config = pluginConfig
+	c.proc.mu.Unlock()
+	if processes[oldKey] == c.proc {
+		delete(processes, oldKey)
+	}
+	processes[newKey] = c.proc
+	return nil
+}
+
+// Close stops the plugin process; a later call restarts it
+func (c *Connector) Close() error {
+	key, err := c.proc.key()
+	if err != nil {
+		return err
+	}
+
+	processesMu.Lock()
+	if processes[key] == c.proc {
+		delete(processes, key)
+	}
+	processesMu.Unlock()
+
+	c.proc.mu.Lock()
+	defer c.proc.mu.Unlock()
+	c.proc.stop()
+	return nil
+}
+
+// SupportsPatch implements connectors.PatchWriter
+func (c *Connector) SupportsPatch() bool {
+	return c.has(CapPatch)
+}
+
+// Preflight implements connectors.Preflighter, reporting the negotiated
+// protocol along with the plugin's own checks
+func (c *Connector) Preflight(ctx context.Context, role string) []connectors.CheckResult {
+	s := c.Session()
+	if s == nil {
+		return []connectors.CheckResult{{
+			Name:    role + "_plugin_protocol",
+			Status:  connectors.CheckFailed,
+			Message: "plugin is not running",
+			Remedy:  "check the plugin logs; it is restarted on the next call",
+		}}
+	}
+	checks := []connectors.CheckResult{{
+		Name:    role + "_plugin_protocol",
+		Status:  connectors.CheckPassed,
+		Message: fmt.Sprintf("protocol v%d, capabilities %v", s.ProtocolVersion, s.Capabilities),
+	}}
+	if len(s.Ignored) > 0 {
+		checks[0].Message += fmt.Sprintf("; ignoring unknown capabilities %v", s.Ignored)
+	}
+	if limits := c.limitsCheck(role); limits != nil {
+		checks = append(checks, *limits)
+	}
+
+	if !s.Has(CapPreflight) {
+		return append(checks, connectors.CheckResult{
+			Name:    role + "_permissions",
+			Status:  connectors.CheckSkipped,
+			Message: "plugin does not implement pre-flight checks",
+		})
+	}
+
+	var results []connectors.CheckResult
+	if err := c.call(ctx, "preflight", map[string]interface{}{"role": role}, &results); err != nil {
+		return append(checks, connectors.CheckResult{
+			Name:    role + "_permissions",
+			Status:  connectors.CheckFailed,
+			Message: err.Error(),
+			Remedy:  "check the plugin logs",
+		})
+	}
+	return append(checks, results...)
+}
+
+// limitsCheck reports whether the limits of the plugin are enforced; nil
+// for plugins without limits
+func (c *Connector) limitsCheck(role string) *connectors.CheckResult {
+	c.proc.mu.Lock()
+	defer c.proc.mu.Unlock()
+
+	if !c.proc.limits.set() {
+		return nil
+	}
+	check := &connectors.CheckResult{
+		Name:    role + "_plugin_limits",
+		Status:  connectors.CheckPassed,
+		Message: "enforcing " + strings.Join(limitNames(c.proc.limits), ", "),
+	}
+	if len(c.proc.unenforced) > 0 {
+		check.Status = connectors.CheckFailed
+		check.Message = "not enforced: " + strings.Join(c.proc.unenforced, "; ")
+		check.Remedy = "run the daemon in a delegated cgroup v2 group on Linux, or drop the limits the platform cannot enforce"
+	}
+	return check
+}
+
+// call sends one request and waits for its response. Cancelling ctx kills
+// the plugin since a blocked read cannot be interrupted otherwise.
+func (p *process) call(ctx context.Context, method string, params, out interface{}) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.ensureStarted(); err != nil {
+		return err
+	}
+	return p.roundTrip(ctx, method, params, out)
+}
+
+// ensureStarted starts the plugin and performs the handshake. Callers hold
+// p.mu.
+func (p *process) ensureStarted() error {
+	if p.cmd != nil {
+		return nil
+	}
+
+	cmd := exec.Command(p.command, p.args...)
+	cmd.Env = append(os.Environ(), egress.Default().WithProxy(p.proxy).Env()...)
+	if fips.Enabled() {
+		cmd.Env = append(cmd.Env, "ESYNC_FIPS=1")
+	}
+	cmd.Stderr = log.Writer()
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("failed to open plugin stdin: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to open plugin stdout: %w", err)
+	}
+	if err := p.startConfined(cmd); err != nil {
+		return err
+	}
+	p.cmd, p.stdin, p.stdout = cmd, stdin, bufio.NewReader(stdout)
+
+	ctx, cancel := context.WithTimeout(context.Background(), handshakeTimeout)
+	defer cancel()
+
+	var result handshakeResult
+	params := handshakeParams{
+		ProtocolVersions: supportedVersions(),
+		Capabilities:     daemonCapabilities(),
+		Config:           p.config,
+	}
+	if err := p.roundTrip(ctx, "handshake", params, &result); err != nil {
+		p.stop()
+		return fmt.Errorf("plugin %s handshake failed: %w", p.command, err)
+	}
+
+	session, err := negotiate(result)
+	if err != nil {
+		p.stop()
+		return fmt.Errorf("plugin %s: %w", p.command, err)
+	}
+	p.session = session
+	p.starts++
+	if p.starts > 1 && p.metrics != nil {
+		p.metrics.Count("restarts", 1)
+	}
+
+	log.Printf("[Plugin] Started %s (protocol v%d, capabilities %v)", p.command, session.ProtocolVersion, session.Capabilities)
+	if len(session.Ignored) > 0 {
+		log.Printf("[Plugin] %s offers capabilities unknown to this daemon, ignoring: %v", p.command, session.Ignored)
+	}
+	return nil
+}
+
+// roundTrip writes a request and reads the matching response. Callers hold
+// p.mu.
+func (p *process) roundTrip(ctx context.Context, method string, params, out interface{}) error {
+	p.nextID++
+	line, err := json.Marshal(request{ID: p.nextID, Method: method, Params: params})
+	if err != nil {
+		return fmt.Errorf("failed to encode %s request: %w", method, err)
+	}
+
+	type reply struct {
+		data []byte
+		err  error
+	}
+	stdin, stdout := p.stdin, p.stdout
+	done := make(chan reply, 1)
+	go func() {
+		if _, err := stdin.Write(append(line, '\n')); err != nil {
+			done <- reply{err: err}
+			return
+		}
+		data, err := stdout.ReadBytes('\n')
+		done <- reply{data: data, err: err}
+	}()
+
+	start := time.Now()
+	var r reply
+	select {
+	case r = <-done:
+	case <-ctx.Done():
+		p.stop()
+		<-done
+		p.count("call_errors")
+		return ctx.Err()
+	}
+	if p.metrics != nil {
+		p.metrics.Observe(method+"_seconds", time.Since(start).Seconds())
+	}
+	p.sample()
+	if r.err != nil {
+		p.stop()
+		p.count("call_errors")
+		return fmt.Errorf("plugin %s: %w", method, r.err)
+	}
+
+	var resp response
+	if err := json.Unmarshal(r.data, &resp); err != nil {
+		p.stop()
+		p.count("call_errors")
+		return fmt.Errorf("plugin %s: invalid response: %w", method, err)
+	}
+	if resp.ID != p.nextID {
+		p.stop()
+		p.count("call_errors")
+		return fmt.Errorf("plugin %s: response id %d does not match request %d", method, resp.ID, p.nextID)
+	}
+	p.report(resp.Metrics)
+	if resp.Error != "" {
+		p.count("call_errors")
+		if resp.Rejected {
+			return fmt.Errorf("plugin %s: %s: %w", method, resp.Error, connectors.ErrRejected)
+		}
+		return fmt.Errorf("plugin %s: %s", method, resp.Error)
+	}
+	if out != nil && len(resp.Result) > 0 {
+		if err := json.Unmarshal(resp.Result, out); err != nil {
+			return fmt.Errorf("plugin %s: failed to decode result: %w", method, err)
+		}
+	}
+	return nil
+}
+
+// count increments a counter of the plugin when it is instrumented.
+// Callers hold p.mu.
+func (p *process) count(name string) {
+	if p.metrics != nil {
+		p.metrics.Count(name, 1)
+	}
+}
+
+// report forwards the stats a plugin sent with a response. Callers hold
+// p.mu.
+func (p *process) report(metrics []metric) {
+	if p.metrics == nil {
+		return
+	}
+	for _, m := range metrics {
+		switch m.Kind {
+		case "counter":
+			p.metrics.Count(m.Name, m.Value)
+		case "gauge":
+			p.metrics.Gauge(m.Name, m.Value)
+		case "histogram":
+			p.metrics.Observe(m.Name, m.Value)
+		default:
+			log.Printf("[Plugin] %s reported metric %s of unknown kind %q", p.command, m.Name, m.Kind)
+		}
+	}
+}
+
+// stop kills the plugin so the next call restarts it. Callers hold p.mu.
+func (p *process) stop() {
+	if p.cmd == nil {
+		return
+	}
+
+	p.stdin.Close()
+	if p.cmd.Process != nil {
+		p.cmd.Process.Kill()
+	}
+	p.cmd.Wait()
+	if p.sandbox != nil {
+		p.sandbox.close()
+	}
+	p.cmd, p.stdin, p.stdout, p.session, p.sandbox = nil, nil, nil, nil, nil
+}
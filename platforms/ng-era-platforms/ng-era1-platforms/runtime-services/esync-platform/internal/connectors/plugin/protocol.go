@@ -0,0 +1,190 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: connector-plugin-protocol
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Connector Plugin Protocol and Version Negotiation
+ */
+
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Protocol versions spoken by the daemon. Version 1 plugins implement the
+// base connector methods and report no capabilities; version 2 adds the
+// capability handshake and the optional methods.
+const (
+	MinProtocolVersion = 1
+	MaxProtocolVersion = 2
+)
+
+// Capabilities a plugin may advertise
+const (
+	CapListChanges     = "list_changes"
+	CapApplyChanges    = "apply_changes"
+	CapCheckpoint      = "checkpoint"
+	CapValidate        = "validate"
+	CapResolveConflict = "resolve_conflict"
+	CapSchema          = "schema"
+	CapEstimate        = "estimate"
+	CapPreflight       = "preflight"
+	CapReadRecords     = "read_records"
+	CapReadRange       = "read_range"
+	// CapPatch means apply_changes merges patch records natively
+	CapPatch           = "patch"
+	CapTableReferences = "table_references"
+	CapListDDL         = "list_ddl"
+	CapApplyDDL        = "apply_ddl"
+	CapBootstrap       = "bootstrap"
+	CapReconfigure     = "reconfigure"
+	CapWriteCanary     = "write_canary"
+	CapAcknowledge     = "acknowledge"
+	CapTeardown        = "teardown"
+	CapListResources   = "list_resources"
+	CapReturningIDs    = "apply_returning_ids"
+	// CapKeyDigests covers the key_digests and hash_keys methods
+	CapKeyDigests = "key_digests"
+	CapSourceTime = "source_time"
+)
+
+// requiredCapabilities must be offered by every plugin
+var requiredCapabilities = []string{CapListChanges, CapApplyChanges, CapCheckpoint}
+
+// knownCapabilities are the capabilities this daemon can use
+var knownCapabilities = map[string]bool{
+	CapListChanges: true, CapApplyChanges: true, CapCheckpoint: true,
+	CapValidate: true, CapResolveConflict: true, CapSchema: true,
+	CapEstimate: true, CapPreflight: true, CapReadRecords: true, CapReadRange: true, CapPatch: true,
+	CapTableReferences: true, CapListDDL: true, CapApplyDDL: true,
+	CapBootstrap: true, CapReconfigure: true, CapWriteCanary: true,
+	CapAcknowledge: true, CapTeardown: true, CapListResources: true,
+	CapKeyDigests: true, CapReturningIDs: true, CapSourceTime: true,
+}
+
+// request is one line sent to the plugin on stdin
+type request struct {
+	ID     uint64      `json:"id"`
+	Method string      `json:"method"`
+	Params interface{} `json:"params,omitempty"`
+}
+
+// response is one line read from the plugin stdout
+type response struct {
+	ID     uint64          `json:"id"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+	// Rejected marks an error of a call that wrote nothing and cannot
+	// succeed when retried unchanged
+	Rejected bool `json:"rejected,omitempty"`
+	// Metrics are internal stats of the plugin, such as HTTP retries or
+	// pool usage, exported as connector metrics
+	Metrics []metric `json:"metrics,omitempty"`
+}
+
+// metric is a stat reported by a plugin; Kind is counter, gauge or
+// histogram
+type metric struct {
+	Kind  string  `json:"kind"`
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+}
+
+// handshakeParams opens a session with the plugin
+type handshakeParams struct {
+	ProtocolVersions []int                  `json:"protocol_versions"`
+	Capabilities     []string               `json:"capabilities"`
+	Config           map[string]interface{} `json:"config"`
+}
+
+// handshakeResult is the plugin's answer to the handshake
+type handshakeResult struct {
+	ProtocolVersion int      `json:"protocol_version"`
+	Capabilities    []string `json:"capabilities,omitempty"`
+	Name            string   `json:"name,omitempty"`
+	Version         string   `json:"version,omitempty"`
+}
+
+// Session describes what was negotiated with a plugin
+type Session struct {
+	ProtocolVersion int      `json:"protocol_version"`
+	Capabilities    []string `json:"capabilities"`
+	// Ignored lists capabilities the plugin offers that this daemon does not
+	// know; they come from newer plugins and are skipped
+	Ignored []string `json:"ignored,omitempty"`
+	Name    string   `json:"name,omitempty"`
+	Version string   `json:"version,omitempty"`
+
+	caps map[string]bool
+}
+
+// Has reports whether a capability was negotiated
+func (s *Session) Has(capability string) bool {
+	return s.caps[capability]
+}
+
+// negotiate validates the plugin's handshake answer
+func negotiate(result handshakeResult) (*Session, error) {
+	v := result.ProtocolVersion
+	if v < MinProtocolVersion || v > MaxProtocolVersion {
+		return nil, fmt.Errorf("plugin selected protocol version %d; daemon supports %d-%d", v, MinProtocolVersion, MaxProtocolVersion)
+	}
+
+	offered := result.Capabilities
+	if v == 1 {
+		// version 1 predates the capability list
+		offered = append(append([]string{}, requiredCapabilities...), CapValidate, CapResolveConflict)
+	}
+
+	s := &Session{ProtocolVersion: v, Name: result.Name, Version: result.Version, caps: make(map[string]bool)}
+	for _, c := range offered {
+		if !knownCapabilities[c] {
+			s.Ignored = append(s.Ignored, c)
+			continue
+		}
+		if !s.caps[c] {
+			s.caps[c] = true
+			s.Capabilities = append(s.Capabilities, c)
+		}
+	}
+	sort.Strings(s.Capabilities)
+	sort.Strings(s.Ignored)
+
+	for _, c := range requiredCapabilities {
+		if !s.caps[c] {
+			return nil, fmt.Errorf("plugin does not offer required capability %s", c)
+		}
+	}
+
+	return s, nil
+}
+
+// supportedVersions lists the protocol versions offered in the handshake
+func supportedVersions() []int {
+	versions := make([]int, 0, MaxProtocolVersion-MinProtocolVersion+1)
+	for v := MaxProtocolVersion; v >= MinProtocolVersion; v-- {
+		versions = append(versions, v)
+	}
+	return versions
+}
+
+// daemonCapabilities lists the capabilities offered in the handshake
+func daemonCapabilities() []string {
+	caps := make([]string, 0, len(knownCapabilities))
+	for c := range knownCapabilities {
+		caps = append(caps, c)
+	}
+	sort.Strings(caps)
+	return caps
+}
@@ -0,0 +1,229 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: connector-config-schema
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Connector Config Schemas
+ */
+
+package connectors
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Type is a connector type: it creates connectors and documents the
+// config block pipelines configure them with
+//
+// Frozen: public as connectors.Type
+type Type interface {
+	New(config map[string]interface{}) (Connector, error)
+	// ConfigSchema returns the JSON Schema of the config block. Pipelines
+	// are validated against it when loaded and synctl renders it as the
+	// documentation of the type.
+	ConfigSchema() []byte
+}
+
+// WithSchema pairs a factory with the config schema of its connectors
+func WithSchema(factory Factory, schema []byte) Type {
+	return factoryType{factory: factory, schema: schema}
+}
+
+// factoryType is a Type built from a factory func
+type factoryType struct {
+	factory Factory
+	schema  []byte
+}
+
+// New implements Type
+func (t factoryType) New(config map[string]interface{}) (Connector, error) {
+	return t.factory(config)
+}
+
+// ConfigSchema implements Type
+func (t factoryType) ConfigSchema() []byte {
+	return t.schema
+}
+
+// ConfigSchema returns the config schema of a registered connector type
+func ConfigSchema(connectorType string) ([]byte, bool) {
+	factoriesMu.RLock()
+	t, exists := factories[connectorType]
+	factoriesMu.RUnlock()
+
+	if !exists {
+		return nil, false
+	}
+	return t.ConfigSchema(), true
+}
+
+// StringList reads a list of strings from a config value, such as an
+// array property of a config block; items are formatted with fmt.Sprint
+// and values that are not lists read as empty
+func StringList(v interface{}) []string {
+	list, _ := v.([]interface{})
+	out := make([]string, 0, len(list))
+	for _, item := range list {
+		out = append(out, fmt.Sprint(item))
+	}
+	return out
+}
+
+// ValidateConfig checks a connector config block against the schema of its
+// type. Unregistered types pass; creating their connector fails instead.
+func ValidateConfig(connectorType string, config map[string]interface{}) error {
+	schema, exists := ConfigSchema(connectorType)
+	if !exists {
+		return nil
+	}
+	var s map[string]interface{}
+	if err := json.Unmarshal(schema, &s); err != nil {
+		return fmt.Errorf("invalid config schema of connector type %s: %w", connectorType, err)
+	}
+
+	// Round-trip through JSON so YAML-decoded values compare as JSON ones
+	var value interface{} = map[string]interface{}{}
+	if config != nil {
+		data, err := json.Marshal(config)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s config: %w", connectorType, err)
+		}
+		if err := json.Unmarshal(data, &value); err != nil {
+			return fmt.Errorf("failed to decode %s config: %w", connectorType, err)
+		}
+	}
+
+	var problems []string
+	checkSchema(s, value, "config", &problems)
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid %s config: %s", connectorType, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// checkSchema validates value against the type, enum, minimum, maximum,
+// required, properties, additionalProperties and items keywords of a
+// schema, collecting the problems found at path
+func checkSchema(schema map[string]interface{}, value interface{}, path string, problems *[]string) {
+	if types := schemaTypes(schema["type"]); len(types) > 0 && !hasType(types, value) {
+		*problems = append(*problems, fmt.Sprintf("%s: expected %s, got %s", path, strings.Join(types, " or "), jsonType(value)))
+		return
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, allowed := range enum {
+			if reflect.DeepEqual(allowed, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			*problems = append(*problems, fmt.Sprintf("%s: %v is not one of %v", path, value, enum))
+		}
+	}
+	if n, ok := value.(float64); ok {
+		if min, ok := schema["minimum"].(float64); ok && n < min {
+			*problems = append(*problems, fmt.Sprintf("%s: %v is less than %v", path, n, min))
+		}
+		if max, ok := schema["maximum"].(float64); ok && n > max {
+			*problems = append(*problems, fmt.Sprintf("%s: %v is greater than %v", path, n, max))
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, name := range required {
+				if _, exists := v[fmt.Sprint(name)]; !exists {
+					*problems = append(*problems, fmt.Sprintf("%s.%v is required", path, name))
+				}
+			}
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if property, ok := properties[key].(map[string]interface{}); ok {
+				checkSchema(property, v[key], path+"."+key, problems)
+				continue
+			}
+			switch additional := schema["additionalProperties"].(type) {
+			case bool:
+				if !additional {
+					*problems = append(*problems, fmt.Sprintf("%s.%s is not a known setting", path, key))
+				}
+			case map[string]interface{}:
+				checkSchema(additional, v[key], path+"."+key, problems)
+			}
+		}
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				checkSchema(items, item, fmt.Sprintf("%s[%d]", path, i), problems)
+			}
+		}
+	}
+}
+
+// schemaTypes returns the type keyword as a list
+func schemaTypes(t interface{}) []string {
+	switch t := t.(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		types := make([]string, 0, len(t))
+		for _, name := range t {
+			types = append(types, fmt.Sprint(name))
+		}
+		return types
+	}
+	return nil
+}
+
+// hasType reports whether a JSON value is of one of the types
+func hasType(types []string, value interface{}) bool {
+	actual := jsonType(value)
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonType names the JSON type of a decoded value
+func jsonType(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
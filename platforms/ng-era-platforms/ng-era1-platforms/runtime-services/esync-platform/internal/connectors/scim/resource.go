@@ -0,0 +1,198 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: scim-resource
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * SCIM Resource Mapping
+ */
+
+package scim
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+)
+
+// Core schemas of SCIM resources (RFC 7643)
+const (
+	SchemaUser  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaGroup = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	schemaPatch = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+)
+
+// Fields of group records holding member IDs
+const (
+	FieldMembers        = "members"
+	FieldMembersAdded   = "members_added"
+	FieldMembersRemoved = "members_removed"
+)
+
+// DefaultUserAttributes maps SCIM user attributes to the inetOrgPerson
+// attributes a directory source reads
+var DefaultUserAttributes = map[string]string{
+	"userName":        "uid",
+	"displayName":     "cn",
+	"name.givenName":  "givenName",
+	"name.familyName": "sn",
+	"title":           "title",
+	"emails":          "mail",
+	"phoneNumbers":    "telephoneNumber",
+}
+
+// DefaultGroupAttributes maps SCIM group attributes to directory attributes
+var DefaultGroupAttributes = map[string]string{
+	"displayName": "cn",
+}
+
+// valueLists are multi-valued SCIM attributes whose items are objects with
+// a value, such as emails; plain values are wrapped and the first is
+// marked primary
+var valueLists = map[string]bool{
+	"emails":       true,
+	"phoneNumbers": true,
+	"ims":          true,
+	"photos":       true,
+	"entitlements": true,
+	"roles":        true,
+}
+
+// resourceType is the SCIM endpoint records of one table are written to
+type resourceType struct {
+	table    string
+	endpoint string
+	schema   string
+	// attributes maps SCIM attribute paths to record fields
+	attributes map[string]string
+	// key is the attribute identifying resources created outside esync,
+	// userName or displayName
+	key   string
+	group bool
+}
+
+// build returns the SCIM resource of a record, without members
+func (t *resourceType) build(r connectors.Record) map[string]interface{} {
+	resource := map[string]interface{}{"externalId": r.ID}
+	schemas := map[string]bool{t.schema: true}
+	if !t.group {
+		resource["active"] = true
+	}
+
+	paths := make([]string, 0, len(t.attributes))
+	for path := range t.attributes {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		value, ok := r.Data[t.attributes[path]]
+		if !ok {
+			continue
+		}
+		target := resource
+		if strings.HasPrefix(path, "urn:") {
+			i := strings.LastIndex(path, ":")
+			urn := path[:i]
+			schemas[urn] = true
+			ext, _ := resource[urn].(map[string]interface{})
+			if ext == nil {
+				ext = make(map[string]interface{})
+				resource[urn] = ext
+			}
+			target, path = ext, path[i+1:]
+		}
+		setPath(target, path, attributeValue(path, value))
+	}
+
+	list := make([]interface{}, 0, len(schemas))
+	for _, schema := range sortedKeys(schemas) {
+		list = append(list, schema)
+	}
+	resource["schemas"] = list
+	return resource
+}
+
+// setPath sets a dotted attribute path such as name.givenName
+func setPath(resource map[string]interface{}, path string, value interface{}) {
+	parts := strings.Split(path, ".")
+	for _, part := range parts[:len(parts)-1] {
+		next, _ := resource[part].(map[string]interface{})
+		if next == nil {
+			next = make(map[string]interface{})
+			resource[part] = next
+		}
+		resource = next
+	}
+	resource[parts[len(parts)-1]] = value
+}
+
+// attributeValue converts a record value to the form of a SCIM attribute:
+// value lists become lists of objects and other lists their first item
+func attributeValue(path string, value interface{}) interface{} {
+	list, isList := value.([]interface{})
+	if !valueLists[path] {
+		if isList {
+			if len(list) == 0 {
+				return nil
+			}
+			return list[0]
+		}
+		return value
+	}
+
+	if !isList {
+		if value == nil {
+			return []interface{}{}
+		}
+		list = []interface{}{value}
+	}
+	out := make([]interface{}, 0, len(list))
+	for i, item := range list {
+		if m, ok := item.(map[string]interface{}); ok {
+			out = append(out, m)
+			continue
+		}
+		out = append(out, map[string]interface{}{"value": fmt.Sprint(item), "primary": i == 0})
+	}
+	return out
+}
+
+// memberIDs reads a list of member IDs from record data
+func memberIDs(v interface{}) ([]string, bool) {
+	var out []string
+	switch list := v.(type) {
+	case []interface{}:
+		for _, item := range list {
+			out = append(out, fmt.Sprint(item))
+		}
+	case []string:
+		out = list
+	default:
+		return nil, false
+	}
+	return out, true
+}
+
+// sortedKeys returns the keys of a set in order
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// filterValue quotes a value for a SCIM filter
+func filterValue(v string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(v) + `"`
+}
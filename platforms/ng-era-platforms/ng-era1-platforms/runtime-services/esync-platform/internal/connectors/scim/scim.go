@@ -0,0 +1,608 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: connector-scim
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * SCIM 2.0 Provisioning Target
+ */
+
+// Package scim provisions users and groups to a SCIM 2.0 service provider
+// such as a SaaS application. Pipelines use it as:
+//
+//	target:
+//	  type: scim
+//	  config:
+//	    url: https://api.example.com/scim/v2
+//	    token: ${secret:SCIM_TOKEN}
+//	    users:
+//	      attributes: {userName: mail, emails: mail, name.givenName: givenName}
+//	    on_delete: deactivate
+//
+// Records of the users table become /Users resources and those of the
+// groups table /Groups resources, identified by their externalId, the
+// record ID. Existing resources are updated with PATCH so attributes the
+// mapping leaves out are kept; a resource created outside esync is adopted
+// when creating it conflicts on userName or displayName. Group members are
+// record IDs of users or groups; members_added and members_removed patch
+// memberships, otherwise members replaces them.
+//
+// SCIM IDs looked up by externalId are cached; a lookup_cache block bounds
+// the cache and can share it through Redis, see package lookupcache.
+package scim
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/conflict"
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/connectors/lookupcache"
+	"github.com/machine-native-ops/esync-platform/internal/egress"
+	"github.com/machine-native-ops/esync-platform/internal/oauth"
+)
+
+// contentType is the media type of SCIM requests and responses
+const contentType = "application/scim+json"
+
+// DefaultTimeout bounds one SCIM request when the config sets no timeout
+const DefaultTimeout = 30 * time.Second
+
+// Actions taken for deleted user records
+const (
+	// OnDeleteDeactivate sets active to false, keeping the account
+	OnDeleteDeactivate = "deactivate"
+	// OnDeleteDelete deletes the resource
+	OnDeleteDelete = "delete"
+)
+
+// configSchema documents the scim block of pipelines
+//
+//go:embed config.schema.json
+var configSchema []byte
+
+func init() {
+	connectors.Register("scim", connectors.WithSchema(New, configSchema))
+}
+
+// Connector writes records to a SCIM service provider
+type Connector struct {
+	base     string
+	http     *http.Client
+	token    string
+	username string
+	password string
+	onDelete string
+	users    *resourceType
+	groups   *resourceType
+	resolver *conflict.Resolver
+	limiter  connectors.Limiter
+
+	// ids caches the SCIM IDs of resources by table and externalId
+	ids *lookupcache.Cache
+}
+
+// New creates a scim connector from its config block
+func New(config map[string]interface{}) (connectors.Connector, error) {
+	str := func(key string) string {
+		s, _ := config[key].(string)
+		return s
+	}
+
+	base := strings.TrimRight(str("url"), "/")
+	if base == "" {
+		return nil, fmt.Errorf("scim url is required")
+	}
+	if u, err := url.Parse(base); err != nil || (u.Scheme != "https" && u.Scheme != "http") {
+		return nil, fmt.Errorf("scim url %s must be an http or https URL", base)
+	}
+
+	timeout := DefaultTimeout
+	if v, ok := config["timeout"]; ok {
+		seconds, err := strconv.ParseFloat(fmt.Sprint(v), 64)
+		if err != nil || seconds <= 0 {
+			return nil, fmt.Errorf("invalid scim timeout %v", v)
+		}
+		timeout = time.Duration(seconds * float64(time.Second))
+	}
+
+	c := &Connector{
+		base:     base,
+		http:     egress.Client(timeout),
+		token:    str("token"),
+		username: str("username"),
+		password: str("password"),
+		onDelete: OnDeleteDeactivate,
+		resolver: conflict.NewResolver(),
+	}
+	ids, err := lookupcache.FromConfig(config["lookup_cache"], "scim:"+base)
+	if err != nil {
+		return nil, err
+	}
+	c.ids = ids
+	if block, ok := config["oauth"].(map[string]interface{}); ok {
+		oauthConfig, err := oauth.FromConfig(block)
+		if err != nil {
+			return nil, err
+		}
+		source, err := oauth.NewTokenSource(oauthConfig)
+		if err != nil {
+			return nil, err
+		}
+		c.http = source.Client(timeout)
+	}
+	switch v := str("on_delete"); v {
+	case "", OnDeleteDeactivate:
+	case OnDeleteDelete:
+		c.onDelete = v
+	default:
+		return nil, fmt.Errorf("unknown scim on_delete %q, expected %s or %s", v, OnDeleteDeactivate, OnDeleteDelete)
+	}
+
+	if c.users, err = newResourceType(config["users"], resourceType{
+		table: "users", endpoint: "/Users", schema: SchemaUser, key: "userName",
+	}, DefaultUserAttributes); err != nil {
+		return nil, fmt.Errorf("scim users: %w", err)
+	}
+	if c.groups, err = newResourceType(config["groups"], resourceType{
+		table: "groups", endpoint: "/Groups", schema: SchemaGroup, key: "displayName", group: true,
+	}, DefaultGroupAttributes); err != nil {
+		return nil, fmt.Errorf("scim groups: %w", err)
+	}
+	if c.users.table == c.groups.table {
+		return nil, fmt.Errorf("scim users and groups must use different tables, both use %s", c.users.table)
+	}
+	return c, nil
+}
+
+// newResourceType reads the block of one resource type over defaults
+func newResourceType(v interface{}, t resourceType, defaults map[string]string) (*resourceType, error) {
+	block, _ := v.(map[string]interface{})
+	if s, _ := block["table"].(string); s != "" {
+		t.table = s
+	}
+	if s, _ := block["endpoint"].(string); s != "" {
+		t.endpoint = "/" + strings.Trim(s, "/")
+	}
+	if s, _ := block["schema"].(string); s != "" {
+		t.schema = s
+	}
+
+	t.attributes = defaults
+	if attrs, ok := block["attributes"].(map[string]interface{}); ok {
+		t.attributes = make(map[string]string, len(attrs))
+		for path, field := range attrs {
+			name, ok := field.(string)
+			if !ok || name == "" {
+				return nil, fmt.Errorf("attribute %s must name a record field", path)
+			}
+			t.attributes[path] = name
+		}
+	}
+	if t.attributes[t.key] == "" {
+		return nil, fmt.Errorf("attributes must map %s", t.key)
+	}
+	return &t, nil
+}
+
+// Limit implements connectors.Limited
+func (c *Connector) Limit(limiter connectors.Limiter) {
+	c.limiter = limiter
+}
+
+// Idempotent implements connectors.IdempotentWriter; resources are upserted
+// by externalId
+func (c *Connector) Idempotent() bool {
+	return true
+}
+
+// SupportsPatch implements connectors.PatchWriter; patches become SCIM
+// PATCH requests replacing the mapped attributes they carry
+func (c *Connector) SupportsPatch() bool {
+	return true
+}
+
+// TableReferences implements connectors.TableReferencer; groups reference
+// their member users
+func (c *Connector) TableReferences(ctx context.Context) (map[string][]string, error) {
+	return map[string][]string{c.groups.table: {c.users.table}}, nil
+}
+
+// resourceType returns the resource type records of a table are written
+// as; single-table pipelines write users
+func (c *Connector) resourceType(table string) (*resourceType, error) {
+	switch table {
+	case "", c.users.table:
+		return c.users, nil
+	case c.groups.table:
+		return c.groups, nil
+	}
+	return nil, fmt.Errorf("scim target has no resource type for table %s, expected %s or %s", table, c.users.table, c.groups.table)
+}
+
+// ApplyChanges implements connectors.Connector. Records are written one
+// request at a time in order.
+func (c *Connector) ApplyChanges(ctx context.Context, changes []connectors.Record) error {
+	for _, r := range changes {
+		t, err := c.resourceType(r.Table)
+		if err != nil {
+			return err
+		}
+		switch r.Operation {
+		case connectors.OperationInsert, connectors.OperationUpdate, connectors.OperationPatch:
+			err = c.upsert(ctx, t, r)
+		case connectors.OperationDelete:
+			err = c.remove(ctx, t, r)
+		default:
+			err = fmt.Errorf("unsupported operation %s", r.Operation)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to provision %s %s: %w", t.table, r.ID, err)
+		}
+	}
+	return nil
+}
+
+// upsert creates the resource of a record or patches the existing one
+func (c *Connector) upsert(ctx context.Context, t *resourceType, r connectors.Record) error {
+	id, err := c.lookup(ctx, t, "externalId", r.ID)
+	if err != nil {
+		return err
+	}
+	if id == "" {
+		if r.Operation == connectors.OperationPatch {
+			return fmt.Errorf("no resource with externalId %s to patch", r.ID)
+		}
+		if id, err = c.create(ctx, t, r); err != nil || id == "" {
+			return err
+		}
+	}
+
+	resource := t.build(r)
+	delete(resource, "schemas")
+	if r.Operation == connectors.OperationPatch {
+		delete(resource, "active")
+	}
+	ops := []interface{}{map[string]interface{}{"op": "replace", "value": resource}}
+	if t.group {
+		memberOps, err := c.memberOps(ctx, r)
+		if err != nil {
+			return err
+		}
+		ops = append(ops, memberOps...)
+	}
+	err = c.patch(ctx, t.endpoint+"/"+url.PathEscape(id), ops)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Status == http.StatusNotFound && r.Operation != connectors.OperationPatch {
+		// Deleted outside esync since it was cached; create it again
+		c.forget(ctx, t, r.ID)
+		_, err = c.create(ctx, t, r)
+	}
+	return err
+}
+
+// create posts the resource of a record. When the service provider already
+// holds a resource with the same userName or displayName, that resource is
+// adopted and its ID returned so the caller patches it.
+func (c *Connector) create(ctx context.Context, t *resourceType, r connectors.Record) (string, error) {
+	resource := t.build(r)
+	if t.group {
+		members, _ := memberIDs(r.Data[FieldMembers])
+		refs, err := c.memberRefs(ctx, members)
+		if err != nil {
+			return "", err
+		}
+		resource["members"] = refs
+	}
+
+	var created struct {
+		ID string `json:"id"`
+	}
+	err := c.do(ctx, http.MethodPost, t.endpoint, resource, &created)
+	if err == nil {
+		c.remember(ctx, t, r.ID, created.ID)
+		return "", nil
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusConflict {
+		return "", err
+	}
+
+	key, _ := resource[t.key].(string)
+	id, err := c.lookup(ctx, t, t.key, key)
+	if err != nil {
+		return "", err
+	}
+	if id == "" {
+		return "", apiErr
+	}
+	log.Printf("[SCIM] Adopting %s resource %s with %s %q as externalId %s", strings.TrimPrefix(t.endpoint, "/"), id, t.key, key, r.ID)
+	c.remember(ctx, t, r.ID, id)
+	return id, nil
+}
+
+// memberOps are the PATCH operations updating the members of a group:
+// added and removed members when the record lists them, or else all
+// members when it carries them
+func (c *Connector) memberOps(ctx context.Context, r connectors.Record) ([]interface{}, error) {
+	added, hasAdded := memberIDs(r.Data[FieldMembersAdded])
+	removed, hasRemoved := memberIDs(r.Data[FieldMembersRemoved])
+	if !hasAdded && !hasRemoved {
+		members, ok := memberIDs(r.Data[FieldMembers])
+		if !ok {
+			return nil, nil
+		}
+		refs, err := c.memberRefs(ctx, members)
+		if err != nil {
+			return nil, err
+		}
+		return []interface{}{map[string]interface{}{"op": "replace", "path": "members", "value": refs}}, nil
+	}
+
+	var ops []interface{}
+	if len(added) > 0 {
+		refs, err := c.memberRefs(ctx, added)
+		if err != nil {
+			return nil, err
+		}
+		if len(refs) > 0 {
+			ops = append(ops, map[string]interface{}{"op": "add", "path": "members", "value": refs})
+		}
+	}
+	for _, member := range removed {
+		id, err := c.memberID(ctx, member)
+		if err != nil {
+			return nil, err
+		}
+		if id != "" {
+			ops = append(ops, map[string]interface{}{"op": "remove", "path": "members[value eq " + filterValue(id) + "]"})
+		}
+	}
+	return ops, nil
+}
+
+// memberRefs resolves member record IDs to SCIM member references. Members
+// not provisioned are left out.
+func (c *Connector) memberRefs(ctx context.Context, members []string) ([]interface{}, error) {
+	refs := make([]interface{}, 0, len(members))
+	for _, member := range members {
+		id, err := c.memberID(ctx, member)
+		if err != nil {
+			return nil, err
+		}
+		if id == "" {
+			log.Printf("[SCIM] Member %s is not provisioned, leaving it out of its group", member)
+			continue
+		}
+		refs = append(refs, map[string]interface{}{"value": id})
+	}
+	return refs, nil
+}
+
+// memberID resolves a member record ID to the SCIM ID of a user or group
+func (c *Connector) memberID(ctx context.Context, member string) (string, error) {
+	for _, t := range []*resourceType{c.users, c.groups} {
+		id, err := c.lookup(ctx, t, "externalId", member)
+		if err != nil || id != "" {
+			return id, err
+		}
+	}
+	return "", nil
+}
+
+// remove deactivates or deletes the resource of a record
+func (c *Connector) remove(ctx context.Context, t *resourceType, r connectors.Record) error {
+	id, err := c.lookup(ctx, t, "externalId", r.ID)
+	if err != nil || id == "" {
+		return err
+	}
+	path := t.endpoint + "/" + url.PathEscape(id)
+
+	if !t.group && c.onDelete == OnDeleteDeactivate {
+		err = c.patch(ctx, path, []interface{}{map[string]interface{}{"op": "replace", "value": map[string]interface{}{"active": false}}})
+	} else {
+		err = c.do(ctx, http.MethodDelete, path, nil, nil)
+		c.forget(ctx, t, r.ID)
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Status == http.StatusNotFound {
+		c.forget(ctx, t, r.ID)
+		return nil
+	}
+	return err
+}
+
+// patch sends a PATCH request with ops
+func (c *Connector) patch(ctx context.Context, path string, ops []interface{}) error {
+	body := map[string]interface{}{"schemas": []interface{}{schemaPatch}, "Operations": ops}
+	return c.do(ctx, http.MethodPatch, path, body, nil)
+}
+
+// lookup returns the SCIM ID of the resource whose attribute equals value,
+// or empty when there is none. IDs by externalId are cached.
+func (c *Connector) lookup(ctx context.Context, t *resourceType, attribute, value string) (string, error) {
+	if attribute == "externalId" {
+		if id, ok := c.ids.Get(ctx, t.table+"/"+value); ok {
+			return id, nil
+		}
+	}
+
+	var list struct {
+		Resources []struct {
+			ID string `json:"id"`
+		} `json:"Resources"`
+	}
+	query := url.Values{"filter": {attribute + " eq " + filterValue(value)}}
+	if err := c.do(ctx, http.MethodGet, t.endpoint+"?"+query.Encode(), nil, &list); err != nil {
+		return "", err
+	}
+	if len(list.Resources) == 0 {
+		return "", nil
+	}
+	id := list.Resources[0].ID
+	if attribute == "externalId" {
+		c.remember(ctx, t, value, id)
+	}
+	return id, nil
+}
+
+// remember caches the SCIM ID of a record
+func (c *Connector) remember(ctx context.Context, t *resourceType, externalID, id string) {
+	c.ids.Set(ctx, t.table+"/"+externalID, id)
+}
+
+// forget drops the cached SCIM ID of a record
+func (c *Connector) forget(ctx context.Context, t *resourceType, externalID string) {
+	c.ids.Delete(ctx, t.table+"/"+externalID)
+}
+
+// LookupCache returns the cache of SCIM IDs
+func (c *Connector) LookupCache() connectors.LookupCache {
+	return c.ids
+}
+
+// APIError is an error response of the service provider
+type APIError struct {
+	Method string
+	Path   string
+	Status int
+	// Detail is the detail of a SCIM error response, or the start of the body
+	Detail string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("SCIM %s %s returned %d: %s", e.Method, e.Path, e.Status, e.Detail)
+}
+
+// do sends a request once the call limits allow it and decodes the response
+// into out when non-nil
+func (c *Connector) do(ctx context.Context, method, path string, in, out interface{}) error {
+	release, err := connectors.Acquire(ctx, c.limiter)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.base+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", contentType+", application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	switch {
+	case c.token != "":
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	case c.username != "":
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("SCIM request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		var scimErr struct {
+			Detail   string `json:"detail"`
+			ScimType string `json:"scimType"`
+		}
+		detail := strings.TrimSpace(string(raw))
+		if json.Unmarshal(raw, &scimErr) == nil && scimErr.Detail != "" {
+			detail = scimErr.Detail
+			if scimErr.ScimType != "" {
+				detail += " (" + scimErr.ScimType + ")"
+			}
+		}
+		if len(detail) > 512 {
+			detail = detail[:512]
+		}
+		return &APIError{Method: method, Path: strings.SplitN(path, "?", 2)[0], Status: resp.StatusCode, Detail: detail}
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode SCIM response: %w", err)
+	}
+	return nil
+}
+
+// Preflight implements connectors.Preflighter by listing one user
+func (c *Connector) Preflight(ctx context.Context, role string) []connectors.CheckResult {
+	check := connectors.CheckResult{Name: "scim_access", Status: connectors.CheckPassed}
+	if role != "target" {
+		check.Status = connectors.CheckFailed
+		check.Message = "the scim connector can only be a target"
+		check.Remedy = "use scim as the pipeline target"
+		return []connectors.CheckResult{check}
+	}
+	if err := c.do(ctx, http.MethodGet, c.users.endpoint+"?count=1", nil, nil); err != nil {
+		check.Status = connectors.CheckFailed
+		check.Message = err.Error()
+		check.Remedy = "verify the scim url and that the token may read and write users and groups"
+	}
+	return []connectors.CheckResult{check}
+}
+
+// ListChanges implements connectors.Connector; the connector is a target
+// only
+func (c *Connector) ListChanges(ctx context.Context, checkpoint *connectors.Checkpoint) ([]connectors.Record, error) {
+	return nil, fmt.Errorf("scim connector cannot be a source: %w", connectors.ErrUnsupported)
+}
+
+// GetLatestCheckpoint implements connectors.Connector
+func (c *Connector) GetLatestCheckpoint(ctx context.Context) (*connectors.Checkpoint, error) {
+	return nil, nil
+}
+
+// Validate implements connectors.Connector. Users need a userName and
+// groups a displayName, except in deletes and patches.
+func (c *Connector) Validate(ctx context.Context, record connectors.Record) connectors.ValidationResult {
+	t, err := c.resourceType(record.Table)
+	if err != nil {
+		return connectors.ValidationResult{Errors: []string{err.Error()}}
+	}
+	if record.Operation == connectors.OperationDelete || record.Operation == connectors.OperationPatch {
+		return connectors.ValidationResult{IsValid: true}
+	}
+	field := t.attributes[t.key]
+	if v, ok := record.Data[field]; !ok || v == nil || v == "" {
+		return connectors.ValidationResult{Errors: []string{fmt.Sprintf("record has no %s for the %s of the %s resource", field, t.key, strings.TrimPrefix(t.endpoint, "/"))}}
+	}
+	return connectors.ValidationResult{IsValid: true}
+}
+
+// ResolveConflict implements connectors.Connector
+func (c *Connector) ResolveConflict(ctx context.Context, existing, incoming connectors.Record) (connectors.Record, error) {
+	winner, _ := c.resolver.Resolve(existing, incoming)
+	return winner, nil
+}
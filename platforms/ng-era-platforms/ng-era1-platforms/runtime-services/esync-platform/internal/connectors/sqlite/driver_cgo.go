@@ -0,0 +1,25 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+
+//go:build sqlite && cgo
+
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: connector-sqlite-driver
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * SQLite Driver
+ */
+
+package sqlite
+
+import _ "github.com/mattn/go-sqlite3"
+
+// driverAvailable reports the sqlite3 driver is linked in
+const driverAvailable = true
@@ -0,0 +1,24 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+
+//go:build !(sqlite && cgo)
+
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: connector-sqlite-driver
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * SQLite Driver
+ */
+
+package sqlite
+
+// driverAvailable leaves the connector unusable in default builds, which
+// are static and without cgo
+const driverAvailable = false
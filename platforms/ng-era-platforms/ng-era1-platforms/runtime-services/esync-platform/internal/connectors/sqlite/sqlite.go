@@ -0,0 +1,366 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: connector-sqlite
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * SQLite Target
+ */
+
+// Package sqlite keeps a local, queryable replica in a SQLite database,
+// for edge devices running syncd:
+//
+//	target:
+//	  type: sqlite
+//	  config:
+//	    path: /var/lib/syncd/replica.db
+//
+// Each record table becomes a table keyed by the record ID, with a column
+// per field added as fields appear; objects and lists are stored as JSON
+// text, which SQLite's JSON functions query. Batches are upserted in one
+// transaction on a database in WAL mode, so readers on the device never
+// block the replica or see half a batch.
+//
+// The driver needs cgo, which the static default build leaves out; build
+// edge binaries with make build-edge, or go build -tags sqlite with
+// CGO_ENABLED=1.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/conflict"
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+)
+
+// Defaults of the connector config
+const (
+	DefaultTable     = "records"
+	DefaultKeyColumn = "id"
+	DefaultTimeout   = 30 * time.Second
+)
+
+// configSchema documents the sqlite block of pipelines
+//
+//go:embed config.schema.json
+var configSchema []byte
+
+func init() {
+	connectors.Register("sqlite", connectors.WithSchema(New, configSchema))
+}
+
+// Connector writes records to a SQLite database
+type Connector struct {
+	path      string
+	db        *sql.DB
+	table     string
+	keyColumn string
+	timeout   time.Duration
+	resolver  *conflict.Resolver
+	limiter   connectors.Limiter
+
+	mu sync.Mutex
+	// columns caches the columns of the tables written to
+	columns map[string]map[string]bool
+}
+
+// New creates a sqlite connector from its config block and opens the
+// database, creating it when missing
+func New(config map[string]interface{}) (connectors.Connector, error) {
+	if !driverAvailable {
+		return nil, fmt.Errorf("sqlite connector is not built into this binary; build with -tags sqlite and CGO_ENABLED=1")
+	}
+	str := func(key string) string {
+		s, _ := config[key].(string)
+		return s
+	}
+
+	c := &Connector{
+		path:      str("path"),
+		table:     str("table"),
+		keyColumn: str("key_column"),
+		timeout:   DefaultTimeout,
+		resolver:  conflict.NewResolver(),
+		columns:   make(map[string]map[string]bool),
+	}
+	if c.path == "" {
+		return nil, fmt.Errorf("sqlite path is required")
+	}
+	if c.table == "" {
+		c.table = DefaultTable
+	}
+	if c.keyColumn == "" {
+		c.keyColumn = DefaultKeyColumn
+	}
+	if v, ok := config["timeout"]; ok {
+		seconds, err := strconv.ParseFloat(fmt.Sprint(v), 64)
+		if err != nil || seconds <= 0 {
+			return nil, fmt.Errorf("invalid sqlite timeout %v", v)
+		}
+		c.timeout = time.Duration(seconds * float64(time.Second))
+	}
+
+	// The busy timeout lets writes wait for readers holding the database
+	// on the device instead of failing
+	dsn := fmt.Sprintf("file:%s?_journal_mode=WAL&_synchronous=NORMAL&_busy_timeout=%d&_foreign_keys=off", c.path, c.timeout.Milliseconds())
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+	// One connection serializes the writes of the replica
+	db.SetMaxOpenConns(1)
+	c.db = db
+	return c, nil
+}
+
+// Limit implements connectors.Limited
+func (c *Connector) Limit(limiter connectors.Limiter) {
+	c.limiter = limiter
+}
+
+// Idempotent implements connectors.IdempotentWriter; records are upserted
+// on their key
+func (c *Connector) Idempotent() bool {
+	return true
+}
+
+// SupportsPatch implements connectors.PatchWriter; patches update only
+// the columns they carry
+func (c *Connector) SupportsPatch() bool {
+	return true
+}
+
+// tableOf returns the table of a record
+func (c *Connector) tableOf(r connectors.Record) string {
+	if r.Table != "" {
+		return r.Table
+	}
+	return c.table
+}
+
+// ApplyChanges implements connectors.Connector by applying the batch in
+// one transaction. Inserts and updates replace the row, clearing columns
+// the record lacks; patches set only the columns they carry.
+func (c *Connector) ApplyChanges(ctx context.Context, changes []connectors.Record) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	release, err := connectors.Acquire(ctx, c.limiter)
+	if err != nil {
+		return err
+	}
+	defer release()
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin sqlite transaction: %w", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			tx.Rollback()
+			// The cached columns may name columns the rollback removed
+			c.columns = make(map[string]map[string]bool)
+		}
+	}()
+
+	for _, r := range changes {
+		table := c.tableOf(r)
+		if err := c.ensureTable(ctx, tx, table, r.Data); err != nil {
+			return err
+		}
+		if r.Operation == connectors.OperationDelete {
+			query := fmt.Sprintf("DELETE FROM %s WHERE %s = ?", quote(table), quote(c.keyColumn))
+			if _, err := tx.ExecContext(ctx, query, r.ID); err != nil {
+				return fmt.Errorf("failed to delete %s from %s: %w", r.ID, table, err)
+			}
+			continue
+		}
+		if err := c.upsert(ctx, tx, table, r); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit sqlite transaction: %w", err)
+	}
+	committed = true
+	return nil
+}
+
+// upsert inserts or updates the row of a record
+func (c *Connector) upsert(ctx context.Context, tx *sql.Tx, table string, r connectors.Record) error {
+	names := []string{quote(c.keyColumn)}
+	placeholders := []string{"?"}
+	args := []interface{}{r.ID}
+	var updates []string
+	for column := range c.columns[table] {
+		if column == c.keyColumn {
+			continue
+		}
+		v, ok := r.Data[column]
+		if !ok && r.Operation == connectors.OperationPatch {
+			continue
+		}
+		value, err := columnValue(v)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s of %s: %w", column, r.ID, err)
+		}
+		names = append(names, quote(column))
+		placeholders = append(placeholders, "?")
+		args = append(args, value)
+		updates = append(updates, quote(column)+" = excluded."+quote(column))
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (%s) DO ", quote(table), strings.Join(names, ", "), strings.Join(placeholders, ", "), quote(c.keyColumn))
+	if len(updates) == 0 {
+		query += "NOTHING"
+	} else {
+		query += "UPDATE SET " + strings.Join(updates, ", ")
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to upsert %s into %s: %w", r.ID, table, err)
+	}
+	return nil
+}
+
+// ensureTable creates a missing table and adds columns for new fields
+func (c *Connector) ensureTable(ctx context.Context, tx *sql.Tx, table string, data map[string]interface{}) error {
+	columns, ok := c.columns[table]
+	if !ok {
+		query := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s TEXT PRIMARY KEY NOT NULL)", quote(table), quote(c.keyColumn))
+		if _, err := tx.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("failed to create sqlite table %s: %w", table, err)
+		}
+		rows, err := tx.QueryContext(ctx, fmt.Sprintf("SELECT name FROM pragma_table_info(%s)", quoteString(table)))
+		if err != nil {
+			return fmt.Errorf("failed to read columns of %s: %w", table, err)
+		}
+		columns = make(map[string]bool)
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				rows.Close()
+				return err
+			}
+			columns[name] = true
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		c.columns[table] = columns
+	}
+
+	for field := range data {
+		if columns[field] {
+			continue
+		}
+		// Columns without a declared type take values of any type
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", quote(table), quote(field))); err != nil {
+			return fmt.Errorf("failed to add column %s to %s: %w", field, table, err)
+		}
+		columns[field] = true
+	}
+	return nil
+}
+
+// columnValue converts a record value to a SQLite value: booleans become
+// 0 or 1 and objects and lists JSON text
+func columnValue(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case nil, string, float64, int64, int:
+		return v, nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	case json.Number:
+		return v.String(), nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// quote quotes an identifier
+func quote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteString quotes a string literal
+func quoteString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// Preflight implements connectors.Preflighter by checking the database
+// opens in WAL mode
+func (c *Connector) Preflight(ctx context.Context, role string) []connectors.CheckResult {
+	check := connectors.CheckResult{Name: "sqlite_database", Status: connectors.CheckPassed}
+	if role != "target" {
+		check.Status = connectors.CheckFailed
+		check.Message = "the sqlite connector can only be a target"
+		check.Remedy = "use sqlite as the pipeline target"
+		return []connectors.CheckResult{check}
+	}
+	var mode string
+	if err := c.db.QueryRowContext(ctx, "PRAGMA journal_mode").Scan(&mode); err != nil {
+		check.Status = connectors.CheckFailed
+		check.Message = err.Error()
+		check.Remedy = fmt.Sprintf("verify syncd may create and write %s and its directory", c.path)
+	} else if !strings.EqualFold(mode, "wal") {
+		check.Message = fmt.Sprintf("database journal mode is %s, not wal, so readers block writes; place it on a local filesystem", mode)
+	}
+	return []connectors.CheckResult{check}
+}
+
+// Close closes the database
+func (c *Connector) Close() error {
+	return c.db.Close()
+}
+
+// ListChanges implements connectors.Connector; the connector is a target
+// only
+func (c *Connector) ListChanges(ctx context.Context, checkpoint *connectors.Checkpoint) ([]connectors.Record, error) {
+	return nil, fmt.Errorf("sqlite connector cannot be a source: %w", connectors.ErrUnsupported)
+}
+
+// GetLatestCheckpoint implements connectors.Connector
+func (c *Connector) GetLatestCheckpoint(ctx context.Context) (*connectors.Checkpoint, error) {
+	return nil, nil
+}
+
+// Validate implements connectors.Connector
+func (c *Connector) Validate(ctx context.Context, record connectors.Record) connectors.ValidationResult {
+	if record.ID == "" {
+		return connectors.ValidationResult{Errors: []string{"record has no ID"}}
+	}
+	return connectors.ValidationResult{IsValid: true}
+}
+
+// ResolveConflict implements connectors.Connector
+func (c *Connector) ResolveConflict(ctx context.Context, existing, incoming connectors.Record) (connectors.Record, error) {
+	winner, _ := c.resolver.Resolve(existing, incoming)
+	return winner, nil
+}
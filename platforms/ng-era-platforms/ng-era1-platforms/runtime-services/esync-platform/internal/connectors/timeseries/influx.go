@@ -0,0 +1,86 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: influx-line-protocol
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * InfluxDB Line Protocol
+ */
+
+package timeseries
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Escapes of the line protocol elements
+var (
+	measurementEscaper = strings.NewReplacer(",", `\,`, " ", `\ `, "\n", `\n`)
+	keyEscaper         = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `, "\n", `\n`)
+	stringEscaper      = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+)
+
+// lineProtocol encodes points one per line with nanosecond timestamps.
+// Numbers are written as floats so a field keeps one type however its
+// values look.
+func lineProtocol(points []point) []byte {
+	var b strings.Builder
+	for _, p := range points {
+		b.WriteString(measurementEscaper.Replace(p.measurement))
+		for _, k := range sortedKeys(p.tags) {
+			if p.tags[k] == "" {
+				continue
+			}
+			b.WriteByte(',')
+			b.WriteString(keyEscaper.Replace(k))
+			b.WriteByte('=')
+			b.WriteString(keyEscaper.Replace(p.tags[k]))
+		}
+
+		fields := make([]string, 0, len(p.fields))
+		for k := range p.fields {
+			fields = append(fields, k)
+		}
+		sort.Strings(fields)
+		for i, k := range fields {
+			if i == 0 {
+				b.WriteByte(' ')
+			} else {
+				b.WriteByte(',')
+			}
+			b.WriteString(keyEscaper.Replace(k))
+			b.WriteByte('=')
+			switch v := p.fields[k].(type) {
+			case float64:
+				b.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+			case bool:
+				b.WriteString(strconv.FormatBool(v))
+			case string:
+				b.WriteString(`"` + stringEscaper.Replace(v) + `"`)
+			}
+		}
+		b.WriteByte(' ')
+		b.WriteString(strconv.FormatInt(p.time.UnixNano(), 10))
+		b.WriteByte('\n')
+	}
+	return []byte(b.String())
+}
+
+// sortedKeys returns the keys of a tag set in order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
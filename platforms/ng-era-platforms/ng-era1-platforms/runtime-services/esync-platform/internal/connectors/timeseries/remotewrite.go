@@ -0,0 +1,165 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: prometheus-remote-write
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Prometheus Remote Write Encoding
+ */
+
+package timeseries
+
+import (
+	"encoding/binary"
+	"math"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// series is one remote write time series
+type series struct {
+	labels  [][2]string
+	samples []sample
+}
+
+// sample is one value of a series in milliseconds since the epoch
+type sample struct {
+	value float64
+	ms    int64
+}
+
+// remoteWriteRequest encodes points as a prometheus.WriteRequest. Each
+// field becomes the series <measurement>_<field> labelled with the tags;
+// samples of a series are in time order, the last value winning for
+// repeated timestamps.
+func remoteWriteRequest(points []point) []byte {
+	bySeries := make(map[string]*series)
+	var keys []string
+	for _, p := range points {
+		for field, v := range p.fields {
+			var value float64
+			switch v := v.(type) {
+			case float64:
+				value = v
+			case bool:
+				if v {
+					value = 1
+				}
+			default:
+				continue
+			}
+
+			key := seriesKey(p, field)
+			s := bySeries[key]
+			if s == nil {
+				s = &series{labels: seriesLabels(p, field)}
+				bySeries[key] = s
+				keys = append(keys, key)
+			}
+			ms := p.time.UnixMilli()
+			if n := len(s.samples); n > 0 && s.samples[n-1].ms == ms {
+				s.samples[n-1].value = value
+				continue
+			}
+			s.samples = append(s.samples, sample{value: value, ms: ms})
+		}
+	}
+
+	var out []byte
+	for _, key := range keys {
+		s := bySeries[key]
+		var ts []byte
+		for _, l := range s.labels {
+			var label []byte
+			label = protowire.AppendTag(label, 1, protowire.BytesType)
+			label = protowire.AppendString(label, l[0])
+			label = protowire.AppendTag(label, 2, protowire.BytesType)
+			label = protowire.AppendString(label, l[1])
+			ts = protowire.AppendTag(ts, 1, protowire.BytesType)
+			ts = protowire.AppendBytes(ts, label)
+		}
+		for _, smp := range s.samples {
+			var encoded []byte
+			encoded = protowire.AppendTag(encoded, 1, protowire.Fixed64Type)
+			encoded = protowire.AppendFixed64(encoded, math.Float64bits(smp.value))
+			encoded = protowire.AppendTag(encoded, 2, protowire.VarintType)
+			encoded = protowire.AppendVarint(encoded, uint64(smp.ms))
+			ts = protowire.AppendTag(ts, 2, protowire.BytesType)
+			ts = protowire.AppendBytes(ts, encoded)
+		}
+		out = protowire.AppendTag(out, 1, protowire.BytesType)
+		out = protowire.AppendBytes(out, ts)
+	}
+	return out
+}
+
+// seriesLabels returns the labels of a series sorted by name, starting with
+// the metric name
+func seriesLabels(p point, field string) [][2]string {
+	labels := [][2]string{{"__name__", metricName(p.measurement + "_" + field)}}
+	for k, v := range p.tags {
+		if v != "" {
+			labels = append(labels, [2]string{labelName(k), v})
+		}
+	}
+	sort.Slice(labels, func(i, j int) bool { return labels[i][0] < labels[j][0] })
+	return labels
+}
+
+// metricName replaces the characters metric names may not hold with _
+func metricName(s string) string {
+	return sanitize(s, true)
+}
+
+// labelName replaces the characters label names may not hold with _
+func labelName(s string) string {
+	return sanitize(s, false)
+}
+
+// sanitize maps s to [a-zA-Z_:][a-zA-Z0-9_:]*, without colons in label
+// names
+func sanitize(s string, colons bool) string {
+	var b strings.Builder
+	for i, r := range s {
+		ok := r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (colons && r == ':') || (i > 0 && r >= '0' && r <= '9')
+		if ok {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+// snappyEncode frames src in the snappy block format as literals only,
+// which every snappy decoder reads; remote write requires snappy framing
+// but not compression
+func snappyEncode(src []byte) []byte {
+	out := binary.AppendUvarint(nil, uint64(len(src)))
+	for len(src) > 0 {
+		n := len(src)
+		if n > 65536 {
+			n = 65536
+		}
+		switch m := n - 1; {
+		case m < 60:
+			out = append(out, byte(m)<<2)
+		case m < 256:
+			out = append(out, 60<<2, byte(m))
+		default:
+			out = append(out, 61<<2, byte(m), byte(m>>8))
+		}
+		out = append(out, src[:n]...)
+		src = src[n:]
+	}
+	return out
+}
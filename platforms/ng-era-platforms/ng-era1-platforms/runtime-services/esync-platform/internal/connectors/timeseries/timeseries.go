@@ -0,0 +1,562 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: connector-timeseries
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Time-Series Store Target
+ */
+
+// Package timeseries writes records as points to time-series stores, with
+// the InfluxDB line protocol or Prometheus remote write. Pipelines use it
+// as:
+//
+//	target:
+//	  type: timeseries
+//	  config:
+//	    protocol: influx
+//	    url: http://influxdb:8086/api/v2/write?org=acme&bucket=metrics
+//	    token: ${secret:INFLUX_TOKEN}
+//	    measurement: "{table}"
+//	    tags: [region, host]
+//	    fields: [cpu, memory]
+//	    time_field: sampled_at
+//
+// Line protocol also feeds QuestDB, VictoriaMetrics and Telegraf, which
+// writes to TimescaleDB; remote write feeds Prometheus, Mimir, Cortex,
+// Thanos and Promscale on TimescaleDB. With remote write each field becomes
+// the series <measurement>_<field> and tags become labels.
+//
+// Stores differ in how they take late points: InfluxDB overwrites them,
+// Prometheus rejects samples older than the newest of their series. Points
+// are sorted per series within each batch, and with out_of_order drop,
+// the default for remote write, points older than the last one written for
+// their series are dropped and counted instead of failing the batch.
+// Deletes are skipped, the stores being append-only.
+package timeseries
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/conflict"
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/egress"
+)
+
+// Write protocols
+const (
+	ProtocolInflux     = "influx"
+	ProtocolPrometheus = "prometheus"
+)
+
+// Handling of points older than the last written for their series
+const (
+	// OutOfOrderAccept writes late points, for stores that take them
+	OutOfOrderAccept = "accept"
+	// OutOfOrderDrop drops and counts them
+	OutOfOrderDrop = "drop"
+)
+
+// Defaults of the connector config
+const (
+	DefaultBatchSize = 5000
+	DefaultTimeout   = 30 * time.Second
+)
+
+// configSchema documents the timeseries block of pipelines
+//
+//go:embed config.schema.json
+var configSchema []byte
+
+func init() {
+	connectors.Register("timeseries", connectors.WithSchema(New, configSchema))
+}
+
+// point is one measurement at one time
+type point struct {
+	measurement string
+	tags        map[string]string
+	fields      map[string]interface{}
+	time        time.Time
+}
+
+// Connector writes records as points
+type Connector struct {
+	protocol         string
+	url              string
+	http             *http.Client
+	token            string
+	username         string
+	password         string
+	headers          map[string]string
+	measurement      string
+	measurementField string
+	tags             []string
+	fields           []string
+	timeField        string
+	timeUnit         time.Duration
+	batchSize        int
+	outOfOrder       string
+	resolver         *conflict.Resolver
+	limiter          connectors.Limiter
+	metrics          connectors.MetricsReporter
+
+	// last holds the time of the newest point written per series
+	mu   sync.Mutex
+	last map[string]time.Time
+}
+
+// New creates a timeseries connector from its config block
+func New(config map[string]interface{}) (connectors.Connector, error) {
+	str := func(key string) string {
+		s, _ := config[key].(string)
+		return s
+	}
+
+	c := &Connector{
+		protocol:         str("protocol"),
+		url:              str("url"),
+		token:            str("token"),
+		username:         str("username"),
+		password:         str("password"),
+		headers:          make(map[string]string),
+		measurement:      str("measurement"),
+		measurementField: str("measurement_field"),
+		tags:             connectors.StringList(config["tags"]),
+		fields:           connectors.StringList(config["fields"]),
+		timeField:        str("time_field"),
+		timeUnit:         time.Millisecond,
+		batchSize:        DefaultBatchSize,
+		outOfOrder:       str("out_of_order"),
+		resolver:         conflict.NewResolver(),
+		last:             make(map[string]time.Time),
+	}
+
+	switch c.protocol {
+	case ProtocolInflux:
+		if c.outOfOrder == "" {
+			c.outOfOrder = OutOfOrderAccept
+		}
+	case ProtocolPrometheus:
+		if c.outOfOrder == "" {
+			c.outOfOrder = OutOfOrderDrop
+		}
+	default:
+		return nil, fmt.Errorf("unknown timeseries protocol %q, expected %s or %s", c.protocol, ProtocolInflux, ProtocolPrometheus)
+	}
+	if c.outOfOrder != OutOfOrderAccept && c.outOfOrder != OutOfOrderDrop {
+		return nil, fmt.Errorf("unknown timeseries out_of_order %q, expected %s or %s", c.outOfOrder, OutOfOrderAccept, OutOfOrderDrop)
+	}
+
+	u, err := url.Parse(c.url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("timeseries url %q must be an http or https URL", c.url)
+	}
+	if c.protocol == ProtocolInflux {
+		// Points carry nanosecond timestamps
+		q := u.Query()
+		q.Set("precision", "ns")
+		u.RawQuery = q.Encode()
+		c.url = u.String()
+	}
+	if c.measurement == "" && c.measurementField == "" {
+		return nil, fmt.Errorf("timeseries measurement or measurement_field is required")
+	}
+
+	if headers, ok := config["headers"].(map[string]interface{}); ok {
+		for k, v := range headers {
+			c.headers[k] = fmt.Sprint(v)
+		}
+	}
+	switch unit := str("time_unit"); unit {
+	case "s":
+		c.timeUnit = time.Second
+	case "", "ms":
+	case "us":
+		c.timeUnit = time.Microsecond
+	case "ns":
+		c.timeUnit = time.Nanosecond
+	default:
+		return nil, fmt.Errorf("unknown timeseries time_unit %q, expected s, ms, us or ns", unit)
+	}
+	if v, ok := config["batch_size"]; ok {
+		n, err := strconv.Atoi(fmt.Sprint(v))
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid timeseries batch_size %v", v)
+		}
+		c.batchSize = n
+	}
+	timeout := DefaultTimeout
+	if v, ok := config["timeout"]; ok {
+		seconds, err := strconv.ParseFloat(fmt.Sprint(v), 64)
+		if err != nil || seconds <= 0 {
+			return nil, fmt.Errorf("invalid timeseries timeout %v", v)
+		}
+		timeout = time.Duration(seconds * float64(time.Second))
+	}
+	c.http = egress.Client(timeout)
+	return c, nil
+}
+
+// Limit implements connectors.Limited
+func (c *Connector) Limit(limiter connectors.Limiter) {
+	c.limiter = limiter
+}
+
+// Instrument implements connectors.Instrumented
+func (c *Connector) Instrument(reporter connectors.MetricsReporter) {
+	c.metrics = reporter
+}
+
+// count adds to a connector counter when instrumented
+func (c *Connector) count(name string, delta float64) {
+	if c.metrics != nil && delta > 0 {
+		c.metrics.Count(name, delta)
+	}
+}
+
+// Idempotent implements connectors.IdempotentWriter; rewriting a point
+// replaces it
+func (c *Connector) Idempotent() bool {
+	return true
+}
+
+// ApplyChanges implements connectors.Connector. Records are written as
+// points in batches of batch_size; deletes are skipped.
+func (c *Connector) ApplyChanges(ctx context.Context, changes []connectors.Record) error {
+	points := make([]point, 0, len(changes))
+	for _, r := range changes {
+		if r.Operation == connectors.OperationDelete {
+			continue
+		}
+		p, err := c.point(r)
+		if err != nil {
+			return fmt.Errorf("record %s: %w", r.ID, err)
+		}
+		if len(p.fields) > 0 {
+			points = append(points, p)
+		}
+	}
+
+	points = c.order(points)
+	for start := 0; start < len(points); start += c.batchSize {
+		end := start + c.batchSize
+		if end > len(points) {
+			end = len(points)
+		}
+		if err := c.write(ctx, points[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// point maps a record to a point
+func (c *Connector) point(r connectors.Record) (point, error) {
+	p := point{
+		measurement: strings.ReplaceAll(c.measurement, "{table}", r.Table),
+		tags:        make(map[string]string, len(c.tags)),
+		fields:      make(map[string]interface{}),
+		time:        r.Timestamp,
+	}
+	if c.measurementField != "" {
+		name, ok := r.Data[c.measurementField].(string)
+		if !ok || name == "" {
+			return p, fmt.Errorf("no measurement in %s", c.measurementField)
+		}
+		p.measurement = name
+	}
+	if p.measurement == "" {
+		return p, fmt.Errorf("empty measurement name")
+	}
+
+	for _, tag := range c.tags {
+		if v, ok := r.Data[tag]; ok && v != nil {
+			p.tags[tag] = fmt.Sprint(v)
+		}
+	}
+	if len(c.fields) > 0 {
+		for _, field := range c.fields {
+			if v, ok := fieldValue(r.Data[field]); ok {
+				p.fields[field] = v
+			}
+		}
+	} else {
+		for field, v := range r.Data {
+			if _, isTag := p.tags[field]; isTag || field == c.timeField || field == c.measurementField {
+				continue
+			}
+			if v, ok := fieldValue(v); ok {
+				if _, isString := v.(string); !isString {
+					p.fields[field] = v
+				}
+			}
+		}
+	}
+	if c.protocol == ProtocolPrometheus {
+		for field, v := range p.fields {
+			if _, ok := v.(string); ok {
+				delete(p.fields, field)
+			}
+		}
+	}
+
+	if c.timeField != "" {
+		t, err := c.parseTime(r.Data[c.timeField])
+		if err != nil {
+			return p, fmt.Errorf("%s: %w", c.timeField, err)
+		}
+		p.time = t
+	}
+	if p.time.IsZero() {
+		return p, fmt.Errorf("no timestamp")
+	}
+	return p, nil
+}
+
+// fieldValue converts a record value to a float64, bool or string field,
+// reporting false for values no store takes such as objects
+func fieldValue(v interface{}) (interface{}, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, !math.IsNaN(v) && !math.IsInf(v, 0)
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case bool:
+		return v, true
+	case string:
+		return v, true
+	}
+	return nil, false
+}
+
+// parseTime reads a timestamp given as RFC 3339 text or a number in the
+// time unit
+func (c *Connector) parseTime(v interface{}) (time.Time, error) {
+	switch v := v.(type) {
+	case string:
+		if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return t, nil
+		}
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid timestamp %q", v)
+		}
+		return c.epoch(n), nil
+	case time.Time:
+		return v, nil
+	}
+	if n, ok := fieldValue(v); ok {
+		if f, isNumber := n.(float64); isNumber {
+			return c.epoch(f), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp %v", v)
+}
+
+// epoch converts a number of time units since the epoch to a time
+func (c *Connector) epoch(n float64) time.Time {
+	return time.Unix(0, int64(n*float64(c.timeUnit))).UTC()
+}
+
+// seriesKey identifies the series of a point and field
+func seriesKey(p point, field string) string {
+	keys := make([]string, 0, len(p.tags))
+	for k := range p.tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	b.WriteString(p.measurement)
+	for _, k := range keys {
+		b.WriteString("\x00" + k + "=" + p.tags[k])
+	}
+	b.WriteString("\x00\x00" + field)
+	return b.String()
+}
+
+// order sorts points by time and, with out_of_order drop, removes the
+// fields older than the newest point written for their series. Points
+// left without fields are dropped.
+func (c *Connector) order(points []point) []point {
+	sort.SliceStable(points, func(i, j int) bool { return points[i].time.Before(points[j].time) })
+	if c.outOfOrder != OutOfOrderDrop {
+		return points
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	dropped := 0
+	out := points[:0]
+	for _, p := range points {
+		for field := range p.fields {
+			if last, ok := c.last[seriesKey(p, field)]; ok && p.time.Before(last) {
+				delete(p.fields, field)
+				dropped++
+			}
+		}
+		if len(p.fields) > 0 {
+			out = append(out, p)
+		}
+	}
+	if dropped > 0 {
+		log.Printf("[TimeSeries] Dropped %d out-of-order values older than the last written for their series", dropped)
+		c.count("out_of_order_dropped", float64(dropped))
+	}
+	return out
+}
+
+// written records the newest time written per series
+func (c *Connector) written(points []point) {
+	if c.outOfOrder != OutOfOrderDrop {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, p := range points {
+		for field := range p.fields {
+			key := seriesKey(p, field)
+			if p.time.After(c.last[key]) {
+				c.last[key] = p.time
+			}
+		}
+	}
+}
+
+// write sends one batch of points
+func (c *Connector) write(ctx context.Context, points []point) error {
+	var body []byte
+	contentType := "text/plain; charset=utf-8"
+	headers := map[string]string{}
+	switch c.protocol {
+	case ProtocolInflux:
+		body = lineProtocol(points)
+	case ProtocolPrometheus:
+		body = snappyEncode(remoteWriteRequest(points))
+		contentType = "application/x-protobuf"
+		headers["Content-Encoding"] = "snappy"
+		headers["X-Prometheus-Remote-Write-Version"] = "0.1.0"
+	}
+
+	release, err := connectors.Acquire(ctx, c.limiter)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	switch {
+	case c.token != "" && c.protocol == ProtocolInflux:
+		req.Header.Set("Authorization", "Token "+c.token)
+	case c.token != "":
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	case c.username != "":
+		req.SetBasicAuth(c.username, c.password)
+	}
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+
+	start := time.Now()
+	resp, err := c.http.Do(req)
+	if c.metrics != nil {
+		c.metrics.Observe("write_seconds", time.Since(start).Seconds())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %d points: %w", len(points), err)
+	}
+	defer resp.Body.Close()
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if resp.StatusCode/100 != 2 {
+		detail := strings.TrimSpace(string(msg))
+		if c.outOfOrder == OutOfOrderDrop && resp.StatusCode == http.StatusBadRequest && rejectedLate(detail) {
+			// The store holds newer samples than this process wrote, such
+			// as after a restart; the batch cannot be written as is
+			log.Printf("[TimeSeries] Store rejected %d points as out of order, dropping them: %s", len(points), detail)
+			c.count("out_of_order_dropped", float64(len(points)))
+			return nil
+		}
+		return fmt.Errorf("timeseries write of %d points returned %s: %s", len(points), resp.Status, detail)
+	}
+	c.written(points)
+	c.count("points_written", float64(len(points)))
+	return nil
+}
+
+// rejectedLate reports whether a store refused a write for holding newer
+// or conflicting samples
+func rejectedLate(detail string) bool {
+	detail = strings.ToLower(detail)
+	for _, s := range []string{"out of order", "out-of-order", "too old", "duplicate sample"} {
+		if strings.Contains(detail, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// ListChanges implements connectors.Connector; the connector is a target
+// only
+func (c *Connector) ListChanges(ctx context.Context, checkpoint *connectors.Checkpoint) ([]connectors.Record, error) {
+	return nil, fmt.Errorf("timeseries connector cannot be a source: %w", connectors.ErrUnsupported)
+}
+
+// GetLatestCheckpoint implements connectors.Connector
+func (c *Connector) GetLatestCheckpoint(ctx context.Context) (*connectors.Checkpoint, error) {
+	return nil, nil
+}
+
+// Validate implements connectors.Connector. Records need a measurement and
+// a readable timestamp.
+func (c *Connector) Validate(ctx context.Context, record connectors.Record) connectors.ValidationResult {
+	if record.Operation == connectors.OperationDelete {
+		return connectors.ValidationResult{IsValid: true}
+	}
+	if _, err := c.point(record); err != nil {
+		return connectors.ValidationResult{Errors: []string{err.Error()}}
+	}
+	return connectors.ValidationResult{IsValid: true}
+}
+
+// ResolveConflict implements connectors.Connector
+func (c *Connector) ResolveConflict(ctx context.Context, existing, incoming connectors.Record) (connectors.Record, error) {
+	winner, _ := c.resolver.Resolve(existing, incoming)
+	return winner, nil
+}
@@ -0,0 +1,293 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: migration-cutover
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Quiesce-and-Cutover Migration Workflow
+ */
+
+package cutover
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/egress"
+	"github.com/machine-native-ops/esync-platform/internal/engine"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+	"github.com/machine-native-ops/esync-platform/internal/state"
+)
+
+const maxLagChecks = 5
+
+// Phase is a step of the cutover state machine
+type Phase string
+
+// Cutover phases in execution order
+const (
+	PhaseDraining    Phase = "draining"
+	PhaseVerifying   Phase = "verifying_lag"
+	PhaseReconciling Phase = "reconciling"
+	PhaseSignaling   Phase = "signaling"
+	PhaseReady       Phase = "ready"
+	PhaseFailed      Phase = "failed"
+)
+
+// Terminal reports whether the phase ends the workflow
+func (p Phase) Terminal() bool {
+	return p == PhaseReady || p == PhaseFailed
+}
+
+// State is the persisted progress of a cutover
+type State struct {
+	PipelineID     string                  `json:"pipeline_id"`
+	Phase          Phase                   `json:"phase"`
+	Ready          bool                    `json:"ready"`
+	DrainedRecords int                     `json:"drained_records"`
+	LagChecks      int                     `json:"lag_checks"`
+	Reconcile      *engine.ReconcileReport `json:"reconcile,omitempty"`
+	Error          string                  `json:"error,omitempty"`
+	StartedAt      time.Time               `json:"started_at"`
+	UpdatedAt      time.Time               `json:"updated_at"`
+}
+
+// Orchestrator drives cutover state machines and resumes them after restarts
+type Orchestrator struct {
+	registry *registry.Service
+	engine   *engine.Engine
+	store    *state.Store
+	client   *http.Client
+
+	mu     sync.Mutex
+	active map[string]bool
+}
+
+// NewOrchestrator creates a new cutover orchestrator
+func NewOrchestrator(reg *registry.Service, eng *engine.Engine, store *state.Store) *Orchestrator {
+	return &Orchestrator{
+		registry: reg,
+		engine:   eng,
+		store:    store,
+		client:   egress.Client(10 * time.Second),
+		active:   make(map[string]bool),
+	}
+}
+
+// Start begins a cutover for a migration pipeline, continuing an unfinished
+// one if it exists
+func (o *Orchestrator) Start(ctx context.Context, pipelineID string) (*State, error) {
+	p, err := o.registry.GetByID(pipelineID)
+	if err != nil {
+		return nil, err
+	}
+	if p.Mode != registry.ModeMigration {
+		return nil, fmt.Errorf("pipeline %s is not a migration pipeline", pipelineID)
+	}
+
+	st, err := o.Status(pipelineID)
+	if err != nil {
+		return nil, err
+	}
+	if st == nil || st.Phase.Terminal() {
+		now := time.Now().UTC()
+		st = &State{PipelineID: pipelineID, Phase: PhaseDraining, StartedAt: now, UpdatedAt: now}
+		if err := o.save(st); err != nil {
+			return nil, err
+		}
+	}
+
+	if !o.launch(ctx, st) {
+		return nil, fmt.Errorf("cutover for pipeline %s already in progress", pipelineID)
+	}
+
+	return st, nil
+}
+
+// Resume restarts every unfinished cutover found in the state store
+func (o *Orchestrator) Resume(ctx context.Context) error {
+	keys, err := o.store.Keys("cutover")
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		var st State
+		if _, err := o.store.Load(key, &st); err != nil {
+			return err
+		}
+		if st.Phase.Terminal() {
+			continue
+		}
+		log.Printf("[Cutover] Resuming cutover for pipeline %s in phase %s", st.PipelineID, st.Phase)
+		o.launch(ctx, &st)
+	}
+
+	return nil
+}
+
+// Status returns the current cutover state of a pipeline, or nil if none exists
+func (o *Orchestrator) Status(pipelineID string) (*State, error) {
+	var st State
+	found, err := o.store.Load("cutover/"+pipelineID, &st)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	return &st, nil
+}
+
+// launch starts the state machine goroutine unless one is already active
+func (o *Orchestrator) launch(ctx context.Context, st *State) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.active[st.PipelineID] {
+		return false
+	}
+	o.active[st.PipelineID] = true
+
+	snapshot := *st
+	go o.run(ctx, &snapshot)
+	return true
+}
+
+// run advances the state machine until it reaches a terminal phase. If the
+// daemon shuts down mid-phase the persisted state is left for Resume.
+func (o *Orchestrator) run(ctx context.Context, st *State) {
+	defer func() {
+		o.mu.Lock()
+		delete(o.active, st.PipelineID)
+		o.mu.Unlock()
+	}()
+
+	for !st.Phase.Terminal() {
+		if err := o.step(ctx, st); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("[Cutover] Pipeline %s failed in phase %s: %v", st.PipelineID, st.Phase, err)
+			st.Phase = PhaseFailed
+			st.Error = err.Error()
+		}
+
+		st.UpdatedAt = time.Now().UTC()
+		if err := o.save(st); err != nil {
+			log.Printf("[Cutover] Failed to persist state for pipeline %s: %v", st.PipelineID, err)
+			return
+		}
+	}
+}
+
+// step executes the current phase and moves to the next one
+func (o *Orchestrator) step(ctx context.Context, st *State) error {
+	p, err := o.registry.GetByID(st.PipelineID)
+	if err != nil {
+		return err
+	}
+	spec := p.Cutover
+	if spec == nil {
+		spec = &registry.CutoverSpec{}
+	}
+
+	switch st.Phase {
+	case PhaseDraining:
+		passes := spec.MaxDrainPasses
+		if passes <= 0 {
+			passes = registry.DefaultMaxDrainPasses
+		}
+		n, err := o.engine.Drain(ctx, st.PipelineID, passes)
+		st.DrainedRecords += n
+		if err != nil {
+			return fmt.Errorf("drain failed: %w", err)
+		}
+		st.Phase = PhaseVerifying
+
+	case PhaseVerifying:
+		caughtUp, err := o.engine.CaughtUp(ctx, st.PipelineID)
+		if err != nil {
+			return fmt.Errorf("lag check failed: %w", err)
+		}
+		st.LagChecks++
+		switch {
+		case caughtUp:
+			st.Phase = PhaseReconciling
+		case st.LagChecks >= maxLagChecks:
+			return fmt.Errorf("source did not quiesce after %d lag checks", st.LagChecks)
+		default:
+			st.Phase = PhaseDraining
+		}
+
+	case PhaseReconciling:
+		if spec.SkipReconcile {
+			st.Phase = PhaseSignaling
+			return nil
+		}
+		report, err := o.engine.Reconcile(ctx, st.PipelineID)
+		if err != nil {
+			return fmt.Errorf("reconciliation failed: %w", err)
+		}
+		st.Reconcile = report
+		if !report.Consistent() {
+			return fmt.Errorf("reconciliation found %d missing and %d extra keys", len(report.Missing), len(report.Extra))
+		}
+		st.Phase = PhaseSignaling
+
+	case PhaseSignaling:
+		st.Ready = true
+		if spec.Webhook != "" {
+			if err := o.notify(ctx, spec.Webhook, st); err != nil {
+				st.Ready = false
+				return fmt.Errorf("readiness webhook failed: %w", err)
+			}
+		}
+		st.Phase = PhaseReady
+		log.Printf("[Cutover] Pipeline %s is ready for application cutover", st.PipelineID)
+	}
+
+	return nil
+}
+
+// notify posts the readiness signal to the configured webhook
+func (o *Orchestrator) notify(ctx context.Context, url string, st *State) error {
+	body, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// save persists the cutover state
+func (o *Orchestrator) save(st *State) error {
+	return o.store.Save("cutover/"+st.PipelineID, st)
+}
@@ -0,0 +1,231 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: egress-control
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Outbound Proxy and Egress Allowlist
+ */
+
+// Package egress routes outbound connections through HTTP or SOCKS5 proxies
+// and enforces an allowlist of destination hosts. The daemon installs its
+// policy with SetDefault; HTTP clients from Client and in-process connectors
+// dialing with Default().DialContext follow it. Plugin processes receive the
+// policy as proxy environment variables.
+package egress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/fips"
+)
+
+// ErrDenied is returned when a destination is not on the egress allowlist
+var ErrDenied = errors.New("egress denied")
+
+// Policy controls outbound connections
+type Policy struct {
+	// Proxy is an http, https or socks5 URL outbound traffic goes through;
+	// empty connects directly
+	Proxy string
+	// NoProxy lists hosts, *.domain wildcards or CIDR ranges reached
+	// without the proxy
+	NoProxy []string
+	// Allow lists the hosts, *.domain wildcards or CIDR ranges outbound
+	// connections may reach; empty allows every destination
+	Allow []string
+}
+
+// Validate checks the proxy URL and host patterns
+func (p *Policy) Validate() error {
+	if p.Proxy != "" {
+		u, err := url.Parse(p.Proxy)
+		if err != nil {
+			return fmt.Errorf("invalid proxy %s: %w", p.Proxy, err)
+		}
+		switch u.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return fmt.Errorf("unsupported proxy scheme %q", u.Scheme)
+		}
+		if u.Host == "" {
+			return fmt.Errorf("proxy %s has no host", p.Proxy)
+		}
+	}
+	for _, pattern := range append(append([]string{}, p.NoProxy...), p.Allow...) {
+		if strings.Contains(pattern, "/") {
+			if _, _, err := net.ParseCIDR(pattern); err != nil {
+				return fmt.Errorf("invalid CIDR %s: %w", pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+// WithProxy returns a copy of the policy using proxy, keeping the
+// allowlist; an empty proxy keeps the current one
+func (p *Policy) WithProxy(proxy string) *Policy {
+	out := *p
+	if proxy != "" {
+		out.Proxy = proxy
+	}
+	return &out
+}
+
+// Allowed reports whether host may be reached
+func (p *Policy) Allowed(host string) bool {
+	return len(p.Allow) == 0 || matchAny(p.Allow, host)
+}
+
+// DialContext connects to addr through the proxy when one is set, after
+// checking the destination against the allowlist
+func (p *Policy) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	if !p.Allowed(host) {
+		return nil, fmt.Errorf("%s: %w", host, ErrDenied)
+	}
+
+	proxy, _ := url.Parse(p.Proxy)
+	if proxy != nil && strings.HasPrefix(proxy.Scheme, "socks5") && !matchAny(p.NoProxy, host) {
+		return dialSOCKS5(ctx, proxy, addr)
+	}
+	var d net.Dialer
+	return d.DialContext(ctx, network, addr)
+}
+
+// proxyFor returns the HTTP proxy of a request, refusing destinations off
+// the allowlist
+func (p *Policy) proxyFor(req *http.Request) (*url.URL, error) {
+	host := req.URL.Hostname()
+	if !p.Allowed(host) {
+		return nil, fmt.Errorf("%s: %w", host, ErrDenied)
+	}
+	proxy, err := url.Parse(p.Proxy)
+	if p.Proxy == "" || err != nil || !strings.HasPrefix(proxy.Scheme, "http") || matchAny(p.NoProxy, host) {
+		return nil, err
+	}
+	return proxy, nil
+}
+
+// dialHTTP dials for the HTTP transport: connections to an HTTP proxy are
+// made directly, the destination having been checked by proxyFor
+func (p *Policy) dialHTTP(ctx context.Context, network, addr string) (net.Conn, error) {
+	if proxy, err := url.Parse(p.Proxy); err == nil && strings.HasPrefix(proxy.Scheme, "http") && addr == hostPort(proxy) {
+		var d net.Dialer
+		return d.DialContext(ctx, network, addr)
+	}
+	return p.DialContext(ctx, network, addr)
+}
+
+// Env returns the policy as environment variables for child processes
+func (p *Policy) Env() []string {
+	var env []string
+	if p.Proxy != "" {
+		env = append(env, "HTTP_PROXY="+p.Proxy, "HTTPS_PROXY="+p.Proxy, "ALL_PROXY="+p.Proxy)
+	}
+	if len(p.NoProxy) > 0 {
+		env = append(env, "NO_PROXY="+strings.Join(p.NoProxy, ","))
+	}
+	if len(p.Allow) > 0 {
+		env = append(env, "ESYNC_EGRESS_ALLOW="+strings.Join(p.Allow, ","))
+	}
+	return env
+}
+
+// matchAny reports whether host matches an exact host, a *.domain wildcard
+// or a CIDR range
+func matchAny(patterns []string, host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	ip := net.ParseIP(host)
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		switch {
+		case strings.Contains(pattern, "/"):
+			_, network, err := net.ParseCIDR(pattern)
+			if err == nil && ip != nil && network.Contains(ip) {
+				return true
+			}
+		case strings.HasPrefix(pattern, "*."):
+			if strings.HasSuffix(host, pattern[1:]) {
+				return true
+			}
+		case pattern == host:
+			return true
+		}
+	}
+	return false
+}
+
+// hostPort returns the host:port of a proxy URL with the scheme's default
+// port
+func hostPort(u *url.URL) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	port := "80"
+	switch u.Scheme {
+	case "https":
+		port = "443"
+	case "socks5", "socks5h":
+		port = "1080"
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+var current atomic.Pointer[Policy]
+
+// SetDefault installs the daemon-wide policy, dropping pooled connections
+// opened under the previous one. It also applies the FIPS TLS restrictions
+// when FIPS mode is on, so it is called at startup before any request.
+func SetDefault(p *Policy) {
+	current.Store(p)
+	transport.TLSClientConfig = fips.TLSConfig(nil)
+	transport.CloseIdleConnections()
+}
+
+// Default returns the daemon-wide policy, which allows direct connections
+// to every host until SetDefault is called
+func Default() *Policy {
+	if p := current.Load(); p != nil {
+		return p
+	}
+	return &Policy{}
+}
+
+// transport follows the policy installed at the time of each request
+var transport = &http.Transport{
+	Proxy: func(req *http.Request) (*url.URL, error) {
+		return Default().proxyFor(req)
+	},
+	DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return Default().dialHTTP(ctx, network, addr)
+	},
+	ForceAttemptHTTP2:     true,
+	MaxIdleConns:          100,
+	IdleConnTimeout:       90 * time.Second,
+	TLSHandshakeTimeout:   10 * time.Second,
+	ExpectContinueTimeout: time.Second,
+}
+
+// Client returns an HTTP client following the daemon-wide policy
+func Client(timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
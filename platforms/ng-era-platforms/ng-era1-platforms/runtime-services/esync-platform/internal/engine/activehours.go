@@ -0,0 +1,102 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: active-hours
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Active Hours Pause and Resume
+ */
+
+package engine
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// pauseReasonHours marks pipelines paused outside their active hours
+const pauseReasonHours = "active_hours"
+
+// activeHoursInterval is how often active hours are applied
+const activeHoursInterval = 30 * time.Second
+
+// ActiveHoursStatus tells whether a pipeline is inside its active hours
+type ActiveHoursStatus struct {
+	Active   bool   `json:"active"`
+	Timezone string `json:"timezone"`
+	// NextChange is when the pipeline is next resumed or paused
+	NextChange time.Time `json:"next_change,omitempty"`
+}
+
+// WatchActiveHours pauses pipelines outside their active hours and resumes
+// them when a window opens, until ctx is cancelled. Operator pauses and
+// pauses of other features are left alone.
+func (e *Engine) WatchActiveHours(ctx context.Context) {
+	ticker := time.NewTicker(activeHoursInterval)
+	defer ticker.Stop()
+
+	for {
+		e.applyActiveHours(time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// applyActiveHours pauses or resumes every pipeline for its active hours
+// at now
+func (e *Engine) applyActiveHours(now time.Time) {
+	for _, p := range e.registry.GetAll() {
+		active := true
+		if p.ActiveHours != nil {
+			cal, err := p.ActiveHours.Calendar()
+			if err != nil {
+				continue
+			}
+			active = cal.Active(now)
+		}
+
+		// Pipelines whose active hours were removed are resumed too
+		var err error
+		if active {
+			err = e.resumeFor(p.ID, pauseReasonHours)
+		} else {
+			err = e.pauseFor(p.ID, pauseReasonHours)
+		}
+		if err != nil {
+			log.Printf("[Engine] Failed to apply active hours of pipeline %s: %v", p.ID, err)
+		}
+	}
+}
+
+// ActiveHours returns whether a pipeline is inside its active hours, or nil
+// for pipelines without active hours
+func (e *Engine) ActiveHours(p *registry.Pipeline) *ActiveHoursStatus {
+	if p.ActiveHours == nil {
+		return nil
+	}
+	cal, err := p.ActiveHours.Calendar()
+	if err != nil {
+		return nil
+	}
+
+	now := time.Now()
+	status := &ActiveHoursStatus{Active: cal.Active(now), Timezone: cal.Location().String()}
+	if next := cal.Next(now); !next.IsZero() {
+		status.NextChange = next.UTC()
+	}
+	return status
+}
@@ -0,0 +1,516 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: edge-agent
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Edge Agent Spooling and Beacons
+ */
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// Defaults of the agent profile
+const (
+	DefaultAgentClockSkew = 5 * time.Minute
+	DefaultBeaconInterval = time.Minute
+)
+
+// beaconsMissed is how many beacon intervals an agent may stay silent
+// before it is reported offline
+const beaconsMissed = 3
+
+// ErrInvalidAgentID is returned for beacons of agents with an ID unfit for
+// the state store
+var ErrInvalidAgentID = errors.New("invalid agent ID")
+
+// agentIDPattern matches the IDs agents may report under
+var agentIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,127}$`)
+
+// AgentConfig tunes the engine for edge nodes with intermittent
+// connectivity
+type AgentConfig struct {
+	// ID names the agent in its beacons
+	ID string
+	// SpoolMaxBytes bounds the local spool of each pipeline; sources are
+	// not read while a spool is full. Zero leaves spools unbounded.
+	SpoolMaxBytes int64
+	// ClockSkew is how far the node clock may be off. Time checkpoints
+	// further ahead of the clock are rewound before listing.
+	ClockSkew time.Duration
+	// Labels describe the agent to a fleet manager, which assigns it
+	// pipelines by them
+	Labels map[string]string
+	// Bandwidth caps the bytes the pipelines of the agent write together
+	Bandwidth *registry.BandwidthSpec
+}
+
+// spoolSegment holds the records one pass listed, waiting for upload
+type spoolSegment struct {
+	Position string              `json:"position,omitempty"`
+	Records  []connectors.Record `json:"records"`
+}
+
+// spoolCursor is how far the upload of a segment got, so an interrupted
+// upload resumes after the last chunk written
+type spoolCursor struct {
+	Segment string `json:"segment"`
+	Offset  int    `json:"offset"`
+}
+
+// spoolStats counts what the spool of a pipeline holds
+type spoolStats struct {
+	records int
+	bytes   int64
+	// next is the sequence number of the next segment
+	next    uint64
+	offline bool
+	err     string
+}
+
+// SpoolStatus is the local spool of a pipeline in agent mode
+type SpoolStatus struct {
+	PipelineID string `json:"pipeline_id"`
+	Records    int    `json:"records"`
+	Bytes      int64  `json:"bytes"`
+	// Full is set while the spool is at its size limit and the source is
+	// not read
+	Full bool `json:"full,omitempty"`
+	// Offline is set while the last upload could not reach the target
+	Offline bool   `json:"offline,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// AgentBeacon is the compact status an agent posts to a central daemon
+type AgentBeacon struct {
+	AgentID string    `json:"agent_id"`
+	Version string    `json:"version,omitempty"`
+	SentAt  time.Time `json:"sent_at"`
+	// Interval is the number of seconds between beacons
+	Interval float64 `json:"interval,omitempty"`
+	// ClockOffset is how many seconds the agent clock was behind the
+	// central one at the previous beacon
+	ClockOffset float64           `json:"clock_offset,omitempty"`
+	Pipelines   []BeaconPipeline  `json:"pipelines"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	// Revision is the fleet revision of the pipelines the agent applied
+	Revision string `json:"revision,omitempty"`
+	// Versions acknowledges the version of each fleet pipeline the agent
+	// applied
+	Versions map[string]uint64 `json:"versions,omitempty"`
+}
+
+// BeaconPipeline is the state of one agent pipeline in a beacon
+type BeaconPipeline struct {
+	ID      string    `json:"id"`
+	Status  string    `json:"status,omitempty"`
+	LastRun time.Time `json:"last_run,omitempty"`
+	Error   string    `json:"error,omitempty"`
+	Spooled int       `json:"spooled,omitempty"`
+	// SpoolBytes is the size of the local spool
+	SpoolBytes int64 `json:"spool_bytes,omitempty"`
+	Offline    bool  `json:"offline,omitempty"`
+	// Records is the number of records the last run wrote
+	Records int `json:"records,omitempty"`
+}
+
+// AgentStatus is the last beacon a central daemon received from an agent
+type AgentStatus struct {
+	AgentBeacon
+	ReceivedAt time.Time `json:"received_at"`
+	// Online is set while beacons keep arriving
+	Online bool `json:"online"`
+	// Assigned is the fleet revision of the pipelines assigned to the
+	// agent, when this daemon manages a fleet
+	Assigned string `json:"assigned,omitempty"`
+	// Pending counts the fleet pipelines the agent has yet to update or
+	// remove
+	Pending int `json:"pending,omitempty"`
+}
+
+// SetAgent runs the engine as an edge agent. Every pass spools the listed
+// records locally and advances the checkpoint before uploading the spool
+// to the target, so sources keep being read while the target is
+// unreachable and uploads resume where they stopped once it is back.
+func (e *Engine) SetAgent(cfg AgentConfig) {
+	e.agentMu.Lock()
+	defer e.agentMu.Unlock()
+
+	e.agent = &cfg
+}
+
+// Agent returns the agent profile, or nil when the engine is not an agent
+func (e *Engine) Agent() *AgentConfig {
+	e.agentMu.Lock()
+	defer e.agentMu.Unlock()
+
+	if e.agent == nil {
+		return nil
+	}
+	cfg := *e.agent
+	return &cfg
+}
+
+// skewTolerant rewinds a time checkpoint further ahead of now than the
+// clock skew, as left behind by a clock that ran fast and was corrected,
+// to the skew before now. Listing from it would skip every change made
+// until the clock catches up.
+func (cfg *AgentConfig) skewTolerant(pipelineID string, checkpoint *connectors.Checkpoint, now time.Time) *connectors.Checkpoint {
+	if checkpoint == nil || cfg.ClockSkew <= 0 {
+		return checkpoint
+	}
+	t, err := time.Parse(time.RFC3339Nano, checkpoint.Position)
+	if err != nil || !t.After(now.Add(cfg.ClockSkew)) {
+		return checkpoint
+	}
+
+	rewound := *checkpoint
+	rewound.Position = now.Add(-cfg.ClockSkew).UTC().Format(time.RFC3339Nano)
+	log.Printf("[Engine] Checkpoint %s of pipeline %s is ahead of the clock, listing from %s", checkpoint.Position, pipelineID, rewound.Position)
+	return &rewound
+}
+
+// spoolKey is the state key of the spool of a pipeline
+func spoolKey(pipelineID string) string {
+	return "spool/" + pipelineID
+}
+
+// spoolCursorKey is the state key of the upload cursor of a pipeline
+func spoolCursorKey(pipelineID string) string {
+	return "spool-cursors/" + pipelineID
+}
+
+// spoolLocked returns the spool statistics of a pipeline, counting the
+// stored segments the first time; e.agentMu must be held
+func (e *Engine) spoolLocked(pipelineID string) (*spoolStats, error) {
+	if st, ok := e.spools[pipelineID]; ok {
+		return st, nil
+	}
+
+	keys, err := e.store.Keys(spoolKey(pipelineID))
+	if err != nil {
+		return nil, err
+	}
+	var cursor spoolCursor
+	if _, err := e.store.Load(spoolCursorKey(pipelineID), &cursor); err != nil {
+		return nil, err
+	}
+	st := &spoolStats{}
+	for _, key := range keys {
+		var seg spoolSegment
+		if _, err := e.store.Load(key, &seg); err != nil {
+			return nil, err
+		}
+		size, err := e.store.Size(key)
+		if err != nil {
+			return nil, err
+		}
+		st.records += len(seg.Records)
+		if key == cursor.Segment {
+			st.records -= cursor.Offset
+		}
+		st.bytes += size
+		seq, _ := strconv.ParseUint(key[strings.LastIndex(key, "/")+1:], 10, 64)
+		st.next = seq + 1
+	}
+	e.spools[pipelineID] = st
+	return st, nil
+}
+
+// SpoolStatus returns the local spool of a pipeline
+func (e *Engine) SpoolStatus(pipelineID string) (*SpoolStatus, error) {
+	e.agentMu.Lock()
+	defer e.agentMu.Unlock()
+
+	st, err := e.spoolLocked(pipelineID)
+	if err != nil {
+		return nil, err
+	}
+	status := &SpoolStatus{
+		PipelineID: pipelineID,
+		Records:    st.records,
+		Bytes:      st.bytes,
+		Offline:    st.offline,
+		Error:      st.err,
+	}
+	if e.agent != nil && e.agent.SpoolMaxBytes > 0 {
+		status.Full = st.bytes >= e.agent.SpoolMaxBytes
+	}
+	return status, nil
+}
+
+// spoolRecords appends the records of a pass to the spool of a pipeline
+func (e *Engine) spoolRecords(pipelineID, position string, records []connectors.Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+	e.agentMu.Lock()
+	defer e.agentMu.Unlock()
+
+	st, err := e.spoolLocked(pipelineID)
+	if err != nil {
+		return err
+	}
+	key := fmt.Sprintf("%s/%020d", spoolKey(pipelineID), st.next)
+	if err := e.store.Save(key, &spoolSegment{Position: position, Records: records}); err != nil {
+		return fmt.Errorf("failed to spool records: %w", err)
+	}
+	size, err := e.store.Size(key)
+	if err != nil {
+		return err
+	}
+	st.next++
+	st.records += len(records)
+	st.bytes += size
+	e.spoolGauges(pipelineID, st)
+	return nil
+}
+
+// spoolUploaded removes uploaded records and deleted segment bytes from
+// the spool statistics
+func (e *Engine) spoolUploaded(pipelineID string, records int, bytes int64) {
+	e.agentMu.Lock()
+	defer e.agentMu.Unlock()
+
+	if st, ok := e.spools[pipelineID]; ok {
+		st.records -= records
+		st.bytes -= bytes
+		e.spoolGauges(pipelineID, st)
+	}
+}
+
+// spoolGauges exports the size of a spool; e.agentMu must be held
+func (e *Engine) spoolGauges(pipelineID string, st *spoolStats) {
+	m := pipelineMetrics{engine: e, pipelineID: pipelineID}
+	m.Gauge("spool_records", float64(st.records))
+	m.Gauge("spool_bytes", float64(st.bytes))
+}
+
+// spoolFull reports whether the spool of a pipeline is at its size limit
+func (e *Engine) spoolFull(pipelineID string, cfg *AgentConfig) (bool, error) {
+	if cfg.SpoolMaxBytes <= 0 {
+		return false, nil
+	}
+	status, err := e.SpoolStatus(pipelineID)
+	if err != nil {
+		return false, err
+	}
+	return status.Full, nil
+}
+
+// uploadSpool uploads the spool of a pipeline to its target. A target
+// that cannot be reached leaves the records spooled without failing the
+// run; fresh is the number of records the pass itself spooled.
+func (e *Engine) uploadSpool(ctx context.Context, p *registry.Pipeline, target connectors.Connector, tracker *progressTracker, fresh int) (int, error) {
+	applied, err := e.flushSpool(ctx, p, target, tracker, fresh)
+	offline := err != nil && ctx.Err() == nil && unreachable(err)
+
+	e.agentMu.Lock()
+	if st, ok := e.spools[p.ID]; ok {
+		st.offline, st.err = offline, ""
+		if err != nil {
+			st.err = err.Error()
+		}
+	}
+	e.agentMu.Unlock()
+
+	if offline {
+		e.recordError(p.ID, "target", err)
+		log.Printf("[Engine] Target of pipeline %s is unreachable, records stay spooled: %v", p.ID, err)
+		return applied, nil
+	}
+	return applied, err
+}
+
+// flushSpool applies the spooled segments of a pipeline oldest first in
+// chunks of the batch size, recording a cursor after each chunk and
+// deleting each segment once applied
+func (e *Engine) flushSpool(ctx context.Context, p *registry.Pipeline, target connectors.Connector, tracker *progressTracker, fresh int) (int, error) {
+	keys, err := e.store.Keys(spoolKey(p.ID))
+	if err != nil || len(keys) == 0 {
+		return 0, err
+	}
+	var cursor spoolCursor
+	if _, err := e.store.Load(spoolCursorKey(p.ID), &cursor); err != nil {
+		return 0, err
+	}
+	status, err := e.SpoolStatus(p.ID)
+	if err != nil {
+		return 0, err
+	}
+	if backlog := status.Records - fresh; backlog > 0 {
+		tracker.discover(int64(backlog))
+	}
+
+	chunk := e.batchSize(p)
+	applied := 0
+	for _, key := range keys {
+		var seg spoolSegment
+		if _, err := e.store.Load(key, &seg); err != nil {
+			return applied, err
+		}
+		offset := 0
+		if key == cursor.Segment {
+			offset = cursor.Offset
+		}
+		for offset < len(seg.Records) {
+			end := offset + chunk
+			if end > len(seg.Records) {
+				end = len(seg.Records)
+			}
+			n, err := e.applyStaged(ctx, p, target, seg.Records[offset:end], tracker, "spool:", seg.Position)
+			applied += n
+			if err != nil {
+				return applied, err
+			}
+			cursor = spoolCursor{Segment: key, Offset: end}
+			if err := e.store.Save(spoolCursorKey(p.ID), &cursor); err != nil {
+				return applied, err
+			}
+			e.spoolUploaded(p.ID, end-offset, 0)
+			offset = end
+		}
+
+		size, err := e.store.Size(key)
+		if err != nil {
+			return applied, err
+		}
+		if err := e.store.Delete(key); err != nil {
+			return applied, err
+		}
+		e.spoolUploaded(p.ID, 0, size)
+	}
+	return applied, e.store.Delete(spoolCursorKey(p.ID))
+}
+
+// unreachable reports whether an error means the target could not be
+// reached rather than that it refused the records
+func unreachable(err error) bool {
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &opErr), errors.As(err, &dnsErr):
+		return true
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return true
+	}
+	return errors.Is(err, context.DeadlineExceeded)
+}
+
+// ResumeSpools runs every pipeline with spooled records, as when the
+// agent gets back online
+func (e *Engine) ResumeSpools(ctx context.Context) {
+	for _, p := range e.registry.GetAll() {
+		status, err := e.SpoolStatus(p.ID)
+		if err != nil || status.Records == 0 {
+			continue
+		}
+		go func(id string) {
+			if _, err := e.RunOnce(ctx, id, Trigger{Type: TriggerReconnect}); err != nil {
+				log.Printf("[Engine] Failed to upload the spool of pipeline %s: %v", id, err)
+			}
+		}(p.ID)
+	}
+}
+
+// Beacon returns the compact status of the agent and its pipelines
+func (e *Engine) Beacon() *AgentBeacon {
+	b := &AgentBeacon{SentAt: time.Now().UTC(), Pipelines: []BeaconPipeline{}}
+	if cfg := e.Agent(); cfg != nil {
+		b.AgentID, b.Labels = cfg.ID, cfg.Labels
+	}
+	if fleet, err := e.FleetState(); err == nil {
+		b.Revision, b.Versions = fleet.Revision, fleet.Versions
+	}
+	for _, p := range e.registry.GetAll() {
+		bp := BeaconPipeline{ID: p.ID}
+		if run := e.LastRun(p.ID); run != nil {
+			bp.Status, bp.LastRun, bp.Error, bp.Records = run.Status, run.FinishedAt, run.Error, run.Records
+		}
+		if status, err := e.SpoolStatus(p.ID); err == nil {
+			bp.Spooled, bp.SpoolBytes, bp.Offline = status.Records, status.Bytes, status.Offline
+		}
+		b.Pipelines = append(b.Pipelines, bp)
+	}
+	return b
+}
+
+// RecordBeacon stores the beacon an agent posted to this daemon
+func (e *Engine) RecordBeacon(b *AgentBeacon) (*AgentStatus, error) {
+	if !agentIDPattern.MatchString(b.AgentID) {
+		return nil, fmt.Errorf("%w %q", ErrInvalidAgentID, b.AgentID)
+	}
+	status := &AgentStatus{AgentBeacon: *b, ReceivedAt: time.Now().UTC()}
+	if err := e.store.Save("agents/"+b.AgentID, status); err != nil {
+		return nil, err
+	}
+	status.Online = true
+	return status, nil
+}
+
+// AgentStatus returns the last beacon of an agent, or nil when it never
+// posted one
+func (e *Engine) AgentStatus(agentID string) (*AgentStatus, error) {
+	if !agentIDPattern.MatchString(agentID) {
+		return nil, nil
+	}
+	var status AgentStatus
+	found, err := e.store.Load("agents/"+agentID, &status)
+	if err != nil || !found {
+		return nil, err
+	}
+	status.Online = online(&status, time.Now())
+	return &status, nil
+}
+
+// Agents returns the last beacon of every agent reporting to this daemon
+func (e *Engine) Agents() ([]*AgentStatus, error) {
+	keys, err := e.store.Keys("agents")
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	agents := make([]*AgentStatus, 0, len(keys))
+	for _, key := range keys {
+		var status AgentStatus
+		if _, err := e.store.Load(key, &status); err != nil {
+			return nil, err
+		}
+		status.Online = online(&status, now)
+		agents = append(agents, &status)
+	}
+	return agents, nil
+}
+
+// online reports whether an agent posted a beacon within the last few
+// of its intervals
+func online(status *AgentStatus, now time.Time) bool {
+	interval := DefaultBeaconInterval
+	if status.Interval > 0 {
+		interval = time.Duration(status.Interval * float64(time.Second))
+	}
+	return now.Sub(status.ReceivedAt) <= beaconsMissed*interval
+}
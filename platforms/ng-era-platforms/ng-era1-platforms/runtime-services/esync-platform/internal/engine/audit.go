@@ -0,0 +1,123 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: audit-log
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Policy Decision Audit Log
+ */
+
+package engine
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// auditFile is the append-only audit log in the state directory, one JSON
+// entry per line
+const auditFile = "audit.log"
+
+// AuditEntry records a policy decision taken by the engine
+type AuditEntry struct {
+	Time       time.Time `json:"time"`
+	PipelineID string    `json:"pipeline_id,omitempty"`
+	// Policy names the policy evaluated, such as residency
+	Policy string `json:"policy"`
+	// Decision is allowed, warned or denied
+	Decision string `json:"decision"`
+	Detail   string `json:"detail,omitempty"`
+	// RunID is the run of the pipeline the entry was made in, and
+	// RequestID the correlation ID of the API request that triggered it
+	RunID     string `json:"run_id,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// Audit decisions
+const (
+	AuditAllowed = "allowed"
+	AuditWarned  = "warned"
+	AuditDenied  = "denied"
+)
+
+// audit appends an entry to the audit log; failures are logged because a
+// policy decision must not depend on the log being writable
+func (e *Engine) audit(entry AuditEntry) {
+	entry.Time = time.Now().UTC()
+	if entry.PipelineID != "" && entry.RunID == "" {
+		e.mu.RLock()
+		if run := e.active[entry.PipelineID]; run != nil {
+			entry.RunID, entry.RequestID = run.ID, run.Trigger.Metadata[CorrelationMetadata]
+		}
+		e.mu.RUnlock()
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		log.Printf("[Engine] Failed to encode audit entry: %v", err)
+		return
+	}
+
+	e.auditMu.Lock()
+	defer e.auditMu.Unlock()
+
+	f, err := os.OpenFile(filepath.Join(e.store.Dir(), auditFile), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o640)
+	if err == nil {
+		_, err = f.Write(append(data, '\n'))
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		log.Printf("[Engine] Failed to write audit entry: %v", err)
+	}
+}
+
+// AuditLog returns up to limit audit entries, newest first, optionally only
+// those of one pipeline
+func (e *Engine) AuditLog(pipelineID string, limit int) ([]AuditEntry, error) {
+	e.auditMu.Lock()
+	defer e.auditMu.Unlock()
+
+	f, err := os.Open(filepath.Join(e.store.Dir(), auditFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return []AuditEntry{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if pipelineID == "" || entry.PipelineID == pipelineID {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	out := make([]AuditEntry, 0, len(entries))
+	for i := len(entries) - 1; i >= 0 && (limit <= 0 || len(out) < limit); i-- {
+		out = append(out, entries[i])
+	}
+	return out, nil
+}
@@ -0,0 +1,149 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: autoscaling-signal
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Autoscaling Signal
+ */
+
+package engine
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// scalingTick is how often WatchAutoscaling publishes the signal
+const scalingTick = 15 * time.Second
+
+// DefaultScalingLag is the lag target of pipelines with neither a
+// freshness objective nor an interval schedule
+const DefaultScalingLag = 5 * time.Minute
+
+// PipelineLoad is the share of its lag target a pipeline is behind
+type PipelineLoad struct {
+	PipelineID string  `json:"pipeline_id"`
+	LagSeconds float64 `json:"lag_seconds"`
+	// LagTarget is the freshness objective of the pipeline, else twice its
+	// schedule interval, else DefaultScalingLag
+	LagTarget float64 `json:"lag_target_seconds"`
+	// QueueDepth counts the runs waiting for the one in progress
+	QueueDepth int  `json:"queue_depth"`
+	Running    bool `json:"running,omitempty"`
+	// Load is LagSeconds over LagTarget plus QueueDepth: 1 means the
+	// pipeline is at its lag target or has a run waiting
+	Load float64 `json:"load"`
+}
+
+// Autoscaling is the load of the daemon for external scalers such as KEDA
+// or an HPA on external metrics. Paused pipelines carry no load.
+type Autoscaling struct {
+	// Pipelines counts the pipelines that are not paused
+	Pipelines  int     `json:"pipelines"`
+	LagSeconds float64 `json:"lag_seconds"`
+	QueueDepth int     `json:"queue_depth"`
+	Running    int     `json:"running"`
+	// Load sums the load of the pipelines and PipelineLoad averages it
+	Load         float64 `json:"load"`
+	PipelineLoad float64 `json:"pipeline_load"`
+	// ScaleInSafe is set while no run is in progress or waiting and no
+	// handoff holds the pipelines, so the daemon may be stopped without
+	// cutting a run short
+	ScaleInSafe bool           `json:"scale_in_safe"`
+	Loads       []PipelineLoad `json:"loads"`
+	At          time.Time      `json:"at"`
+}
+
+// lagTarget returns the lag a pipeline may have at full load
+func lagTarget(p *registry.Pipeline) time.Duration {
+	switch {
+	case p.SLO != nil && p.SLO.Freshness > 0:
+		return time.Duration(p.SLO.Freshness) * time.Second
+	case p.Schedule != nil && p.Schedule.Interval > 0:
+		return 2 * time.Duration(p.Schedule.Interval) * time.Second
+	default:
+		return DefaultScalingLag
+	}
+}
+
+// Autoscaling returns the load of every pipeline that is not paused, by
+// descending load, and its aggregate
+func (e *Engine) Autoscaling() (*Autoscaling, error) {
+	out := &Autoscaling{Loads: []PipelineLoad{}, At: time.Now().UTC()}
+	for _, p := range e.registry.GetAll() {
+		paused, err := e.Paused(p.ID)
+		if err != nil {
+			return nil, err
+		}
+		if paused {
+			continue
+		}
+
+		load := PipelineLoad{PipelineID: p.ID, LagTarget: lagTarget(p).Seconds(), Running: e.Running(p.ID)}
+		if a := e.SourceActivity(p.ID); a != nil {
+			load.LagSeconds = a.LagSeconds
+		}
+		lock := e.lockFor(p.ID)
+		e.mu.Lock()
+		load.QueueDepth = lock.queued
+		if lock.pending != nil {
+			load.QueueDepth++
+		}
+		e.mu.Unlock()
+		load.Load = load.LagSeconds/load.LagTarget + float64(load.QueueDepth)
+
+		out.Pipelines++
+		out.LagSeconds = max(out.LagSeconds, load.LagSeconds)
+		out.QueueDepth += load.QueueDepth
+		if load.Running {
+			out.Running++
+		}
+		out.Load += load.Load
+		out.Loads = append(out.Loads, load)
+	}
+	if out.Pipelines > 0 {
+		out.PipelineLoad = out.Load / float64(out.Pipelines)
+	}
+	sort.SliceStable(out.Loads, func(i, j int) bool {
+		if out.Loads[i].Load != out.Loads[j].Load {
+			return out.Loads[i].Load > out.Loads[j].Load
+		}
+		return out.Loads[i].PipelineID < out.Loads[j].PipelineID
+	})
+
+	e.handoffMu.Lock()
+	handedOff := e.handoff != nil && (e.handoff.Status == HandoffDraining || e.handoff.Status == HandoffLeased)
+	e.handoffMu.Unlock()
+	out.ScaleInSafe = out.Running == 0 && out.QueueDepth == 0 && !handedOff
+	return out, nil
+}
+
+// WatchAutoscaling publishes the autoscaling signal as metrics every few
+// seconds until ctx is cancelled
+func (e *Engine) WatchAutoscaling(ctx context.Context) {
+	ticker := time.NewTicker(scalingTick)
+	defer ticker.Stop()
+
+	for {
+		if signal, err := e.Autoscaling(); err == nil {
+			e.monitor.RecordAutoscaling(signal.Load, signal.PipelineLoad, signal.QueueDepth, signal.ScaleInSafe)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
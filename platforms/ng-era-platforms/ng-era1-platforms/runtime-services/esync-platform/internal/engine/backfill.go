@@ -0,0 +1,410 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: chunked-backfill
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Resumable Chunked Backfills
+ */
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// ChunkState tracks one key range of a backfill
+type ChunkState struct {
+	Range   connectors.KeyRange `json:"range"`
+	Done    bool                `json:"done"`
+	Records int                 `json:"records"`
+}
+
+// BackfillState is the persisted progress of a backfill
+type BackfillState struct {
+	PipelineID string `json:"pipeline_id"`
+	// Position is the source position captured before the first chunk was
+	// read; incremental sync resumes from it once every chunk is copied
+	Position    *connectors.Checkpoint `json:"position,omitempty"`
+	Chunks      []ChunkState           `json:"chunks"`
+	StartedAt   time.Time              `json:"started_at"`
+	CompletedAt time.Time              `json:"completed_at,omitempty"`
+	Error       string                 `json:"error,omitempty"`
+	// Budget is the volume copied in the current period of a backfill
+	// paced by a budget
+	Budget *BudgetWindow `json:"budget,omitempty"`
+}
+
+// BudgetWindow tracks the records a paced backfill copied in the current
+// budget period
+type BudgetWindow struct {
+	Start   time.Time `json:"start"`
+	Records int       `json:"records"`
+	// PausedUntil is set while the budget of the period is spent
+	PausedUntil time.Time `json:"paused_until,omitempty"`
+}
+
+// Completed reports whether every chunk has been copied
+func (b *BackfillState) Completed() bool {
+	return !b.CompletedAt.IsZero()
+}
+
+// StartBackfill plans a chunked backfill, or continues an unfinished one, and
+// runs it in the background under the pipeline run lock. It fails like a
+// run would while the pipeline is paused, handed off or failing its
+// pre-flight checks.
+func (e *Engine) StartBackfill(ctx context.Context, pipelineID string) (*BackfillState, error) {
+	p, err := e.registry.GetByID(pipelineID)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.admit(p, Trigger{Type: TriggerBackfill}); err != nil {
+		return nil, err
+	}
+
+	lock := e.lockFor(p.ID)
+	select {
+	case lock.sem <- struct{}{}:
+	default:
+		return nil, fmt.Errorf("cannot backfill %s: %w", p.ID, ErrRunInProgress)
+	}
+
+	st, err := e.BackfillStatus(p.ID)
+	if err == nil && (st == nil || st.Completed()) {
+		st, err = e.planBackfill(ctx, p)
+	}
+	if err != nil {
+		<-lock.sem
+		return nil, err
+	}
+
+	// The workers update st once started, so the caller gets a copy
+	snapshot := st.clone()
+	go func() {
+		defer func() { <-lock.sem }()
+		release, err := e.acquireRun(ctx, p)
+		if err != nil {
+			log.Printf("[Engine] Backfill of pipeline %s not started: %v", p.ID, err)
+			return
+		}
+		defer release()
+		e.runBackfill(ctx, p, st)
+	}()
+
+	return snapshot, nil
+}
+
+// clone returns a copy of the state sharing nothing with it
+func (st *BackfillState) clone() *BackfillState {
+	out := *st
+	out.Chunks = append([]ChunkState(nil), st.Chunks...)
+	if st.Budget != nil {
+		budget := *st.Budget
+		out.Budget = &budget
+	}
+	return &out
+}
+
+// ResumeBackfills continues every unfinished backfill found in the state store
+func (e *Engine) ResumeBackfills(ctx context.Context) error {
+	keys, err := e.store.Keys("backfill")
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		var st BackfillState
+		if _, err := e.store.Load(key, &st); err != nil {
+			return err
+		}
+		if st.Completed() {
+			continue
+		}
+
+		log.Printf("[Engine] Resuming backfill of pipeline %s", st.PipelineID)
+		if _, err := e.StartBackfill(ctx, st.PipelineID); err != nil {
+			log.Printf("[Engine] Failed to resume backfill of pipeline %s: %v", st.PipelineID, err)
+		}
+	}
+
+	return nil
+}
+
+// BackfillStatus returns the stored backfill state of a pipeline, or nil
+func (e *Engine) BackfillStatus(pipelineID string) (*BackfillState, error) {
+	var st BackfillState
+	found, err := e.store.Load("backfill/"+pipelineID, &st)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	return &st, nil
+}
+
+// planBackfill splits the source key space and persists the initial state
+func (e *Engine) planBackfill(ctx context.Context, p *registry.Pipeline) (*BackfillState, error) {
+	source, _, err := e.Connect(p)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, ok := source.(connectors.RangeReader)
+	if !ok {
+		return nil, fmt.Errorf("source type %s does not support chunked backfills", p.Source.Type)
+	}
+
+	position, err := source.GetLatestCheckpoint(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read source position: %w", err)
+	}
+
+	chunks := registry.DefaultBackfillChunks
+	if p.Backfill != nil && p.Backfill.Chunks > 0 {
+		chunks = p.Backfill.Chunks
+	}
+	ranges, err := reader.KeyRanges(ctx, chunks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to split key space: %w", err)
+	}
+
+	st := &BackfillState{
+		PipelineID: p.ID,
+		Position:   position,
+		StartedAt:  time.Now().UTC(),
+	}
+	for _, r := range collationOf(p).partition(ranges) {
+		st.Chunks = append(st.Chunks, ChunkState{Range: r})
+	}
+
+	return st, e.store.Save("backfill/"+p.ID, st)
+}
+
+// runBackfill copies every pending chunk with a pool of workers, persisting
+// each completed chunk so an interrupted backfill resumes where it stopped
+func (e *Engine) runBackfill(ctx context.Context, p *registry.Pipeline, st *BackfillState) {
+	run := &Run{
+		ID:         fmt.Sprintf("%s-backfill-%d", p.ID, time.Now().UnixNano()),
+		PipelineID: p.ID,
+		Trigger:    Trigger{Type: TriggerBackfill},
+		Status:     StatusRunning,
+		StartedAt:  time.Now().UTC(),
+		Progress:   &Progress{},
+	}
+	ctx, cancel := context.WithCancelCause(e.withCallLimits(ctx, p))
+	defer cancel(nil)
+	tracker := e.track(run, cancel)
+
+	err := e.copyChunks(ctx, p, st, tracker)
+	if cause := watchdogCause(ctx); cause != nil && err != nil {
+		err = cause
+	}
+
+	if err == nil {
+		st.CompletedAt = time.Now().UTC()
+		st.Error = ""
+		if st.Position != nil {
+			if err = e.store.SaveCheckpoint(p.ID, st.Position); err == nil {
+				tracker.moved(nil, st.Position)
+			}
+		}
+	} else {
+		st.Error = err.Error()
+	}
+	if saveErr := e.store.Save("backfill/"+p.ID, st); saveErr != nil && err == nil {
+		err = saveErr
+	}
+
+	e.mu.Lock()
+	run.FinishedAt = time.Now().UTC()
+	run.Records = int(run.Progress.RecordsDone)
+	run.Status = StatusSucceeded
+	if err != nil {
+		run.Status = StatusFailed
+		run.Error = err.Error()
+	}
+	e.mu.Unlock()
+
+	if err != nil {
+		e.recordError(p.ID, "backfill", err)
+		e.reportFailure(p, run, "backfill", err, "")
+	} else {
+		e.monitor.RecordSuccess(p.ID, run.Records)
+		log.Printf("[Engine] Backfill of pipeline %s completed (%d records)", p.ID, run.Records)
+	}
+	e.finishVerification(p, run)
+	tracker.finish()
+	if err := e.saveMeter(p.ID); err != nil {
+		log.Printf("[Engine] Failed to save traffic of %s: %v", p.ID, err)
+	}
+	e.notify(run)
+}
+
+// copyChunks distributes pending chunks over the configured workers
+func (e *Engine) copyChunks(ctx context.Context, p *registry.Pipeline, st *BackfillState, tracker *progressTracker) error {
+	source, target, err := e.Connect(p)
+	if err != nil {
+		return err
+	}
+	reader, ok := source.(connectors.RangeReader)
+	if !ok {
+		return fmt.Errorf("source type %s does not support chunked backfills", p.Source.Type)
+	}
+	if err := e.bootstrap(ctx, p, source, target); err != nil {
+		return err
+	}
+	tracker.estimate(ctx, source)
+
+	workers := registry.DefaultBackfillWorkers
+	if p.Backfill != nil && p.Backfill.Workers > 0 {
+		workers = p.Backfill.Workers
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var budget *registry.BackfillBudget
+	if p.Backfill != nil {
+		budget = p.Backfill.Budget
+	}
+
+	pending := make(chan int)
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range pending {
+				chunk := st.Chunks[i].Range
+				var records []connectors.Record
+				err := e.pace(ctx, p, st, budget, &mu, tracker)
+				if err == nil {
+					records, err = reader.ReadRange(ctx, chunk)
+				}
+				if err == nil {
+					label := fmt.Sprintf("[%s,%s) ", chunk.Start, chunk.End)
+					var n int
+					n, err = e.applyStaged(ctx, p, target, records, tracker, label, "")
+					if err == nil {
+						mu.Lock()
+						st.Chunks[i].Done = true
+						st.Chunks[i].Records = n
+						st.spend(budget, n, time.Now())
+						err = e.store.Save("backfill/"+p.ID, st)
+						mu.Unlock()
+					}
+				}
+				if err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = fmt.Errorf("chunk [%s,%s): %w", chunk.Start, chunk.End, err)
+					}
+					mu.Unlock()
+					cancel()
+				}
+			}
+		}()
+	}
+
+	for i := range st.Chunks {
+		if st.Chunks[i].Done {
+			tracker.advance(st.Chunks[i].Records, "")
+			continue
+		}
+		select {
+		case pending <- i:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	close(pending)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// spend counts records copied against the budget period they were copied
+// in; the caller holds the lock guarding st
+func (st *BackfillState) spend(budget *registry.BackfillBudget, n int, now time.Time) {
+	if budget == nil {
+		return
+	}
+	st.roll(budget, now)
+	st.Budget.Records += n
+}
+
+// roll starts a new budget window once the period of the current one ended
+func (st *BackfillState) roll(budget *registry.BackfillBudget, now time.Time) {
+	start := now.UTC().Truncate(budget.Period())
+	if st.Budget == nil || !st.Budget.Start.Equal(start) {
+		st.Budget = &BudgetWindow{Start: start}
+	}
+}
+
+// pace waits for the next budget period before a chunk is copied while the
+// budget of the current one is spent, pausing the run progress so the
+// watchdog does not take the wait for a stall
+func (e *Engine) pace(ctx context.Context, p *registry.Pipeline, st *BackfillState, budget *registry.BackfillBudget, mu *sync.Mutex, tracker *progressTracker) error {
+	if budget == nil {
+		return nil
+	}
+
+	for {
+		now := time.Now()
+		mu.Lock()
+		st.roll(budget, now)
+		if st.Budget.Records < budget.Records {
+			mu.Unlock()
+			return nil
+		}
+		until := st.Budget.Start.Add(budget.Period())
+		if !st.Budget.PausedUntil.Equal(until) {
+			st.Budget.PausedUntil = until
+			if err := e.store.Save("backfill/"+p.ID, st); err != nil {
+				mu.Unlock()
+				return err
+			}
+			log.Printf("[Engine] Backfill of pipeline %s spent its budget of %d records per %s, pausing until %s",
+				p.ID, budget.Records, budget.Per, until.Format(time.RFC3339))
+			tracker.pause(until)
+		}
+		mu.Unlock()
+
+		timer := time.NewTimer(until.Sub(now))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+
+		tracker.pause(time.Time{})
+		mu.Lock()
+		if st.Budget.PausedUntil.Equal(until) {
+			st.Budget.PausedUntil = time.Time{}
+		}
+		mu.Unlock()
+	}
+}
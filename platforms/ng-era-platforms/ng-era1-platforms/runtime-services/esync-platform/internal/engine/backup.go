@@ -0,0 +1,124 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: state-backup
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Backup and Restore
+ */
+
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/state"
+)
+
+// runsEntry is the archive entry holding the in-memory run history
+const runsEntry = "runs.json"
+
+// AuditRestored is the audit decision recorded when state is restored
+const AuditRestored = "restored"
+
+// Backup writes an archive of the state store (checkpoints, workflow state,
+// the audit log) and the run history to w
+func (e *Engine) Backup(w io.Writer) (*state.Manifest, error) {
+	e.mu.RLock()
+	history := make(map[string][]*Run, len(e.history))
+	for id, runs := range e.history {
+		for _, run := range runs {
+			history[id] = append(history[id], snapshot(run))
+		}
+	}
+	e.mu.RUnlock()
+
+	runs, err := json.Marshal(history)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode run history: %w", err)
+	}
+
+	e.auditMu.Lock()
+	defer e.auditMu.Unlock()
+	return e.store.Backup(w, map[string][]byte{runsEntry: runs})
+}
+
+// Restore replaces the state store and run history with an archive written
+// by Backup. It holds the run lock of every pipeline while restoring and
+// fails with ErrRunInProgress when any pipeline is busy.
+func (e *Engine) Restore(r io.Reader) (*state.Manifest, error) {
+	var held []*runLock
+	defer func() {
+		for _, lock := range held {
+			<-lock.sem
+		}
+	}()
+	for _, p := range e.registry.GetAll() {
+		lock := e.lockFor(p.ID)
+		select {
+		case lock.sem <- struct{}{}:
+			held = append(held, lock)
+		default:
+			return nil, fmt.Errorf("cannot restore while %s is busy: %w", p.ID, ErrRunInProgress)
+		}
+	}
+
+	e.auditMu.Lock()
+	manifest, extra, err := e.store.Restore(r)
+	e.auditMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	history := make(map[string][]*Run)
+	if data, ok := extra[runsEntry]; ok {
+		if err := json.Unmarshal(data, &history); err != nil {
+			log.Printf("[Engine] Restored archive has an unreadable run history: %v", err)
+			history = make(map[string][]*Run)
+		}
+	}
+	e.mu.Lock()
+	e.history = history
+	e.mu.Unlock()
+
+	// Drop caches read from the replaced state
+	e.piiMu.Lock()
+	e.pii = make(map[string]map[string]*PIIFinding)
+	e.piiDirty = make(map[string]bool)
+	e.piiMu.Unlock()
+	e.bandwidthMu.Lock()
+	e.meters = make(map[string][]*BandwidthDay)
+	e.meterDirty = make(map[string]bool)
+	e.bandwidthMu.Unlock()
+	e.residencyMu.Lock()
+	e.residency = make(map[string]string)
+	e.residencyMu.Unlock()
+	e.keyMapsMu.Lock()
+	e.keyMaps = make(map[string]*keyMap)
+	e.keyMapsMu.Unlock()
+	e.versionsMu.Lock()
+	e.versions = make(map[string]*versionLog)
+	e.versionsMu.Unlock()
+	e.agentMu.Lock()
+	e.spools = make(map[string]*spoolStats)
+	e.agentMu.Unlock()
+
+	log.Printf("[Engine] Restored %d state files from archive created %s", len(manifest.Files), manifest.CreatedAt.Format(time.RFC3339))
+	e.audit(AuditEntry{
+		Policy:   "backup",
+		Decision: AuditRestored,
+		Detail:   fmt.Sprintf("%d state files from archive created %s", len(manifest.Files), manifest.CreatedAt.Format(time.RFC3339)),
+	})
+	return manifest, nil
+}
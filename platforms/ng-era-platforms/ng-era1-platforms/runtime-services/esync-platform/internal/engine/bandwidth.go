@@ -0,0 +1,319 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: bandwidth-caps
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Bandwidth Caps and Metering
+ */
+
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// Directions of metered traffic
+const (
+	DirectionRead    = "read"
+	DirectionWritten = "written"
+)
+
+// meterDays is how many days of traffic are kept per pipeline
+const meterDays = 31
+
+// LoadBandwidth reads a bandwidth file, the bandwidth spec of pipelines:
+//
+//	bytes_per_second: 1048576
+//	timezone: Europe/Berlin
+//	profiles:
+//	  - windows:
+//	      - days: [mon, tue, wed, thu, fri, sat]
+//	        start: "08:00"
+//	        end: "20:00"
+//	    bytes_per_second: 131072
+func LoadBandwidth(path string) (*registry.BandwidthSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bandwidth file: %w", err)
+	}
+
+	var spec registry.BandwidthSpec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("failed to parse bandwidth file %s: %w", path, err)
+	}
+	if _, err := spec.Schedule(); err != nil {
+		return nil, fmt.Errorf("invalid bandwidth file %s: %w", path, err)
+	}
+	return &spec, nil
+}
+
+// byteBucket is a token bucket of bytes holding at most one second of the
+// current rate. Writes larger than the bucket take it into debt, which the
+// next writes wait off, so the average stays at the rate.
+type byteBucket struct {
+	mu       sync.Mutex
+	spec     *registry.BandwidthSpec
+	schedule *registry.BandwidthSchedule
+	tokens   float64
+	last     time.Time
+}
+
+// reserve takes n bytes under spec and returns how long to wait before
+// sending them
+func (b *byteBucket) reserve(spec *registry.BandwidthSpec, n int64, now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if spec != b.spec {
+		schedule, err := spec.Schedule()
+		if err != nil {
+			// Specs are validated when loaded
+			schedule = &registry.BandwidthSchedule{}
+		}
+		b.spec, b.schedule = spec, schedule
+	}
+	rate := float64(b.schedule.Rate(now))
+	if rate <= 0 {
+		b.tokens, b.last = 0, now
+		return 0
+	}
+	// A new bucket starts full
+	b.tokens += now.Sub(b.last).Seconds() * rate
+	if b.tokens > rate {
+		b.tokens = rate
+	}
+	b.last = now
+	b.tokens -= float64(n)
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / rate * float64(time.Second))
+}
+
+// bucket returns the byte bucket of key, created on first use
+func (e *Engine) bucket(key string) *byteBucket {
+	e.bandwidthMu.Lock()
+	defer e.bandwidthMu.Unlock()
+
+	b, exists := e.buckets[key]
+	if !exists {
+		b = &byteBucket{}
+		e.buckets[key] = b
+	}
+	return b
+}
+
+// recordBytes returns the size of records as JSON, the measure of the
+// traffic they cause
+func recordBytes(records []connectors.Record) int64 {
+	if len(records) == 0 {
+		return 0
+	}
+	data, err := json.Marshal(records)
+	if err != nil {
+		return 0
+	}
+	return int64(len(data))
+}
+
+// throttle waits until the bandwidth caps of the pipeline and the agent
+// allow writing records and returns their size. Only writes are paced:
+// what a pass read is in memory by the time its size is known.
+func (e *Engine) throttle(ctx context.Context, p *registry.Pipeline, records []connectors.Record) (int64, error) {
+	n := recordBytes(records)
+	now := time.Now()
+	var wait time.Duration
+	if p.Bandwidth != nil {
+		wait = e.bucket("pipeline:"+p.ID).reserve(p.Bandwidth, n, now)
+	}
+	if agent := e.Agent(); agent != nil && agent.Bandwidth != nil {
+		if w := e.bucket("agent").reserve(agent.Bandwidth, n, now); w > wait {
+			wait = w
+		}
+	}
+	if wait <= 0 {
+		return n, nil
+	}
+
+	e.tracef(p.ID, "waiting %s for the bandwidth caps to write %d bytes", wait.Round(time.Millisecond), n)
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		e.meter(p.ID, DirectionWritten, 0, time.Since(now))
+		return 0, ctx.Err()
+	case <-timer.C:
+	}
+	e.meter(p.ID, DirectionWritten, 0, wait)
+	return n, nil
+}
+
+// BandwidthDay is the traffic of a pipeline on one UTC day
+type BandwidthDay struct {
+	Date    string `json:"date"`
+	Read    int64  `json:"read"`
+	Written int64  `json:"written"`
+	// Waited is the number of seconds writes waited for the caps
+	Waited float64 `json:"waited,omitempty"`
+	// HourlyRead and HourlyWritten split the bytes by UTC hour
+	HourlyRead    [24]int64 `json:"hourly_read"`
+	HourlyWritten [24]int64 `json:"hourly_written"`
+}
+
+// meter counts bytes a pipeline read or wrote and the time its writes
+// waited into its traffic of the current day
+func (e *Engine) meter(pipelineID, direction string, bytes int64, waited time.Duration) {
+	e.monitor.RecordBandwidth(pipelineID, direction, bytes, waited)
+
+	e.bandwidthMu.Lock()
+	defer e.bandwidthMu.Unlock()
+
+	days, err := e.loadMeter(pipelineID)
+	if err != nil {
+		return
+	}
+	now := time.Now().UTC()
+	date := now.Format("2006-01-02")
+	if len(days) == 0 || days[len(days)-1].Date != date {
+		days = append(days, &BandwidthDay{Date: date})
+		if len(days) > meterDays {
+			days = days[len(days)-meterDays:]
+		}
+		e.meters[pipelineID] = days
+	}
+	day := days[len(days)-1]
+	switch direction {
+	case DirectionRead:
+		day.Read += bytes
+		day.HourlyRead[now.Hour()] += bytes
+	case DirectionWritten:
+		day.Written += bytes
+		day.HourlyWritten[now.Hour()] += bytes
+	}
+	day.Waited += waited.Seconds()
+	e.meterDirty[pipelineID] = true
+}
+
+// loadMeter returns the cached traffic of a pipeline, reading it from the
+// state store on first use. Callers hold bandwidthMu.
+func (e *Engine) loadMeter(pipelineID string) ([]*BandwidthDay, error) {
+	if days, ok := e.meters[pipelineID]; ok {
+		return days, nil
+	}
+	var days []*BandwidthDay
+	if _, err := e.store.Load("meter/"+pipelineID, &days); err != nil {
+		return nil, err
+	}
+	e.meters[pipelineID] = days
+	return days, nil
+}
+
+// saveMeter stores the traffic of a pipeline if it changed
+func (e *Engine) saveMeter(pipelineID string) error {
+	e.bandwidthMu.Lock()
+	defer e.bandwidthMu.Unlock()
+	if !e.meterDirty[pipelineID] {
+		return nil
+	}
+	delete(e.meterDirty, pipelineID)
+	return e.store.Save("meter/"+pipelineID, e.meters[pipelineID])
+}
+
+// BandwidthCap is a bandwidth spec and the cap it sets now
+type BandwidthCap struct {
+	Spec *registry.BandwidthSpec `json:"spec"`
+	// BytesPerSecond is the cap applying now; zero means unlimited
+	BytesPerSecond int64 `json:"bytes_per_second"`
+}
+
+// PipelineBandwidth is the cap and metered traffic of a pipeline
+type PipelineBandwidth struct {
+	PipelineID string        `json:"pipeline_id"`
+	Cap        *BandwidthCap `json:"cap,omitempty"`
+	// Read, Written and Waited sum the days reported
+	Read    int64          `json:"read"`
+	Written int64          `json:"written"`
+	Waited  float64        `json:"waited"`
+	Days    []BandwidthDay `json:"days"`
+}
+
+// BandwidthReport is the bandwidth caps and metered traffic of the daemon
+type BandwidthReport struct {
+	// Agent is the cap shared by the pipelines of an edge agent
+	Agent     *BandwidthCap       `json:"agent,omitempty"`
+	Read      int64               `json:"read"`
+	Written   int64               `json:"written"`
+	Pipelines []PipelineBandwidth `json:"pipelines"`
+}
+
+// bandwidthCap returns the cap spec sets at t, or nil without a spec
+func bandwidthCap(spec *registry.BandwidthSpec, t time.Time) *BandwidthCap {
+	if spec == nil {
+		return nil
+	}
+	c := &BandwidthCap{Spec: spec}
+	if schedule, err := spec.Schedule(); err == nil {
+		c.BytesPerSecond = schedule.Rate(t)
+	}
+	return c
+}
+
+// BandwidthReport returns the caps of the pipelines and the agent and the
+// traffic of the last days UTC days, today included, up to the 31 kept
+func (e *Engine) BandwidthReport(days int) (*BandwidthReport, error) {
+	if days < 1 || days > meterDays {
+		days = meterDays
+	}
+	now := time.Now()
+	since := now.UTC().AddDate(0, 0, 1-days).Format("2006-01-02")
+	report := &BandwidthReport{Pipelines: []PipelineBandwidth{}}
+	if agent := e.Agent(); agent != nil {
+		report.Agent = bandwidthCap(agent.Bandwidth, now)
+	}
+
+	pipelines := e.registry.GetAll()
+	sort.Slice(pipelines, func(i, j int) bool { return pipelines[i].ID < pipelines[j].ID })
+
+	e.bandwidthMu.Lock()
+	defer e.bandwidthMu.Unlock()
+
+	for _, p := range pipelines {
+		stored, err := e.loadMeter(p.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load traffic of pipeline %s: %w", p.ID, err)
+		}
+		pb := PipelineBandwidth{PipelineID: p.ID, Cap: bandwidthCap(p.Bandwidth, now), Days: []BandwidthDay{}}
+		for _, day := range stored {
+			if day.Date < since {
+				continue
+			}
+			pb.Read += day.Read
+			pb.Written += day.Written
+			pb.Waited += day.Waited
+			pb.Days = append(pb.Days, *day)
+		}
+		report.Read += pb.Read
+		report.Written += pb.Written
+		report.Pipelines = append(report.Pipelines, pb)
+	}
+	return report, nil
+}
@@ -0,0 +1,123 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: target-bootstrap
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Target Bootstrap of Missing Destination Objects
+ */
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// BootstrapState records the last target bootstrap of a pipeline
+type BootstrapState struct {
+	PipelineID string `json:"pipeline_id"`
+	// Version is the pipeline version bootstrapped; a new version is
+	// bootstrapped again
+	Version     string    `json:"version"`
+	Created     []string  `json:"created,omitempty"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// bootstrap lets the targets of a pipeline with bootstrap enabled create
+// their missing destination objects, once per pipeline version
+func (e *Engine) bootstrap(ctx context.Context, p *registry.Pipeline, source, target connectors.Connector) error {
+	if !e.pendingBootstrap(p) {
+		return nil
+	}
+
+	req := connectors.BootstrapRequest{}
+	if schema, ok := introspect(ctx, source); ok {
+		req.Schema = schema
+	}
+
+	routed := make(map[int][]string)
+	for i, route := range p.Routes {
+		routed[i] = append(routed[i], route.Table)
+	}
+	if p.Tables != nil {
+		for _, pattern := range p.Tables.Include {
+			if route := routeFor(p, pattern); route < 0 && !isPattern(pattern) {
+				routed[-1] = append(routed[-1], pattern)
+			}
+		}
+	}
+
+	created, err := bootstrapTarget(ctx, target, req, routed[-1])
+	if err != nil {
+		return err
+	}
+	for i, route := range p.Routes {
+		routeTarget, err := e.connector(route.Target)
+		if err != nil {
+			return err
+		}
+		objects, err := bootstrapTarget(ctx, routeTarget, req, routed[i])
+		if err != nil {
+			return fmt.Errorf("route %s: %w", route.Table, err)
+		}
+		created = append(created, objects...)
+	}
+
+	for _, object := range created {
+		log.Printf("[Engine] Bootstrap of pipeline %s created %s", p.ID, object)
+	}
+	return e.store.Save("bootstrap/"+p.ID, BootstrapState{
+		PipelineID:  p.ID,
+		Version:     p.Version,
+		Created:     created,
+		CompletedAt: time.Now().UTC(),
+	})
+}
+
+// pendingBootstrap reports whether the current pipeline version still has
+// to bootstrap its targets
+func (e *Engine) pendingBootstrap(p *registry.Pipeline) bool {
+	if !p.Bootstrap {
+		return false
+	}
+	st, err := e.BootstrapStatus(p.ID)
+	return err != nil || st == nil || st.Version != p.Version
+}
+
+// bootstrapTarget asks one target to create its missing objects
+func bootstrapTarget(ctx context.Context, target connectors.Connector, req connectors.BootstrapRequest, tables []string) ([]string, error) {
+	bootstrapper, ok := target.(connectors.Bootstrapper)
+	if !ok {
+		return nil, fmt.Errorf("bootstrap: %w", connectors.ErrUnsupported)
+	}
+
+	req.Tables = tables
+	created, err := bootstrapper.Bootstrap(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bootstrap target: %w", err)
+	}
+	return created, nil
+}
+
+// BootstrapStatus returns the last target bootstrap of a pipeline, or nil
+func (e *Engine) BootstrapStatus(pipelineID string) (*BootstrapState, error) {
+	var st BootstrapState
+	found, err := e.store.Load("bootstrap/"+pipelineID, &st)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &st, nil
+}
@@ -0,0 +1,312 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: canary-records
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * End-to-End Canary Records
+ */
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// CanaryField marks canary records; its value is the RFC 3339 time the
+// canary was sent
+const CanaryField = "_esync_canary"
+
+// canaryPrefix starts the ID of every canary record
+const canaryPrefix = "esync-canary-"
+
+// canaryTick is how often WatchCanaries checks for due and lost canaries
+const canaryTick = time.Second
+
+// Routes a canary takes into the pipeline
+const (
+	// CanaryViaSource canaries are written to the source by a
+	// connectors.CanaryWriter and travel the whole path
+	CanaryViaSource = "source"
+	// CanaryViaEngine canaries are added to the input of the next sync pass
+	CanaryViaEngine = "engine"
+)
+
+// Canary statuses
+const (
+	CanaryPending = "pending"
+	CanaryArrived = "arrived"
+	CanaryLost    = "lost"
+)
+
+// Canary is the last synthetic record sent through a pipeline. Canaries
+// bypass transforms and validation, are written to the target like any
+// other record and deleted from it once they arrive. They ride along with
+// the next run, so the timeout must exceed the pipeline schedule.
+type Canary struct {
+	ID             string    `json:"id"`
+	Via            string    `json:"via"`
+	Status         string    `json:"status"`
+	SentAt         time.Time `json:"sent_at"`
+	ArrivedAt      time.Time `json:"arrived_at,omitempty"`
+	LatencySeconds float64   `json:"latency_seconds,omitempty"`
+	Error          string    `json:"error,omitempty"`
+	// Sent, Arrived and Lost count the canaries of the pipeline since
+	// startup
+	Sent    int `json:"sent"`
+	Arrived int `json:"arrived"`
+	Lost    int `json:"lost"`
+}
+
+// canaryState is the last canary of a pipeline
+type canaryState struct {
+	Canary
+	// queued is a canary waiting to be added to the next sync pass
+	queued *connectors.Record
+}
+
+// WatchCanaries sends the canaries of pipelines with a canary spec and
+// reports lost ones until ctx is cancelled
+func (e *Engine) WatchCanaries(ctx context.Context) {
+	ticker := time.NewTicker(canaryTick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			for _, p := range e.registry.GetAll() {
+				if p.Canary != nil {
+					e.checkCanary(ctx, p, now)
+				}
+			}
+		}
+	}
+}
+
+// LastCanary returns the last canary of a pipeline, or nil
+func (e *Engine) LastCanary(pipelineID string) *Canary {
+	e.canaryMu.Lock()
+	defer e.canaryMu.Unlock()
+
+	c := e.canaries[pipelineID]
+	if c == nil {
+		return nil
+	}
+	out := c.Canary
+	return &out
+}
+
+// canaryTimes returns the interval and timeout of a canary spec
+func canaryTimes(spec *registry.CanarySpec) (time.Duration, time.Duration) {
+	interval, timeout := registry.DefaultCanaryInterval, registry.DefaultCanaryTimeout
+	if spec.Interval > 0 {
+		interval = spec.Interval
+	}
+	if spec.Timeout > 0 {
+		timeout = spec.Timeout
+	}
+	return time.Duration(interval) * time.Second, time.Duration(timeout) * time.Second
+}
+
+// checkCanary reports the pending canary of a pipeline lost once it times
+// out and sends a new one when due. Paused pipelines and pipelines being
+// handed off are skipped.
+func (e *Engine) checkCanary(ctx context.Context, p *registry.Pipeline, now time.Time) {
+	if paused, err := e.Paused(p.ID); err != nil || paused {
+		return
+	}
+	if e.checkHandoff(p.ID) != nil {
+		return
+	}
+	interval, timeout := canaryTimes(p.Canary)
+
+	e.canaryMu.Lock()
+	c := e.canaries[p.ID]
+	if c != nil && c.Status == CanaryPending {
+		if now.Sub(c.SentAt) <= timeout {
+			e.canaryMu.Unlock()
+			return
+		}
+		c.Status = CanaryLost
+		c.Error = fmt.Sprintf("did not reach the target within %s", timeout)
+		c.Lost++
+		c.queued = nil
+		id := c.ID
+		e.canaryMu.Unlock()
+
+		e.monitor.RecordCanaryLost(p.ID)
+		e.recordError(p.ID, "canary", fmt.Errorf("canary %s did not reach the target within %s", id, timeout))
+		return
+	}
+	due := c == nil || now.Sub(c.SentAt) >= interval
+	e.canaryMu.Unlock()
+
+	if due {
+		e.sendCanary(ctx, p, now)
+	}
+}
+
+// sendCanary writes a canary to the source when it supports it, or queues
+// one for the next sync pass
+func (e *Engine) sendCanary(ctx context.Context, p *registry.Pipeline, now time.Time) {
+	now = now.UTC()
+	record := connectors.Record{
+		ID:        fmt.Sprintf("%s%s-%d", canaryPrefix, p.ID, now.UnixNano()),
+		Operation: connectors.OperationInsert,
+		Data:      map[string]interface{}{CanaryField: now.Format(time.RFC3339Nano)},
+		Timestamp: now,
+		Table:     p.Canary.Table,
+	}
+	c := &canaryState{Canary: Canary{ID: record.ID, Via: CanaryViaEngine, Status: CanaryPending, SentAt: now}}
+
+	source, _, err := e.Connect(p)
+	if err == nil {
+		if w, ok := source.(connectors.CanaryWriter); ok {
+			err = w.WriteCanary(ctx, record)
+			if err == nil {
+				c.Via = CanaryViaSource
+			} else if errors.Is(err, connectors.ErrUnsupported) {
+				err = nil
+			}
+		}
+	}
+	if err != nil {
+		c.Status = CanaryLost
+		c.Error = err.Error()
+	} else if c.Via == CanaryViaEngine {
+		c.queued = &record
+	}
+
+	e.canaryMu.Lock()
+	if prev := e.canaries[p.ID]; prev != nil {
+		c.Sent, c.Arrived, c.Lost = prev.Sent, prev.Arrived, prev.Lost
+	}
+	c.Sent++
+	if err != nil {
+		c.Lost++
+	}
+	e.canaries[p.ID] = c
+	e.canaryMu.Unlock()
+
+	if err != nil {
+		e.monitor.RecordCanaryLost(p.ID)
+		e.recordError(p.ID, "canary", fmt.Errorf("failed to send canary: %w", err))
+	}
+}
+
+// queuedCanary takes the canary waiting to be added to the next sync pass
+// of a pipeline
+func (e *Engine) queuedCanary(pipelineID string) []connectors.Record {
+	e.canaryMu.Lock()
+	defer e.canaryMu.Unlock()
+
+	c := e.canaries[pipelineID]
+	if c == nil || c.queued == nil {
+		return nil
+	}
+	record := *c.queued
+	c.queued = nil
+	return []connectors.Record{record}
+}
+
+// isCanary reports whether a record is a canary
+func isCanary(r connectors.Record) bool {
+	return strings.HasPrefix(r.ID, canaryPrefix) && r.Has(CanaryField)
+}
+
+// splitCanaries separates canaries from the records of a pass
+func splitCanaries(records []connectors.Record) ([]connectors.Record, []connectors.Record) {
+	var canaries []connectors.Record
+	kept := records[:0:0]
+	for _, r := range records {
+		if isCanary(r) {
+			canaries = append(canaries, r)
+		} else {
+			kept = append(kept, r)
+		}
+	}
+	if canaries == nil {
+		return records, nil
+	}
+	return kept, canaries
+}
+
+// canariesArrived confirms canaries written to the target, reading them
+// back where the target supports it, records their latency and deletes
+// them from the target
+func (e *Engine) canariesArrived(ctx context.Context, p *registry.Pipeline, target connectors.Connector, canaries []connectors.Record) {
+	for _, r := range canaries {
+		err := e.confirmCanary(ctx, target, r)
+		now := time.Now().UTC()
+		sent, _ := time.Parse(time.RFC3339Nano, fmt.Sprint(r.Data[CanaryField]))
+		latency := now.Sub(sent)
+
+		e.canaryMu.Lock()
+		c := e.canaries[p.ID]
+		current := c != nil && c.ID == r.ID && c.Status == CanaryPending
+		if current && err != nil {
+			c.Status = CanaryLost
+			c.Error = err.Error()
+			c.Lost++
+		} else if current {
+			c.Status = CanaryArrived
+			c.ArrivedAt = now
+			c.LatencySeconds = latency.Seconds()
+			c.Arrived++
+		}
+		e.canaryMu.Unlock()
+
+		if err != nil {
+			e.monitor.RecordCanaryLost(p.ID)
+			e.recordError(p.ID, "canary", fmt.Errorf("canary %s: %w", r.ID, err))
+			continue
+		}
+		if !sent.IsZero() {
+			e.monitor.RecordCanary(p.ID, latency)
+		}
+		log.Printf("[Engine] Canary %s of pipeline %s reached the target after %s", r.ID, p.ID, latency.Round(time.Millisecond))
+
+		cleanup := connectors.Record{ID: r.ID, Operation: connectors.OperationDelete, Timestamp: now, Table: r.Table}
+		if err := target.ApplyChanges(ctx, []connectors.Record{cleanup}); err != nil {
+			log.Printf("[Engine] Failed to delete canary %s of pipeline %s from the target: %v", r.ID, p.ID, err)
+		}
+	}
+}
+
+// confirmCanary reads a canary back from targets that support it
+func (e *Engine) confirmCanary(ctx context.Context, target connectors.Connector, r connectors.Record) error {
+	reader, ok := target.(connectors.RecordReader)
+	if !ok {
+		return nil
+	}
+	found, err := reader.ReadRecords(ctx, []string{r.ID})
+	if errors.Is(err, connectors.ErrUnsupported) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read back from the target: %w", err)
+	}
+	for _, f := range found {
+		if f.ID == r.ID {
+			return nil
+		}
+	}
+	return fmt.Errorf("written but not found on the target")
+}
@@ -0,0 +1,228 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: cleanup-pipelines
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Orphan and TTL Cleanup Runs
+ */
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// CleanupResult reports what a run of a cleanup pipeline deleted
+//
+// Frozen: public as engine.CleanupResult
+type CleanupResult struct {
+	TargetRecords int `json:"target_records"`
+	// Orphaned and Expired count the records selected because the source
+	// no longer holds them and because they outlived the TTL
+	Orphaned int  `json:"orphaned"`
+	Expired  int  `json:"expired"`
+	Deleted  int  `json:"deleted"`
+	DryRun   bool `json:"dry_run,omitempty"`
+	// Keys samples the selected records as table/id
+	Keys []string `json:"keys,omitempty"`
+}
+
+// cleanupPass deletes the target records a cleanup pipeline selects,
+// returning the number deleted. Orphans are found by digest exchange when
+// both connectors can digest their keys and no TTL needs record ages.
+// Passes that are not dry runs need the definition confirmed.
+func (e *Engine) cleanupPass(ctx context.Context, p *registry.Pipeline, source, target connectors.Connector, tracker *progressTracker) (int, error) {
+	spec := p.Cleanup
+	if spec == nil {
+		return 0, fmt.Errorf("cleanup pipeline %s has no cleanup block", p.ID)
+	}
+	if err := e.checkConfirmation(p); err != nil {
+		return 0, err
+	}
+
+	var (
+		result *CleanupResult
+		keys   []connectors.Key
+		err    error
+	)
+	if s, t, ok := digesters(source, target); ok && spec.TTL == 0 {
+		result, keys, err = digestOrphans(ctx, s, t)
+		if errors.Is(err, connectors.ErrUnsupported) {
+			result, err = nil, nil
+		}
+	}
+	if result == nil && err == nil {
+		result, keys, err = e.selectCleanup(ctx, p, source, target)
+	}
+	if err != nil {
+		return 0, err
+	}
+	result.DryRun = spec.DryRun
+
+	if spec.MaxDeletes > 0 && len(keys) > spec.MaxDeletes {
+		return 0, fmt.Errorf("cleanup selected %d records, more than max_deletes %d", len(keys), spec.MaxDeletes)
+	}
+	result.Keys = keySample(keys)
+	tracker.cleaned(result)
+	if spec.DryRun || len(keys) == 0 {
+		return 0, nil
+	}
+
+	now := time.Now().UTC()
+	deletes := make([]connectors.Record, len(keys))
+	for i, key := range keys {
+		deletes[i] = connectors.Record{ID: key.ID, Table: key.Table, KeyFields: p.PrimaryKey.KeyFields(key.Table), Operation: connectors.OperationDelete, Timestamp: now}
+	}
+	tracker.discover(int64(len(deletes)))
+	n, err := e.write(ctx, p, target, deletes, tracker, "cleanup:")
+	tracker.cleaned(&CleanupResult{Deleted: n})
+	if err != nil {
+		return n, err
+	}
+
+	log.Printf("[Engine] Cleanup of pipeline %s deleted %d orphaned and %d expired records", p.ID, result.Orphaned, result.Expired)
+	return n, nil
+}
+
+// digestOrphans selects the target keys the source lacks by digest
+// exchange
+func digestOrphans(ctx context.Context, source, target connectors.KeyDigester) (*CleanupResult, []connectors.Key, error) {
+	diff, err := diffKeys(ctx, source, target)
+	if err != nil {
+		return nil, nil, err
+	}
+	if diff.SourceKeys == 0 && diff.TargetKeys > 0 {
+		return nil, nil, emptySource(int(diff.TargetKeys))
+	}
+	return &CleanupResult{TargetRecords: int(diff.TargetKeys), Orphaned: len(diff.Extra)}, diff.Extra, nil
+}
+
+// selectCleanup selects the orphaned and expired target records from full
+// listings of source and target
+func (e *Engine) selectCleanup(ctx context.Context, p *registry.Pipeline, source, target connectors.Connector) (*CleanupResult, []connectors.Key, error) {
+	spec := p.Cleanup
+	targetRecords, err := e.liveRecords(ctx, p, target)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list target records: %w", err)
+	}
+	result := &CleanupResult{TargetRecords: len(targetRecords)}
+
+	var sourceRecords map[connectors.Key]connectors.Record
+	if spec.Orphans {
+		if sourceRecords, err = e.liveRecords(ctx, p, source); err != nil {
+			return nil, nil, fmt.Errorf("failed to list source records: %w", err)
+		}
+		if len(sourceRecords) == 0 && len(targetRecords) > 0 {
+			return nil, nil, emptySource(len(targetRecords))
+		}
+	}
+	var cutoff time.Time
+	if spec.TTL > 0 {
+		cutoff = time.Now().Add(-time.Duration(spec.TTL) * time.Second)
+	}
+
+	var keys []connectors.Key
+	for key, record := range targetRecords {
+		switch _, exists := sourceRecords[key]; {
+		case spec.Orphans && !exists:
+			result.Orphaned++
+		case spec.TTL > 0 && expired(record, spec.AgeField, cutoff):
+			result.Expired++
+		default:
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sortKeys(keys)
+	return result, keys, nil
+}
+
+// emptySource refuses to delete every target record because the source
+// lists none, which more likely means the source lost its data
+func emptySource(targetRecords int) error {
+	return fmt.Errorf("source lists no records; refusing to delete all %d target records", targetRecords)
+}
+
+// liveRecords replays the full change history of a connector and returns
+// the latest version of each record that is not deleted, by key
+func (e *Engine) liveRecords(ctx context.Context, p *registry.Pipeline, c connectors.Connector) (map[connectors.Key]connectors.Record, error) {
+	records, err := c.ListChanges(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	records, _ = splitHeartbeats(records)
+	records = e.normalizeKeys(p, records)
+
+	live := make(map[connectors.Key]connectors.Record, len(records))
+	for _, record := range records {
+		key := record.Key()
+		if record.Operation == connectors.OperationDelete {
+			delete(live, key)
+			continue
+		}
+		live[key] = record
+	}
+	return live, nil
+}
+
+// expired reports whether a record is older than cutoff, measured by its
+// age field or, without one, its timestamp. Records whose age cannot be
+// read are kept.
+func expired(record connectors.Record, field string, cutoff time.Time) bool {
+	if field == "" {
+		return !record.Timestamp.IsZero() && record.Timestamp.Before(cutoff)
+	}
+
+	var at time.Time
+	switch v := record.Data[field].(type) {
+	case time.Time:
+		at = v
+	case string:
+		parsed, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return false
+		}
+		at = parsed
+	case float64:
+		at = time.Unix(int64(v), 0)
+	case int:
+		at = time.Unix(int64(v), 0)
+	case int64:
+		at = time.Unix(v, 0)
+	default:
+		return false
+	}
+	return at.Before(cutoff)
+}
+
+// cleaned merges a cleanup result into the run; later results only add
+// the deleted count
+func (t *progressTracker) cleaned(result *CleanupResult) {
+	if t == nil {
+		return
+	}
+
+	t.e.mu.Lock()
+	defer t.e.mu.Unlock()
+
+	if t.run.Cleanup == nil {
+		t.run.Cleanup = result
+		return
+	}
+	t.run.Cleanup.Deleted += result.Deleted
+}
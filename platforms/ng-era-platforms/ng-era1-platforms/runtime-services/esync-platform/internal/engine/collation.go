@@ -0,0 +1,127 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: key-collation
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Key Collation
+ */
+
+package engine
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// collation compares record keys the way the source of a pipeline does
+type collation string
+
+// collationOf returns the key collation a pipeline declares, binary by
+// default
+func collationOf(p *registry.Pipeline) collation {
+	if p.PrimaryKey != nil && p.PrimaryKey.Collation != "" {
+		return collation(p.PrimaryKey.Collation)
+	}
+	return registry.CollationBinary
+}
+
+// fold returns the form of a key equal keys share under the collation
+func (c collation) fold(s string) string {
+	switch c {
+	case registry.CollationCaseInsensitive:
+		return strings.Map(func(r rune) rune {
+			if 'A' <= r && r <= 'Z' {
+				return r + 'a' - 'A'
+			}
+			return r
+		}, s)
+	case registry.CollationUnicode:
+		return strings.Map(func(r rune) rune { return unicode.ToLower(unicode.ToUpper(r)) }, s)
+	default:
+		return s
+	}
+}
+
+// compare orders two keys under the collation
+func (c collation) compare(a, b string) int {
+	return strings.Compare(c.fold(a), c.fold(b))
+}
+
+// key returns the key of a record that records of the same source row
+// share under the collation
+func (c collation) key(r connectors.Record) connectors.Key {
+	return connectors.Key{Table: r.Table, ID: c.fold(r.ID)}
+}
+
+// partition turns the key ranges a source splits its key space into into
+// ranges that neither overlap nor leave gaps under the collation. Sources
+// compute their split points bytewise, so with keys differing only in case
+// ranges such as [B, a) come back empty or inverted and overlap their
+// neighbours; the split points are ordered and merged under the collation
+// instead. Binary ranges are returned unchanged.
+func (c collation) partition(ranges []connectors.KeyRange) []connectors.KeyRange {
+	if c == registry.CollationBinary || len(ranges) < 2 {
+		return ranges
+	}
+
+	var start, end string
+	openStart, openEnd := false, false
+	var points []string
+	for i, r := range ranges {
+		switch {
+		case r.Start == "":
+			openStart = true
+		case i == 0 || c.compare(r.Start, start) < 0:
+			start = r.Start
+		}
+		switch {
+		case r.End == "":
+			openEnd = true
+		case i == 0 || c.compare(r.End, end) > 0:
+			end = r.End
+		}
+		points = append(points, r.Start, r.End)
+	}
+	if openStart {
+		start = ""
+	}
+	if openEnd {
+		end = ""
+	}
+
+	sort.SliceStable(points, func(i, j int) bool { return c.compare(points[i], points[j]) < 0 })
+	out := []connectors.KeyRange{}
+	from := start
+	for _, point := range points {
+		if point == "" || c.compare(point, from) <= 0 || (end != "" && c.compare(point, end) >= 0) {
+			continue
+		}
+		out = append(out, connectors.KeyRange{Start: from, End: point})
+		from = point
+	}
+	return append(out, connectors.KeyRange{Start: from, End: end})
+}
+
+// clipRange intersects two key ranges under the collation, reporting false
+// when they do not overlap
+func (c collation) clipRange(r, bounds connectors.KeyRange) (connectors.KeyRange, bool) {
+	if c.compare(bounds.Start, r.Start) > 0 {
+		r.Start = bounds.Start
+	}
+	if bounds.End != "" && (r.End == "" || c.compare(bounds.End, r.End) < 0) {
+		r.End = bounds.End
+	}
+	return r, r.End == "" || c.compare(r.Start, r.End) < 0
+}
@@ -0,0 +1,198 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: schema-compatibility
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Dry-Run Schema Compatibility Checker
+ */
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/coercion"
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+)
+
+// Kinds of schema incompatibilities
+const (
+	IssueMissingColumn  = "missing_column"
+	IssueTypeMismatch   = "type_mismatch"
+	IssueNarrowerType   = "narrower_type"
+	IssueNullability    = "nullability"
+	IssueRequiredColumn = "required_column"
+)
+
+// Severities of schema incompatibilities; errors make the schemas
+// incompatible, warnings may lose precision or fail for some records
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// SchemaIssue is one incompatibility between a source and target field
+type SchemaIssue struct {
+	Field      string `json:"field"`
+	Kind       string `json:"kind"`
+	Severity   string `json:"severity"`
+	SourceType string `json:"source_type,omitempty"`
+	TargetType string `json:"target_type,omitempty"`
+	Message    string `json:"message"`
+}
+
+// CompatibilityReport is the result of comparing the source and target
+// schemas of a pipeline without running it
+type CompatibilityReport struct {
+	PipelineID string `json:"pipeline_id"`
+	// Compatible is false when any issue is an error
+	Compatible   bool          `json:"compatible"`
+	SourceFields int           `json:"source_fields"`
+	TargetFields int           `json:"target_fields"`
+	Issues       []SchemaIssue `json:"issues"`
+	// Skipped explains why the schemas could not be compared
+	Skipped   string    `json:"skipped,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// CheckSchemas introspects the source and target of a pipeline and reports
+// missing columns, narrower types and nullability conflicts
+func (e *Engine) CheckSchemas(ctx context.Context, pipelineID string) (*CompatibilityReport, error) {
+	p, err := e.registry.GetByID(pipelineID)
+	if err != nil {
+		return nil, err
+	}
+	source, target, err := e.Connect(p)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &CompatibilityReport{PipelineID: p.ID, Compatible: true, Issues: []SchemaIssue{}, CheckedAt: time.Now().UTC()}
+	sourceSchema, ok := introspect(ctx, source)
+	if !ok {
+		report.Skipped = "source schema not available"
+		return report, nil
+	}
+	targetSchema, ok := introspect(ctx, target)
+	if !ok {
+		report.Skipped = "target schema not available"
+		return report, nil
+	}
+
+	report.SourceFields = len(sourceSchema.Fields)
+	report.TargetFields = len(targetSchema.Fields)
+	report.Issues = compareSchemas(sourceSchema, targetSchema)
+	for _, issue := range report.Issues {
+		if issue.Severity == SeverityError {
+			report.Compatible = false
+		}
+	}
+	return report, nil
+}
+
+// compareSchemas lists the incompatibilities of writing records of the
+// source schema to the target schema
+func compareSchemas(source, target *connectors.Schema) []SchemaIssue {
+	issues := []SchemaIssue{}
+	for _, sf := range source.Fields {
+		tf, exists := target.Field(sf.Name)
+		if !exists {
+			issues = append(issues, SchemaIssue{
+				Field: sf.Name, Kind: IssueMissingColumn, Severity: SeverityError, SourceType: sf.Type,
+				Message: fmt.Sprintf("target has no column %s", sf.Name),
+			})
+			continue
+		}
+		if issue, incompatible := compareTypes(sf, tf); incompatible {
+			issues = append(issues, issue)
+		}
+		if sf.Nullable && !tf.Nullable {
+			issues = append(issues, SchemaIssue{
+				Field: sf.Name, Kind: IssueNullability, Severity: SeverityError, SourceType: sf.Type, TargetType: tf.Type,
+				Message: fmt.Sprintf("%s is nullable in the source but NOT NULL in the target", sf.Name),
+			})
+		}
+	}
+
+	for _, tf := range target.Fields {
+		if _, exists := source.Field(tf.Name); !exists && !tf.Nullable {
+			issues = append(issues, SchemaIssue{
+				Field: tf.Name, Kind: IssueRequiredColumn, Severity: SeverityWarning, TargetType: tf.Type,
+				Message: fmt.Sprintf("target requires %s, which the source does not provide; inserts fail unless it has a default", tf.Name),
+			})
+		}
+	}
+	return issues
+}
+
+// compareTypes reports whether source values may not fit the target type
+func compareTypes(sf, tf connectors.Field) (SchemaIssue, bool) {
+	issue := SchemaIssue{Field: sf.Name, SourceType: sf.Type, TargetType: tf.Type}
+	sourceKind, sourceWidth := typeWidth(sf.Type)
+	targetKind, targetWidth := typeWidth(tf.Type)
+	if sourceKind == "" || targetKind == "" {
+		return issue, false
+	}
+
+	switch {
+	case sourceKind == targetKind:
+		if sourceWidth > 0 && targetWidth > 0 && targetWidth < sourceWidth || sourceWidth == 0 && targetWidth > 0 {
+			issue.Kind, issue.Severity = IssueNarrowerType, SeverityWarning
+			issue.Message = fmt.Sprintf("%s narrows from %s to %s; larger values are rejected or truncated", sf.Name, sf.Type, tf.Type)
+			return issue, true
+		}
+		return issue, false
+	case targetKind == coercion.TypeString:
+		return issue, false
+	case sourceKind == coercion.TypeInteger && targetKind == coercion.TypeNumber:
+		return issue, false
+	case sourceKind == coercion.TypeNumber && targetKind == coercion.TypeInteger:
+		issue.Kind, issue.Severity = IssueNarrowerType, SeverityWarning
+		issue.Message = fmt.Sprintf("%s narrows from %s to %s; fractions are lost", sf.Name, sf.Type, tf.Type)
+		return issue, true
+	default:
+		issue.Kind, issue.Severity = IssueTypeMismatch, SeverityError
+		issue.Message = fmt.Sprintf("%s is %s in the source but %s in the target", sf.Name, sf.Type, tf.Type)
+		return issue, true
+	}
+}
+
+// integerWidths gives the bit width of sized integer type names
+var integerWidths = map[string]int{
+	"int2": 16, "smallint": 16, "int4": 32, "int": 32, "integer": 32,
+	"int8": 64, "bigint": 64, "long": 64,
+}
+
+// typeWidth returns the canonical kind of a schema type and its width: bits
+// for integers, length for strings and precision for numbers, or 0 when
+// the type is unbounded or the width unknown
+func typeWidth(t string) (string, int) {
+	kind := coercion.NormalizeType(t)
+	t = strings.ToLower(strings.TrimSpace(t))
+
+	if kind == coercion.TypeInteger {
+		return kind, integerWidths[t]
+	}
+	open, end := strings.IndexByte(t, '('), strings.IndexByte(t, ')')
+	if open < 0 || end < open {
+		return kind, 0
+	}
+	size := strings.TrimSpace(strings.SplitN(t[open+1:end], ",", 2)[0])
+	width, err := strconv.Atoi(size)
+	if err != nil {
+		return kind, 0
+	}
+	return kind, width
+}
@@ -0,0 +1,214 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: destructive-run-confirmation
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Destructive Run Confirmation
+ */
+
+package engine
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// pauseReasonConfirm marks destructive pipelines paused until their
+// definition is confirmed
+const pauseReasonConfirm = "confirmation"
+
+// policyConfirm is the audit policy of destructive run confirmations
+const policyConfirm = "destructive_confirmation"
+
+var (
+	// ErrConfirmationRequired is returned by destructive passes of
+	// pipelines whose definition is not confirmed
+	ErrConfirmationRequired = errors.New("destructive run awaits confirmation")
+	// ErrNotDestructive is returned when confirming a pipeline whose runs
+	// delete nothing
+	ErrNotDestructive = errors.New("pipeline runs are not destructive")
+	// ErrDefinitionChanged is returned when confirming a definition that
+	// is no longer the pipeline's
+	ErrDefinitionChanged = errors.New("pipeline definition changed")
+)
+
+// Confirmation records who or what confirmed a definition of a destructive
+// pipeline
+type Confirmation struct {
+	PipelineID string `json:"pipeline_id"`
+	// Definition is the digest of the confirmed definition
+	Definition string `json:"definition"`
+	// Ticket is the change ticket the confirmation refers to and By the
+	// person who confirmed it through the API, if given
+	Ticket      string    `json:"ticket,omitempty"`
+	By          string    `json:"by,omitempty"`
+	ConfirmedAt time.Time `json:"confirmed_at"`
+}
+
+// ConfirmationState tells whether the current definition of a destructive
+// pipeline is confirmed
+type ConfirmationState struct {
+	PipelineID string `json:"pipeline_id"`
+	// Definition is the digest of the current definition, which a
+	// confirmation must name
+	Definition string `json:"definition"`
+	Confirmed  bool   `json:"confirmed"`
+	// Confirmation is the last confirmation, possibly of an earlier
+	// definition
+	Confirmation *Confirmation `json:"confirmation,omitempty"`
+}
+
+// destructive reports whether the runs of a pipeline delete target records
+// in bulk
+func destructive(p *registry.Pipeline) bool {
+	return p.Mode == registry.ModeCleanup && p.Cleanup != nil && !p.Cleanup.DryRun
+}
+
+// definitionDigest identifies a pipeline definition, so any edit needs a
+// new confirmation
+func definitionDigest(p *registry.Pipeline) (string, error) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode pipeline %s: %w", p.ID, err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// checkConfirmation lets a destructive pass proceed when the pipeline's
+// definition is confirmed or carries a change ticket, which confirms it
+// and is recorded in the audit log. Otherwise the pipeline is paused until
+// a confirmation through the API and the pass fails with
+// ErrConfirmationRequired.
+func (e *Engine) checkConfirmation(p *registry.Pipeline) error {
+	if !destructive(p) {
+		return nil
+	}
+	digest, err := definitionDigest(p)
+	if err != nil {
+		return err
+	}
+
+	e.confirmMu.Lock()
+	defer e.confirmMu.Unlock()
+
+	var last Confirmation
+	found, err := e.store.Load(confirmationKey(p.ID), &last)
+	if err != nil {
+		return err
+	}
+	if found && last.Definition == digest {
+		return nil
+	}
+
+	if ticket := p.Cleanup.ChangeTicket; ticket != "" {
+		c := Confirmation{PipelineID: p.ID, Definition: digest, Ticket: ticket, ConfirmedAt: time.Now().UTC()}
+		if err := e.store.Save(confirmationKey(p.ID), c); err != nil {
+			return err
+		}
+		log.Printf("[Engine] Pipeline %s definition %s confirmed by change ticket %s", p.ID, digest[:12], ticket)
+		e.audit(AuditEntry{PipelineID: p.ID, Policy: policyConfirm, Decision: AuditAllowed, Detail: "definition " + digest + " confirmed by change ticket " + ticket})
+		return nil
+	}
+
+	e.audit(AuditEntry{PipelineID: p.ID, Policy: policyConfirm, Decision: AuditDenied, Detail: "definition " + digest + " awaits confirmation"})
+	if err := e.pauseFor(p.ID, pauseReasonConfirm); err != nil {
+		return err
+	}
+	return fmt.Errorf("pipeline %s paused until definition %s is confirmed: %w", p.ID, digest[:12], ErrConfirmationRequired)
+}
+
+// Confirmation returns whether the current definition of a destructive
+// pipeline is confirmed
+func (e *Engine) Confirmation(pipelineID string) (*ConfirmationState, error) {
+	p, err := e.registry.GetByID(pipelineID)
+	if err != nil {
+		return nil, err
+	}
+	if !destructive(p) {
+		return nil, fmt.Errorf("%s: %w", p.ID, ErrNotDestructive)
+	}
+	digest, err := definitionDigest(p)
+	if err != nil {
+		return nil, err
+	}
+
+	e.confirmMu.Lock()
+	defer e.confirmMu.Unlock()
+
+	state := &ConfirmationState{PipelineID: p.ID, Definition: digest}
+	var last Confirmation
+	found, err := e.store.Load(confirmationKey(p.ID), &last)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		state.Confirmation = &last
+		state.Confirmed = last.Definition == digest
+	}
+	return state, nil
+}
+
+// Confirm confirms the definition of a destructive pipeline with the given
+// digest, as read from Confirmation by a second person reviewing it, and
+// resumes the pipeline if it was paused awaiting confirmation. The
+// confirmation and the ticket it refers to are recorded in the audit log.
+func (e *Engine) Confirm(pipelineID, digest, ticket, by string) (*Confirmation, error) {
+	p, err := e.registry.GetByID(pipelineID)
+	if err != nil {
+		return nil, err
+	}
+	if !destructive(p) {
+		return nil, fmt.Errorf("%s: %w", p.ID, ErrNotDestructive)
+	}
+	current, err := definitionDigest(p)
+	if err != nil {
+		return nil, err
+	}
+	if digest != current {
+		return nil, fmt.Errorf("%s is at definition %s, not %s: %w", p.ID, current, digest, ErrDefinitionChanged)
+	}
+
+	e.confirmMu.Lock()
+	c := Confirmation{PipelineID: p.ID, Definition: digest, Ticket: ticket, By: by, ConfirmedAt: time.Now().UTC()}
+	err = e.store.Save(confirmationKey(p.ID), c)
+	e.confirmMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	detail := []string{"definition " + digest + " confirmed"}
+	if by != "" {
+		detail = append(detail, "by "+by)
+	}
+	if ticket != "" {
+		detail = append(detail, "for change ticket "+ticket)
+	}
+	log.Printf("[Engine] Pipeline %s %s", p.ID, strings.Join(detail, " "))
+	e.audit(AuditEntry{PipelineID: p.ID, Policy: policyConfirm, Decision: AuditAllowed, Detail: strings.Join(detail, " ")})
+	if err := e.resumeFor(p.ID, pauseReasonConfirm); err != nil {
+		return &c, err
+	}
+	return &c, nil
+}
+
+// confirmationKey is the state key of the last confirmation of a pipeline
+func confirmationKey(pipelineID string) string {
+	return "confirmations/" + pipelineID
+}
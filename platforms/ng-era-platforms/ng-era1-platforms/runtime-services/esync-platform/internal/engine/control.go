@@ -0,0 +1,100 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: pipeline-control
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Pipeline Pause/Resume Control
+ */
+
+package engine
+
+import (
+	"errors"
+	"log"
+	"time"
+)
+
+// ErrPaused is returned when a run is requested for a paused pipeline
+var ErrPaused = errors.New("pipeline is paused")
+
+// pauseState is the persisted marker of a paused pipeline
+type pauseState struct {
+	PausedAt time.Time `json:"paused_at"`
+	// Reason is empty for operator pauses and names the engine feature
+	// otherwise, so that feature can lift its own pause
+	Reason string `json:"reason,omitempty"`
+}
+
+// Pause stops new runs of a pipeline until it is resumed
+func (e *Engine) Pause(pipelineID string) error {
+	if _, err := e.registry.GetByID(pipelineID); err != nil {
+		return err
+	}
+
+	if err := e.store.Save("paused/"+pipelineID, pauseState{PausedAt: time.Now().UTC()}); err != nil {
+		return err
+	}
+	e.publish(EventPaused, pipelineID, nil, "")
+	return nil
+}
+
+// Resume allows runs of a paused pipeline again
+func (e *Engine) Resume(pipelineID string) error {
+	if _, err := e.registry.GetByID(pipelineID); err != nil {
+		return err
+	}
+
+	if err := e.store.Delete("paused/" + pipelineID); err != nil {
+		return err
+	}
+	e.publish(EventResumed, pipelineID, nil, "")
+	return nil
+}
+
+// pauseFor pauses a pipeline on behalf of an engine feature, leaving an
+// existing pause untouched
+func (e *Engine) pauseFor(pipelineID, reason string) error {
+	var st pauseState
+	found, err := e.store.Load("paused/"+pipelineID, &st)
+	if err != nil || found {
+		return err
+	}
+
+	log.Printf("[Engine] Pausing pipeline %s (%s)", pipelineID, reason)
+	if err := e.store.Save("paused/"+pipelineID, pauseState{PausedAt: time.Now().UTC(), Reason: reason}); err != nil {
+		return err
+	}
+	e.publish(EventPaused, pipelineID, nil, reason)
+	return nil
+}
+
+// resumeFor lifts a pause made by pauseFor with the same reason; operator
+// pauses are kept
+func (e *Engine) resumeFor(pipelineID, reason string) error {
+	var st pauseState
+	found, err := e.store.Load("paused/"+pipelineID, &st)
+	if err != nil || !found || st.Reason != reason {
+		return err
+	}
+
+	log.Printf("[Engine] Resuming pipeline %s (%s)", pipelineID, reason)
+	if err := e.store.Delete("paused/" + pipelineID); err != nil {
+		return err
+	}
+	e.publish(EventResumed, pipelineID, nil, reason)
+	return nil
+}
+
+// Paused reports whether a pipeline is paused
+func (e *Engine) Paused(pipelineID string) (bool, error) {
+	var st pauseState
+	return e.store.Load("paused/"+pipelineID, &st)
+}
@@ -0,0 +1,343 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: credential-rotation
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Connection Manager and Credential Rotation
+ */
+
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/fips"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+	"github.com/machine-native-ops/esync-platform/internal/secrets"
+	"github.com/machine-native-ops/esync-platform/internal/tunnel"
+)
+
+// reconfigureTimeout bounds handing new credentials to a connector
+const reconfigureTimeout = 30 * time.Second
+
+// managedConnector is a connector kept open across runs together with the
+// secret values it was configured with and the SSH tunnel it dials through
+type managedConnector struct {
+	spec    registry.ConnectorSpec
+	conn    connectors.Connector
+	secrets map[string]string
+	tunnel  *tunnel.Tunnel
+}
+
+// connector returns the open connector of a spec, creating it on first
+// use. When a secret the spec references changed since, the connector is
+// reconfigured or reconnected first.
+func (e *Engine) connector(spec registry.ConnectorSpec) (connectors.Connector, error) {
+	resolved, err := e.registry.ResolveConnector(spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := fips.Validate(resolved.Config); err != nil {
+		return nil, err
+	}
+	key, err := connectorKey(resolved)
+	if err != nil {
+		return nil, err
+	}
+	config, refs, err := e.resolver.ExpandConfig(resolved.Config, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve config: %w", err)
+	}
+	values := secretValues(config, refs)
+
+	e.connsMu.Lock()
+	defer e.connsMu.Unlock()
+
+	m, exists := e.conns[key]
+	if !exists {
+		config, tun, err := openTunnel(config)
+		if err != nil {
+			return nil, err
+		}
+		conn, err := connectors.New(resolved.Type, config)
+		if err != nil {
+			closeTunnel(tun)
+			return nil, err
+		}
+		e.instrument(resolved, conn)
+		e.limit(resolved, conn)
+		e.conns[key] = &managedConnector{spec: resolved, conn: conn, secrets: values, tunnel: tun}
+		return conn, nil
+	}
+
+	if resolved.Connection != "" {
+		e.connectionLimiter(resolved.Connection)
+	}
+	if changed := changedSecrets(m.secrets, values); len(changed) > 0 {
+		if err := e.rotate(m, config, values, changed); err != nil {
+			return nil, err
+		}
+	}
+	return m.conn, nil
+}
+
+// rotate applies changed secrets to a managed connector: connectors that
+// implement connectors.Reconfigurer receive the new credentials, others are
+// replaced by a new connector. A changed tunnel secret reopens the tunnel
+// and always reconnects. Callers hold e.connsMu.
+func (e *Engine) rotate(m *managedConnector, config map[string]interface{}, values map[string]string, changed []string) error {
+	tun, retunnel := m.tunnel, tunnelChanged(changed)
+	if retunnel {
+		var err error
+		if config, tun, err = openTunnel(config); err != nil {
+			return fmt.Errorf("failed to reopen tunnel with rotated credentials: %w", err)
+		}
+	} else if tun != nil {
+		config = tun.Rewrite(config)
+	}
+
+	if r, ok := m.conn.(connectors.Reconfigurer); ok && !retunnel {
+		ctx, cancel := context.WithTimeout(context.Background(), reconfigureTimeout)
+		defer cancel()
+
+		err := r.Reconfigure(ctx, connectors.Credentials{Config: config, Changed: changed})
+		if err == nil {
+			log.Printf("[Engine] Reconfigured %s connector with rotated credentials %v", m.spec.Type, changed)
+			m.secrets = values
+			return nil
+		}
+		if !errors.Is(err, connectors.ErrUnsupported) {
+			log.Printf("[Engine] Failed to reconfigure %s connector, reconnecting: %v", m.spec.Type, err)
+		}
+	}
+
+	conn, err := connectors.New(m.spec.Type, config)
+	if err != nil {
+		if retunnel {
+			closeTunnel(tun)
+		}
+		return fmt.Errorf("failed to reconnect with rotated credentials: %w", err)
+	}
+	if closer, ok := m.conn.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			log.Printf("[Engine] Failed to close %s connector: %v", m.spec.Type, err)
+		}
+	}
+	if retunnel {
+		closeTunnel(m.tunnel)
+	}
+	e.instrument(m.spec, conn)
+	e.limit(m.spec, conn)
+	log.Printf("[Engine] Reconnected %s connector with rotated credentials %v", m.spec.Type, changed)
+	m.conn, m.secrets, m.tunnel = conn, values, tun
+	return nil
+}
+
+// connectorMetrics exports the stats an instrumented connector reports
+type connectorMetrics struct {
+	engine *Engine
+	spec   registry.ConnectorSpec
+}
+
+// instrument hands a new connector its metrics reporter when it reports
+// internal stats
+func (e *Engine) instrument(spec registry.ConnectorSpec, conn connectors.Connector) {
+	if i, ok := conn.(connectors.Instrumented); ok {
+		i.Instrument(connectorMetrics{engine: e, spec: spec})
+	}
+}
+
+// Count implements connectors.MetricsReporter
+func (m connectorMetrics) Count(name string, delta float64) {
+	if err := m.engine.monitor.AddConnectorCounter(m.spec.Type, m.spec.Connection, name, delta); err != nil {
+		log.Printf("[Engine] %s connector: %v", m.spec.Type, err)
+	}
+}
+
+// Gauge implements connectors.MetricsReporter
+func (m connectorMetrics) Gauge(name string, value float64) {
+	if err := m.engine.monitor.SetConnectorGauge(m.spec.Type, m.spec.Connection, name, value); err != nil {
+		log.Printf("[Engine] %s connector: %v", m.spec.Type, err)
+	}
+}
+
+// Observe implements connectors.MetricsReporter
+func (m connectorMetrics) Observe(name string, value float64) {
+	if err := m.engine.monitor.ObserveConnector(m.spec.Type, m.spec.Connection, name, value); err != nil {
+		log.Printf("[Engine] %s connector: %v", m.spec.Type, err)
+	}
+}
+
+// CloseConnectors closes every open connector and the tunnels they dial
+// through
+func (e *Engine) CloseConnectors() {
+	e.connsMu.Lock()
+	defer e.connsMu.Unlock()
+
+	for key, m := range e.conns {
+		if closer, ok := m.conn.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				log.Printf("[Engine] Failed to close %s connector: %v", m.spec.Type, err)
+			}
+		}
+		closeTunnel(m.tunnel)
+		delete(e.conns, key)
+	}
+}
+
+// openTunnel starts the SSH tunnel of a config with a tunnel block and
+// returns the config pointing at it; other configs are returned unchanged
+func openTunnel(config map[string]interface{}) (map[string]interface{}, *tunnel.Tunnel, error) {
+	block, ok := config["tunnel"].(map[string]interface{})
+	if !ok {
+		return config, nil, nil
+	}
+	spec, err := tunnel.FromConfig(block)
+	if err != nil {
+		return nil, nil, err
+	}
+	host, _ := config["host"].(string)
+	port, exists := config["port"]
+	if host == "" || !exists {
+		return nil, nil, fmt.Errorf("tunnel requires the connector host and port")
+	}
+
+	t, err := tunnel.Open(spec, net.JoinHostPort(host, fmt.Sprint(port)))
+	if err != nil {
+		return nil, nil, err
+	}
+	return t.Rewrite(config), t, nil
+}
+
+// closeTunnel closes a tunnel no connector uses anymore
+func closeTunnel(t *tunnel.Tunnel) {
+	if t == nil {
+		return
+	}
+	if err := t.Close(); err != nil {
+		log.Printf("[Engine] Failed to close tunnel: %v", err)
+	}
+}
+
+// tunnelChanged reports whether a changed secret belongs to the tunnel
+func tunnelChanged(changed []string) bool {
+	for _, path := range changed {
+		if strings.HasPrefix(path, "tunnel.") {
+			return true
+		}
+	}
+	return false
+}
+
+// WatchCredentials re-resolves the secrets of every open connector each
+// interval, rotating connectors whose secrets changed without waiting for
+// their next run. It returns when ctx is cancelled.
+func (e *Engine) WatchCredentials(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		e.connsMu.Lock()
+		specs := make([]registry.ConnectorSpec, 0, len(e.conns))
+		for _, m := range e.conns {
+			specs = append(specs, m.spec)
+		}
+		e.connsMu.Unlock()
+
+		for _, spec := range specs {
+			if _, err := e.connector(spec); err != nil {
+				log.Printf("[Engine] Failed to refresh credentials of %s connector: %v", spec.Type, err)
+			}
+		}
+	}
+}
+
+// connectorKey identifies a connector by its type and unexpanded config, so
+// secret changes keep the key
+func connectorKey(spec registry.ConnectorSpec) (string, error) {
+	key, err := json.Marshal([]interface{}{spec.Type, spec.Config})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode connector config: %w", err)
+	}
+	return string(key), nil
+}
+
+// secretValues collects the expanded values of secret references by path
+func secretValues(config map[string]interface{}, refs []secrets.Reference) map[string]string {
+	values := make(map[string]string, len(refs))
+	for _, ref := range refs {
+		values[ref.Path] = fmt.Sprint(valueAt(config, ref.Path))
+	}
+	return values
+}
+
+// changedSecrets lists the paths whose secret values differ
+func changedSecrets(before, after map[string]string) []string {
+	var changed []string
+	for path, value := range after {
+		if old, exists := before[path]; !exists || old != value {
+			changed = append(changed, path)
+		}
+	}
+	for path := range before {
+		if _, exists := after[path]; !exists {
+			changed = append(changed, path)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// valueAt returns the config value at a dotted path such as a.b[1].c
+func valueAt(config map[string]interface{}, path string) interface{} {
+	var v interface{} = config
+	for _, part := range strings.Split(path, ".") {
+		name, index := part, ""
+		if i := strings.IndexByte(part, '['); i >= 0 {
+			name, index = part[:i], part[i:]
+		}
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = m[name]
+		for index != "" {
+			end := strings.IndexByte(index, ']')
+			if end < 0 {
+				return nil
+			}
+			i, err := strconv.Atoi(index[1:end])
+			list, ok := v.([]interface{})
+			if err != nil || !ok || i < 0 || i >= len(list) {
+				return nil
+			}
+			v, index = list[i], index[end+1:]
+		}
+	}
+	return v
+}
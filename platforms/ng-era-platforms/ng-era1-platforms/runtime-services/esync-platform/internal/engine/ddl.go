@@ -0,0 +1,281 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: ddl-propagation
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * DDL Change Capture and Propagation
+ */
+
+package engine
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// Statuses of captured schema changes
+const (
+	DDLPending  = "pending"
+	DDLApplied  = "applied"
+	DDLIgnored  = "ignored"
+	DDLRejected = "rejected"
+	DDLFailed   = "failed"
+)
+
+// ddlHistorySize bounds the decided schema changes kept per pipeline
+const ddlHistorySize = 100
+
+// pauseReasonDDL marks pipelines paused for schema change approval
+const pauseReasonDDL = "ddl"
+
+var (
+	// ErrDDLPending is returned by passes of pipelines holding schema
+	// changes that await approval
+	ErrDDLPending = errors.New("schema changes await approval")
+	// ErrDDLNotFound is returned when deciding an unknown schema change
+	ErrDDLNotFound = errors.New("schema change not found")
+	// ErrDDLDecided is returned when deciding a change that is not pending
+	ErrDDLDecided = errors.New("schema change already decided")
+)
+
+// DDLChange is a captured schema change and what became of it
+type DDLChange struct {
+	ID         string              `json:"id"`
+	Event      connectors.DDLEvent `json:"event"`
+	Status     string              `json:"status"`
+	DetectedAt time.Time           `json:"detected_at"`
+	DecidedAt  time.Time           `json:"decided_at,omitempty"`
+	Error      string              `json:"error,omitempty"`
+}
+
+// ddlID identifies a schema change by its position, table and statement,
+// so the same event listed by later passes is recognized
+func ddlID(event connectors.DDLEvent) string {
+	sum := sha256.Sum256([]byte(event.Position + "\x00" + event.Table + "\x00" + event.Statement))
+	return hex.EncodeToString(sum[:8])
+}
+
+// captureDDL records the schema changes after checkpoint and handles them
+// by the pipeline's DDL policy before the data that follows them is applied.
+// Under the approve policy new changes pause the pipeline and the pass fails
+// with ErrDDLPending.
+func (e *Engine) captureDDL(ctx context.Context, p *registry.Pipeline, source, target connectors.Connector, checkpoint *connectors.Checkpoint) error {
+	if p.DDL == nil {
+		return nil
+	}
+	ddlSource, ok := source.(connectors.DDLSource)
+	if !ok {
+		return nil
+	}
+
+	events, err := ddlSource.ListDDL(ctx, checkpoint)
+	if errors.Is(err, connectors.ErrUnsupported) {
+		return nil
+	}
+	if err != nil {
+		e.recordError(p.ID, "source", err)
+		return fmt.Errorf("failed to list schema changes: %w", err)
+	}
+
+	e.ddlMu.Lock()
+	defer e.ddlMu.Unlock()
+
+	changes, err := e.loadDDL(p.ID)
+	if err != nil {
+		return err
+	}
+	index := make(map[string]int, len(changes))
+	for i, c := range changes {
+		index[c.ID] = i
+	}
+
+	var applyErr error
+	for _, event := range events {
+		id := ddlID(event)
+		i, seen := index[id]
+		if seen && changes[i].Status != DDLFailed {
+			continue
+		}
+		if !seen {
+			changes = append(changes, DDLChange{ID: id, Event: event, DetectedAt: time.Now().UTC()})
+			i = len(changes) - 1
+			index[id] = i
+			log.Printf("[Engine] Pipeline %s captured schema change on %s: %s", p.ID, event.Table, event.Statement)
+		}
+
+		c := &changes[i]
+		switch p.DDL.Policy {
+		case registry.DDLPropagate:
+			if applyErr == nil {
+				applyErr = e.applyDDL(ctx, p, target, c)
+			}
+		case registry.DDLApprove:
+			c.Status = DDLPending
+		default:
+			c.Status = DDLIgnored
+			c.DecidedAt = time.Now().UTC()
+		}
+	}
+
+	if err := e.saveDDL(p.ID, changes); err != nil {
+		return err
+	}
+	if applyErr != nil {
+		return applyErr
+	}
+
+	if pending := countPending(changes); pending > 0 {
+		if err := e.pauseFor(p.ID, pauseReasonDDL); err != nil {
+			return err
+		}
+		return fmt.Errorf("pipeline %s paused with %d schema changes: %w", p.ID, pending, ErrDDLPending)
+	}
+	return nil
+}
+
+// applyDDL replays a schema change on the target the change's table is
+// routed to, recording the outcome on the change
+func (e *Engine) applyDDL(ctx context.Context, p *registry.Pipeline, target connectors.Connector, c *DDLChange) error {
+	if route := routeFor(p, c.Event.Table); route >= 0 {
+		var err error
+		if target, err = e.connector(p.Routes[route].Target); err != nil {
+			return e.failDDL(p.ID, c, err)
+		}
+	}
+
+	applier, ok := target.(connectors.DDLApplier)
+	if !ok {
+		return e.failDDL(p.ID, c, connectors.ErrUnsupported)
+	}
+	if err := applier.ApplyDDL(ctx, c.Event); err != nil {
+		return e.failDDL(p.ID, c, err)
+	}
+
+	c.Status = DDLApplied
+	c.DecidedAt = time.Now().UTC()
+	c.Error = ""
+	log.Printf("[Engine] Pipeline %s applied schema change %s on %s", p.ID, c.ID, c.Event.Table)
+	return nil
+}
+
+// failDDL marks a schema change as failed; failed changes are retried by
+// the next pass or approval
+func (e *Engine) failDDL(pipelineID string, c *DDLChange, err error) error {
+	c.Status = DDLFailed
+	c.Error = err.Error()
+	err = fmt.Errorf("failed to apply schema change %s on %s: %w", c.ID, c.Event.Table, err)
+	e.recordError(pipelineID, "ddl", err)
+	return err
+}
+
+// DDLChanges returns the captured schema changes of a pipeline, oldest first
+func (e *Engine) DDLChanges(pipelineID string) ([]DDLChange, error) {
+	e.ddlMu.Lock()
+	defer e.ddlMu.Unlock()
+
+	return e.loadDDL(pipelineID)
+}
+
+// DecideDDL approves or rejects a pending schema change. Approved changes
+// are applied to the target; rejected ones are skipped. Once no change is
+// pending the pipeline resumes if it was paused for approval.
+func (e *Engine) DecideDDL(ctx context.Context, pipelineID, changeID string, approve bool) (*DDLChange, error) {
+	p, err := e.registry.GetByID(pipelineID)
+	if err != nil {
+		return nil, err
+	}
+
+	e.ddlMu.Lock()
+	defer e.ddlMu.Unlock()
+
+	changes, err := e.loadDDL(p.ID)
+	if err != nil {
+		return nil, err
+	}
+	var c *DDLChange
+	for i := range changes {
+		if changes[i].ID == changeID {
+			c = &changes[i]
+		}
+	}
+	if c == nil {
+		return nil, fmt.Errorf("%s: %w", changeID, ErrDDLNotFound)
+	}
+	if c.Status != DDLPending && c.Status != DDLFailed {
+		return nil, fmt.Errorf("%s is %s: %w", changeID, c.Status, ErrDDLDecided)
+	}
+
+	var applyErr error
+	if approve {
+		var target connectors.Connector
+		if target, applyErr = e.connector(p.Target); applyErr == nil {
+			applyErr = e.applyDDL(ctx, p, target, c)
+		}
+	} else {
+		c.Status = DDLRejected
+		c.DecidedAt = time.Now().UTC()
+		log.Printf("[Engine] Pipeline %s rejected schema change %s on %s", p.ID, c.ID, c.Event.Table)
+	}
+	decided := *c
+
+	if err := e.saveDDL(p.ID, changes); err != nil {
+		return nil, err
+	}
+	if applyErr != nil {
+		return &decided, applyErr
+	}
+
+	if countPending(changes) == 0 {
+		if err := e.resumeFor(p.ID, pauseReasonDDL); err != nil {
+			return &decided, err
+		}
+	}
+	return &decided, nil
+}
+
+// countPending counts the changes that block the pipeline
+func countPending(changes []DDLChange) int {
+	n := 0
+	for _, c := range changes {
+		if c.Status == DDLPending {
+			n++
+		}
+	}
+	return n
+}
+
+// loadDDL reads the stored schema changes of a pipeline
+func (e *Engine) loadDDL(pipelineID string) ([]DDLChange, error) {
+	var changes []DDLChange
+	_, err := e.store.Load("ddl/"+pipelineID, &changes)
+	return changes, err
+}
+
+// saveDDL stores the schema changes of a pipeline, dropping the oldest
+// decided changes beyond ddlHistorySize
+func (e *Engine) saveDDL(pipelineID string, changes []DDLChange) error {
+	for i := 0; len(changes) > ddlHistorySize && i < len(changes); {
+		if s := changes[i].Status; s == DDLPending || s == DDLFailed {
+			i++
+			continue
+		}
+		changes = append(changes[:i], changes[i+1:]...)
+	}
+	return e.store.Save("ddl/"+pipelineID, changes)
+}
@@ -0,0 +1,739 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: reporting-digests
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Scheduled Reporting Digests
+ */
+
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// Digest schedules
+const (
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
+const (
+	// digestTick is how often WatchDigests samples freshness objectives and
+	// sends the digests that are due
+	digestTick = time.Minute
+	// digestRetry is the wait before a digest that failed to send is sent
+	// again
+	digestRetry = 15 * time.Minute
+	// defaultDigestAt is the time of day digests are sent at by default
+	defaultDigestAt = "08:00"
+	// digestErrors bounds the distinct errors counted per pipeline and
+	// digestTopErrors those reported
+	digestErrors    = 20
+	digestTopErrors = 3
+)
+
+// ErrUnknownDigest is returned for digests the digest file does not define
+var ErrUnknownDigest = errors.New("unknown digest")
+
+// digestNamePattern is what digest names may look like
+var digestNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,127}$`)
+
+// DigestConfig is a digest file sending daily or weekly reports on the
+// pipelines of a namespace or team to a notify target, so stakeholders stay
+// informed without dashboard access:
+//
+//	digests:
+//	  - name: payments-weekly
+//	    namespace: payments
+//	    every: weekly
+//	    weekday: monday
+//	    at: "08:00"
+//	    timezone: Europe/Berlin
+//	    target:
+//	      type: notify
+//	      config:
+//	        channel: email
+//	        email:
+//	          host: smtp.example.com
+//	          from: esync@example.com
+//	          to: [payments-leads@example.com]
+//
+// The target is configured like a pipeline target, including connection
+// profiles and secret references. Each digest is sent as one record whose
+// data holds the summary, the totals and a row per pipeline; notify targets
+// without a subject or body send its title and text summary.
+type DigestConfig struct {
+	Digests []DigestSpec `yaml:"digests"`
+}
+
+// DigestSpec is one digest of a digest file
+type DigestSpec struct {
+	Name string `yaml:"name"`
+	// Namespace and Owner select the pipelines reported on; without
+	// either the digest covers every pipeline
+	Namespace string `yaml:"namespace,omitempty"`
+	Owner     string `yaml:"owner,omitempty"`
+	// Every is daily (default) or weekly; weekly digests are sent on
+	// Weekday (default monday)
+	Every   string `yaml:"every,omitempty"`
+	Weekday string `yaml:"weekday,omitempty"`
+	// At is the time of day the digest is sent, 08:00 by default, in
+	// Timezone (default UTC)
+	At       string                 `yaml:"at,omitempty"`
+	Timezone string                 `yaml:"timezone,omitempty"`
+	Target   registry.ConnectorSpec `yaml:"target"`
+
+	location *time.Location
+	weekday  time.Weekday
+	hour     int
+	minute   int
+}
+
+// LoadDigests reads a digest file
+func LoadDigests(path string) (*DigestConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read digests: %w", err)
+	}
+
+	var config DigestConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse digests %s: %w", path, err)
+	}
+	if err := config.compile(); err != nil {
+		return nil, fmt.Errorf("invalid digests %s: %w", path, err)
+	}
+	return &config, nil
+}
+
+// compile validates the digests and parses their schedules
+func (c *DigestConfig) compile() error {
+	names := make(map[string]bool)
+	for i := range c.Digests {
+		d := &c.Digests[i]
+		if !digestNamePattern.MatchString(d.Name) {
+			return fmt.Errorf("digest %d: name %q must be letters, digits, '.', '_' and '-'", i+1, d.Name)
+		}
+		if names[d.Name] {
+			return fmt.Errorf("digest %s is defined twice", d.Name)
+		}
+		names[d.Name] = true
+		if err := d.compile(); err != nil {
+			return fmt.Errorf("digest %s: %w", d.Name, err)
+		}
+	}
+	return nil
+}
+
+// compile validates a digest and parses its schedule
+func (d *DigestSpec) compile() error {
+	if d.Target.Type == "" && d.Target.Connection == "" {
+		return fmt.Errorf("target type or connection is required")
+	}
+	if d.Every == "" {
+		d.Every = DigestDaily
+	}
+	if d.Every != DigestDaily && d.Every != DigestWeekly {
+		return fmt.Errorf("every must be %s or %s, not %q", DigestDaily, DigestWeekly, d.Every)
+	}
+	d.weekday = time.Monday
+	if d.Weekday != "" {
+		if d.Every != DigestWeekly {
+			return fmt.Errorf("weekday applies to weekly digests only")
+		}
+		found := false
+		for wd := time.Sunday; wd <= time.Saturday; wd++ {
+			if strings.EqualFold(d.Weekday, wd.String()) {
+				d.weekday, found = wd, true
+			}
+		}
+		if !found {
+			return fmt.Errorf("unknown weekday %q", d.Weekday)
+		}
+	}
+	if d.At == "" {
+		d.At = defaultDigestAt
+	}
+	at, err := time.Parse("15:04", d.At)
+	if err != nil {
+		return fmt.Errorf("at must be a time of day such as 08:00, not %q", d.At)
+	}
+	d.hour, d.minute = at.Hour(), at.Minute()
+	if d.location, err = time.LoadLocation(d.Timezone); err != nil {
+		return fmt.Errorf("unknown timezone %q: %w", d.Timezone, err)
+	}
+	return nil
+}
+
+// covers reports whether a pipeline is reported on by the digest
+func (d *DigestSpec) covers(p *registry.Pipeline) bool {
+	return (d.Namespace == "" || p.Namespace == d.Namespace) && (d.Owner == "" || p.Owner == d.Owner)
+}
+
+// scope describes the pipelines the digest covers
+func (d *DigestSpec) scope() string {
+	switch {
+	case d.Namespace != "" && d.Owner != "":
+		return fmt.Sprintf("namespace %s owned by %s", d.Namespace, d.Owner)
+	case d.Namespace != "":
+		return "namespace " + d.Namespace
+	case d.Owner != "":
+		return "owner " + d.Owner
+	default:
+		return "all pipelines"
+	}
+}
+
+// occurrence returns the latest send time of the digest at or before now,
+// or with next the first one after now
+func (d *DigestSpec) occurrence(now time.Time, next bool) time.Time {
+	local := now.In(d.location)
+	for i := 0; i <= 8; i++ {
+		day := -i
+		if next {
+			day = i
+		}
+		at := time.Date(local.Year(), local.Month(), local.Day()+day, d.hour, d.minute, 0, 0, d.location)
+		if at.After(local) != next || d.Every == DigestWeekly && at.Weekday() != d.weekday {
+			continue
+		}
+		return at
+	}
+	return time.Time{}
+}
+
+// digestState is what a digest accumulated since it was last sent, kept in
+// the store so restarts lose nothing
+type digestState struct {
+	// Since is the start of the reported period
+	Since time.Time `json:"since"`
+	// Sent is the send time of the schedule last sent for; SentAt is when
+	// it was sent
+	Sent      time.Time `json:"sent"`
+	SentAt    time.Time `json:"sent_at,omitempty"`
+	RetryAt   time.Time `json:"retry_at,omitempty"`
+	LastError string    `json:"last_error,omitempty"`
+
+	Pipelines map[string]*digestTally `json:"pipelines"`
+
+	dirty bool
+}
+
+// digestTally is what one pipeline did in the reported period
+type digestTally struct {
+	Runs    int `json:"runs"`
+	Failed  int `json:"failed"`
+	Records int `json:"records"`
+	Invalid int `json:"invalid"`
+	// Errors counts the failed runs by error
+	Errors map[string]int `json:"errors,omitempty"`
+	// SLOSamples counts the freshness samples taken and SLOFresh those
+	// within the objective
+	SLOSamples int `json:"slo_samples,omitempty"`
+	SLOFresh   int `json:"slo_fresh,omitempty"`
+}
+
+// Digest is the report a digest sends
+type Digest struct {
+	Name        string    `json:"name"`
+	Title       string    `json:"title"`
+	Scope       string    `json:"scope"`
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+	// Health counts the pipelines by health level
+	Health     map[string]int `json:"health"`
+	Runs       int            `json:"runs"`
+	FailedRuns int            `json:"failed_runs"`
+	Records    int            `json:"records"`
+	// SLOCompliance is the share of the period in percent the pipelines
+	// with a freshness objective met it
+	SLOCompliance *float64         `json:"slo_compliance,omitempty"`
+	Pipelines     []DigestPipeline `json:"pipelines"`
+	// Summary is the digest as plain text
+	Summary string `json:"summary"`
+}
+
+// DigestPipeline is the row of one pipeline in a digest
+type DigestPipeline struct {
+	PipelineID    string        `json:"pipeline_id"`
+	Owner         string        `json:"owner,omitempty"`
+	Health        string        `json:"health"`
+	Score         int           `json:"score"`
+	Reasons       []string      `json:"reasons,omitempty"`
+	Runs          int           `json:"runs"`
+	FailedRuns    int           `json:"failed_runs"`
+	Records       int           `json:"records"`
+	Invalid       int           `json:"invalid,omitempty"`
+	SLOCompliance *float64      `json:"slo_compliance,omitempty"`
+	Errors        []DigestError `json:"errors,omitempty"`
+}
+
+// DigestError is an error failed runs of a pipeline ended with
+type DigestError struct {
+	Message string `json:"message"`
+	Count   int    `json:"count"`
+}
+
+// DigestStatus is a configured digest and when it is sent
+type DigestStatus struct {
+	Name      string    `json:"name"`
+	Scope     string    `json:"scope"`
+	Every     string    `json:"every"`
+	Weekday   string    `json:"weekday,omitempty"`
+	At        string    `json:"at"`
+	Timezone  string    `json:"timezone,omitempty"`
+	Since     time.Time `json:"since,omitempty"`
+	SentAt    time.Time `json:"sent_at,omitempty"`
+	NextAt    time.Time `json:"next_at"`
+	LastError string    `json:"last_error,omitempty"`
+}
+
+// SetDigests replaces the digests; nil disables them. What the digests
+// kept accumulated is kept for those still configured.
+func (e *Engine) SetDigests(config *DigestConfig) error {
+	if config != nil {
+		if err := config.compile(); err != nil {
+			return err
+		}
+	}
+
+	e.digestsMu.Lock()
+	defer e.digestsMu.Unlock()
+
+	e.digestConfig = config
+	for name := range e.digests {
+		if e.digestSpec(name) == nil {
+			delete(e.digests, name)
+		}
+	}
+	return nil
+}
+
+// digestSpec returns a configured digest; the caller holds e.digestsMu
+func (e *Engine) digestSpec(name string) *DigestSpec {
+	if e.digestConfig == nil {
+		return nil
+	}
+	for i := range e.digestConfig.Digests {
+		if e.digestConfig.Digests[i].Name == name {
+			return &e.digestConfig.Digests[i]
+		}
+	}
+	return nil
+}
+
+// digestState returns what a digest accumulated, loading it from the store
+// on first use; the caller holds e.digestsMu
+func (e *Engine) digestState(name string, now time.Time) *digestState {
+	if st := e.digests[name]; st != nil {
+		return st
+	}
+	st := &digestState{}
+	if _, err := e.store.Load("digests/"+name, st); err != nil {
+		log.Printf("[Engine] Failed to load digest %s, starting a new period: %v", name, err)
+		st = &digestState{}
+	}
+	if st.Since.IsZero() {
+		st.Since, st.dirty = now.UTC(), true
+	}
+	if st.Pipelines == nil {
+		st.Pipelines = make(map[string]*digestTally)
+	}
+	e.digests[name] = st
+	return st
+}
+
+// tally returns the tally of a pipeline
+func (st *digestState) tally(pipelineID string) *digestTally {
+	t := st.Pipelines[pipelineID]
+	if t == nil {
+		t = &digestTally{}
+		st.Pipelines[pipelineID] = t
+	}
+	st.dirty = true
+	return t
+}
+
+// tallyDigests counts a finished run in the digests covering its pipeline
+func (e *Engine) tallyDigests(run *Run) {
+	p, err := e.registry.GetByID(run.PipelineID)
+	if err != nil {
+		return
+	}
+
+	e.digestsMu.Lock()
+	defer e.digestsMu.Unlock()
+
+	if e.digestConfig == nil {
+		return
+	}
+	for i := range e.digestConfig.Digests {
+		spec := &e.digestConfig.Digests[i]
+		if !spec.covers(p) {
+			continue
+		}
+		t := e.digestState(spec.Name, run.FinishedAt).tally(p.ID)
+		t.Runs++
+		t.Records += run.Records
+		t.Invalid += run.Invalid
+		if run.Status != StatusFailed {
+			continue
+		}
+		t.Failed++
+		if run.Error == "" {
+			continue
+		}
+		if t.Errors == nil {
+			t.Errors = make(map[string]int)
+		}
+		if _, known := t.Errors[run.Error]; known || len(t.Errors) < digestErrors {
+			t.Errors[run.Error]++
+		}
+	}
+}
+
+// WatchDigests counts finished runs and samples freshness objectives for
+// the digests, sending each when it is due, until ctx is cancelled
+func (e *Engine) WatchDigests(ctx context.Context) {
+	e.OnRunComplete(e.tallyDigests)
+
+	ticker := time.NewTicker(digestTick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			e.saveDigests()
+			return
+		case now := <-ticker.C:
+			e.EvaluateDigests(ctx, now)
+		}
+	}
+}
+
+// EvaluateDigests samples the freshness objectives of the pipelines the
+// digests cover, then sends the digests that are due
+func (e *Engine) EvaluateDigests(ctx context.Context, now time.Time) {
+	pipelines := e.registry.GetAll()
+	slos := make(map[string]*SLOStatus)
+	for _, p := range pipelines {
+		if st := e.SLOStatus(p.ID); st != nil {
+			slos[p.ID] = st
+		}
+	}
+
+	var due []string
+	e.digestsMu.Lock()
+	if e.digestConfig != nil {
+		for i := range e.digestConfig.Digests {
+			spec := &e.digestConfig.Digests[i]
+			st := e.digestState(spec.Name, now)
+			for _, p := range pipelines {
+				slo := slos[p.ID]
+				if slo == nil || !spec.covers(p) {
+					continue
+				}
+				t := st.tally(p.ID)
+				t.SLOSamples++
+				if !slo.Breached {
+					t.SLOFresh++
+				}
+			}
+
+			at := spec.occurrence(now, false)
+			switch {
+			case st.Sent.IsZero():
+				// The first period runs until the next send time
+				st.Sent, st.dirty = at.UTC(), true
+			case at.After(st.Sent) && !now.Before(st.RetryAt):
+				due = append(due, spec.Name)
+			}
+		}
+	}
+	e.digestsMu.Unlock()
+
+	for _, name := range due {
+		if _, err := e.sendDigest(ctx, name, now, true); err != nil {
+			log.Printf("[Engine] Failed to send digest %s, retrying in %s: %v", name, digestRetry, err)
+		}
+	}
+	e.saveDigests()
+}
+
+// saveDigests stores what the digests accumulated since the last save
+func (e *Engine) saveDigests() {
+	e.digestsMu.Lock()
+	defer e.digestsMu.Unlock()
+
+	for name, st := range e.digests {
+		if !st.dirty {
+			continue
+		}
+		if err := e.store.Save("digests/"+name, st); err != nil {
+			log.Printf("[Engine] Failed to save digest %s: %v", name, err)
+			continue
+		}
+		st.dirty = false
+	}
+}
+
+// Digests returns the configured digests and when they are sent
+func (e *Engine) Digests() []DigestStatus {
+	now := time.Now()
+
+	e.digestsMu.Lock()
+	defer e.digestsMu.Unlock()
+
+	out := []DigestStatus{}
+	if e.digestConfig == nil {
+		return out
+	}
+	for _, spec := range e.digestConfig.Digests {
+		st := e.digestState(spec.Name, now)
+		out = append(out, DigestStatus{
+			Name:      spec.Name,
+			Scope:     spec.scope(),
+			Every:     spec.Every,
+			Weekday:   spec.Weekday,
+			At:        spec.At,
+			Timezone:  spec.Timezone,
+			Since:     st.Since,
+			SentAt:    st.SentAt,
+			NextAt:    spec.occurrence(now, true).UTC(),
+			LastError: st.LastError,
+		})
+	}
+	return out
+}
+
+// PreviewDigest returns the digest as it would be sent now
+func (e *Engine) PreviewDigest(name string) (*Digest, error) {
+	now := time.Now()
+
+	e.digestsMu.Lock()
+	defer e.digestsMu.Unlock()
+
+	spec := e.digestSpec(name)
+	if spec == nil {
+		return nil, fmt.Errorf("%w %s", ErrUnknownDigest, name)
+	}
+	return e.digest(spec, e.digestState(name, now), now)
+}
+
+// SendDigest sends the digest now without starting a new period, for
+// trying out its target
+func (e *Engine) SendDigest(ctx context.Context, name string) (*Digest, error) {
+	return e.sendDigest(ctx, name, time.Now(), false)
+}
+
+// sendDigest sends a digest to its target. A scheduled send starts a new
+// period when it succeeds and backs off when it fails.
+func (e *Engine) sendDigest(ctx context.Context, name string, now time.Time, scheduled bool) (*Digest, error) {
+	e.digestsMu.Lock()
+	spec := e.digestSpec(name)
+	if spec == nil {
+		e.digestsMu.Unlock()
+		return nil, fmt.Errorf("%w %s", ErrUnknownDigest, name)
+	}
+	target := *spec
+	d, err := e.digest(spec, e.digestState(name, now), now)
+	e.digestsMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	err = e.deliverDigest(ctx, &target, d)
+
+	e.digestsMu.Lock()
+	defer e.digestsMu.Unlock()
+
+	if e.digestSpec(name) == nil || !scheduled {
+		return d, err
+	}
+	st := e.digestState(name, now)
+	st.dirty = true
+	if err != nil {
+		st.RetryAt, st.LastError = now.Add(digestRetry).UTC(), err.Error()
+		return nil, err
+	}
+	log.Printf("[Engine] Sent digest %s of %s to a %s target", name, spec.scope(), ledgerTargetName(spec.Target))
+	*st = digestState{
+		Since:     now.UTC(),
+		Sent:      spec.occurrence(now, false).UTC(),
+		SentAt:    now.UTC(),
+		Pipelines: make(map[string]*digestTally),
+		dirty:     true,
+	}
+	return d, nil
+}
+
+// deliverDigest applies a digest as a record to the target of its spec
+func (e *Engine) deliverDigest(ctx context.Context, spec *DigestSpec, d *Digest) error {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	record := connectors.Record{
+		ID:        fmt.Sprintf("%s-%d", d.Name, d.PeriodEnd.Unix()),
+		Operation: connectors.OperationInsert,
+		Timestamp: d.PeriodEnd,
+	}
+	if err := json.Unmarshal(data, &record.Data); err != nil {
+		return err
+	}
+
+	target := spec.Target
+	if target.Type == "notify" {
+		// Notify targets send the title and summary unless told otherwise
+		config := make(map[string]interface{}, len(target.Config)+2)
+		for k, v := range target.Config {
+			config[k] = v
+		}
+		if _, set := config["subject"]; !set {
+			config["subject"] = "{{.Data.title}}"
+		}
+		if _, set := config["body"]; !set {
+			config["body"] = "{{.Data.summary}}"
+		}
+		target.Config = config
+	}
+	conn, err := e.connector(target)
+	if err != nil {
+		return err
+	}
+	return conn.ApplyChanges(ctx, []connectors.Record{record})
+}
+
+// digest reports on the pipelines a digest covers for the period it
+// accumulated; the caller holds e.digestsMu
+func (e *Engine) digest(spec *DigestSpec, st *digestState, now time.Time) (*Digest, error) {
+	d := &Digest{
+		Name:        spec.Name,
+		Scope:       spec.scope(),
+		PeriodStart: st.Since,
+		PeriodEnd:   now.UTC(),
+		Health:      make(map[string]int),
+		Pipelines:   []DigestPipeline{},
+	}
+
+	sloSamples, sloFresh := 0, 0
+	for _, p := range e.registry.GetAll() {
+		if !spec.covers(p) {
+			continue
+		}
+		h, err := e.health(p, now)
+		if err != nil {
+			return nil, err
+		}
+		row := DigestPipeline{PipelineID: p.ID, Owner: p.Owner, Health: h.Level, Score: h.Score, Reasons: h.Reasons}
+		if t := st.Pipelines[p.ID]; t != nil {
+			row.Runs, row.FailedRuns, row.Records, row.Invalid = t.Runs, t.Failed, t.Records, t.Invalid
+			if t.SLOSamples > 0 {
+				compliance := 100 * float64(t.SLOFresh) / float64(t.SLOSamples)
+				row.SLOCompliance = &compliance
+				sloSamples += t.SLOSamples
+				sloFresh += t.SLOFresh
+			}
+			for message, count := range t.Errors {
+				row.Errors = append(row.Errors, DigestError{Message: message, Count: count})
+			}
+			sort.Slice(row.Errors, func(i, j int) bool {
+				if row.Errors[i].Count != row.Errors[j].Count {
+					return row.Errors[i].Count > row.Errors[j].Count
+				}
+				return row.Errors[i].Message < row.Errors[j].Message
+			})
+			if len(row.Errors) > digestTopErrors {
+				row.Errors = row.Errors[:digestTopErrors]
+			}
+		}
+		d.Health[h.Level]++
+		d.Runs += row.Runs
+		d.FailedRuns += row.FailedRuns
+		d.Records += row.Records
+		d.Pipelines = append(d.Pipelines, row)
+	}
+	if sloSamples > 0 {
+		compliance := 100 * float64(sloFresh) / float64(sloSamples)
+		d.SLOCompliance = &compliance
+	}
+	sort.Slice(d.Pipelines, func(i, j int) bool {
+		pi, pj := d.Pipelines[i], d.Pipelines[j]
+		switch {
+		case pi.Score != pj.Score:
+			return pi.Score < pj.Score
+		case pi.FailedRuns != pj.FailedRuns:
+			return pi.FailedRuns > pj.FailedRuns
+		default:
+			return pi.PipelineID < pj.PipelineID
+		}
+	})
+
+	d.Title = fmt.Sprintf("esync %s digest %s: %d pipelines, %d failed runs", spec.Every, spec.Name, len(d.Pipelines), d.FailedRuns)
+	d.Summary = digestSummary(spec, d)
+	return d, nil
+}
+
+// digestSummary renders a digest as plain text
+func digestSummary(spec *DigestSpec, d *Digest) string {
+	const layout = "2006-01-02 15:04 MST"
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s digest %s for %s\n", strings.ToUpper(spec.Every[:1])+spec.Every[1:], d.Name, d.Scope)
+	fmt.Fprintf(&b, "Period: %s to %s\n\n", d.PeriodStart.In(spec.location).Format(layout), d.PeriodEnd.In(spec.location).Format(layout))
+
+	var levels []string
+	for _, level := range []string{HealthHealthy, HealthDegraded, HealthUnhealthy, HealthPaused} {
+		if n := d.Health[level]; n > 0 {
+			levels = append(levels, fmt.Sprintf("%d %s", n, level))
+		}
+	}
+	fmt.Fprintf(&b, "Pipelines: %d", len(d.Pipelines))
+	if len(levels) > 0 {
+		fmt.Fprintf(&b, " (%s)", strings.Join(levels, ", "))
+	}
+	fmt.Fprintf(&b, "\nRuns: %d, %d failed\nRecords synced: %d\n", d.Runs, d.FailedRuns, d.Records)
+	if d.SLOCompliance != nil {
+		fmt.Fprintf(&b, "Freshness SLO compliance: %.1f%%\n", *d.SLOCompliance)
+	}
+
+	for _, p := range d.Pipelines {
+		fmt.Fprintf(&b, "\n%s: %s (score %d), %d runs, %d failed, %d records", p.PipelineID, p.Health, p.Score, p.Runs, p.FailedRuns, p.Records)
+		if p.SLOCompliance != nil {
+			fmt.Fprintf(&b, ", SLO %.1f%%", *p.SLOCompliance)
+		}
+		if p.Owner != "" {
+			fmt.Fprintf(&b, ", owner %s", p.Owner)
+		}
+		b.WriteString("\n")
+		if len(p.Reasons) > 0 && p.Health != HealthHealthy {
+			fmt.Fprintf(&b, "  %s\n", strings.Join(p.Reasons, "; "))
+		}
+		for _, e := range p.Errors {
+			fmt.Fprintf(&b, "  %dx %s\n", e.Count, e.Message)
+		}
+	}
+	return b.String()
+}
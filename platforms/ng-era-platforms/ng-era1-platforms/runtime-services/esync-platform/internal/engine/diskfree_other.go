@@ -0,0 +1,30 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+
+//go:build !linux && !darwin && !freebsd
+
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: preflight-disk-space
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Disk Space Probe
+ */
+
+package engine
+
+import (
+	"errors"
+	"runtime"
+)
+
+// diskFree is not implemented on this platform
+func diskFree(string) (uint64, error) {
+	return 0, errors.New("disk space check not supported on " + runtime.GOOS)
+}
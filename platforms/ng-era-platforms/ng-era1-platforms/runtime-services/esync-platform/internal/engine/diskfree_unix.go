@@ -0,0 +1,32 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+
+//go:build linux || darwin || freebsd
+
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: preflight-disk-space
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Disk Space Probe
+ */
+
+package engine
+
+import "syscall"
+
+// diskFree returns the bytes available to unprivileged users at path
+func diskFree(path string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, err
+	}
+
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil
+}
@@ -0,0 +1,122 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: dry-run
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Dry-Run Batches
+ */
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+	"github.com/machine-native-ops/esync-platform/internal/transform"
+)
+
+// dryRunErrors bounds the record errors a dry run reports
+const dryRunErrors = 10
+
+// DryRun is one batch read from a pipeline's source and taken through its
+// transforms and target validation without writing anything
+type DryRun struct {
+	PipelineID string `json:"pipeline_id"`
+	Passed     bool   `json:"passed"`
+	// Listed is the number of records the source listed from the stored
+	// checkpoint, heartbeats excluded
+	Listed int `json:"listed"`
+	// Transformed is the number left after the transform chain and Valid
+	// the number of those the target accepts
+	Transformed int `json:"transformed"`
+	Valid       int `json:"valid"`
+	// Errors holds the first record errors; records failing validation or
+	// set aside as dead letters do not fail the dry run, as they do not
+	// fail a run
+	Errors          []string  `json:"errors,omitempty"`
+	Error           string    `json:"error,omitempty"`
+	StartedAt       time.Time `json:"started_at"`
+	DurationSeconds float64   `json:"duration_seconds"`
+}
+
+// DryRun lists one batch of changes after the stored checkpoint of a
+// pipeline and runs it through the transform chain and the target's
+// validation. Nothing is written: the checkpoint and the target are left
+// as they are, and the batch is listed again by the next run.
+func (e *Engine) DryRun(ctx context.Context, pipelineID string) (*DryRun, error) {
+	p, err := e.registry.GetByID(pipelineID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DryRun{PipelineID: p.ID, StartedAt: time.Now().UTC()}
+	fail := func(err error) (*DryRun, error) {
+		result.Error = err.Error()
+		result.DurationSeconds = time.Since(result.StartedAt).Seconds()
+		return result, nil
+	}
+
+	source, target, err := e.Connect(p)
+	if err != nil {
+		return fail(err)
+	}
+	chain, err := transform.Build(p.Transforms, nil)
+	if err != nil {
+		return fail(fmt.Errorf("failed to build transforms: %w", err))
+	}
+	checkpoint, err := e.store.LoadCheckpoint(p.ID)
+	if err != nil {
+		return fail(err)
+	}
+	changes, err := source.ListChanges(ctx, checkpoint)
+	if err != nil {
+		return fail(fmt.Errorf("failed to list changes: %w", err))
+	}
+	changes, _ = splitHeartbeats(changes)
+	changes, _ = e.outboxEvents(p, changes)
+	changes, _ = splitCanaries(e.normalizeKeys(p, changes))
+	result.Listed = len(changes)
+	if p.RecordLimits != nil {
+		var rejected []DeadLetter
+		changes, _, rejected = limitRecords(p.RecordLimits, changes)
+		if len(rejected) > 0 && p.RecordLimits.OnExceeded == registry.RecordLimitFail {
+			return fail(fmt.Errorf("%w: record %s: %s", ErrRecordLimit, rejected[0].Record.ID, rejected[0].Reason))
+		}
+		for _, letter := range rejected {
+			if len(result.Errors) < dryRunErrors {
+				result.Errors = append(result.Errors, fmt.Sprintf("record %s: %s", letter.Record.ID, letter.Reason))
+			}
+		}
+	}
+
+	records, err := chain.ApplyContext(ctx, changes)
+	if err != nil {
+		return fail(fmt.Errorf("failed to transform records: %w", err))
+	}
+	result.Transformed = len(records)
+	for _, record := range records {
+		check := target.Validate(ctx, record)
+		if check.IsValid {
+			result.Valid++
+			continue
+		}
+		if len(result.Errors) < dryRunErrors {
+			result.Errors = append(result.Errors, fmt.Sprintf("record %s: %s", record.ID, strings.Join(check.Errors, "; ")))
+		}
+	}
+
+	result.Passed = true
+	result.DurationSeconds = time.Since(result.StartedAt).Seconds()
+	return result, nil
+}
@@ -0,0 +1,851 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: sync-engine
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Sync Engine
+ */
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/coercion"
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/errortrack"
+	"github.com/machine-native-ops/esync-platform/internal/monitoring"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+	"github.com/machine-native-ops/esync-platform/internal/secrets"
+	"github.com/machine-native-ops/esync-platform/internal/state"
+	"github.com/machine-native-ops/esync-platform/internal/transform"
+)
+
+// Run statuses
+const (
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// Trigger types
+const (
+	TriggerManual   = "manual"
+	TriggerSchedule = "schedule"
+	TriggerWebhook  = "webhook"
+	TriggerKafka    = "kafka"
+	TriggerPipeline = "pipeline"
+	TriggerBackfill = "backfill"
+	// TriggerReconnect runs pipelines with spooled records when an agent
+	// gets back online
+	TriggerReconnect = "reconnect"
+	// TriggerSharedSource runs the pipelines sharing a source after one of
+	// them read it
+	TriggerSharedSource = "shared_source"
+	// TriggerHoldback runs pipelines with spooled records once their
+	// holdback is released
+	TriggerHoldback = "holdback"
+)
+
+// Trigger records what caused a run
+//
+// Frozen: public as engine.Trigger
+type Trigger struct {
+	Type     string            `json:"type"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Run describes a single pipeline execution
+//
+// Frozen: public as engine.Run
+type Run struct {
+	ID         string    `json:"id"`
+	PipelineID string    `json:"pipeline_id"`
+	Trigger    Trigger   `json:"trigger"`
+	Status     string    `json:"status"`
+	Records    int       `json:"records"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at,omitempty"`
+	Error      string    `json:"error,omitempty"`
+
+	// CoalescedTriggers counts duplicate triggers folded into this run
+	CoalescedTriggers int `json:"coalesced_triggers,omitempty"`
+
+	Progress *Progress `json:"progress,omitempty"`
+
+	// Listed, Filtered and Invalid count records read from the source,
+	// dropped by transforms and rejected by target validation
+	Listed   int `json:"listed,omitempty"`
+	Filtered int `json:"filtered,omitempty"`
+	Invalid  int `json:"invalid,omitempty"`
+
+	// Checkpoint records how far the run advanced the source checkpoint
+	Checkpoint *CheckpointMove `json:"checkpoint,omitempty"`
+
+	// Verification compares a sample of written records read back from the
+	// target with what was sent
+	Verification *Verification `json:"verification,omitempty"`
+
+	// Errors groups the errors raised during the run by fingerprint
+	Errors []ErrorGroup `json:"errors,omitempty"`
+
+	// Recorded reports that the input of the run was saved as a replay
+	// fixture
+	Recorded bool `json:"recorded,omitempty"`
+
+	// Cleanup reports what a run of a cleanup pipeline deleted
+	Cleanup *CleanupResult `json:"cleanup,omitempty"`
+
+	// SharedRead reports that the run read a source it shares with other
+	// pipelines, which then run to consume the read
+	SharedRead bool `json:"shared_read,omitempty"`
+
+	// Schema identifies the definition and target schema the run executed
+	// against
+	Schema *RunSchema `json:"schema,omitempty"`
+}
+
+// CheckpointMove is the checkpoint position before and after a run
+//
+// Frozen: public as engine.CheckpointMove
+type CheckpointMove struct {
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+}
+
+// runHistorySize is the number of finished runs kept per pipeline
+const runHistorySize = 20
+
+// Engine executes pipelines against their source and target connectors
+type Engine struct {
+	registry *registry.Service
+	store    *state.Store
+	monitor  *monitoring.Monitor
+	resolver *secrets.Resolver
+	reporter errortrack.Reporter
+	// retention bounds the run history and what Prune keeps
+	retention Retention
+	pruners   map[string]Pruner
+
+	mu       sync.RWMutex
+	tablesMu sync.Mutex
+	ddlMu    sync.Mutex
+	connsMu  sync.Mutex
+	conns    map[string]*managedConnector
+	auditMu  sync.Mutex
+	// residency holds the last audited residency decision per pipeline
+	residencyMu sync.Mutex
+	residency   map[string]string
+	// pii caches the PII findings per pipeline and field
+	piiMu    sync.Mutex
+	pii      map[string]map[string]*PIIFinding
+	piiDirty map[string]bool
+	// handoff is the handoff of the pipelines to another daemon;
+	// handedOff is closed once it completes
+	handoffMu sync.Mutex
+	handoff   *handoffState
+	handedOff chan struct{}
+	// flags holds the runtime diagnostic flags
+	flagsMu sync.RWMutex
+	flags   map[string]*Flag
+	// traces holds the active per-pipeline traces
+	tracesMu sync.Mutex
+	traces   map[string]*traceState
+	// slos tracks freshness objectives; shed holds the bulk pipelines
+	// whose automatic triggers are shed and sloWake is closed on every
+	// reprioritization
+	sloMu   sync.Mutex
+	slos    map[string]*sloState
+	shed    map[string]bool
+	sloWake chan struct{}
+	// memory is the memory pressure as of the last check
+	memoryMu sync.Mutex
+	memory   MemoryStatus
+	// canaries holds the last canary per pipeline
+	canaryMu  sync.Mutex
+	canaries  map[string]*canaryState
+	listeners []func(*Run)
+	locks     map[string]*runLock
+	active    map[string]*Run
+	history   map[string][]*Run
+	errors    map[string][]ErrorGroup
+	preflight map[string]*PreflightReport
+	// sources holds what the last sync pass saw of each source
+	sourcesMu sync.Mutex
+	sources   map[string]*SourceActivity
+	// deadLettersMu guards the stored dead-letter queues, confirmMu the
+	// confirmations of destructive pipelines and rolloutMu their rollouts
+	deadLettersMu sync.Mutex
+	confirmMu     sync.Mutex
+	rolloutMu     sync.Mutex
+	// shares holds the recent reads of shared sources by source key
+	sharesMu sync.Mutex
+	shares   map[string]*sourceShare
+	// snapshotMu guards the stored incremental snapshots
+	snapshotMu sync.Mutex
+	// watchers receive the published events; eventSeq numbers them
+	eventsMu sync.Mutex
+	watchers map[chan Event]bool
+	eventSeq uint64
+	// limiters enforce the call limits of connection profiles and pipelines
+	limitsMu sync.Mutex
+	limiters map[string]*callLimiter
+	// cancels cancels the active run of each pipeline; watched records the
+	// watchdog bounds each active run has exceeded
+	cancels map[string]context.CancelCauseFunc
+	watched map[string]map[string]bool
+	// keyMaps caches the loaded key mappings of pipelines
+	keyMapsMu sync.Mutex
+	keyMaps   map[string]*keyMap
+	// versions caches the loaded record version logs of pipelines
+	versionsMu sync.Mutex
+	versions   map[string]*versionLog
+	// quotas holds the run and worker slots of each namespace under
+	// quotaConfig
+	quotasMu    sync.Mutex
+	quotaConfig *QuotaConfig
+	quotas      map[string]*namespaceQuota
+	// actions holds what each post-run action accumulated
+	actionsMu sync.Mutex
+	actions   map[string]*actionState
+	// agent is the edge agent profile and spools the spool statistics of
+	// each pipeline under it
+	agentMu sync.Mutex
+	agent   *AgentConfig
+	spools  map[string]*spoolStats
+	// holdbackWake wakes WatchHoldbacks when a holdback is released
+	holdbackWake chan struct{}
+	// buckets pace the writes of pipelines and the agent under their
+	// bandwidth caps; meters caches the traffic of each pipeline
+	bandwidthMu sync.Mutex
+	buckets     map[string]*byteBucket
+	meters      map[string][]*BandwidthDay
+	meterDirty  map[string]bool
+	// sourceLoads paces the reads of pipelines under their source load
+	// limits
+	sourceLoadMu sync.Mutex
+	sourceLoads  map[string]*sourceLoadState
+	// idempotent holds the idempotent triggers being run by run ID
+	idempotentMu sync.Mutex
+	idempotent   map[string]*IdempotentCall
+	// digests holds what each digest of digestConfig accumulated for its
+	// next report
+	digestsMu    sync.Mutex
+	digestConfig *DigestConfig
+	digests      map[string]*digestState
+	// ctx ends when the engine stops, ending the work it runs detached
+	// from any caller
+	ctx  context.Context
+	stop context.CancelFunc
+}
+
+// New creates a new sync engine
+func New(reg *registry.Service, store *state.Store, monitor *monitoring.Monitor, resolver *secrets.Resolver) *Engine {
+	ctx, stop := context.WithCancel(context.Background())
+	return &Engine{
+		registry:     reg,
+		store:        store,
+		monitor:      monitor,
+		resolver:     resolver,
+		locks:        make(map[string]*runLock),
+		active:       make(map[string]*Run),
+		history:      make(map[string][]*Run),
+		errors:       make(map[string][]ErrorGroup),
+		preflight:    make(map[string]*PreflightReport),
+		cancels:      make(map[string]context.CancelCauseFunc),
+		watched:      make(map[string]map[string]bool),
+		conns:        make(map[string]*managedConnector),
+		residency:    make(map[string]string),
+		pii:          make(map[string]map[string]*PIIFinding),
+		piiDirty:     make(map[string]bool),
+		handedOff:    make(chan struct{}),
+		flags:        defaultFlags(),
+		traces:       make(map[string]*traceState),
+		canaries:     make(map[string]*canaryState),
+		shares:       make(map[string]*sourceShare),
+		sources:      make(map[string]*SourceActivity),
+		keyMaps:      make(map[string]*keyMap),
+		versions:     make(map[string]*versionLog),
+		watchers:     make(map[chan Event]bool),
+		limiters:     make(map[string]*callLimiter),
+		actions:      make(map[string]*actionState),
+		slos:         make(map[string]*sloState),
+		shed:         make(map[string]bool),
+		sloWake:      make(chan struct{}),
+		spools:       make(map[string]*spoolStats),
+		holdbackWake: make(chan struct{}, 1),
+		buckets:      make(map[string]*byteBucket),
+		meters:       make(map[string][]*BandwidthDay),
+		meterDirty:   make(map[string]bool),
+		sourceLoads:  make(map[string]*sourceLoadState),
+		idempotent:   make(map[string]*IdempotentCall),
+		digests:      make(map[string]*digestState),
+		ctx:          ctx,
+		stop:         stop,
+	}
+}
+
+// Stop ends the work the engine runs detached from any caller, such as the
+// follow-up runs of coalesced triggers still waiting for the run lock.
+// Runs already executing are left to finish.
+func (e *Engine) Stop() {
+	e.stop()
+}
+
+// Connect instantiates the source and target connectors of a pipeline
+func (e *Engine) Connect(p *registry.Pipeline) (connectors.Connector, connectors.Connector, error) {
+	source, err := e.connector(p.Source)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect source: %w", err)
+	}
+
+	target, err := e.connector(p.Target)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect target: %w", err)
+	}
+
+	return source, target, nil
+}
+
+// OnRunComplete registers a callback invoked after every finished run
+func (e *Engine) OnRunComplete(fn func(*Run)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.listeners = append(e.listeners, fn)
+}
+
+// RunOnce executes a single sync pass for a pipeline
+func (e *Engine) RunOnce(ctx context.Context, pipelineID string, trigger Trigger) (*Run, error) {
+	p, err := e.registry.GetByID(pipelineID)
+	if err != nil {
+		return nil, err
+	}
+
+	if trigger.Metadata[IdempotencyMetadata] != "" {
+		return e.runIdempotent(ctx, p, trigger, func() (*Run, error) { return e.start(ctx, p, trigger) })
+	}
+	return e.start(ctx, p, trigger)
+}
+
+// start runs a trigger unless the pipeline cannot run now
+func (e *Engine) start(ctx context.Context, p *registry.Pipeline, trigger Trigger) (*Run, error) {
+	if err := e.admit(p, trigger); err != nil {
+		return nil, err
+	}
+
+	return e.runExclusive(ctx, p, trigger)
+}
+
+// admit checks that a trigger may run the pipeline now: no handoff or
+// load shedding holds it, it is not paused, and its residency and
+// pre-flight checks pass
+func (e *Engine) admit(p *registry.Pipeline, trigger Trigger) error {
+	if err := e.checkHandoff(p.ID); err != nil {
+		return err
+	}
+	if err := e.checkShed(p.ID, trigger); err != nil {
+		return err
+	}
+	paused, err := e.Paused(p.ID)
+	if err != nil {
+		return err
+	}
+	if paused {
+		return fmt.Errorf("cannot run %s: %w", p.ID, ErrPaused)
+	}
+	if err := e.checkResidency(p); err != nil {
+		return err
+	}
+	return e.checkPreflight(p.ID)
+}
+
+// execute performs a run while holding the pipeline run lock
+func (e *Engine) execute(ctx context.Context, p *registry.Pipeline, trigger Trigger, coalesced int) (*Run, error) {
+	release, err := e.acquireRun(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	run := &Run{
+		ID:                runID(p.ID, trigger),
+		PipelineID:        p.ID,
+		Trigger:           trigger,
+		CoalescedTriggers: coalesced,
+		Status:            StatusRunning,
+		StartedAt:         time.Now().UTC(),
+		Progress:          &Progress{},
+		Schema:            e.runSchema(p),
+	}
+	ctx, cancel := context.WithCancelCause(e.withCallLimits(ctx, p))
+	defer cancel(nil)
+	tracker := e.track(run, cancel)
+	if id := trigger.Metadata[CorrelationMetadata]; id != "" {
+		log.Printf("[Engine] Run %s of pipeline %s triggered by request %s from %s", run.ID, p.ID, id, trigger.Metadata["remote_addr"])
+		e.audit(AuditEntry{
+			PipelineID: p.ID,
+			Policy:     "trigger",
+			Decision:   AuditTriggered,
+			Detail:     fmt.Sprintf("%s trigger from %s", trigger.Type, trigger.Metadata["remote_addr"]),
+			RunID:      run.ID,
+			RequestID:  id,
+		})
+	}
+
+	var records int
+	stack, err := recoverPanic(func() error {
+		source, target, err := e.Connect(p)
+		if err != nil {
+			return err
+		}
+		if p.Mode == registry.ModeCleanup {
+			records, err = e.cleanupPass(ctx, p, source, target, tracker)
+			return err
+		}
+		if err := e.bootstrap(ctx, p, source, target); err != nil {
+			return err
+		}
+		tracker.estimate(ctx, source)
+		records, err = e.syncPass(ctx, p, source, target, tracker)
+		return err
+	})
+	if cause := watchdogCause(ctx); cause != nil && err != nil {
+		err = cause
+	}
+
+	e.mu.Lock()
+	run.Records = records
+	run.FinishedAt = time.Now().UTC()
+	if err != nil {
+		run.Status = StatusFailed
+		run.Error = err.Error()
+	} else {
+		run.Status = StatusSucceeded
+	}
+	e.mu.Unlock()
+
+	if err != nil {
+		errorType := "run"
+		if stack != "" {
+			errorType = "panic"
+		}
+		e.recordError(p.ID, errorType, err)
+		e.reportFailure(p, run, errorType, err, stack)
+		if id := trigger.Metadata[CorrelationMetadata]; id != "" {
+			log.Printf("[Engine] Run %s of pipeline %s started by request %s failed: %v", run.ID, p.ID, id, err)
+		}
+	} else {
+		e.monitor.RecordSuccess(p.ID, run.Records)
+		e.writeFreshnessMarker(ctx, p, run)
+		e.postRunActions(p, run)
+	}
+	e.finishVerification(p, run)
+	tracker.finish()
+	if err := e.saveMeter(p.ID); err != nil {
+		log.Printf("[Engine] Failed to save traffic of %s: %v", p.ID, err)
+	}
+
+	e.notify(run)
+	return run, err
+}
+
+// notify invokes the run completion listeners
+func (e *Engine) notify(run *Run) {
+	e.mu.RLock()
+	listeners := e.listeners
+	e.mu.RUnlock()
+
+	for _, fn := range listeners {
+		fn(run)
+	}
+}
+
+// Drain runs sync passes until the source reports no further changes,
+// returning the number of records applied. It holds the pipeline's run
+// lock and fails with ErrRunInProgress when a run is active.
+func (e *Engine) Drain(ctx context.Context, pipelineID string, maxPasses int) (int, error) {
+	lock := e.lockFor(pipelineID)
+	select {
+	case lock.sem <- struct{}{}:
+	default:
+		return 0, fmt.Errorf("cannot drain %s: %w", pipelineID, ErrRunInProgress)
+	}
+	defer func() { <-lock.sem }()
+
+	return e.drain(ctx, pipelineID, maxPasses)
+}
+
+// drain runs the passes of Drain under a run slot of the pipeline's
+// namespace; callers hold the run lock
+func (e *Engine) drain(ctx context.Context, pipelineID string, maxPasses int) (int, error) {
+	p, err := e.registry.GetByID(pipelineID)
+	if err != nil {
+		return 0, err
+	}
+	release, err := e.acquireRun(ctx, p)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	source, target, err := e.Connect(p)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx = e.withCallLimits(ctx, p)
+	total := 0
+	for pass := 0; pass < maxPasses; pass++ {
+		n, err := e.syncPass(ctx, p, source, target, nil)
+		if err != nil {
+			return total, err
+		}
+		total += n
+		if n == 0 {
+			return total, nil
+		}
+	}
+
+	return total, fmt.Errorf("source still producing changes after %d passes", maxPasses)
+}
+
+// CaughtUp reports whether the stored checkpoint matches the source position
+func (e *Engine) CaughtUp(ctx context.Context, pipelineID string) (bool, error) {
+	p, err := e.registry.GetByID(pipelineID)
+	if err != nil {
+		return false, err
+	}
+
+	source, _, err := e.Connect(p)
+	if err != nil {
+		return false, err
+	}
+
+	latest, err := source.GetLatestCheckpoint(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to read source position: %w", err)
+	}
+
+	checkpoint, err := e.store.LoadCheckpoint(p.ID)
+	if err != nil {
+		return false, err
+	}
+
+	if latest == nil {
+		return true, nil
+	}
+	return checkpoint != nil && checkpoint.Position == latest.Position, nil
+}
+
+// syncPass moves one batch of changes from source to target and advances the
+// checkpoint. The source position is captured before listing so changes that
+// arrive mid-pass are re-read on the next pass rather than skipped. Changes
+// are applied in chunks of the pipeline batch size, reporting progress to
+// tracker when non-nil. The next chunk of a pending incremental snapshot is
+// applied ahead of the changes. While the target is held back, or its spool
+// still holds records read under a holdback, changes are spooled as on an
+// agent and the spool is applied oldest first once released. Passes the
+// source load limits do not let read yet only apply what is spooled.
+func (e *Engine) syncPass(ctx context.Context, p *registry.Pipeline, source, target connectors.Connector, tracker *progressTracker) (int, error) {
+	checkpoint, err := e.store.LoadCheckpoint(p.ID)
+	if err != nil {
+		return 0, err
+	}
+	agent := e.Agent()
+	held, err := e.heldBack(p.ID, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	spool := agent != nil || held != nil
+	if !spool {
+		status, err := e.SpoolStatus(p.ID)
+		if err != nil {
+			return 0, err
+		}
+		spool = status.Records > 0
+	}
+	if agent != nil {
+		checkpoint = agent.skewTolerant(p.ID, checkpoint, time.Now())
+		full, err := e.spoolFull(p.ID, agent)
+		if err != nil {
+			return 0, err
+		}
+		if full && held != nil {
+			log.Printf("[Engine] Spool of pipeline %s is full and its target held back, not listing", p.ID)
+			return 0, nil
+		}
+		if full {
+			// The source is read again once the upload makes room
+			log.Printf("[Engine] Spool of pipeline %s is full, uploading before listing", p.ID)
+			return e.uploadSpool(ctx, p, target, tracker, 0)
+		}
+	}
+
+	if ok, err := e.awaitSourceLoad(ctx, p); err != nil || !ok {
+		// A spool left by a holdback is applied even while reads wait
+		switch {
+		case err != nil || held != nil:
+			return 0, err
+		case agent != nil:
+			return e.uploadSpool(ctx, p, target, tracker, 0)
+		case spool:
+			return e.flushSpool(ctx, p, target, tracker, 0)
+		}
+		return 0, nil
+	}
+	latest, changes, err := e.readShared(ctx, p, source, target, checkpoint, tracker)
+	if err != nil {
+		return 0, err
+	}
+	changes, heartbeat := splitHeartbeats(changes)
+	changes, outbox := e.outboxEvents(p, changes)
+	changes = e.normalizeKeys(p, changes)
+	if changes, err = e.stamp(p, changes); err != nil {
+		return 0, err
+	}
+	e.recordFixture(ctx, p, target, tracker, changes)
+	listed := changes
+	listed, heartbeat, skew := e.correctSkew(p, listed, heartbeat, e.measureSkew(ctx, p, source, listed, heartbeat))
+	changes = listed
+	snapshot, snapshotDone := e.snapshotChunk(ctx, p, source, listed)
+	changes = append(snapshot, changes...)
+	changes = append(changes, e.queuedCanary(p.ID)...)
+
+	tracker.discover(int64(len(changes)))
+	position := ""
+	if latest != nil {
+		position = latest.Position
+	}
+	applied := 0
+	if spool {
+		err = e.spoolRecords(p.ID, position, changes)
+	} else {
+		applied, err = e.applyStaged(ctx, p, target, changes, tracker, "", position)
+	}
+	e.saveFixture(tracker, err)
+	if err != nil {
+		return applied, err
+	}
+	if snapshotDone != nil {
+		snapshotDone()
+	}
+	e.consumeOutbox(ctx, p, source, outbox)
+
+	moved := false
+	if latest != nil {
+		if err := e.store.SaveCheckpoint(p.ID, latest); err != nil {
+			return applied, err
+		}
+		tracker.moved(checkpoint, latest)
+		moved = checkpoint == nil || checkpoint.Position != latest.Position
+		e.acknowledge(ctx, p, source, latest)
+	}
+	e.observeSource(p, listed, heartbeat, moved, skew)
+
+	switch {
+	case held != nil:
+		e.tracef(p.ID, "target held back (%s), %d records spooled", held.Reason, len(changes))
+		return 0, nil
+	case agent != nil:
+		return e.uploadSpool(ctx, p, target, tracker, len(changes))
+	case spool:
+		return e.flushSpool(ctx, p, target, tracker, len(changes))
+	}
+	return applied, nil
+}
+
+// readSource reads the source position, captures schema changes and lists
+// the changes since checkpoint. The position is read first so changes that
+// arrive while listing are read again by the next pass.
+func (e *Engine) readSource(ctx context.Context, p *registry.Pipeline, source, target connectors.Connector, checkpoint *connectors.Checkpoint) (*connectors.Checkpoint, []connectors.Record, error) {
+	start := time.Now()
+	latest, err := source.GetLatestCheckpoint(ctx)
+	e.traceCall(p.ID, e.connectorType(p.Source), "get_latest_checkpoint", start, 0, err)
+	if err != nil {
+		e.recordError(p.ID, "source", err)
+		return nil, nil, fmt.Errorf("failed to read source position: %w", err)
+	}
+
+	if err := e.captureDDL(ctx, p, source, target, checkpoint); err != nil {
+		return nil, nil, err
+	}
+
+	start = time.Now()
+	changes, err := source.ListChanges(ctx, checkpoint)
+	e.traceCall(p.ID, e.connectorType(p.Source), "list_changes", start, len(changes), err)
+	e.observeSourceLoad(p, len(changes), time.Since(start))
+	if err != nil {
+		e.recordError(p.ID, "source", err)
+		return nil, nil, fmt.Errorf("failed to list changes: %w", err)
+	}
+	e.meter(p.ID, DirectionRead, recordBytes(changes), 0)
+	return latest, changes, nil
+}
+
+// apply enforces the record limits, runs records through the transform
+// chain and coercion policy, validates them against the target and writes
+// the valid ones in dependency order, returning the number applied. Chunk
+// labels reported to tracker are prefixed with label.
+func (e *Engine) apply(ctx context.Context, p *registry.Pipeline, target connectors.Connector, records []connectors.Record, tracker *progressTracker, label string) (int, error) {
+	chain, err := transform.Build(p.Transforms, pipelineMetrics{engine: e, pipelineID: p.ID})
+	if err != nil {
+		return 0, err
+	}
+	records, canaries := splitCanaries(e.normalizeKeys(p, records))
+	listed := len(records)
+	if records, err = e.enforceRecordLimits(p, records, tracker); err != nil {
+		return 0, err
+	}
+	limited := len(records)
+	var before []connectors.Record
+	if e.tracing(p.ID) {
+		before = records
+	}
+	records, err = chain.ApplyContext(ctx, records)
+	if before != nil {
+		e.traceDropped(p.ID, before, records)
+	}
+	if saveErr := e.savePII(p.ID); saveErr != nil {
+		log.Printf("[Engine] Failed to save PII findings of %s: %v", p.ID, saveErr)
+	}
+	if err != nil {
+		return 0, err
+	}
+	transformed := len(records)
+	if records, err = e.mergePatches(ctx, p, target, records); err != nil {
+		return 0, err
+	}
+
+	var schema *connectors.Schema
+	nullMissing := p.MissingFields == registry.MissingFieldsNull
+	if p.Coercion != nil || nullMissing {
+		schema, _ = introspect(ctx, target)
+	}
+	if nullMissing && schema == nil {
+		return 0, fmt.Errorf("missing_fields: null requires a target that reports its schema")
+	}
+
+	var policy *coercion.Policy
+	if p.Coercion != nil {
+		if policy, err = coercion.NewPolicy(p.Coercion, schema); err != nil {
+			return 0, fmt.Errorf("invalid coercion policy: %w", err)
+		}
+	}
+
+	valid := make([]connectors.Record, 0, len(records))
+	for _, record := range records {
+		if policy != nil {
+			var warnings []string
+			if record, warnings, err = policy.Apply(record); err != nil {
+				e.recordError(p.ID, "coercion", fmt.Errorf("record %s: %w", record.ID, err))
+				e.tracef(p.ID, "record %s failed coercion: %v", record.ID, err)
+				continue
+			}
+			for _, warning := range warnings {
+				e.groupError(p.ID, "coercion_warning", fmt.Sprintf("record %s: %s", record.ID, warning))
+			}
+		}
+		if nullMissing {
+			record = nullAbsent(record, schema)
+		}
+
+		result := target.Validate(ctx, record)
+		if !result.IsValid {
+			e.recordError(p.ID, "validation", fmt.Errorf("record %s: %s", record.ID, strings.Join(result.Errors, "; ")))
+			e.tracef(p.ID, "record %s failed validation: %s", record.ID, strings.Join(result.Errors, "; "))
+			continue
+		}
+		valid = append(valid, record)
+	}
+	tracker.count(listed, limited-transformed, listed-limited+transformed-len(valid))
+	valid, commitVersions, err := e.resolveConflicts(p, valid)
+	if err != nil {
+		return 0, err
+	}
+
+	segments, err := tableSegments(ctx, p, target, append(valid, canaries...))
+	if err != nil {
+		return 0, err
+	}
+	applied := 0
+	for _, segment := range segments {
+		n, err := e.write(ctx, p, target, orderRecords(segment, collationOf(p)), tracker, label)
+		applied += n
+		if err != nil {
+			return applied, err
+		}
+	}
+	if err := commitVersions(); err != nil {
+		return applied, err
+	}
+	e.verify(ctx, p, target, valid, tracker)
+	e.canariesArrived(ctx, p, target, canaries)
+
+	return len(valid), nil
+}
+
+// nullAbsent sets target fields absent from an update record to explicit
+// nulls, turning a partial update into a full one
+func nullAbsent(r connectors.Record, schema *connectors.Schema) connectors.Record {
+	if r.Operation != connectors.OperationUpdate {
+		return r
+	}
+
+	var data map[string]interface{}
+	for _, f := range schema.Fields {
+		if r.Has(f.Name) {
+			continue
+		}
+		if data == nil {
+			data = make(map[string]interface{}, len(schema.Fields))
+			for k, v := range r.Data {
+				data[k] = v
+			}
+		}
+		data[f.Name] = nil
+	}
+	if data != nil {
+		r.Data = data
+	}
+	return r
+}
+
+// pipelineMetrics exports custom transform metrics and PII findings of one
+// pipeline
+type pipelineMetrics struct {
+	engine     *Engine
+	pipelineID string
+}
+
+// Count implements transform.Metrics
+func (m pipelineMetrics) Count(name string, delta float64) {
+	if err := m.engine.monitor.AddCustomCounter(m.pipelineID, name, delta); err != nil {
+		log.Printf("[Engine] Pipeline %s: %v", m.pipelineID, err)
+	}
+}
+
+// Gauge implements transform.Metrics
+func (m pipelineMetrics) Gauge(name string, value float64) {
+	if err := m.engine.monitor.SetCustomGauge(m.pipelineID, name, value); err != nil {
+		log.Printf("[Engine] Pipeline %s: %v", m.pipelineID, err)
+	}
+}
+
+// Observe implements transform.Findings
+func (m pipelineMetrics) Observe(field string, categories []string) {
+	m.engine.observePII(m.pipelineID, field, categories)
+}
@@ -0,0 +1,451 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: data-erasure
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Data Subject Erasure Requests
+ */
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// ErrNoErasurePipelines is returned when no selected pipeline declares an
+// erasure block
+var ErrNoErasurePipelines = errors.New("no pipeline declares erasure")
+
+// ErrErasureNotFailed is returned when retrying an erasure request that has
+// not failed
+var ErrErasureNotFailed = errors.New("only failed erasure requests can be retried")
+
+// Erasure statuses of requests and of their targets
+const (
+	ErasurePending   = "pending"
+	ErasureCompleted = "completed"
+	ErasureFailed    = "failed"
+)
+
+// AuditErased is the audit decision recorded for each erased target
+const AuditErased = "erased"
+
+// ErasureTarget tracks an erasure request against one target of a pipeline
+type ErasureTarget struct {
+	PipelineID string `json:"pipeline_id"`
+	// Target is "target" or "route <table>"
+	Target      string    `json:"target"`
+	Action      string    `json:"action"`
+	Records     int       `json:"records"`
+	Status      string    `json:"status"`
+	Error       string    `json:"error,omitempty"`
+	CompletedAt time.Time `json:"completed_at,omitempty"`
+}
+
+// ErasureRequest removes the records of one data subject from the targets
+// of every pipeline declaring erasure
+type ErasureRequest struct {
+	ID      string `json:"id"`
+	Subject string `json:"subject"`
+	Reason  string `json:"reason,omitempty"`
+	// Selector limits the request to matching pipelines
+	Selector    string          `json:"selector,omitempty"`
+	Status      string          `json:"status"`
+	Targets     []ErasureTarget `json:"targets"`
+	RequestedAt time.Time       `json:"requested_at"`
+	CompletedAt time.Time       `json:"completed_at,omitempty"`
+}
+
+// ErasureReport summarizes an erasure request for compliance records
+type ErasureReport struct {
+	RequestID   string    `json:"request_id"`
+	Subject     string    `json:"subject"`
+	Reason      string    `json:"reason,omitempty"`
+	RequestedAt time.Time `json:"requested_at"`
+	CompletedAt time.Time `json:"completed_at,omitempty"`
+	// Complete is true once every target confirmed the erasure
+	Complete  bool `json:"complete"`
+	Pipelines int  `json:"pipelines"`
+	Targets   int  `json:"targets"`
+	Erased    int  `json:"erased"`
+	Pending   int  `json:"pending"`
+	// Failed lists the targets that could not be erased, with the reason
+	Failed      []ErasureTarget `json:"failed,omitempty"`
+	Audit       []AuditEntry    `json:"audit,omitempty"`
+	GeneratedAt time.Time       `json:"generated_at"`
+}
+
+// StartErasure plans an erasure request for subject across the pipelines
+// matching selector and runs it in the background
+func (e *Engine) StartErasure(ctx context.Context, subject, reason, selector string) (*ErasureRequest, error) {
+	if strings.TrimSpace(subject) == "" {
+		return nil, fmt.Errorf("erasure subject is required")
+	}
+	sel, err := registry.ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &ErasureRequest{
+		ID:          fmt.Sprintf("erasure-%d", time.Now().UnixNano()),
+		Subject:     subject,
+		Reason:      reason,
+		Selector:    selector,
+		Status:      ErasurePending,
+		RequestedAt: time.Now().UTC(),
+	}
+	for _, p := range e.registry.Select(sel) {
+		if p.Erasure == nil {
+			continue
+		}
+		action := erasureAction(p)
+		req.Targets = append(req.Targets, ErasureTarget{PipelineID: p.ID, Target: "target", Action: action, Status: ErasurePending})
+		for _, route := range p.Routes {
+			req.Targets = append(req.Targets, ErasureTarget{PipelineID: p.ID, Target: "route " + route.Table, Action: action, Status: ErasurePending})
+		}
+	}
+	if len(req.Targets) == 0 {
+		return nil, ErrNoErasurePipelines
+	}
+
+	if err := e.store.Save("erasure/"+req.ID, req); err != nil {
+		return nil, err
+	}
+	log.Printf("[Engine] Erasure request %s covers %d targets", req.ID, len(req.Targets))
+
+	snapshot := *req
+	snapshot.Targets = append([]ErasureTarget(nil), req.Targets...)
+	go e.runErasure(ctx, req)
+	return &snapshot, nil
+}
+
+// ResumeErasures continues every unfinished erasure request found in the
+// state store
+func (e *Engine) ResumeErasures(ctx context.Context) error {
+	requests, err := e.Erasures()
+	if err != nil {
+		return err
+	}
+	for _, req := range requests {
+		if req.Status != ErasurePending {
+			continue
+		}
+		log.Printf("[Engine] Resuming erasure request %s", req.ID)
+		go e.runErasure(ctx, req)
+	}
+	return nil
+}
+
+// RetryErasure runs the failed targets of a finished erasure request again,
+// returning nil when the request does not exist
+func (e *Engine) RetryErasure(ctx context.Context, id string) (*ErasureRequest, error) {
+	req, err := e.Erasure(id)
+	if err != nil || req == nil {
+		return nil, err
+	}
+	if req.Status != ErasureFailed {
+		return nil, fmt.Errorf("erasure request %s is %s: %w", id, req.Status, ErrErasureNotFailed)
+	}
+
+	req.Status, req.CompletedAt = ErasurePending, time.Time{}
+	for i := range req.Targets {
+		if req.Targets[i].Status == ErasureFailed {
+			req.Targets[i].Status, req.Targets[i].Error = ErasurePending, ""
+		}
+	}
+	if err := e.store.Save("erasure/"+req.ID, req); err != nil {
+		return nil, err
+	}
+
+	snapshot := *req
+	snapshot.Targets = append([]ErasureTarget(nil), req.Targets...)
+	go e.runErasure(ctx, req)
+	return &snapshot, nil
+}
+
+// Erasures returns the stored erasure requests, newest first
+func (e *Engine) Erasures() ([]*ErasureRequest, error) {
+	keys, err := e.store.Keys("erasure")
+	if err != nil {
+		return nil, err
+	}
+
+	requests := make([]*ErasureRequest, 0, len(keys))
+	for _, key := range keys {
+		var req ErasureRequest
+		if _, err := e.store.Load(key, &req); err != nil {
+			return nil, err
+		}
+		requests = append(requests, &req)
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		return requests[i].RequestedAt.After(requests[j].RequestedAt)
+	})
+	return requests, nil
+}
+
+// Erasure returns a stored erasure request, or nil
+func (e *Engine) Erasure(id string) (*ErasureRequest, error) {
+	var req ErasureRequest
+	found, err := e.store.Load("erasure/"+id, &req)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// ErasureReport summarizes a stored erasure request and its audit trail,
+// or returns nil when it does not exist
+func (e *Engine) ErasureReport(id string) (*ErasureReport, error) {
+	req, err := e.Erasure(id)
+	if err != nil || req == nil {
+		return nil, err
+	}
+
+	report := &ErasureReport{
+		RequestID:   req.ID,
+		Subject:     req.Subject,
+		Reason:      req.Reason,
+		RequestedAt: req.RequestedAt,
+		CompletedAt: req.CompletedAt,
+		Targets:     len(req.Targets),
+		GeneratedAt: time.Now().UTC(),
+	}
+	pipelines := make(map[string]bool)
+	for _, t := range req.Targets {
+		pipelines[t.PipelineID] = true
+		switch t.Status {
+		case ErasureCompleted:
+			report.Erased += t.Records
+		case ErasureFailed:
+			report.Failed = append(report.Failed, t)
+		default:
+			report.Pending++
+		}
+	}
+	report.Pipelines = len(pipelines)
+	report.Complete = req.Status == ErasureCompleted
+
+	entries, err := e.AuditLog("", 0)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Policy == "erasure" && strings.HasPrefix(entries[i].Detail, req.ID+":") {
+			report.Audit = append(report.Audit, entries[i])
+		}
+	}
+	return report, nil
+}
+
+// runErasure erases the subject from every pending target, persisting each
+// outcome so an interrupted request resumes where it stopped
+func (e *Engine) runErasure(ctx context.Context, req *ErasureRequest) {
+	for i := range req.Targets {
+		t := &req.Targets[i]
+		if t.Status == ErasureCompleted {
+			continue
+		}
+
+		n, err := e.eraseTarget(ctx, req.Subject, t)
+		t.Records, t.Error = n, ""
+		if err != nil {
+			t.Status, t.Error = ErasureFailed, err.Error()
+			log.Printf("[Engine] Erasure request %s: pipeline %s %s failed: %v", req.ID, t.PipelineID, t.Target, err)
+		} else {
+			t.Status, t.CompletedAt = ErasureCompleted, time.Now().UTC()
+			e.audit(AuditEntry{
+				PipelineID: t.PipelineID,
+				Policy:     "erasure",
+				Decision:   AuditErased,
+				Detail:     fmt.Sprintf("%s: %s %d records from %s", req.ID, erasedVerb(t.Action), n, t.Target),
+			})
+		}
+		if err := e.store.Save("erasure/"+req.ID, req); err != nil {
+			log.Printf("[Engine] Failed to save erasure request %s: %v", req.ID, err)
+		}
+		if ctx.Err() != nil {
+			return
+		}
+	}
+
+	req.Status = ErasureCompleted
+	for _, t := range req.Targets {
+		if t.Status != ErasureCompleted {
+			req.Status = ErasureFailed
+		}
+	}
+	req.CompletedAt = time.Now().UTC()
+	if err := e.store.Save("erasure/"+req.ID, req); err != nil {
+		log.Printf("[Engine] Failed to save erasure request %s: %v", req.ID, err)
+	}
+	log.Printf("[Engine] Erasure request %s %s", req.ID, req.Status)
+}
+
+// eraseTarget writes the erasure records of subject to one target of a
+// pipeline under its run lock, returning the number of records written
+func (e *Engine) eraseTarget(ctx context.Context, subject string, t *ErasureTarget) (int, error) {
+	p, err := e.registry.GetByID(t.PipelineID)
+	if err != nil {
+		return 0, err
+	}
+	if p.Erasure == nil {
+		return 0, fmt.Errorf("pipeline no longer declares erasure")
+	}
+	t.Action = erasureAction(p)
+
+	spec, table := p.Target, ""
+	if route := strings.TrimPrefix(t.Target, "route "); route != t.Target {
+		i := routeFor(p, route)
+		if i < 0 {
+			return 0, fmt.Errorf("route %s no longer exists", route)
+		}
+		spec, table = p.Routes[i].Target, route
+	}
+	if t.Action == registry.ErasurePatch && len(p.Erasure.Fields) == 0 {
+		return 0, fmt.Errorf("erasure action patch requires fields")
+	}
+
+	lock := e.lockFor(p.ID)
+	select {
+	case lock.sem <- struct{}{}:
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+	defer func() { <-lock.sem }()
+
+	target, err := e.connector(spec)
+	if err != nil {
+		return 0, fmt.Errorf("failed to connect target: %w", err)
+	}
+	if t.Action == registry.ErasurePatch {
+		if pw, ok := target.(connectors.PatchWriter); !ok || !pw.SupportsPatch() {
+			return 0, fmt.Errorf("target type %s does not support patch", spec.Type)
+		}
+	}
+
+	ids := []string{subject}
+	if p.Erasure.SubjectField != "" {
+		if ids, err = e.subjectRecords(ctx, p, subject, table); err != nil {
+			return 0, err
+		}
+	}
+
+	records := make([]connectors.Record, 0, len(ids))
+	for _, id := range ids {
+		r := connectors.Record{ID: id, Table: table, KeyFields: p.PrimaryKey.KeyFields(table), Operation: connectors.OperationDelete, Timestamp: time.Now().UTC()}
+		if t.Action == registry.ErasurePatch {
+			r.Operation = connectors.OperationPatch
+			r.Data = make(map[string]interface{}, len(p.Erasure.Fields))
+			for _, field := range p.Erasure.Fields {
+				r.Data[field] = nil
+			}
+		}
+		records = append(records, r)
+	}
+
+	batchSize := p.BatchSize
+	if batchSize <= 0 {
+		batchSize = registry.DefaultBatchSize
+	}
+	mapper, err := e.keyMapper(p)
+	if err != nil {
+		return 0, err
+	}
+	for start := 0; start < len(records); start += batchSize {
+		end := start + batchSize
+		if end > len(records) {
+			end = len(records)
+		}
+		if mapper != nil {
+			err = mapper.apply(ctx, target, records[start:end])
+		} else {
+			err = target.ApplyChanges(ctx, records[start:end])
+		}
+		if err != nil {
+			return start, fmt.Errorf("failed to apply erasure: %w", err)
+		}
+	}
+	if err := e.eraseFixture(p, table, ids, t.Action); err != nil {
+		return len(records), err
+	}
+	return len(records), nil
+}
+
+// subjectRecords reads the source of a pipeline and returns the IDs of the
+// records of table whose subject field holds subject
+func (e *Engine) subjectRecords(ctx context.Context, p *registry.Pipeline, subject, table string) ([]string, error) {
+	source, err := e.connector(p.Source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect source: %w", err)
+	}
+
+	var records []connectors.Record
+	if reader, ok := source.(connectors.RangeReader); ok {
+		ranges, err := reader.KeyRanges(ctx, 1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to split key space: %w", err)
+		}
+		for _, r := range ranges {
+			chunk, err := reader.ReadRange(ctx, r)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read source: %w", err)
+			}
+			records = append(records, chunk...)
+		}
+	} else if records, err = source.ListChanges(ctx, nil); err != nil {
+		return nil, fmt.Errorf("failed to read source: %w", err)
+	}
+	records, _ = splitHeartbeats(records)
+	records = e.normalizeKeys(p, records)
+
+	seen := make(map[string]bool)
+	var ids []string
+	for _, r := range records {
+		if r.Operation == connectors.OperationDelete || seen[r.ID] {
+			continue
+		}
+		if table != "" && r.Table != table || table == "" && routeFor(p, r.Table) >= 0 {
+			continue
+		}
+		if v, ok := r.Data[p.Erasure.SubjectField]; ok && fmt.Sprint(v) == subject {
+			seen[r.ID] = true
+			ids = append(ids, r.ID)
+		}
+	}
+	return ids, nil
+}
+
+// erasureAction returns the erasure action of a pipeline, delete unless it
+// opts into patching
+func erasureAction(p *registry.Pipeline) string {
+	if p.Erasure != nil && p.Erasure.Action != "" {
+		return p.Erasure.Action
+	}
+	return registry.DefaultErasureAction
+}
+
+// erasedVerb describes an erasure action in audit details
+func erasedVerb(action string) string {
+	if action == registry.ErasurePatch {
+		return "patched"
+	}
+	return "deleted"
+}
@@ -0,0 +1,142 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: error-grouping
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Error Fingerprinting and Grouping
+ */
+
+package engine
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"time"
+)
+
+// maxErrorGroups bounds the groups kept per run and per pipeline; errors of
+// further fingerprints are counted in a single overflow group
+const maxErrorGroups = 50
+
+// overflowFingerprint identifies the group collecting errors past
+// maxErrorGroups
+const overflowFingerprint = "overflow"
+
+// ErrorGroup aggregates errors sharing a fingerprint
+//
+// Frozen: public as engine.ErrorGroup
+type ErrorGroup struct {
+	Fingerprint string `json:"fingerprint"`
+	Type        string `json:"type"`
+	// Message is the first error seen in the group
+	Message   string    `json:"message"`
+	Count     int       `json:"count"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+// Variable parts of error messages, replaced before fingerprinting so that
+// errors differing only in record IDs, offsets or addresses group together
+var (
+	quotedPattern = regexp.MustCompile(`"[^"]*"|'[^']*'`)
+	uuidPattern   = regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`)
+	hexPattern    = regexp.MustCompile(`(?i)\b0x[0-9a-f]+\b|\b[0-9a-f]{16,}\b`)
+	numberPattern = regexp.MustCompile(`\d+`)
+	recordPattern = regexp.MustCompile(`\brecord \S+:`)
+)
+
+// NormalizeError strips the variable parts of an error message
+func NormalizeError(message string) string {
+	message = recordPattern.ReplaceAllString(message, "record <id>:")
+	message = quotedPattern.ReplaceAllString(message, "<str>")
+	message = uuidPattern.ReplaceAllString(message, "<uuid>")
+	message = hexPattern.ReplaceAllString(message, "<hex>")
+	return numberPattern.ReplaceAllString(message, "<n>")
+}
+
+// Fingerprint identifies the group of an error by its type and normalized
+// message
+func Fingerprint(errorType, message string) string {
+	sum := sha256.Sum256([]byte(errorType + "\x00" + NormalizeError(message)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// addError counts an error into groups, returning the updated groups
+func addError(groups []ErrorGroup, errorType, message string, at time.Time) []ErrorGroup {
+	fingerprint := Fingerprint(errorType, message)
+	for i := range groups {
+		if groups[i].Fingerprint == fingerprint {
+			groups[i].Count++
+			groups[i].LastSeen = at
+			return groups
+		}
+	}
+
+	if len(groups) >= maxErrorGroups {
+		last := &groups[len(groups)-1]
+		if last.Fingerprint != overflowFingerprint {
+			groups = append(groups, ErrorGroup{
+				Fingerprint: overflowFingerprint,
+				Type:        "overflow",
+				Message:     "further error types were not grouped",
+				FirstSeen:   at,
+			})
+			last = &groups[len(groups)-1]
+		}
+		last.Count++
+		last.LastSeen = at
+		return groups
+	}
+
+	return append(groups, ErrorGroup{
+		Fingerprint: fingerprint,
+		Type:        errorType,
+		Message:     message,
+		Count:       1,
+		FirstSeen:   at,
+		LastSeen:    at,
+	})
+}
+
+// recordError reports an error to the monitor and groups it under the
+// pipeline and its in-flight run
+func (e *Engine) recordError(pipelineID, errorType string, err error) {
+	if errorType == "source" {
+		e.monitor.RecordSourceError(pipelineID, err)
+	} else {
+		e.monitor.RecordError(pipelineID, errorType, err)
+	}
+
+	e.groupError(pipelineID, errorType, err.Error())
+}
+
+// groupError groups a message under the pipeline and its in-flight run
+// without reporting it to the monitor, e.g. for warnings
+func (e *Engine) groupError(pipelineID, errorType, message string) {
+	now := time.Now().UTC()
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.errors[pipelineID] = addError(e.errors[pipelineID], errorType, message, now)
+	if run := e.active[pipelineID]; run != nil {
+		run.Errors = addError(run.Errors, errorType, message, now)
+	}
+}
+
+// ErrorGroups returns the errors of a pipeline since startup, grouped by
+// fingerprint
+func (e *Engine) ErrorGroups(pipelineID string) []ErrorGroup {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return append([]ErrorGroup(nil), e.errors[pipelineID]...)
+}
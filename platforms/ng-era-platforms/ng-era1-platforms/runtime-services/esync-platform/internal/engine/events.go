@@ -0,0 +1,113 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: pipeline-events
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Pipeline Status Events
+ */
+
+package engine
+
+import (
+	"log"
+	"time"
+)
+
+// Event types published to subscribers
+const (
+	EventRunStarted  = "run_started"
+	EventRunFinished = "run_finished"
+	EventPaused      = "paused"
+	EventResumed     = "resumed"
+)
+
+// eventBuffer is the number of events a subscriber may fall behind before
+// it is dropped
+const eventBuffer = 256
+
+// Event is a status transition of a pipeline. Paused and Running are the
+// state of the pipeline after the transition. Daemon-wide events, such as
+// memory pressure changes, have no pipeline.
+type Event struct {
+	Seq        uint64    `json:"seq"`
+	Type       string    `json:"type"`
+	PipelineID string    `json:"pipeline_id"`
+	Time       time.Time `json:"time"`
+	Paused     bool      `json:"paused"`
+	Running    bool      `json:"running"`
+	// Run is the started or finished run
+	Run *Run `json:"run,omitempty"`
+	// Reason names the engine feature behind a pause or resume; empty for
+	// operator actions
+	Reason string `json:"reason,omitempty"`
+	// Memory is the memory pressure a memory_pressure event moved to and
+	// the actions taken
+	Memory *MemoryStatus `json:"memory,omitempty"`
+	// Rollout is the rollout a rollout event started or finished
+	Rollout *Rollout `json:"rollout,omitempty"`
+}
+
+// Subscribe returns a channel receiving every event published from now on
+// and a func that ends the subscription. A subscriber that falls more than
+// eventBuffer events behind is dropped and its channel closed, so it can
+// resubscribe and re-read the status it missed.
+func (e *Engine) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, eventBuffer)
+
+	e.eventsMu.Lock()
+	e.watchers[ch] = true
+	e.eventsMu.Unlock()
+
+	return ch, func() {
+		e.eventsMu.Lock()
+		defer e.eventsMu.Unlock()
+		if e.watchers[ch] {
+			delete(e.watchers, ch)
+			close(ch)
+		}
+	}
+}
+
+// publish sends an event of a pipeline to every subscriber
+func (e *Engine) publish(eventType, pipelineID string, run *Run, reason string) {
+	paused, err := e.Paused(pipelineID)
+	if err != nil {
+		log.Printf("[Engine] Failed to read pause state of pipeline %s: %v", pipelineID, err)
+	}
+	event := Event{
+		Type:       eventType,
+		PipelineID: pipelineID,
+		Time:       time.Now().UTC(),
+		Paused:     paused,
+		Running:    e.CurrentRun(pipelineID) != nil,
+		Run:        run,
+		Reason:     reason,
+	}
+	e.broadcast(event)
+}
+
+// broadcast numbers an event and sends it to every subscriber
+func (e *Engine) broadcast(event Event) {
+	e.eventsMu.Lock()
+	defer e.eventsMu.Unlock()
+
+	e.eventSeq++
+	event.Seq = e.eventSeq
+	for ch := range e.watchers {
+		select {
+		case ch <- event:
+		default:
+			log.Printf("[Engine] Dropping event subscriber %d events behind", len(ch))
+			delete(e.watchers, ch)
+			close(ch)
+		}
+	}
+}
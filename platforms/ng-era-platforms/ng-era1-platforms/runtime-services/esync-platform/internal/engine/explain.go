@@ -0,0 +1,112 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: pipeline-explain
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Resolved Pipeline Explanation
+ */
+
+package engine
+
+import (
+	"fmt"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+	"github.com/machine-native-ops/esync-platform/internal/secrets"
+)
+
+// Delivery guarantees
+const (
+	DeliveryAtLeastOnce     = "at-least-once"
+	DeliveryEffectivelyOnce = "effectively-once"
+	DeliveryUnknown         = "unknown"
+)
+
+// TransformStep is one stage of the resolved transform chain
+type TransformStep struct {
+	Order   int                    `json:"order"`
+	Type    string                 `json:"type"`
+	Options map[string]interface{} `json:"options,omitempty"`
+}
+
+// Explanation is the fully resolved form of a pipeline as the engine will
+// execute it
+type Explanation struct {
+	PipelineID string `json:"pipeline_id"`
+	// Pipeline has overlays applied, defaults filled in, config templates
+	// expanded and secret values redacted
+	Pipeline          *registry.Pipeline  `json:"pipeline"`
+	Defaulted         []string            `json:"defaulted,omitempty"`
+	SecretRefs        []secrets.Reference `json:"secret_refs,omitempty"`
+	Transforms        []TransformStep     `json:"transforms"`
+	DeliveryGuarantee string              `json:"delivery_guarantee"`
+	GuaranteeReason   string              `json:"guarantee_reason"`
+}
+
+// Explain resolves a pipeline the way a run would see it, with secret values
+// redacted
+func (e *Engine) Explain(pipelineID string) (*Explanation, error) {
+	p, err := e.registry.GetByID(pipelineID)
+	if err != nil {
+		return nil, err
+	}
+
+	effective, defaulted := p.Effective()
+	x := &Explanation{
+		PipelineID: p.ID,
+		Pipeline:   effective,
+		Defaulted:  defaulted,
+		Transforms: make([]TransformStep, 0, len(p.Transforms)),
+	}
+
+	for _, side := range []struct {
+		role string
+		spec *registry.ConnectorSpec
+	}{{"source", &effective.Source}, {"target", &effective.Target}} {
+		resolved, err := e.registry.ResolveConnector(*side.spec)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s connection: %w", side.role, err)
+		}
+		*side.spec = resolved
+		config, refs, err := e.resolver.ExpandConfig(side.spec.Config, true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s config: %w", side.role, err)
+		}
+		side.spec.Config = config
+		for _, ref := range refs {
+			ref.Path = side.role + ".config." + ref.Path
+			x.SecretRefs = append(x.SecretRefs, ref)
+		}
+	}
+
+	for i, t := range p.Transforms {
+		x.Transforms = append(x.Transforms, TransformStep{Order: i + 1, Type: t.Type, Options: t.Options})
+	}
+
+	x.DeliveryGuarantee, x.GuaranteeReason = e.deliveryGuarantee(p)
+	return x, nil
+}
+
+// deliveryGuarantee derives the end-to-end guarantee of a pipeline. The
+// checkpoint only advances after a batch is applied, so a crash re-applies
+// the batch: at-least-once, or effectively-once on idempotent targets.
+func (e *Engine) deliveryGuarantee(p *registry.Pipeline) (string, string) {
+	target, err := e.connector(p.Target)
+	if err != nil {
+		return DeliveryUnknown, fmt.Sprintf("target could not be instantiated: %v", err)
+	}
+
+	if w, ok := target.(connectors.IdempotentWriter); ok && w.Idempotent() {
+		return DeliveryEffectivelyOnce, "checkpoints advance after apply and the target upserts by record ID"
+	}
+	return DeliveryAtLeastOnce, "checkpoints advance after apply; a batch interrupted by a crash is re-applied"
+}
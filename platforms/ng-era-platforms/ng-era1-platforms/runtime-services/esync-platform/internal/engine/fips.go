@@ -0,0 +1,57 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: fips-mode
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * FIPS Config Validation
+ */
+
+package engine
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/machine-native-ops/esync-platform/internal/fips"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// ValidateFIPS checks the connectors of every pipeline and every connection
+// profile against FIPS mode, returning all violations. It returns nil when
+// FIPS mode is off.
+func (e *Engine) ValidateFIPS() error {
+	if !fips.Enabled() {
+		return nil
+	}
+
+	reg := e.registry.Snapshot()
+	var errs []error
+	check := func(owner string, spec registry.ConnectorSpec) {
+		resolved, err := reg.ResolveConnector(spec)
+		if err == nil {
+			err = fips.Validate(resolved.Config)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", owner, err))
+		}
+	}
+	for _, p := range reg.GetAll() {
+		check("pipeline "+p.ID+" source", p.Source)
+		check("pipeline "+p.ID+" target", p.Target)
+		for _, route := range p.Routes {
+			check("pipeline "+p.ID+" route "+route.Table, route.Target)
+		}
+	}
+	for _, c := range reg.Connections() {
+		check("connection "+c.Name, registry.ConnectorSpec{Type: c.Type, Connection: c.Name})
+	}
+	return errors.Join(errs...)
+}
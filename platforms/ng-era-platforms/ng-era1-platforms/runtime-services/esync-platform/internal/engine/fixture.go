@@ -0,0 +1,294 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: replay-fixtures
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Recorded Run Fixtures
+ */
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+	"github.com/machine-native-ops/esync-platform/internal/secrets"
+)
+
+// FixtureFormat is the version of the fixture layout
+const FixtureFormat = 1
+
+// RecordMetadata is the trigger metadata key that, set to "true", records
+// the input of the run as a replay fixture
+const RecordMetadata = "record"
+
+// ErrNoFixture is returned when a pipeline has no recorded fixture
+var ErrNoFixture = errors.New("no recorded fixture")
+
+// Fixture is the input of a recorded run and what it wrote to the target.
+// Replaying it through Replay against an in-memory target reproduces the
+// run without connecting, so transform and conflict policy changes can be
+// checked against recorded traffic.
+type Fixture struct {
+	Format     int       `json:"format"`
+	PipelineID string    `json:"pipeline_id"`
+	RunID      string    `json:"run_id"`
+	RecordedAt time.Time `json:"recorded_at"`
+	// Pipeline is the definition the run used, without connector configs
+	Pipeline *registry.Pipeline `json:"pipeline"`
+	// Input holds the records listed from the source, before transforms
+	Input []connectors.Record `json:"input"`
+	// Existing holds the target versions of the input records before the
+	// run, when the target can read records back
+	Existing []connectors.Record `json:"existing,omitempty"`
+	// Schema is the target schema, when the target reports one
+	Schema *connectors.Schema `json:"schema,omitempty"`
+	// Output holds the records written to the target, in apply order
+	Output []connectors.Record `json:"output"`
+	// Error is the error the run failed with
+	Error string `json:"error,omitempty"`
+	// Redacted lists the fields whose values were replaced by
+	// secrets.Redacted because the pipeline flags them as personal data
+	Redacted []string `json:"redacted,omitempty"`
+}
+
+// Fixture returns the last fixture recorded for a pipeline
+func (e *Engine) Fixture(pipelineID string) (*Fixture, error) {
+	var f Fixture
+	found, err := e.store.Load(fixtureKey(pipelineID), &f)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("%w for %s", ErrNoFixture, pipelineID)
+	}
+	return &f, nil
+}
+
+// Replay runs records through the transforms, coercion and validation of a
+// pipeline and writes them to target, as a sync pass would, without a run or
+// checkpoint. Routes are ignored so every record reaches target, and records
+// are applied by a single worker so the apply order is deterministic.
+func (e *Engine) Replay(ctx context.Context, p *registry.Pipeline, target connectors.Connector, records []connectors.Record) (int, error) {
+	replayed := *p
+	replayed.Routes = nil
+	replayed.ApplyWorkers = 1
+	replayed.SLO = nil
+	return e.applyRouted(ctx, &replayed, target, records, nil, "", "")
+}
+
+// recordFixture starts the fixture of a pass when its run was triggered
+// with RecordMetadata, capturing the target versions of the input records
+// and the target schema
+func (e *Engine) recordFixture(ctx context.Context, p *registry.Pipeline, target connectors.Connector, tracker *progressTracker, input []connectors.Record) {
+	if tracker == nil || tracker.run.Trigger.Metadata[RecordMetadata] != "true" {
+		return
+	}
+
+	definition := *p
+	definition.Source = registry.ConnectorSpec{Type: p.Source.Type}
+	definition.Target = registry.ConnectorSpec{Type: p.Target.Type}
+	definition.Routes = nil
+	f := &Fixture{
+		Format:     FixtureFormat,
+		PipelineID: p.ID,
+		RunID:      tracker.run.ID,
+		RecordedAt: time.Now().UTC(),
+		Pipeline:   &definition,
+		Input:      append([]connectors.Record{}, input...),
+		Output:     []connectors.Record{},
+		Redacted:   e.personalFields(p),
+	}
+	if reader, ok := target.(connectors.RecordReader); ok && len(input) > 0 {
+		ids := make([]string, 0, len(input))
+		for _, r := range input {
+			ids = append(ids, r.ID)
+		}
+		existing, err := reader.ReadRecords(ctx, ids)
+		if err != nil && !errors.Is(err, connectors.ErrUnsupported) {
+			log.Printf("[Engine] Failed to read existing records for the fixture of %s: %v", p.ID, err)
+		}
+		f.Existing = existing
+	}
+	f.Schema, _ = introspect(ctx, target)
+
+	e.mu.Lock()
+	tracker.fixture = f
+	e.mu.Unlock()
+}
+
+// wrote adds records written to the target to the fixture being recorded
+func (t *progressTracker) wrote(records []connectors.Record) {
+	if t == nil {
+		return
+	}
+
+	t.e.mu.Lock()
+	defer t.e.mu.Unlock()
+
+	if t.fixture != nil {
+		written, _ := splitCanaries(records)
+		t.fixture.Output = append(t.fixture.Output, written...)
+	}
+}
+
+// saveFixture stores the fixture recorded by a pass, including the error
+// the pass failed with
+func (e *Engine) saveFixture(tracker *progressTracker, passErr error) {
+	if tracker == nil {
+		return
+	}
+
+	e.mu.Lock()
+	f := tracker.fixture
+	tracker.fixture = nil
+	e.mu.Unlock()
+	if f == nil {
+		return
+	}
+
+	if passErr != nil {
+		f.Error = passErr.Error()
+	}
+	f.redact()
+	if err := e.store.Save(fixtureKey(f.PipelineID), f); err != nil {
+		log.Printf("[Engine] Failed to save fixture of %s: %v", f.PipelineID, err)
+		return
+	}
+	e.mu.Lock()
+	tracker.run.Recorded = true
+	e.mu.Unlock()
+	log.Printf("[Engine] Recorded fixture of run %s: %d input records, %d written", f.RunID, len(f.Input), len(f.Output))
+}
+
+// personalFields returns the fields of a pipeline holding personal data:
+// those its PII findings flag and those its erasure block names
+func (e *Engine) personalFields(p *registry.Pipeline) []string {
+	fields := make(map[string]bool)
+	findings, err := e.PIIFindings(p.ID)
+	if err != nil {
+		log.Printf("[Engine] Failed to read PII findings of %s: %v", p.ID, err)
+	}
+	for _, f := range findings {
+		if len(f.Categories) > 0 {
+			fields[f.Field] = true
+		}
+	}
+	if p.Erasure != nil {
+		for _, field := range p.Erasure.Fields {
+			fields[field] = true
+		}
+		if p.Erasure.SubjectField != "" {
+			fields[p.Erasure.SubjectField] = true
+		}
+	}
+
+	out := make([]string, 0, len(fields))
+	for field := range fields {
+		out = append(out, field)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// redact replaces the values of the redacted fields in every record of the
+// fixture
+func (f *Fixture) redact() {
+	if len(f.Redacted) == 0 {
+		return
+	}
+	f.Input = redactRecords(f.Input, f.Redacted)
+	f.Existing = redactRecords(f.Existing, f.Redacted)
+	f.Output = redactRecords(f.Output, f.Redacted)
+}
+
+// redactRecords returns records with the non-null values of fields
+// replaced, copying the data of changed records
+func redactRecords(records []connectors.Record, fields []string) []connectors.Record {
+	out := make([]connectors.Record, len(records))
+	for i, r := range records {
+		var data map[string]interface{}
+		for _, field := range fields {
+			if v, ok := r.Data[field]; !ok || v == nil {
+				continue
+			}
+			if data == nil {
+				data = make(map[string]interface{}, len(r.Data))
+				for k, v := range r.Data {
+					data[k] = v
+				}
+			}
+			data[field] = secrets.Redacted
+		}
+		if data != nil {
+			r.Data = data
+		}
+		out[i] = r
+	}
+	return out
+}
+
+// eraseFixture removes the records of erased IDs from the fixture of a
+// pipeline, or nulls fields of them when the erasure action patches. Only
+// records of table are touched when it is set.
+func (e *Engine) eraseFixture(p *registry.Pipeline, table string, ids []string, action string) error {
+	var f Fixture
+	found, err := e.store.Load(fixtureKey(p.ID), &f)
+	if err != nil || !found {
+		return err
+	}
+
+	erased := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		erased[id] = true
+	}
+	n := 0
+	erase := func(records []connectors.Record) []connectors.Record {
+		kept := records[:0]
+		for _, r := range records {
+			if !erased[r.ID] || table != "" && r.Table != table {
+				kept = append(kept, r)
+				continue
+			}
+			n++
+			if action == registry.ErasurePatch {
+				for _, field := range p.Erasure.Fields {
+					if r.Has(field) {
+						r.Data[field] = nil
+					}
+				}
+				kept = append(kept, r)
+			}
+		}
+		return kept
+	}
+	f.Input = erase(f.Input)
+	f.Existing = erase(f.Existing)
+	f.Output = erase(f.Output)
+	if n == 0 {
+		return nil
+	}
+	if err := e.store.Save(fixtureKey(p.ID), &f); err != nil {
+		return fmt.Errorf("failed to save erased fixture: %w", err)
+	}
+	return nil
+}
+
+// fixtureKey is the store key of the fixture of a pipeline
+func fixtureKey(pipelineID string) string {
+	return "fixtures/" + pipelineID
+}
@@ -0,0 +1,207 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: runtime-flags
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Runtime Diagnostic Flags
+ */
+
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/logging"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// Runtime flags toggled through the admin API. They are diagnostics only,
+// are not persisted and reset to their defaults on restart.
+const (
+	// FlagVerboseConnectors logs every connector call with its duration
+	FlagVerboseConnectors = "verbose_connector_logging"
+	// FlagTapSampleRate logs the ID and operation of this share of the
+	// applied records, never their data
+	FlagTapSampleRate = "tap_sample_rate"
+)
+
+// Flag types
+const (
+	FlagBool  = "bool"
+	FlagFloat = "float"
+)
+
+// ErrUnknownFlag is returned when setting a flag that does not exist
+var ErrUnknownFlag = errors.New("unknown flag")
+
+// Flag is a diagnostic setting that can be changed without a restart
+type Flag struct {
+	Name        string      `json:"name"`
+	Type        string      `json:"type"`
+	Value       interface{} `json:"value"`
+	Default     interface{} `json:"default"`
+	Description string      `json:"description"`
+	UpdatedAt   time.Time   `json:"updated_at,omitempty"`
+}
+
+// defaultFlags are the runtime flags of a new engine
+func defaultFlags() map[string]*Flag {
+	flags := []*Flag{
+		{Name: FlagVerboseConnectors, Type: FlagBool, Value: false, Description: "Log every connector call with its duration and record count"},
+		{Name: FlagTapSampleRate, Type: FlagFloat, Value: 0.0, Description: "Share of applied records logged with their ID and operation (0-1)"},
+	}
+	out := make(map[string]*Flag, len(flags))
+	for _, f := range flags {
+		f.Default = f.Value
+		out[f.Name] = f
+	}
+	return out
+}
+
+// Flags returns the runtime flags sorted by name
+func (e *Engine) Flags() []Flag {
+	e.flagsMu.RLock()
+	defer e.flagsMu.RUnlock()
+
+	out := make([]Flag, 0, len(e.flags))
+	for _, f := range e.flags {
+		out = append(out, *f)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// SetFlag parses value for a runtime flag and applies it immediately
+func (e *Engine) SetFlag(name, value string) (*Flag, error) {
+	e.flagsMu.Lock()
+	defer e.flagsMu.Unlock()
+
+	f, ok := e.flags[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %s", ErrUnknownFlag, name)
+	}
+
+	var v interface{}
+	switch f.Type {
+	case FlagBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("flag %s takes true or false", name)
+		}
+		v = b
+	case FlagFloat:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil || n < 0 || n > 1 {
+			return nil, fmt.Errorf("flag %s takes a number between 0 and 1", name)
+		}
+		v = n
+	}
+
+	f.Value, f.UpdatedAt = v, time.Now().UTC()
+	log.Printf("[Engine] Flag %s set to %v", name, v)
+	out := *f
+	return &out, nil
+}
+
+// flagBool returns the value of a bool flag
+func (e *Engine) flagBool(name string) bool {
+	e.flagsMu.RLock()
+	defer e.flagsMu.RUnlock()
+
+	v, _ := e.flags[name].Value.(bool)
+	return v
+}
+
+// flagFloat returns the value of a float flag
+func (e *Engine) flagFloat(name string) float64 {
+	e.flagsMu.RLock()
+	defer e.flagsMu.RUnlock()
+
+	v, _ := e.flags[name].Value.(float64)
+	return v
+}
+
+// traceCall logs a call to a connector of a type when verbose connector
+// logging is on, the debug level applies or the pipeline is traced
+func (e *Engine) traceCall(pipelineID, connector, call string, start time.Time, records int, err error) {
+	msg := fmt.Sprintf("%s took %s (%d records)", call, time.Since(start), records)
+	if err != nil {
+		msg = fmt.Sprintf("%s failed after %s: %v", call, time.Since(start), err)
+	}
+	if e.flagBool(FlagVerboseConnectors) {
+		log.Printf("[Engine] Pipeline %s: %s", pipelineID, msg)
+		return
+	}
+	scope := logging.Scope{Module: "engine", Connector: connector, Pipeline: pipelineID}
+	if !e.tracing(pipelineID) && logging.Enabled(logging.LevelDebug, scope) {
+		logging.Debugf(scope, "[Engine] Pipeline %s: %s %s", pipelineID, connector, msg)
+		return
+	}
+	e.tracef(pipelineID, "%s", msg)
+}
+
+// connectorType returns the type of a connector spec, resolving the type
+// of its connection profile
+func (e *Engine) connectorType(spec registry.ConnectorSpec) string {
+	if spec.Type == "" && spec.Connection != "" {
+		if resolved, err := e.registry.ResolveConnector(spec); err == nil {
+			return resolved.Type
+		}
+	}
+	return spec.Type
+}
+
+// tap logs a sample of applied records at the tap sample rate, or every
+// applied record of a traced pipeline
+func (e *Engine) tap(pipelineID string, records []connectors.Record) {
+	traced := e.tracing(pipelineID)
+	rate := e.flagFloat(FlagTapSampleRate)
+	if rate <= 0 && !traced {
+		return
+	}
+	for _, r := range records {
+		if traced {
+			e.tracef(pipelineID, "applied %s %s", r.Operation, recordRef(r))
+			continue
+		}
+		if rand.Float64() < rate {
+			log.Printf("[Tap] Pipeline %s: %s %s", pipelineID, r.Operation, recordRef(r))
+		}
+	}
+}
+
+// traceDropped traces the records a transform chain dropped
+func (e *Engine) traceDropped(pipelineID string, before, after []connectors.Record) {
+	kept := make(map[string]bool, len(after))
+	for _, r := range after {
+		kept[recordRef(r)] = true
+	}
+	for _, r := range before {
+		if !kept[recordRef(r)] {
+			e.tracef(pipelineID, "record %s dropped by transforms", recordRef(r))
+		}
+	}
+}
+
+// recordRef identifies a record in logs by table and ID, never its data
+func recordRef(r connectors.Record) string {
+	if r.Table != "" {
+		return r.Table + "/" + r.ID
+	}
+	return r.ID
+}
@@ -0,0 +1,51 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: engine-fleet
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Fleet Pipeline State
+ */
+
+package engine
+
+import (
+	"fmt"
+	"time"
+)
+
+// fleetKey is where an agent keeps the fleet revision it applied
+const fleetKey = "fleet"
+
+// FleetState is the fleet revision an agent applied and the version of
+// each pipeline the fleet manager distributed
+type FleetState struct {
+	Revision  string            `json:"revision"`
+	Versions  map[string]uint64 `json:"versions"`
+	AppliedAt time.Time         `json:"applied_at"`
+}
+
+// FleetState returns the fleet revision the agent applied; it is empty
+// until the first one is
+func (e *Engine) FleetState() (*FleetState, error) {
+	var st FleetState
+	if _, err := e.store.Load(fleetKey, &st); err != nil {
+		return nil, fmt.Errorf("failed to load fleet state: %w", err)
+	}
+	return &st, nil
+}
+
+// SaveFleetState records the fleet revision the agent applied
+func (e *Engine) SaveFleetState(st *FleetState) error {
+	if err := e.store.Save(fleetKey, st); err != nil {
+		return fmt.Errorf("failed to save fleet state: %w", err)
+	}
+	return nil
+}
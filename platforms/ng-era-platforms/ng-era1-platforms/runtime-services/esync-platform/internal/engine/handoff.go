@@ -0,0 +1,255 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: daemon-handoff
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Blue/Green Daemon Handoff
+ */
+
+package engine
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// DefaultHandoffLease is how long a new daemon has to complete a handoff
+// before the old one resumes its pipelines
+const DefaultHandoffLease = 2 * time.Minute
+
+// Handoff states
+const (
+	HandoffDraining  = "draining"
+	HandoffLeased    = "leased"
+	HandoffCompleted = "completed"
+	HandoffAborted   = "aborted"
+	HandoffExpired   = "expired"
+)
+
+// AuditHandedOff is the audit decision recorded when pipelines are handed
+// off to another daemon
+const AuditHandedOff = "handed_off"
+
+var (
+	// ErrHandoff is returned for runs requested while the pipelines are
+	// being or have been handed off to another daemon
+	ErrHandoff = errors.New("pipelines are handed off to another daemon")
+	// ErrNoHandoff is returned when a handoff ID does not match the
+	// leased handoff
+	ErrNoHandoff = errors.New("no matching handoff in progress")
+)
+
+// Handoff describes the transfer of every pipeline to a new daemon. The old
+// daemon stops starting runs, drains each pipeline and hands over its state
+// under a lease; the pipelines stay with it unless the new daemon completes
+// the handoff before the lease expires.
+type Handoff struct {
+	ID           string    `json:"id"`
+	Status       string    `json:"status"`
+	StartedAt    time.Time `json:"started_at"`
+	LeaseExpires time.Time `json:"lease_expires,omitempty"`
+	CompletedAt  time.Time `json:"completed_at,omitempty"`
+	// Drained counts the records applied per pipeline while draining
+	Drained map[string]int `json:"drained,omitempty"`
+	// Errors holds the pipelines that failed to drain; they resume from
+	// their last checkpoint on the new daemon
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// handoffState is the handoff of this daemon and the run locks it holds
+type handoffState struct {
+	Handoff
+	held  []*runLock
+	timer *time.Timer
+}
+
+// BeginHandoff stops new runs, waits for running ones, drains every
+// unpaused pipeline and writes a backup of the state to w. The run locks
+// stay held until the handoff is completed, aborted, or its lease expires.
+func (e *Engine) BeginHandoff(ctx context.Context, lease time.Duration, w io.Writer) (*Handoff, error) {
+	if lease <= 0 {
+		lease = DefaultHandoffLease
+	}
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("failed to generate handoff id: %w", err)
+	}
+
+	h := &handoffState{Handoff: Handoff{
+		ID:        hex.EncodeToString(id),
+		Status:    HandoffDraining,
+		StartedAt: time.Now().UTC(),
+		Drained:   make(map[string]int),
+		Errors:    make(map[string]string),
+	}}
+	e.handoffMu.Lock()
+	if cur := e.handoff; cur != nil && (cur.Status == HandoffDraining || cur.Status == HandoffLeased || cur.Status == HandoffCompleted) {
+		e.handoffMu.Unlock()
+		return nil, fmt.Errorf("handoff %s is %s: %w", cur.ID, cur.Status, ErrHandoff)
+	}
+	e.handoff = h
+	e.handoffMu.Unlock()
+	log.Printf("[Engine] Handing off pipelines (handoff %s)", h.ID)
+
+	for _, p := range e.registry.GetAll() {
+		lock := e.lockFor(p.ID)
+		select {
+		case lock.sem <- struct{}{}:
+			e.handoffMu.Lock()
+			h.held = append(h.held, lock)
+			e.handoffMu.Unlock()
+		case <-ctx.Done():
+			e.releaseHandoff(h, HandoffAborted, HandoffDraining)
+			return nil, fmt.Errorf("failed to wait for running pipelines: %w", ctx.Err())
+		}
+	}
+
+	for _, p := range e.registry.GetAll() {
+		if paused, err := e.Paused(p.ID); err != nil || paused {
+			continue
+		}
+		n, err := e.drain(ctx, p.ID, registry.DefaultMaxDrainPasses)
+		e.handoffMu.Lock()
+		h.Drained[p.ID] = n
+		if err != nil {
+			h.Errors[p.ID] = err.Error()
+		}
+		e.handoffMu.Unlock()
+		if err != nil {
+			log.Printf("[Engine] Pipeline %s failed to drain for handoff: %v", p.ID, err)
+		}
+	}
+
+	if _, err := e.Backup(w); err != nil {
+		e.releaseHandoff(h, HandoffAborted, HandoffDraining)
+		return nil, err
+	}
+
+	e.handoffMu.Lock()
+	h.Status = HandoffLeased
+	h.LeaseExpires = time.Now().UTC().Add(lease)
+	h.timer = time.AfterFunc(lease, func() {
+		if e.releaseHandoff(h, HandoffExpired, HandoffLeased) {
+			log.Printf("[Engine] Handoff %s lease expired; resuming pipelines", h.ID)
+		}
+	})
+	out := h.snapshot()
+	e.handoffMu.Unlock()
+	return out, nil
+}
+
+// CompleteHandoff records that the new daemon has taken over. The run locks
+// are never released, so the pipelines do not run here again.
+func (e *Engine) CompleteHandoff(id string) (*Handoff, error) {
+	e.handoffMu.Lock()
+	h := e.handoff
+	if h == nil || h.ID != id || h.Status != HandoffLeased {
+		e.handoffMu.Unlock()
+		return nil, ErrNoHandoff
+	}
+	h.timer.Stop()
+	h.Status = HandoffCompleted
+	h.CompletedAt = time.Now().UTC()
+	out := h.snapshot()
+	e.handoffMu.Unlock()
+
+	log.Printf("[Engine] Handoff %s completed; pipelines are handed off", id)
+	e.audit(AuditEntry{
+		Policy:   "handoff",
+		Decision: AuditHandedOff,
+		Detail:   fmt.Sprintf("handoff %s: %d pipelines drained", id, len(out.Drained)),
+	})
+	close(e.handedOff)
+	return out, nil
+}
+
+// AbortHandoff releases the run locks of a leased handoff, resuming the
+// pipelines here
+func (e *Engine) AbortHandoff(id string) (*Handoff, error) {
+	e.handoffMu.Lock()
+	h := e.handoff
+	e.handoffMu.Unlock()
+	if h == nil || h.ID != id || !e.releaseHandoff(h, HandoffAborted, HandoffLeased) {
+		return nil, ErrNoHandoff
+	}
+	h.timer.Stop()
+
+	log.Printf("[Engine] Handoff %s aborted; resuming pipelines", id)
+	return e.CurrentHandoff(), nil
+}
+
+// releaseHandoff moves a handoff in state from to status and releases its
+// run locks, reporting false when it was no longer in state from
+func (e *Engine) releaseHandoff(h *handoffState, status, from string) bool {
+	e.handoffMu.Lock()
+	if h.Status != from {
+		e.handoffMu.Unlock()
+		return false
+	}
+	h.Status = status
+	held := h.held
+	h.held = nil
+	e.handoffMu.Unlock()
+
+	for _, lock := range held {
+		<-lock.sem
+	}
+	return true
+}
+
+// CurrentHandoff returns the last handoff of this daemon, or nil
+func (e *Engine) CurrentHandoff() *Handoff {
+	e.handoffMu.Lock()
+	defer e.handoffMu.Unlock()
+
+	if e.handoff == nil {
+		return nil
+	}
+	return e.handoff.snapshot()
+}
+
+// snapshot copies the handoff. Callers hold e.handoffMu.
+func (h *handoffState) snapshot() *Handoff {
+	out := h.Handoff
+	out.Drained = make(map[string]int, len(h.Drained))
+	for id, n := range h.Drained {
+		out.Drained[id] = n
+	}
+	out.Errors = make(map[string]string, len(h.Errors))
+	for id, msg := range h.Errors {
+		out.Errors[id] = msg
+	}
+	return &out
+}
+
+// HandedOff is closed once a handoff completes
+func (e *Engine) HandedOff() <-chan struct{} {
+	return e.handedOff
+}
+
+// checkHandoff rejects runs while a handoff holds the pipelines
+func (e *Engine) checkHandoff(pipelineID string) error {
+	e.handoffMu.Lock()
+	defer e.handoffMu.Unlock()
+
+	if h := e.handoff; h != nil && (h.Status == HandoffDraining || h.Status == HandoffLeased || h.Status == HandoffCompleted) {
+		return fmt.Errorf("cannot run %s: %w", pipelineID, ErrHandoff)
+	}
+	return nil
+}
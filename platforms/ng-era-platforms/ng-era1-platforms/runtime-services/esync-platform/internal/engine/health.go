@@ -0,0 +1,233 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: pipeline-health
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Pipeline Health Score
+ */
+
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// Health levels of a pipeline by score
+const (
+	HealthHealthy   = "healthy"
+	HealthDegraded  = "degraded"
+	HealthUnhealthy = "unhealthy"
+	// HealthPaused pipelines were paused by an operator and are not scored
+	HealthPaused = "paused"
+)
+
+const (
+	// healthRuns is how many recent runs the failure factor looks at
+	healthRuns = 10
+	// healthDegradedBelow and healthUnhealthyBelow are the score bounds of
+	// the levels
+	healthDegradedBelow  = 90
+	healthUnhealthyBelow = 60
+	// healthDLQWindow is the window dead-letter growth is measured over and
+	// healthDLQLimit the growth that counts fully against the score
+	healthDLQWindow = time.Hour
+	healthDLQLimit  = 100
+)
+
+// Weights of the health factors, summing to 100
+const (
+	weightFailures = 45
+	weightLag      = 25
+	weightHeld     = 15
+	weightDLQ      = 15
+)
+
+// PipelineHealth is a composite health score of a pipeline from 0 (worst)
+// to 100 and the factors it is made of
+type PipelineHealth struct {
+	PipelineID string `json:"pipeline_id"`
+	Score      int    `json:"score"`
+	Level      string `json:"level"`
+	// Reasons explains what lowered the score, worst first
+	Reasons []string `json:"reasons,omitempty"`
+	Owner   string   `json:"owner,omitempty"`
+	Tier    string   `json:"tier,omitempty"`
+
+	// FailureRate is the share of the recent runs that failed and
+	// ConsecutiveFailures how many of the latest failed in a row
+	FailureRate         float64 `json:"failure_rate"`
+	ConsecutiveFailures int     `json:"consecutive_failures"`
+	// LagRatio is the SLO staleness over the freshness objective or, without
+	// one, the source lag over the lag target of the autoscaling signal
+	LagRatio float64 `json:"lag_ratio"`
+	// Held names what keeps the pipeline from running on its own, such as a
+	// watchdog quarantine, a pending schema change, a failed preflight or a
+	// holdback of its target
+	Held string `json:"held,omitempty"`
+	// DeadLetters counts the dead letters and DeadLettersAdded those added
+	// in the last hour
+	DeadLetters      int `json:"dead_letters"`
+	DeadLettersAdded int `json:"dead_letters_added"`
+}
+
+// healthFactor is one factor lowering the score: its weight times its
+// badness from 0 to 1
+type healthFactor struct {
+	penalty float64
+	reason  string
+}
+
+// Health returns the health of a pipeline
+func (e *Engine) Health(pipelineID string) (*PipelineHealth, error) {
+	p, err := e.registry.GetByID(pipelineID)
+	if err != nil {
+		return nil, err
+	}
+	return e.health(p, time.Now())
+}
+
+// FleetHealth returns the health of every pipeline, worst first, with
+// operator-paused pipelines last
+func (e *Engine) FleetHealth() ([]PipelineHealth, error) {
+	now := time.Now()
+	out := []PipelineHealth{}
+	for _, p := range e.registry.GetAll() {
+		h, err := e.health(p, now)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *h)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		pi, pj := out[i].Level == HealthPaused, out[j].Level == HealthPaused
+		switch {
+		case pi != pj:
+			return pj
+		case out[i].Score != out[j].Score:
+			return out[i].Score < out[j].Score
+		default:
+			return out[i].PipelineID < out[j].PipelineID
+		}
+	})
+	return out, nil
+}
+
+// health scores a pipeline from its recent runs, its lag against its
+// objective, what holds it and the growth of its dead-letter queue
+func (e *Engine) health(p *registry.Pipeline, now time.Time) (*PipelineHealth, error) {
+	h := &PipelineHealth{PipelineID: p.ID, Owner: p.Owner, Tier: p.Tier}
+
+	var pause pauseState
+	paused, err := e.store.Load("paused/"+p.ID, &pause)
+	if err != nil {
+		return nil, err
+	}
+	if paused && pause.Reason == "" {
+		h.Score, h.Level = 100, HealthPaused
+		h.Reasons = []string{"paused by an operator"}
+		return h, nil
+	}
+
+	var factors []healthFactor
+
+	runs := e.Runs(p.ID)
+	if len(runs) > healthRuns {
+		runs = runs[:healthRuns]
+	}
+	failed := 0
+	for i, run := range runs {
+		if run.Status != StatusFailed {
+			continue
+		}
+		failed++
+		if i == h.ConsecutiveFailures {
+			h.ConsecutiveFailures++
+		}
+	}
+	if len(runs) > 0 {
+		h.FailureRate = float64(failed) / float64(len(runs))
+	}
+	if badness := max(h.FailureRate, min(float64(h.ConsecutiveFailures)/3, 1)); badness > 0 {
+		factors = append(factors, healthFactor{weightFailures * badness,
+			fmt.Sprintf("%d of the last %d runs failed, %d in a row", failed, len(runs), h.ConsecutiveFailures)})
+	}
+
+	stuck := false
+	if slo := e.SLOStatus(p.ID); slo != nil && slo.FreshnessSeconds > 0 {
+		h.LagRatio = slo.StalenessSeconds / slo.FreshnessSeconds
+	} else if a := e.SourceActivity(p.ID); a != nil {
+		h.LagRatio = a.LagSeconds / lagTarget(p).Seconds()
+		stuck = a.State == SourceStuck
+	}
+	if badness := min(max(h.LagRatio-0.5, 0), 1); badness > 0 || stuck {
+		reason := fmt.Sprintf("lag at %.0f%% of its target", h.LagRatio*100)
+		if stuck {
+			badness, reason = 1, "source stuck: no changes or heartbeats within the heartbeat timeout"
+		}
+		factors = append(factors, healthFactor{weightLag * badness, reason})
+	}
+
+	held, err := e.heldBack(p.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case paused:
+		h.Held = "paused for " + pause.Reason
+	case e.checkPreflight(p.ID) != nil:
+		h.Held = "preflight failed"
+	case held != nil:
+		h.Held = "target held back"
+		if held.Reason != "" {
+			h.Held += ": " + held.Reason
+		}
+	}
+	if h.Held != "" {
+		factors = append(factors, healthFactor{weightHeld, h.Held})
+	}
+
+	letters, err := e.DeadLetters(p.ID)
+	if err != nil {
+		return nil, err
+	}
+	h.DeadLetters = len(letters)
+	for _, l := range letters {
+		if now.Sub(l.At) <= healthDLQWindow {
+			h.DeadLettersAdded++
+		}
+	}
+	if h.DeadLettersAdded > 0 {
+		badness := min(float64(h.DeadLettersAdded)/healthDLQLimit, 1)
+		factors = append(factors, healthFactor{weightDLQ * badness,
+			fmt.Sprintf("%d dead letters added in the last hour", h.DeadLettersAdded)})
+	}
+
+	sort.SliceStable(factors, func(i, j int) bool { return factors[i].penalty > factors[j].penalty })
+	score := 100.0
+	for _, f := range factors {
+		score -= f.penalty
+		h.Reasons = append(h.Reasons, f.reason)
+	}
+	h.Score = int(max(score, 0) + 0.5)
+	switch {
+	case h.Score < healthUnhealthyBelow:
+		h.Level = HealthUnhealthy
+	case h.Score < healthDegradedBelow:
+		h.Level = HealthDegraded
+	default:
+		h.Level = HealthHealthy
+	}
+	return h, nil
+}
@@ -0,0 +1,180 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: source-heartbeats
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * CDC Source Heartbeats
+ */
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// Source states
+const (
+	// SourceActive sources returned changes in the last pass
+	SourceActive = "active"
+	// SourceIdle sources returned no changes but sent a heartbeat or
+	// advanced their position within their heartbeat timeout; sources
+	// without a heartbeat spec are never stuck
+	SourceIdle = "idle"
+	// SourceStuck sources sent nothing for longer than their heartbeat
+	// timeout
+	SourceStuck = "stuck"
+)
+
+// SourceActivity is what the sync passes of a pipeline last saw of its
+// source
+type SourceActivity struct {
+	State         string    `json:"state"`
+	LastChange    time.Time `json:"last_change,omitempty"`
+	LastHeartbeat time.Time `json:"last_heartbeat,omitempty"`
+	// LagSeconds is the time since the source time of the newest change or
+	// heartbeat, corrected by the skew of the source clock; heartbeats keep
+	// it low while the source is idle
+	LagSeconds float64 `json:"lag_seconds"`
+	// ClockSkew is the last measured skew of the source clock
+	ClockSkew *ClockSkew `json:"clock_skew,omitempty"`
+	// lastSeen is the source time the source last showed it was alive
+	lastSeen time.Time
+}
+
+// SourceActivity returns what the sync passes of a pipeline last saw of its
+// source, or nil before the first pass
+func (e *Engine) SourceActivity(pipelineID string) *SourceActivity {
+	e.sourcesMu.Lock()
+	defer e.sourcesMu.Unlock()
+
+	a := e.sources[pipelineID]
+	if a == nil {
+		return nil
+	}
+	out := *a
+	return &out
+}
+
+// splitHeartbeats drops heartbeat records, returning the remaining records
+// and the source time of the newest heartbeat
+func splitHeartbeats(records []connectors.Record) ([]connectors.Record, time.Time) {
+	var newest time.Time
+	kept := records[:0:0]
+	for _, r := range records {
+		if r.Operation != connectors.OperationHeartbeat {
+			kept = append(kept, r)
+			continue
+		}
+		ts := r.Timestamp
+		if ts.IsZero() {
+			ts = time.Now().UTC()
+		}
+		if ts.After(newest) {
+			newest = ts
+		}
+	}
+	if newest.IsZero() {
+		return records, newest
+	}
+	return kept, newest
+}
+
+// observeSource updates the source activity of a pipeline after a sync pass
+// that listed changes, saw a heartbeat at the given source time (zero for
+// none) and moved the checkpoint or not. Skew is how far the source times
+// are still ahead of the daemon clock.
+func (e *Engine) observeSource(p *registry.Pipeline, changes []connectors.Record, heartbeat time.Time, moved bool, skew time.Duration) {
+	now := time.Now().UTC()
+	heartbeat = shiftTime(heartbeat, skew)
+
+	e.sourcesMu.Lock()
+	a := e.sources[p.ID]
+	if a == nil {
+		a = &SourceActivity{lastSeen: now}
+		e.sources[p.ID] = a
+	}
+	var newest time.Time
+	for _, r := range changes {
+		ts := shiftTime(r.Timestamp, skew)
+		if ts.IsZero() {
+			ts = now
+		}
+		if ts.After(newest) {
+			newest = ts
+		}
+	}
+	if !newest.IsZero() {
+		a.LastChange = newest
+	}
+	if !heartbeat.IsZero() {
+		a.LastHeartbeat = heartbeat
+		if heartbeat.After(newest) {
+			newest = heartbeat
+		}
+	}
+	// A position moved without changes or heartbeats is a sign of life
+	// without a source time
+	if newest.IsZero() && moved {
+		newest = now
+	}
+	if !newest.IsZero() {
+		a.lastSeen = newest
+	}
+
+	lag := now.Sub(a.lastSeen)
+	switch {
+	case len(changes) > 0:
+		a.State = SourceActive
+	case newest.IsZero() && p.Heartbeat != nil && lag > heartbeatTimeout(p.Heartbeat):
+		a.State = SourceStuck
+	default:
+		a.State = SourceIdle
+	}
+	a.LagSeconds = lag.Seconds()
+	state := a.State
+	e.sourcesMu.Unlock()
+
+	e.monitor.RecordSourceActivity(p.ID, state, lag, heartbeat)
+}
+
+// heartbeatTimeout returns the timeout of a heartbeat spec
+func heartbeatTimeout(spec *registry.HeartbeatSpec) time.Duration {
+	if spec.Timeout > 0 {
+		return time.Duration(spec.Timeout) * time.Second
+	}
+	return registry.DefaultHeartbeatTimeout * time.Second
+}
+
+// acknowledge confirms a saved checkpoint to sources that hold changes until
+// confirmed. A failed acknowledgement does not fail the pass: the checkpoint
+// is saved and the next pass acknowledges a later one.
+func (e *Engine) acknowledge(ctx context.Context, p *registry.Pipeline, source connectors.Connector, checkpoint *connectors.Checkpoint) {
+	a, ok := source.(connectors.Acknowledger)
+	if !ok {
+		return
+	}
+
+	start := time.Now()
+	err := a.Acknowledge(ctx, checkpoint)
+	if errors.Is(err, connectors.ErrUnsupported) {
+		return
+	}
+	e.traceCall(p.ID, e.connectorType(p.Source), "acknowledge", start, 0, err)
+	if err != nil {
+		e.recordError(p.ID, "source", fmt.Errorf("failed to acknowledge position %s: %w", checkpoint.Position, err))
+	}
+}
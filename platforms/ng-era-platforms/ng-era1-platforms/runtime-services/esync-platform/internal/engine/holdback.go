@@ -0,0 +1,193 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: target-holdback
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Target Holdbacks
+ */
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+)
+
+// holdbackInterval is how often expired holdbacks are released and the
+// spools of released pipelines applied
+const holdbackInterval = 30 * time.Second
+
+// Events of holdbacks being set and released; Reason is the reason given
+const (
+	EventHeldBack = "held_back"
+	EventReleased = "released"
+)
+
+// holdbackState is the persisted holdback of a pipeline
+type holdbackState struct {
+	Reason string    `json:"reason,omitempty"`
+	By     string    `json:"by,omitempty"`
+	SetAt  time.Time `json:"set_at"`
+	Until  time.Time `json:"until,omitempty"`
+}
+
+// Holdback is whether writes of a pipeline to its target are held back, as
+// asked by a downstream system such as a warehouse outside its load window,
+// and what is spooled meanwhile
+type Holdback struct {
+	PipelineID string `json:"pipeline_id"`
+	Held       bool   `json:"held"`
+	// Reason and By are what the system setting the holdback gave
+	Reason string    `json:"reason,omitempty"`
+	By     string    `json:"by,omitempty"`
+	SetAt  time.Time `json:"set_at,omitempty"`
+	// Until releases the holdback on its own; zero holds until released
+	Until time.Time `json:"until,omitempty"`
+	// Spooled counts the records read while held back and not yet applied
+	Spooled    int   `json:"spooled"`
+	SpoolBytes int64 `json:"spool_bytes"`
+}
+
+// holdbackKey is the state key of the holdback of a pipeline
+func holdbackKey(pipelineID string) string {
+	return "holdbacks/" + pipelineID
+}
+
+// SetHoldback holds back the writes of a pipeline to its target until it
+// is released or until passes, when not zero. Sync passes keep reading the
+// source and advancing the checkpoint, spooling the changes in the state
+// store, and apply the spool in order once the holdback is released.
+// Setting a holdback again replaces it.
+func (e *Engine) SetHoldback(pipelineID, reason, by string, until time.Time) (*Holdback, error) {
+	if _, err := e.registry.GetByID(pipelineID); err != nil {
+		return nil, err
+	}
+	if !until.IsZero() && !until.After(time.Now()) {
+		return nil, errors.New("holdback must last until a future time")
+	}
+
+	st := holdbackState{Reason: reason, By: by, SetAt: time.Now().UTC()}
+	if !until.IsZero() {
+		st.Until = until.UTC()
+	}
+	if err := e.store.Save(holdbackKey(pipelineID), st); err != nil {
+		return nil, err
+	}
+	log.Printf("[Engine] Holding back writes of pipeline %s (%s)", pipelineID, reason)
+	e.publish(EventHeldBack, pipelineID, nil, reason)
+	return e.Holdback(pipelineID)
+}
+
+// ReleaseHoldback lets a pipeline write to its target again; its spool is
+// applied in the background
+func (e *Engine) ReleaseHoldback(pipelineID string) (*Holdback, error) {
+	if _, err := e.registry.GetByID(pipelineID); err != nil {
+		return nil, err
+	}
+
+	if err := e.store.Delete(holdbackKey(pipelineID)); err != nil {
+		return nil, err
+	}
+	log.Printf("[Engine] Released the holdback of pipeline %s", pipelineID)
+	e.publish(EventReleased, pipelineID, nil, "")
+	select {
+	case e.holdbackWake <- struct{}{}:
+	default:
+	}
+	return e.Holdback(pipelineID)
+}
+
+// Holdback returns the holdback of a pipeline and its spool
+func (e *Engine) Holdback(pipelineID string) (*Holdback, error) {
+	if _, err := e.registry.GetByID(pipelineID); err != nil {
+		return nil, err
+	}
+
+	h := &Holdback{PipelineID: pipelineID}
+	st, err := e.heldBack(pipelineID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if st != nil {
+		h.Held, h.Reason, h.By, h.SetAt, h.Until = true, st.Reason, st.By, st.SetAt, st.Until
+	}
+	spool, err := e.SpoolStatus(pipelineID)
+	if err != nil {
+		return nil, err
+	}
+	h.Spooled, h.SpoolBytes = spool.Records, spool.Bytes
+	return h, nil
+}
+
+// heldBack returns the holdback of a pipeline in force at now, or nil
+func (e *Engine) heldBack(pipelineID string, now time.Time) (*holdbackState, error) {
+	var st holdbackState
+	found, err := e.store.Load(holdbackKey(pipelineID), &st)
+	if err != nil || !found {
+		return nil, err
+	}
+	if !st.Until.IsZero() && !now.Before(st.Until) {
+		return nil, nil
+	}
+	return &st, nil
+}
+
+// WatchHoldbacks releases expired holdbacks and applies the spools of
+// released pipelines, until ctx is cancelled. Agents upload their spools
+// on every pass and are left alone.
+func (e *Engine) WatchHoldbacks(ctx context.Context) {
+	ticker := time.NewTicker(holdbackInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-e.holdbackWake:
+		}
+
+		if e.Agent() != nil {
+			continue
+		}
+		now := time.Now()
+		for _, p := range e.registry.GetAll() {
+			var st holdbackState
+			found, err := e.store.Load(holdbackKey(p.ID), &st)
+			if err != nil {
+				log.Printf("[Engine] Failed to load the holdback of pipeline %s: %v", p.ID, err)
+				continue
+			}
+			if found {
+				if st.Until.IsZero() || now.Before(st.Until) {
+					continue
+				}
+				if _, err := e.ReleaseHoldback(p.ID); err != nil {
+					log.Printf("[Engine] Failed to release the expired holdback of pipeline %s: %v", p.ID, err)
+					continue
+				}
+			}
+
+			status, err := e.SpoolStatus(p.ID)
+			if err != nil || status.Records == 0 || e.CurrentRun(p.ID) != nil {
+				continue
+			}
+			go func(id string) {
+				_, err := e.RunOnce(ctx, id, Trigger{Type: TriggerHoldback})
+				if err != nil && !errors.Is(err, ErrPaused) && !errors.Is(err, ErrRunInProgress) {
+					log.Printf("[Engine] Failed to apply the spool of pipeline %s: %v", id, err)
+				}
+			}(p.ID)
+		}
+	}
+}
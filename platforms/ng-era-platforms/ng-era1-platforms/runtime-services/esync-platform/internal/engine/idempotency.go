@@ -0,0 +1,264 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: idempotent-runs
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Idempotent Runs
+ */
+
+package engine
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// IdempotencyMetadata is the trigger metadata key of an idempotency key.
+// Triggers carrying one run at most once per pipeline, trigger type and key
+// within IdempotencyTTL.
+const IdempotencyMetadata = "idempotency_key"
+
+// CorrelationMetadata is the trigger metadata key of the correlation ID of
+// the HTTP request that triggered a run, and TraceparentMetadata of its W3C
+// trace context. Runs carrying a correlation ID log and audit it, so a
+// request can be followed from its response into the run.
+const (
+	CorrelationMetadata = "correlation_id"
+	TraceparentMetadata = "traceparent"
+)
+
+// AuditTriggered is the audit decision recorded when an API request
+// triggers a run
+const AuditTriggered = "triggered"
+
+// IdempotencyTTL is how long an idempotency key keeps returning its run
+const IdempotencyTTL = 24 * time.Hour
+
+// idempotencyPrefix is where the runs of idempotent triggers are stored
+const idempotencyPrefix = "idempotency"
+
+// IdempotentCall is a trigger run under an idempotency key: in flight, or
+// finished with the run the idempotency store recorded
+type IdempotentCall struct {
+	// RunID is the ID the run is recorded under
+	RunID string
+	done  chan struct{}
+	run   *Run
+	err   error
+}
+
+// Done is closed once the call finished
+func (c *IdempotentCall) Done() <-chan struct{} {
+	return c.done
+}
+
+// Result returns the run of a finished call, or the error of a trigger
+// that started none
+func (c *IdempotentCall) Result() (*Run, error) {
+	return c.run, c.err
+}
+
+// idempotentRun is the stored run of an idempotent trigger
+type idempotentRun struct {
+	Run       *Run      `json:"run"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// RunID returns the run ID of a pipeline's trigger from source, such as
+// manual or webhook, under an idempotency key. The same three always give
+// the same ID.
+func RunID(pipelineID, source, key string) string {
+	sum := sha256.Sum256([]byte(pipelineID + "\x00" + source + "\x00" + key))
+	return fmt.Sprintf("%s-%s", pipelineID, hex.EncodeToString(sum[:8]))
+}
+
+// runID returns the ID of a run started by trigger: derived from its
+// idempotency key, or unique otherwise
+func runID(pipelineID string, trigger Trigger) string {
+	if key := trigger.Metadata[IdempotencyMetadata]; key != "" {
+		return RunID(pipelineID, trigger.Type, key)
+	}
+	return fmt.Sprintf("%s-%d", pipelineID, time.Now().UnixNano())
+}
+
+// runIdempotent runs a trigger carrying an idempotency key once. Retries
+// while the run is in flight wait for it, and later retries get the stored
+// run back, across restarts. Triggers that started no run and runs cut
+// short by the end of ctx are not remembered, so their retries try again.
+func (e *Engine) runIdempotent(ctx context.Context, p *registry.Pipeline, trigger Trigger, start func() (*Run, error)) (*Run, error) {
+	call, owner, err := e.idempotentCall(p, trigger)
+	if err != nil {
+		return nil, err
+	}
+	if owner {
+		e.finishIdempotent(ctx, p, call, start)
+		return call.Result()
+	}
+
+	select {
+	case <-call.done:
+		return call.Result()
+	default:
+	}
+	e.tracef(p.ID, "%s trigger waits for run %s in flight", trigger.Type, call.RunID)
+	select {
+	case <-call.done:
+		return call.Result()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// TriggerIdempotent starts a trigger carrying an idempotency key without
+// waiting for its run, which runs under ctx. A trigger whose key already
+// has a run in flight or recorded returns that call instead of starting
+// another.
+func (e *Engine) TriggerIdempotent(ctx context.Context, pipelineID string, trigger Trigger) (*IdempotentCall, error) {
+	if trigger.Metadata[IdempotencyMetadata] == "" {
+		return nil, fmt.Errorf("trigger of %s carries no idempotency key", pipelineID)
+	}
+	p, err := e.registry.GetByID(pipelineID)
+	if err != nil {
+		return nil, err
+	}
+
+	call, owner, err := e.idempotentCall(p, trigger)
+	if err != nil {
+		return nil, err
+	}
+	if owner {
+		go e.finishIdempotent(ctx, p, call, func() (*Run, error) { return e.start(ctx, p, trigger) })
+	}
+	return call, nil
+}
+
+// IdempotentTrigger returns the call of a pipeline's trigger from source
+// under an idempotency key: the one in flight or the run recorded within
+// IdempotencyTTL, or nil when there is neither
+func (e *Engine) IdempotentTrigger(pipelineID, source, key string) (*IdempotentCall, error) {
+	id := RunID(pipelineID, source, key)
+
+	e.idempotentMu.Lock()
+	defer e.idempotentMu.Unlock()
+	if call, inFlight := e.idempotent[id]; inFlight {
+		return call, nil
+	}
+	run, err := e.IdempotentRun(pipelineID, source, key)
+	if err != nil || run == nil {
+		return nil, err
+	}
+	return recordedCall(run), nil
+}
+
+// idempotentCall returns the call of a trigger in flight or recorded under
+// its key, or registers a new one the caller owns and must finish
+func (e *Engine) idempotentCall(p *registry.Pipeline, trigger Trigger) (*IdempotentCall, bool, error) {
+	id := runID(p.ID, trigger)
+
+	e.idempotentMu.Lock()
+	defer e.idempotentMu.Unlock()
+	if call, inFlight := e.idempotent[id]; inFlight {
+		return call, false, nil
+	}
+	run, err := e.IdempotentRun(p.ID, trigger.Type, trigger.Metadata[IdempotencyMetadata])
+	if err != nil {
+		return nil, false, err
+	}
+	if run != nil {
+		e.tracef(p.ID, "%s trigger repeats run %s", trigger.Type, run.ID)
+		return recordedCall(run), false, nil
+	}
+	call := &IdempotentCall{RunID: id, done: make(chan struct{})}
+	e.idempotent[id] = call
+	return call, true, nil
+}
+
+// finishIdempotent runs an owned call under ctx and records its run. A run
+// cut short because ctx ended is not recorded, so retries run again rather
+// than get the interrupted run back.
+func (e *Engine) finishIdempotent(ctx context.Context, p *registry.Pipeline, call *IdempotentCall, start func() (*Run, error)) {
+	call.run, call.err = start()
+	interrupted := ctx.Err() != nil && errors.Is(call.err, ctx.Err())
+	if call.run != nil && !interrupted {
+		stored := idempotentRun{Run: snapshot(call.run), ExpiresAt: time.Now().Add(IdempotencyTTL).UTC()}
+		if err := e.store.Save(idempotencyPrefix+"/"+call.RunID, stored); err != nil {
+			e.tracef(p.ID, "failed to store idempotent run %s: %v", call.RunID, err)
+		}
+	}
+	e.idempotentMu.Lock()
+	delete(e.idempotent, call.RunID)
+	e.idempotentMu.Unlock()
+	close(call.done)
+}
+
+// recordedCall returns the finished call of a recorded run
+func recordedCall(run *Run) *IdempotentCall {
+	call := &IdempotentCall{RunID: run.ID, done: make(chan struct{}), run: run, err: runError(run)}
+	close(call.done)
+	return call
+}
+
+// IdempotentRun returns the stored run of a pipeline's trigger from source
+// under an idempotency key, or nil when none ran within IdempotencyTTL
+func (e *Engine) IdempotentRun(pipelineID, source, key string) (*Run, error) {
+	var stored idempotentRun
+	found, err := e.store.Load(idempotencyPrefix+"/"+RunID(pipelineID, source, key), &stored)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load idempotent run: %w", err)
+	}
+	if !found || stored.Run == nil || time.Now().After(stored.ExpiresAt) {
+		return nil, nil
+	}
+	return stored.Run, nil
+}
+
+// runError returns the error a finished run failed with
+func runError(run *Run) error {
+	if run.Status == StatusFailed {
+		return errors.New(run.Error)
+	}
+	return nil
+}
+
+// pruneIdempotency removes the stored runs of idempotency keys past
+// IdempotencyTTL
+func (e *Engine) pruneIdempotency(now time.Time) (int, int64, error) {
+	keys, err := e.store.Keys(idempotencyPrefix)
+	if err != nil {
+		return 0, 0, err
+	}
+	items, reclaimed := 0, int64(0)
+	for _, key := range keys {
+		var stored idempotentRun
+		if _, err := e.store.Load(key, &stored); err != nil {
+			return items, reclaimed, err
+		}
+		if !now.After(stored.ExpiresAt) {
+			continue
+		}
+		size, err := e.store.Size(key)
+		if err == nil {
+			err = e.store.Delete(key)
+		}
+		if err != nil {
+			return items, reclaimed, err
+		}
+		items++
+		reclaimed += size
+	}
+	return items, reclaimed, nil
+}
@@ -0,0 +1,75 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: idempotent-trigger-tests
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Idempotent Trigger Tests
+ */
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// TestIdempotentReplayAfterFailure retries a failed keyed trigger: the
+// retry must get the failed run back rather than run again
+func TestIdempotentReplayAfterFailure(t *testing.T) {
+	source := newTestSource()
+	source.err = errors.New("source unavailable")
+	source.release()
+	e := newTestEngine(t, registry.RunPolicyCoalesce, source)
+	trigger := Trigger{Type: TriggerManual, Metadata: map[string]string{IdempotencyMetadata: "nightly"}}
+
+	first, err := e.RunOnce(context.Background(), "orders", trigger)
+	if err == nil || first == nil || first.Status != StatusFailed {
+		t.Fatalf("first run returned %+v, %v; want a failed run", first, err)
+	}
+	retry, err := e.RunOnce(context.Background(), "orders", trigger)
+	if err == nil {
+		t.Error("retry of a failed run returned no error")
+	}
+	if retry == nil || retry.ID != first.ID || retry.Status != StatusFailed {
+		t.Errorf("retry returned %+v, want failed run %s", retry, first.ID)
+	}
+	if runs := e.Runs("orders"); len(runs) != 1 {
+		t.Errorf("pipeline ran %d times, want once", len(runs))
+	}
+}
+
+// TestIdempotentInterruptedRunNotRecorded cancels a keyed trigger mid-run:
+// its retry must run again rather than get the cancelled run back
+func TestIdempotentInterruptedRunNotRecorded(t *testing.T) {
+	source := newTestSource()
+	e := newTestEngine(t, registry.RunPolicyCoalesce, source)
+	metadata := map[string]string{IdempotencyMetadata: "nightly"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := runAsync(e, ctx, metadata)
+	<-source.started
+	cancel()
+	if r := await(t, first); !errors.Is(r.err, context.Canceled) {
+		t.Fatalf("cancelled run returned %v, want context.Canceled", r.err)
+	}
+
+	source.release()
+	retry := await(t, runAsync(e, context.Background(), metadata))
+	if retry.err != nil || retry.run.Status != StatusSucceeded {
+		t.Errorf("retry returned %+v, %v; want a new successful run", retry.run, retry.err)
+	}
+	if runs := e.Runs("orders"); len(runs) != 2 {
+		t.Errorf("pipeline ran %d times, want twice", len(runs))
+	}
+}
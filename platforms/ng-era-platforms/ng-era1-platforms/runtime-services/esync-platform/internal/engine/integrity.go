@@ -0,0 +1,151 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: referential-integrity-ordering
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Referential-Integrity Aware Apply Ordering
+ */
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// tableSegments splits records into segments applied one after another:
+// inserts and updates table by table, parents first, then deletes table by
+// table, children first. Tables missing from the order are applied after
+// known tables and deleted before them. Without an apply order all records
+// form one segment.
+func tableSegments(ctx context.Context, p *registry.Pipeline, target connectors.Connector, records []connectors.Record) ([][]connectors.Record, error) {
+	if p.ApplyOrder == nil || len(records) == 0 {
+		return [][]connectors.Record{records}, nil
+	}
+
+	order, err := tableOrder(ctx, p, target)
+	if err != nil {
+		return nil, err
+	}
+	rank := make(map[string]int, len(order))
+	for i, table := range order {
+		rank[table] = i
+	}
+	unknown := len(order)
+
+	upserts := make([][]connectors.Record, len(order)+1)
+	deletes := make([][]connectors.Record, len(order)+1)
+	for _, r := range records {
+		i, known := rank[r.Table]
+		if !known {
+			i = unknown
+		}
+		if r.Operation == connectors.OperationDelete {
+			deletes[i] = append(deletes[i], r)
+		} else {
+			upserts[i] = append(upserts[i], r)
+		}
+	}
+
+	var segments [][]connectors.Record
+	for _, segment := range upserts {
+		if len(segment) > 0 {
+			segments = append(segments, segment)
+		}
+	}
+	for i := len(deletes) - 1; i >= 0; i-- {
+		if len(deletes[i]) > 0 {
+			segments = append(segments, deletes[i])
+		}
+	}
+	return segments, nil
+}
+
+// tableOrder returns the pipeline's tables parents first, either as declared
+// or inferred from the target's foreign keys
+func tableOrder(ctx context.Context, p *registry.Pipeline, target connectors.Connector) ([]string, error) {
+	if len(p.ApplyOrder.Tables) > 0 || !p.ApplyOrder.Infer {
+		return p.ApplyOrder.Tables, nil
+	}
+
+	referencer, ok := target.(connectors.TableReferencer)
+	if !ok {
+		return nil, fmt.Errorf("apply_order.infer requires a target that reports its foreign keys")
+	}
+	refs, err := referencer.TableReferences(ctx)
+	if errors.Is(err, connectors.ErrUnsupported) {
+		return nil, fmt.Errorf("apply_order.infer requires a target that reports its foreign keys")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read table references: %w", err)
+	}
+
+	order, cyclic := sortTables(refs)
+	if len(cyclic) > 0 {
+		log.Printf("[Engine] Pipeline %s: tables %v reference each other; applying them in name order", p.ID, cyclic)
+	}
+	return order, nil
+}
+
+// sortTables orders tables so referenced tables come first, breaking ties by
+// name. Tables on reference cycles are appended in name order and returned
+// separately.
+func sortTables(refs map[string][]string) (order, cyclic []string) {
+	pending := make(map[string]int)
+	children := make(map[string][]string)
+	for table, parents := range refs {
+		if _, exists := pending[table]; !exists {
+			pending[table] = 0
+		}
+		for _, parent := range parents {
+			if parent == table {
+				continue
+			}
+			if _, exists := pending[parent]; !exists {
+				pending[parent] = 0
+			}
+			pending[table]++
+			children[parent] = append(children[parent], table)
+		}
+	}
+
+	var ready []string
+	for table, n := range pending {
+		if n == 0 {
+			ready = append(ready, table)
+		}
+	}
+	for len(ready) > 0 {
+		sort.Strings(ready)
+		table := ready[0]
+		ready = ready[1:]
+		order = append(order, table)
+		delete(pending, table)
+		for _, child := range children[table] {
+			pending[child]--
+			if pending[child] == 0 {
+				ready = append(ready, child)
+			}
+		}
+	}
+
+	for table := range pending {
+		cyclic = append(cyclic, table)
+	}
+	sort.Strings(cyclic)
+	return append(order, cyclic...), cyclic
+}
@@ -0,0 +1,190 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: key-digests
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Key Set Diff by Digest Exchange
+ */
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+)
+
+// digestLeafKeys is how many keys a differing hash range may hold on
+// either side before its sub-ranges are compared instead of its keys
+// listed
+const digestLeafKeys = 1024
+
+// maxDigestDepth bounds the hex digits of hash prefixes; ranges that still
+// differ at this depth are listed whatever their size
+const maxDigestDepth = 16
+
+// digestBatch bounds the prefixes requested in one key_digests call
+const digestBatch = 4096
+
+// hexDigits are the children of a hash prefix
+const hexDigits = "0123456789abcdef"
+
+// keyDiff is the difference of two key sets found by digest exchange
+type keyDiff struct {
+	SourceKeys int64
+	TargetKeys int64
+	// Missing are source keys the target lacks, Extra target keys the
+	// source lacks
+	Missing []connectors.Key
+	Extra   []connectors.Key
+	// Ranges counts the hash ranges compared and Listed the keys
+	// transferred to compare the ranges that differ
+	Ranges int
+	Listed int
+}
+
+// digesters returns the source and target as key digesters when both are
+func digesters(source, target connectors.Connector) (connectors.KeyDigester, connectors.KeyDigester, bool) {
+	s, ok := source.(connectors.KeyDigester)
+	if !ok {
+		return nil, nil, false
+	}
+	t, ok := target.(connectors.KeyDigester)
+	return s, t, ok
+}
+
+// diffKeys compares the live key sets of two connectors as a hash tree:
+// starting from the whole hash space, ranges whose digests match are
+// skipped and ranges that differ are split into their 16 sub-ranges until
+// they are small enough to list. Key sets differing in few keys are thus
+// compared in a few round trips of digests. It fails with
+// connectors.ErrUnsupported when either connector cannot digest its keys.
+func diffKeys(ctx context.Context, source, target connectors.KeyDigester) (*keyDiff, error) {
+	diff := &keyDiff{}
+	frontier := []string{""}
+	for len(frontier) > 0 {
+		sourceDigests, err := keyDigests(ctx, source, frontier)
+		if err != nil {
+			return nil, fmt.Errorf("failed to digest source keys: %w", err)
+		}
+		targetDigests, err := keyDigests(ctx, target, frontier)
+		if err != nil {
+			return nil, fmt.Errorf("failed to digest target keys: %w", err)
+		}
+		diff.Ranges += len(frontier)
+
+		var next []string
+		for i, prefix := range frontier {
+			s, t := sourceDigests[i], targetDigests[i]
+			if prefix == "" {
+				diff.SourceKeys, diff.TargetKeys = s.Count, t.Count
+			}
+			if s.Count == t.Count && s.Digest == t.Digest {
+				continue
+			}
+			if (s.Count <= digestLeafKeys && t.Count <= digestLeafKeys) || len(prefix) >= maxDigestDepth || s.Count == 0 || t.Count == 0 {
+				if err := diff.listRange(ctx, source, target, prefix, s.Count, t.Count); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			for _, digit := range hexDigits {
+				next = append(next, prefix+string(digit))
+			}
+		}
+		frontier = next
+	}
+
+	sortKeys(diff.Missing)
+	sortKeys(diff.Extra)
+	return diff, nil
+}
+
+// keyDigests requests the digests of prefixes in batches, checking the
+// connector answered each one
+func keyDigests(ctx context.Context, c connectors.KeyDigester, prefixes []string) ([]connectors.KeyDigest, error) {
+	out := make([]connectors.KeyDigest, 0, len(prefixes))
+	for start := 0; start < len(prefixes); start += digestBatch {
+		end := start + digestBatch
+		if end > len(prefixes) {
+			end = len(prefixes)
+		}
+		digests, err := c.KeyDigests(ctx, prefixes[start:end])
+		if err != nil {
+			return nil, err
+		}
+		if len(digests) != end-start {
+			return nil, fmt.Errorf("connector returned %d digests for %d prefixes", len(digests), end-start)
+		}
+		out = append(out, digests...)
+	}
+	return out, nil
+}
+
+// listRange lists the keys of a differing hash range on the sides that
+// hold any and records the keys only one side holds
+func (d *keyDiff) listRange(ctx context.Context, source, target connectors.KeyDigester, prefix string, sourceCount, targetCount int64) error {
+	var sourceKeys, targetKeys []connectors.Key
+	var err error
+	if sourceCount > 0 {
+		if sourceKeys, err = source.HashKeys(ctx, prefix); err != nil {
+			return fmt.Errorf("failed to list source keys: %w", err)
+		}
+	}
+	if targetCount > 0 {
+		if targetKeys, err = target.HashKeys(ctx, prefix); err != nil {
+			return fmt.Errorf("failed to list target keys: %w", err)
+		}
+	}
+	d.Listed += len(sourceKeys) + len(targetKeys)
+
+	inSource := make(map[connectors.Key]bool, len(sourceKeys))
+	for _, k := range sourceKeys {
+		inSource[k] = true
+	}
+	inTarget := make(map[connectors.Key]bool, len(targetKeys))
+	for _, k := range targetKeys {
+		inTarget[k] = true
+		if !inSource[k] {
+			d.Extra = append(d.Extra, k)
+		}
+	}
+	for _, k := range sourceKeys {
+		if !inTarget[k] {
+			d.Missing = append(d.Missing, k)
+		}
+	}
+	return nil
+}
+
+// sortKeys orders keys by table and ID
+func sortKeys(keys []connectors.Key) {
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Table != keys[j].Table {
+			return keys[i].Table < keys[j].Table
+		}
+		return keys[i].ID < keys[j].ID
+	})
+}
+
+// keySample renders up to maxReportedKeys keys for reports
+func keySample(keys []connectors.Key) []string {
+	if len(keys) > maxReportedKeys {
+		keys = keys[:maxReportedKeys]
+	}
+	var out []string
+	for _, k := range keys {
+		out = append(out, k.String())
+	}
+	return out
+}
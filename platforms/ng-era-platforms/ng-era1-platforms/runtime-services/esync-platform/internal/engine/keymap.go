@@ -0,0 +1,249 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: key-mapping
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Source to Target Key Mapping
+ */
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/idgen"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// keyMap is the persisted mapping of a pipeline from source keys to the
+// IDs of the target records written for them
+type keyMap struct {
+	mu         sync.Mutex
+	pipelineID string
+	// ids maps table to source ID to target ID
+	ids map[string]map[string]string
+}
+
+// keyMap returns the key mapping of a pipeline, loading it on first use
+func (e *Engine) keyMap(pipelineID string) (*keyMap, error) {
+	e.keyMapsMu.Lock()
+	defer e.keyMapsMu.Unlock()
+
+	if m, exists := e.keyMaps[pipelineID]; exists {
+		return m, nil
+	}
+	m := &keyMap{pipelineID: pipelineID}
+	if _, err := e.store.Load("keymaps/"+pipelineID, &m.ids); err != nil {
+		return nil, err
+	}
+	if m.ids == nil {
+		m.ids = make(map[string]map[string]string)
+	}
+	e.keyMaps[pipelineID] = m
+	return m, nil
+}
+
+// get returns the target ID of a source key
+func (m *keyMap) get(k connectors.Key) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id, exists := m.ids[k.Table][k.ID]
+	return id, exists
+}
+
+// set records the target ID of a source key
+func (m *keyMap) set(k connectors.Key, id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.ids[k.Table] == nil {
+		m.ids[k.Table] = make(map[string]string)
+	}
+	m.ids[k.Table][k.ID] = id
+}
+
+// forget drops the mapping of a deleted record
+func (m *keyMap) forget(k connectors.Key) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.ids[k.Table], k.ID)
+	if len(m.ids[k.Table]) == 0 {
+		delete(m.ids, k.Table)
+	}
+}
+
+// save persists the mapping
+func (m *keyMap) save(e *Engine) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := e.store.Save("keymaps/"+m.pipelineID, m.ids); err != nil {
+		return fmt.Errorf("failed to save key mapping: %w", err)
+	}
+	return nil
+}
+
+// keyMapper writes batches of a key-mapped pipeline under target IDs
+type keyMapper struct {
+	e    *Engine
+	keys *keyMap
+	// generator creates the IDs of new records; nil when the target
+	// assigns them
+	generator idgen.Generator
+}
+
+// keyMapper returns the mapper of a pipeline, or nil when the pipeline
+// writes records under their source IDs
+func (e *Engine) keyMapper(p *registry.Pipeline) (*keyMapper, error) {
+	if p.KeyMapping == nil {
+		return nil, nil
+	}
+	keys, err := e.keyMap(p.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &keyMapper{e: e, keys: keys}
+	name := p.KeyMapping.Generator
+	if name == "" {
+		name = registry.DefaultIDGenerator
+	}
+	if name != registry.IDGeneratorTarget {
+		if m.generator, err = idgen.New(name, p.KeyMapping.NodeID); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// apply writes a batch under the target IDs of its records. New records
+// get a generated ID or the ID the target assigns. Deletes and patches of
+// records never written are dropped. Records of a key whose target ID is
+// still to be assigned wait for the write of the preceding ones.
+func (m *keyMapper) apply(ctx context.Context, target connectors.Connector, batch []connectors.Record) error {
+	var (
+		mapped    []connectors.Record
+		keys      []connectors.Key
+		generated []connectors.Key
+		pending   = make(map[connectors.Key]bool)
+	)
+	for _, r := range batch {
+		k := connectors.Key{Table: r.Table, ID: r.ID}
+		if pending[k] {
+			if err := m.flush(ctx, target, mapped, keys, generated, pending); err != nil {
+				return err
+			}
+			mapped, keys, generated, pending = nil, nil, nil, make(map[connectors.Key]bool)
+		}
+
+		id, known := m.keys.get(k)
+		switch {
+		case known:
+		case r.Operation == connectors.OperationDelete, r.Operation == connectors.OperationPatch:
+			continue
+		case m.generator == nil:
+			pending[k] = true
+		default:
+			var err error
+			if id, err = m.generator.Next(); err != nil {
+				return fmt.Errorf("failed to generate target ID: %w", err)
+			}
+			m.keys.set(k, id)
+			generated = append(generated, k)
+		}
+		r.ID = id
+		mapped = append(mapped, r)
+		keys = append(keys, k)
+	}
+	return m.flush(ctx, target, mapped, keys, generated, pending)
+}
+
+// flush writes mapped records, recording the IDs the target assigned to
+// pending keys and forgetting deleted ones, and saves the mapping.
+// Generated IDs are saved before the write: once the target may hold a
+// record under an ID, a crash or failed save must not lose it, or the
+// retried batch would write the record again under a new one. They are
+// forgotten again when the target rejects the write without writing any
+// of it.
+func (m *keyMapper) flush(ctx context.Context, target connectors.Connector, mapped []connectors.Record, keys, generated []connectors.Key, pending map[connectors.Key]bool) error {
+	if len(mapped) == 0 {
+		return nil
+	}
+	if len(generated) > 0 {
+		if err := m.keys.save(m.e); err != nil {
+			for _, k := range generated {
+				m.keys.forget(k)
+			}
+			return err
+		}
+	}
+
+	var err error
+	if len(pending) == 0 {
+		err = target.ApplyChanges(ctx, mapped)
+	} else {
+		err = m.assign(ctx, target, mapped, keys, pending)
+	}
+	switch {
+	case err == nil:
+		for i, r := range mapped {
+			if r.Operation == connectors.OperationDelete {
+				m.keys.forget(keys[i])
+			}
+		}
+	case errors.Is(err, connectors.ErrRejected):
+		for _, k := range generated {
+			m.keys.forget(k)
+		}
+	case len(pending) == 0:
+		// The mapping is unchanged since it was saved
+		return err
+	}
+	if saveErr := m.keys.save(m.e); saveErr != nil && err == nil {
+		err = saveErr
+	}
+	return err
+}
+
+// assign writes records through a target assigning the IDs of new ones
+func (m *keyMapper) assign(ctx context.Context, target connectors.Connector, mapped []connectors.Record, keys []connectors.Key, pending map[connectors.Key]bool) error {
+	assigner, ok := target.(connectors.IDAssigner)
+	if !ok {
+		return fmt.Errorf("target cannot assign record IDs")
+	}
+
+	ids, err := assigner.ApplyReturningIDs(ctx, mapped)
+	if errors.Is(err, connectors.ErrUnsupported) {
+		return fmt.Errorf("target cannot assign record IDs")
+	}
+	if err != nil {
+		return err
+	}
+	if len(ids) != len(mapped) {
+		return fmt.Errorf("target returned %d IDs for %d records", len(ids), len(mapped))
+	}
+	for i, k := range keys {
+		if !pending[k] {
+			continue
+		}
+		if ids[i] == "" {
+			return fmt.Errorf("target assigned no ID to record %s", k)
+		}
+		m.keys.set(k, ids[i])
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: composite-keys
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Composite Key Normalization
+ */
+
+package engine
+
+import (
+	"fmt"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// normalizeKeys gives records the key fields the pipeline's primary_key
+// block names when the source reports none, and sets the ID of records
+// with key fields to the encoding of their values, so dedup, ordering, key
+// mapping and targets all see one key per record. Records without an ID
+// whose key cannot be read from their data are dropped as errors; others,
+// such as deletes carrying only their ID, keep it.
+func (e *Engine) normalizeKeys(p *registry.Pipeline, records []connectors.Record) []connectors.Record {
+	if p.PrimaryKey == nil && !hasKeyFields(records) {
+		return records
+	}
+
+	out := make([]connectors.Record, 0, len(records))
+	for _, r := range records {
+		if len(r.KeyFields) == 0 {
+			r.KeyFields = p.PrimaryKey.KeyFields(r.Table)
+		}
+		if len(r.KeyFields) > 0 {
+			id, err := r.CompositeID()
+			switch {
+			case err == nil:
+				r.ID = id
+			case r.ID == "":
+				e.recordError(p.ID, "key", fmt.Errorf("record of table %q: %w", r.Table, err))
+				continue
+			}
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// hasKeyFields reports whether any record names its key fields
+func hasKeyFields(records []connectors.Record) bool {
+	for _, r := range records {
+		if len(r.KeyFields) > 0 {
+			return true
+		}
+	}
+	return false
+}
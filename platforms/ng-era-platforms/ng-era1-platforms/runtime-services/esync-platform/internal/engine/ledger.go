@@ -0,0 +1,233 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: run-ledger
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Run Ledger Export
+ */
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// Defaults of the run ledger export
+const (
+	defaultLedgerBatchSize     = 100
+	defaultLedgerFlushInterval = 30 * time.Second
+	// ledgerQueueSize bounds the runs held while the target is unreachable;
+	// the oldest are dropped beyond it
+	ledgerQueueSize = 10000
+)
+
+// LedgerConfig is a run ledger file exporting every finished run as a
+// record to a target connector, so sync observability lands next to the
+// synced data:
+//
+//	target:
+//	  type: postgres
+//	  config:
+//	    dsn: ${secret:WAREHOUSE_DSN}
+//	table: esync_runs
+//	batch_size: 100
+//	flush_interval: 30s
+//
+// The target is configured like a pipeline target, including connection
+// profiles and secret references. Records are keyed by run ID.
+type LedgerConfig struct {
+	Target registry.ConnectorSpec `yaml:"target"`
+	// Table sets the table of the records for multi-table targets
+	Table string `yaml:"table,omitempty"`
+	// BatchSize caps the records applied at once
+	BatchSize int `yaml:"batch_size,omitempty"`
+	// FlushInterval is the time between applies
+	FlushInterval time.Duration `yaml:"flush_interval,omitempty"`
+}
+
+// LoadLedger reads a run ledger file
+func LoadLedger(path string) (*LedgerConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run ledger config: %w", err)
+	}
+
+	var config LedgerConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse run ledger config %s: %w", path, err)
+	}
+	if config.Target.Type == "" && config.Target.Connection == "" {
+		return nil, fmt.Errorf("invalid run ledger config %s: target type or connection is required", path)
+	}
+	if config.BatchSize < 0 || config.FlushInterval < 0 {
+		return nil, fmt.Errorf("invalid run ledger config %s: batch_size and flush_interval must not be negative", path)
+	}
+	return &config, nil
+}
+
+// ledger holds the run records waiting to be exported
+type ledger struct {
+	mu      sync.Mutex
+	pending []connectors.Record
+	dropped int
+}
+
+// add queues a record, dropping the oldest beyond the queue size
+func (l *ledger) add(r connectors.Record) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.pending) == ledgerQueueSize {
+		l.pending = l.pending[1:]
+		l.dropped++
+	}
+	l.pending = append(l.pending, r)
+}
+
+// ExportRuns exports every run finished from now on to the ledger target
+// in the background until ctx is done, flushing what is left on the way
+// out. Runs that cannot be exported are retried at the next flush.
+func (e *Engine) ExportRuns(ctx context.Context, config *LedgerConfig) {
+	batchSize := config.BatchSize
+	if batchSize == 0 {
+		batchSize = defaultLedgerBatchSize
+	}
+	interval := config.FlushInterval
+	if interval == 0 {
+		interval = defaultLedgerFlushInterval
+	}
+
+	l := &ledger{}
+	e.OnRunComplete(func(run *Run) {
+		l.add(e.ledgerRecord(run, config.Table))
+	})
+
+	log.Printf("[Engine] Exporting the run ledger to a %s target every %s", ledgerTargetName(config.Target), interval)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				flushCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+				e.flushLedger(flushCtx, l, config.Target, batchSize)
+				cancel()
+				return
+			case <-ticker.C:
+				e.flushLedger(ctx, l, config.Target, batchSize)
+			}
+		}
+	}()
+}
+
+// flushLedger applies the queued records in batches, keeping those of a
+// failed batch for the next flush
+func (e *Engine) flushLedger(ctx context.Context, l *ledger, target registry.ConnectorSpec, batchSize int) {
+	l.mu.Lock()
+	pending, dropped := l.pending, l.dropped
+	l.pending, l.dropped = nil, 0
+	l.mu.Unlock()
+
+	if dropped > 0 {
+		log.Printf("[Engine] Dropped %d run ledger records while the ledger target was unreachable", dropped)
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	conn, err := e.connector(target)
+	if err == nil {
+		for len(pending) > 0 {
+			n := batchSize
+			if n > len(pending) {
+				n = len(pending)
+			}
+			if err = conn.ApplyChanges(ctx, pending[:n]); err != nil {
+				break
+			}
+			pending = pending[n:]
+		}
+	}
+	if err == nil {
+		return
+	}
+
+	log.Printf("[Engine] Failed to export %d run ledger records, retrying at the next flush: %v", len(pending), err)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.pending = append(pending, l.pending...)
+	if excess := len(l.pending) - ledgerQueueSize; excess > 0 {
+		l.pending = l.pending[excess:]
+		l.dropped += excess
+	}
+}
+
+// ledgerRecord is the ledger record of a finished run
+func (e *Engine) ledgerRecord(run *Run, table string) connectors.Record {
+	data := map[string]interface{}{
+		"run_id":           run.ID,
+		"pipeline_id":      run.PipelineID,
+		"trigger":          run.Trigger.Type,
+		"status":           run.Status,
+		"records":          run.Records,
+		"listed":           run.Listed,
+		"filtered":         run.Filtered,
+		"invalid":          run.Invalid,
+		"started_at":       run.StartedAt.UTC().Format(time.RFC3339Nano),
+		"finished_at":      run.FinishedAt.UTC().Format(time.RFC3339Nano),
+		"duration_seconds": run.FinishedAt.Sub(run.StartedAt).Seconds(),
+		"error":            run.Error,
+	}
+	errorCount := 0
+	for _, g := range run.Errors {
+		errorCount += g.Count
+	}
+	data["error_count"] = errorCount
+	if run.Checkpoint != nil {
+		data["checkpoint_from"] = run.Checkpoint.From
+		data["checkpoint_to"] = run.Checkpoint.To
+	}
+	if run.Verification != nil {
+		data["verification_score"] = run.Verification.Score
+	}
+	if p, err := e.registry.GetByID(run.PipelineID); err == nil {
+		data["pipeline_version"] = p.Version
+		data["environment"] = p.Environment
+		data["namespace"] = p.Namespace
+		data["owner"] = p.Owner
+	}
+
+	return connectors.Record{
+		ID:        run.ID,
+		Operation: connectors.OperationInsert,
+		Data:      data,
+		Timestamp: run.FinishedAt,
+		Table:     table,
+	}
+}
+
+// ledgerTargetName names the ledger target in logs without its config
+func ledgerTargetName(spec registry.ConnectorSpec) string {
+	if spec.Connection != "" {
+		return spec.Connection
+	}
+	return spec.Type
+}
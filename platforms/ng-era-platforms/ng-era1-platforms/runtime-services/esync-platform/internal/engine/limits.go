@@ -0,0 +1,187 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: connector-call-limits
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Connector Call Limits
+ */
+
+package engine
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// callLimiter is a token bucket bounding the call rate together with a cap
+// on the calls in flight. A zero rate or concurrency leaves that bound off.
+type callLimiter struct {
+	mu       sync.Mutex
+	limits   registry.CallLimits
+	tokens   float64
+	last     time.Time
+	inflight int
+	// wake is closed and replaced whenever a call ends or the limits change
+	wake chan struct{}
+}
+
+// newCallLimiter creates a limiter with a full bucket
+func newCallLimiter(limits registry.CallLimits) *callLimiter {
+	return &callLimiter{
+		limits: limits,
+		tokens: float64(burst(limits)),
+		last:   time.Now(),
+		wake:   make(chan struct{}),
+	}
+}
+
+// Acquire implements connectors.Limiter
+func (l *callLimiter) Acquire(ctx context.Context) (func(), error) {
+	for {
+		l.mu.Lock()
+		wait := l.reserve(time.Now())
+		if wait == 0 {
+			l.mu.Unlock()
+			var once sync.Once
+			return func() { once.Do(l.release) }, nil
+		}
+		wake := l.wake
+		l.mu.Unlock()
+
+		var timer *time.Timer
+		var expired <-chan time.Time
+		if wait > 0 {
+			timer = time.NewTimer(wait)
+			expired = timer.C
+		}
+		select {
+		case <-ctx.Done():
+		case <-wake:
+		case <-expired:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// reserve takes a token and a concurrency slot when both are available and
+// returns 0; otherwise it returns how long to wait for a token, or -1 to
+// wait for a call to end. The caller holds l.mu.
+func (l *callLimiter) reserve(now time.Time) time.Duration {
+	if l.limits.MaxConcurrent > 0 && l.inflight >= l.limits.MaxConcurrent {
+		return -1
+	}
+	if rate := l.limits.RatePerSecond; rate > 0 {
+		l.tokens += now.Sub(l.last).Seconds() * rate
+		if max := float64(burst(l.limits)); l.tokens > max {
+			l.tokens = max
+		}
+		l.last = now
+		if l.tokens < 1 {
+			return time.Duration((1 - l.tokens) / rate * float64(time.Second))
+		}
+		l.tokens--
+	}
+	l.inflight++
+	return 0
+}
+
+// release frees the concurrency slot of an ended call
+func (l *callLimiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.inflight--
+	l.notify()
+}
+
+// update applies changed limits without losing the calls in flight
+func (l *callLimiter) update(limits registry.CallLimits) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if limits == l.limits {
+		return
+	}
+	l.limits = limits
+	if max := float64(burst(limits)); l.tokens > max {
+		l.tokens = max
+	}
+	l.notify()
+}
+
+// notify wakes the waiting callers; the caller holds l.mu
+func (l *callLimiter) notify() {
+	close(l.wake)
+	l.wake = make(chan struct{})
+}
+
+// burst returns the bucket size of limits
+func burst(limits registry.CallLimits) int {
+	if limits.Burst > 0 {
+		return limits.Burst
+	}
+	return registry.DefaultCallBurst
+}
+
+// limiter returns the shared limiter of key, created on first use and
+// updated to the current limits on every later use
+func (e *Engine) limiter(key string, limits registry.CallLimits) *callLimiter {
+	e.limitsMu.Lock()
+	defer e.limitsMu.Unlock()
+
+	l, exists := e.limiters[key]
+	if !exists {
+		l = newCallLimiter(limits)
+		e.limiters[key] = l
+		return l
+	}
+	l.update(limits)
+	return l
+}
+
+// withCallLimits bounds the connector calls made with ctx by the call
+// limits of a pipeline, which add to the limits of its connection profiles
+func (e *Engine) withCallLimits(ctx context.Context, p *registry.Pipeline) context.Context {
+	if p.CallLimits == nil {
+		return ctx
+	}
+	return connectors.WithLimiter(ctx, e.limiter("pipeline:"+p.ID, *p.CallLimits))
+}
+
+// limit hands a connector the limiter shared by the pipelines using its
+// connection profile. Profiles without limits get an unbounded limiter, so
+// limits added later apply to connectors already open.
+func (e *Engine) limit(spec registry.ConnectorSpec, conn connectors.Connector) {
+	l, ok := conn.(connectors.Limited)
+	if !ok || spec.Connection == "" {
+		return
+	}
+	l.Limit(e.connectionLimiter(spec.Connection))
+}
+
+// connectionLimiter returns the limiter of a connection profile updated to
+// its current limits
+func (e *Engine) connectionLimiter(name string) *callLimiter {
+	var limits registry.CallLimits
+	if c, ok := e.registry.Snapshot().Connection(name); ok && c.Limits != nil {
+		limits = *c.Limits
+	}
+	return e.limiter("connection:"+name, limits)
+}
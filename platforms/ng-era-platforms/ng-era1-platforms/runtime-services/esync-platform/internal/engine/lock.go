@@ -0,0 +1,179 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: pipeline-run-lock
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Exactly-One-Run Locking
+ */
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// maxQueuedRuns bounds the number of runs waiting under the queue policy
+const maxQueuedRuns = 16
+
+// ErrRunInProgress is returned when a trigger is rejected because the
+// pipeline is already running
+var ErrRunInProgress = errors.New("run already in progress")
+
+// runLock serializes runs of one pipeline
+type runLock struct {
+	sem     chan struct{}
+	queued  int
+	pending *pendingRun
+}
+
+// pendingRun is the single follow-up run shared by coalesced triggers
+type pendingRun struct {
+	done      chan struct{}
+	coalesced int
+	run       *Run
+	err       error
+}
+
+// lockFor returns the run lock of a pipeline
+func (e *Engine) lockFor(pipelineID string) *runLock {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	lock, exists := e.locks[pipelineID]
+	if !exists {
+		lock = &runLock{sem: make(chan struct{}, 1)}
+		e.locks[pipelineID] = lock
+	}
+	return lock
+}
+
+// Running reports whether a run of the pipeline is in progress
+func (e *Engine) Running(pipelineID string) bool {
+	return len(e.lockFor(pipelineID).sem) > 0
+}
+
+// runExclusive executes a run under the pipeline's run policy so that at
+// most one run of a pipeline executes at a time
+func (e *Engine) runExclusive(ctx context.Context, p *registry.Pipeline, trigger Trigger) (*Run, error) {
+	lock := e.lockFor(p.ID)
+
+	select {
+	case lock.sem <- struct{}{}:
+		e.monitor.RecordTrigger(p.ID, "started")
+		defer func() { <-lock.sem }()
+		return e.execute(ctx, p, trigger, 0)
+	default:
+	}
+
+	switch p.RunPolicy {
+	case registry.RunPolicyReject:
+		e.monitor.RecordTrigger(p.ID, "rejected")
+		return nil, fmt.Errorf("cannot run %s: %w", p.ID, ErrRunInProgress)
+	case registry.RunPolicyQueue:
+		return e.enqueue(ctx, p, trigger, lock)
+	default:
+		return e.coalesce(ctx, p, trigger, lock)
+	}
+}
+
+// enqueue waits for the lock and runs every queued trigger in turn
+func (e *Engine) enqueue(ctx context.Context, p *registry.Pipeline, trigger Trigger, lock *runLock) (*Run, error) {
+	e.mu.Lock()
+	if lock.queued >= maxQueuedRuns {
+		e.mu.Unlock()
+		e.monitor.RecordTrigger(p.ID, "rejected")
+		return nil, fmt.Errorf("cannot run %s: run queue full: %w", p.ID, ErrRunInProgress)
+	}
+	lock.queued++
+	e.mu.Unlock()
+
+	defer func() {
+		e.mu.Lock()
+		lock.queued--
+		e.mu.Unlock()
+	}()
+
+	e.monitor.RecordTrigger(p.ID, "queued")
+	select {
+	case lock.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-lock.sem }()
+
+	// The pipeline may have been paused or handed off while queued
+	if err := e.admit(p, trigger); err != nil {
+		return nil, err
+	}
+	return e.execute(ctx, p, trigger, 0)
+}
+
+// coalesce folds the trigger into the single pending follow-up run, creating
+// it if needed, and returns that run's result. The run does not belong to
+// any one trigger, so it runs detached from the callers' contexts and each
+// caller only stops waiting for it when its own context ends.
+func (e *Engine) coalesce(ctx context.Context, p *registry.Pipeline, trigger Trigger, lock *runLock) (*Run, error) {
+	e.mu.Lock()
+	pending := lock.pending
+	if pending != nil {
+		pending.coalesced++
+		e.mu.Unlock()
+		e.monitor.RecordTrigger(p.ID, "coalesced")
+	} else {
+		pending = &pendingRun{done: make(chan struct{})}
+		lock.pending = pending
+		e.mu.Unlock()
+		e.monitor.RecordTrigger(p.ID, "queued")
+		go e.runPending(context.WithoutCancel(ctx), p, trigger, lock, pending)
+	}
+
+	select {
+	case <-pending.done:
+		return pending.run, pending.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// runPending waits for the lock and executes the pending follow-up run. It
+// gives up when the engine stops, and once it holds the lock admits the
+// trigger again: the pipeline may have been paused or handed off while it
+// waited.
+func (e *Engine) runPending(ctx context.Context, p *registry.Pipeline, trigger Trigger, lock *runLock, pending *pendingRun) {
+	defer close(pending.done)
+
+	select {
+	case lock.sem <- struct{}{}:
+	case <-e.ctx.Done():
+		e.mu.Lock()
+		lock.pending = nil
+		e.mu.Unlock()
+		pending.err = fmt.Errorf("cannot run %s: %w", p.ID, e.ctx.Err())
+		return
+	}
+	defer func() { <-lock.sem }()
+
+	// Later triggers start a new pending run once this one holds the lock
+	e.mu.Lock()
+	lock.pending = nil
+	coalesced := pending.coalesced
+	e.mu.Unlock()
+
+	if err := e.admit(p, trigger); err != nil {
+		pending.err = err
+		return
+	}
+	pending.run, pending.err = e.execute(ctx, p, trigger, coalesced)
+}
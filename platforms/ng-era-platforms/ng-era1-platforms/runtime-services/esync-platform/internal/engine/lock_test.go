@@ -0,0 +1,294 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: pipeline-run-lock-tests
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Run Lock Tests
+ */
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/monitoring"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+	"github.com/machine-native-ops/esync-platform/internal/secrets"
+	"github.com/machine-native-ops/esync-platform/internal/state"
+)
+
+// testSource is a source whose reads wait until released and then fail
+// with err, if set
+type testSource struct {
+	once     sync.Once
+	started  chan struct{}
+	released chan struct{}
+	err      error
+}
+
+// newTestSource returns a source holding its reads until release
+func newTestSource() *testSource {
+	return &testSource{started: make(chan struct{}), released: make(chan struct{})}
+}
+
+// release lets held and later reads return
+func (c *testSource) release() {
+	close(c.released)
+}
+
+func (c *testSource) ListChanges(ctx context.Context, _ *connectors.Checkpoint) ([]connectors.Record, error) {
+	c.once.Do(func() { close(c.started) })
+	select {
+	case <-c.released:
+		return nil, c.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (c *testSource) ApplyChanges(context.Context, []connectors.Record) error { return nil }
+
+func (c *testSource) Validate(context.Context, connectors.Record) connectors.ValidationResult {
+	return connectors.ValidationResult{IsValid: true}
+}
+
+func (c *testSource) ResolveConflict(_ context.Context, _ connectors.Record, newSource connectors.Record) (connectors.Record, error) {
+	return newSource, nil
+}
+
+func (c *testSource) GetLatestCheckpoint(context.Context) (*connectors.Checkpoint, error) {
+	return &connectors.Checkpoint{}, nil
+}
+
+// testSources holds the sources of the engine-test connector type by the
+// name in their config
+var testSources sync.Map
+
+func init() {
+	connectors.Register("engine-test", connectors.WithSchema(func(config map[string]interface{}) (connectors.Connector, error) {
+		if source, ok := testSources.Load(config["name"]); ok {
+			return source.(*testSource), nil
+		}
+		target := newTestSource()
+		target.release()
+		return target, nil
+	}, []byte(`{"type":"object"}`)))
+}
+
+// newTestEngine returns an engine with one pipeline, orders, reading from
+// source under a run policy
+func newTestEngine(t *testing.T, policy string, source *testSource) *Engine {
+	t.Helper()
+	testSources.Store(t.Name(), source)
+	t.Cleanup(func() { testSources.Delete(t.Name()) })
+
+	dir := t.TempDir()
+	store, err := state.NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reg := registry.NewService(t.TempDir())
+	if err := reg.Add(&registry.Pipeline{
+		ID:        "orders",
+		RunPolicy: policy,
+		Source:    registry.ConnectorSpec{Type: "engine-test", Config: map[string]interface{}{"name": t.Name()}},
+		Target:    registry.ConnectorSpec{Type: "engine-test", Config: map[string]interface{}{"name": t.Name() + "/target"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	e := New(reg, store, monitoring.NewMonitor(), secrets.NewResolver(dir))
+	t.Cleanup(e.Stop)
+	return e
+}
+
+// result is what RunOnce returned
+type result struct {
+	run *Run
+	err error
+}
+
+// runAsync triggers a manual run of orders in the background
+func runAsync(e *Engine, ctx context.Context, metadata map[string]string) <-chan result {
+	out := make(chan result, 1)
+	go func() {
+		run, err := e.RunOnce(ctx, "orders", Trigger{Type: TriggerManual, Metadata: metadata})
+		out <- result{run, err}
+	}()
+	return out
+}
+
+// await returns the result of a background run
+func await(t *testing.T, results <-chan result) result {
+	t.Helper()
+	select {
+	case r := <-results:
+		return r
+	case <-time.After(10 * time.Second):
+		t.Fatal("run did not finish")
+		return result{}
+	}
+}
+
+// waitFor polls cond until it holds
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// startHeld starts a run of orders that holds the run lock until its
+// source is released
+func startHeld(t *testing.T, e *Engine, source *testSource) <-chan result {
+	t.Helper()
+	first := runAsync(e, context.Background(), nil)
+	select {
+	case <-source.started:
+	case <-time.After(10 * time.Second):
+		t.Fatal("run did not start")
+	}
+	return first
+}
+
+// pendingRunOf returns the pending coalesced run of a pipeline, if any
+func (e *Engine) pendingRunOf(pipelineID string) *pendingRun {
+	lock := e.lockFor(pipelineID)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return lock.pending
+}
+
+// TestCoalesceFoldsTriggers checks that triggers arriving during a run
+// share one follow-up run
+func TestCoalesceFoldsTriggers(t *testing.T) {
+	source := newTestSource()
+	e := newTestEngine(t, registry.RunPolicyCoalesce, source)
+	first := startHeld(t, e, source)
+
+	second := runAsync(e, context.Background(), nil)
+	waitFor(t, "the pending run", func() bool { return e.pendingRunOf("orders") != nil })
+	third := runAsync(e, context.Background(), nil)
+	waitFor(t, "the coalesced trigger", func() bool {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		pending := e.locks["orders"].pending
+		return pending != nil && pending.coalesced == 1
+	})
+	source.release()
+
+	if r := await(t, first); r.err != nil {
+		t.Fatalf("first run failed: %v", r.err)
+	}
+	a, b := await(t, second), await(t, third)
+	if a.err != nil || b.err != nil {
+		t.Fatalf("follow-up run failed: %v, %v", a.err, b.err)
+	}
+	if a.run.ID != b.run.ID || a.run.CoalescedTriggers != 1 {
+		t.Errorf("triggers got runs %s and %s with %d coalesced, want one run with 1", a.run.ID, b.run.ID, a.run.CoalescedTriggers)
+	}
+	if runs := e.Runs("orders"); len(runs) != 2 {
+		t.Errorf("pipeline ran %d times, want 2", len(runs))
+	}
+}
+
+// TestCoalescedRunHonorsPause pauses a pipeline while a coalesced trigger
+// waits for the run lock: the follow-up run must not start
+func TestCoalescedRunHonorsPause(t *testing.T) {
+	source := newTestSource()
+	e := newTestEngine(t, registry.RunPolicyCoalesce, source)
+	first := startHeld(t, e, source)
+
+	pending := runAsync(e, context.Background(), nil)
+	waitFor(t, "the pending run", func() bool { return e.pendingRunOf("orders") != nil })
+	if err := e.Pause("orders"); err != nil {
+		t.Fatal(err)
+	}
+	source.release()
+
+	if r := await(t, first); r.err != nil {
+		t.Fatalf("run in progress failed: %v", r.err)
+	}
+	if r := await(t, pending); !errors.Is(r.err, ErrPaused) {
+		t.Errorf("coalesced trigger returned %v, want ErrPaused", r.err)
+	}
+	if runs := e.Runs("orders"); len(runs) != 1 {
+		t.Errorf("pipeline ran %d times, want once", len(runs))
+	}
+}
+
+// TestCoalescedRunStopsWithEngine stops the engine while a coalesced
+// trigger waits for the run lock
+func TestCoalescedRunStopsWithEngine(t *testing.T) {
+	source := newTestSource()
+	e := newTestEngine(t, registry.RunPolicyCoalesce, source)
+	first := startHeld(t, e, source)
+
+	pending := runAsync(e, context.Background(), nil)
+	waitFor(t, "the pending run", func() bool { return e.pendingRunOf("orders") != nil })
+	e.Stop()
+
+	if r := await(t, pending); !errors.Is(r.err, context.Canceled) {
+		t.Errorf("coalesced trigger returned %v, want context.Canceled", r.err)
+	}
+	if e.pendingRunOf("orders") != nil {
+		t.Error("stopped pending run is still registered")
+	}
+	source.release()
+	if r := await(t, first); r.err != nil {
+		t.Fatalf("run in progress failed: %v", r.err)
+	}
+}
+
+// TestQueueLimit fills the run queue behind a run in progress: one
+// trigger past maxQueuedRuns is rejected and the queued ones all run
+func TestQueueLimit(t *testing.T) {
+	source := newTestSource()
+	e := newTestEngine(t, registry.RunPolicyQueue, source)
+	first := startHeld(t, e, source)
+
+	queued := make([]<-chan result, maxQueuedRuns)
+	for i := range queued {
+		queued[i] = runAsync(e, context.Background(), map[string]string{"n": fmt.Sprint(i)})
+	}
+	waitFor(t, "a full queue", func() bool {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		return e.locks["orders"].queued == maxQueuedRuns
+	})
+
+	_, err := e.RunOnce(context.Background(), "orders", Trigger{Type: TriggerManual})
+	if !errors.Is(err, ErrRunInProgress) {
+		t.Errorf("trigger past a full queue returned %v, want ErrRunInProgress", err)
+	}
+
+	source.release()
+	if r := await(t, first); r.err != nil {
+		t.Fatalf("run in progress failed: %v", r.err)
+	}
+	for i, results := range queued {
+		if r := await(t, results); r.err != nil {
+			t.Errorf("queued run %d failed: %v", i, r.err)
+		}
+	}
+	if runs := e.Runs("orders"); len(runs) != 1+maxQueuedRuns {
+		t.Errorf("pipeline ran %d times, want %d", len(runs), 1+maxQueuedRuns)
+	}
+}
@@ -0,0 +1,94 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: connector-lookup-cache
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Connector Lookup Caches
+ */
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// LookupCacheState reports the lookup cache of one connector of a pipeline
+type LookupCacheState struct {
+	// Role is source or target
+	Role string `json:"role"`
+	Type string `json:"type"`
+	connectors.LookupCacheStats
+}
+
+// lookupCaches returns the lookup caches of a pipeline's connectors by role
+func (e *Engine) lookupCaches(pipelineID string) (*registry.Pipeline, []LookupCacheState, []connectors.LookupCache, error) {
+	p, err := e.registry.GetByID(pipelineID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	source, target, err := e.Connect(p)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var (
+		states []LookupCacheState
+		caches []connectors.LookupCache
+	)
+	for _, c := range []struct {
+		role, typ string
+		conn      connectors.Connector
+	}{{"source", p.Source.Type, source}, {"target", p.Target.Type, target}} {
+		cacher, ok := c.conn.(connectors.LookupCacher)
+		if !ok {
+			continue
+		}
+		states = append(states, LookupCacheState{Role: c.role, Type: c.typ})
+		caches = append(caches, cacher.LookupCache())
+	}
+	return p, states, caches, nil
+}
+
+// LookupCaches reports the lookup caches of a pipeline's connectors. The
+// connectors are shared by pipelines with the same config, and so are
+// their caches.
+func (e *Engine) LookupCaches(pipelineID string) ([]LookupCacheState, error) {
+	_, states, caches, err := e.lookupCaches(pipelineID)
+	if err != nil {
+		return nil, err
+	}
+	for i, cache := range caches {
+		states[i].LookupCacheStats = cache.Stats()
+	}
+	return states, nil
+}
+
+// ClearLookupCaches drops the cached lookups of a pipeline's connectors,
+// such as after the IDs in the endpoint were changed outside esync
+func (e *Engine) ClearLookupCaches(ctx context.Context, pipelineID string) ([]LookupCacheState, error) {
+	p, states, caches, err := e.lookupCaches(pipelineID)
+	if err != nil {
+		return nil, err
+	}
+	for i, cache := range caches {
+		if err := cache.Clear(ctx); err != nil {
+			return nil, fmt.Errorf("failed to clear %s lookup cache: %w", states[i].Role, err)
+		}
+		states[i].LookupCacheStats = cache.Stats()
+		log.Printf("[Engine] Pipeline %s %s lookup cache cleared", p.ID, states[i].Role)
+	}
+	return states, nil
+}
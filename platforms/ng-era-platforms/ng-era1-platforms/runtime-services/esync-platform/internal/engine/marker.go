@@ -0,0 +1,79 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: freshness-markers
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Downstream Freshness Markers
+ */
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// writeFreshnessMarker writes the freshness marker of a successful run. A
+// marker that cannot be written is reported without failing the run.
+func (e *Engine) writeFreshnessMarker(ctx context.Context, p *registry.Pipeline, run *Run) {
+	spec := p.FreshnessMarker
+	if spec == nil || p.Mode == registry.ModeCleanup {
+		return
+	}
+
+	if err := e.applyFreshnessMarker(ctx, p, run); err != nil {
+		e.recordError(p.ID, "freshness_marker", fmt.Errorf("failed to write freshness marker: %w", err))
+	}
+}
+
+// applyFreshnessMarker writes the marker record to the marker target
+func (e *Engine) applyFreshnessMarker(ctx context.Context, p *registry.Pipeline, run *Run) error {
+	spec := p.FreshnessMarker
+	targetSpec := p.Target
+	if spec.Target != nil {
+		targetSpec = *spec.Target
+	}
+	target, err := e.connector(targetSpec)
+	if err != nil {
+		return err
+	}
+
+	table := spec.Table
+	if table == "" {
+		table = registry.DefaultFreshnessTable
+	}
+	data := map[string]interface{}{
+		"pipeline_id": p.ID,
+		"run_id":      run.ID,
+		"records":     run.Records,
+		"synced_at":   run.FinishedAt.Format(time.RFC3339Nano),
+	}
+	if run.Checkpoint != nil {
+		data["watermark"] = run.Checkpoint.To
+	} else if checkpoint, err := e.store.LoadCheckpoint(p.ID); err == nil && checkpoint != nil {
+		data["watermark"] = checkpoint.Position
+	}
+	if a := e.SourceActivity(p.ID); a != nil && !a.LastChange.IsZero() {
+		data["source_time"] = a.LastChange.UTC().Format(time.RFC3339Nano)
+	}
+
+	return target.ApplyChanges(ctx, []connectors.Record{{
+		ID:        p.ID,
+		Operation: connectors.OperationInsert,
+		Data:      data,
+		Timestamp: run.FinishedAt,
+		Table:     table,
+	}})
+}
@@ -0,0 +1,294 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: memory-pressure
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Memory Pressure
+ */
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"runtime/debug"
+	"runtime/metrics"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// Memory pressure levels
+const (
+	MemoryNormal   = "normal"
+	MemoryElevated = "elevated"
+	MemoryCritical = "critical"
+)
+
+// EventMemoryPressure is published when the memory pressure level changes
+const EventMemoryPressure = "memory_pressure"
+
+// pauseReasonMemory marks bulk pipelines paused under critical memory
+// pressure
+const pauseReasonMemory = "memory_pressure"
+
+const (
+	// memoryTick is how often memory use is checked
+	memoryTick = 5 * time.Second
+	// memoryElevated and memoryCritical are the shares of the memory limit
+	// in use at which the levels are entered; a level is left once use
+	// drops memoryHysteresis below it
+	memoryElevated   = 0.75
+	memoryCritical   = 0.90
+	memoryHysteresis = 0.05
+)
+
+// memoryScale is the share of the configured batch size used per level
+var memoryScale = map[string]float64{
+	MemoryNormal:   1,
+	MemoryElevated: 0.5,
+	MemoryCritical: 0.25,
+}
+
+// memoryRank orders the levels
+var memoryRank = map[string]int{MemoryNormal: 0, MemoryElevated: 1, MemoryCritical: 2}
+
+// MemoryStatus is the memory use of the daemon against its limit and what
+// the engine does about it
+type MemoryStatus struct {
+	Level string `json:"level"`
+	// UsedBytes is the memory the Go runtime holds from the OS, as counted
+	// against GOMEMLIMIT
+	UsedBytes uint64 `json:"used_bytes"`
+	// LimitBytes is the memory limit, 0 when unbounded, and LimitSource
+	// where it came from: GOMEMLIMIT or cgroup
+	LimitBytes  uint64  `json:"limit_bytes"`
+	LimitSource string  `json:"limit_source,omitempty"`
+	Ratio       float64 `json:"ratio"`
+	// BatchScale is the share of their batch size pipelines write at
+	BatchScale float64 `json:"batch_scale"`
+	// Paused holds the bulk pipelines paused for memory pressure
+	Paused []string `json:"paused,omitempty"`
+	// Actions describes what the last level change did
+	Actions   []string  `json:"actions,omitempty"`
+	Since     time.Time `json:"since"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Memory returns the memory status as of the last check
+func (e *Engine) Memory() MemoryStatus {
+	e.memoryMu.Lock()
+	defer e.memoryMu.Unlock()
+
+	out := e.memory
+	if out.Level == "" {
+		out.Level, out.BatchScale = MemoryNormal, 1
+	}
+	out.Paused = slices.Clone(out.Paused)
+	out.Actions = slices.Clone(out.Actions)
+	return out
+}
+
+// WatchMemory checks memory use every few seconds until ctx is cancelled.
+// Under elevated pressure batch sizes are halved and garbage is collected;
+// under critical pressure they are quartered and bulk pipelines paused,
+// so the daemon sheds load before it is OOM-killed.
+func (e *Engine) WatchMemory(ctx context.Context) {
+	ticker := time.NewTicker(memoryTick)
+	defer ticker.Stop()
+
+	for {
+		e.checkMemory(time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkMemory moves to the level of the current memory use and pauses or
+// resumes bulk pipelines for it. Pipelines paused by an earlier daemon are
+// resumed once pressure is below critical.
+func (e *Engine) checkMemory(now time.Time) {
+	used := memoryUsed()
+	limit, source := memoryLimit()
+
+	e.memoryMu.Lock()
+	prev := e.memory.Level
+	if prev == "" {
+		prev = MemoryNormal
+	}
+	ratio := 0.0
+	if limit > 0 {
+		ratio = float64(used) / float64(limit)
+	}
+	level := memoryLevel(prev, ratio)
+	e.memory.UsedBytes, e.memory.LimitBytes, e.memory.LimitSource = used, limit, source
+	e.memory.Ratio, e.memory.CheckedAt = ratio, now.UTC()
+	if e.memory.Level == "" || level != prev {
+		e.memory.Level, e.memory.BatchScale, e.memory.Since = level, memoryScale[level], now.UTC()
+		e.memory.Actions = nil
+	}
+	e.memoryMu.Unlock()
+
+	paused, resumed := e.applyMemoryPressure(level == MemoryCritical)
+	if level == prev && len(paused) == 0 && len(resumed) == 0 {
+		e.monitor.RecordMemory(level, used, limit)
+		return
+	}
+
+	var actions []string
+	if memoryRank[level] > memoryRank[prev] {
+		debug.FreeOSMemory()
+		actions = append(actions, "collected garbage and returned freed memory to the OS")
+	}
+	if level != prev {
+		actions = append(actions, fmt.Sprintf("scaled batch sizes to %.0f%%", memoryScale[level]*100))
+	}
+	if len(paused) > 0 {
+		actions = append(actions, "paused bulk pipelines "+strings.Join(paused, ", "))
+	}
+	if len(resumed) > 0 {
+		actions = append(actions, "resumed pipelines "+strings.Join(resumed, ", "))
+	}
+
+	e.memoryMu.Lock()
+	for _, id := range paused {
+		if !slices.Contains(e.memory.Paused, id) {
+			e.memory.Paused = append(e.memory.Paused, id)
+		}
+	}
+	e.memory.Paused = slices.DeleteFunc(e.memory.Paused, func(id string) bool { return slices.Contains(resumed, id) })
+	e.memory.Actions = actions
+	status := e.memory
+	status.Paused = slices.Clone(status.Paused)
+	e.memoryMu.Unlock()
+
+	e.monitor.RecordMemory(level, used, limit)
+	if level == prev {
+		return
+	}
+	log.Printf("[Engine] Memory pressure %s: %d of %d bytes in use (%s)", level, used, limit, strings.Join(actions, "; "))
+	e.broadcast(Event{Type: EventMemoryPressure, Time: now.UTC(), Reason: pauseReasonMemory, Memory: &status})
+}
+
+// applyMemoryPressure pauses the bulk pipelines while critical and resumes
+// the pipelines it paused otherwise, returning the IDs of both
+func (e *Engine) applyMemoryPressure(critical bool) (paused, resumed []string) {
+	for _, p := range e.registry.GetAll() {
+		was, err := e.pausedFor(p.ID)
+		if err != nil {
+			log.Printf("[Engine] Failed to read pause state of pipeline %s: %v", p.ID, err)
+			continue
+		}
+		switch {
+		case critical && p.Priority == registry.PriorityBulk && was == "":
+			err = e.pauseFor(p.ID, pauseReasonMemory)
+			paused = append(paused, p.ID)
+		case !critical && was == pauseReasonMemory:
+			err = e.resumeFor(p.ID, pauseReasonMemory)
+			resumed = append(resumed, p.ID)
+		}
+		if err != nil {
+			log.Printf("[Engine] Failed to apply memory pressure to pipeline %s: %v", p.ID, err)
+		}
+	}
+	return paused, resumed
+}
+
+// pausedFor returns the reason a pipeline is paused for, "operator" for
+// operator pauses and empty when it is not paused
+func (e *Engine) pausedFor(pipelineID string) (string, error) {
+	var st pauseState
+	found, err := e.store.Load("paused/"+pipelineID, &st)
+	if err != nil || !found {
+		return "", err
+	}
+	if st.Reason == "" {
+		return "operator", nil
+	}
+	return st.Reason, nil
+}
+
+// memoryLevel returns the level for the share of the limit in use, keeping
+// the current level until use drops memoryHysteresis below it
+func memoryLevel(current string, ratio float64) string {
+	switch {
+	case ratio >= memoryCritical:
+		return MemoryCritical
+	case current == MemoryCritical && ratio >= memoryCritical-memoryHysteresis:
+		return MemoryCritical
+	case ratio >= memoryElevated:
+		return MemoryElevated
+	case current != MemoryNormal && ratio >= memoryElevated-memoryHysteresis:
+		return MemoryElevated
+	}
+	return MemoryNormal
+}
+
+// batchSize returns the batch size of a pipeline scaled down for memory
+// pressure
+func (e *Engine) batchSize(p *registry.Pipeline) int {
+	size := p.BatchSize
+	if size <= 0 {
+		size = registry.DefaultBatchSize
+	}
+	e.memoryMu.Lock()
+	scale := e.memory.BatchScale
+	e.memoryMu.Unlock()
+	if scale > 0 && scale < 1 {
+		size = max(1, int(float64(size)*scale))
+	}
+	return size
+}
+
+// memoryUsed returns the memory the Go runtime holds from the OS and has
+// not released, which is what GOMEMLIMIT bounds
+func memoryUsed() uint64 {
+	samples := []metrics.Sample{
+		{Name: "/memory/classes/total:bytes"},
+		{Name: "/memory/classes/heap/released:bytes"},
+	}
+	metrics.Read(samples)
+	total, released := samples[0].Value.Uint64(), samples[1].Value.Uint64()
+	if released > total {
+		return 0
+	}
+	return total - released
+}
+
+// memoryLimit returns GOMEMLIMIT or, when unset, the memory limit of the
+// daemon's cgroup; 0 when neither bounds it
+func memoryLimit() (uint64, string) {
+	if limit := debug.SetMemoryLimit(-1); limit > 0 && limit < math.MaxInt64 {
+		return uint64(limit), "GOMEMLIMIT"
+	}
+	for _, path := range []string{"/sys/fs/cgroup/memory.max", "/sys/fs/cgroup/memory/memory.limit_in_bytes"} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		// cgroup v1 reports no limit as a page-rounded MaxInt64
+		limit, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+		if err == nil && limit > 0 && limit < 1<<62 {
+			return limit, "cgroup"
+		}
+	}
+	return 0, ""
+}
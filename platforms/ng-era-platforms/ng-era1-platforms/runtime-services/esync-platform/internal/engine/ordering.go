@@ -0,0 +1,270 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: apply-ordering
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Ordered Parallel Apply
+ */
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// orderRecords moves records after the records they depend on, keeping the
+// source order otherwise. Dependencies outside the batch are ignored and
+// cycles fall back to source order. Records with keys equal under the
+// collation keep their order.
+func orderRecords(records []connectors.Record, coll collation) []connectors.Record {
+	byID := make(map[string][]int)
+	byKey := make(map[connectors.Key][]int)
+	hasDeps := false
+	for i, r := range records {
+		byID[r.ID] = append(byID[r.ID], i)
+		byKey[coll.key(r)] = append(byKey[coll.key(r)], i)
+		hasDeps = hasDeps || len(r.DependsOn) > 0
+	}
+	if !hasDeps {
+		return records
+	}
+
+	// Each record waits for every record carrying an ID it depends on, and
+	// for earlier records with its own key
+	blockers := make([][]int, len(records))
+	waiting := make([]int, len(records))
+	dependents := make([][]int, len(records))
+	for i, r := range records {
+		for _, dep := range r.DependsOn {
+			for _, j := range byID[dep] {
+				if j != i {
+					blockers[i] = append(blockers[i], j)
+				}
+			}
+		}
+		if same := byKey[coll.key(r)]; len(same) > 1 {
+			for _, j := range same {
+				if j < i {
+					blockers[i] = append(blockers[i], j)
+				}
+			}
+		}
+		waiting[i] = len(blockers[i])
+		for _, j := range blockers[i] {
+			dependents[j] = append(dependents[j], i)
+		}
+	}
+
+	out := make([]connectors.Record, 0, len(records))
+	done := make([]bool, len(records))
+	ready := []int{}
+	for i := range records {
+		if waiting[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+	for len(out) < len(records) {
+		if len(ready) == 0 {
+			// Cycle: release the earliest remaining record
+			for i := range records {
+				if !done[i] {
+					ready = append(ready, i)
+					break
+				}
+			}
+		}
+		sort.Ints(ready)
+		i := ready[0]
+		ready = ready[1:]
+		if done[i] {
+			continue
+		}
+		done[i] = true
+		out = append(out, records[i])
+		for _, d := range dependents[i] {
+			waiting[d]--
+			if waiting[d] == 0 && !done[d] {
+				ready = append(ready, d)
+			}
+		}
+	}
+	return out
+}
+
+// lanes splits ordered records into at most n lanes that can be applied in
+// parallel. Records sharing an ordering key (the record key by default), or
+// linked by DependsOn, stay in one lane in order; lanes are balanced by size.
+// Keys equal under the collation count as one.
+func lanes(records []connectors.Record, n int, coll collation) [][]connectors.Record {
+	if n <= 1 || len(records) <= 1 {
+		return [][]connectors.Record{records}
+	}
+
+	parent := make([]int, len(records))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	union := func(a, b int) {
+		if ra, rb := find(a), find(b); ra != rb {
+			parent[rb] = ra
+		}
+	}
+
+	byOrdering := make(map[string]int)
+	byKey := make(map[connectors.Key]int)
+	byID := make(map[string]int)
+	for i, r := range records {
+		if r.OrderingKey != "" {
+			if j, exists := byOrdering[r.OrderingKey]; exists {
+				union(j, i)
+			} else {
+				byOrdering[r.OrderingKey] = i
+			}
+		}
+		if j, exists := byKey[coll.key(r)]; exists {
+			union(j, i)
+		} else {
+			byKey[coll.key(r)] = i
+		}
+		if _, exists := byID[r.ID]; !exists {
+			byID[r.ID] = i
+		}
+	}
+	for i, r := range records {
+		for _, dep := range r.DependsOn {
+			if j, exists := byID[dep]; exists {
+				union(j, i)
+			}
+		}
+	}
+
+	groups := make(map[int][]connectors.Record)
+	var roots []int
+	for i, r := range records {
+		root := find(i)
+		if _, exists := groups[root]; !exists {
+			roots = append(roots, root)
+		}
+		groups[root] = append(groups[root], r)
+	}
+
+	// Largest groups first, each to the currently smallest lane
+	sort.SliceStable(roots, func(a, b int) bool { return len(groups[roots[a]]) > len(groups[roots[b]]) })
+	if n > len(roots) {
+		n = len(roots)
+	}
+	out := make([][]connectors.Record, n)
+	for _, root := range roots {
+		smallest := 0
+		for l := range out {
+			if len(out[l]) < len(out[smallest]) {
+				smallest = l
+			}
+		}
+		out[smallest] = append(out[smallest], groups[root]...)
+	}
+	return out
+}
+
+// write applies records in batches of the pipeline batch size, scaled down
+// under memory pressure, spread over the pipeline's apply workers, and
+// returns the number applied. The first failure stops the other lanes.
+func (e *Engine) write(ctx context.Context, p *registry.Pipeline, target connectors.Connector, records []connectors.Record, tracker *progressTracker, label string) (int, error) {
+	batchSize := e.batchSize(p)
+	workers := e.applyWorkers(p)
+	mapper, err := e.keyMapper(p)
+	if err != nil {
+		return 0, err
+	}
+
+	split := lanes(records, workers, collationOf(p))
+	if len(split) == 1 {
+		return e.writeLane(ctx, p, target, mapper, split[0], batchSize, tracker, label)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		applied  int
+		firstErr error
+	)
+	for l, lane := range split {
+		wg.Add(1)
+		go func(l int, lane []connectors.Record) {
+			defer wg.Done()
+
+			n, err := e.writeLane(ctx, p, target, mapper, lane, batchSize, tracker, fmt.Sprintf("%slane%d:", label, l))
+			mu.Lock()
+			defer mu.Unlock()
+			applied += n
+			if err != nil && firstErr == nil {
+				firstErr = err
+				cancel()
+			}
+		}(l, lane)
+	}
+	wg.Wait()
+
+	return applied, firstErr
+}
+
+// writeLane applies one lane of records in order, under mapped target IDs
+// when mapper is non-nil, holding a worker slot of the pipeline's namespace
+func (e *Engine) writeLane(ctx context.Context, p *registry.Pipeline, target connectors.Connector, mapper *keyMapper, records []connectors.Record, batchSize int, tracker *progressTracker, label string) (int, error) {
+	release, err := e.acquireWorker(ctx, e.quotaFor(p))
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	for start := 0; start < len(records); start += batchSize {
+		end := start + batchSize
+		if end > len(records) {
+			end = len(records)
+		}
+		size, err := e.throttle(ctx, p, records[start:end])
+		if err != nil {
+			return start, err
+		}
+		began := time.Now()
+		if mapper != nil {
+			err = mapper.apply(ctx, target, records[start:end])
+		} else {
+			err = target.ApplyChanges(ctx, records[start:end])
+		}
+		e.traceCall(p.ID, e.connectorType(p.Target), "apply_changes", began, end-start, err)
+		if err != nil {
+			return start, fmt.Errorf("failed to apply changes: %w", err)
+		}
+		e.meter(p.ID, DirectionWritten, size, 0)
+		e.tap(p.ID, records[start:end])
+		tracker.wrote(records[start:end])
+		tracker.advance(end-start, fmt.Sprintf("%s%d-%d", label, start, end-1))
+	}
+	return len(records), nil
+}
@@ -0,0 +1,179 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: orphan-detection
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Orphaned Resource Detection
+ */
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// orphansKey is the state key of the last orphan report
+const orphansKey = "orphans"
+
+// OrphanedResource is a connector resource no registered pipeline owns
+type OrphanedResource struct {
+	connectors.Resource
+	// Connector is the type of the connector that listed the resource
+	Connector string `json:"connector"`
+	// FirstSeen is when a check first reported the resource
+	FirstSeen time.Time `json:"first_seen"`
+}
+
+// OrphanReport is the outcome of an orphan check
+type OrphanReport struct {
+	CheckedAt time.Time `json:"checked_at"`
+	// Connectors counts the connectors that listed their resources
+	Connectors int                `json:"connectors"`
+	Orphans    []OrphanedResource `json:"orphans"`
+	// Errors lists the connectors that failed to list their resources
+	Errors []string `json:"errors,omitempty"`
+}
+
+// WatchOrphans checks for orphaned resources every interval until ctx is
+// cancelled
+func (e *Engine) WatchOrphans(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := e.CheckOrphans(ctx); err != nil {
+			log.Printf("[Engine] Orphan check failed: %v", err)
+		}
+	}
+}
+
+// CheckOrphans asks the connectors of every pipeline and connection
+// profile to list their sync-related resources and reports those created
+// for no registered pipeline, typically left behind by deleted pipelines.
+// Resources whose pipeline the connector cannot tell are reported too.
+func (e *Engine) CheckOrphans(ctx context.Context) (*OrphanReport, error) {
+	reg := e.registry.Snapshot()
+	var specs []registry.ConnectorSpec
+	for _, p := range reg.GetAll() {
+		specs = append(specs, p.Source, p.Target)
+		for _, route := range p.Routes {
+			specs = append(specs, route.Target)
+		}
+	}
+	for _, c := range reg.Connections() {
+		specs = append(specs, registry.ConnectorSpec{Connection: c.Name})
+	}
+
+	prev, err := e.OrphanReport()
+	if err != nil {
+		return nil, err
+	}
+	firstSeen := make(map[string]time.Time)
+	if prev != nil {
+		for _, o := range prev.Orphans {
+			firstSeen[orphanKey(o)] = o.FirstSeen
+		}
+	}
+
+	now := time.Now().UTC()
+	report := &OrphanReport{CheckedAt: now, Orphans: []OrphanedResource{}}
+	counts := make(map[string]map[string]int)
+	seen := make(map[string]bool)
+	for _, spec := range specs {
+		resolved, err := reg.ResolveConnector(spec)
+		if err != nil {
+			continue
+		}
+		key, err := connectorKey(resolved)
+		if err != nil || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		resources, err := e.listResources(ctx, resolved)
+		if errors.Is(err, connectors.ErrUnsupported) {
+			continue
+		}
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s connector: %v", resolved.Type, err))
+			continue
+		}
+		report.Connectors++
+
+		for _, r := range resources {
+			if r.Pipeline != "" {
+				if _, err := reg.GetByID(r.Pipeline); err == nil {
+					continue
+				}
+			}
+			o := OrphanedResource{Resource: r, Connector: resolved.Type, FirstSeen: now}
+			if t, ok := firstSeen[orphanKey(o)]; ok {
+				o.FirstSeen = t
+			} else if r.Pipeline == "" {
+				log.Printf("[Engine] Orphaned %s %s found by %s connector (no pipeline recorded)", r.Kind, r.Name, resolved.Type)
+			} else {
+				log.Printf("[Engine] Orphaned %s %s found by %s connector (pipeline %s is not registered)", r.Kind, r.Name, resolved.Type, r.Pipeline)
+			}
+			report.Orphans = append(report.Orphans, o)
+			if counts[o.Connector] == nil {
+				counts[o.Connector] = make(map[string]int)
+			}
+			counts[o.Connector][r.Kind]++
+		}
+	}
+	sort.Slice(report.Orphans, func(i, j int) bool { return orphanKey(report.Orphans[i]) < orphanKey(report.Orphans[j]) })
+
+	e.monitor.RecordOrphans(counts)
+	return report, e.store.Save(orphansKey, report)
+}
+
+// listResources lists the resources of one connector
+func (e *Engine) listResources(ctx context.Context, spec registry.ConnectorSpec) ([]connectors.Resource, error) {
+	conn, err := e.connector(spec)
+	if err != nil {
+		return nil, err
+	}
+	lister, ok := conn.(connectors.ResourceLister)
+	if !ok {
+		return nil, connectors.ErrUnsupported
+	}
+	return lister.ListResources(ctx)
+}
+
+// orphanKey identifies an orphaned resource across checks
+func orphanKey(o OrphanedResource) string {
+	return o.Connector + "/" + o.Kind + "/" + o.Name
+}
+
+// OrphanReport returns the last orphan report, or nil before the first
+// check
+func (e *Engine) OrphanReport() (*OrphanReport, error) {
+	var report OrphanReport
+	found, err := e.store.Load(orphansKey, &report)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &report, nil
+}
@@ -0,0 +1,161 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: outbox-source
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Transactional Outbox Source
+ */
+
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// outboxOperations are the operations outbox rows may request
+var outboxOperations = map[string]bool{
+	connectors.OperationInsert: true,
+	connectors.OperationUpdate: true,
+	connectors.OperationDelete: true,
+	connectors.OperationPatch:  true,
+}
+
+// outboxEvents replaces the outbox rows among changes with the records
+// their payloads carry, returning the rows to consume once the records are
+// applied. Deleted rows, such as consumed rows a CDC source reports, and
+// rows already marked are skipped; rows with an invalid payload are
+// reported and left in the outbox.
+func (e *Engine) outboxEvents(p *registry.Pipeline, changes []connectors.Record) ([]connectors.Record, []connectors.Record) {
+	spec := p.Outbox
+	if spec == nil {
+		return changes, nil
+	}
+	payloadField := spec.PayloadField
+	if payloadField == "" {
+		payloadField = registry.DefaultOutboxPayloadField
+	}
+	markField := outboxMarkField(spec)
+
+	out := make([]connectors.Record, 0, len(changes))
+	var rows []connectors.Record
+	for _, row := range changes {
+		if spec.Table != "" && row.Table != spec.Table {
+			out = append(out, row)
+			continue
+		}
+		if row.Operation == connectors.OperationDelete {
+			continue
+		}
+		if spec.Consume == registry.OutboxMark && row.Data[markField] != nil {
+			continue
+		}
+
+		event, err := outboxEvent(spec, payloadField, row)
+		if err != nil {
+			e.recordError(p.ID, "outbox", fmt.Errorf("outbox row %s: %w", row.ID, err))
+			continue
+		}
+		out = append(out, event)
+		rows = append(rows, row)
+	}
+	return out, rows
+}
+
+// outboxEvent is the record an outbox row carries
+func outboxEvent(spec *registry.OutboxSpec, payloadField string, row connectors.Record) (connectors.Record, error) {
+	event := connectors.Record{
+		ID:        row.ID,
+		Operation: connectors.OperationInsert,
+		Timestamp: row.Timestamp,
+		Clock:     row.Clock,
+	}
+	if spec.KeyField != "" {
+		key := row.Data[spec.KeyField]
+		if key == nil {
+			return event, fmt.Errorf("no %s", spec.KeyField)
+		}
+		event.ID = fmt.Sprint(key)
+	}
+	if spec.TableField != "" {
+		table, _ := row.Data[spec.TableField].(string)
+		event.Table = table
+	}
+	if spec.OperationField != "" {
+		if op, _ := row.Data[spec.OperationField].(string); op != "" {
+			if !outboxOperations[op] {
+				return event, fmt.Errorf("unknown operation %q", op)
+			}
+			event.Operation = op
+		}
+	}
+
+	switch payload := row.Data[payloadField].(type) {
+	case map[string]interface{}:
+		event.Data = payload
+	case string:
+		if err := json.Unmarshal([]byte(payload), &event.Data); err != nil {
+			return event, fmt.Errorf("%s is not a JSON object: %w", payloadField, err)
+		}
+	case nil:
+		if event.Operation != connectors.OperationDelete {
+			return event, fmt.Errorf("no %s", payloadField)
+		}
+	default:
+		return event, fmt.Errorf("%s holds %T, not an object", payloadField, payload)
+	}
+	return event, nil
+}
+
+// consumeOutbox deletes or marks the applied outbox rows through the source
+// in one batch. Rows that fail to be consumed are emitted again by the next
+// run.
+func (e *Engine) consumeOutbox(ctx context.Context, p *registry.Pipeline, source connectors.Connector, rows []connectors.Record) {
+	if len(rows) == 0 {
+		return
+	}
+
+	now := time.Now().UTC()
+	markField := outboxMarkField(p.Outbox)
+	consumed := make([]connectors.Record, len(rows))
+	for i, row := range rows {
+		data := make(map[string]interface{}, len(row.KeyFields)+1)
+		for _, f := range row.KeyFields {
+			data[f] = row.Data[f]
+		}
+		r := connectors.Record{ID: row.ID, Operation: connectors.OperationDelete, Data: data, Timestamp: now, Table: row.Table, KeyFields: row.KeyFields}
+		if p.Outbox.Consume == registry.OutboxMark {
+			r.Operation = connectors.OperationPatch
+			data[markField] = now.Format(time.RFC3339Nano)
+		}
+		consumed[i] = r
+	}
+
+	start := time.Now()
+	err := source.ApplyChanges(ctx, consumed)
+	e.traceCall(p.ID, e.connectorType(p.Source), "consume_outbox", start, len(consumed), err)
+	if err != nil {
+		e.recordError(p.ID, "outbox", fmt.Errorf("failed to consume %d outbox rows, they will be emitted again: %w", len(consumed), err))
+	}
+}
+
+// outboxMarkField is the field marking consumed rows
+func outboxMarkField(spec *registry.OutboxSpec) string {
+	if spec.MarkField != "" {
+		return spec.MarkField
+	}
+	return registry.DefaultOutboxMarkField
+}
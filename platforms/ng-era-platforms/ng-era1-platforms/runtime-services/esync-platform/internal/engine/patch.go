@@ -0,0 +1,88 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: patch-apply
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Partial Update (Patch) Merging
+ */
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// mergePatches prepares patch records for the target. Targets implementing
+// PatchWriter receive patches unchanged; for other targets the stored
+// records are read back and each patch is merged into a full update.
+// Patches whose record does not exist at the target are rejected.
+func (e *Engine) mergePatches(ctx context.Context, p *registry.Pipeline, target connectors.Connector, records []connectors.Record) ([]connectors.Record, error) {
+	var ids []string
+	for _, r := range records {
+		if r.Operation == connectors.OperationPatch {
+			ids = append(ids, r.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return records, nil
+	}
+	if writer, ok := target.(connectors.PatchWriter); ok && writer.SupportsPatch() {
+		return records, nil
+	}
+
+	reader, ok := target.(connectors.RecordReader)
+	if !ok {
+		return nil, fmt.Errorf("target cannot apply patch records: it neither merges patches nor reads records back")
+	}
+	current, err := reader.ReadRecords(ctx, ids)
+	if errors.Is(err, connectors.ErrUnsupported) {
+		return nil, fmt.Errorf("target cannot apply patch records: it neither merges patches nor reads records back")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read records to patch: %w", err)
+	}
+	stored := make(map[string]map[string]interface{}, len(current))
+	for _, r := range current {
+		stored[r.ID] = r.Data
+	}
+
+	out := make([]connectors.Record, 0, len(records))
+	for _, r := range records {
+		if r.Operation != connectors.OperationPatch {
+			out = append(out, r)
+			continue
+		}
+
+		base, exists := stored[r.ID]
+		if !exists {
+			e.recordError(p.ID, "patch", fmt.Errorf("record %s: cannot patch a record missing at the target", r.ID))
+			continue
+		}
+		merged := make(map[string]interface{}, len(base)+len(r.Data))
+		for k, v := range base {
+			merged[k] = v
+		}
+		for k, v := range r.Data {
+			merged[k] = v
+		}
+		r.Operation = connectors.OperationUpdate
+		r.Data = merged
+		// Later patches of the same record build on this one
+		stored[r.ID] = merged
+		out = append(out, r)
+	}
+	return out, nil
+}
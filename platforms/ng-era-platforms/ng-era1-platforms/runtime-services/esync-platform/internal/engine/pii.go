@@ -0,0 +1,136 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: pii-classifier
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * PII Findings
+ */
+
+package engine
+
+import (
+	"sort"
+	"time"
+)
+
+// piiFlagShare is the share of sampled values of a field that must match a
+// category for the field to be flagged as holding it
+const piiFlagShare = 0.5
+
+// PIIFinding summarizes what classify_pii stages saw in one field
+type PIIFinding struct {
+	Field string `json:"field"`
+	// Sampled counts the non-empty string values classified
+	Sampled int64 `json:"sampled"`
+	// Matches counts the sampled values matching each category
+	Matches map[string]int64 `json:"matches,omitempty"`
+	// Categories are those matched by at least half of the samples
+	Categories []string  `json:"categories,omitempty"`
+	LastSeen   time.Time `json:"last_seen"`
+}
+
+// observePII counts a classified value into the findings of a pipeline
+func (e *Engine) observePII(pipelineID, field string, categories []string) {
+	e.piiMu.Lock()
+	defer e.piiMu.Unlock()
+
+	findings, err := e.loadPII(pipelineID)
+	if err != nil {
+		return
+	}
+	f := findings[field]
+	if f == nil {
+		f = &PIIFinding{Field: field, Matches: make(map[string]int64)}
+		findings[field] = f
+	}
+	f.Sampled++
+	for _, category := range categories {
+		f.Matches[category]++
+	}
+	f.LastSeen = time.Now().UTC()
+
+	f.Categories = f.Categories[:0]
+	for category, n := range f.Matches {
+		if float64(n) >= piiFlagShare*float64(f.Sampled) {
+			f.Categories = append(f.Categories, category)
+		}
+	}
+	sort.Strings(f.Categories)
+	e.piiDirty[pipelineID] = true
+}
+
+// loadPII returns the cached findings of a pipeline, reading them from the
+// state store on first use. Callers hold piiMu.
+func (e *Engine) loadPII(pipelineID string) (map[string]*PIIFinding, error) {
+	if findings, ok := e.pii[pipelineID]; ok {
+		return findings, nil
+	}
+	var stored []*PIIFinding
+	if _, err := e.store.Load("pii/"+pipelineID, &stored); err != nil {
+		return nil, err
+	}
+	findings := make(map[string]*PIIFinding, len(stored))
+	for _, f := range stored {
+		findings[f.Field] = f
+	}
+	e.pii[pipelineID] = findings
+	return findings, nil
+}
+
+// savePII stores the findings of a pipeline if they changed
+func (e *Engine) savePII(pipelineID string) error {
+	e.piiMu.Lock()
+	defer e.piiMu.Unlock()
+	if !e.piiDirty[pipelineID] {
+		return nil
+	}
+	delete(e.piiDirty, pipelineID)
+	return e.store.Save("pii/"+pipelineID, sortedFindings(e.pii[pipelineID]))
+}
+
+// PIIFindings returns the PII findings of a pipeline ordered by field,
+// flagged fields first
+func (e *Engine) PIIFindings(pipelineID string) ([]PIIFinding, error) {
+	e.piiMu.Lock()
+	defer e.piiMu.Unlock()
+
+	findings, err := e.loadPII(pipelineID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]PIIFinding, 0, len(findings))
+	for _, f := range sortedFindings(findings) {
+		c := *f
+		c.Matches = make(map[string]int64, len(f.Matches))
+		for k, v := range f.Matches {
+			c.Matches[k] = v
+		}
+		c.Categories = append([]string(nil), f.Categories...)
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+// sortedFindings orders findings with flagged fields first, then by name
+func sortedFindings(findings map[string]*PIIFinding) []*PIIFinding {
+	out := make([]*PIIFinding, 0, len(findings))
+	for _, f := range findings {
+		out = append(out, f)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		fi, fj := len(out[i].Categories) > 0, len(out[j].Categories) > 0
+		if fi != fj {
+			return fi
+		}
+		return out[i].Field < out[j].Field
+	})
+	return out
+}
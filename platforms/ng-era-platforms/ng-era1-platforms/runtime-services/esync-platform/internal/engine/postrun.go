@@ -0,0 +1,231 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: post-run-actions
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Post-Run Actions
+ */
+
+package engine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/egress"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// Defaults of post-run actions
+const (
+	defaultActionAttempts = 3
+	defaultActionTimeout  = 30 * time.Second
+	actionBackoff         = 2 * time.Second
+	defaultDBTCloudURL    = "https://cloud.getdbt.com"
+)
+
+// actionState is what a post-run action accumulated since it last fired
+type actionState struct {
+	records  int
+	inFlight bool
+}
+
+// postRunActions fires the post-run actions of a pipeline whose run moved
+// the watermark once their record threshold is reached. Actions are
+// triggered in the background, and a triggering action keeps accumulating
+// records until it completes; a failed action fires again after the next
+// run.
+func (e *Engine) postRunActions(p *registry.Pipeline, run *Run) {
+	if len(p.PostRun) == 0 || p.Mode == registry.ModeCleanup {
+		return
+	}
+	if run.Checkpoint == nil || run.Checkpoint.From == run.Checkpoint.To {
+		return
+	}
+
+	e.actionsMu.Lock()
+	defer e.actionsMu.Unlock()
+
+	for i := range p.PostRun {
+		spec := p.PostRun[i]
+		key := fmt.Sprintf("%s/%d", p.ID, i)
+		st := e.actions[key]
+		if st == nil {
+			st = &actionState{}
+			e.actions[key] = st
+		}
+		st.records += run.Records
+
+		threshold := spec.MinRecords
+		if threshold == 0 {
+			threshold = 1
+		}
+		if st.inFlight || st.records < threshold {
+			continue
+		}
+		st.inFlight = true
+		records := st.records
+		go func() {
+			err := e.triggerAction(p, spec, run, records)
+			e.actionsMu.Lock()
+			st.inFlight = false
+			if err == nil {
+				st.records -= records
+			}
+			e.actionsMu.Unlock()
+		}()
+	}
+}
+
+// triggerAction calls the downstream job, retrying failures with
+// exponential backoff
+func (e *Engine) triggerAction(p *registry.Pipeline, spec registry.PostRunActionSpec, run *Run, records int) error {
+	attempts := spec.Attempts
+	if attempts == 0 {
+		attempts = defaultActionAttempts
+	}
+	timeout := defaultActionTimeout
+	if spec.Timeout > 0 {
+		timeout = time.Duration(spec.Timeout) * time.Second
+	}
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(actionBackoff << (attempt - 2))
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		err = e.callAction(ctx, p, spec, run, records)
+		cancel()
+		if err == nil {
+			log.Printf("[Engine] Post-run %s action of pipeline %s triggered for run %s", spec.Type, p.ID, run.ID)
+			return nil
+		}
+		log.Printf("[Engine] Post-run %s action of pipeline %s failed (attempt %d/%d): %v", spec.Type, p.ID, attempt, attempts, err)
+	}
+	err = fmt.Errorf("post-run %s action failed after %d attempts: %w", spec.Type, attempts, err)
+	e.recordError(p.ID, "post_run", err)
+	return err
+}
+
+// callAction sends one trigger request. Every request carries the run ID
+// as X-Esync-Run-Id for correlating the downstream job with the run.
+func (e *Engine) callAction(ctx context.Context, p *registry.Pipeline, spec registry.PostRunActionSpec, run *Run, records int) error {
+	secrets, _, err := e.resolver.ExpandConfig(map[string]interface{}{
+		"token":    spec.Token,
+		"password": spec.Password,
+		"headers":  stringMapToConfig(spec.Headers),
+	}, false)
+	if err != nil {
+		return fmt.Errorf("failed to resolve secrets: %w", err)
+	}
+	token, _ := secrets["token"].(string)
+	password, _ := secrets["password"].(string)
+
+	watermark := ""
+	if run.Checkpoint != nil {
+		watermark = run.Checkpoint.To
+	}
+	event := map[string]interface{}{
+		"pipeline_id": p.ID,
+		"run_id":      run.ID,
+		"records":     records,
+		"watermark":   watermark,
+		"finished_at": run.FinishedAt,
+	}
+
+	method, endpoint := http.MethodPost, spec.URL
+	var body interface{}
+	header := make(http.Header)
+	switch spec.Type {
+	case registry.ActionDBTCloud:
+		base := spec.URL
+		if base == "" {
+			base = defaultDBTCloudURL
+		}
+		endpoint = fmt.Sprintf("%s/api/v2/accounts/%d/jobs/%d/run/", strings.TrimRight(base, "/"), spec.AccountID, spec.JobID)
+		header.Set("Authorization", "Token "+token)
+		body = map[string]interface{}{"cause": fmt.Sprintf("esync pipeline %s run %s", p.ID, run.ID)}
+	case registry.ActionAirflow:
+		endpoint = fmt.Sprintf("%s/dags/%s/dagRuns", strings.TrimRight(spec.URL, "/"), url.PathEscape(spec.DAG))
+		// The DAG run ID makes retries of an accepted trigger conflict
+		// instead of starting a second DAG run
+		body = map[string]interface{}{"dag_run_id": "esync-" + run.ID, "conf": event}
+	default:
+		if spec.Method != "" {
+			method = spec.Method
+		}
+		if method != http.MethodGet {
+			body = event
+		}
+		if headers, ok := secrets["headers"].(map[string]interface{}); ok {
+			for k, v := range headers {
+				header.Set(k, fmt.Sprint(v))
+			}
+		}
+	}
+	if spec.Type != registry.ActionDBTCloud && spec.Username == "" && token != "" {
+		header.Set("Authorization", "Bearer "+token)
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+		header.Set("Content-Type", "application/json")
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if spec.Type != registry.ActionDBTCloud && spec.Username != "" {
+		req.SetBasicAuth(spec.Username, password)
+	}
+	req.Header.Set("X-Esync-Run-Id", run.ID)
+	req.Header.Set("X-Esync-Pipeline", p.ID)
+
+	resp, err := egress.Client(0).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if spec.Type == registry.ActionAirflow && resp.StatusCode == http.StatusConflict {
+		return nil
+	}
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s returned %s: %s", endpoint, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// stringMapToConfig converts headers to a config map for secret expansion
+func stringMapToConfig(m map[string]string) map[string]interface{} {
+	config := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		config[k] = v
+	}
+	return config
+}
@@ -0,0 +1,341 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: pipeline-preflight
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Pre-Flight Checks Before First Run
+ */
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/coercion"
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+	"github.com/machine-native-ops/esync-platform/internal/transform"
+)
+
+// ErrPreflightFailed is returned when a run is requested for a pipeline whose
+// last pre-flight check failed
+var ErrPreflightFailed = errors.New("pre-flight checks failed")
+
+// PreflightReport collects the results of all pre-flight checks of a pipeline
+type PreflightReport struct {
+	PipelineID string                   `json:"pipeline_id"`
+	Passed     bool                     `json:"passed"`
+	Checks     []connectors.CheckResult `json:"checks"`
+	CheckedAt  time.Time                `json:"checked_at"`
+}
+
+// Failures returns the failed checks
+func (r *PreflightReport) Failures() []connectors.CheckResult {
+	var failed []connectors.CheckResult
+	for _, c := range r.Checks {
+		if c.Status == connectors.CheckFailed {
+			failed = append(failed, c)
+		}
+	}
+	return failed
+}
+
+// Preflight verifies connectivity, permissions, schema compatibility and
+// local disk space for a pipeline. The report is kept so that runs of a
+// pipeline with failing checks are refused until a later check passes.
+func (e *Engine) Preflight(ctx context.Context, pipelineID string) (*PreflightReport, error) {
+	p, err := e.registry.GetByID(pipelineID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &PreflightReport{PipelineID: p.ID, CheckedAt: time.Now().UTC()}
+	add := func(checks ...connectors.CheckResult) {
+		report.Checks = append(report.Checks, checks...)
+	}
+
+	source, sourceErr := e.connector(p.Source)
+	add(connectCheck("source", p.Source, sourceErr))
+	target, targetErr := e.connector(p.Target)
+	add(connectCheck("target", p.Target, targetErr))
+	for _, route := range p.Routes {
+		_, err := e.connector(route.Target)
+		add(connectCheck("route_"+route.Table, route.Target, err))
+	}
+	add(transformCheck(p))
+	add(coercionCheck(p))
+	add(tableSelectionCheck(p))
+	add(e.residencyCheck(p))
+
+	if sourceErr == nil {
+		add(e.positionCheck(ctx, source))
+		add(connectorChecks(ctx, source, "source")...)
+	}
+	if targetErr == nil {
+		add(connectorChecks(ctx, target, "target")...)
+		if p.MissingFields == registry.MissingFieldsNull {
+			add(missingFieldsCheck(ctx, target))
+		}
+		if p.Bootstrap {
+			add(bootstrapCheck(target))
+		}
+	}
+	if sourceErr == nil && targetErr == nil {
+		if e.pendingBootstrap(p) {
+			add(connectors.CheckResult{
+				Name:    "schema_compatibility",
+				Status:  connectors.CheckSkipped,
+				Message: "target objects are created by bootstrap on the first run",
+			})
+		} else {
+			add(schemaCheck(ctx, source, target))
+		}
+		if p.DDL != nil {
+			add(ddlCheck(p, source, target))
+		}
+	}
+	add(e.diskCheck(p))
+
+	report.Passed = len(report.Failures()) == 0
+
+	e.mu.Lock()
+	e.preflight[p.ID] = report
+	e.mu.Unlock()
+
+	return report, nil
+}
+
+// PreflightReport returns the last pre-flight report of a pipeline, or nil
+func (e *Engine) PreflightReport(pipelineID string) *PreflightReport {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.preflight[pipelineID]
+}
+
+// checkPreflight refuses runs of pipelines whose last pre-flight failed
+func (e *Engine) checkPreflight(pipelineID string) error {
+	report := e.PreflightReport(pipelineID)
+	if report == nil || report.Passed {
+		return nil
+	}
+
+	failed := report.Failures()
+	return fmt.Errorf("cannot run %s: %s: %w", pipelineID, failed[0].Message, ErrPreflightFailed)
+}
+
+// connectCheck reports whether a connector could be instantiated
+func connectCheck(role string, spec registry.ConnectorSpec, err error) connectors.CheckResult {
+	check := connectors.CheckResult{Name: role + "_connector", Status: connectors.CheckPassed}
+	if err != nil {
+		check.Status = connectors.CheckFailed
+		check.Message = err.Error()
+		check.Remedy = fmt.Sprintf("check the %s type %q and its config block; registered types: %v", role, spec.Type, connectors.Types())
+	}
+	return check
+}
+
+// transformCheck verifies the declared transform chain can be built
+func transformCheck(p *registry.Pipeline) connectors.CheckResult {
+	check := connectors.CheckResult{Name: "transform_chain", Status: connectors.CheckPassed}
+	if _, err := transform.Build(p.Transforms, nil); err != nil {
+		check.Status = connectors.CheckFailed
+		check.Message = err.Error()
+		check.Remedy = fmt.Sprintf("fix the transforms block; registered types: %v", transform.Types())
+	}
+	return check
+}
+
+// coercionCheck verifies the coercion policy names known types and modes
+func coercionCheck(p *registry.Pipeline) connectors.CheckResult {
+	check := connectors.CheckResult{Name: "coercion_policy", Status: connectors.CheckPassed}
+	if p.Coercion == nil {
+		check.Status = connectors.CheckSkipped
+		check.Message = "no coercion policy configured"
+		return check
+	}
+	if _, err := coercion.NewPolicy(p.Coercion, nil); err != nil {
+		check.Status = connectors.CheckFailed
+		check.Message = err.Error()
+		check.Remedy = "use modes strict, lenient or off and types string, integer, number, boolean or timestamp"
+	}
+	return check
+}
+
+// tableSelectionCheck verifies the table patterns compile
+func tableSelectionCheck(p *registry.Pipeline) connectors.CheckResult {
+	check := connectors.CheckResult{Name: "table_selection", Status: connectors.CheckPassed}
+	if p.Tables == nil {
+		check.Status = connectors.CheckSkipped
+		check.Message = "no table selection configured"
+		return check
+	}
+	if _, err := compileSelection(p); err != nil {
+		check.Status = connectors.CheckFailed
+		check.Message = err.Error()
+		check.Remedy = "use globs such as orders_* or regular expressions wrapped in slashes, and new_tables include, ignore or alert"
+	}
+	return check
+}
+
+// positionCheck verifies the source can be read by fetching its position
+func (e *Engine) positionCheck(ctx context.Context, source connectors.Connector) connectors.CheckResult {
+	check := connectors.CheckResult{Name: "source_connectivity", Status: connectors.CheckPassed}
+	if _, err := source.GetLatestCheckpoint(ctx); err != nil {
+		check.Status = connectors.CheckFailed
+		check.Message = fmt.Sprintf("failed to read source position: %v", err)
+		check.Remedy = "verify the source endpoint is reachable and the credentials grant read access"
+	}
+	return check
+}
+
+// connectorChecks runs connector-specific connectivity and permission checks
+func connectorChecks(ctx context.Context, c connectors.Connector, role string) []connectors.CheckResult {
+	preflighter, ok := c.(connectors.Preflighter)
+	if !ok {
+		return []connectors.CheckResult{{
+			Name:    role + "_permissions",
+			Status:  connectors.CheckSkipped,
+			Message: "connector does not implement pre-flight checks",
+		}}
+	}
+
+	return preflighter.Preflight(ctx, role)
+}
+
+// missingFieldsCheck verifies the target can list the fields that absent
+// update fields are nulled for
+func missingFieldsCheck(ctx context.Context, target connectors.Connector) connectors.CheckResult {
+	check := connectors.CheckResult{Name: "missing_fields", Status: connectors.CheckPassed}
+	if _, ok := introspect(ctx, target); !ok {
+		check.Status = connectors.CheckFailed
+		check.Message = "missing_fields: null requires the target schema, which is not available"
+		check.Remedy = "use missing_fields: ignore or a target connector that reports its schema"
+	}
+	return check
+}
+
+// bootstrapCheck verifies the target can create missing destination objects
+func bootstrapCheck(target connectors.Connector) connectors.CheckResult {
+	check := connectors.CheckResult{Name: "target_bootstrap", Status: connectors.CheckPassed}
+	if _, ok := target.(connectors.Bootstrapper); !ok {
+		check.Status = connectors.CheckFailed
+		check.Message = "bootstrap: true requires a target that can create its destination objects"
+		check.Remedy = "create the destination objects manually and remove bootstrap: true"
+	}
+	return check
+}
+
+// ddlCheck verifies the source captures schema changes and, unless they are
+// only recorded, that the target can apply them
+func ddlCheck(p *registry.Pipeline, source, target connectors.Connector) connectors.CheckResult {
+	check := connectors.CheckResult{Name: "ddl_capture", Status: connectors.CheckPassed}
+	if _, ok := source.(connectors.DDLSource); !ok {
+		check.Status = connectors.CheckSkipped
+		check.Message = "source does not capture schema changes"
+		return check
+	}
+	switch p.DDL.Policy {
+	case registry.DDLIgnore, registry.DDLPropagate, registry.DDLApprove:
+	default:
+		check.Status = connectors.CheckFailed
+		check.Message = fmt.Sprintf("unknown ddl policy %q", p.DDL.Policy)
+		check.Remedy = "use ddl policy ignore, propagate or approve"
+		return check
+	}
+	if _, ok := target.(connectors.DDLApplier); !ok && p.DDL.Policy != registry.DDLIgnore {
+		check.Status = connectors.CheckFailed
+		check.Message = fmt.Sprintf("ddl policy %s requires a target that applies schema changes", p.DDL.Policy)
+		check.Remedy = "use ddl policy ignore or a SQL target that supports schema changes"
+	}
+	return check
+}
+
+// schemaCheck verifies the source schema can be written to the target
+// schema; warnings such as narrower types do not fail the check
+func schemaCheck(ctx context.Context, source, target connectors.Connector) connectors.CheckResult {
+	check := connectors.CheckResult{Name: "schema_compatibility", Status: connectors.CheckSkipped}
+
+	sourceSchema, ok := introspect(ctx, source)
+	if !ok {
+		check.Message = "source schema not available"
+		return check
+	}
+	targetSchema, ok := introspect(ctx, target)
+	if !ok {
+		check.Message = "target schema not available"
+		return check
+	}
+
+	var missing, failed []string
+	for _, issue := range compareSchemas(sourceSchema, targetSchema) {
+		switch {
+		case issue.Kind == IssueMissingColumn:
+			missing = append(missing, issue.Field)
+		case issue.Severity == SeverityError:
+			failed = append(failed, issue.Message)
+		}
+	}
+
+	check.Status = connectors.CheckPassed
+	switch {
+	case len(missing) > 0:
+		check.Status = connectors.CheckFailed
+		check.Message = fmt.Sprintf("target is missing fields %v", missing)
+		check.Remedy = "add the missing columns to the target or drop them with a transform"
+	case len(failed) > 0:
+		check.Status = connectors.CheckFailed
+		check.Message = strings.Join(failed, "; ")
+		check.Remedy = "align the target column types and nullability, or convert the fields with a transform; see the schema compatibility report"
+	}
+	return check
+}
+
+// introspect returns the schema of a connector when it can provide one
+func introspect(ctx context.Context, c connectors.Connector) (*connectors.Schema, bool) {
+	provider, ok := c.(connectors.SchemaProvider)
+	if !ok {
+		return nil, false
+	}
+
+	schema, err := provider.Schema(ctx)
+	if err != nil || schema == nil {
+		return nil, false
+	}
+	return schema, true
+}
+
+// diskCheck verifies the state directory has room for checkpoints and spool
+func (e *Engine) diskCheck(p *registry.Pipeline) connectors.CheckResult {
+	check := connectors.CheckResult{Name: "disk_space", Status: connectors.CheckPassed}
+
+	free, err := diskFree(e.store.Dir())
+	if err != nil {
+		check.Status = connectors.CheckSkipped
+		check.Message = err.Error()
+		return check
+	}
+
+	required := uint64(registry.DefaultMinFreeBytes)
+	if p.Preflight != nil && p.Preflight.MinFreeBytes > 0 {
+		required = uint64(p.Preflight.MinFreeBytes)
+	}
+	if free < required {
+		check.Status = connectors.CheckFailed
+		check.Message = fmt.Sprintf("only %d bytes free in %s, %d required", free, e.store.Dir(), required)
+		check.Remedy = "free space on the state volume or enlarge it"
+	}
+	return check
+}
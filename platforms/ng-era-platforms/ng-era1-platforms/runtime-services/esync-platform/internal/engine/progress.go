@@ -0,0 +1,259 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: run-progress
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Long-Running Run Progress Reporting
+ */
+
+package engine
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+)
+
+// Progress reports how far an in-flight run has come
+//
+// Frozen: public as engine.Progress
+type Progress struct {
+	RecordsDone    int64     `json:"records_done"`
+	EstimatedTotal int64     `json:"estimated_total,omitempty"`
+	Percent        float64   `json:"percent,omitempty"`
+	ETA            time.Time `json:"eta,omitempty"`
+	CurrentChunk   string    `json:"current_chunk,omitempty"`
+	// PausedUntil is set while a backfill waits for its next budget period
+	PausedUntil time.Time `json:"paused_until,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// progressTracker updates the progress of an active run. A nil tracker
+// ignores all updates.
+type progressTracker struct {
+	e   *Engine
+	run *Run
+	// fixture is the replay fixture recorded by the run, if requested
+	fixture *Fixture
+}
+
+// track registers run as the active run of its pipeline, cancelled by
+// cancel when the watchdog stops it
+func (e *Engine) track(run *Run, cancel context.CancelCauseFunc) *progressTracker {
+	e.mu.Lock()
+	e.active[run.PipelineID] = run
+	e.cancels[run.PipelineID] = cancel
+	e.mu.Unlock()
+	e.publish(EventRunStarted, run.PipelineID, e.CurrentRun(run.PipelineID), "")
+
+	return &progressTracker{e: e, run: run}
+}
+
+// CurrentRun returns a snapshot of the in-flight run of a pipeline
+func (e *Engine) CurrentRun(pipelineID string) *Run {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return snapshot(e.active[pipelineID])
+}
+
+// LastRun returns a snapshot of the last finished run of a pipeline
+func (e *Engine) LastRun(pipelineID string) *Run {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	history := e.history[pipelineID]
+	if len(history) == 0 {
+		return nil
+	}
+	return snapshot(history[len(history)-1])
+}
+
+// Runs returns snapshots of the recent finished runs of a pipeline, newest
+// first
+func (e *Engine) Runs(pipelineID string) []*Run {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	history := e.history[pipelineID]
+	runs := make([]*Run, 0, len(history))
+	for i := len(history) - 1; i >= 0; i-- {
+		runs = append(runs, snapshot(history[i]))
+	}
+	return runs
+}
+
+// snapshot copies a run so callers can read it without holding the lock
+func snapshot(run *Run) *Run {
+	if run == nil {
+		return nil
+	}
+
+	out := *run
+	if run.Progress != nil {
+		progress := *run.Progress
+		out.Progress = &progress
+	}
+	if run.Checkpoint != nil {
+		checkpoint := *run.Checkpoint
+		out.Checkpoint = &checkpoint
+	}
+	if run.Verification != nil {
+		verification := *run.Verification
+		verification.Examples = append([]string(nil), run.Verification.Examples...)
+		out.Verification = &verification
+	}
+	if run.Schema != nil {
+		schema := *run.Schema
+		out.Schema = &schema
+	}
+	if run.Cleanup != nil {
+		cleanup := *run.Cleanup
+		cleanup.Keys = append([]string(nil), run.Cleanup.Keys...)
+		out.Cleanup = &cleanup
+	}
+	out.Errors = append([]ErrorGroup(nil), run.Errors...)
+	return &out
+}
+
+// estimate asks an Estimator source for the expected record count
+func (t *progressTracker) estimate(ctx context.Context, source connectors.Connector) {
+	estimator, ok := source.(connectors.Estimator)
+	if t == nil || !ok {
+		return
+	}
+
+	total, err := estimator.EstimateTotal(ctx)
+	if err != nil {
+		log.Printf("[Engine] Failed to estimate total for pipeline %s: %v", t.run.PipelineID, err)
+		return
+	}
+	t.update(func(p *Progress) { p.EstimatedTotal = total })
+}
+
+// discover raises the estimate once the actual change count is known
+func (t *progressTracker) discover(total int64) {
+	t.update(func(p *Progress) {
+		if p.EstimatedTotal < p.RecordsDone+total {
+			p.EstimatedTotal = p.RecordsDone + total
+		}
+	})
+}
+
+// advance records n applied records in the given chunk
+func (t *progressTracker) advance(n int, chunk string) {
+	t.update(func(p *Progress) {
+		p.RecordsDone += int64(n)
+		p.CurrentChunk = chunk
+	})
+}
+
+// count adds listed, filtered and invalid records to the run totals
+func (t *progressTracker) count(listed, filtered, invalid int) {
+	if t == nil {
+		return
+	}
+
+	t.e.mu.Lock()
+	defer t.e.mu.Unlock()
+
+	t.run.Listed += listed
+	t.run.Filtered += filtered
+	t.run.Invalid += invalid
+}
+
+// moved records the checkpoint movement of the run, keeping the first
+// starting position across passes
+func (t *progressTracker) moved(from, to *connectors.Checkpoint) {
+	if t == nil {
+		return
+	}
+
+	t.e.mu.Lock()
+	defer t.e.mu.Unlock()
+
+	if t.run.Checkpoint == nil {
+		t.run.Checkpoint = &CheckpointMove{}
+		if from != nil {
+			t.run.Checkpoint.From = from.Position
+		}
+	}
+	t.run.Checkpoint.To = to.Position
+}
+
+// verified updates the verification result of the run
+func (t *progressTracker) verified(fn func(*Verification)) {
+	t.e.mu.Lock()
+	defer t.e.mu.Unlock()
+
+	if t.run.Verification == nil {
+		t.run.Verification = &Verification{}
+	}
+	fn(t.run.Verification)
+}
+
+// pause marks the run as paused until the given time, or resumed when it is
+// zero
+func (t *progressTracker) pause(until time.Time) {
+	t.update(func(p *Progress) {
+		p.PausedUntil = until
+	})
+}
+
+// finish moves the run from active to the run history
+func (t *progressTracker) finish() {
+	if t == nil {
+		return
+	}
+
+	t.e.mu.Lock()
+	delete(t.e.active, t.run.PipelineID)
+	delete(t.e.cancels, t.run.PipelineID)
+	history := append(t.e.history[t.run.PipelineID], t.run)
+	if size := t.e.historySize(); len(history) > size {
+		history = history[len(history)-size:]
+	}
+	t.e.history[t.run.PipelineID] = history
+	t.e.mu.Unlock()
+	t.e.publish(EventRunFinished, t.run.PipelineID, t.e.LastRun(t.run.PipelineID), "")
+
+	t.e.monitor.ClearProgress(t.run.PipelineID)
+}
+
+// update applies fn to the run progress, recomputes derived fields and
+// publishes them as metrics
+func (t *progressTracker) update(fn func(*Progress)) {
+	if t == nil {
+		return
+	}
+
+	t.e.mu.Lock()
+	p := t.run.Progress
+	fn(p)
+
+	now := time.Now().UTC()
+	p.UpdatedAt = now
+	if p.EstimatedTotal > 0 {
+		p.Percent = 100 * float64(p.RecordsDone) / float64(p.EstimatedTotal)
+	}
+	var eta time.Duration
+	if elapsed := now.Sub(t.run.StartedAt); p.RecordsDone > 0 && p.EstimatedTotal > p.RecordsDone {
+		rate := float64(p.RecordsDone) / elapsed.Seconds()
+		eta = time.Duration(float64(p.EstimatedTotal-p.RecordsDone) / rate * float64(time.Second))
+		p.ETA = now.Add(eta)
+	}
+	done, total := p.RecordsDone, p.EstimatedTotal
+	t.e.mu.Unlock()
+
+	t.e.monitor.RecordProgress(t.run.PipelineID, done, total, eta)
+}
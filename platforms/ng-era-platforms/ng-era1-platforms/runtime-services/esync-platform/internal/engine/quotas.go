@@ -0,0 +1,211 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: namespace-quotas
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Namespace Scheduling Quotas
+ */
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// Quota bounds what the pipelines of one namespace use at a time
+type Quota struct {
+	// MaxConcurrentRuns caps the runs and backfills in progress across the
+	// namespace; zero means unlimited
+	MaxConcurrentRuns int `yaml:"max_concurrent_runs" json:"max_concurrent_runs,omitempty"`
+	// MaxWorkers caps the apply workers writing across the runs of the
+	// namespace; zero means unlimited
+	MaxWorkers int `yaml:"max_workers" json:"max_workers,omitempty"`
+}
+
+// QuotaConfig is a namespace quota file:
+//
+//	default:
+//	  max_concurrent_runs: 4
+//	  max_workers: 8
+//	namespaces:
+//	  payments:
+//	    max_concurrent_runs: 10
+//	    max_workers: 32
+//
+// Every namespace not listed gets a quota of its own with the default
+// bounds. Pipelines without a namespace are not bounded.
+type QuotaConfig struct {
+	Default    *Quota           `yaml:"default,omitempty"`
+	Namespaces map[string]Quota `yaml:"namespaces"`
+}
+
+// LoadQuotas reads a namespace quota file
+func LoadQuotas(path string) (*QuotaConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read namespace quotas: %w", err)
+	}
+
+	var config QuotaConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse namespace quotas %s: %w", path, err)
+	}
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("invalid namespace quotas %s: %w", path, err)
+	}
+	return &config, nil
+}
+
+// validate rejects negative bounds
+func (c *QuotaConfig) validate() error {
+	if c.Default != nil {
+		if err := c.Default.validate(); err != nil {
+			return fmt.Errorf("default quota: %w", err)
+		}
+	}
+	for name, q := range c.Namespaces {
+		if err := q.validate(); err != nil {
+			return fmt.Errorf("namespace %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// validate rejects negative bounds
+func (q Quota) validate() error {
+	if q.MaxConcurrentRuns < 0 || q.MaxWorkers < 0 {
+		return fmt.Errorf("max_concurrent_runs and max_workers must not be negative")
+	}
+	return nil
+}
+
+// namespaceQuota holds the run and worker slots of a namespace; a nil
+// channel leaves that resource unbounded
+type namespaceQuota struct {
+	name    string
+	runs    chan struct{}
+	workers chan struct{}
+}
+
+// SetQuotas bounds the runs and apply workers of each namespace. Runs in
+// progress keep the slots of the previous quotas until they end.
+func (e *Engine) SetQuotas(config *QuotaConfig) error {
+	if config != nil {
+		if err := config.validate(); err != nil {
+			return err
+		}
+	}
+
+	e.quotasMu.Lock()
+	defer e.quotasMu.Unlock()
+
+	e.quotaConfig = config
+	e.quotas = make(map[string]*namespaceQuota)
+	return nil
+}
+
+// quotaFor returns the quota of the pipeline's namespace, or nil when the
+// pipeline is not bounded
+func (e *Engine) quotaFor(p *registry.Pipeline) *namespaceQuota {
+	if p.Namespace == "" {
+		return nil
+	}
+
+	e.quotasMu.Lock()
+	defer e.quotasMu.Unlock()
+
+	if e.quotaConfig == nil {
+		return nil
+	}
+	if q, exists := e.quotas[p.Namespace]; exists {
+		return q
+	}
+	bounds, listed := e.quotaConfig.Namespaces[p.Namespace]
+	if !listed {
+		if e.quotaConfig.Default == nil {
+			e.quotas[p.Namespace] = nil
+			return nil
+		}
+		bounds = *e.quotaConfig.Default
+	}
+
+	q := &namespaceQuota{name: p.Namespace}
+	if bounds.MaxConcurrentRuns > 0 {
+		q.runs = make(chan struct{}, bounds.MaxConcurrentRuns)
+	}
+	if bounds.MaxWorkers > 0 {
+		q.workers = make(chan struct{}, bounds.MaxWorkers)
+	}
+	e.quotas[p.Namespace] = q
+	return q
+}
+
+// acquireRun takes a run slot of the pipeline's namespace, waiting while
+// the namespace is at its quota, and returns the function releasing it
+func (e *Engine) acquireRun(ctx context.Context, p *registry.Pipeline) (func(), error) {
+	q := e.quotaFor(p)
+	if q == nil || q.runs == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case q.runs <- struct{}{}:
+	default:
+		log.Printf("[Engine] Pipeline %s waits for a run slot of namespace %s", p.ID, q.name)
+		e.monitor.RecordNamespaceWait(q.name, "runs")
+		select {
+		case q.runs <- struct{}{}:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for a run slot of namespace %s: %w", q.name, ctx.Err())
+		}
+	}
+	e.publishQuota(q)
+	return func() {
+		<-q.runs
+		e.publishQuota(q)
+	}, nil
+}
+
+// acquireWorker takes a worker slot of the namespace, waiting while the
+// namespace is at its quota, and returns the function releasing it
+func (e *Engine) acquireWorker(ctx context.Context, q *namespaceQuota) (func(), error) {
+	if q == nil || q.workers == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case q.workers <- struct{}{}:
+	default:
+		e.monitor.RecordNamespaceWait(q.name, "workers")
+		select {
+		case q.workers <- struct{}{}:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for a worker slot of namespace %s: %w", q.name, ctx.Err())
+		}
+	}
+	e.publishQuota(q)
+	return func() {
+		<-q.workers
+		e.publishQuota(q)
+	}, nil
+}
+
+// publishQuota exports the slots the namespace holds
+func (e *Engine) publishQuota(q *namespaceQuota) {
+	e.monitor.RecordNamespaceUsage(q.name, len(q.runs), len(q.workers))
+}
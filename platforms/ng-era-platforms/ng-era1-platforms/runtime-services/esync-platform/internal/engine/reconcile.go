@@ -0,0 +1,164 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: sync-reconciliation
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Source/Target Reconciliation
+ */
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// maxReportedKeys bounds the key samples included in a reconcile report
+const maxReportedKeys = 100
+
+// Reconcile methods
+const (
+	// ReconcileDigest compares key sets by exchanging hash range digests
+	ReconcileDigest = "digest"
+	// ReconcileFull compares full key dumps of source and target
+	ReconcileFull = "full"
+)
+
+// ReconcileReport summarizes differences between source and target key sets
+type ReconcileReport struct {
+	SourceKeys int      `json:"source_keys"`
+	TargetKeys int      `json:"target_keys"`
+	Missing    []string `json:"missing,omitempty"`
+	Extra      []string `json:"extra,omitempty"`
+	Method     string   `json:"method,omitempty"`
+	// DigestRanges and KeysListed count the hash ranges compared and the
+	// keys transferred by a digest reconciliation
+	DigestRanges int `json:"digest_ranges,omitempty"`
+	KeysListed   int `json:"keys_listed,omitempty"`
+}
+
+// Consistent reports whether source and target hold the same keys
+func (r *ReconcileReport) Consistent() bool {
+	return len(r.Missing) == 0 && len(r.Extra) == 0
+}
+
+// Reconcile compares the live keys of the source and target of a pipeline,
+// by digest exchange when both connectors can digest their keys. Source
+// keys of key-mapped pipelines are translated to the target IDs written
+// for them, so those pipelines always compare full key lists.
+func (e *Engine) Reconcile(ctx context.Context, pipelineID string) (*ReconcileReport, error) {
+	p, err := e.registry.GetByID(pipelineID)
+	if err != nil {
+		return nil, err
+	}
+
+	source, target, err := e.Connect(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys *keyMap
+	if p.KeyMapping != nil {
+		if keys, err = e.keyMap(p.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if s, t, ok := digesters(source, target); ok && keys == nil {
+		diff, err := diffKeys(ctx, s, t)
+		if err == nil {
+			return &ReconcileReport{
+				SourceKeys:   int(diff.SourceKeys),
+				TargetKeys:   int(diff.TargetKeys),
+				Missing:      keySample(diff.Missing),
+				Extra:        keySample(diff.Extra),
+				Method:       ReconcileDigest,
+				DigestRanges: diff.Ranges,
+				KeysListed:   diff.Listed,
+			}, nil
+		}
+		if !errors.Is(err, connectors.ErrUnsupported) {
+			return nil, err
+		}
+	}
+
+	sourceKeys, err := e.liveKeys(ctx, p, source, keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list source keys: %w", err)
+	}
+
+	targetKeys, err := e.liveKeys(ctx, p, target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list target keys: %w", err)
+	}
+
+	report := &ReconcileReport{
+		SourceKeys: len(sourceKeys),
+		TargetKeys: len(targetKeys),
+		Missing:    difference(sourceKeys, targetKeys),
+		Extra:      difference(targetKeys, sourceKeys),
+		Method:     ReconcileFull,
+	}
+
+	return report, nil
+}
+
+// liveKeys replays the full change history of a connector and returns the
+// keys whose latest operation is not a delete. Keys written under mapped
+// IDs are translated through mapped when non-nil; keys never written keep
+// their source ID.
+func (e *Engine) liveKeys(ctx context.Context, p *registry.Pipeline, c connectors.Connector, mapped *keyMap) (map[string]bool, error) {
+	records, err := c.ListChanges(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	records, _ = splitHeartbeats(records)
+	records = e.normalizeKeys(p, records)
+
+	keys := make(map[string]bool, len(records))
+	for _, record := range records {
+		id := record.ID
+		if mapped != nil {
+			if target, exists := mapped.get(record.Key()); exists {
+				id = target
+			}
+		}
+		keys[id] = record.Operation != connectors.OperationDelete
+	}
+	for id, live := range keys {
+		if !live {
+			delete(keys, id)
+		}
+	}
+
+	return keys, nil
+}
+
+// difference returns up to maxReportedKeys keys present in a but not in b
+func difference(a, b map[string]bool) []string {
+	var out []string
+	for key := range a {
+		if !b[key] {
+			out = append(out, key)
+		}
+	}
+	sort.Strings(out)
+
+	if len(out) > maxReportedKeys {
+		out = out[:maxReportedKeys]
+	}
+	return out
+}
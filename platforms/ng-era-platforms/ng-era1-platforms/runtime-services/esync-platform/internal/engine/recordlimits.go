@@ -0,0 +1,360 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: record-limits
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Record Limits and Dead Letters
+ */
+
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// ErrRecordLimit is returned when a record exceeds the record limits of a
+// pipeline failing on them
+var ErrRecordLimit = errors.New("record exceeds the record limits")
+
+// maxDeadLetters bounds the dead letters kept per pipeline; the oldest are
+// dropped first
+const maxDeadLetters = 100
+
+// DeadLetter is a record set aside because it exceeded the record limits
+// of its pipeline
+type DeadLetter struct {
+	PipelineID string            `json:"pipeline_id"`
+	RunID      string            `json:"run_id,omitempty"`
+	Reason     string            `json:"reason"`
+	Record     connectors.Record `json:"record"`
+	// Redacted lists the fields whose values were replaced by
+	// secrets.Redacted because the pipeline flags them as personal data
+	Redacted []string  `json:"redacted,omitempty"`
+	At       time.Time `json:"at"`
+}
+
+// recordSize is the size of a record as the record limits measure it
+type recordSize struct {
+	bytes  int
+	fields int
+	depth  int
+}
+
+// measureRecord measures the data of a record, encoding it only when the
+// limits bound its size
+func measureRecord(limits *registry.RecordLimitsSpec, data map[string]interface{}) recordSize {
+	var size recordSize
+	for _, v := range data {
+		fields, depth := measureValue(v, 1)
+		size.fields += 1 + fields
+		size.depth = max(size.depth, depth)
+	}
+	if limits.MaxBytes > 0 {
+		size.bytes = encodedSize(data)
+	}
+	return size
+}
+
+// measureValue returns the fields nested in a value at depth and the
+// deepest level it reaches
+func measureValue(v interface{}, depth int) (int, int) {
+	fields, deepest := 0, depth
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for _, child := range v {
+			f, d := measureValue(child, depth+1)
+			fields += 1 + f
+			deepest = max(deepest, d)
+		}
+	case []interface{}:
+		for _, child := range v {
+			f, d := measureValue(child, depth+1)
+			fields += f
+			deepest = max(deepest, d)
+		}
+	}
+	return fields, deepest
+}
+
+// encodedSize returns the size of a value encoded as JSON
+func encodedSize(v interface{}) int {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return 0
+	}
+	return len(data)
+}
+
+// exceeds describes how a record size is over the limits, empty when it is
+// within them
+func exceeds(limits *registry.RecordLimitsSpec, size recordSize) string {
+	var over []string
+	if limits.MaxBytes > 0 && size.bytes > limits.MaxBytes {
+		over = append(over, fmt.Sprintf("%d bytes over the limit of %d", size.bytes, limits.MaxBytes))
+	}
+	if limits.MaxFields > 0 && size.fields > limits.MaxFields {
+		over = append(over, fmt.Sprintf("%d fields over the limit of %d", size.fields, limits.MaxFields))
+	}
+	if limits.MaxDepth > 0 && size.depth > limits.MaxDepth {
+		over = append(over, fmt.Sprintf("nested %d deep over the limit of %d", size.depth, limits.MaxDepth))
+	}
+	return strings.Join(over, ", ")
+}
+
+// limitRecords applies record limits to records, returning those to apply,
+// the messages of the truncated ones and the dead letters of those over the
+// limits. Under the truncate policy only records still over the limits
+// after truncation, such as those whose key fields alone exceed them, are
+// dead letters.
+func limitRecords(limits *registry.RecordLimitsSpec, records []connectors.Record) ([]connectors.Record, []string, []DeadLetter) {
+	var kept []connectors.Record
+	var truncated []string
+	var rejected []DeadLetter
+	for i, r := range records {
+		reason := exceeds(limits, measureRecord(limits, r.Data))
+		if reason == "" {
+			if kept != nil {
+				kept = append(kept, r)
+			}
+			continue
+		}
+		if kept == nil {
+			kept = append(make([]connectors.Record, 0, len(records)), records[:i]...)
+		}
+		if limits.OnExceeded == registry.RecordLimitTruncate {
+			if cut, ok := truncateRecord(limits, r); ok {
+				kept = append(kept, cut)
+				truncated = append(truncated, fmt.Sprintf("record %s truncated: %s", r.ID, reason))
+				continue
+			}
+		}
+		rejected = append(rejected, DeadLetter{Reason: reason, Record: r})
+	}
+	if kept == nil {
+		return records, nil, nil
+	}
+	return kept, truncated, rejected
+}
+
+// truncateRecord cuts the data of a record down to the limits: values
+// nested too deep are dropped, then the fields past the field limit in key
+// order, then the longest strings are shortened and the largest fields
+// dropped until the record fits. Key fields are kept. It reports whether
+// the result is within the limits.
+func truncateRecord(limits *registry.RecordLimitsSpec, r connectors.Record) (connectors.Record, bool) {
+	maxDepth := limits.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = math.MaxInt
+	}
+	names := make([]string, 0, len(r.Data))
+	for name := range r.Data {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		ki, kj := slices.Contains(r.KeyFields, names[i]), slices.Contains(r.KeyFields, names[j])
+		if ki != kj {
+			return ki
+		}
+		return names[i] < names[j]
+	})
+
+	data := make(map[string]interface{}, len(r.Data))
+	fields := 0
+	for _, name := range names {
+		v, ok := pruneDepth(r.Data[name], 1, maxDepth)
+		if !ok {
+			continue
+		}
+		n, _ := measureValue(v, 1)
+		key := slices.Contains(r.KeyFields, name)
+		if !key && limits.MaxFields > 0 && fields+1+n > limits.MaxFields {
+			continue
+		}
+		data[name] = v
+		fields += 1 + n
+	}
+
+	if limits.MaxBytes > 0 {
+		for size := encodedSize(data); size > limits.MaxBytes; size = encodedSize(data) {
+			if !shrinkData(data, r.KeyFields, size-limits.MaxBytes) {
+				break
+			}
+		}
+	}
+
+	r.Data = data
+	return r, exceeds(limits, measureRecord(limits, data)) == ""
+}
+
+// pruneDepth copies a value at depth without the values nested deeper than
+// maxDepth, reporting false when the value itself is too deep
+func pruneDepth(v interface{}, depth, maxDepth int) (interface{}, bool) {
+	if depth > maxDepth {
+		return nil, false
+	}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, child := range v {
+			if c, ok := pruneDepth(child, depth+1, maxDepth); ok {
+				out[k] = c
+			}
+		}
+		return out, true
+	case []interface{}:
+		out := make([]interface{}, 0, len(v))
+		for _, child := range v {
+			if c, ok := pruneDepth(child, depth+1, maxDepth); ok {
+				out = append(out, c)
+			}
+		}
+		return out, true
+	}
+	return v, true
+}
+
+// shrinkData removes about excess bytes from data by shortening its longest
+// string or, when no string is left to shorten, dropping its largest field;
+// key fields are left alone. It returns false when nothing can be removed.
+func shrinkData(data map[string]interface{}, keyFields []string, excess int) bool {
+	longest, largest := "", ""
+	longestLen, largestSize := 0, 0
+	for name, v := range data {
+		if slices.Contains(keyFields, name) {
+			continue
+		}
+		if s, ok := v.(string); ok && len(s) > longestLen {
+			longest, longestLen = name, len(s)
+		}
+		if size := encodedSize(v); largest == "" || size > largestSize {
+			largest, largestSize = name, size
+		}
+	}
+
+	if longestLen > 0 {
+		s := data[longest].(string)
+		data[longest] = strings.ToValidUTF8(s[:max(0, len(s)-excess)], "")
+		return true
+	}
+	if largest != "" {
+		delete(data, largest)
+		return true
+	}
+	return false
+}
+
+// enforceRecordLimits applies the record limits of a pipeline to records
+// read from its source. Records over the limits fail the pass under the
+// fail policy and are otherwise truncated or set aside as dead letters, as
+// the policy says, and reported as errors.
+func (e *Engine) enforceRecordLimits(p *registry.Pipeline, records []connectors.Record, tracker *progressTracker) ([]connectors.Record, error) {
+	limits := p.RecordLimits
+	if limits == nil {
+		return records, nil
+	}
+
+	kept, truncated, rejected := limitRecords(limits, records)
+	for _, message := range truncated {
+		e.groupError(p.ID, "record_limit_truncated", message)
+	}
+	if len(rejected) == 0 {
+		return kept, nil
+	}
+	if limits.OnExceeded == registry.RecordLimitFail {
+		return nil, fmt.Errorf("%w: record %s: %s", ErrRecordLimit, rejected[0].Record.ID, rejected[0].Reason)
+	}
+
+	for _, letter := range rejected {
+		e.recordError(p.ID, "record_limit", fmt.Errorf("record %s: %s", letter.Record.ID, letter.Reason))
+		e.tracef(p.ID, "record %s set aside as a dead letter: %s", letter.Record.ID, letter.Reason)
+	}
+	e.saveDeadLetters(p, tracker, rejected)
+	return kept, nil
+}
+
+// saveDeadLetters appends letters to the dead-letter queue of a pipeline,
+// redacting its personal fields
+func (e *Engine) saveDeadLetters(p *registry.Pipeline, tracker *progressTracker, letters []DeadLetter) {
+	runID := ""
+	if tracker != nil {
+		runID = tracker.run.ID
+	}
+	redacted := e.personalFields(p)
+	now := time.Now().UTC()
+	for i := range letters {
+		letters[i].PipelineID, letters[i].RunID, letters[i].At = p.ID, runID, now
+		if len(redacted) > 0 {
+			letters[i].Record = redactRecords([]connectors.Record{letters[i].Record}, redacted)[0]
+			letters[i].Redacted = redacted
+		}
+	}
+
+	e.deadLettersMu.Lock()
+	defer e.deadLettersMu.Unlock()
+
+	var queue []DeadLetter
+	if _, err := e.store.Load(deadLetterKey(p.ID), &queue); err != nil {
+		log.Printf("[Engine] Failed to load dead letters of %s: %v", p.ID, err)
+		return
+	}
+	queue = append(queue, letters...)
+	if len(queue) > maxDeadLetters {
+		queue = queue[len(queue)-maxDeadLetters:]
+	}
+	if err := e.store.Save(deadLetterKey(p.ID), queue); err != nil {
+		log.Printf("[Engine] Failed to save dead letters of %s: %v", p.ID, err)
+	}
+}
+
+// DeadLetters returns the dead letters of a pipeline, oldest first
+func (e *Engine) DeadLetters(pipelineID string) ([]DeadLetter, error) {
+	e.deadLettersMu.Lock()
+	defer e.deadLettersMu.Unlock()
+
+	queue := []DeadLetter{}
+	if _, err := e.store.Load(deadLetterKey(pipelineID), &queue); err != nil {
+		return nil, err
+	}
+	return queue, nil
+}
+
+// ClearDeadLetters empties the dead-letter queue of a pipeline and returns
+// the letters it held, so a client can drain it without losing letters
+// added in between
+func (e *Engine) ClearDeadLetters(pipelineID string) ([]DeadLetter, error) {
+	e.deadLettersMu.Lock()
+	defer e.deadLettersMu.Unlock()
+
+	queue := []DeadLetter{}
+	if _, err := e.store.Load(deadLetterKey(pipelineID), &queue); err != nil {
+		return nil, err
+	}
+	if err := e.store.Delete(deadLetterKey(pipelineID)); err != nil {
+		return nil, err
+	}
+	return queue, nil
+}
+
+// deadLetterKey is the state key of the dead-letter queue of a pipeline
+func deadLetterKey(pipelineID string) string {
+	return "deadletters/" + pipelineID
+}
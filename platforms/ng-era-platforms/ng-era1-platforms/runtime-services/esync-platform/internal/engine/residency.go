@@ -0,0 +1,159 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: data-residency
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Data Residency Enforcement
+ */
+
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// ErrResidency is returned when a run would move data out of the regions
+// its residency policy allows
+var ErrResidency = errors.New("data residency policy violated")
+
+// residencyViolations lists the targets of a pipeline outside the regions
+// data from its source may move to. Pipelines whose source has no region
+// are unrestricted. Connections resolve from one registry snapshot, so a
+// reload cannot mix regions of two generations.
+func (e *Engine) residencyViolations(p *registry.Pipeline) (string, []string, error) {
+	reg := e.registry.Snapshot()
+	source, err := reg.ResolveConnector(p.Source)
+	if err != nil {
+		return "", nil, err
+	}
+	if source.Region == "" {
+		return "", nil, nil
+	}
+
+	allowed := map[string]bool{source.Region: true}
+	if p.Residency != nil {
+		for _, region := range p.Residency.Allow {
+			allowed[region] = true
+		}
+	}
+
+	var violations []string
+	check := func(role string, spec registry.ConnectorSpec) error {
+		target, err := reg.ResolveConnector(spec)
+		if err != nil {
+			return err
+		}
+		switch {
+		case target.Region == "":
+			violations = append(violations, fmt.Sprintf("%s has no region", role))
+		case !allowed[target.Region]:
+			violations = append(violations, fmt.Sprintf("%s is in %s", role, target.Region))
+		}
+		return nil
+	}
+	if err := check("target", p.Target); err != nil {
+		return "", nil, err
+	}
+	for _, route := range p.Routes {
+		if err := check("route "+route.Table, route.Target); err != nil {
+			return "", nil, err
+		}
+	}
+	return source.Region, violations, nil
+}
+
+// residencyPolicy returns the policy of a pipeline, enforce unless it
+// opts into warnings
+func residencyPolicy(p *registry.Pipeline) string {
+	if p.Residency != nil && p.Residency.Policy != "" {
+		return p.Residency.Policy
+	}
+	return registry.DefaultResidency
+}
+
+// checkResidency applies the residency policy before data moves: enforce
+// refuses the run, warn logs and lets it proceed. Every refusal is audited,
+// other decisions whenever they change for a pipeline.
+func (e *Engine) checkResidency(p *registry.Pipeline) error {
+	region, violations, err := e.residencyViolations(p)
+	if err != nil || region == "" {
+		return err
+	}
+
+	entry := AuditEntry{PipelineID: p.ID, Policy: "residency", Decision: AuditAllowed}
+	entry.Detail = fmt.Sprintf("data from %s stays within %s", region, allowedRegions(p, region))
+	if len(violations) > 0 {
+		entry.Decision = AuditDenied
+		if residencyPolicy(p) == registry.ResidencyWarn {
+			entry.Decision = AuditWarned
+		}
+		entry.Detail = fmt.Sprintf("data from %s may only move to %s: %s", region, allowedRegions(p, region), strings.Join(violations, ", "))
+	}
+
+	e.residencyMu.Lock()
+	changed := e.residency[p.ID] != entry.Decision+entry.Detail
+	e.residency[p.ID] = entry.Decision + entry.Detail
+	e.residencyMu.Unlock()
+	if changed || entry.Decision == AuditDenied {
+		e.audit(entry)
+	}
+
+	switch entry.Decision {
+	case AuditDenied:
+		return fmt.Errorf("cannot run %s: %s: %w", p.ID, entry.Detail, ErrResidency)
+	case AuditWarned:
+		if changed {
+			log.Printf("[Engine] Pipeline %s breaks its residency policy: %s", p.ID, entry.Detail)
+		}
+	}
+	return nil
+}
+
+// allowedRegions formats the regions data from region may move to
+func allowedRegions(p *registry.Pipeline, region string) string {
+	regions := []string{region}
+	if p.Residency != nil {
+		for _, r := range p.Residency.Allow {
+			if r != region {
+				regions = append(regions, r)
+			}
+		}
+	}
+	return strings.Join(regions, ", ")
+}
+
+// residencyCheck reports residency violations in the pre-flight report
+func (e *Engine) residencyCheck(p *registry.Pipeline) connectors.CheckResult {
+	check := connectors.CheckResult{Name: "data_residency", Status: connectors.CheckPassed}
+	region, violations, err := e.residencyViolations(p)
+	switch {
+	case err != nil:
+		check.Status = connectors.CheckFailed
+		check.Message = err.Error()
+	case region == "":
+		check.Status = connectors.CheckSkipped
+		check.Message = "source has no residency region"
+	case len(violations) > 0 && residencyPolicy(p) == registry.ResidencyWarn:
+		check.Message = fmt.Sprintf("data from %s leaves its allowed regions: %s", region, strings.Join(violations, ", "))
+		check.Remedy = "tag targets with a region listed in residency.allow"
+	case len(violations) > 0:
+		check.Status = connectors.CheckFailed
+		check.Message = fmt.Sprintf("data from %s would leave its allowed regions: %s", region, strings.Join(violations, ", "))
+		check.Remedy = "tag targets with a region listed in residency.allow, or set residency.policy to warn"
+	}
+	return check
+}
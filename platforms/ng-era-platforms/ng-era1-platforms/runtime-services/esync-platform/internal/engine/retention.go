@@ -0,0 +1,322 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: retention
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Retention and Pruning
+ */
+
+package engine
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// DefaultPruneInterval is how often WatchRetention prunes when the
+// retention sets no interval
+const DefaultPruneInterval = time.Hour
+
+// Retention bounds what the engine keeps. Zero ages and sizes keep
+// everything.
+//
+// Frozen: public as engine.Retention
+type Retention struct {
+	// RunHistory is the number of finished runs kept per pipeline
+	// (default 20)
+	RunHistory int
+	// RunMaxAge drops finished runs older than this from the history
+	RunMaxAge time.Duration
+	// AuditMaxAge and AuditMaxBytes bound the audit log, dropping the
+	// oldest entries first
+	AuditMaxAge   time.Duration
+	AuditMaxBytes int64
+	// ErasureMaxAge removes finished erasure requests, and with them their
+	// compliance reports, once they are older than this
+	ErasureMaxAge time.Duration
+	// FixtureMaxAge removes recorded fixtures older than this
+	FixtureMaxAge time.Duration
+	// Interval is how often WatchRetention prunes
+	Interval time.Duration
+}
+
+// Pruner removes expired data kept outside the engine, such as archived
+// run reports, returning the number of items removed and bytes reclaimed
+type Pruner func(now time.Time) (int, int64, error)
+
+// PruneResult reports what one pruning pass removed of one kind of data
+type PruneResult struct {
+	Kind  string `json:"kind"`
+	Items int    `json:"items"`
+	Bytes int64  `json:"bytes"`
+	Error string `json:"error,omitempty"`
+}
+
+// prunePass is one kind of data pruned by Prune
+type prunePass struct {
+	kind string
+	fn   Pruner
+}
+
+// SetRetention replaces the retention policy
+func (e *Engine) SetRetention(r Retention) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.retention = r
+}
+
+// AddPruner registers a pruner run by every pruning pass under kind
+func (e *Engine) AddPruner(kind string, p Pruner) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.pruners == nil {
+		e.pruners = make(map[string]Pruner)
+	}
+	e.pruners[kind] = p
+}
+
+// historySize returns the number of finished runs kept per pipeline.
+// Callers hold e.mu.
+func (e *Engine) historySize() int {
+	if e.retention.RunHistory > 0 {
+		return e.retention.RunHistory
+	}
+	return runHistorySize
+}
+
+// WatchRetention prunes expired data each retention interval until ctx is
+// cancelled
+func (e *Engine) WatchRetention(ctx context.Context) {
+	e.mu.RLock()
+	interval := e.retention.Interval
+	e.mu.RUnlock()
+	if interval <= 0 {
+		interval = DefaultPruneInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		e.Prune(time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Prune removes run history, audit entries, erasure requests, fixtures,
+// idempotent runs and data of registered pruners past their retention,
+// recording what was reclaimed
+func (e *Engine) Prune(now time.Time) []PruneResult {
+	e.mu.RLock()
+	r := e.retention
+	kinds := make([]string, 0, len(e.pruners))
+	for kind := range e.pruners {
+		kinds = append(kinds, kind)
+	}
+	pruners := e.pruners
+	e.mu.RUnlock()
+	sort.Strings(kinds)
+
+	passes := []prunePass{
+		{"runs", e.pruneRuns},
+		{"audit", func(now time.Time) (int, int64, error) { return e.pruneAudit(now, r.AuditMaxAge, r.AuditMaxBytes) }},
+		{"erasures", func(now time.Time) (int, int64, error) { return e.pruneErasures(now, r.ErasureMaxAge) }},
+		{"fixtures", func(now time.Time) (int, int64, error) { return e.pruneFixtures(now, r.FixtureMaxAge) }},
+		{"idempotency", e.pruneIdempotency},
+	}
+	for _, kind := range kinds {
+		passes = append(passes, prunePass{kind, pruners[kind]})
+	}
+
+	results := make([]PruneResult, 0, len(passes))
+	for _, pass := range passes {
+		items, reclaimed, err := pass.fn(now)
+		result := PruneResult{Kind: pass.kind, Items: items, Bytes: reclaimed}
+		if err != nil {
+			result.Error = err.Error()
+			log.Printf("[Engine] Failed to prune %s: %v", pass.kind, err)
+		}
+		if items > 0 {
+			log.Printf("[Engine] Pruned %d %s, reclaiming %d bytes", items, pass.kind, reclaimed)
+		}
+		e.monitor.RecordPruned(pass.kind, items, reclaimed)
+		results = append(results, result)
+	}
+	return results
+}
+
+// pruneRuns drops finished runs beyond the history size or older than
+// RunMaxAge. Runs live in memory; the reclaimed bytes are their encoded
+// size.
+func (e *Engine) pruneRuns(now time.Time) (int, int64, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	size, maxAge := e.historySize(), e.retention.RunMaxAge
+	items, reclaimed := 0, int64(0)
+	for id, history := range e.history {
+		keep := 0
+		for i, run := range history {
+			expired := maxAge > 0 && now.Sub(run.FinishedAt) > maxAge
+			if !expired && len(history)-i <= size {
+				history[keep] = run
+				keep++
+				continue
+			}
+			if data, err := json.Marshal(run); err == nil {
+				reclaimed += int64(len(data))
+			}
+			items++
+		}
+		for i := keep; i < len(history); i++ {
+			history[i] = nil
+		}
+		e.history[id] = history[:keep]
+	}
+	return items, reclaimed, nil
+}
+
+// pruneAudit rewrites the audit log without entries older than maxAge,
+// then drops the oldest entries until it fits maxBytes
+func (e *Engine) pruneAudit(now time.Time, maxAge time.Duration, maxBytes int64) (int, int64, error) {
+	if maxAge <= 0 && maxBytes <= 0 {
+		return 0, 0, nil
+	}
+
+	e.auditMu.Lock()
+	defer e.auditMu.Unlock()
+
+	path := filepath.Join(e.store.Dir(), auditFile)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	var lines [][]byte
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var entry AuditEntry
+		if maxAge > 0 && json.Unmarshal(scanner.Bytes(), &entry) == nil && now.Sub(entry.Time) > maxAge {
+			continue
+		}
+		lines = append(lines, append([]byte(nil), scanner.Bytes()...))
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, 0, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	total := int64(0)
+	for _, line := range lines {
+		total += int64(len(line)) + 1
+	}
+	for maxBytes > 0 && total > maxBytes && len(lines) > 0 {
+		total -= int64(len(lines[0])) + 1
+		lines = lines[1:]
+	}
+
+	kept := bytes.Join(lines, []byte("\n"))
+	if len(lines) > 0 {
+		kept = append(kept, '\n')
+	}
+	if len(kept) == len(data) {
+		return 0, 0, nil
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, kept, 0o640); err != nil {
+		return 0, 0, fmt.Errorf("failed to write audit log: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return 0, 0, fmt.Errorf("failed to replace audit log: %w", err)
+	}
+	removed := bytes.Count(data, []byte("\n")) - len(lines)
+	return removed, int64(len(data) - len(kept)), nil
+}
+
+// pruneErasures removes finished erasure requests completed more than
+// maxAge ago
+func (e *Engine) pruneErasures(now time.Time, maxAge time.Duration) (int, int64, error) {
+	if maxAge <= 0 {
+		return 0, 0, nil
+	}
+
+	requests, err := e.Erasures()
+	if err != nil {
+		return 0, 0, err
+	}
+	items, reclaimed := 0, int64(0)
+	for _, req := range requests {
+		if req.Status == ErasurePending || now.Sub(req.CompletedAt) <= maxAge {
+			continue
+		}
+		size, err := e.store.Size("erasure/" + req.ID)
+		if err == nil {
+			err = e.store.Delete("erasure/" + req.ID)
+		}
+		if err != nil {
+			return items, reclaimed, err
+		}
+		items++
+		reclaimed += size
+	}
+	return items, reclaimed, nil
+}
+
+// pruneFixtures removes fixtures recorded more than maxAge ago
+func (e *Engine) pruneFixtures(now time.Time, maxAge time.Duration) (int, int64, error) {
+	if maxAge <= 0 {
+		return 0, 0, nil
+	}
+
+	keys, err := e.store.Keys("fixtures")
+	if err != nil {
+		return 0, 0, err
+	}
+	items, reclaimed := 0, int64(0)
+	for _, key := range keys {
+		var f Fixture
+		if _, err := e.store.Load(key, &f); err != nil {
+			return items, reclaimed, err
+		}
+		if now.Sub(f.RecordedAt) <= maxAge {
+			continue
+		}
+		size, err := e.store.Size(key)
+		if err == nil {
+			err = e.store.Delete(key)
+		}
+		if err != nil {
+			return items, reclaimed, err
+		}
+		items++
+		reclaimed += size
+	}
+	return items, reclaimed, nil
+}
@@ -0,0 +1,342 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: staged-rollout
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Staged Rollout
+ */
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"reflect"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// Rollout phases
+const (
+	// RolloutStable applies one definition to all keys
+	RolloutStable = "stable"
+	// RolloutSoaking applies the changed definition to a share of the keys
+	RolloutSoaking = "soaking"
+	// RolloutRolledBack applies the stable definition to all keys until the
+	// definition changes again or the rollout is promoted
+	RolloutRolledBack = "rolled_back"
+)
+
+// EventRollout is published when a rollout starts, is promoted or is
+// rolled back
+const EventRollout = "rollout"
+
+// ErrNoRollout is returned when acting on a pipeline with no rollout in
+// progress
+var ErrNoRollout = errors.New("no rollout in progress")
+
+// Rollout is the staged rollout of a changed pipeline definition
+type Rollout struct {
+	PipelineID string `json:"pipeline_id"`
+	Phase      string `json:"phase"`
+	// Stable is the digest of the definition applied to the keys outside
+	// the rollout and Candidate the digest of the changed one
+	Stable    string `json:"stable"`
+	Candidate string `json:"candidate,omitempty"`
+	// Percent is the share of keys the candidate applies to
+	Percent   int       `json:"percent,omitempty"`
+	StartedAt time.Time `json:"started_at,omitempty"`
+	SoakUntil time.Time `json:"soak_until,omitempty"`
+	// Passes and Records count what the candidate applied
+	Passes  int `json:"passes"`
+	Records int `json:"records"`
+	// Reason tells why the last rollout was promoted or rolled back
+	Reason     string    `json:"reason,omitempty"`
+	FinishedAt time.Time `json:"finished_at,omitempty"`
+}
+
+// rolloutState is the stored rollout of a pipeline with the stable
+// definition. The definition is stored as JSON, without secrets such as
+// trigger secrets, which applying records does not use.
+type rolloutState struct {
+	Rollout
+	Definition *registry.Pipeline `json:"definition"`
+}
+
+// rolloutDigest identifies a definition apart from its rollout settings,
+// so tuning a rollout does not start another
+func rolloutDigest(p *registry.Pipeline) (string, error) {
+	staged := *p
+	staged.Rollout = nil
+	return definitionDigest(&staged)
+}
+
+// stageable reports whether a change from one definition to another can
+// be staged; a changed mode, source or target applies to all keys at once
+func stageable(from, to *registry.Pipeline) bool {
+	return from.Mode == to.Mode && reflect.DeepEqual(from.Source, to.Source) && reflect.DeepEqual(from.Target, to.Target)
+}
+
+// rolloutFor tracks the definition of a pipeline and returns its rollout
+// when one is soaking or rolled back. A changed definition of a pipeline
+// with a rollout block starts a rollout; otherwise it becomes the stable
+// one. A soaking rollout whose candidate applied at least once and did not
+// fail by the end of the soak is promoted.
+func (e *Engine) rolloutFor(p *registry.Pipeline, now time.Time) (*rolloutState, error) {
+	digest, err := rolloutDigest(p)
+	if err != nil {
+		return nil, err
+	}
+
+	e.rolloutMu.Lock()
+	defer e.rolloutMu.Unlock()
+
+	var st rolloutState
+	found, err := e.store.Load(rolloutKey(p.ID), &st)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := false
+	switch {
+	case !found:
+		st = rolloutState{Rollout: Rollout{PipelineID: p.ID, Phase: RolloutStable, Stable: digest}, Definition: p}
+	case st.Stable == digest && st.Phase == RolloutStable:
+		return nil, nil
+	case st.Stable == digest:
+		log.Printf("[Engine] Pipeline %s definition reverted to %s, ending its rollout", p.ID, digest[:12])
+		st.Phase, st.Candidate, st.Reason, st.FinishedAt = RolloutStable, "", "definition reverted", now.UTC()
+		st.Definition = p
+		changed = true
+	case p.Rollout == nil || st.Definition == nil || !stageable(st.Definition, p):
+		// Unstaged changes replace the stable definition at once, ending a
+		// rollout of an earlier change
+		if st.Candidate != "" {
+			st.Reason, st.FinishedAt = "definition changed without a rollout", now.UTC()
+			changed = true
+		}
+		st.Phase, st.Stable, st.Candidate, st.Definition = RolloutStable, digest, "", p
+	case st.Candidate != digest:
+		spec, _ := p.Effective()
+		st.Rollout = Rollout{
+			PipelineID: p.ID,
+			Phase:      RolloutSoaking,
+			Stable:     st.Stable,
+			Candidate:  digest,
+			Percent:    spec.Rollout.Percent,
+			StartedAt:  now.UTC(),
+			SoakUntil:  now.Add(time.Duration(spec.Rollout.SoakSeconds) * time.Second).UTC(),
+		}
+		log.Printf("[Engine] Pipeline %s rolling out definition %s to %d%% of keys until %s",
+			p.ID, digest[:12], st.Percent, st.SoakUntil.Format(time.RFC3339))
+		changed = true
+	case st.Phase == RolloutSoaking && !now.Before(st.SoakUntil) && st.Passes > 0:
+		log.Printf("[Engine] Pipeline %s promoting definition %s after a soak of %d passes", p.ID, digest[:12], st.Passes)
+		st.promote(p, "soaked without failures", now)
+		changed = true
+	default:
+		spec, _ := p.Effective()
+		if st.Percent == spec.Rollout.Percent {
+			return &st, nil
+		}
+		st.Percent = spec.Rollout.Percent
+	}
+
+	if err := e.store.Save(rolloutKey(p.ID), &st); err != nil {
+		return nil, err
+	}
+	if changed {
+		e.publishRollout(st.Rollout)
+	}
+	if st.Phase == RolloutStable {
+		return nil, nil
+	}
+	return &st, nil
+}
+
+// promote makes the candidate the stable definition
+func (st *rolloutState) promote(p *registry.Pipeline, reason string, now time.Time) {
+	st.Phase, st.Stable, st.Candidate, st.Definition = RolloutStable, st.Candidate, "", p
+	st.Reason, st.FinishedAt = reason, now.UTC()
+}
+
+// applyStaged applies records with the definitions of a pipeline's
+// rollout: those whose key hashes into the rollout share with the changed
+// definition and the others with the stable one. Records of a key always
+// apply with the same definition, so their order is kept. A failing
+// candidate rolls the rollout back, and the failed pass is retried with
+// the stable definition.
+func (e *Engine) applyStaged(ctx context.Context, p *registry.Pipeline, target connectors.Connector, records []connectors.Record, tracker *progressTracker, label, position string) (int, error) {
+	st, err := e.rolloutFor(p, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	if st == nil {
+		return e.applyRouted(ctx, p, target, records, tracker, label, position)
+	}
+	if st.Phase == RolloutRolledBack {
+		return e.applyRouted(ctx, st.Definition, target, records, tracker, label, position)
+	}
+
+	var stable, candidate []connectors.Record
+	for _, r := range records {
+		if inRollout(r, st.Percent) {
+			candidate = append(candidate, r)
+		} else {
+			stable = append(stable, r)
+		}
+	}
+	applied, err := e.applyRouted(ctx, st.Definition, target, stable, tracker, label, position)
+	if err != nil || len(candidate) == 0 {
+		return applied, err
+	}
+	n, err := e.applyRouted(ctx, p, target, candidate, tracker, label+"rollout:", position)
+	e.rolloutApplied(p.ID, st.Candidate, n, err)
+	return applied + n, err
+}
+
+// rolloutApplied counts a pass of the candidate, rolling the rollout back
+// when it failed
+func (e *Engine) rolloutApplied(pipelineID, candidate string, records int, applyErr error) {
+	e.rolloutMu.Lock()
+	defer e.rolloutMu.Unlock()
+
+	var st rolloutState
+	found, err := e.store.Load(rolloutKey(pipelineID), &st)
+	if err != nil || !found || st.Candidate != candidate || st.Phase != RolloutSoaking {
+		if err != nil {
+			log.Printf("[Engine] Failed to load rollout of %s: %v", pipelineID, err)
+		}
+		return
+	}
+	st.Passes++
+	st.Records += records
+	if applyErr != nil {
+		log.Printf("[Engine] Pipeline %s rolling back definition %s: %v", pipelineID, candidate[:12], applyErr)
+		st.Phase, st.Reason, st.FinishedAt = RolloutRolledBack, applyErr.Error(), time.Now().UTC()
+	}
+	if err := e.store.Save(rolloutKey(pipelineID), &st); err != nil {
+		log.Printf("[Engine] Failed to save rollout of %s: %v", pipelineID, err)
+		return
+	}
+	if applyErr != nil {
+		e.publishRollout(st.Rollout)
+	}
+}
+
+// inRollout reports whether the key of a record hashes into the rollout
+// share
+func inRollout(r connectors.Record, percent int) bool {
+	h := fnv.New32a()
+	h.Write([]byte(r.Key().String()))
+	return int(h.Sum32()%100) < percent
+}
+
+// Rollout returns the rollout state of a pipeline; pipelines that have not
+// run since loading report the stable phase
+func (e *Engine) Rollout(pipelineID string) (*Rollout, error) {
+	p, err := e.registry.GetByID(pipelineID)
+	if err != nil {
+		return nil, err
+	}
+
+	e.rolloutMu.Lock()
+	defer e.rolloutMu.Unlock()
+
+	var st rolloutState
+	found, err := e.store.Load(rolloutKey(p.ID), &st)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		digest, err := rolloutDigest(p)
+		if err != nil {
+			return nil, err
+		}
+		return &Rollout{PipelineID: p.ID, Phase: RolloutStable, Stable: digest}, nil
+	}
+	return &st.Rollout, nil
+}
+
+// PromoteRollout applies the changed definition of a soaking or rolled
+// back rollout to all keys at once
+func (e *Engine) PromoteRollout(pipelineID string) (*Rollout, error) {
+	return e.finishRollout(pipelineID, func(st *rolloutState, p *registry.Pipeline, now time.Time) {
+		st.promote(p, "promoted by operator", now)
+	})
+}
+
+// RollBack applies the stable definition of a soaking rollout to all keys
+// until the definition changes again
+func (e *Engine) RollBack(pipelineID string) (*Rollout, error) {
+	return e.finishRollout(pipelineID, func(st *rolloutState, p *registry.Pipeline, now time.Time) {
+		st.Phase, st.Reason, st.FinishedAt = RolloutRolledBack, "rolled back by operator", now.UTC()
+	})
+}
+
+// finishRollout applies an operator decision to the rollout of the
+// current definition of a pipeline
+func (e *Engine) finishRollout(pipelineID string, decide func(st *rolloutState, p *registry.Pipeline, now time.Time)) (*Rollout, error) {
+	p, err := e.registry.GetByID(pipelineID)
+	if err != nil {
+		return nil, err
+	}
+	digest, err := rolloutDigest(p)
+	if err != nil {
+		return nil, err
+	}
+
+	e.rolloutMu.Lock()
+	var st rolloutState
+	found, err := e.store.Load(rolloutKey(p.ID), &st)
+	if err == nil && (!found || st.Phase == RolloutStable || st.Candidate != digest) {
+		err = fmt.Errorf("pipeline %s: %w", p.ID, ErrNoRollout)
+	}
+	if err == nil {
+		decide(&st, p, time.Now())
+		err = e.store.Save(rolloutKey(p.ID), &st)
+	}
+	e.rolloutMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[Engine] Pipeline %s rollout of definition %s %s", p.ID, digest[:12], st.Reason)
+	e.publishRollout(st.Rollout)
+	return &st.Rollout, nil
+}
+
+// publishRollout sends a rollout event of a pipeline to every subscriber
+func (e *Engine) publishRollout(r Rollout) {
+	paused, err := e.Paused(r.PipelineID)
+	if err != nil {
+		log.Printf("[Engine] Failed to read pause state of pipeline %s: %v", r.PipelineID, err)
+	}
+	e.broadcast(Event{
+		Type:       EventRollout,
+		PipelineID: r.PipelineID,
+		Time:       time.Now().UTC(),
+		Paused:     paused,
+		Running:    e.CurrentRun(r.PipelineID) != nil,
+		Reason:     r.Phase,
+		Rollout:    &r,
+	})
+}
+
+// rolloutKey is the state key of the rollout of a pipeline
+func rolloutKey(pipelineID string) string {
+	return "rollouts/" + pipelineID
+}
@@ -0,0 +1,291 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: run-diff
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Run Diffing
+ */
+
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// Run references resolved by DiffRuns besides run IDs
+const (
+	// RunLatest is the newest finished run
+	RunLatest = "latest"
+	// RunLastGood is the newest succeeded run
+	RunLastGood = "last-good"
+)
+
+// Changes of an error group between two runs
+const (
+	ErrorsNew       = "new"
+	ErrorsResolved  = "resolved"
+	ErrorsIncreased = "increased"
+	ErrorsDecreased = "decreased"
+	ErrorsUnchanged = "unchanged"
+)
+
+// ErrRunNotFound is returned for runs not in the history of a pipeline
+var ErrRunNotFound = errors.New("run not found")
+
+// RunSchema identifies the definition and target schema a run executed
+// against
+//
+// Frozen: public as engine.RunSchema
+type RunSchema struct {
+	// Version is the version the definition declares and Definition the
+	// digest of the definition
+	Version    string `json:"version,omitempty"`
+	Definition string `json:"definition,omitempty"`
+	// DDL is the ID of the last schema change applied to the target before
+	// the run
+	DDL string `json:"ddl,omitempty"`
+}
+
+// RunDiff compares two runs of a pipeline, B against A
+type RunDiff struct {
+	PipelineID string       `json:"pipeline_id"`
+	A          RunSide      `json:"a"`
+	B          RunSide      `json:"b"`
+	Duration   DurationDiff `json:"duration"`
+	// Counts compares records, listed, filtered and invalid, and the
+	// verification and cleanup counts of runs that have them
+	Counts map[string]CountDiff `json:"counts"`
+	// Errors compares the error groups of the runs by fingerprint, new and
+	// increased first
+	Errors []ErrorGroupDiff `json:"errors"`
+	// SchemaChanged is set when the runs executed different definitions or
+	// target schemas; DDL holds the schema changes applied between their
+	// starts
+	SchemaChanged bool        `json:"schema_changed"`
+	DDL           []DDLChange `json:"ddl,omitempty"`
+}
+
+// RunSide is one of the runs compared
+type RunSide struct {
+	ID         string     `json:"id"`
+	Status     string     `json:"status"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt time.Time  `json:"finished_at,omitempty"`
+	Schema     *RunSchema `json:"schema,omitempty"`
+}
+
+// CountDiff is a count in both runs and its change
+type CountDiff struct {
+	A     int `json:"a"`
+	B     int `json:"b"`
+	Delta int `json:"delta"`
+}
+
+// DurationDiff is the duration of both runs in seconds and its change
+type DurationDiff struct {
+	A     float64 `json:"a"`
+	B     float64 `json:"b"`
+	Delta float64 `json:"delta"`
+	// Ratio is B over A, 0 when A took no time
+	Ratio float64 `json:"ratio,omitempty"`
+}
+
+// ErrorGroupDiff is an error group and how often each run raised it
+type ErrorGroupDiff struct {
+	Fingerprint string `json:"fingerprint"`
+	Type        string `json:"type"`
+	Message     string `json:"message"`
+	A           int    `json:"a"`
+	B           int    `json:"b"`
+	Change      string `json:"change"`
+}
+
+// runSchema returns the definition and target schema a run of a pipeline
+// starts against
+func (e *Engine) runSchema(p *registry.Pipeline) *RunSchema {
+	schema := &RunSchema{Version: p.Version}
+	if digest, err := definitionDigest(p); err == nil {
+		schema.Definition = digest
+	}
+	if p.DDL != nil {
+		e.ddlMu.Lock()
+		changes, err := e.loadDDL(p.ID)
+		e.ddlMu.Unlock()
+		if err == nil {
+			var last time.Time
+			for _, c := range changes {
+				if c.Status == DDLApplied && !c.DecidedAt.Before(last) {
+					schema.DDL, last = c.ID, c.DecidedAt
+				}
+			}
+		}
+	}
+	return schema
+}
+
+// findRun returns a finished run of a pipeline by ID, RunLatest or
+// RunLastGood
+func (e *Engine) findRun(pipelineID, ref string) (*Run, error) {
+	for _, run := range e.Runs(pipelineID) {
+		switch {
+		case ref == RunLatest,
+			ref == RunLastGood && run.Status == StatusSucceeded,
+			run.ID == ref:
+			return run, nil
+		}
+	}
+	return nil, fmt.Errorf("%s of %s: %w", ref, pipelineID, ErrRunNotFound)
+}
+
+// DiffRuns compares run b of a pipeline against run a, each given by ID,
+// RunLatest or RunLastGood, such as the latest run against the last good
+// one: their counts, durations, error groups and the schema changes
+// between them
+func (e *Engine) DiffRuns(pipelineID, a, b string) (*RunDiff, error) {
+	if _, err := e.registry.GetByID(pipelineID); err != nil {
+		return nil, err
+	}
+	runA, err := e.findRun(pipelineID, a)
+	if err != nil {
+		return nil, err
+	}
+	runB, err := e.findRun(pipelineID, b)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &RunDiff{
+		PipelineID: pipelineID,
+		A:          runSide(runA),
+		B:          runSide(runB),
+		Counts:     make(map[string]CountDiff),
+		Errors:     diffErrors(runA.Errors, runB.Errors),
+	}
+
+	durA, durB := runDuration(runA).Seconds(), runDuration(runB).Seconds()
+	diff.Duration = DurationDiff{A: durA, B: durB, Delta: durB - durA}
+	if durA > 0 {
+		diff.Duration.Ratio = durB / durA
+	}
+
+	count := func(name string, a, b int) {
+		diff.Counts[name] = CountDiff{A: a, B: b, Delta: b - a}
+	}
+	count("records", runA.Records, runB.Records)
+	count("listed", runA.Listed, runB.Listed)
+	count("filtered", runA.Filtered, runB.Filtered)
+	count("invalid", runA.Invalid, runB.Invalid)
+	if runA.Verification != nil || runB.Verification != nil {
+		var va, vb Verification
+		if runA.Verification != nil {
+			va = *runA.Verification
+		}
+		if runB.Verification != nil {
+			vb = *runB.Verification
+		}
+		count("verify_sampled", va.Sampled, vb.Sampled)
+		count("verify_missing", va.Missing, vb.Missing)
+		count("verify_mismatched", va.Mismatched, vb.Mismatched)
+	}
+	if runA.Cleanup != nil || runB.Cleanup != nil {
+		var ca, cb CleanupResult
+		if runA.Cleanup != nil {
+			ca = *runA.Cleanup
+		}
+		if runB.Cleanup != nil {
+			cb = *runB.Cleanup
+		}
+		count("cleanup_deleted", ca.Deleted, cb.Deleted)
+	}
+
+	if sa, sb := runA.Schema, runB.Schema; sa != nil && sb != nil && *sa != *sb {
+		diff.SchemaChanged = true
+	}
+	from, to := runA.StartedAt, runB.StartedAt
+	if to.Before(from) {
+		from, to = to, from
+	}
+	changes, err := e.DDLChanges(pipelineID)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range changes {
+		if c.Status == DDLApplied && c.DecidedAt.After(from) && !c.DecidedAt.After(to) {
+			diff.DDL = append(diff.DDL, c)
+			diff.SchemaChanged = true
+		}
+	}
+	return diff, nil
+}
+
+// runSide returns what a diff shows of a run
+func runSide(run *Run) RunSide {
+	return RunSide{
+		ID:         run.ID,
+		Status:     run.Status,
+		Error:      run.Error,
+		StartedAt:  run.StartedAt,
+		FinishedAt: run.FinishedAt,
+		Schema:     run.Schema,
+	}
+}
+
+// runDuration returns how long a finished run took
+func runDuration(run *Run) time.Duration {
+	if run.FinishedAt.IsZero() {
+		return 0
+	}
+	return run.FinishedAt.Sub(run.StartedAt)
+}
+
+// diffErrors pairs the error groups of two runs by fingerprint, ordering
+// new groups first, then increased, resolved, decreased and unchanged ones
+func diffErrors(a, b []ErrorGroup) []ErrorGroupDiff {
+	index := make(map[string]int)
+	out := []ErrorGroupDiff{}
+	for _, g := range a {
+		index[g.Fingerprint] = len(out)
+		out = append(out, ErrorGroupDiff{Fingerprint: g.Fingerprint, Type: g.Type, Message: g.Message, A: g.Count})
+	}
+	for _, g := range b {
+		i, ok := index[g.Fingerprint]
+		if !ok {
+			i = len(out)
+			out = append(out, ErrorGroupDiff{Fingerprint: g.Fingerprint, Type: g.Type, Message: g.Message})
+		}
+		out[i].B = g.Count
+	}
+
+	rank := map[string]int{ErrorsNew: 0, ErrorsIncreased: 1, ErrorsResolved: 2, ErrorsDecreased: 3, ErrorsUnchanged: 4}
+	for i := range out {
+		d := &out[i]
+		switch {
+		case d.A == 0:
+			d.Change = ErrorsNew
+		case d.B == 0:
+			d.Change = ErrorsResolved
+		case d.B > d.A:
+			d.Change = ErrorsIncreased
+		case d.B < d.A:
+			d.Change = ErrorsDecreased
+		default:
+			d.Change = ErrorsUnchanged
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return rank[out[i].Change] < rank[out[j].Change] })
+	return out
+}
@@ -0,0 +1,222 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: table-selection
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Table Include/Exclude Patterns and New-Table Policy
+ */
+
+package engine
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// Table states of a multi-table pipeline
+const (
+	TableActive  = "active"
+	TableIgnored = "ignored"
+)
+
+// tableSelection is a compiled table selection
+type tableSelection struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+	// literal holds tables named without wildcards in include or routes,
+	// which are synced whatever the new-table policy
+	literal map[string]bool
+	policy  string
+}
+
+// compileSelection compiles the table patterns of a pipeline
+func compileSelection(p *registry.Pipeline) (*tableSelection, error) {
+	s := &tableSelection{literal: make(map[string]bool), policy: registry.DefaultNewTables}
+	for _, route := range p.Routes {
+		s.literal[route.Table] = true
+	}
+
+	spec := p.Tables
+	switch spec.NewTables {
+	case "":
+	case registry.NewTablesInclude, registry.NewTablesIgnore, registry.NewTablesAlert:
+		s.policy = spec.NewTables
+	default:
+		return nil, fmt.Errorf("unknown new_tables policy %q", spec.NewTables)
+	}
+
+	for _, pattern := range spec.Include {
+		re, err := compilePattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		s.include = append(s.include, re)
+		if !isPattern(pattern) {
+			s.literal[pattern] = true
+		}
+	}
+	for _, pattern := range spec.Exclude {
+		re, err := compilePattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		s.exclude = append(s.exclude, re)
+	}
+	return s, nil
+}
+
+// isPattern reports whether an include entry is a pattern rather than a
+// literal table name
+func isPattern(entry string) bool {
+	return strings.ContainsAny(entry, "*?[/")
+}
+
+// compilePattern compiles a glob, or a regular expression wrapped in slashes
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if len(pattern) >= 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		re, err := regexp.Compile(pattern[1 : len(pattern)-1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid table pattern %s: %w", pattern, err)
+		}
+		return re, nil
+	}
+
+	var expr strings.Builder
+	expr.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			expr.WriteString(".*")
+		case '?':
+			expr.WriteString(".")
+		case '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid table pattern %s: unterminated [", pattern)
+			}
+			class := pattern[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			expr.WriteString("[" + class + "]")
+			i += end
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	expr.WriteString("$")
+
+	re, err := regexp.Compile(expr.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid table pattern %s: %w", pattern, err)
+	}
+	return re, nil
+}
+
+// matches reports whether a table passes the include and exclude patterns
+func (s *tableSelection) matches(table string) bool {
+	for _, re := range s.exclude {
+		if re.MatchString(table) {
+			return false
+		}
+	}
+	if len(s.include) == 0 {
+		return true
+	}
+	for _, re := range s.include {
+		if re.MatchString(table) {
+			return true
+		}
+	}
+	return false
+}
+
+// selectTables drops records of tables the pipeline does not select. The
+// patterns are evaluated on every pass, so tables that appear in the source
+// at runtime are picked up; selected tables first seen after the first pass
+// are handled by the new-table policy and remembered as active or ignored.
+// Records without a table are always kept.
+func (e *Engine) selectTables(p *registry.Pipeline, records []connectors.Record, tracker *progressTracker) ([]connectors.Record, error) {
+	if p.Tables == nil {
+		return records, nil
+	}
+	selection, err := compileSelection(p)
+	if err != nil {
+		return nil, fmt.Errorf("invalid table selection: %w", err)
+	}
+
+	e.tablesMu.Lock()
+	states := make(map[string]*TableState)
+	if _, err := e.store.Load("tables/"+p.ID, &states); err != nil {
+		e.tablesMu.Unlock()
+		return nil, err
+	}
+	firstPass := len(states) == 0
+
+	keep := make(map[string]bool)
+	var discovered []string
+	for _, r := range records {
+		if _, decided := keep[r.Table]; decided || r.Table == "" {
+			continue
+		}
+		st, known := states[r.Table]
+		switch {
+		case !selection.matches(r.Table):
+			keep[r.Table] = false
+		case selection.literal[r.Table]:
+			keep[r.Table] = true
+		case known:
+			keep[r.Table] = st.Status != TableIgnored
+		case firstPass || selection.policy == registry.NewTablesInclude:
+			keep[r.Table] = true
+		default:
+			keep[r.Table] = false
+			states[r.Table] = &TableState{Table: r.Table, Status: TableIgnored}
+			discovered = append(discovered, r.Table)
+		}
+	}
+
+	var saveErr error
+	if len(discovered) > 0 {
+		saveErr = e.store.Save("tables/"+p.ID, states)
+	}
+	e.tablesMu.Unlock()
+	if saveErr != nil {
+		return nil, saveErr
+	}
+
+	for _, table := range discovered {
+		log.Printf("[Engine] Pipeline %s ignores new table %s (new_tables: %s)", p.ID, table, selection.policy)
+		if selection.policy == registry.NewTablesAlert {
+			err := fmt.Errorf("new table %s found in source; add it to tables.include to sync it", table)
+			e.recordError(p.ID, "new_table", err)
+			if tracker != nil {
+				e.reportFailure(p, tracker.run, "new_table", err, "")
+			}
+		}
+	}
+
+	selected := make([]connectors.Record, 0, len(records))
+	for _, r := range records {
+		if r.Table == "" || keep[r.Table] {
+			selected = append(selected, r)
+		}
+	}
+	if dropped := len(records) - len(selected); dropped > 0 {
+		tracker.count(dropped, dropped, 0)
+	}
+	return selected, nil
+}
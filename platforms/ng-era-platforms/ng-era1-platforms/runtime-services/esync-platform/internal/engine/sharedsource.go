@@ -0,0 +1,182 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: shared-source
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Shared Source Reads
+ */
+
+package engine
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/logging"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// maxSharedReads bounds the reads kept per shared source; the oldest are
+// dropped first
+const maxSharedReads = 8
+
+// sharedRead is a read of a shared source: the changes listed from a
+// checkpoint and the source position captured before listing them
+type sharedRead struct {
+	from       string
+	latest     *connectors.Checkpoint
+	changes    []connectors.Record
+	pipelineID string
+	at         time.Time
+}
+
+// sourceShare holds the recent reads of one source by a group of
+// pipelines. mu is held while reading, so pipelines of the group passing
+// at once read the source once.
+type sourceShare struct {
+	mu    sync.Mutex
+	reads []*sharedRead
+}
+
+// sharedSourceKey identifies the source a pipeline shares, by group and
+// source definition; empty when it shares none
+func sharedSourceKey(p *registry.Pipeline) string {
+	if p.SharedSource == nil {
+		return ""
+	}
+	data, err := json.Marshal(p.Source)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return p.SharedSource.Group + "/" + hex.EncodeToString(sum[:])
+}
+
+// sourceShare returns the share of a pipeline's source, nil when it shares
+// none
+func (e *Engine) sourceShare(p *registry.Pipeline) *sourceShare {
+	key := sharedSourceKey(p)
+	if key == "" {
+		return nil
+	}
+
+	e.sharesMu.Lock()
+	defer e.sharesMu.Unlock()
+
+	share, exists := e.shares[key]
+	if !exists {
+		share = &sourceShare{}
+		e.shares[key] = share
+	}
+	return share
+}
+
+// readShared reads the source of a pipeline like readSource. A pipeline
+// sharing its source is served the read another pipeline of its group
+// made from the same checkpoint within the max age instead, with schema
+// changes still captured for its own target; its fresh reads are kept for
+// the group and flag the run so the scheduler runs the others.
+func (e *Engine) readShared(ctx context.Context, p *registry.Pipeline, source, target connectors.Connector, checkpoint *connectors.Checkpoint, tracker *progressTracker) (*connectors.Checkpoint, []connectors.Record, error) {
+	share := e.sourceShare(p)
+	if share == nil {
+		return e.readSource(ctx, p, source, target, checkpoint)
+	}
+	spec, _ := p.Effective()
+	maxAge := time.Duration(spec.SharedSource.MaxAge) * time.Second
+	from := ""
+	if checkpoint != nil {
+		from = checkpoint.Position
+	}
+
+	share.mu.Lock()
+	defer share.mu.Unlock()
+
+	now := time.Now()
+	share.reads = slices.DeleteFunc(share.reads, func(read *sharedRead) bool { return now.Sub(read.at) > maxAge })
+	for i := len(share.reads) - 1; i >= 0; i-- {
+		read := share.reads[i]
+		if read.from != from {
+			continue
+		}
+		if err := e.captureDDL(ctx, p, source, target, checkpoint); err != nil {
+			return nil, nil, err
+		}
+		logging.Debugf(logging.Scope{Module: "engine", Pipeline: p.ID}, "[Engine] Pipeline %s served %d changes read by %s %s ago",
+			p.ID, len(read.changes), read.pipelineID, now.Sub(read.at).Round(time.Millisecond))
+		e.monitor.RecordSharedRead(p.ID, true)
+		return read.latest, copyRecords(read.changes), nil
+	}
+
+	latest, changes, err := e.readSource(ctx, p, source, target, checkpoint)
+	if err != nil {
+		return nil, nil, err
+	}
+	e.monitor.RecordSharedRead(p.ID, false)
+	share.reads = append(share.reads, &sharedRead{from: from, latest: latest, changes: copyRecords(changes), pipelineID: p.ID, at: now})
+	if len(share.reads) > maxSharedReads {
+		share.reads = share.reads[len(share.reads)-maxSharedReads:]
+	}
+	if tracker != nil {
+		e.mu.Lock()
+		tracker.run.SharedRead = true
+		e.mu.Unlock()
+	}
+	return latest, changes, nil
+}
+
+// copyRecords copies records and their data maps, so pipelines served a
+// shared read never see each other's changes to it
+func copyRecords(records []connectors.Record) []connectors.Record {
+	if records == nil {
+		return nil
+	}
+	out := make([]connectors.Record, len(records))
+	for i, r := range records {
+		if r.Data != nil {
+			data := make(map[string]interface{}, len(r.Data))
+			for k, v := range r.Data {
+				data[k] = v
+			}
+			r.Data = data
+		}
+		out[i] = r
+	}
+	return out
+}
+
+// SourcePeers returns the IDs of the other pipelines sharing the source of
+// a pipeline, ordered by ID
+func (e *Engine) SourcePeers(pipelineID string) []string {
+	p, err := e.registry.GetByID(pipelineID)
+	if err != nil {
+		return nil
+	}
+	key := sharedSourceKey(p)
+	if key == "" {
+		return nil
+	}
+
+	var peers []string
+	for _, other := range e.registry.GetAll() {
+		if other.ID != p.ID && sharedSourceKey(other) == key {
+			peers = append(peers, other.ID)
+		}
+	}
+	sort.Strings(peers)
+	return peers
+}
@@ -0,0 +1,212 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: clock-skew
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Source Clock Skew
+ */
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// Ways the skew of a source clock is measured
+const (
+	// SkewSourceClock compares the time the source reports with the daemon
+	// clock around the call
+	SkewSourceClock = "source_clock"
+	// SkewFutureTimestamps takes the newest record or heartbeat time ahead
+	// of the daemon clock; it only detects sources running ahead
+	SkewFutureTimestamps = "future_timestamps"
+)
+
+// ClockSkew is how far the clock of a pipeline's source is off and how its
+// record timestamps were corrected
+type ClockSkew struct {
+	// OffsetSeconds is how far the source clock is ahead of the daemon
+	// clock, negative when behind
+	OffsetSeconds float64   `json:"offset_seconds"`
+	Method        string    `json:"method"`
+	MeasuredAt    time.Time `json:"measured_at"`
+	// Exceeded is set while the offset, beyond the configured one, is over
+	// the threshold
+	Exceeded bool `json:"exceeded,omitempty"`
+	// Correction is what the last pass did to record timestamps: none,
+	// offset, compensate or logical
+	Correction string `json:"correction"`
+	// offset is OffsetSeconds as a duration
+	offset time.Duration
+}
+
+// measureSkew returns how far the source clock is ahead of the daemon
+// clock, preferring the time the source reports over record and heartbeat
+// times in the future. It returns nil when the pass gave nothing to
+// measure by.
+func (e *Engine) measureSkew(ctx context.Context, p *registry.Pipeline, source connectors.Connector, changes []connectors.Record, heartbeat time.Time) *ClockSkew {
+	if c, ok := source.(connectors.SourceClock); ok {
+		before := time.Now()
+		t, err := c.SourceTime(ctx)
+		after := time.Now()
+		if !errors.Is(err, connectors.ErrUnsupported) {
+			e.traceCall(p.ID, e.connectorType(p.Source), "source_time", before, 0, err)
+		}
+		if err == nil && !t.IsZero() {
+			offset := t.Sub(before.Add(after.Sub(before) / 2)).Round(time.Millisecond)
+			return &ClockSkew{offset: offset, Method: SkewSourceClock, MeasuredAt: after.UTC()}
+		}
+	}
+
+	newest := heartbeat
+	for _, r := range changes {
+		if r.Timestamp.After(newest) {
+			newest = r.Timestamp
+		}
+	}
+	if newest.IsZero() {
+		return nil
+	}
+	now := time.Now()
+	var offset time.Duration
+	if newest.After(now) {
+		offset = newest.Sub(now).Round(time.Millisecond)
+	}
+	return &ClockSkew{offset: offset, Method: SkewFutureTimestamps, MeasuredAt: now.UTC()}
+}
+
+// correctSkew records the measured skew of a pipeline's source and shifts
+// the timestamps of changes and the heartbeat by its clock skew spec. A
+// source off by more than the threshold is reported as an error group, and
+// its timestamps are compensated or replaced by their arrival order. It
+// returns the changes, the heartbeat and the skew left in their times.
+func (e *Engine) correctSkew(p *registry.Pipeline, changes []connectors.Record, heartbeat time.Time, skew *ClockSkew) ([]connectors.Record, time.Time, time.Duration) {
+	threshold := time.Duration(registry.DefaultClockSkew) * time.Second
+	var fixed time.Duration
+	// Without a spec the skew is only reported
+	action := registry.ClockSkewReport
+	if c := p.ClockSkew; c != nil {
+		if c.Threshold > 0 {
+			threshold = time.Duration(c.Threshold) * time.Second
+		}
+		fixed = time.Duration(c.Offset * float64(time.Second))
+		action = c.OnExceeded
+		if action == "" {
+			action = registry.DefaultClockSkewAction
+		}
+	}
+
+	if skew == nil {
+		// Nothing to measure by; keep the last measurement
+		if skew = e.clockSkew(p.ID); skew == nil {
+			return shiftTimestamps(changes, fixed), shiftTime(heartbeat, fixed), 0
+		}
+	}
+	residual := skew.offset - fixed
+	skew.Exceeded = math.Abs(residual.Seconds()) > threshold.Seconds()
+	skew.OffsetSeconds = skew.offset.Seconds()
+
+	correction := "none"
+	if fixed != 0 {
+		correction = "offset"
+	}
+	shift := fixed
+	if skew.Exceeded {
+		switch action {
+		case registry.ClockSkewCompensate:
+			shift, correction = skew.offset, registry.ClockSkewCompensate
+		case registry.ClockSkewLogical:
+			correction = registry.ClockSkewLogical
+		}
+	}
+	skew.Correction = correction
+	e.recordSkew(p.ID, skew, threshold)
+
+	if correction == registry.ClockSkewLogical {
+		if !heartbeat.IsZero() {
+			heartbeat = time.Now().UTC()
+		}
+		return arrivalOrder(changes), heartbeat, 0
+	}
+	return shiftTimestamps(changes, shift), shiftTime(heartbeat, shift), skew.offset - shift
+}
+
+// shiftTime subtracts offset from a non-zero time
+func shiftTime(t time.Time, offset time.Duration) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return t.Add(-offset)
+}
+
+// shiftTimestamps subtracts offset from the timestamps of records
+func shiftTimestamps(records []connectors.Record, offset time.Duration) []connectors.Record {
+	if offset == 0 {
+		return records
+	}
+	out := make([]connectors.Record, len(records))
+	for i, r := range records {
+		r.Timestamp = shiftTime(r.Timestamp, offset)
+		out[i] = r
+	}
+	return out
+}
+
+// arrivalOrder replaces the timestamps of records by the daemon time they
+// arrived at, one nanosecond apart in listing order
+func arrivalOrder(records []connectors.Record) []connectors.Record {
+	now := time.Now().UTC()
+	out := make([]connectors.Record, len(records))
+	for i, r := range records {
+		r.Timestamp = now.Add(time.Duration(i))
+		out[i] = r
+	}
+	return out
+}
+
+// clockSkew returns the last measured skew of a pipeline's source
+func (e *Engine) clockSkew(pipelineID string) *ClockSkew {
+	e.sourcesMu.Lock()
+	defer e.sourcesMu.Unlock()
+
+	if a := e.sources[pipelineID]; a != nil && a.ClockSkew != nil {
+		skew := *a.ClockSkew
+		return &skew
+	}
+	return nil
+}
+
+// recordSkew publishes the skew of a pipeline's source, reporting it as an
+// error group when it is over the threshold
+func (e *Engine) recordSkew(pipelineID string, skew *ClockSkew, threshold time.Duration) {
+	e.sourcesMu.Lock()
+	a := e.sources[pipelineID]
+	if a == nil {
+		a = &SourceActivity{lastSeen: time.Now().UTC()}
+		e.sources[pipelineID] = a
+	}
+	c := *skew
+	a.ClockSkew = &c
+	e.sourcesMu.Unlock()
+
+	e.monitor.RecordClockSkew(pipelineID, skew.offset, skew.Exceeded)
+	if skew.Exceeded {
+		e.groupError(pipelineID, "clock_skew", fmt.Sprintf("source clock is off by %s (%s), beyond the threshold of %s; timestamps corrected: %s",
+			skew.offset.Round(time.Millisecond), skew.Method, threshold, skew.Correction))
+	}
+}
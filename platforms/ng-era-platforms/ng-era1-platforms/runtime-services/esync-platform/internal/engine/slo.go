@@ -0,0 +1,294 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: slo-reprioritization
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * SLO-Aware Reprioritization
+ */
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// sloTick is how often WatchSLOs evaluates freshness objectives
+const sloTick = 5 * time.Second
+
+// Audit decisions of automatic reprioritization, recorded under the "slo"
+// policy
+const (
+	AuditBoosted    = "boosted"
+	AuditUnboosted  = "unboosted"
+	AuditShed       = "shed"
+	AuditReadmitted = "readmitted"
+)
+
+// ErrShed is returned for automatic triggers of bulk pipelines while the
+// freshness objective of another pipeline is burning
+var ErrShed = errors.New("bulk pipeline shed during an SLO incident")
+
+// SLOStatus is the freshness of a pipeline against its objective
+type SLOStatus struct {
+	FreshnessSeconds float64 `json:"freshness_seconds"`
+	// StalenessSeconds is the time since the last successful run
+	StalenessSeconds float64 `json:"staleness_seconds"`
+	// Burning is set once staleness reaches the boost threshold, Breached
+	// once it exceeds the objective
+	Burning  bool `json:"burning"`
+	Breached bool `json:"breached"`
+	// Boosted pipelines run every MinInterval seconds with MaxApplyWorkers
+	// until they are fresh again
+	Boosted   bool      `json:"boosted"`
+	BoostedAt time.Time `json:"boosted_at,omitempty"`
+}
+
+// sloState tracks the objective of one pipeline
+type sloState struct {
+	SLOStatus
+	// since is when the engine started watching the pipeline, the staleness
+	// baseline until it first succeeds
+	since time.Time
+}
+
+// WatchSLOs evaluates the freshness objectives of all pipelines every few
+// seconds until ctx is cancelled, boosting burning pipelines and shedding
+// bulk ones
+func (e *Engine) WatchSLOs(ctx context.Context) {
+	ticker := time.NewTicker(sloTick)
+	defer ticker.Stop()
+
+	for {
+		e.EvaluateSLOs(time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// EvaluateSLOs updates the staleness of every pipeline with an objective,
+// boosting pipelines whose objective is burning, restoring those fresh
+// again and shedding bulk pipelines while any pipeline is boosted
+func (e *Engine) EvaluateSLOs(now time.Time) {
+	pipelines := e.registry.GetAll()
+	sort.Slice(pipelines, func(i, j int) bool { return pipelines[i].ID < pipelines[j].ID })
+
+	type change struct {
+		p       *registry.Pipeline
+		boosted bool
+		st      SLOStatus
+	}
+	var changes []change
+	var boosted []string
+
+	e.sloMu.Lock()
+	for _, p := range pipelines {
+		if p.SLO == nil || p.SLO.Freshness <= 0 {
+			delete(e.slos, p.ID)
+			continue
+		}
+		st := e.slos[p.ID]
+		if st == nil {
+			st = &sloState{since: now}
+			e.slos[p.ID] = st
+		}
+
+		objective := time.Duration(p.SLO.Freshness) * time.Second
+		staleness := now.Sub(st.since)
+		if last := e.lastSuccess(p.ID); !last.IsZero() {
+			staleness = now.Sub(last)
+		}
+		st.FreshnessSeconds = objective.Seconds()
+		st.StalenessSeconds = staleness.Seconds()
+		st.Burning = staleness.Seconds() >= sloBoostAt(p.SLO)*objective.Seconds()
+		st.Breached = staleness > objective
+		e.monitor.RecordStaleness(p.ID, staleness)
+
+		if st.Burning != st.Boosted {
+			st.Boosted = st.Burning
+			if st.Boosted {
+				st.BoostedAt = now.UTC()
+			} else {
+				st.BoostedAt = time.Time{}
+			}
+			changes = append(changes, change{p, st.Boosted, st.SLOStatus})
+			e.monitor.RecordBoosted(p.ID, st.Boosted)
+		}
+		if st.Boosted {
+			boosted = append(boosted, p.ID)
+		}
+	}
+
+	var shed, readmitted []string
+	for _, p := range pipelines {
+		shedding := len(boosted) > 0 && p.Priority == registry.PriorityBulk
+		switch {
+		case shedding && !e.shed[p.ID]:
+			e.shed[p.ID] = true
+			shed = append(shed, p.ID)
+		case !shedding && e.shed[p.ID]:
+			delete(e.shed, p.ID)
+			readmitted = append(readmitted, p.ID)
+		}
+	}
+	if len(changes) > 0 || len(shed) > 0 || len(readmitted) > 0 {
+		close(e.sloWake)
+		e.sloWake = make(chan struct{})
+	}
+	e.sloMu.Unlock()
+
+	for _, c := range changes {
+		staleness := time.Duration(c.st.StalenessSeconds * float64(time.Second)).Round(time.Second)
+		objective := time.Duration(c.st.FreshnessSeconds * float64(time.Second))
+		entry := AuditEntry{PipelineID: c.p.ID, Policy: "slo"}
+		if c.boosted {
+			entry.Decision = AuditBoosted
+			entry.Detail = fmt.Sprintf("staleness %s of %s objective; running every %s with %d apply workers",
+				staleness, objective, sloInterval(c.p.SLO), sloWorkers(c.p))
+		} else {
+			entry.Decision = AuditUnboosted
+			entry.Detail = fmt.Sprintf("staleness %s of %s objective; restored regular schedule and workers", staleness, objective)
+		}
+		log.Printf("[Engine] Pipeline %s %s: %s", c.p.ID, entry.Decision, entry.Detail)
+		e.audit(entry)
+	}
+	for _, id := range shed {
+		detail := fmt.Sprintf("deferring automatic runs while freshness objectives burn: %s", strings.Join(boosted, ", "))
+		log.Printf("[Engine] Pipeline %s shed: %s", id, detail)
+		e.audit(AuditEntry{PipelineID: id, Policy: "slo", Decision: AuditShed, Detail: detail})
+	}
+	for _, id := range readmitted {
+		log.Printf("[Engine] Pipeline %s readmitted: no freshness objective is burning", id)
+		e.audit(AuditEntry{PipelineID: id, Policy: "slo", Decision: AuditReadmitted, Detail: "no freshness objective is burning"})
+	}
+}
+
+// SLOStatus returns the freshness of a pipeline with an objective, or nil
+func (e *Engine) SLOStatus(pipelineID string) *SLOStatus {
+	e.sloMu.Lock()
+	defer e.sloMu.Unlock()
+
+	st := e.slos[pipelineID]
+	if st == nil {
+		return nil
+	}
+	out := st.SLOStatus
+	return &out
+}
+
+// Shed reports whether the automatic triggers of a pipeline are shed
+func (e *Engine) Shed(pipelineID string) bool {
+	e.sloMu.Lock()
+	defer e.sloMu.Unlock()
+
+	return e.shed[pipelineID]
+}
+
+// SLOChanged returns a channel closed the next time a pipeline is boosted,
+// restored, shed or readmitted, so schedulers can recompute their timers
+func (e *Engine) SLOChanged() <-chan struct{} {
+	e.sloMu.Lock()
+	defer e.sloMu.Unlock()
+
+	return e.sloWake
+}
+
+// BoostInterval returns the schedule interval of a boosted pipeline, or
+// zero when it is not boosted
+func (e *Engine) BoostInterval(p *registry.Pipeline) time.Duration {
+	if p.SLO == nil || !e.boosted(p.ID) {
+		return 0
+	}
+	return sloInterval(p.SLO)
+}
+
+// applyWorkers returns the apply workers of a pipeline, raised to the SLO
+// bound while it is boosted
+func (e *Engine) applyWorkers(p *registry.Pipeline) int {
+	workers := p.ApplyWorkers
+	if workers <= 0 {
+		workers = registry.DefaultApplyWorkers
+	}
+	if e.boosted(p.ID) {
+		if boost := sloWorkers(p); boost > workers {
+			return boost
+		}
+	}
+	return workers
+}
+
+// checkShed rejects automatic triggers of shed pipelines; manual and
+// backfill runs are never shed
+func (e *Engine) checkShed(pipelineID string, trigger Trigger) error {
+	if trigger.Type == TriggerManual || trigger.Type == TriggerBackfill || !e.Shed(pipelineID) {
+		return nil
+	}
+	e.monitor.RecordTrigger(pipelineID, "shed")
+	return fmt.Errorf("cannot run %s: %w", pipelineID, ErrShed)
+}
+
+// boosted reports whether a pipeline is boosted
+func (e *Engine) boosted(pipelineID string) bool {
+	e.sloMu.Lock()
+	defer e.sloMu.Unlock()
+
+	st := e.slos[pipelineID]
+	return st != nil && st.Boosted
+}
+
+// lastSuccess returns when the last successful run of a pipeline finished
+func (e *Engine) lastSuccess(pipelineID string) time.Time {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	history := e.history[pipelineID]
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Status == StatusSucceeded {
+			return history[i].FinishedAt
+		}
+	}
+	return time.Time{}
+}
+
+// sloBoostAt returns the share of the objective that boosts a pipeline
+func sloBoostAt(spec *registry.SLOSpec) float64 {
+	if spec.BoostAt > 0 && spec.BoostAt <= 1 {
+		return spec.BoostAt
+	}
+	return registry.DefaultSLOBoostAt
+}
+
+// sloInterval returns the schedule interval of a boosted pipeline
+func sloInterval(spec *registry.SLOSpec) time.Duration {
+	if spec.MinInterval > 0 {
+		return time.Duration(spec.MinInterval) * time.Second
+	}
+	return registry.DefaultSLOMinInterval * time.Second
+}
+
+// sloWorkers returns the apply workers of a boosted pipeline
+func sloWorkers(p *registry.Pipeline) int {
+	if p.SLO.MaxApplyWorkers > 0 {
+		return p.SLO.MaxApplyWorkers
+	}
+	return registry.DefaultSLOApplyWorkers
+}
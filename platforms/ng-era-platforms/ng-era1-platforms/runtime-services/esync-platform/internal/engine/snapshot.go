@@ -0,0 +1,277 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: incremental-snapshot
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Incremental Snapshots
+ */
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// ErrSnapshotInProgress is returned when a snapshot is requested while
+// another one of the pipeline is unfinished
+var ErrSnapshotInProgress = errors.New("incremental snapshot already in progress")
+
+// ErrNoSnapshot is returned when cancelling a pipeline without an
+// unfinished snapshot
+var ErrNoSnapshot = errors.New("no incremental snapshot in progress")
+
+// SnapshotChunk tracks one key range of an incremental snapshot
+type SnapshotChunk struct {
+	Range   connectors.KeyRange `json:"range"`
+	Done    bool                `json:"done"`
+	Records int                 `json:"records"`
+	// Superseded counts records of the chunk dropped because the same sync
+	// pass streamed a change of their key
+	Superseded int `json:"superseded,omitempty"`
+}
+
+// IncrementalSnapshot is an ad-hoc re-snapshot of selected tables or key
+// ranges. Its chunks are interleaved with the incremental stream, one per
+// sync pass, so the pipeline keeps streaming while it runs.
+type IncrementalSnapshot struct {
+	ID         string `json:"id"`
+	PipelineID string `json:"pipeline_id"`
+	// Tables limits the snapshot to records of these tables; empty means
+	// every table
+	Tables      []string        `json:"tables,omitempty"`
+	Chunks      []SnapshotChunk `json:"chunks"`
+	RequestedAt time.Time       `json:"requested_at"`
+	CompletedAt time.Time       `json:"completed_at,omitempty"`
+	CancelledAt time.Time       `json:"cancelled_at,omitempty"`
+	// Error is the last chunk read failure; the chunk is retried on the
+	// next pass
+	Error string `json:"error,omitempty"`
+}
+
+// Finished reports whether the snapshot completed or was cancelled
+func (s *IncrementalSnapshot) Finished() bool {
+	return !s.CompletedAt.IsZero() || !s.CancelledAt.IsZero()
+}
+
+// next returns the index of the first pending chunk, or -1
+func (s *IncrementalSnapshot) next() int {
+	for i, chunk := range s.Chunks {
+		if !chunk.Done {
+			return i
+		}
+	}
+	return -1
+}
+
+// RequestSnapshot plans an incremental snapshot of the given tables and key
+// range; empty tables and bounds select everything. The source key space is
+// split as for backfills and clipped to the range.
+func (e *Engine) RequestSnapshot(ctx context.Context, pipelineID string, tables []string, keys connectors.KeyRange) (*IncrementalSnapshot, error) {
+	p, err := e.registry.GetByID(pipelineID)
+	if err != nil {
+		return nil, err
+	}
+	coll := collationOf(p)
+	if keys.End != "" && coll.compare(keys.Start, keys.End) >= 0 {
+		return nil, fmt.Errorf("key range [%s,%s) is empty", keys.Start, keys.End)
+	}
+
+	source, _, err := e.Connect(p)
+	if err != nil {
+		return nil, err
+	}
+	reader, ok := source.(connectors.RangeReader)
+	if !ok {
+		return nil, fmt.Errorf("source type %s does not support incremental snapshots", p.Source.Type)
+	}
+
+	chunks := registry.DefaultBackfillChunks
+	if p.Backfill != nil && p.Backfill.Chunks > 0 {
+		chunks = p.Backfill.Chunks
+	}
+	ranges, err := reader.KeyRanges(ctx, chunks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to split key space: %w", err)
+	}
+
+	e.snapshotMu.Lock()
+	defer e.snapshotMu.Unlock()
+
+	current, err := e.loadSnapshot(p.ID)
+	if err != nil {
+		return nil, err
+	}
+	if current != nil && !current.Finished() {
+		return nil, fmt.Errorf("cannot snapshot %s: %w", p.ID, ErrSnapshotInProgress)
+	}
+
+	now := time.Now().UTC()
+	snap := &IncrementalSnapshot{
+		ID:          fmt.Sprintf("%s-snapshot-%d", p.ID, now.UnixNano()),
+		PipelineID:  p.ID,
+		Tables:      tables,
+		RequestedAt: now,
+	}
+	for _, r := range coll.partition(ranges) {
+		if clipped, ok := coll.clipRange(r, keys); ok {
+			snap.Chunks = append(snap.Chunks, SnapshotChunk{Range: clipped})
+		}
+	}
+	if len(snap.Chunks) == 0 {
+		snap.CompletedAt = now
+	}
+
+	if err := e.store.Save("snapshot/"+p.ID, snap); err != nil {
+		return nil, err
+	}
+	log.Printf("[Engine] Incremental snapshot %s of pipeline %s requested (%d chunks)", snap.ID, p.ID, len(snap.Chunks))
+	return snap, nil
+}
+
+// SnapshotStatus returns the last incremental snapshot of a pipeline, or nil
+func (e *Engine) SnapshotStatus(pipelineID string) (*IncrementalSnapshot, error) {
+	e.snapshotMu.Lock()
+	defer e.snapshotMu.Unlock()
+
+	return e.loadSnapshot(pipelineID)
+}
+
+// CancelSnapshot stops the unfinished incremental snapshot of a pipeline;
+// chunks already applied stay applied
+func (e *Engine) CancelSnapshot(pipelineID string) (*IncrementalSnapshot, error) {
+	e.snapshotMu.Lock()
+	defer e.snapshotMu.Unlock()
+
+	snap, err := e.loadSnapshot(pipelineID)
+	if err != nil {
+		return nil, err
+	}
+	if snap == nil || snap.Finished() {
+		return nil, fmt.Errorf("cannot cancel snapshot of %s: %w", pipelineID, ErrNoSnapshot)
+	}
+
+	snap.CancelledAt = time.Now().UTC()
+	if err := e.store.Save("snapshot/"+pipelineID, snap); err != nil {
+		return nil, err
+	}
+	log.Printf("[Engine] Incremental snapshot %s of pipeline %s cancelled", snap.ID, pipelineID)
+	return snap, nil
+}
+
+// loadSnapshot reads the stored snapshot of a pipeline; the caller holds
+// snapshotMu
+func (e *Engine) loadSnapshot(pipelineID string) (*IncrementalSnapshot, error) {
+	var snap IncrementalSnapshot
+	found, err := e.store.Load("snapshot/"+pipelineID, &snap)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &snap, nil
+}
+
+// snapshotChunk reads the next pending chunk of the pipeline's incremental
+// snapshot. Records whose key is among the changes listed by the same pass
+// are dropped: the streamed change is at least as new as the snapshot read,
+// so the stream wins. It returns the records to apply ahead of the changes
+// and a func that marks the chunk done once they are applied.
+func (e *Engine) snapshotChunk(ctx context.Context, p *registry.Pipeline, source connectors.Connector, changes []connectors.Record) ([]connectors.Record, func()) {
+	e.snapshotMu.Lock()
+	snap, err := e.loadSnapshot(p.ID)
+	e.snapshotMu.Unlock()
+	if err != nil {
+		log.Printf("[Engine] Failed to load incremental snapshot of pipeline %s: %v", p.ID, err)
+		return nil, nil
+	}
+	if snap == nil || snap.Finished() {
+		return nil, nil
+	}
+	i := snap.next()
+	reader, ok := source.(connectors.RangeReader)
+	if i < 0 || !ok {
+		return nil, nil
+	}
+
+	chunk := snap.Chunks[i].Range
+	start := time.Now()
+	read, err := reader.ReadRange(ctx, chunk)
+	e.traceCall(p.ID, e.connectorType(p.Source), "read_range", start, len(read), err)
+	if err != nil {
+		err = fmt.Errorf("snapshot chunk [%s,%s): %w", chunk.Start, chunk.End, err)
+		e.recordError(p.ID, "snapshot", err)
+		e.updateSnapshot(p.ID, snap.ID, func(s *IncrementalSnapshot) { s.Error = err.Error() })
+		return nil, nil
+	}
+
+	read = e.normalizeKeys(p, read)
+	coll := collationOf(p)
+	streamed := make(map[connectors.Key]bool, len(changes))
+	for _, change := range changes {
+		streamed[coll.key(change)] = true
+	}
+	var tables map[string]bool
+	if len(snap.Tables) > 0 {
+		tables = make(map[string]bool, len(snap.Tables))
+		for _, table := range snap.Tables {
+			tables[table] = true
+		}
+	}
+
+	records := make([]connectors.Record, 0, len(read))
+	superseded := 0
+	for _, record := range read {
+		if tables != nil && !tables[record.Table] {
+			continue
+		}
+		if streamed[coll.key(record)] {
+			superseded++
+			continue
+		}
+		records = append(records, record)
+	}
+	e.tracef(p.ID, "snapshot chunk [%s,%s): %d records, %d superseded by streamed changes", chunk.Start, chunk.End, len(records), superseded)
+
+	done := func() {
+		e.updateSnapshot(p.ID, snap.ID, func(s *IncrementalSnapshot) {
+			s.Chunks[i].Done = true
+			s.Chunks[i].Records = len(records)
+			s.Chunks[i].Superseded = superseded
+			s.Error = ""
+			if s.next() < 0 {
+				s.CompletedAt = time.Now().UTC()
+				log.Printf("[Engine] Incremental snapshot %s of pipeline %s completed", s.ID, p.ID)
+			}
+		})
+	}
+	return records, done
+}
+
+// updateSnapshot applies fn to the stored snapshot of a pipeline unless it
+// was replaced or finished in the meantime
+func (e *Engine) updateSnapshot(pipelineID, id string, fn func(*IncrementalSnapshot)) {
+	e.snapshotMu.Lock()
+	defer e.snapshotMu.Unlock()
+
+	snap, err := e.loadSnapshot(pipelineID)
+	if err == nil && snap != nil && snap.ID == id && !snap.Finished() {
+		fn(snap)
+		err = e.store.Save("snapshot/"+pipelineID, snap)
+	}
+	if err != nil {
+		log.Printf("[Engine] Failed to save incremental snapshot of pipeline %s: %v", pipelineID, err)
+	}
+}
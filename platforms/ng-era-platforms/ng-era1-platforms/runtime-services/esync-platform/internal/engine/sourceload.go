@@ -0,0 +1,188 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: source-load
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Source Load Limits
+ */
+
+package engine
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// maxSourceLoadWait is the longest a sync pass waits for its source to
+// allow reading; passes that would wait longer end without reading
+const maxSourceLoadWait = 5 * time.Second
+
+// sourceLoadState is how hard a pipeline may read its source next
+type sourceLoadState struct {
+	nextRead time.Time
+	backoff  time.Duration
+	latency  time.Duration
+	deferred int
+}
+
+// SourceLoadStatus reports how the source load limits of a pipeline are
+// holding back its reads
+type SourceLoadStatus struct {
+	// InWindow tells whether reads are allowed by the read windows, and
+	// NextWindow when that changes
+	InWindow   bool      `json:"in_window"`
+	NextWindow time.Time `json:"next_window,omitempty"`
+	// NextRead is the earliest the source is read again
+	NextRead time.Time `json:"next_read,omitempty"`
+	// Latency is how many seconds the last read took and Backoff how many
+	// seconds reads are spaced for the source answering slowly
+	Latency float64 `json:"latency"`
+	Backoff float64 `json:"backoff,omitempty"`
+	// Deferred counts the sync passes that ended without reading
+	Deferred int `json:"deferred"`
+}
+
+// sourceLoad returns the source load state of a pipeline; callers hold
+// sourceLoadMu
+func (e *Engine) sourceLoad(pipelineID string) *sourceLoadState {
+	st := e.sourceLoads[pipelineID]
+	if st == nil {
+		st = &sourceLoadState{}
+		e.sourceLoads[pipelineID] = st
+	}
+	return st
+}
+
+// awaitSourceLoad waits until the source load limits of a pipeline allow
+// reading its source, reporting false when the pass should end without
+// reading: outside the read windows, or when the next read is further off
+// than maxSourceLoadWait
+func (e *Engine) awaitSourceLoad(ctx context.Context, p *registry.Pipeline) (bool, error) {
+	limits := p.SourceLoad
+	if limits == nil {
+		return true, nil
+	}
+	now := time.Now()
+	cal, err := limits.Calendar()
+	if err != nil {
+		return false, err
+	}
+
+	e.sourceLoadMu.Lock()
+	st := e.sourceLoad(p.ID)
+	if cal != nil && !cal.Active(now) {
+		st.deferred++
+		e.sourceLoadMu.Unlock()
+		e.tracef(p.ID, "outside the source read windows until %s, not reading", cal.Next(now).UTC().Format(time.RFC3339))
+		return false, nil
+	}
+	wait := st.nextRead.Sub(now)
+	if wait > maxSourceLoadWait {
+		st.deferred++
+		e.sourceLoadMu.Unlock()
+		e.tracef(p.ID, "source load limits allow reading in %s, not reading", wait.Round(time.Millisecond))
+		return false, nil
+	}
+	e.sourceLoadMu.Unlock()
+	if wait <= 0 {
+		return true, nil
+	}
+
+	e.tracef(p.ID, "waiting %s for the source load limits", wait.Round(time.Millisecond))
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false, ctx.Err()
+	case <-timer.C:
+	}
+	return true, nil
+}
+
+// observeSourceLoad spaces the next read of a pipeline's source after a
+// read listing n records took latency: by the record rate, and by a backoff
+// that doubles while the source answers slower than the latency threshold
+// and halves once it recovers
+func (e *Engine) observeSourceLoad(p *registry.Pipeline, n int, latency time.Duration) {
+	limits := p.SourceLoad
+	if limits == nil {
+		return
+	}
+
+	e.sourceLoadMu.Lock()
+	st := e.sourceLoad(p.ID)
+	previous := st.backoff
+	st.latency = latency
+	if limits.LatencyThreshold > 0 {
+		ceiling := time.Duration(limits.MaxBackoff) * time.Second
+		if ceiling <= 0 {
+			ceiling = registry.DefaultMaxSourceBackoff * time.Second
+		}
+		if latency.Seconds() > limits.LatencyThreshold {
+			st.backoff = min(max(2*st.backoff, time.Second), ceiling)
+		} else if st.backoff /= 2; st.backoff < time.Second {
+			st.backoff = 0
+		}
+	}
+	var spacing time.Duration
+	if limits.MaxRecordsPerSecond > 0 {
+		spacing = time.Duration(float64(n) / limits.MaxRecordsPerSecond * float64(time.Second))
+	}
+	st.nextRead = time.Now().Add(max(spacing, st.backoff))
+	backoff := st.backoff
+	e.sourceLoadMu.Unlock()
+
+	switch {
+	case backoff > previous:
+		log.Printf("[Engine] Source of pipeline %s answered in %s, backing off %s", p.ID, latency.Round(time.Millisecond), backoff)
+	case backoff == 0 && previous > 0:
+		log.Printf("[Engine] Source of pipeline %s recovered, no longer backing off", p.ID)
+	}
+	metrics := pipelineMetrics{engine: e, pipelineID: p.ID}
+	metrics.Gauge("source_read_latency_seconds", latency.Seconds())
+	metrics.Gauge("source_backoff_seconds", backoff.Seconds())
+}
+
+// SourceLoad returns how the source load limits of a pipeline are holding
+// back its reads, or nil for pipelines without them
+func (e *Engine) SourceLoad(p *registry.Pipeline) *SourceLoadStatus {
+	if p.SourceLoad == nil {
+		return nil
+	}
+	cal, err := p.SourceLoad.Calendar()
+	if err != nil {
+		return nil
+	}
+
+	now := time.Now()
+	status := &SourceLoadStatus{InWindow: true}
+	if cal != nil {
+		status.InWindow = cal.Active(now)
+		if next := cal.Next(now); !next.IsZero() {
+			status.NextWindow = next.UTC()
+		}
+	}
+
+	e.sourceLoadMu.Lock()
+	defer e.sourceLoadMu.Unlock()
+	if st := e.sourceLoads[p.ID]; st != nil {
+		if st.nextRead.After(now) {
+			status.NextRead = st.nextRead.UTC()
+		}
+		status.Latency = st.latency.Seconds()
+		status.Backoff = st.backoff.Seconds()
+		status.Deferred = st.deferred
+	}
+	return status
+}
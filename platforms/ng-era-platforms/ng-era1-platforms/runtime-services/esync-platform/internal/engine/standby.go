@@ -0,0 +1,232 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: warm-standby
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Warm Standby Failover Barrier
+ */
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// pauseReasonStandby marks standby pipelines whose barrier was released
+const pauseReasonStandby = "standby"
+
+// Standby phases. A standby pipeline primes its target on its schedule
+// while the barrier is held; a failover stops priming, applies the final
+// delta and leaves the pipeline paused with the target promoted.
+const (
+	StandbyPriming  = "priming"
+	StandbyApplying = "applying"
+	StandbyPromoted = "promoted"
+	StandbyFailed   = "failed"
+)
+
+// Event types of standby failovers
+const (
+	EventFailoverStarted = "failover_started"
+	EventPromoted        = "promoted"
+)
+
+// ErrNotStandby is returned for failovers of pipelines not in standby mode
+var ErrNotStandby = errors.New("pipeline is not a standby pipeline")
+
+// StandbyState is the persisted barrier of a standby pipeline
+type StandbyState struct {
+	PipelineID string `json:"pipeline_id"`
+	Phase      string `json:"phase"`
+	// DeltaRecords counts the records the final delta applied
+	DeltaRecords int `json:"delta_records"`
+	// Forced is set when the target was promoted although the final delta
+	// failed, as when the source is lost
+	Forced      bool      `json:"forced,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	RequestedAt time.Time `json:"requested_at,omitempty"`
+	PromotedAt  time.Time `json:"promoted_at,omitempty"`
+}
+
+// Standby returns the barrier state of a standby pipeline; pipelines never
+// failed over are priming
+func (e *Engine) Standby(pipelineID string) (*StandbyState, error) {
+	p, err := e.registry.GetByID(pipelineID)
+	if err != nil {
+		return nil, err
+	}
+	if p.Mode != registry.ModeStandby {
+		return nil, fmt.Errorf("%w: %s", ErrNotStandby, pipelineID)
+	}
+
+	var st StandbyState
+	found, err := e.store.Load("standby/"+pipelineID, &st)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return &StandbyState{PipelineID: pipelineID, Phase: StandbyPriming}, nil
+	}
+	return &st, nil
+}
+
+// Failover releases the barrier of a standby pipeline: priming stops, the
+// final delta is applied in the background once any priming run has
+// finished, and the target is promoted when the source is caught up. With
+// force the target is promoted even if the final delta fails.
+func (e *Engine) Failover(ctx context.Context, pipelineID string, force bool) (*StandbyState, error) {
+	st, err := e.Standby(pipelineID)
+	if err != nil {
+		return nil, err
+	}
+	switch st.Phase {
+	case StandbyApplying:
+		return nil, fmt.Errorf("failover of %s already in progress", pipelineID)
+	case StandbyPromoted:
+		return nil, fmt.Errorf("standby %s is already promoted", pipelineID)
+	}
+
+	st = &StandbyState{PipelineID: pipelineID, Phase: StandbyApplying, Forced: force, RequestedAt: time.Now().UTC()}
+	if err := e.pauseFor(pipelineID, pauseReasonStandby); err != nil {
+		return nil, err
+	}
+	if err := e.store.Save("standby/"+pipelineID, st); err != nil {
+		return nil, err
+	}
+	log.Printf("[Engine] Releasing standby barrier of pipeline %s", pipelineID)
+	e.publish(EventFailoverStarted, pipelineID, nil, pauseReasonStandby)
+
+	snapshot := *st
+	go e.failover(ctx, st)
+	return &snapshot, nil
+}
+
+// ResumeFailovers continues every failover interrupted by a restart
+func (e *Engine) ResumeFailovers(ctx context.Context) error {
+	keys, err := e.store.Keys("standby")
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		var st StandbyState
+		if _, err := e.store.Load(key, &st); err != nil {
+			return err
+		}
+		if st.Phase != StandbyApplying {
+			continue
+		}
+		log.Printf("[Engine] Resuming failover of pipeline %s", st.PipelineID)
+		go e.failover(ctx, &st)
+	}
+	return nil
+}
+
+// Rearm holds the barrier of a standby pipeline again after a failover,
+// as after a drill, and lets it resume priming
+func (e *Engine) Rearm(pipelineID string) (*StandbyState, error) {
+	st, err := e.Standby(pipelineID)
+	if err != nil {
+		return nil, err
+	}
+	if st.Phase == StandbyApplying {
+		return nil, fmt.Errorf("failover of %s is in progress", pipelineID)
+	}
+
+	if err := e.store.Delete("standby/" + pipelineID); err != nil {
+		return nil, err
+	}
+	if err := e.resumeFor(pipelineID, pauseReasonStandby); err != nil {
+		return nil, err
+	}
+	return &StandbyState{PipelineID: pipelineID, Phase: StandbyPriming}, nil
+}
+
+// failover applies the final delta under the pipeline run lock and
+// records the outcome. A failed failover holds the barrier again and lets
+// the pipeline resume priming.
+func (e *Engine) failover(ctx context.Context, st *StandbyState) {
+	lock := e.lockFor(st.PipelineID)
+	select {
+	case lock.sem <- struct{}{}:
+	case <-ctx.Done():
+		return
+	}
+	defer func() { <-lock.sem }()
+
+	n, err := e.finalDelta(ctx, st.PipelineID)
+	if ctx.Err() != nil {
+		// Left in the applying phase for ResumeFailovers
+		return
+	}
+	st.DeltaRecords += n
+	switch {
+	case err == nil:
+		st.Phase = StandbyPromoted
+		st.Error = ""
+	case st.Forced:
+		st.Phase = StandbyPromoted
+		st.Error = err.Error()
+		log.Printf("[Engine] Promoting standby %s without its final delta: %v", st.PipelineID, err)
+	default:
+		st.Phase = StandbyFailed
+		st.Error = err.Error()
+		log.Printf("[Engine] Failover of pipeline %s failed: %v", st.PipelineID, err)
+	}
+	if st.Phase == StandbyPromoted {
+		st.PromotedAt = time.Now().UTC()
+	}
+
+	if err := e.store.Save("standby/"+st.PipelineID, st); err != nil {
+		log.Printf("[Engine] Failed to persist standby state of pipeline %s: %v", st.PipelineID, err)
+		return
+	}
+	if st.Phase == StandbyFailed {
+		if err := e.resumeFor(st.PipelineID, pauseReasonStandby); err != nil {
+			log.Printf("[Engine] Failed to resume priming of pipeline %s: %v", st.PipelineID, err)
+		}
+		return
+	}
+	log.Printf("[Engine] Standby target of pipeline %s promoted after %d final records", st.PipelineID, st.DeltaRecords)
+	e.publish(EventPromoted, st.PipelineID, nil, pauseReasonStandby)
+}
+
+// finalDelta drains the changes the standby has not received yet and
+// checks the source has nothing left
+func (e *Engine) finalDelta(ctx context.Context, pipelineID string) (int, error) {
+	p, err := e.registry.GetByID(pipelineID)
+	if err != nil {
+		return 0, err
+	}
+	passes := registry.DefaultMaxDrainPasses
+	if p.Standby != nil && p.Standby.MaxDrainPasses > 0 {
+		passes = p.Standby.MaxDrainPasses
+	}
+
+	n, err := e.drain(ctx, pipelineID, passes)
+	if err != nil {
+		return n, fmt.Errorf("final delta failed: %w", err)
+	}
+	caughtUp, err := e.CaughtUp(ctx, pipelineID)
+	if err != nil {
+		return n, fmt.Errorf("lag check failed: %w", err)
+	}
+	if !caughtUp {
+		return n, fmt.Errorf("target is behind the source after the final delta")
+	}
+	return n, nil
+}
@@ -0,0 +1,199 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: multi-table-routing
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Multi-Table Pipelines and Per-Table Routing
+ */
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// TableState is the per-table progress of a multi-table pipeline
+type TableState struct {
+	Table string `json:"table"`
+	// Status is active, or ignored for new tables held back by the
+	// new-table policy
+	Status string `json:"status"`
+	// Target is the connector type the table is routed to
+	Target string `json:"target"`
+	// Position is the source position of the last pass that applied the
+	// table; backfills leave it unchanged
+	Position    string    `json:"position,omitempty"`
+	Records     int64     `json:"records"`
+	Errors      int64     `json:"errors"`
+	LastApplied time.Time `json:"last_applied,omitempty"`
+	LastError   string    `json:"last_error,omitempty"`
+}
+
+// routeGroup is the records of a pass bound for one target
+type routeGroup struct {
+	route   int
+	records []connectors.Record
+	tables  map[string]int
+}
+
+// applyRouted applies records of a multi-table pipeline: records are grouped
+// by their route's target and each group is applied independently, so one
+// failing target does not hold back the other tables. Per-table progress is
+// recorded at position. Pipelines whose records carry no table and that have
+// no routes are applied as a whole.
+func (e *Engine) applyRouted(ctx context.Context, p *registry.Pipeline, target connectors.Connector, records []connectors.Record, tracker *progressTracker, label, position string) (int, error) {
+	records, err := e.selectTables(p, records, tracker)
+	if err != nil {
+		return 0, err
+	}
+	if len(p.Routes) == 0 && !hasTables(records) {
+		return e.apply(ctx, p, target, records, tracker, label)
+	}
+
+	var groups []*routeGroup
+	byRoute := make(map[int]*routeGroup)
+	for _, r := range records {
+		route := routeFor(p, r.Table)
+		g, exists := byRoute[route]
+		if !exists {
+			g = &routeGroup{route: route, tables: make(map[string]int)}
+			byRoute[route] = g
+			groups = append(groups, g)
+		}
+		g.records = append(g.records, r)
+		g.tables[r.Table]++
+	}
+
+	applied := 0
+	var errs []error
+	for _, g := range groups {
+		spec, groupTarget := p.Target, target
+		prefix := label
+		if g.route >= 0 {
+			spec = p.Routes[g.route].Target
+			prefix = fmt.Sprintf("%sroute%d:", label, g.route)
+		}
+
+		var err error
+		if g.route >= 0 {
+			groupTarget, err = e.connector(spec)
+		}
+		var n int
+		if err == nil {
+			n, err = e.apply(ctx, p, groupTarget, g.records, tracker, prefix)
+		}
+		applied += n
+		if err != nil {
+			errs = append(errs, fmt.Errorf("tables %v: %w", tableNames(g.tables), err))
+		}
+		e.recordTables(p.ID, spec.Type, g.tables, position, err)
+	}
+
+	return applied, errors.Join(errs...)
+}
+
+// routeFor returns the index of the route of a table, or -1 for the
+// pipeline target
+func routeFor(p *registry.Pipeline, table string) int {
+	for i, route := range p.Routes {
+		if route.Table == table {
+			return i
+		}
+	}
+	return -1
+}
+
+// hasTables reports whether any record names its table
+func hasTables(records []connectors.Record) bool {
+	for _, r := range records {
+		if r.Table != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// tableNames returns the sorted table names of a group
+func tableNames(tables map[string]int) []string {
+	names := make([]string, 0, len(tables))
+	for t := range tables {
+		names = append(names, t)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// recordTables updates the stored per-table progress and metrics after a
+// group of tables was applied
+func (e *Engine) recordTables(pipelineID, targetType string, tables map[string]int, position string, applyErr error) {
+	e.tablesMu.Lock()
+	defer e.tablesMu.Unlock()
+
+	states := make(map[string]*TableState)
+	if _, err := e.store.Load("tables/"+pipelineID, &states); err != nil {
+		e.monitor.RecordError(pipelineID, "tables", err)
+		return
+	}
+
+	now := time.Now().UTC()
+	for table, n := range tables {
+		st, exists := states[table]
+		if !exists {
+			st = &TableState{Table: table, Status: TableActive}
+			states[table] = st
+		}
+		st.Target = targetType
+		st.Status = TableActive
+		if applyErr != nil {
+			st.Errors++
+			st.LastError = applyErr.Error()
+			e.monitor.RecordTableError(pipelineID, table)
+			continue
+		}
+		st.Records += int64(n)
+		st.LastApplied = now
+		st.LastError = ""
+		if position != "" {
+			st.Position = position
+		}
+		e.monitor.RecordTableRecords(pipelineID, table, n)
+	}
+
+	if err := e.store.Save("tables/"+pipelineID, states); err != nil {
+		e.monitor.RecordError(pipelineID, "tables", err)
+	}
+}
+
+// Tables returns the per-table progress of a pipeline, including ignored
+// tables, sorted by table
+func (e *Engine) Tables(pipelineID string) ([]TableState, error) {
+	e.tablesMu.Lock()
+	defer e.tablesMu.Unlock()
+
+	states := make(map[string]*TableState)
+	if _, err := e.store.Load("tables/"+pipelineID, &states); err != nil {
+		return nil, err
+	}
+
+	out := make([]TableState, 0, len(states))
+	for _, st := range states {
+		out = append(out, *st)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Table < out[j].Table })
+	return out, nil
+}
@@ -0,0 +1,120 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: pipeline-teardown
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Connector Resource Teardown
+ */
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// ErrNotPaused is returned when tearing down a pipeline that may still run
+var ErrNotPaused = errors.New("pipeline must be paused and idle before teardown")
+
+// TeardownResource is a connector resource released for a pipeline
+type TeardownResource struct {
+	// Connector is source, target or route:<table>
+	Connector   string `json:"connector"`
+	Description string `json:"description"`
+}
+
+// TeardownReport lists the connector resources a teardown released, or
+// would release on a dry run
+type TeardownReport struct {
+	PipelineID string             `json:"pipeline_id"`
+	DryRun     bool               `json:"dry_run"`
+	Resources  []TeardownResource `json:"resources"`
+	// Unsupported lists the connectors that cannot release resources and
+	// must be cleaned up by hand
+	Unsupported []string  `json:"unsupported,omitempty"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// Teardown releases the source and target resources of a pipeline about to
+// be removed, such as replication slots and consumer groups, and clears its
+// checkpoint, which no longer resumes anything. The pipeline must be paused
+// with no run in flight. A dry run only lists the resources.
+func (e *Engine) Teardown(ctx context.Context, pipelineID string, dryRun bool) (*TeardownReport, error) {
+	p, err := e.registry.GetByID(pipelineID)
+	if err != nil {
+		return nil, err
+	}
+	if !dryRun {
+		paused, err := e.Paused(p.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !paused || e.CurrentRun(p.ID) != nil {
+			return nil, fmt.Errorf("%w: pause pipeline %s and wait for its run to finish", ErrNotPaused, p.ID)
+		}
+	}
+
+	type connectorSpec struct {
+		name, role string
+		spec       registry.ConnectorSpec
+	}
+	specs := []connectorSpec{{"source", "source", p.Source}, {"target", "target", p.Target}}
+	for _, route := range p.Routes {
+		specs = append(specs, connectorSpec{"route:" + route.Table, "target", route.Target})
+	}
+
+	report := &TeardownReport{PipelineID: p.ID, DryRun: dryRun, Resources: []TeardownResource{}}
+	var errs []error
+	for _, c := range specs {
+		conn, err := e.connector(c.spec)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", c.name, err))
+			continue
+		}
+		t, ok := conn.(connectors.Teardowner)
+		if !ok {
+			report.Unsupported = append(report.Unsupported, c.name)
+			continue
+		}
+		resources, err := t.Teardown(ctx, connectors.TeardownRequest{Role: c.role, DryRun: dryRun})
+		if errors.Is(err, connectors.ErrUnsupported) {
+			report.Unsupported = append(report.Unsupported, c.name)
+			continue
+		}
+		for _, r := range resources {
+			report.Resources = append(report.Resources, TeardownResource{Connector: c.name, Description: r})
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to tear down %s: %w", c.name, err))
+		}
+	}
+	report.CompletedAt = time.Now().UTC()
+	if dryRun {
+		return report, errors.Join(errs...)
+	}
+
+	for _, r := range report.Resources {
+		log.Printf("[Engine] Teardown of pipeline %s released %s (%s)", p.ID, r.Description, r.Connector)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return report, err
+	}
+	if err := e.store.Delete("checkpoints/" + p.ID); err != nil {
+		return report, fmt.Errorf("failed to clear checkpoint: %w", err)
+	}
+	return report, e.store.Save("teardown/"+p.ID, report)
+}
@@ -0,0 +1,168 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: pipeline-trace
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Per-Pipeline Trace Mode
+ */
+
+package engine
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/logging"
+)
+
+// Trace durations
+const (
+	DefaultTraceDuration = 10 * time.Minute
+	MaxTraceDuration     = time.Hour
+)
+
+// traceRate and traceBurst bound the trace lines logged per pipeline: a
+// token bucket refilled at traceRate lines per second
+const (
+	traceRate  = 20
+	traceBurst = 100
+)
+
+// Trace is a temporary verbose logging session of one pipeline. While it
+// lasts, connector call timings and the ID of every record applied, dropped
+// by transforms or rejected are logged with the [Trace] prefix.
+type Trace struct {
+	PipelineID string    `json:"pipeline_id"`
+	StartedAt  time.Time `json:"started_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	// Lines counts the lines logged; Suppressed those dropped by the rate
+	// limit
+	Lines      int `json:"lines"`
+	Suppressed int `json:"suppressed"`
+}
+
+// traceState is an active trace and its rate limiter
+type traceState struct {
+	Trace
+	tokens   float64
+	refilled time.Time
+	timer    *time.Timer
+}
+
+// StartTrace traces a pipeline for d (default 10 minutes, at most an
+// hour). Starting an active trace extends it.
+func (e *Engine) StartTrace(pipelineID string, d time.Duration) (*Trace, error) {
+	if _, err := e.registry.GetByID(pipelineID); err != nil {
+		return nil, err
+	}
+	if d <= 0 {
+		d = DefaultTraceDuration
+	}
+	if d > MaxTraceDuration {
+		return nil, fmt.Errorf("trace duration %s exceeds the maximum of %s", d, MaxTraceDuration)
+	}
+
+	e.tracesMu.Lock()
+	defer e.tracesMu.Unlock()
+
+	now := time.Now().UTC()
+	t := e.traces[pipelineID]
+	if t == nil {
+		t = &traceState{Trace: Trace{PipelineID: pipelineID, StartedAt: now}, tokens: traceBurst, refilled: now}
+		e.traces[pipelineID] = t
+		t.timer = time.AfterFunc(d, func() { e.endTrace(t, "expired") })
+		log.Printf("[Engine] Tracing pipeline %s for %s", pipelineID, d)
+	} else {
+		t.timer.Reset(d)
+		log.Printf("[Engine] Extending trace of pipeline %s by %s", pipelineID, d)
+	}
+	t.ExpiresAt = now.Add(d)
+	out := t.Trace
+	return &out, nil
+}
+
+// StopTrace ends the trace of a pipeline, returning nil when none is active
+func (e *Engine) StopTrace(pipelineID string) *Trace {
+	e.tracesMu.Lock()
+	t := e.traces[pipelineID]
+	e.tracesMu.Unlock()
+	if t == nil {
+		return nil
+	}
+
+	t.timer.Stop()
+	return e.endTrace(t, "stopped")
+}
+
+// ActiveTrace returns the active trace of a pipeline, or nil
+func (e *Engine) ActiveTrace(pipelineID string) *Trace {
+	e.tracesMu.Lock()
+	defer e.tracesMu.Unlock()
+
+	t := e.traces[pipelineID]
+	if t == nil {
+		return nil
+	}
+	out := t.Trace
+	return &out
+}
+
+// endTrace removes a trace unless it was replaced and logs its summary
+func (e *Engine) endTrace(t *traceState, reason string) *Trace {
+	e.tracesMu.Lock()
+	if e.traces[t.PipelineID] != t {
+		e.tracesMu.Unlock()
+		return nil
+	}
+	delete(e.traces, t.PipelineID)
+	out := t.Trace
+	e.tracesMu.Unlock()
+
+	log.Printf("[Engine] Trace of pipeline %s %s after %d lines (%d suppressed)", t.PipelineID, reason, out.Lines, out.Suppressed)
+	return &out
+}
+
+// tracing reports whether a pipeline is traced
+func (e *Engine) tracing(pipelineID string) bool {
+	e.tracesMu.Lock()
+	defer e.tracesMu.Unlock()
+
+	return e.traces[pipelineID] != nil
+}
+
+// tracef logs a trace line of a traced pipeline within its rate limit, or
+// a debug line when the debug level applies to the pipeline
+func (e *Engine) tracef(pipelineID, format string, args ...interface{}) {
+	e.tracesMu.Lock()
+	t := e.traces[pipelineID]
+	if t == nil {
+		e.tracesMu.Unlock()
+		logging.Debugf(logging.Scope{Module: "engine", Pipeline: pipelineID}, "[Engine] Pipeline %s: "+format, append([]interface{}{pipelineID}, args...)...)
+		return
+	}
+	now := time.Now()
+	t.tokens += now.Sub(t.refilled).Seconds() * traceRate
+	if t.tokens > traceBurst {
+		t.tokens = traceBurst
+	}
+	t.refilled = now
+	if t.tokens < 1 {
+		t.Suppressed++
+		e.tracesMu.Unlock()
+		return
+	}
+	t.tokens--
+	t.Lines++
+	e.tracesMu.Unlock()
+
+	log.Printf("[Trace] Pipeline %s: "+format, append([]interface{}{pipelineID}, args...)...)
+}
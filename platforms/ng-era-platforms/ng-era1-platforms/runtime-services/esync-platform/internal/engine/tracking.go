@@ -0,0 +1,94 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: error-tracker-reporting
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Failure and Panic Reporting
+ */
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/errortrack"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// reportTimeout bounds a single error tracker request
+const reportTimeout = 10 * time.Second
+
+// SetErrorReporter sends run failures and panics to an error tracker
+func (e *Engine) SetErrorReporter(r errortrack.Reporter) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.reporter = r
+}
+
+// reportFailure sends a failed run to the error tracker in the background,
+// tagged with the pipeline, run, trigger and pipeline labels
+func (e *Engine) reportFailure(p *registry.Pipeline, run *Run, errorType string, err error, stack string) {
+	e.mu.RLock()
+	reporter := e.reporter
+	e.mu.RUnlock()
+	if reporter == nil {
+		return
+	}
+
+	tags := map[string]string{"trigger": run.Trigger.Type}
+	if p.Environment != "" {
+		tags["environment"] = p.Environment
+	}
+	for k, v := range p.Labels {
+		tags["label."+k] = v
+	}
+
+	event := errortrack.Event{
+		PipelineID:  p.ID,
+		RunID:       run.ID,
+		Type:        errorType,
+		Message:     err.Error(),
+		Fingerprint: Fingerprint(errorType, err.Error()),
+		Stack:       stack,
+		Tags:        tags,
+		Timestamp:   time.Now().UTC(),
+		Owner:       p.Owner,
+		Runbook:     p.Runbook,
+		Tier:        p.Tier,
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), reportTimeout)
+		defer cancel()
+
+		if err := reporter.Report(ctx, event); err != nil {
+			log.Printf("[Engine] Failed to report error of pipeline %s: %v", p.ID, err)
+		}
+	}()
+}
+
+// recoverPanic runs fn, turning a panic into an error and returning the
+// stack trace of the panic
+func recoverPanic(fn func() error) (stack string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			stack = string(debug.Stack())
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return "", fn()
+}
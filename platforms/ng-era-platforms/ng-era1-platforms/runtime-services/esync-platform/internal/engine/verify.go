@@ -0,0 +1,175 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: target-verification
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Checksum-Based Target Verification
+ */
+
+package engine
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// maxVerificationExamples bounds the mismatching record IDs kept per run
+const maxVerificationExamples = 10
+
+// Verification is the outcome of reading back a sample of written records
+//
+// Frozen: public as engine.Verification
+type Verification struct {
+	Sampled    int `json:"sampled"`
+	Matched    int `json:"matched"`
+	Missing    int `json:"missing"`
+	Mismatched int `json:"mismatched"`
+	// Score is the share of sampled records found unchanged (0-1)
+	Score float64 `json:"score"`
+	// Fields counts mismatches per field, pointing at truncating or lossy
+	// target columns
+	Fields   map[string]int `json:"fields,omitempty"`
+	Examples []string       `json:"examples,omitempty"`
+	Skipped  string         `json:"skipped,omitempty"`
+}
+
+// verify reads back a random sample of the written records, up to the
+// pipeline sample size per run, and accumulates the result on the run
+func (e *Engine) verify(ctx context.Context, p *registry.Pipeline, target connectors.Connector, written []connectors.Record, tracker *progressTracker) {
+	if p.Verify == nil || p.Verify.SampleSize <= 0 || tracker == nil || len(written) == 0 {
+		return
+	}
+
+	if p.KeyMapping != nil {
+		tracker.verified(func(v *Verification) { v.Skipped = "records are written under mapped target IDs" })
+		return
+	}
+	reader, ok := target.(connectors.RecordReader)
+	if !ok {
+		tracker.verified(func(v *Verification) { v.Skipped = "target cannot read records back" })
+		return
+	}
+
+	var remaining int
+	tracker.verified(func(v *Verification) { remaining = p.Verify.SampleSize - v.Sampled })
+	if remaining <= 0 {
+		return
+	}
+
+	sample := make(map[string]connectors.Record)
+	for _, i := range rand.Perm(len(written)) {
+		if len(sample) >= remaining {
+			break
+		}
+		sample[written[i].ID] = written[i]
+	}
+	ids := make([]string, 0, len(sample))
+	for id := range sample {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	read, err := reader.ReadRecords(ctx, ids)
+	if errors.Is(err, connectors.ErrUnsupported) {
+		tracker.verified(func(v *Verification) { v.Skipped = "target cannot read records back" })
+		return
+	}
+	if err != nil {
+		e.recordError(p.ID, "verification", fmt.Errorf("failed to read back records: %w", err))
+		return
+	}
+	found := make(map[string]connectors.Record, len(read))
+	for _, r := range read {
+		found[r.ID] = r
+	}
+
+	tracker.verified(func(v *Verification) {
+		for _, id := range ids {
+			sent := sample[id]
+			got, exists := found[id]
+			v.Sampled++
+
+			switch {
+			case sent.Operation == connectors.OperationDelete && !exists:
+				v.Matched++
+				continue
+			case sent.Operation == connectors.OperationDelete || !exists:
+				v.Missing++
+			case sent.Operation == connectors.OperationPatch && len(diffFields(sent.Data, got.Data)) == 0:
+				v.Matched++
+				continue
+			case sent.Operation != connectors.OperationPatch && checksum(sent.Data) == checksum(got.Data):
+				v.Matched++
+				continue
+			default:
+				v.Mismatched++
+				if v.Fields == nil {
+					v.Fields = make(map[string]int)
+				}
+				for _, field := range diffFields(sent.Data, got.Data) {
+					v.Fields[field]++
+				}
+			}
+			if len(v.Examples) < maxVerificationExamples {
+				v.Examples = append(v.Examples, id)
+			}
+		}
+		v.Score = float64(v.Matched) / float64(v.Sampled)
+	})
+}
+
+// finishVerification publishes the verification score of a finished run and
+// reports it when below the pipeline minimum
+func (e *Engine) finishVerification(p *registry.Pipeline, run *Run) {
+	e.mu.RLock()
+	v := run.Verification
+	var score float64
+	var sampled int
+	if v != nil {
+		score, sampled = v.Score, v.Sampled
+	}
+	e.mu.RUnlock()
+	if sampled == 0 {
+		return
+	}
+
+	e.monitor.RecordVerification(p.ID, score)
+	if score < p.Verify.MinScore {
+		e.recordError(p.ID, "verification", fmt.Errorf("verification score %.3f below minimum %.3f (%d records sampled)", score, p.Verify.MinScore, sampled))
+	}
+}
+
+// checksum hashes record data; encoding/json sorts map keys, so equal data
+// hashes equally
+func checksum(data map[string]interface{}) [32]byte {
+	encoded, _ := json.Marshal(data)
+	return sha256.Sum256(encoded)
+}
+
+// diffFields lists the fields whose values differ between sent and got
+func diffFields(sent, got map[string]interface{}) []string {
+	var fields []string
+	for k, v := range sent {
+		if g, exists := got[k]; !exists || checksum(map[string]interface{}{k: v}) != checksum(map[string]interface{}{k: g}) {
+			fields = append(fields, k)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
@@ -0,0 +1,255 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: record-versions
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Applied Record Versions
+ */
+
+package engine
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/conflict"
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// versionCompactSegments is the number of version log segments after
+// which the log is compacted into a single one
+const versionCompactSegments = 64
+
+// version is the causality metadata of the last record applied for a key
+type version struct {
+	Clock     connectors.VectorClock `json:"clock"`
+	Timestamp time.Time              `json:"timestamp"`
+}
+
+// versionLog is the persisted log of the versions a pipeline applied,
+// which incoming records are resolved against. Each commit appends a
+// segment holding only the versions it changed.
+type versionLog struct {
+	mu         sync.Mutex
+	pipelineID string
+	// versions maps table to record ID to the applied version
+	versions map[string]map[string]version
+	// segments lists the stored segment keys in order; next is the
+	// sequence number of the next one
+	segments []string
+	next     uint64
+}
+
+// versionKey is the state key of the version log segments of a pipeline
+func versionKey(pipelineID string) string {
+	return "versions/" + pipelineID
+}
+
+// versionLog returns the version log of a pipeline, loading it on first use
+func (e *Engine) versionLog(pipelineID string) (*versionLog, error) {
+	e.versionsMu.Lock()
+	defer e.versionsMu.Unlock()
+
+	if l, exists := e.versions[pipelineID]; exists {
+		return l, nil
+	}
+	l := &versionLog{pipelineID: pipelineID, versions: make(map[string]map[string]version)}
+	keys, err := e.store.Keys(versionKey(pipelineID))
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		var segment map[string]map[string]version
+		if _, err := e.store.Load(key, &segment); err != nil {
+			return nil, err
+		}
+		l.merge(segment)
+		seq, _ := strconv.ParseUint(key[strings.LastIndex(key, "/")+1:], 10, 64)
+		l.next = seq + 1
+	}
+	l.segments = keys
+	e.versions[pipelineID] = l
+	return l, nil
+}
+
+// merge overlays versions on the log; l.mu must be held or the log unshared
+func (l *versionLog) merge(versions map[string]map[string]version) {
+	for table, ids := range versions {
+		if l.versions[table] == nil {
+			l.versions[table] = make(map[string]version, len(ids))
+		}
+		for id, v := range ids {
+			l.versions[table][id] = v
+		}
+	}
+}
+
+// get returns the applied version of a key
+func (l *versionLog) get(k connectors.Key) (version, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	v, exists := l.versions[k.Table][k.ID]
+	return v, exists
+}
+
+// commit records applied versions, persisting them as a new segment of the
+// log, and compacts the log once it has grown too many segments
+func (l *versionLog) commit(e *Engine, applied map[connectors.Key]version) error {
+	if len(applied) == 0 {
+		return nil
+	}
+	changed := make(map[string]map[string]version)
+	for k, v := range applied {
+		if changed[k.Table] == nil {
+			changed[k.Table] = make(map[string]version)
+		}
+		changed[k.Table][k.ID] = v
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := l.append(e, changed); err != nil {
+		return err
+	}
+	l.merge(changed)
+	if len(l.segments) < versionCompactSegments {
+		return nil
+	}
+
+	// The compacted segment holds every version, so the older segments
+	// can go once it is stored
+	older := l.segments
+	l.segments = nil
+	if err := l.append(e, l.versions); err != nil {
+		l.segments = older
+		return err
+	}
+	for _, key := range older {
+		if err := e.store.Delete(key); err != nil {
+			return fmt.Errorf("failed to compact record versions: %w", err)
+		}
+	}
+	return nil
+}
+
+// append stores versions as the next segment of the log; l.mu must be held
+func (l *versionLog) append(e *Engine, versions map[string]map[string]version) error {
+	key := fmt.Sprintf("%s/%020d", versionKey(l.pipelineID), l.next)
+	if err := e.store.Save(key, versions); err != nil {
+		return fmt.Errorf("failed to save record versions: %w", err)
+	}
+	l.next++
+	l.segments = append(l.segments, key)
+	return nil
+}
+
+// stamp checks the clocks of changes listed from the source of a
+// versioned pipeline against the applied versions, dropping replays the
+// source marks as old, and then advances the pipeline's counter on the
+// rest. Clocks carried by the source are compared as they arrive, since
+// ticking the counter first would order every replay after the applied
+// version. The counter continues from the last applied version of each
+// key, so successive changes of a key stay causally ordered.
+func (e *Engine) stamp(p *registry.Pipeline, changes []connectors.Record) ([]connectors.Record, error) {
+	if !p.Versioning || len(changes) == 0 {
+		return changes, nil
+	}
+	l, err := e.versionLog(p.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	latest := make(map[connectors.Key]connectors.VectorClock)
+	stamped := changes[:0:0]
+	for _, r := range changes {
+		k := r.Key()
+		clock, seen := latest[k]
+		if !seen {
+			if v, exists := l.get(k); exists {
+				clock = v.Clock
+			}
+		}
+		if len(r.Clock) > 0 && clock != nil {
+			// The applied clock carries the pipeline's own counter, which
+			// the source knows nothing about
+			sourceClock := clock.Copy()
+			delete(sourceClock, p.ID)
+			if order := r.Clock.Compare(sourceClock); order == connectors.OrderBefore || order == connectors.OrderEqual {
+				e.monitor.RecordConflict(p.ID, string(conflict.OutcomeStale))
+				e.tracef(p.ID, "record %s replays a version older than the applied one, skipped", r.ID)
+				continue
+			}
+		}
+		r.Clock = r.Clock.Merge(connectors.VectorClock{p.ID: clock[p.ID]})
+		r.Stamp(p.ID)
+		latest[k] = r.Clock
+		stamped = append(stamped, r)
+	}
+	return stamped, nil
+}
+
+// resolveConflicts resolves clocked records of a versioned pipeline
+// against the versions applied before them, dropping the records that
+// lose. Records without a clock, keys without an applied version and
+// pipelines without versioning pass through. It returns the records to
+// write and a function committing their versions once they are written.
+func (e *Engine) resolveConflicts(p *registry.Pipeline, records []connectors.Record) ([]connectors.Record, func() error, error) {
+	if !p.Versioning {
+		return records, func() error { return nil }, nil
+	}
+	l, err := e.versionLog(p.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resolver := conflict.NewResolver()
+	applied := make(map[connectors.Key]version)
+	kept := records[:0:0]
+	for _, r := range records {
+		if len(r.Clock) == 0 {
+			kept = append(kept, r)
+			continue
+		}
+		k := r.Key()
+		existing, exists := applied[k]
+		if !exists {
+			existing, exists = l.get(k)
+		}
+		if !exists {
+			applied[k] = version{Clock: r.Clock, Timestamp: r.Timestamp}
+			kept = append(kept, r)
+			continue
+		}
+
+		winner, outcome := resolver.Resolve(connectors.Record{ID: r.ID, Clock: existing.Clock, Timestamp: existing.Timestamp}, r)
+		e.monitor.RecordConflict(p.ID, string(outcome))
+		applied[k] = version{Clock: winner.Clock, Timestamp: winner.Timestamp}
+		switch {
+		case outcome == conflict.OutcomeStale:
+			e.tracef(p.ID, "record %s is older than the applied version, skipped", r.ID)
+		case outcome == conflict.OutcomeApplied:
+			kept = append(kept, r)
+		case r.Timestamp.After(existing.Timestamp):
+			// The incoming record won last-write-wins
+			r.Clock = winner.Clock
+			kept = append(kept, r)
+		default:
+			e.tracef(p.ID, "record %s lost a %s conflict with the applied version, skipped", r.ID, outcome)
+		}
+	}
+	return kept, func() error { return l.commit(e, applied) }, nil
+}
@@ -0,0 +1,163 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: run-watchdog
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Run Time Budget and Watchdog
+ */
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// watchdogTick is how often WatchRuns checks the active runs
+const watchdogTick = 5 * time.Second
+
+// watchdogPause is the pause reason of quarantined pipelines
+const watchdogPause = "watchdog"
+
+// Errors of runs stopped by the watchdog
+var (
+	ErrRunTimeout = errors.New("run time budget exceeded")
+	ErrRunStalled = errors.New("run stalled")
+)
+
+// Audit decisions of the run watchdog, recorded under the "watchdog" policy
+const (
+	AuditAlerted     = "alerted"
+	AuditCancelled   = "cancelled"
+	AuditQuarantined = "quarantined"
+)
+
+// violation is a run exceeding a bound of its pipeline watchdog
+type violation struct {
+	p      *registry.Pipeline
+	run    *Run
+	err    error
+	cancel context.CancelCauseFunc
+}
+
+// WatchRuns checks the active runs against the watchdog of their pipeline
+// every few seconds until ctx is cancelled
+func (e *Engine) WatchRuns(ctx context.Context) {
+	ticker := time.NewTicker(watchdogTick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			e.checkRuns(now)
+		}
+	}
+}
+
+// checkRuns acts on runs exceeding their time budget or making no progress
+// for the stall timeout, once per run and bound. Backfills are only checked
+// for stalls; copying a whole source routinely outlasts a sync budget. A
+// backfill waiting for its next budget period is not stalled.
+func (e *Engine) checkRuns(now time.Time) {
+	var violations []violation
+
+	e.mu.Lock()
+	running := make(map[string]bool, len(e.active))
+	for id, run := range e.active {
+		running[run.ID] = true
+		p, err := e.registry.GetByID(id)
+		if err != nil || p.Watchdog == nil {
+			continue
+		}
+
+		var kind string
+		budget := time.Duration(p.Watchdog.MaxRunDuration) * time.Second
+		stall := time.Duration(p.Watchdog.StallTimeout) * time.Second
+		last := run.StartedAt
+		if run.Progress != nil && run.Progress.UpdatedAt.After(last) {
+			last = run.Progress.UpdatedAt
+		}
+		paused := run.Progress != nil && run.Progress.PausedUntil.After(now)
+		switch {
+		case budget > 0 && run.Trigger.Type != TriggerBackfill && now.Sub(run.StartedAt) > budget:
+			kind, err = "budget", fmt.Errorf("run %s took longer than %s: %w", run.ID, budget, ErrRunTimeout)
+		case stall > 0 && !paused && now.Sub(last) > stall:
+			kind, err = "stall", fmt.Errorf("run %s made no progress for %s: %w", run.ID, stall, ErrRunStalled)
+		default:
+			continue
+		}
+		if e.watched[run.ID] == nil {
+			e.watched[run.ID] = make(map[string]bool)
+		}
+		if e.watched[run.ID][kind] {
+			continue
+		}
+		e.watched[run.ID][kind] = true
+		violations = append(violations, violation{p: p, run: snapshot(run), err: err, cancel: e.cancels[id]})
+	}
+	for id := range e.watched {
+		if !running[id] {
+			delete(e.watched, id)
+		}
+	}
+	e.mu.Unlock()
+
+	for _, v := range violations {
+		e.enforce(v)
+	}
+}
+
+// enforce reports a violation and takes the watchdog action of its pipeline
+func (e *Engine) enforce(v violation) {
+	action := v.p.Watchdog.Action
+	decision := AuditAlerted
+	switch action {
+	case registry.WatchdogCancel:
+		decision = AuditCancelled
+	case registry.WatchdogQuarantine:
+		decision = AuditQuarantined
+	}
+
+	log.Printf("[Engine] Watchdog %s pipeline %s: %v", decision, v.p.ID, v.err)
+	e.recordError(v.p.ID, "watchdog", v.err)
+	e.reportFailure(v.p, v.run, "watchdog", v.err, "")
+	e.monitor.RecordWatchdog(v.p.ID, decision)
+	e.audit(AuditEntry{PipelineID: v.p.ID, Policy: "watchdog", Decision: decision, Detail: v.err.Error()})
+
+	if decision == AuditAlerted {
+		return
+	}
+	if decision == AuditQuarantined {
+		if err := e.pauseFor(v.p.ID, watchdogPause); err != nil {
+			log.Printf("[Engine] Failed to quarantine pipeline %s: %v", v.p.ID, err)
+		}
+	}
+	if v.cancel != nil {
+		v.cancel(v.err)
+	}
+}
+
+// watchdogCause returns the watchdog error that cancelled a run context,
+// or nil
+func watchdogCause(ctx context.Context) error {
+	cause := context.Cause(ctx)
+	if errors.Is(cause, ErrRunTimeout) || errors.Is(cause, ErrRunStalled) {
+		return cause
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: alert-routing
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Ownership-Aware Alert Routing
+ */
+
+package errortrack
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// RouteConfig is an alert routing file:
+//
+//	default:
+//	  webhook: https://hooks.example.com/platform
+//	  escalation: platform-oncall
+//	routes:
+//	  - owner: team-payments
+//	    webhook: https://hooks.example.com/payments
+//	    escalation: payments-primary
+//	  - selector: tier=critical,team!=payments
+//	    webhook: https://hooks.example.com/critical
+//
+// Routes are tried in order and the first match receives the event.
+// Events no route matches go to the default route, or to the daemon's
+// error webhook when the file has none.
+type RouteConfig struct {
+	Default *Route  `yaml:"default,omitempty"`
+	Routes  []Route `yaml:"routes"`
+}
+
+// Route sends the events of matching pipelines to a webhook
+type Route struct {
+	// Name identifies the route in events; it defaults to the owner or
+	// selector matched
+	Name string `yaml:"name,omitempty"`
+	// Owner matches pipelines with this owner
+	Owner string `yaml:"owner,omitempty"`
+	// Selector matches pipelines by label, e.g. team=payments,env!=prod
+	Selector string `yaml:"selector,omitempty"`
+	// Webhook receives the events as JSON
+	Webhook string `yaml:"webhook"`
+	// Escalation names the escalation policy the receiver should page
+	Escalation string `yaml:"escalation,omitempty"`
+}
+
+// LoadRoutes reads an alert routing file
+func LoadRoutes(path string) (*RouteConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read alert routes: %w", err)
+	}
+
+	var config RouteConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse alert routes %s: %w", path, err)
+	}
+	return &config, nil
+}
+
+// Router is a reporter sending each event to the route of the pipeline's
+// owner or labels
+type Router struct {
+	routes   []route
+	fallback Reporter
+}
+
+// route is a route with its selector parsed and its reporter created
+type route struct {
+	Route
+	selector registry.Selector
+	reporter Reporter
+}
+
+// NewRouter creates a router from a routing config. Events no route
+// matches go to the config's default route, or to fallback when the config
+// has none; fallback may be nil to drop them.
+func NewRouter(config *RouteConfig, fallback Reporter) (*Router, error) {
+	r := &Router{fallback: fallback}
+	for i, rt := range config.Routes {
+		if rt.Owner == "" && rt.Selector == "" {
+			return nil, fmt.Errorf("alert route %d matches nothing: set owner or selector", i+1)
+		}
+		compiled, err := newRoute(rt)
+		if err != nil {
+			return nil, fmt.Errorf("alert route %d: %w", i+1, err)
+		}
+		r.routes = append(r.routes, compiled)
+	}
+	if config.Default != nil {
+		def := *config.Default
+		if def.Owner != "" || def.Selector != "" {
+			return nil, fmt.Errorf("the default alert route matches every event: remove its owner and selector")
+		}
+		if def.Name == "" {
+			def.Name = "default"
+		}
+		compiled, err := newRoute(def)
+		if err != nil {
+			return nil, fmt.Errorf("default alert route: %w", err)
+		}
+		r.fallback = &compiled
+	}
+	return r, nil
+}
+
+// newRoute validates a route and creates its reporter
+func newRoute(rt Route) (route, error) {
+	if rt.Webhook == "" {
+		return route{}, fmt.Errorf("webhook is required")
+	}
+	sel, err := registry.ParseSelector(rt.Selector)
+	if err != nil {
+		return route{}, err
+	}
+	if rt.Name == "" {
+		rt.Name = strings.Trim(rt.Owner+","+rt.Selector, ",")
+	}
+	return route{Route: rt, selector: sel, reporter: NewWebhook(rt.Webhook)}, nil
+}
+
+// Report implements Reporter
+func (r *Router) Report(ctx context.Context, event Event) error {
+	for i := range r.routes {
+		if r.routes[i].matches(event) {
+			return r.routes[i].Report(ctx, event)
+		}
+	}
+	if r.fallback == nil {
+		return nil
+	}
+	return r.fallback.Report(ctx, event)
+}
+
+// matches reports whether the event's pipeline has the route's owner and
+// labels
+func (rt *route) matches(event Event) bool {
+	if rt.Owner != "" && rt.Owner != event.Owner {
+		return false
+	}
+	return rt.selector.Matches(event.Labels())
+}
+
+// Report implements Reporter, naming the route and escalation policy in
+// the event
+func (rt *route) Report(ctx context.Context, event Event) error {
+	event.Route = rt.Name
+	event.Escalation = rt.Escalation
+	return rt.reporter.Report(ctx, event)
+}
+
+// Labels returns the pipeline labels carried in the event's tags
+func (e Event) Labels() map[string]string {
+	labels := make(map[string]string)
+	for k, v := range e.Tags {
+		if key, ok := strings.CutPrefix(k, "label."); ok {
+			labels[key] = v
+		}
+	}
+	return labels
+}
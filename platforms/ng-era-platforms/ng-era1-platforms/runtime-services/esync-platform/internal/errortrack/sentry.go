@@ -0,0 +1,133 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: sentry-reporter
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Sentry Reporter
+ */
+
+package errortrack
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/egress"
+)
+
+// Sentry reports events to a Sentry project through its store endpoint
+type Sentry struct {
+	endpoint    string
+	auth        string
+	environment string
+	client      *http.Client
+}
+
+// NewSentry creates a reporter from a DSN such as
+// https://<key>@o0.ingest.sentry.io/<project>
+func NewSentry(dsn, environment string) (*Sentry, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sentry DSN: %w", err)
+	}
+	key := u.User.Username()
+	project := strings.Trim(u.Path, "/")
+	if key == "" || project == "" {
+		return nil, fmt.Errorf("invalid sentry DSN: key and project are required")
+	}
+
+	// Sentry served under a path prefix keeps it before the project ID
+	prefix := ""
+	if i := strings.LastIndex(project, "/"); i >= 0 {
+		prefix, project = "/"+project[:i], project[i+1:]
+	}
+
+	return &Sentry{
+		endpoint:    fmt.Sprintf("%s://%s%s/api/%s/store/", u.Scheme, u.Host, prefix, project),
+		auth:        "Sentry sentry_version=7, sentry_client=esync/1.0, sentry_key=" + key,
+		environment: environment,
+		client:      egress.Client(10 * time.Second),
+	}, nil
+}
+
+// sentryEvent is the subset of the Sentry event payload we send
+type sentryEvent struct {
+	EventID     string                 `json:"event_id"`
+	Timestamp   string                 `json:"timestamp"`
+	Level       string                 `json:"level"`
+	Platform    string                 `json:"platform"`
+	Logger      string                 `json:"logger"`
+	Environment string                 `json:"environment,omitempty"`
+	Message     map[string]string      `json:"message"`
+	Exception   map[string]interface{} `json:"exception"`
+	Tags        map[string]string      `json:"tags"`
+	Fingerprint []string               `json:"fingerprint"`
+	Extra       map[string]string      `json:"extra,omitempty"`
+}
+
+// Report implements Reporter
+func (s *Sentry) Report(ctx context.Context, event Event) error {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return fmt.Errorf("failed to generate event id: %w", err)
+	}
+
+	tags := map[string]string{"pipeline_id": event.PipelineID, "error_type": event.Type}
+	if event.RunID != "" {
+		tags["run_id"] = event.RunID
+	}
+	for k, v := range event.Tags {
+		tags[k] = v
+	}
+	if event.Owner != "" {
+		tags["owner"] = event.Owner
+	}
+	if event.Tier != "" {
+		tags["tier"] = event.Tier
+	}
+
+	level := "error"
+	if event.Type == "panic" {
+		level = "fatal"
+	}
+
+	payload := sentryEvent{
+		EventID:     hex.EncodeToString(id),
+		Timestamp:   event.Timestamp.UTC().Format(time.RFC3339),
+		Level:       level,
+		Platform:    "go",
+		Logger:      "esync",
+		Environment: s.environment,
+		Message:     map[string]string{"formatted": event.Message},
+		Exception: map[string]interface{}{
+			"values": []map[string]string{{"type": event.Type, "value": event.Message, "module": event.PipelineID}},
+		},
+		Tags:        tags,
+		Fingerprint: []string{event.Fingerprint},
+	}
+	if event.Stack != "" || event.Runbook != "" {
+		payload.Extra = make(map[string]string)
+	}
+	if event.Stack != "" {
+		payload.Extra["stack"] = event.Stack
+	}
+	if event.Runbook != "" {
+		payload.Extra["runbook"] = event.Runbook
+	}
+
+	return postJSON(ctx, s.client, s.endpoint, http.Header{"X-Sentry-Auth": {s.auth}}, payload)
+}
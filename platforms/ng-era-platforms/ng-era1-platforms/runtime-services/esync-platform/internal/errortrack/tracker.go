@@ -0,0 +1,117 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: error-tracking
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Error Tracker Reporting
+ */
+
+// Package errortrack reports pipeline failures and panics to external
+// error trackers such as Sentry or a generic webhook
+package errortrack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/egress"
+)
+
+// Event is one failure reported to an error tracker
+type Event struct {
+	PipelineID string `json:"pipeline_id"`
+	RunID      string `json:"run_id,omitempty"`
+	// Type classifies the failure: run, backfill or panic
+	Type    string `json:"type"`
+	Message string `json:"message"`
+	// Fingerprint matches the engine's error grouping so the tracker groups
+	// events the same way as the status API
+	Fingerprint string            `json:"fingerprint"`
+	Stack       string            `json:"stack,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Timestamp   time.Time         `json:"timestamp"`
+	// Owner, Runbook and Tier tell on-call who to page and where to start
+	Owner   string `json:"owner,omitempty"`
+	Runbook string `json:"runbook,omitempty"`
+	Tier    string `json:"tier,omitempty"`
+	// Route and Escalation name the alert route that matched the event and
+	// its escalation policy
+	Route      string `json:"route,omitempty"`
+	Escalation string `json:"escalation,omitempty"`
+}
+
+// Reporter sends events to an error tracker
+type Reporter interface {
+	Report(ctx context.Context, event Event) error
+}
+
+// Multi reports every event to all reporters
+type Multi []Reporter
+
+// Report implements Reporter
+func (m Multi) Report(ctx context.Context, event Event) error {
+	var errs []error
+	for _, r := range m {
+		if err := r.Report(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Webhook posts events as JSON to a URL
+type Webhook struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhook creates a reporter posting to url
+func NewWebhook(url string) *Webhook {
+	return &Webhook{url: url, client: egress.Client(10 * time.Second)}
+}
+
+// Report implements Reporter
+func (w *Webhook) Report(ctx context.Context, event Event) error {
+	return postJSON(ctx, w.client, w.url, nil, event)
+}
+
+// postJSON sends body as JSON and fails on non-2xx responses
+func postJSON(ctx context.Context, client *http.Client, url string, header http.Header, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send event: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("error tracker responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
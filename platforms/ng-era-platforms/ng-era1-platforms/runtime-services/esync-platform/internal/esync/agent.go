@@ -0,0 +1,177 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: esync-agent
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Edge Agent Profile
+ */
+
+package esync
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/egress"
+	"github.com/machine-native-ops/esync-platform/internal/engine"
+	"github.com/machine-native-ops/esync-platform/internal/scheduler"
+	"github.com/machine-native-ops/esync-platform/pkg/client"
+)
+
+// Defaults of the agent profile
+const (
+	DefaultAgentClockSkew = engine.DefaultAgentClockSkew
+	DefaultBeaconInterval = engine.DefaultBeaconInterval
+)
+
+// beaconTimeout bounds posting one beacon
+const beaconTimeout = 30 * time.Second
+
+// AgentOptions configures the edge agent profile for nodes with
+// intermittent connectivity. Listed records are spooled in StateDir
+// before upload, so pipelines keep reading their sources while targets are
+// unreachable and uploads resume where they stopped.
+type AgentOptions struct {
+	Enabled bool
+	// ID names the agent in its beacons (default the host name)
+	ID string
+	// Central is the admin API URL of the daemon receiving a compact
+	// status beacon every BeaconInterval (default 1m) when set
+	Central        string
+	BeaconInterval time.Duration
+	// SpoolMaxBytes bounds the spool of each pipeline; sources are not
+	// read while a spool is full. Zero leaves spools unbounded.
+	SpoolMaxBytes int64
+	// ClockSkew is how far the node clock may be off (default 5m)
+	ClockSkew time.Duration
+	// Labels describe the agent to the fleet manager of the central
+	// daemon, which keeps the pipelines it assigns by them in
+	// PipelinesDir
+	Labels map[string]string
+	// Bandwidth is a bandwidth file capping the bytes the pipelines of the
+	// agent write together, by time of day
+	Bandwidth string
+}
+
+// startAgent applies the agent profile to the engine
+func (e *Engine) startAgent(eng *engine.Engine) error {
+	opts := &e.opts.Agent
+	if opts.ID == "" {
+		host, err := os.Hostname()
+		if err != nil {
+			return fmt.Errorf("agent ID required: %w", err)
+		}
+		opts.ID = host
+	}
+	if opts.Central != "" {
+		u, err := url.Parse(opts.Central)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("agent central %q must be an http or https URL", opts.Central)
+		}
+	}
+	if opts.BeaconInterval <= 0 {
+		opts.BeaconInterval = DefaultBeaconInterval
+	}
+	if opts.ClockSkew <= 0 {
+		opts.ClockSkew = DefaultAgentClockSkew
+	}
+
+	cfg := engine.AgentConfig{ID: opts.ID, SpoolMaxBytes: opts.SpoolMaxBytes, ClockSkew: opts.ClockSkew, Labels: opts.Labels}
+	if opts.Bandwidth != "" {
+		bandwidth, err := engine.LoadBandwidth(opts.Bandwidth)
+		if err != nil {
+			return err
+		}
+		cfg.Bandwidth = bandwidth
+	}
+	eng.SetAgent(cfg)
+	log.Printf("Running as edge agent %s", opts.ID)
+	return nil
+}
+
+// beacon posts the status of the agent to the central daemon every
+// interval until ctx is cancelled. Pipelines with spooled records are run
+// as soon as the central daemon is reachable again, and the pipelines the
+// central daemon assigns are applied when their revision changes and
+// acknowledged with another beacon right away.
+func (e *Engine) beacon(ctx context.Context, eng *engine.Engine, sched *scheduler.Scheduler) {
+	opts := e.opts.Agent
+	central := client.New(opts.Central).WithHTTPClient(egress.Client(beaconTimeout))
+	ticker := time.NewTicker(opts.BeaconInterval)
+	defer ticker.Stop()
+
+	online := true
+	var offset float64
+	// acking is set while the beacon acknowledges a delta just applied
+	acking := false
+	for {
+		b := eng.Beacon()
+		out := &client.AgentBeacon{
+			AgentID:     b.AgentID,
+			Version:     e.opts.Version,
+			SentAt:      b.SentAt,
+			Interval:    opts.BeaconInterval.Seconds(),
+			ClockOffset: offset,
+			Pipelines:   make([]client.BeaconPipeline, 0, len(b.Pipelines)),
+			Labels:      b.Labels,
+			Revision:    b.Revision,
+			Versions:    b.Versions,
+		}
+		for _, p := range b.Pipelines {
+			out.Pipelines = append(out.Pipelines, client.BeaconPipeline(p))
+		}
+
+		sent := time.Now()
+		status, err := central.PutBeacon(ctx, out)
+		applied := false
+		switch {
+		case ctx.Err() != nil:
+			return
+		case err != nil:
+			if online {
+				log.Printf("Central daemon %s is unreachable, spooling until it is back: %v", opts.Central, err)
+			}
+			online = false
+		default:
+			// The central daemon received the beacon about half way
+			// through the round trip
+			received := sent.Add(time.Since(sent) / 2)
+			offset = status.ReceivedAt.Sub(received).Seconds()
+			if math.Abs(offset) > opts.ClockSkew.Seconds() {
+				log.Printf("Clock of agent %s is %.0fs off the central daemon, beyond the tolerated skew of %s", opts.ID, -offset, opts.ClockSkew)
+			}
+			if !online {
+				log.Printf("Central daemon %s is reachable again, uploading spooled records", opts.Central)
+				eng.ResumeSpools(ctx)
+			}
+			online = true
+			if status.Assigned != "" && status.Assigned != b.Revision && !acking {
+				if applied, err = e.syncFleet(ctx, central, eng, sched); err != nil {
+					log.Printf("Failed to apply the pipelines assigned to agent %s: %v", opts.ID, err)
+				}
+			}
+		}
+		if acking = applied; acking {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
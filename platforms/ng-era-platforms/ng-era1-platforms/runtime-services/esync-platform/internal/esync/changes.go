@@ -0,0 +1,125 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: embedded-pipeline-changes
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Pipeline Change Subscriptions
+ */
+
+package esync
+
+import (
+	"context"
+	"sync"
+
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// PipelineChange is a pipeline added, updated or removed from the
+// registry; Pipeline is nil when it was removed
+type PipelineChange = registry.Change
+
+// Kinds of pipeline changes
+const (
+	PipelineAdded   = registry.ChangeAdded
+	PipelineUpdated = registry.ChangeUpdated
+	PipelineRemoved = registry.ChangeRemoved
+)
+
+// OnPipelineChange calls fn with every pipeline added, updated or removed
+// from now on, whether in code, by loading or reloading the definition
+// files or through the API, until cancel is called. Subscribing before
+// Start reports the pipelines Start loads. fn is called in order on a
+// goroutine of its own, so it may call back into the engine; changes queue
+// while it runs.
+func (e *Engine) OnPipelineChange(fn func(PipelineChange)) (cancel func()) {
+	return e.followPipelines(func(c PipelineChange, stop <-chan struct{}) { fn(c) }, nil)
+}
+
+// PipelineChanges returns a channel of the pipelines added, updated or
+// removed from now on, as OnPipelineChange reports them. The channel is
+// closed once ctx is done; changes queue while the receiver is busy.
+func (e *Engine) PipelineChanges(ctx context.Context) <-chan PipelineChange {
+	ch := make(chan PipelineChange)
+	cancel := e.followPipelines(func(c PipelineChange, stop <-chan struct{}) {
+		select {
+		case ch <- c:
+		case <-stop:
+		}
+	}, func() { close(ch) })
+	go func() {
+		<-ctx.Done()
+		cancel()
+	}()
+	return ch
+}
+
+// changeFeed queues the pipeline changes of a subscription for delivery
+// outside the registry lock
+type changeFeed struct {
+	mu      sync.Mutex
+	pending []PipelineChange
+	wake    chan struct{}
+	stop    chan struct{}
+}
+
+// push queues a change; it runs under the registry lock and never blocks
+func (f *changeFeed) push(c PipelineChange) {
+	f.mu.Lock()
+	f.pending = append(f.pending, c)
+	f.mu.Unlock()
+
+	select {
+	case f.wake <- struct{}{}:
+	default:
+	}
+}
+
+// followPipelines subscribes to the registry and delivers its changes in
+// order until the returned cancel is called, then calls done
+func (e *Engine) followPipelines(deliver func(c PipelineChange, stop <-chan struct{}), done func()) (cancel func()) {
+	f := &changeFeed{wake: make(chan struct{}, 1), stop: make(chan struct{})}
+	unsubscribe := e.registry.Subscribe(f.push)
+
+	go func() {
+		if done != nil {
+			defer done()
+		}
+		for {
+			select {
+			case <-f.stop:
+				return
+			case <-f.wake:
+			}
+			f.mu.Lock()
+			pending := f.pending
+			f.pending = nil
+			f.mu.Unlock()
+
+			for _, c := range pending {
+				select {
+				case <-f.stop:
+					return
+				default:
+				}
+				deliver(c, f.stop)
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			unsubscribe()
+			close(f.stop)
+		})
+	}
+}
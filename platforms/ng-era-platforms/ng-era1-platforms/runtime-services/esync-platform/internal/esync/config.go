@@ -0,0 +1,314 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: esync-config
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Daemon Config Reload
+ */
+
+package esync
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/machine-native-ops/esync-platform/internal/api"
+	"github.com/machine-native-ops/esync-platform/internal/engine"
+	"github.com/machine-native-ops/esync-platform/internal/logging"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// Log levels
+const (
+	LogLevelDebug = "debug"
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+)
+
+// ReloadReport reports what a config reload changed
+type ReloadReport = api.ReloadReport
+
+// DaemonConfig is a daemon config file. The settings it sets override the
+// options of the same name:
+//
+//	log_level: debug
+//	alert_routes: /etc/esync/routes.yaml
+//	namespace_quotas: /etc/esync/quotas.yaml
+//	digests: /etc/esync/digests.yaml
+//	api_addr: ":8080"
+//
+// Reload applies the log level, error reporting, alert routes, namespace
+// quotas, digests and agent bandwidth without a restart and reads the connection
+// profiles and pipelines again; the other settings only take effect on
+// the next start.
+type DaemonConfig struct {
+	LogLevel        string `yaml:"log_level"`
+	SentryDSN       string `yaml:"sentry_dsn"`
+	ErrorWebhookURL string `yaml:"error_webhook"`
+	AlertRoutes     string `yaml:"alert_routes"`
+	NamespaceQuotas string `yaml:"namespace_quotas"`
+	Digests         string `yaml:"digests"`
+	AgentBandwidth  string `yaml:"agent_bandwidth"`
+
+	StateDir     string   `yaml:"state_dir"`
+	PipelinesDir string   `yaml:"pipelines"`
+	Environment  string   `yaml:"env"`
+	SecretsDir   string   `yaml:"secrets_dir"`
+	APIAddr      string   `yaml:"api_addr"`
+	MetricsAddr  string   `yaml:"metrics_addr"`
+	WebhookAddr  string   `yaml:"webhook_addr"`
+	Proxy        string   `yaml:"proxy"`
+	NoProxy      []string `yaml:"no_proxy"`
+	EgressAllow  []string `yaml:"egress_allow"`
+	FIPS         bool     `yaml:"fips"`
+	PluginCgroup string   `yaml:"plugin_cgroup"`
+	RunLedger    string   `yaml:"run_ledger"`
+	RunReports   string   `yaml:"run_reports"`
+	Backups      string   `yaml:"backups"`
+	Fleet        string   `yaml:"fleet"`
+}
+
+// LoadConfig reads a daemon config file
+func LoadConfig(path string) (*DaemonConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var cfg DaemonConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	if err := validLogLevel(cfg.LogLevel); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// apply overrides the options with the settings the config sets
+func (c *DaemonConfig) apply(opts *Options) {
+	set := func(dst *string, value string) {
+		if value != "" {
+			*dst = value
+		}
+	}
+	set(&opts.LogLevel, c.LogLevel)
+	set(&opts.SentryDSN, c.SentryDSN)
+	set(&opts.ErrorWebhookURL, c.ErrorWebhookURL)
+	set(&opts.AlertRoutes, c.AlertRoutes)
+	set(&opts.NamespaceQuotas, c.NamespaceQuotas)
+	set(&opts.Digests, c.Digests)
+	set(&opts.Agent.Bandwidth, c.AgentBandwidth)
+	set(&opts.StateDir, c.StateDir)
+	set(&opts.PipelinesDir, c.PipelinesDir)
+	set(&opts.Environment, c.Environment)
+	set(&opts.SecretsDir, c.SecretsDir)
+	set(&opts.APIAddr, c.APIAddr)
+	set(&opts.MetricsAddr, c.MetricsAddr)
+	set(&opts.WebhookAddr, c.WebhookAddr)
+	set(&opts.Proxy, c.Proxy)
+	set(&opts.RunLedger, c.RunLedger)
+	set(&opts.RunReports, c.RunReports)
+	set(&opts.Backups, c.Backups)
+	set(&opts.Fleet, c.Fleet)
+	set(&opts.PluginCgroup, c.PluginCgroup)
+	if c.NoProxy != nil {
+		opts.NoProxy = c.NoProxy
+	}
+	if c.EgressAllow != nil {
+		opts.EgressAllow = c.EgressAllow
+	}
+	if c.FIPS {
+		opts.FIPS = true
+	}
+}
+
+// restartRequired lists the settings changed from prev that only take
+// effect on the next start
+func (c *DaemonConfig) restartRequired(prev *DaemonConfig) []string {
+	settings := []struct {
+		name    string
+		changed bool
+	}{
+		{"state_dir", c.StateDir != prev.StateDir},
+		{"pipelines", c.PipelinesDir != prev.PipelinesDir},
+		{"env", c.Environment != prev.Environment},
+		{"secrets_dir", c.SecretsDir != prev.SecretsDir},
+		{"api_addr", c.APIAddr != prev.APIAddr},
+		{"metrics_addr", c.MetricsAddr != prev.MetricsAddr},
+		{"webhook_addr", c.WebhookAddr != prev.WebhookAddr},
+		{"proxy", c.Proxy != prev.Proxy},
+		{"no_proxy", !slices.Equal(c.NoProxy, prev.NoProxy)},
+		{"egress_allow", !slices.Equal(c.EgressAllow, prev.EgressAllow)},
+		{"fips", c.FIPS != prev.FIPS},
+		{"plugin_cgroup", c.PluginCgroup != prev.PluginCgroup},
+		{"run_ledger", c.RunLedger != prev.RunLedger},
+		{"run_reports", c.RunReports != prev.RunReports},
+		{"backups", c.Backups != prev.Backups},
+		{"fleet", c.Fleet != prev.Fleet},
+	}
+	restart := []string{}
+	for _, s := range settings {
+		if s.changed {
+			restart = append(restart, s.name)
+		}
+	}
+	return restart
+}
+
+// validLogLevel checks a log level is known
+func validLogLevel(level string) error {
+	if level == "" {
+		return nil
+	}
+	_, err := logging.ParseLevel(level)
+	return err
+}
+
+// setLogLevel sets the log level of scopes without an override; empty is
+// info
+func setLogLevel(level string) error {
+	if level == "" {
+		level = LogLevelInfo
+	}
+	l, err := logging.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	logging.SetLevel(l)
+	return nil
+}
+
+// Reload reads the config file again and applies what changed without a
+// restart: the log level, error reporting and alert routes, namespace
+// quotas, agent bandwidth and the connection profiles and pipelines. Every
+// file is read before anything is applied, so a broken one changes
+// nothing. Changed settings that need a restart are reported, not applied.
+func (e *Engine) Reload(ctx context.Context) (*ReloadReport, error) {
+	e.reloadMu.Lock()
+	defer e.reloadMu.Unlock()
+
+	if e.engine == nil {
+		return nil, fmt.Errorf("engine not started")
+	}
+	cfg := &DaemonConfig{}
+	if e.opts.Config != "" {
+		var err error
+		if cfg, err = LoadConfig(e.opts.Config); err != nil {
+			return nil, err
+		}
+	}
+	next := e.base
+	cfg.apply(&next)
+
+	reporter, err := errorReporter(next)
+	if err != nil {
+		return nil, err
+	}
+	var quotas *engine.QuotaConfig
+	if next.NamespaceQuotas != "" {
+		if quotas, err = engine.LoadQuotas(next.NamespaceQuotas); err != nil {
+			return nil, err
+		}
+	}
+	var digests *engine.DigestConfig
+	if next.Digests != "" {
+		if digests, err = engine.LoadDigests(next.Digests); err != nil {
+			return nil, err
+		}
+	}
+	var bandwidth *registry.BandwidthSpec
+	if e.opts.Agent.Enabled && next.Agent.Bandwidth != "" {
+		if bandwidth, err = engine.LoadBandwidth(next.Agent.Bandwidth); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &ReloadReport{Applied: []string{}, RestartRequired: cfg.restartRequired(e.config), ReloadedAt: time.Now().UTC()}
+	if e.opts.PipelinesDir != "" {
+		if err := e.reloadPipelines(ctx); err != nil {
+			return nil, err
+		}
+		report.Applied = append(report.Applied, "connection_profiles", "pipelines")
+	}
+	if next.LogLevel != e.opts.LogLevel {
+		if err := setLogLevel(next.LogLevel); err != nil {
+			return nil, err
+		}
+		report.Applied = append(report.Applied, "log_level")
+	}
+	e.engine.SetErrorReporter(reporter)
+	if reporter != nil || e.opts.SentryDSN != "" || e.opts.ErrorWebhookURL != "" || e.opts.AlertRoutes != "" {
+		report.Applied = append(report.Applied, "alert_routes")
+	}
+	if err := e.engine.SetQuotas(quotas); err != nil {
+		return nil, err
+	}
+	if quotas != nil || e.opts.NamespaceQuotas != "" {
+		report.Applied = append(report.Applied, "namespace_quotas")
+	}
+	if err := e.engine.SetDigests(digests); err != nil {
+		return nil, err
+	}
+	if digests != nil || e.opts.Digests != "" {
+		report.Applied = append(report.Applied, "digests")
+	}
+	if agent := e.engine.Agent(); agent != nil {
+		agent.Bandwidth = bandwidth
+		e.engine.SetAgent(*agent)
+		if bandwidth != nil || e.opts.Agent.Bandwidth != "" {
+			report.Applied = append(report.Applied, "agent_bandwidth")
+		}
+	}
+
+	e.opts.LogLevel = next.LogLevel
+	e.opts.SentryDSN, e.opts.ErrorWebhookURL, e.opts.AlertRoutes = next.SentryDSN, next.ErrorWebhookURL, next.AlertRoutes
+	e.opts.NamespaceQuotas = next.NamespaceQuotas
+	e.opts.Digests = next.Digests
+	e.opts.Agent.Bandwidth = next.Agent.Bandwidth
+	e.config = cfg
+
+	log.Printf("Reloaded config, applied: %s", strings.Join(report.Applied, ", "))
+	if len(report.RestartRequired) > 0 {
+		log.Printf("Config changes requiring a restart: %s", strings.Join(report.RestartRequired, ", "))
+	}
+	return report, nil
+}
+
+// reloadPipelines reads the connection profiles and pipelines again and
+// schedules what changed
+func (e *Engine) reloadPipelines(ctx context.Context) error {
+	prev := make(map[string]bool)
+	for _, p := range e.registry.GetAll() {
+		prev[p.ID] = true
+	}
+	if err := e.registry.LoadAll(ctx); err != nil {
+		return fmt.Errorf("failed to reload pipelines: %w", err)
+	}
+	for _, p := range e.registry.GetAll() {
+		delete(prev, p.ID)
+		if err := e.scheduler.Schedule(p); err != nil {
+			log.Printf("Failed to schedule pipeline %s: %v", p.ID, err)
+		}
+	}
+	for id := range prev {
+		e.scheduler.Unschedule(id)
+	}
+	return nil
+}
@@ -0,0 +1,778 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: embeddable-engine-sdk
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Embeddable Sync Engine
+ */
+
+// Package esync wires the sync engine, its servers and the fleet agent
+// together. The syncd daemon is a thin wrapper around it, and pkg/engine
+// exposes it to services embedding the engine.
+package esync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/api"
+	"github.com/machine-native-ops/esync-platform/internal/backup"
+	_ "github.com/machine-native-ops/esync-platform/internal/connectors/ldap"
+	_ "github.com/machine-native-ops/esync-platform/internal/connectors/neo4j"
+	_ "github.com/machine-native-ops/esync-platform/internal/connectors/notify"
+	"github.com/machine-native-ops/esync-platform/internal/connectors/plugin"
+	_ "github.com/machine-native-ops/esync-platform/internal/connectors/scim"
+	_ "github.com/machine-native-ops/esync-platform/internal/connectors/sqlite"
+	_ "github.com/machine-native-ops/esync-platform/internal/connectors/timeseries"
+	"github.com/machine-native-ops/esync-platform/internal/cutover"
+	"github.com/machine-native-ops/esync-platform/internal/egress"
+	"github.com/machine-native-ops/esync-platform/internal/engine"
+	"github.com/machine-native-ops/esync-platform/internal/errortrack"
+	"github.com/machine-native-ops/esync-platform/internal/fips"
+	"github.com/machine-native-ops/esync-platform/internal/fleet"
+	"github.com/machine-native-ops/esync-platform/internal/listener"
+	"github.com/machine-native-ops/esync-platform/internal/monitoring"
+	"github.com/machine-native-ops/esync-platform/internal/problem"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+	"github.com/machine-native-ops/esync-platform/internal/runreport"
+	"github.com/machine-native-ops/esync-platform/internal/scheduler"
+	"github.com/machine-native-ops/esync-platform/internal/secrets"
+	"github.com/machine-native-ops/esync-platform/internal/state"
+	"github.com/machine-native-ops/esync-platform/pkg/client"
+)
+
+// Pipelines and connection profiles added to an embedded engine
+type (
+	Pipeline   = registry.Pipeline
+	Connection = registry.Connection
+)
+
+// Run results
+type (
+	Run     = engine.Run
+	Trigger = engine.Trigger
+)
+
+// DefaultHandoffLease is the HandoffLease used when none is set
+const DefaultHandoffLease = engine.DefaultHandoffLease
+
+// DefaultErrorLogBurst and DefaultErrorLogWindow are the error log limits
+// used when ErrorLogBurst and ErrorLogWindow are not set
+const (
+	DefaultErrorLogBurst  = monitoring.DefaultErrorLogBurst
+	DefaultErrorLogWindow = monitoring.DefaultErrorLogWindow
+)
+
+// defaultBackupInterval is the BackupInterval used when none is set
+const defaultBackupInterval = 24 * time.Hour
+
+// Retention bounds the run history, audit log, erasure requests and
+// fixtures kept
+type Retention = engine.Retention
+
+// MetricsPush configures pushing metrics to a Pushgateway or OTLP collector
+type MetricsPush = monitoring.PushConfig
+
+// MetricsBackend selects where metrics are sent besides the metrics
+// endpoint
+type MetricsBackend = monitoring.BackendConfig
+
+// ErrNotStarted is returned by calls that need a started engine
+var ErrNotStarted = errors.New("engine not started")
+
+// Options configures an embedded engine. Zero values disable the optional
+// servers, so an embedding service only gets what it asks for.
+//
+// Frozen: public as engine.Options
+type Options struct {
+	// Config is a daemon config file whose settings override these
+	// options; Reload reads it again
+	Config string
+	// LogLevel is debug, info (default), warn or error; the admin API
+	// overrides it for single modules, connector types and pipelines
+	LogLevel string
+	// StateDir holds checkpoints and workflow state (default "data")
+	StateDir string
+	// Version of the embedding service, recorded in StateDir so state
+	// written by a newer release is reported with the release that wrote it
+	Version string
+	// PipelinesDir optionally loads YAML pipeline definitions
+	PipelinesDir string
+	// Environment selects pipeline overlays under PipelinesDir
+	Environment string
+	// SecretsDir resolves ${secret:NAME} references (default "/run/secrets")
+	SecretsDir string
+	// Proxy routes outbound traffic through an http, https or socks5 proxy
+	Proxy string
+	// NoProxy lists hosts, *.domain wildcards or CIDR ranges reached
+	// without the proxy
+	NoProxy []string
+	// EgressAllow restricts outbound connections to these hosts,
+	// *.domain wildcards or CIDR ranges when set
+	EgressAllow []string
+	// FIPS restricts TLS, SSH tunnels and cryptographic primitives to FIPS
+	// approved ones and rejects non-compliant connector configs at startup.
+	// Binaries built with the fips tag always run in FIPS mode.
+	FIPS bool
+	// PluginCgroup is the cgroup v2 group, as listed in /proc/self/cgroup,
+	// delegated to the daemon for the groups bounding plugin connector
+	// processes on Linux. Without one, the cpu, memory and process limits of
+	// plugins are not enforced.
+	PluginCgroup string
+	// CredentialRefresh re-reads referenced secrets of open connectors at
+	// this interval, rotating credentials without a restart; zero only
+	// picks up changes when connectors are next used
+	CredentialRefresh time.Duration
+	// APIAddr serves the admin API when set. Listener addresses are
+	// comma-separated bind addresses such as ":8080", "[fd00::5]:8080",
+	// "tcp6://[::]:8080" or "unix:///run/esync/api.sock".
+	APIAddr string
+	// MetricsAddr serves metrics and health checks when set
+	MetricsAddr string
+	// WebhookAddr serves webhook triggers on a listener of their own when
+	// set; they are otherwise served by the admin API under /hooks/
+	WebhookAddr string
+	// MetricLabels lists pipeline label keys exported for metric aggregation
+	MetricLabels []string
+	// MetricsPush pushes the metrics served on MetricsAddr to a Pushgateway
+	// or OTLP collector when its URL is set
+	MetricsPush MetricsPush
+	// MetricsBackend sends the metrics to StatsD or a Datadog agent; they
+	// are only served on MetricsAddr by default
+	MetricsBackend MetricsBackend
+	// SentryDSN reports run failures and panics to Sentry when set
+	SentryDSN string
+	// ErrorWebhookURL posts run failures and panics as JSON when set
+	ErrorWebhookURL string
+	// AlertRoutes is an alert routing file sending the failures of each
+	// pipeline to the webhook of its owner or labels; failures no route
+	// matches go to its default route or ErrorWebhookURL
+	AlertRoutes string
+	// NamespaceQuotas is a quota file bounding the concurrent runs and
+	// apply workers of each pipeline namespace
+	NamespaceQuotas string
+	// Digests is a digest file sending daily or weekly reports on the
+	// pipelines of a namespace or team to notify targets
+	Digests string
+	// RunLedger is a run ledger file exporting every finished run as a
+	// record to a target connector
+	RunLedger string
+	// RunReports archives a JSON report and Markdown summary of every run
+	// to a directory or an http(s) object store prefix when set
+	RunReports string
+	// RunReportMaxAge removes archived run reports older than this from a
+	// local RunReports directory
+	RunReportMaxAge time.Duration
+	// Retention bounds the run history, audit log, erasure requests and
+	// fixtures; expired data is pruned in the background
+	Retention Retention
+	// Backups stores an archive of the daemon state every BackupInterval
+	// (default 24h) in a directory or an http(s) object store prefix when
+	// set
+	Backups        string
+	BackupInterval time.Duration
+	// BackupMaxAge removes backups older than this from a local Backups
+	// directory
+	BackupMaxAge time.Duration
+	// OrphanCheck asks the connectors for their sync-related resources
+	// every interval and reports those no registered pipeline owns; zero
+	// disables the periodic check
+	OrphanCheck time.Duration
+	// MemoryLimit sets the soft memory limit of the Go runtime in bytes, as
+	// GOMEMLIMIT does; zero keeps GOMEMLIMIT. Batch sizes shrink and bulk
+	// pipelines pause as memory use nears the limit, or the cgroup limit
+	// when neither is set.
+	MemoryLimit int64
+	// ErrorLogBurst is how many error lines each pipeline and error type
+	// may log per ErrorLogWindow before further ones are only counted;
+	// pipelines override either with their error_log setting
+	ErrorLogBurst  int
+	ErrorLogWindow time.Duration
+	// HandoffFrom is the admin API URL of a running daemon whose pipelines
+	// Start takes over: it drains them, leases them for HandoffLease
+	// (default 2m) and hands over its state, which is restored here
+	HandoffFrom  string
+	HandoffLease time.Duration
+	// Agent runs the daemon as an edge agent that spools records locally
+	// and reports to a central daemon
+	Agent AgentOptions
+	// Fleet is a fleet file assigning pipelines to the edge agents
+	// reporting to this daemon
+	Fleet string
+	// FleetKey signs the pipeline deltas of the fleet manager, and agents
+	// reject deltas it does not verify when set
+	FleetKey string
+}
+
+// Engine is an embeddable sync engine. Configuration errors from the
+// chaining methods are reported by Start or Run.
+//
+// Frozen: public as engine.Engine
+type Engine struct {
+	opts Options
+	// base is the options before the config file was applied and config
+	// the config file last applied; reloadMu serializes reloads
+	base      Options
+	config    *DaemonConfig
+	reloadMu  sync.Mutex
+	registry  *registry.Service
+	errs      []error
+	engine    *engine.Engine
+	scheduler *scheduler.Scheduler
+	cutovers  *cutover.Orchestrator
+	fleet     *fleet.Manager
+}
+
+// New creates an embedded engine
+func New(opts Options) *Engine {
+	base := opts
+	cfg := &DaemonConfig{}
+	var errs []error
+	if opts.Config != "" {
+		loaded, err := LoadConfig(opts.Config)
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			cfg = loaded
+			cfg.apply(&opts)
+		}
+	}
+	if opts.StateDir == "" {
+		opts.StateDir = "data"
+	}
+	if opts.SecretsDir == "" {
+		opts.SecretsDir = "/run/secrets"
+	}
+
+	return &Engine{
+		opts:     opts,
+		base:     base,
+		config:   cfg,
+		errs:     errs,
+		registry: registry.NewService(opts.PipelinesDir, registry.WithEnvironment(opts.Environment)),
+	}
+}
+
+// AddPipeline registers a pipeline defined in code
+func (e *Engine) AddPipeline(p *Pipeline) *Engine {
+	if err := e.registry.Add(p); err != nil {
+		e.errs = append(e.errs, err)
+	}
+	return e
+}
+
+// AddConnection registers a connection profile pipelines can reference
+func (e *Engine) AddConnection(c *Connection) *Engine {
+	if err := e.registry.AddConnection(c); err != nil {
+		e.errs = append(e.errs, err)
+	}
+	return e
+}
+
+// AddPipelineYAML registers a pipeline from its YAML definition
+func (e *Engine) AddPipelineYAML(data []byte) *Engine {
+	p, err := registry.Parse(data)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("failed to parse pipeline: %w", err))
+		return e
+	}
+	return e.AddPipeline(p)
+}
+
+// Start loads pipelines, runs pre-flight checks, resumes interrupted
+// backfills and cutovers, and starts the scheduler and any configured
+// servers. Background work stops when ctx is cancelled.
+func (e *Engine) Start(ctx context.Context) error {
+	if e.engine != nil {
+		return fmt.Errorf("engine already started")
+	}
+	if err := errors.Join(e.errs...); err != nil {
+		return err
+	}
+
+	if e.opts.FIPS {
+		fips.Enable()
+	}
+	if fips.Enabled() {
+		log.Printf("FIPS mode enabled: TLS 1.2 with approved suites and curves only")
+	}
+
+	policy := &egress.Policy{Proxy: e.opts.Proxy, NoProxy: e.opts.NoProxy, Allow: e.opts.EgressAllow}
+	if err := policy.Validate(); err != nil {
+		return fmt.Errorf("invalid egress policy: %w", err)
+	}
+	egress.SetDefault(policy)
+	plugin.SetCgroup(e.opts.PluginCgroup)
+
+	if e.opts.PipelinesDir != "" {
+		if err := e.registry.LoadAll(ctx); err != nil {
+			return fmt.Errorf("failed to load pipelines: %w", err)
+		}
+	}
+	for _, p := range e.registry.GetAll() {
+		for _, warning := range p.Warnings {
+			log.Printf("Pipeline %s: %s", p.ID, warning)
+		}
+	}
+
+	store, err := state.NewStore(e.opts.StateDir)
+	if err != nil {
+		return fmt.Errorf("failed to open state store: %w", err)
+	}
+	if _, err := store.CheckVersion(e.opts.Version); err != nil {
+		return err
+	}
+
+	monitor := monitoring.NewMonitor()
+	if e.opts.ErrorLogBurst < 0 || e.opts.ErrorLogWindow < 0 {
+		return fmt.Errorf("error log limits must not be negative")
+	}
+	monitor.SetErrorLogLimit(e.opts.ErrorLogBurst, e.opts.ErrorLogWindow)
+	e.registry.OnChange(func(id string, p *registry.Pipeline) {
+		setErrorLogLimit(monitor, id, p)
+	})
+	for _, p := range e.registry.GetAll() {
+		setErrorLogLimit(monitor, p.ID, p)
+	}
+	if len(e.opts.MetricLabels) > 0 {
+		if err := monitor.ExposePipelineLabels(e.opts.MetricLabels); err != nil {
+			return fmt.Errorf("failed to expose pipeline labels: %w", err)
+		}
+		e.registry.OnChange(func(id string, p *registry.Pipeline) {
+			if p == nil {
+				monitor.DeletePipelineLabels(id)
+				return
+			}
+			monitor.SetPipelineLabels(id, p.Labels)
+		})
+		for _, p := range e.registry.GetAll() {
+			monitor.SetPipelineLabels(p.ID, p.Labels)
+		}
+	}
+
+	eng := engine.New(e.registry, store, monitor, secrets.NewResolver(e.opts.SecretsDir))
+	if err := eng.ValidateFIPS(); err != nil {
+		return fmt.Errorf("configuration is not FIPS compliant: %w", err)
+	}
+	reporter, err := errorReporter(e.opts)
+	if err != nil {
+		return err
+	}
+	if reporter != nil {
+		eng.SetErrorReporter(reporter)
+	}
+	if err := setLogLevel(e.opts.LogLevel); err != nil {
+		return err
+	}
+	if e.opts.MemoryLimit < 0 {
+		return fmt.Errorf("memory limit must not be negative")
+	}
+	if e.opts.MemoryLimit > 0 {
+		debug.SetMemoryLimit(e.opts.MemoryLimit)
+	}
+	eng.SetRetention(e.opts.Retention)
+	if e.opts.Agent.Enabled {
+		if err := e.startAgent(eng); err != nil {
+			return err
+		}
+	}
+	if err := e.opts.MetricsPush.Validate(); err != nil {
+		return err
+	}
+	backend, err := monitoring.NewBackend(e.opts.MetricsBackend)
+	if err != nil {
+		return err
+	}
+	if e.opts.NamespaceQuotas != "" {
+		quotas, err := engine.LoadQuotas(e.opts.NamespaceQuotas)
+		if err != nil {
+			return err
+		}
+		if err := eng.SetQuotas(quotas); err != nil {
+			return err
+		}
+	}
+	if e.opts.Digests != "" {
+		digests, err := engine.LoadDigests(e.opts.Digests)
+		if err != nil {
+			return err
+		}
+		if err := eng.SetDigests(digests); err != nil {
+			return err
+		}
+	}
+	if e.opts.Fleet != "" {
+		if e.fleet, err = fleet.New(e.opts.Fleet, store, []byte(e.opts.FleetKey)); err != nil {
+			return err
+		}
+	}
+	if e.opts.RunLedger != "" {
+		ledger, err := engine.LoadLedger(e.opts.RunLedger)
+		if err != nil {
+			return err
+		}
+		eng.ExportRuns(ctx, ledger)
+	}
+	if e.opts.RunReports != "" {
+		writer := runreport.NewWriter(e.opts.RunReports)
+		e.writeRunReports(ctx, eng, writer)
+		if maxAge := e.opts.RunReportMaxAge; maxAge > 0 {
+			eng.AddPruner("run_reports", func(now time.Time) (int, int64, error) {
+				return writer.Prune(now, maxAge)
+			})
+		}
+	}
+
+	if e.opts.Backups != "" {
+		writer := backup.NewWriter(e.opts.Backups)
+		interval := e.opts.BackupInterval
+		if interval <= 0 {
+			interval = defaultBackupInterval
+		}
+		go writer.Schedule(ctx, eng, interval)
+		if maxAge := e.opts.BackupMaxAge; maxAge > 0 {
+			eng.AddPruner("backups", func(now time.Time) (int, int64, error) {
+				return writer.Prune(now, maxAge)
+			})
+		}
+	}
+
+	for _, p := range e.registry.GetAll() {
+		report, err := eng.Preflight(ctx, p.ID)
+		if err != nil {
+			return fmt.Errorf("failed to run pre-flight checks for pipeline %s: %w", p.ID, err)
+		}
+		for _, check := range report.Failures() {
+			log.Printf("Pipeline %s failed pre-flight check %s: %s (remedy: %s)", p.ID, check.Name, check.Message, check.Remedy)
+		}
+	}
+
+	listeners, err := e.listen()
+	if err != nil {
+		return err
+	}
+	if e.opts.HandoffFrom != "" {
+		if err := e.takeOver(ctx, eng); err != nil {
+			closeListeners(listeners)
+			return err
+		}
+	}
+
+	if err := eng.ResumeBackfills(ctx); err != nil {
+		log.Printf("Failed to resume backfills: %v", err)
+	}
+	if err := eng.ResumeErasures(ctx); err != nil {
+		log.Printf("Failed to resume erasure requests: %v", err)
+	}
+	if err := eng.ResumeFailovers(ctx); err != nil {
+		log.Printf("Failed to resume standby failovers: %v", err)
+	}
+
+	cutovers := cutover.NewOrchestrator(e.registry, eng, store)
+	if err := cutovers.Resume(ctx); err != nil {
+		log.Printf("Failed to resume cutovers: %v", err)
+	}
+
+	if e.opts.CredentialRefresh > 0 {
+		go eng.WatchCredentials(ctx, e.opts.CredentialRefresh)
+	}
+	go eng.WatchRetention(ctx)
+	go eng.WatchCanaries(ctx)
+	go eng.WatchActiveHours(ctx)
+	if e.opts.OrphanCheck > 0 {
+		go eng.WatchOrphans(ctx, e.opts.OrphanCheck)
+	}
+	go eng.WatchSLOs(ctx)
+	go eng.WatchDigests(ctx)
+	go eng.WatchRuns(ctx)
+	go eng.WatchMemory(ctx)
+	go eng.WatchAutoscaling(ctx)
+	go eng.WatchHoldbacks(ctx)
+	go func() {
+		<-ctx.Done()
+		eng.Stop()
+		eng.CloseConnectors()
+	}()
+
+	sched := scheduler.New(e.registry, eng)
+	if err := sched.Start(ctx); err != nil {
+		closeListeners(listeners)
+		return fmt.Errorf("failed to start scheduler: %w", err)
+	}
+	if e.opts.Agent.Enabled && e.opts.Agent.Central != "" {
+		go e.beacon(ctx, eng, sched)
+	}
+	if e.fleet != nil {
+		go e.watchFleet(ctx, eng, monitor)
+	}
+
+	if ls := listeners["metrics"]; ls != nil {
+		go func() {
+			if err := monitor.Serve(ls); err != nil {
+				log.Printf("Monitoring server stopped: %v", err)
+			}
+		}()
+	}
+	if e.opts.MetricsPush.URL != "" {
+		go monitor.Push(ctx, e.opts.MetricsPush)
+	}
+	if backend != nil {
+		go monitor.Flush(ctx, backend, e.opts.MetricsBackend.Interval)
+	}
+
+	e.engine, e.scheduler, e.cutovers = eng, sched, cutovers
+	if ls := listeners["api"]; ls != nil {
+		server := e.apiServer(ctx)
+		go func() {
+			if err := server.Serve(ls); err != nil {
+				log.Printf("Admin API stopped: %v", err)
+			}
+		}()
+	}
+	if ls := listeners["webhook"]; ls != nil {
+		mux := http.NewServeMux()
+		mux.Handle("/hooks/", sched.WebhookHandler())
+		mux.HandleFunc("/", problem.NotFound)
+		log.Printf("Starting webhook receiver on %s", listener.Addrs(ls))
+		go func() {
+			if err := listener.Serve(ls, problem.Middleware(mux)); err != nil {
+				log.Printf("Webhook receiver stopped: %v", err)
+			}
+		}()
+	}
+
+	return nil
+}
+
+// errorReporter returns the reporter of run failures the options
+// configure, or nil when they configure none
+func errorReporter(opts Options) (errortrack.Reporter, error) {
+	var reporters errortrack.Multi
+	if opts.SentryDSN != "" {
+		sentry, err := errortrack.NewSentry(opts.SentryDSN, opts.Environment)
+		if err != nil {
+			return nil, err
+		}
+		reporters = append(reporters, sentry)
+	}
+	var webhook errortrack.Reporter
+	if opts.ErrorWebhookURL != "" {
+		webhook = errortrack.NewWebhook(opts.ErrorWebhookURL)
+	}
+	if opts.AlertRoutes != "" {
+		routes, err := errortrack.LoadRoutes(opts.AlertRoutes)
+		if err != nil {
+			return nil, err
+		}
+		router, err := errortrack.NewRouter(routes, webhook)
+		if err != nil {
+			return nil, fmt.Errorf("invalid alert routes: %w", err)
+		}
+		webhook = router
+	}
+	if webhook != nil {
+		reporters = append(reporters, webhook)
+	}
+	if len(reporters) == 0 {
+		return nil, nil
+	}
+	return reporters, nil
+}
+
+// writeRunReports archives a report of every finished run
+func (e *Engine) writeRunReports(ctx context.Context, eng *engine.Engine, writer *runreport.Writer) {
+	eng.OnRunComplete(func(run *engine.Run) {
+		p, err := e.registry.GetByID(run.PipelineID)
+		if err != nil {
+			return
+		}
+		if err := writer.Write(ctx, runreport.NewReport(p, run)); err != nil {
+			log.Printf("Pipeline %s: failed to write run report: %v", run.PipelineID, err)
+		}
+	})
+}
+
+// takeOver takes the pipelines over from the daemon at HandoffFrom,
+// restoring its drained state before anything runs here
+func (e *Engine) takeOver(ctx context.Context, eng *engine.Engine) error {
+	from := client.New(e.opts.HandoffFrom).WithHTTPClient(egress.Client(10 * time.Minute))
+
+	log.Printf("Taking over pipelines from %s", e.opts.HandoffFrom)
+	var buf bytes.Buffer
+	h, err := from.BeginHandoff(ctx, e.opts.HandoffLease, &buf)
+	if err != nil {
+		return fmt.Errorf("failed to begin handoff from %s: %w", e.opts.HandoffFrom, err)
+	}
+	if _, err := eng.Restore(&buf); err != nil {
+		if _, abortErr := from.AbortHandoff(ctx, h.ID); abortErr != nil {
+			log.Printf("Failed to abort handoff %s: %v", h.ID, abortErr)
+		}
+		return fmt.Errorf("failed to restore handed off state: %w", err)
+	}
+	done, err := from.CompleteHandoff(ctx, h.ID)
+	if err != nil {
+		return fmt.Errorf("failed to complete handoff %s: %w", h.ID, err)
+	}
+	for id, msg := range done.Errors {
+		log.Printf("Pipeline %s was not drained before the handoff and resumes from its checkpoint: %s", id, msg)
+	}
+	log.Printf("Took over pipelines from %s (handoff %s)", e.opts.HandoffFrom, h.ID)
+	return nil
+}
+
+// HandedOff is closed once the pipelines of a started engine have been
+// handed off to another daemon, which is then safe to stop
+func (e *Engine) HandedOff() <-chan struct{} {
+	if e.engine == nil {
+		return nil
+	}
+	return e.engine.HandedOff()
+}
+
+// Run starts the engine and blocks until ctx is cancelled or its pipelines
+// are handed off
+func (e *Engine) Run(ctx context.Context) error {
+	if err := e.Start(ctx); err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-e.engine.HandedOff():
+	}
+	return nil
+}
+
+// listen binds the configured listeners before anything is served, so an
+// unusable address fails Start
+func (e *Engine) listen() (map[string][]net.Listener, error) {
+	addrs := map[string]string{
+		"metrics": e.opts.MetricsAddr,
+		"api":     e.opts.APIAddr,
+		"webhook": e.opts.WebhookAddr,
+	}
+	listeners := make(map[string][]net.Listener)
+	for _, name := range []string{"metrics", "api", "webhook"} {
+		if addrs[name] == "" {
+			continue
+		}
+		ls, err := listener.Listen(addrs[name])
+		if err != nil {
+			closeListeners(listeners)
+			return nil, fmt.Errorf("failed to bind %s listener: %w", name, err)
+		}
+		listeners[name] = ls
+	}
+	return listeners, nil
+}
+
+// closeListeners releases listeners bound by a failed Start
+func closeListeners(listeners map[string][]net.Listener) {
+	for _, ls := range listeners {
+		for _, l := range ls {
+			l.Close()
+		}
+	}
+}
+
+// setErrorLogLimit applies the error log limits of a pipeline, or drops
+// those of a removed one
+func setErrorLogLimit(monitor *monitoring.Monitor, id string, p *registry.Pipeline) {
+	if p == nil || p.ErrorLog == nil {
+		monitor.SetPipelineErrorLogLimit(id, 0, 0)
+		return
+	}
+	monitor.SetPipelineErrorLogLimit(id, p.ErrorLog.Burst, time.Duration(p.ErrorLog.Window)*time.Second)
+}
+
+// apiServer builds the admin API of a started engine. Webhook triggers are
+// mounted under /hooks/ unless they have a listener of their own.
+func (e *Engine) apiServer(ctx context.Context) *api.Server {
+	server := api.NewServer(ctx, e.registry, e.engine, e.cutovers)
+	server.SetFeatures(e.features())
+	server.OnPipelineAdded(e.scheduler.Schedule)
+	server.OnNextRun(e.scheduler.NextRun)
+	server.OnReload(e.Reload)
+	if e.fleet != nil {
+		server.SetFleet(e.fleet)
+	}
+	if e.opts.WebhookAddr == "" {
+		server.Mount("/hooks/", e.scheduler.WebhookHandler())
+	}
+	return server
+}
+
+// features lists the optional features enabled by the options
+func (e *Engine) features() []string {
+	r := e.opts.Retention
+	enabled := []struct {
+		name string
+		on   bool
+	}{
+		{"fips", fips.Enabled()},
+		{"proxy", e.opts.Proxy != ""},
+		{"egress_allowlist", len(e.opts.EgressAllow) > 0},
+		{"plugin_cgroup", e.opts.PluginCgroup != ""},
+		{"error_tracking", e.opts.SentryDSN != "" || e.opts.ErrorWebhookURL != "" || e.opts.AlertRoutes != ""},
+		{"alert_routing", e.opts.AlertRoutes != ""},
+		{"metrics_push", e.opts.MetricsPush.URL != ""},
+		{"statsd_metrics", e.opts.MetricsBackend.Kind == monitoring.BackendStatsD || e.opts.MetricsBackend.Kind == monitoring.BackendDogStatsD},
+		{"namespace_quotas", e.opts.NamespaceQuotas != ""},
+		{"digests", e.opts.Digests != ""},
+		{"run_ledger", e.opts.RunLedger != ""},
+		{"run_reports", e.opts.RunReports != ""},
+		{"retention", r.RunMaxAge > 0 || r.AuditMaxAge > 0 || r.AuditMaxBytes > 0 || r.ErasureMaxAge > 0 || r.FixtureMaxAge > 0 || e.opts.RunReportMaxAge > 0},
+		{"scheduled_backups", e.opts.Backups != ""},
+		{"credential_rotation", e.opts.CredentialRefresh > 0},
+		{"webhook_listener", e.opts.WebhookAddr != ""},
+		{"orphan_detection", e.opts.OrphanCheck > 0},
+		{"memory_limit", e.opts.MemoryLimit > 0},
+		{"agent", e.opts.Agent.Enabled},
+		{"fleet", e.opts.Fleet != ""},
+		{"agent_bandwidth", e.opts.Agent.Bandwidth != ""},
+		{"config_file", e.opts.Config != ""},
+	}
+	var features []string
+	for _, f := range enabled {
+		if f.on {
+			features = append(features, f.name)
+		}
+	}
+	return features
+}
+
+// Handler returns the admin API handler of a started engine so an embedding
+// service can mount it on its own listener
+func (e *Engine) Handler(ctx context.Context) http.Handler {
+	return e.apiServer(ctx).Handler()
+}
+
+// Trigger runs a pipeline once and returns the result
+func (e *Engine) Trigger(ctx context.Context, pipelineID string) (*Run, error) {
+	if e.engine == nil {
+		return nil, ErrNotStarted
+	}
+
+	return e.engine.RunOnce(ctx, pipelineID, Trigger{Type: engine.TriggerManual})
+}
+
+// Pipelines returns the registered pipelines
+func (e *Engine) Pipelines() []*Pipeline {
+	return e.registry.GetAll()
+}
@@ -0,0 +1,145 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: esync-fleet
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Fleet Coordination
+ */
+
+package esync
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/engine"
+	"github.com/machine-native-ops/esync-platform/internal/fleet"
+	"github.com/machine-native-ops/esync-platform/internal/monitoring"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+	"github.com/machine-native-ops/esync-platform/internal/scheduler"
+	"github.com/machine-native-ops/esync-platform/pkg/client"
+)
+
+// fleetMetricsInterval is how often the fleet metrics are published
+const fleetMetricsInterval = time.Minute
+
+// syncFleet fetches the delta taking the agent to the pipelines the
+// central daemon assigns and applies it: updated pipelines are registered,
+// overwriting local definitions of the same ID, and pipelines no longer
+// assigned are removed. The versions applied are recorded for the next
+// beacon to acknowledge, even when part of the delta failed, so a retry
+// only carries the rest. It reports whether anything was applied.
+func (e *Engine) syncFleet(ctx context.Context, central *client.Client, eng *engine.Engine, sched *scheduler.Scheduler) (bool, error) {
+	prev, err := eng.FleetState()
+	if err != nil {
+		return false, err
+	}
+	cd, err := central.FleetDelta(ctx, e.opts.Agent.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch fleet delta: %w", err)
+	}
+	delta := fleet.Delta(*cd)
+	if e.opts.FleetKey != "" {
+		if err := delta.Verify([]byte(e.opts.FleetKey)); err != nil {
+			return false, err
+		}
+	}
+	if delta.AgentID != e.opts.Agent.ID {
+		return false, fmt.Errorf("fleet delta is for agent %s", delta.AgentID)
+	}
+
+	next := &engine.FleetState{Revision: prev.Revision, Versions: make(map[string]uint64), AppliedAt: time.Now().UTC()}
+	for id, version := range prev.Versions {
+		next.Versions[id] = version
+	}
+	complete := true
+	if len(delta.Updated) > 0 {
+		report, err := e.registry.Import(bytes.NewReader(delta.Bundle), registry.ConflictOverwrite, false)
+		if err != nil {
+			return false, err
+		}
+		for _, result := range report.Pipelines {
+			if result.Action == "skipped" {
+				log.Printf("Skipped fleet pipeline %s: %s", result.ID, result.Reason)
+				complete = false
+				continue
+			}
+			next.Versions[result.ID] = delta.Versions[result.ID]
+			p, err := e.registry.GetByID(result.ID)
+			if err == nil {
+				err = sched.Schedule(p)
+			}
+			if err != nil {
+				log.Printf("Failed to schedule fleet pipeline %s: %v", result.ID, err)
+			}
+		}
+	}
+	for _, id := range delta.Removed {
+		sched.Unschedule(id)
+		if _, err := e.registry.GetByID(id); err == nil {
+			if err := e.registry.Remove(id); err != nil {
+				log.Printf("Failed to remove fleet pipeline %s: %v", id, err)
+				complete = false
+				continue
+			}
+		}
+		delete(next.Versions, id)
+	}
+	if complete {
+		next.Revision = delta.Revision
+	}
+
+	if err := eng.SaveFleetState(next); err != nil {
+		return false, err
+	}
+	log.Printf("Applied fleet delta with %d updated and %d removed pipelines (revision %.12s)", len(delta.Updated), len(delta.Removed), next.Revision)
+	return true, nil
+}
+
+// watchFleet publishes the status of the managed fleet as metrics until
+// ctx is cancelled
+func (e *Engine) watchFleet(ctx context.Context, eng *engine.Engine, monitor *monitoring.Monitor) {
+	ticker := time.NewTicker(fleetMetricsInterval)
+	defer ticker.Stop()
+
+	for {
+		agents, err := eng.Agents()
+		if err == nil {
+			err = e.fleet.Annotate(agents...)
+		}
+		if err != nil {
+			log.Printf("Failed to aggregate fleet status: %v", err)
+		} else {
+			status := e.fleet.Status(agents)
+			states := map[string]int{
+				"online":   status.Online,
+				"offline":  status.Agents - status.Online,
+				"outdated": status.Outdated,
+			}
+			statuses := make(map[string]map[string]int)
+			spooled := make(map[string]int)
+			for _, p := range status.Pipelines {
+				statuses[p.ID] = p.Statuses
+				spooled[p.ID] = p.Spooled
+			}
+			monitor.RecordFleet(states, statuses, spooled)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
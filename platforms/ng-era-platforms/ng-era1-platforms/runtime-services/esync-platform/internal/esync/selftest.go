@@ -0,0 +1,205 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: esync-self-test
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Startup Self-Test
+ */
+
+package esync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/egress"
+	"github.com/machine-native-ops/esync-platform/internal/engine"
+	"github.com/machine-native-ops/esync-platform/internal/fips"
+	"github.com/machine-native-ops/esync-platform/internal/monitoring"
+	"github.com/machine-native-ops/esync-platform/internal/secrets"
+	"github.com/machine-native-ops/esync-platform/internal/state"
+)
+
+// Self-test results
+type (
+	CheckResult     = connectors.CheckResult
+	PreflightReport = engine.PreflightReport
+	DryRun          = engine.DryRun
+)
+
+// SelfTestReport is the result of a self-test, meant to gate deployments
+//
+// Frozen: public as engine.SelfTestReport
+type SelfTestReport struct {
+	Passed  bool   `json:"passed"`
+	Version string `json:"version,omitempty"`
+	// Config holds the checks of the daemon config, the files it names,
+	// the pipeline definitions and the state directory
+	Config    []CheckResult      `json:"config"`
+	Pipelines []PipelineSelfTest `json:"pipelines"`
+	StartedAt time.Time          `json:"started_at"`
+	// DurationSeconds is how long the whole self-test took
+	DurationSeconds float64 `json:"duration_seconds"`
+}
+
+// PipelineSelfTest is the self-test of one pipeline: its pre-flight checks
+// and, once they pass, a dry-run batch against its source
+//
+// Frozen: public as engine.PipelineSelfTest
+type PipelineSelfTest struct {
+	PipelineID string           `json:"pipeline_id"`
+	Passed     bool             `json:"passed"`
+	Preflight  *PreflightReport `json:"preflight,omitempty"`
+	DryRun     *DryRun          `json:"dry_run,omitempty"`
+	Error      string           `json:"error,omitempty"`
+}
+
+// SelfTest validates the configuration, loads the pipelines, runs the
+// pre-flight checks of every pipeline and a dry-run batch against each
+// source, instead of starting. It writes no state and starts no servers or
+// background work; a failed check is reported, not returned as an error.
+func (e *Engine) SelfTest(ctx context.Context) (*SelfTestReport, error) {
+	if e.engine != nil {
+		return nil, fmt.Errorf("engine already started")
+	}
+
+	report := &SelfTestReport{Version: e.opts.Version, Config: []CheckResult{}, Pipelines: []PipelineSelfTest{}, StartedAt: time.Now().UTC()}
+	check := func(name string, configured bool, run func() error, remedy string) bool {
+		result := CheckResult{Name: name, Status: connectors.CheckPassed}
+		if !configured {
+			result.Status, result.Message = connectors.CheckSkipped, "not configured"
+		} else if err := run(); err != nil {
+			result.Status, result.Message, result.Remedy = connectors.CheckFailed, err.Error(), remedy
+		}
+		report.Config = append(report.Config, result)
+		return result.Status != connectors.CheckFailed
+	}
+
+	check("config", true, func() error { return errors.Join(e.errs...) },
+		"fix the config file and the pipelines added in code")
+	check("log_level", true, func() error { return validLogLevel(e.opts.LogLevel) },
+		"use debug, info, warn or error")
+	if e.opts.FIPS {
+		fips.Enable()
+	}
+	check("egress_policy", true, func() error {
+		policy := &egress.Policy{Proxy: e.opts.Proxy, NoProxy: e.opts.NoProxy, Allow: e.opts.EgressAllow}
+		if err := policy.Validate(); err != nil {
+			return err
+		}
+		egress.SetDefault(policy)
+		return nil
+	}, "fix the proxy, no-proxy and egress-allow settings")
+	check("error_reporting", e.opts.SentryDSN != "" || e.opts.ErrorWebhookURL != "" || e.opts.AlertRoutes != "", func() error {
+		_, err := errorReporter(e.opts)
+		return err
+	}, "fix the Sentry DSN, error webhook or alert routes file")
+	check("metrics", true, func() error {
+		if err := e.opts.MetricsPush.Validate(); err != nil {
+			return err
+		}
+		return e.opts.MetricsBackend.Validate()
+	}, "fix the metrics push and metrics backend settings")
+	check("run_ledger", e.opts.RunLedger != "", func() error {
+		_, err := engine.LoadLedger(e.opts.RunLedger)
+		return err
+	}, "fix the run ledger file")
+	check("agent_bandwidth", e.opts.Agent.Enabled && e.opts.Agent.Bandwidth != "", func() error {
+		_, err := engine.LoadBandwidth(e.opts.Agent.Bandwidth)
+		return err
+	}, "fix the agent bandwidth file")
+	pipelines := check("pipelines", e.opts.PipelinesDir != "", func() error {
+		return e.registry.LoadAll(ctx)
+	}, "fix the pipeline definitions and connection profiles")
+
+	var store *state.Store
+	stored := check("state", true, func() error {
+		var err error
+		if store, err = state.NewStore(e.opts.StateDir); err != nil {
+			return err
+		}
+		_, err = store.ReadVersion()
+		return err
+	}, "check the state directory is writable and was written by this or an older release")
+	if !stored {
+		return report.finish(), nil
+	}
+
+	eng := engine.New(e.registry, store, monitoring.NewMonitor(), secrets.NewResolver(e.opts.SecretsDir))
+	defer eng.CloseConnectors()
+	defer eng.Stop()
+	check("fips", fips.Enabled(), eng.ValidateFIPS, "remove the non-compliant settings from the connector configs")
+	check("namespace_quotas", e.opts.NamespaceQuotas != "", func() error {
+		quotas, err := engine.LoadQuotas(e.opts.NamespaceQuotas)
+		if err != nil {
+			return err
+		}
+		return eng.SetQuotas(quotas)
+	}, "fix the namespace quotas file")
+	check("digests", e.opts.Digests != "", func() error {
+		digests, err := engine.LoadDigests(e.opts.Digests)
+		if err != nil {
+			return err
+		}
+		return eng.SetDigests(digests)
+	}, "fix the digests file")
+
+	if pipelines {
+		for _, p := range e.registry.GetAll() {
+			report.Pipelines = append(report.Pipelines, selfTestPipeline(ctx, eng, p.ID))
+		}
+	}
+	return report.finish(), nil
+}
+
+// selfTestPipeline runs the pre-flight checks of a pipeline and, when they
+// pass, a dry-run batch
+func selfTestPipeline(ctx context.Context, eng *engine.Engine, pipelineID string) PipelineSelfTest {
+	result := PipelineSelfTest{PipelineID: pipelineID}
+	preflight, err := eng.Preflight(ctx, pipelineID)
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to run pre-flight checks: %v", err)
+		return result
+	}
+	result.Preflight = preflight
+	if !preflight.Passed {
+		return result
+	}
+
+	dryRun, err := eng.DryRun(ctx, pipelineID)
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to run dry-run batch: %v", err)
+		return result
+	}
+	result.DryRun = dryRun
+	result.Passed = dryRun.Passed
+	return result
+}
+
+// finish decides whether the self-test passed and records its duration
+func (r *SelfTestReport) finish() *SelfTestReport {
+	r.Passed = true
+	for _, c := range r.Config {
+		if c.Status == connectors.CheckFailed {
+			r.Passed = false
+		}
+	}
+	for _, p := range r.Pipelines {
+		if !p.Passed {
+			r.Passed = false
+		}
+	}
+	r.DurationSeconds = time.Since(r.StartedAt).Seconds()
+	return r
+}
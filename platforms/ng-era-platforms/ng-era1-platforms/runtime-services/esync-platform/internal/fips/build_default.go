@@ -0,0 +1,23 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+
+//go:build !fips
+
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: fips-mode
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Default Build Mode
+ */
+
+package fips
+
+// buildEnabled leaves FIPS mode to Enable in default builds
+const buildEnabled = false
@@ -0,0 +1,231 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: fips-mode
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * FIPS Approved-Crypto Mode
+ */
+
+// Package fips restricts the daemon to FIPS 140 approved cryptography. The
+// mode is switched on by building with the fips tag or by Enable at
+// startup. When on, TLS is limited to approved versions, suites and curves,
+// features using hashing, encryption or signatures must name approved
+// primitives, and Validate rejects connector configs that would weaken TLS.
+package fips
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// ErrNotApproved is returned for primitives and settings outside the
+// approved set
+var ErrNotApproved = errors.New("not FIPS approved")
+
+// Primitive kinds checked by Check
+const (
+	KindHash      = "hash"
+	KindMAC       = "mac"
+	KindCipher    = "cipher"
+	KindSignature = "signature"
+)
+
+// approved lists the primitives allowed in FIPS mode by kind
+var approved = map[string]map[string]bool{
+	KindHash: {
+		"sha224": true, "sha256": true, "sha384": true, "sha512": true,
+		"sha3-256": true, "sha3-384": true, "sha3-512": true,
+	},
+	KindMAC: {
+		"hmac-sha256": true, "hmac-sha384": true, "hmac-sha512": true,
+	},
+	KindCipher: {
+		"aes-128-gcm": true, "aes-192-gcm": true, "aes-256-gcm": true,
+		"aes-128-ctr": true, "aes-192-ctr": true, "aes-256-ctr": true,
+		"aes-128-cbc": true, "aes-192-cbc": true, "aes-256-cbc": true,
+	},
+	KindSignature: {
+		"ecdsa-p256": true, "ecdsa-p384": true, "ecdsa-p521": true,
+		"rsa-pss-sha256": true, "rsa-pss-sha384": true, "rsa-pss-sha512": true,
+		"rsa-pkcs1-sha256": true, "rsa-pkcs1-sha384": true, "rsa-pkcs1-sha512": true,
+	},
+}
+
+// cipherSuites are the approved TLS 1.2 suites
+var cipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+}
+
+// tls13Suites are the approved TLS 1.3 suites. crypto/tls offers every
+// TLS 1.3 suite regardless of the config, so TLSConfig fails handshakes
+// that settle on another.
+var tls13Suites = []uint16{
+	tls.TLS_AES_128_GCM_SHA256,
+	tls.TLS_AES_256_GCM_SHA384,
+}
+
+// curves are the approved key exchange curves
+var curves = []tls.CurveID{tls.CurveP256, tls.CurveP384, tls.CurveP521}
+
+var enabled atomic.Bool
+
+func init() {
+	enabled.Store(buildEnabled)
+}
+
+// Enable switches FIPS mode on for the rest of the process
+func Enable() {
+	enabled.Store(true)
+}
+
+// Enabled reports whether FIPS mode is on
+func Enabled() bool {
+	return enabled.Load()
+}
+
+// Check returns ErrNotApproved when FIPS mode is on and name is not an
+// approved primitive of kind
+func Check(kind, name string) error {
+	if !Enabled() || approved[kind][strings.ToLower(name)] {
+		return nil
+	}
+	return fmt.Errorf("%s %s: %w (approved: %s)", kind, name, ErrNotApproved, strings.Join(Approved(kind), ", "))
+}
+
+// Approved lists the approved primitives of kind
+func Approved(kind string) []string {
+	names := make([]string, 0, len(approved[kind]))
+	for name := range approved[kind] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// TLSConfig restricts a TLS config to approved versions, suites and curves
+// when FIPS mode is on: TLS 1.2 or 1.3, keeping a minimum of 1.3 the config
+// already sets. A nil config starts from the defaults; with FIPS mode off
+// the config is returned unchanged.
+func TLSConfig(config *tls.Config) *tls.Config {
+	if !Enabled() {
+		return config
+	}
+	if config == nil {
+		config = &tls.Config{}
+	} else {
+		config = config.Clone()
+	}
+	if config.MinVersion < tls.VersionTLS12 {
+		config.MinVersion = tls.VersionTLS12
+	}
+	config.MaxVersion = tls.VersionTLS13
+	config.CipherSuites = cipherSuites
+	config.CurvePreferences = curves
+	config.InsecureSkipVerify = false
+
+	verify := config.VerifyConnection
+	config.VerifyConnection = func(state tls.ConnectionState) error {
+		if !approvedSuiteID(state.CipherSuite) {
+			return fmt.Errorf("tls cipher suite %s: %w", tls.CipherSuiteName(state.CipherSuite), ErrNotApproved)
+		}
+		if verify != nil {
+			return verify(state)
+		}
+		return nil
+	}
+	return config
+}
+
+// SSHOptions returns ssh client options limiting ciphers, MACs, key
+// exchange and host key algorithms to approved ones, or nil when FIPS mode
+// is off
+func SSHOptions() []string {
+	if !Enabled() {
+		return nil
+	}
+	return []string{
+		"-o", "Ciphers=aes256-gcm@openssh.com,aes128-gcm@openssh.com,aes256-ctr,aes192-ctr,aes128-ctr",
+		"-o", "MACs=hmac-sha2-512,hmac-sha2-256",
+		"-o", "KexAlgorithms=ecdh-sha2-nistp384,ecdh-sha2-nistp256,diffie-hellman-group16-sha512,diffie-hellman-group14-sha256",
+		"-o", "HostKeyAlgorithms=ecdsa-sha2-nistp384,ecdsa-sha2-nistp256,rsa-sha2-512,rsa-sha2-256",
+		"-o", "PubkeyAcceptedAlgorithms=ecdsa-sha2-nistp384,ecdsa-sha2-nistp256,rsa-sha2-512,rsa-sha2-256",
+	}
+}
+
+// Validate checks the tls and tunnel blocks of a connector config. It
+// returns nil when FIPS mode is off.
+func Validate(config map[string]interface{}) error {
+	if !Enabled() {
+		return nil
+	}
+
+	var errs []error
+	if block, ok := config["tls"].(map[string]interface{}); ok {
+		if skip, _ := block["insecure_skip_verify"].(bool); skip {
+			errs = append(errs, fmt.Errorf("tls.insecure_skip_verify: %w", ErrNotApproved))
+		}
+		if v, ok := block["min_version"]; ok {
+			switch fmt.Sprint(v) {
+			case "1.2", "1.3":
+			default:
+				errs = append(errs, fmt.Errorf("tls.min_version %v: %w (use 1.2 or 1.3)", v, ErrNotApproved))
+			}
+		}
+		if list, ok := block["cipher_suites"].([]interface{}); ok {
+			for _, suite := range list {
+				if !approvedSuite(fmt.Sprint(suite)) {
+					errs = append(errs, fmt.Errorf("tls.cipher_suites %v: %w", suite, ErrNotApproved))
+				}
+			}
+		}
+	}
+	if block, ok := config["tunnel"].(map[string]interface{}); ok {
+		key, _ := block["host_key"].(string)
+		if fields := strings.Fields(key); len(fields) > 0 {
+			switch fields[0] {
+			case "ecdsa-sha2-nistp256", "ecdsa-sha2-nistp384", "ecdsa-sha2-nistp521", "ssh-rsa":
+			default:
+				errs = append(errs, fmt.Errorf("tunnel.host_key type %s: %w", fields[0], ErrNotApproved))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// approvedSuite reports whether a TLS 1.2 suite name is approved
+func approvedSuite(name string) bool {
+	for _, id := range cipherSuites {
+		if tls.CipherSuiteName(id) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// approvedSuiteID reports whether a negotiated TLS 1.2 or 1.3 suite is
+// approved
+func approvedSuiteID(suite uint16) bool {
+	for _, suites := range [][]uint16{cipherSuites, tls13Suites} {
+		for _, id := range suites {
+			if id == suite {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,230 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: fleet-delta
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Fleet Pipeline Deltas
+ */
+
+package fleet
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/machine-native-ops/esync-platform/internal/fips"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// versionsKey is where the central daemon keeps the pipeline versions
+const versionsKey = "fleet-versions"
+
+// ErrSignature is returned for deltas whose signature does not verify
+var ErrSignature = errors.New("invalid fleet delta signature")
+
+// signatureMAC is the MAC deltas are signed with
+const signatureMAC = "hmac-sha256"
+
+// Version is the version of a fleet pipeline
+type Version struct {
+	Version uint64 `json:"version"`
+	// Digest identifies the content of the version
+	Digest string `json:"digest"`
+	// Removed is set once the pipeline left the fleet; the version is
+	// kept so it keeps counting up if the pipeline comes back
+	Removed bool `json:"removed,omitempty"`
+}
+
+// Delta is what an agent applies to converge on the pipelines assigned to
+// it, from the versions it acknowledged in its last beacon
+type Delta struct {
+	AgentID string `json:"agent_id"`
+	// Revision is the revision the agent reaches by applying the delta
+	Revision string `json:"revision"`
+	// Versions is the version vector of the pipelines assigned to the
+	// agent
+	Versions map[string]uint64 `json:"versions"`
+	// Updated lists the pipelines the agent lacks or holds an older
+	// version of, whose definitions Bundle holds
+	Updated []string `json:"updated"`
+	Removed []string `json:"removed"`
+	Bundle  []byte   `json:"bundle,omitempty"`
+	// Signature is the hex HMAC-SHA256 of the delta without signature
+	// under the fleet key
+	Signature string `json:"signature,omitempty"`
+}
+
+// pipelineDigests returns the digest of the definition, overlays and
+// connection profiles of every pipeline of a registry
+func pipelineDigests(reg *registry.Service) (map[string]string, error) {
+	digests := make(map[string]string)
+	for _, p := range reg.Select(nil) {
+		id := p.ID
+		manifest, err := reg.ExportMatching(io.Discard, "", func(q *registry.Pipeline) bool { return q.ID == id })
+		if err != nil {
+			return nil, fmt.Errorf("failed to export fleet pipeline %s: %w", id, err)
+		}
+		digests[id] = manifest.Digest
+	}
+	return digests, nil
+}
+
+// nextVersions bumps the version of every pipeline whose digest changed,
+// came back or left the fleet
+func nextVersions(prev map[string]*Version, digests map[string]string) (map[string]*Version, bool) {
+	next := make(map[string]*Version, len(prev))
+	changed := false
+	for id, v := range prev {
+		copied := *v
+		next[id] = &copied
+	}
+	for id, digest := range digests {
+		v, ok := next[id]
+		switch {
+		case !ok:
+			next[id] = &Version{Version: 1, Digest: digest}
+		case v.Removed || v.Digest != digest:
+			*v = Version{Version: v.Version + 1, Digest: digest}
+		default:
+			continue
+		}
+		changed = true
+	}
+	for id, v := range next {
+		if _, ok := digests[id]; !ok && !v.Removed {
+			*v = Version{Version: v.Version + 1, Digest: v.Digest, Removed: true}
+			changed = true
+		}
+	}
+	return next, changed
+}
+
+// Versions returns the version vector of the fleet pipelines
+func (m *Manager) Versions() map[string]uint64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	versions := make(map[string]uint64)
+	for id, v := range m.versions {
+		if !v.Removed {
+			versions[id] = v.Version
+		}
+	}
+	return versions
+}
+
+// changes returns the assigned pipelines an agent holding the acknowledged
+// versions lacks or holds an older version of, and the pipelines it holds
+// that are no longer assigned
+func (m *Manager) changes(labels map[string]string, acked map[string]uint64) (updated, removed []string) {
+	versions := m.Versions()
+	assigned := make(map[string]bool)
+	for _, id := range m.Assigned(labels) {
+		assigned[id] = true
+		if acked[id] < versions[id] {
+			updated = append(updated, id)
+		}
+	}
+	for id := range acked {
+		if !assigned[id] {
+			removed = append(removed, id)
+		}
+	}
+	sort.Strings(removed)
+	return updated, removed
+}
+
+// Delta returns the signed delta taking an agent from the acknowledged
+// versions to the pipelines assigned to it
+func (m *Manager) Delta(agentID string, labels map[string]string, acked map[string]uint64) (*Delta, error) {
+	revision, err := m.Revision(labels)
+	if err != nil {
+		return nil, err
+	}
+	updated, removed := m.changes(labels, acked)
+	d := &Delta{
+		AgentID:  agentID,
+		Revision: revision,
+		Versions: make(map[string]uint64),
+		Updated:  []string{},
+		Removed:  []string{},
+	}
+	versions := m.Versions()
+	for _, id := range m.Assigned(labels) {
+		d.Versions[id] = versions[id]
+	}
+	d.Removed = append(d.Removed, removed...)
+
+	if len(updated) > 0 {
+		include := make(map[string]bool)
+		for _, id := range updated {
+			include[id] = true
+		}
+		var buf bytes.Buffer
+		m.mu.RLock()
+		manifest, err := m.registry.ExportMatching(&buf, "agent "+agentID, func(p *registry.Pipeline) bool { return include[p.ID] })
+		m.mu.RUnlock()
+		if err != nil {
+			return nil, err
+		}
+		d.Updated, d.Bundle = manifest.Pipelines, buf.Bytes()
+	}
+
+	if len(m.key) > 0 {
+		if d.Signature, err = d.sign(m.key); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// sign returns the signature of the delta under key
+func (d *Delta) sign(key []byte) (string, error) {
+	if err := fips.Check(fips.KindMAC, signatureMAC); err != nil {
+		return "", err
+	}
+	unsigned := *d
+	unsigned.Signature = ""
+	data, err := json.Marshal(&unsigned)
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// Verify checks the signature of a delta under the fleet key
+func (d *Delta) Verify(key []byte) error {
+	if d.Signature == "" {
+		return fmt.Errorf("%w: delta is not signed", ErrSignature)
+	}
+	sig, err := hex.DecodeString(d.Signature)
+	if err != nil {
+		return ErrSignature
+	}
+	want, err := d.sign(key)
+	if err != nil {
+		return err
+	}
+	expected, _ := hex.DecodeString(want)
+	if !hmac.Equal(sig, expected) {
+		return ErrSignature
+	}
+	return nil
+}
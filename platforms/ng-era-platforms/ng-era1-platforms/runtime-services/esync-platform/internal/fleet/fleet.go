@@ -0,0 +1,340 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: fleet-manager
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Fleet Manager
+ */
+
+// Package fleet lets a central daemon manage many edge agents. A fleet
+// file names a directory of pipeline definitions and assigns them to
+// agents by label:
+//
+//	pipelines: fleet/pipelines
+//	assignments:
+//	  - agents: format=express
+//	    pipelines: tier=store
+//	  - agents: region=eu,format!=express
+//	    pipelines: tier=store,pos
+//
+// Agents receive every pipeline an assignment matching their labels
+// selects; empty selectors match everything. The fleet pipelines are only
+// distributed, never run by the central daemon. Each agent's set of
+// pipelines is identified by the digest of its bundle, the revision the
+// agent reports back once it applied it.
+//
+// Every fleet pipeline has a version, bumped whenever its definition,
+// overlays or connection profiles change. Agents report the versions they
+// applied in their beacons, and receive signed deltas holding only the
+// pipelines they lack or hold an older version of, and the pipelines to
+// remove.
+package fleet
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/machine-native-ops/esync-platform/internal/engine"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+	"github.com/machine-native-ops/esync-platform/internal/state"
+)
+
+// Config is a fleet file
+type Config struct {
+	// Pipelines is the directory of the distributed pipeline definitions,
+	// relative to the fleet file
+	Pipelines   string       `yaml:"pipelines"`
+	Assignments []Assignment `yaml:"assignments"`
+}
+
+// Assignment gives the agents matching a label selector the pipelines
+// matching another
+type Assignment struct {
+	Agents    string `yaml:"agents"`
+	Pipelines string `yaml:"pipelines"`
+}
+
+// assignment is a parsed Assignment
+type assignment struct {
+	agents    registry.Selector
+	pipelines registry.Selector
+}
+
+// Load reads a fleet file
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fleet file: %w", err)
+	}
+
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse fleet file %s: %w", path, err)
+	}
+	if config.Pipelines == "" {
+		return nil, fmt.Errorf("invalid fleet file %s: pipelines directory is required", path)
+	}
+	if !filepath.IsAbs(config.Pipelines) {
+		config.Pipelines = filepath.Join(filepath.Dir(path), config.Pipelines)
+	}
+	if _, err := config.parse(); err != nil {
+		return nil, fmt.Errorf("invalid fleet file %s: %w", path, err)
+	}
+	return &config, nil
+}
+
+// parse parses the selectors of the assignments
+func (c *Config) parse() ([]assignment, error) {
+	assignments := make([]assignment, 0, len(c.Assignments))
+	for i, a := range c.Assignments {
+		agents, err := registry.ParseSelector(a.Agents)
+		if err != nil {
+			return nil, fmt.Errorf("assignment %d: agents: %w", i+1, err)
+		}
+		pipelines, err := registry.ParseSelector(a.Pipelines)
+		if err != nil {
+			return nil, fmt.Errorf("assignment %d: pipelines: %w", i+1, err)
+		}
+		assignments = append(assignments, assignment{agents: agents, pipelines: pipelines})
+	}
+	return assignments, nil
+}
+
+// Manager distributes the fleet pipelines to agents
+type Manager struct {
+	path  string
+	store *state.Store
+	// key signs deltas when set
+	key []byte
+
+	mu          sync.RWMutex
+	registry    *registry.Service
+	assignments []assignment
+	versions    map[string]*Version
+}
+
+// New creates a fleet manager from a fleet file and loads its pipelines.
+// Pipeline versions are kept in store; deltas are signed with key when it
+// is not empty.
+func New(path string, store *state.Store, key []byte) (*Manager, error) {
+	m := &Manager{path: path, store: store, key: key}
+	if _, err := store.Load(versionsKey, &m.versions); err != nil {
+		return nil, fmt.Errorf("failed to load fleet pipeline versions: %w", err)
+	}
+	if m.versions == nil {
+		m.versions = make(map[string]*Version)
+	}
+	if err := m.Reload(context.Background()); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Reload reads the fleet file and the fleet pipelines again and bumps the
+// version of every pipeline that changed; nothing changes when either
+// fails to load. Agents pick up their new revision with their next beacon.
+func (m *Manager) Reload(ctx context.Context) error {
+	config, err := Load(m.path)
+	if err != nil {
+		return err
+	}
+	assignments, _ := config.parse()
+	reg := registry.NewService(config.Pipelines)
+	if err := reg.LoadAll(ctx); err != nil {
+		return fmt.Errorf("failed to load fleet pipelines: %w", err)
+	}
+	digests, err := pipelineDigests(reg)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	versions, changed := nextVersions(m.versions, digests)
+	if changed {
+		if err := m.store.Save(versionsKey, versions); err != nil {
+			return fmt.Errorf("failed to save fleet pipeline versions: %w", err)
+		}
+	}
+	m.registry, m.assignments, m.versions = reg, assignments, versions
+	return nil
+}
+
+// Pipelines returns every fleet pipeline, ordered by ID
+func (m *Manager) Pipelines() []*registry.Pipeline {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.registry.Select(nil)
+}
+
+// assigned reports whether an assignment gives the agent with these labels
+// a pipeline
+func assigned(assignments []assignment, labels map[string]string, p *registry.Pipeline) bool {
+	for _, a := range assignments {
+		if a.agents.Matches(labels) && a.pipelines.Matches(p.Labels) {
+			return true
+		}
+	}
+	return false
+}
+
+// Assigned returns the IDs of the pipelines assigned to an agent with the
+// labels, ordered
+func (m *Manager) Assigned(labels map[string]string) []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ids := []string{}
+	for _, p := range m.registry.Select(nil) {
+		if assigned(m.assignments, labels, p) {
+			ids = append(ids, p.ID)
+		}
+	}
+	return ids
+}
+
+// Export writes the bundle of the pipelines assigned to an agent with the
+// labels to w; the digest of the manifest is the revision of the bundle
+func (m *Manager) Export(w io.Writer, agentID string, labels map[string]string) (*registry.BundleManifest, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.registry.ExportMatching(w, "agent "+agentID, func(p *registry.Pipeline) bool {
+		return assigned(m.assignments, labels, p)
+	})
+}
+
+// Revision returns the revision of the pipelines assigned to an agent with
+// the labels
+func (m *Manager) Revision(labels map[string]string) (string, error) {
+	manifest, err := m.Export(io.Discard, "", labels)
+	if err != nil {
+		return "", err
+	}
+	return manifest.Digest, nil
+}
+
+// Annotate sets the revision assigned to each agent and the number of
+// pipelines it has yet to update or remove
+func (m *Manager) Annotate(agents ...*engine.AgentStatus) error {
+	revisions := make(map[string]string)
+	for _, agent := range agents {
+		key := fmt.Sprint(m.Assigned(agent.Labels))
+		revision, ok := revisions[key]
+		if !ok {
+			var err error
+			if revision, err = m.Revision(agent.Labels); err != nil {
+				return fmt.Errorf("failed to compute the fleet revision of agent %s: %w", agent.AgentID, err)
+			}
+			revisions[key] = revision
+		}
+		agent.Assigned = revision
+		updated, removed := m.changes(agent.Labels, agent.Versions)
+		agent.Pending = len(updated) + len(removed)
+	}
+	return nil
+}
+
+// Status aggregates the beacons of a fleet
+type Status struct {
+	Agents int `json:"agents"`
+	Online int `json:"online"`
+	// Outdated counts the agents that have not applied the revision
+	// assigned to them yet
+	Outdated   int              `json:"outdated"`
+	Spooled    int              `json:"spooled"`
+	SpoolBytes int64            `json:"spool_bytes"`
+	Pipelines  []PipelineStatus `json:"pipelines"`
+}
+
+// PipelineStatus aggregates one pipeline across the agents running it
+type PipelineStatus struct {
+	ID string `json:"id"`
+	// Version is the current version of the pipeline
+	Version uint64 `json:"version"`
+	// Assigned counts the agents the fleet assigns the pipeline to, and
+	// Acknowledged those of them that applied its current version
+	Assigned     int `json:"assigned"`
+	Acknowledged int `json:"acknowledged"`
+	// Agents counts the agents reporting the pipeline
+	Agents int `json:"agents"`
+	// Statuses counts the agents by the status of their last run
+	Statuses map[string]int `json:"statuses"`
+	// Offline counts the agents whose last upload missed the target
+	Offline int `json:"offline"`
+	Spooled int `json:"spooled"`
+	// Records sums the records the last run of each agent wrote
+	Records int `json:"records"`
+}
+
+// Status aggregates annotated agent beacons by pipeline. Offline agents
+// count with their last beacon.
+func (m *Manager) Status(agents []*engine.AgentStatus) *Status {
+	status := &Status{Agents: len(agents), Pipelines: []PipelineStatus{}}
+	pipelines := make(map[string]*PipelineStatus)
+	pipeline := func(id string) *PipelineStatus {
+		ps, ok := pipelines[id]
+		if !ok {
+			ps = &PipelineStatus{ID: id, Statuses: make(map[string]int)}
+			pipelines[id] = ps
+		}
+		return ps
+	}
+	versions := m.Versions()
+	for id, version := range versions {
+		pipeline(id).Version = version
+	}
+
+	for _, agent := range agents {
+		if agent.Online {
+			status.Online++
+		}
+		if agent.Assigned != "" && agent.Revision != agent.Assigned {
+			status.Outdated++
+		}
+		for _, id := range m.Assigned(agent.Labels) {
+			ps := pipeline(id)
+			ps.Assigned++
+			if agent.Versions[id] == versions[id] {
+				ps.Acknowledged++
+			}
+		}
+		for _, bp := range agent.Pipelines {
+			ps := pipeline(bp.ID)
+			ps.Agents++
+			if bp.Status != "" {
+				ps.Statuses[bp.Status]++
+			}
+			if bp.Offline {
+				ps.Offline++
+			}
+			ps.Spooled += bp.Spooled
+			ps.Records += bp.Records
+			status.Spooled += bp.Spooled
+			status.SpoolBytes += bp.SpoolBytes
+		}
+	}
+
+	for _, ps := range pipelines {
+		status.Pipelines = append(status.Pipelines, *ps)
+	}
+	sort.Slice(status.Pipelines, func(i, j int) bool { return status.Pipelines[i].ID < status.Pipelines[j].ID })
+	return status
+}
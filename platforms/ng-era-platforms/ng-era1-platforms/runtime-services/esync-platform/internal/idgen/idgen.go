@@ -0,0 +1,177 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: id-generation
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Target ID Generators
+ */
+
+// Package idgen creates the target IDs of records whose pipelines map
+// source keys to target keys of their own
+package idgen
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Generator creates unique record IDs
+//
+// Frozen: public as engine.IDGenerator
+type Generator interface {
+	Next() (string, error)
+}
+
+// Factory creates a generator; node is the key_mapping node ID, which
+// generators that need no node ignore
+type Factory func(node int) (Generator, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = map[string]Factory{
+		"uuidv7":    newUUIDv7,
+		"snowflake": newSnowflake,
+	}
+)
+
+// Register makes an ID generator available to key mappings
+func Register(name string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	factories[name] = factory
+}
+
+// Names returns all registered generator names
+func Names() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New creates the named generator
+func New(name string, node int) (Generator, error) {
+	factoriesMu.RLock()
+	factory, exists := factories[name]
+	factoriesMu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("unknown ID generator %q", name)
+	}
+	return factory(node)
+}
+
+// uuidv7 creates RFC 9562 version 7 UUIDs: a millisecond timestamp
+// followed by random bits, so IDs sort roughly by creation time
+type uuidv7 struct{}
+
+func newUUIDv7(int) (Generator, error) {
+	return uuidv7{}, nil
+}
+
+// Next implements Generator
+func (uuidv7) Next() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[6:]); err != nil {
+		return "", fmt.Errorf("failed to read random bits: %w", err)
+	}
+	ms := uint64(time.Now().UnixMilli())
+	for i := 0; i < 6; i++ {
+		b[i] = byte(ms >> (40 - 8*i))
+	}
+	b[6] = 0x70 | b[6]&0x0f
+	b[8] = 0x80 | b[8]&0x3f
+
+	h := hex.EncodeToString(b[:])
+	return h[:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:], nil
+}
+
+// Snowflake ID layout: 41 bits of milliseconds since snowflakeEpoch, 10
+// bits of node ID and 12 bits of sequence within the millisecond
+const (
+	snowflakeNodeBits = 10
+	snowflakeSeqBits  = 12
+	maxSnowflakeNode  = 1<<snowflakeNodeBits - 1
+	maxSnowflakeSeq   = 1<<snowflakeSeqBits - 1
+)
+
+// snowflakeEpoch is the zero time of snowflake timestamps
+var snowflakeEpoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// snowflakes holds one generator per node, so pipelines sharing a node
+// never hand out the same sequence number
+var (
+	snowflakesMu sync.Mutex
+	snowflakes   = map[int]*snowflake{}
+)
+
+// snowflake creates the IDs of one node
+type snowflake struct {
+	mu   sync.Mutex
+	node int64
+	last int64
+	seq  int64
+}
+
+func newSnowflake(node int) (Generator, error) {
+	if node < 0 || node > maxSnowflakeNode {
+		return nil, fmt.Errorf("snowflake node %d outside 0-%d", node, maxSnowflakeNode)
+	}
+
+	snowflakesMu.Lock()
+	defer snowflakesMu.Unlock()
+
+	g, exists := snowflakes[node]
+	if !exists {
+		g = &snowflake{node: int64(node)}
+		snowflakes[node] = g
+	}
+	return g, nil
+}
+
+// Next implements Generator. It waits for the next millisecond when the
+// sequence of the current one is used up and never goes back in time when
+// the clock does.
+func (g *snowflake) Next() (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Since(snowflakeEpoch).Milliseconds()
+	if now < g.last {
+		now = g.last
+	}
+	if now == g.last {
+		g.seq = (g.seq + 1) & maxSnowflakeSeq
+		if g.seq == 0 {
+			for now <= g.last {
+				time.Sleep(time.Millisecond)
+				now = time.Since(snowflakeEpoch).Milliseconds()
+			}
+		}
+	} else {
+		g.seq = 0
+	}
+	g.last = now
+
+	id := now<<(snowflakeNodeBits+snowflakeSeqBits) | g.node<<snowflakeSeqBits | g.seq
+	return strconv.FormatInt(id, 10), nil
+}
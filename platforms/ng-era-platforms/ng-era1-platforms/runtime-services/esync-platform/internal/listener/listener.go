@@ -0,0 +1,129 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: listener-binding
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Listener Address Binding
+ */
+
+// Package listener binds the daemon's HTTP listeners. A listener address is
+// a comma-separated list of bind addresses, each one of:
+//
+//	:8080                   dual-stack on every interface
+//	10.0.0.5:8080           one IPv4 address
+//	[fd00::5]:8080          one IPv6 address
+//	tcp4://0.0.0.0:8080     IPv4 only
+//	tcp6://[::]:8080        IPv6 only
+//	unix:///run/esync.sock  unix socket
+package listener
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Listen binds every address of a listener address, closing those already
+// bound when one fails
+func Listen(addr string) ([]net.Listener, error) {
+	var listeners []net.Listener
+	for _, bind := range strings.Split(addr, ",") {
+		bind = strings.TrimSpace(bind)
+		if bind == "" {
+			continue
+		}
+		l, err := listen(bind)
+		if err != nil {
+			closeAll(listeners)
+			return nil, fmt.Errorf("failed to listen on %s: %w", bind, err)
+		}
+		listeners = append(listeners, l)
+	}
+	if len(listeners) == 0 {
+		return nil, fmt.Errorf("no bind address in %q", addr)
+	}
+	return listeners, nil
+}
+
+// listen binds a single address
+func listen(bind string) (net.Listener, error) {
+	network, address := "tcp", bind
+	if i := strings.Index(bind, "://"); i >= 0 {
+		network, address = bind[:i], bind[i+3:]
+	}
+
+	switch network {
+	case "tcp", "tcp4", "tcp6":
+		return net.Listen(network, address)
+	case "unix":
+		if address == "" {
+			return nil, fmt.Errorf("unix socket path is empty")
+		}
+		if err := removeStaleSocket(address); err != nil {
+			return nil, err
+		}
+		return net.Listen("unix", address)
+	default:
+		return nil, fmt.Errorf("unsupported network %q", network)
+	}
+}
+
+// removeStaleSocket removes a socket file left behind by a previous process,
+// refusing to remove anything else
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%s exists and is not a socket", path)
+	}
+	return os.Remove(path)
+}
+
+// Serve serves handler on bound listeners until one stops
+func Serve(listeners []net.Listener, handler http.Handler) error {
+	server := &http.Server{Handler: handler}
+	errs := make(chan error, len(listeners))
+	for _, l := range listeners {
+		go func(l net.Listener) {
+			errs <- server.Serve(l)
+		}(l)
+	}
+	err := <-errs
+	server.Close()
+	return err
+}
+
+// Addrs formats the bound addresses of listeners for logging
+func Addrs(listeners []net.Listener) string {
+	addrs := make([]string, len(listeners))
+	for i, l := range listeners {
+		addrs[i] = l.Addr().Network() + "://" + l.Addr().String()
+	}
+	return strings.Join(addrs, ", ")
+}
+
+// closeAll closes listeners bound before a failure
+func closeAll(listeners []net.Listener) {
+	for _, l := range listeners {
+		if err := l.Close(); err != nil {
+			log.Printf("[Listener] Failed to close %s: %v", l.Addr(), err)
+		}
+	}
+}
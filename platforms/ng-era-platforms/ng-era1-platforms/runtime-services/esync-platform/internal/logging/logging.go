@@ -0,0 +1,259 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: log-levels
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Log Levels
+ */
+
+// Package logging holds the log level of the daemon and the overrides of
+// single scopes: a module such as engine or registry, a connector type or
+// a pipeline. Debugf logs debug lines of a scope when its level allows.
+// Lines logged through the standard logger are leveled by Install: they
+// are info lines, warnings when they report a failure, scoped by their
+// [Module] prefix and the pipeline they name.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Level is the severity of a log line
+type Level int
+
+// Levels
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// MaxOverrideTTL bounds the expiry of an override
+const MaxOverrideTTL = 24 * time.Hour
+
+// levelNames maps levels to their names
+var levelNames = []string{"debug", "info", "warn", "error"}
+
+// String returns the name of the level
+func (l Level) String() string {
+	if l < LevelDebug || l > LevelError {
+		return fmt.Sprintf("level(%d)", int(l))
+	}
+	return levelNames[l]
+}
+
+// ParseLevel parses the name of a level
+func ParseLevel(name string) (Level, error) {
+	for i, n := range levelNames {
+		if strings.EqualFold(name, n) {
+			return Level(i), nil
+		}
+	}
+	return LevelInfo, fmt.Errorf("unknown log level %q, expected debug, info, warn or error", name)
+}
+
+// Scope is what a log line is about; empty fields do not apply
+type Scope struct {
+	Module    string
+	Connector string
+	Pipeline  string
+}
+
+// keys returns the override keys of the scope, most specific first
+func (s Scope) keys() []string {
+	keys := make([]string, 0, 3)
+	if s.Pipeline != "" {
+		keys = append(keys, "pipeline:"+s.Pipeline)
+	}
+	if s.Connector != "" {
+		keys = append(keys, "connector:"+s.Connector)
+	}
+	if s.Module != "" {
+		keys = append(keys, strings.ToLower(s.Module))
+	}
+	return keys
+}
+
+// Override is the level of one scope: a module name, connector:<type> or
+// pipeline:<id>. Overrides without ExpiresAt last until removed.
+type Override struct {
+	Scope     string     `json:"scope"`
+	Level     string     `json:"level"`
+	SetAt     time.Time  `json:"set_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	level     Level
+}
+
+// expired reports whether the override expired at now
+func (o *Override) expired(now time.Time) bool {
+	return o.ExpiresAt != nil && !now.Before(*o.ExpiresAt)
+}
+
+// Levels is the log level of the daemon and its overrides
+type Levels struct {
+	Level     string     `json:"level"`
+	Overrides []Override `json:"overrides"`
+}
+
+var (
+	mu        sync.RWMutex
+	level     = LevelInfo
+	overrides = make(map[string]*Override)
+	// direct logs the lines Debugf decided on past the Install filter
+	direct atomic.Pointer[log.Logger]
+)
+
+// validScope matches the scopes overrides can be set for
+var validScope = regexp.MustCompile(`^([a-z][a-z0-9_]*|connector:[A-Za-z0-9_.-]+|pipeline:[A-Za-z0-9_.-]+)$`)
+
+// SetLevel sets the level of scopes without an override
+func SetLevel(l Level) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	level = l
+}
+
+// Get returns the level and the active overrides sorted by scope
+func Get() Levels {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	now := time.Now()
+	out := Levels{Level: level.String(), Overrides: []Override{}}
+	for _, o := range overrides {
+		if !o.expired(now) {
+			out.Overrides = append(out.Overrides, *o)
+		}
+	}
+	sort.Slice(out.Overrides, func(i, j int) bool { return out.Overrides[i].Scope < out.Overrides[j].Scope })
+	return out
+}
+
+// SetOverride sets the level of a scope, for ttl when positive. Setting an
+// override again replaces it.
+func SetOverride(scope string, l Level, ttl time.Duration) (*Override, error) {
+	if !validScope.MatchString(scope) {
+		return nil, fmt.Errorf("invalid scope %q, expected a module name, connector:<type> or pipeline:<id>", scope)
+	}
+	if ttl < 0 || ttl > MaxOverrideTTL {
+		return nil, fmt.Errorf("expiry must be between 0 and %s", MaxOverrideTTL)
+	}
+
+	now := time.Now().UTC()
+	o := &Override{Scope: scope, Level: l.String(), SetAt: now, level: l}
+	if ttl > 0 {
+		expires := now.Add(ttl)
+		o.ExpiresAt = &expires
+	}
+
+	mu.Lock()
+	for key, prev := range overrides {
+		if prev.expired(now) {
+			delete(overrides, key)
+		}
+	}
+	overrides[scope] = o
+	mu.Unlock()
+
+	out := *o
+	return &out, nil
+}
+
+// RemoveOverride removes the override of a scope, returning nil when none
+// was active
+func RemoveOverride(scope string) *Override {
+	mu.Lock()
+	defer mu.Unlock()
+
+	o := overrides[scope]
+	delete(overrides, scope)
+	if o == nil || o.expired(time.Now()) {
+		return nil
+	}
+	out := *o
+	return &out
+}
+
+// Enabled reports whether lines of level l about s are logged: the most
+// specific active override decides, the daemon level otherwise
+func Enabled(l Level, s Scope) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	now := time.Now()
+	for _, key := range s.keys() {
+		if o := overrides[key]; o != nil && !o.expired(now) {
+			return l >= o.level
+		}
+	}
+	return l >= level
+}
+
+// Debugf logs a debug line about s when its level allows
+func Debugf(s Scope, format string, args ...interface{}) {
+	if !Enabled(LevelDebug, s) {
+		return
+	}
+	if l := direct.Load(); l != nil {
+		l.Output(2, fmt.Sprintf(format, args...))
+		return
+	}
+	log.Output(2, fmt.Sprintf(format, args...))
+}
+
+// Install routes the standard logger through a filter writing to out the
+// lines the levels allow
+func Install(out io.Writer) {
+	direct.Store(log.New(out, log.Prefix(), log.Flags()))
+	log.SetOutput(&filter{out: out})
+}
+
+// filter drops the lines of the standard logger the levels do not allow
+type filter struct {
+	out io.Writer
+}
+
+var (
+	modulePrefix = regexp.MustCompile(`\[([A-Za-z]+)\] `)
+	pipelineName = regexp.MustCompile(`[Pp]ipeline ([A-Za-z0-9_.-]+)`)
+	failure      = regexp.MustCompile(`\b[Ff]ailed\b`)
+)
+
+// Write implements io.Writer; the standard logger writes one line per call
+func (f *filter) Write(p []byte) (int, error) {
+	line := string(p)
+	var s Scope
+	if m := modulePrefix.FindStringSubmatchIndex(line); m != nil {
+		s.Module = line[m[2]:m[3]]
+		line = line[m[1]:]
+	}
+	if m := pipelineName.FindStringSubmatch(line); m != nil {
+		s.Pipeline = m[1]
+	}
+	l := LevelInfo
+	if failure.MatchString(line) {
+		l = LevelWarn
+	}
+	if !Enabled(l, s) {
+		return len(p), nil
+	}
+	return f.out.Write(p)
+}
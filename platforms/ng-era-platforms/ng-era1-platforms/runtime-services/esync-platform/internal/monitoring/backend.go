@@ -0,0 +1,132 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: metrics-backends
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Metrics Backends
+ */
+
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// Metrics backends
+const (
+	// BackendPrometheus only serves the metrics for scraping
+	BackendPrometheus = "prometheus"
+	// BackendStatsD also sends them to a StatsD daemon, folding labels
+	// into the metric name
+	BackendStatsD = "statsd"
+	// BackendDogStatsD also sends them to a Datadog agent with labels as
+	// tags
+	BackendDogStatsD = "dogstatsd"
+)
+
+// DefaultFlushInterval is the interval of backend flushes when none is set
+const DefaultFlushInterval = 10 * time.Second
+
+// Backend receives the metrics of the daemon at every flush. Every metric
+// is recorded in the Prometheus registry, which stays the source of truth;
+// backends translate the gathered families to their own model.
+type Backend interface {
+	// Emit sends the current metric families
+	Emit(families []*dto.MetricFamily) error
+	// Close releases the backend
+	Close() error
+}
+
+// BackendConfig selects the metrics backend
+type BackendConfig struct {
+	// Kind is prometheus (default), statsd or dogstatsd
+	Kind string
+	// Addr is the host:port of the StatsD daemon or Datadog agent,
+	// 127.0.0.1:8125 by default
+	Addr string
+	// Prefix is prepended to every metric name
+	Prefix string
+	// Tags are added to every DogStatsD metric
+	Tags map[string]string
+	// Interval is the time between flushes
+	Interval time.Duration
+}
+
+// Validate checks the backend kind and its options
+func (c BackendConfig) Validate() error {
+	switch c.Kind {
+	case "", BackendPrometheus:
+		return nil
+	case BackendStatsD:
+		if len(c.Tags) > 0 {
+			return fmt.Errorf("metric tags need the %s backend", BackendDogStatsD)
+		}
+		return nil
+	case BackendDogStatsD:
+		return nil
+	default:
+		return fmt.Errorf("unknown metrics backend %q: use %s, %s or %s", c.Kind, BackendPrometheus, BackendStatsD, BackendDogStatsD)
+	}
+}
+
+// NewBackend creates the configured backend, or returns nil when metrics
+// are only served for scraping
+func NewBackend(config BackendConfig) (Backend, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	switch config.Kind {
+	case BackendStatsD, BackendDogStatsD:
+		return newStatsD(config)
+	default:
+		return nil, nil
+	}
+}
+
+// Flush emits the metrics to the backend every interval until ctx is done,
+// then once more and closes the backend
+func (m *Monitor) Flush(ctx context.Context, backend Backend, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultFlushInterval
+	}
+	defer backend.Close()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if err := emit(backend); err != nil {
+				log.Printf("[Monitoring] Failed to flush metrics: %v", err)
+			}
+			return
+		case <-ticker.C:
+			if err := emit(backend); err != nil && m.errors.allow("", "metrics flush") {
+				log.Printf("[Monitoring] Failed to flush metrics: %v", err)
+			}
+		}
+	}
+}
+
+// emit gathers the registered metrics and hands them to the backend
+func emit(backend Backend) error {
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		return fmt.Errorf("failed to gather metrics: %w", err)
+	}
+	return backend.Emit(families)
+}
@@ -0,0 +1,124 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: connector-metrics
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Connector-Reported Metrics
+ */
+
+package monitoring
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// connectorPrefix namespaces metrics reported by connectors
+const connectorPrefix = "esync_connector_"
+
+var (
+	connectorMu      sync.Mutex
+	connectorMetrics = make(map[string]prometheus.Collector)
+)
+
+// AddConnectorCounter increments a connector-reported counter, registering
+// it as esync_connector_<type>_<name>_total with a connection label on
+// first use
+func (m *Monitor) AddConnectorCounter(connectorType, connection, name string, delta float64) error {
+	if delta < 0 {
+		return fmt.Errorf("connector counter %s cannot decrease", name)
+	}
+	metric := connectorMetric(connectorType, strings.TrimSuffix(sanitizeLabel(name), "_total")+"_total")
+	counter, err := registerConnectorMetric(metric, func() prometheus.Collector {
+		return prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: metric,
+			Help: fmt.Sprintf("Counter %s reported by %s connectors", name, connectorType),
+		}, []string{"connection"})
+	})
+	if err != nil {
+		return err
+	}
+	vec, ok := counter.(*prometheus.CounterVec)
+	if !ok {
+		return fmt.Errorf("connector metric %s is not a counter", metric)
+	}
+	vec.WithLabelValues(connection).Add(delta)
+	return nil
+}
+
+// SetConnectorGauge sets a connector-reported gauge, registering it as
+// esync_connector_<type>_<name> with a connection label on first use
+func (m *Monitor) SetConnectorGauge(connectorType, connection, name string, value float64) error {
+	metric := connectorMetric(connectorType, sanitizeLabel(name))
+	gauge, err := registerConnectorMetric(metric, func() prometheus.Collector {
+		return prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: metric,
+			Help: fmt.Sprintf("Gauge %s reported by %s connectors", name, connectorType),
+		}, []string{"connection"})
+	})
+	if err != nil {
+		return err
+	}
+	vec, ok := gauge.(*prometheus.GaugeVec)
+	if !ok {
+		return fmt.Errorf("connector metric %s is not a gauge", metric)
+	}
+	vec.WithLabelValues(connection).Set(value)
+	return nil
+}
+
+// ObserveConnector records a value in a connector-reported histogram,
+// registering it as esync_connector_<type>_<name> with a connection label
+// on first use
+func (m *Monitor) ObserveConnector(connectorType, connection, name string, value float64) error {
+	metric := connectorMetric(connectorType, sanitizeLabel(name))
+	histogram, err := registerConnectorMetric(metric, func() prometheus.Collector {
+		return prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    metric,
+			Help:    fmt.Sprintf("Histogram %s reported by %s connectors", name, connectorType),
+			Buckets: prometheus.DefBuckets,
+		}, []string{"connection"})
+	})
+	if err != nil {
+		return err
+	}
+	vec, ok := histogram.(*prometheus.HistogramVec)
+	if !ok {
+		return fmt.Errorf("connector metric %s is not a histogram", metric)
+	}
+	vec.WithLabelValues(connection).Observe(value)
+	return nil
+}
+
+// connectorMetric builds the namespaced name of a connector metric
+func connectorMetric(connectorType, name string) string {
+	return connectorPrefix + strings.ToLower(sanitizeLabel(connectorType)) + "_" + name
+}
+
+// registerConnectorMetric returns the collector registered under metric,
+// registering the one built by create on first use
+func registerConnectorMetric(metric string, create func() prometheus.Collector) (prometheus.Collector, error) {
+	connectorMu.Lock()
+	defer connectorMu.Unlock()
+
+	if collector, exists := connectorMetrics[metric]; exists {
+		return collector, nil
+	}
+	collector := create()
+	if err := prometheus.Register(collector); err != nil {
+		return nil, fmt.Errorf("failed to register connector metric %s: %w", metric, err)
+	}
+	connectorMetrics[metric] = collector
+	return collector, nil
+}
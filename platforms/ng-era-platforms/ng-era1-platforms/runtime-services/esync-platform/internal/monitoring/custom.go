@@ -0,0 +1,91 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: custom-pipeline-metrics
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Custom Pipeline Metrics
+ */
+
+package monitoring
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// customPrefix namespaces metrics emitted by transforms
+const customPrefix = "esync_custom_"
+
+var (
+	customMu       sync.Mutex
+	customCounters = make(map[string]*prometheus.CounterVec)
+	customGauges   = make(map[string]*prometheus.GaugeVec)
+)
+
+// AddCustomCounter increments a transform-defined counter, registering it as
+// esync_custom_<name>_total with a pipeline_id label on first use
+func (m *Monitor) AddCustomCounter(pipelineID, name string, delta float64) error {
+	metric := customPrefix + strings.TrimSuffix(sanitizeLabel(name), "_total") + "_total"
+
+	customMu.Lock()
+	defer customMu.Unlock()
+
+	counter, exists := customCounters[metric]
+	if !exists {
+		if _, isGauge := customGauges[metric]; isGauge {
+			return fmt.Errorf("custom metric %s is already a gauge", name)
+		}
+		counter = prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: metric,
+			Help: fmt.Sprintf("Custom counter %s emitted by pipeline transforms", name),
+		}, []string{"pipeline_id"})
+		if err := prometheus.Register(counter); err != nil {
+			return fmt.Errorf("failed to register custom metric %s: %w", name, err)
+		}
+		customCounters[metric] = counter
+	}
+
+	if delta < 0 {
+		return fmt.Errorf("custom counter %s cannot decrease", name)
+	}
+	counter.WithLabelValues(pipelineID).Add(delta)
+	return nil
+}
+
+// SetCustomGauge sets a transform-defined gauge, registering it as
+// esync_custom_<name> with a pipeline_id label on first use
+func (m *Monitor) SetCustomGauge(pipelineID, name string, value float64) error {
+	metric := customPrefix + sanitizeLabel(name)
+
+	customMu.Lock()
+	defer customMu.Unlock()
+
+	gauge, exists := customGauges[metric]
+	if !exists {
+		if _, isCounter := customCounters[metric]; isCounter {
+			return fmt.Errorf("custom metric %s is already a counter", name)
+		}
+		gauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: metric,
+			Help: fmt.Sprintf("Custom gauge %s emitted by pipeline transforms", name),
+		}, []string{"pipeline_id"})
+		if err := prometheus.Register(gauge); err != nil {
+			return fmt.Errorf("failed to register custom metric %s: %w", name, err)
+		}
+		customGauges[metric] = gauge
+	}
+
+	gauge.WithLabelValues(pipelineID).Set(value)
+	return nil
+}
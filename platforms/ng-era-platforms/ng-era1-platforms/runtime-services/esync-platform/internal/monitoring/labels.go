@@ -0,0 +1,117 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: monitoring-pipeline-labels
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Pipeline Label Info Metric
+ */
+
+package monitoring
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// labelCollector exports esync_pipeline_labels, an info metric carrying
+// selected pipeline labels. Join it on pipeline_id to aggregate any metric by
+// label, e.g.:
+//
+//	sum by (label_team) (rate(esync_pipeline_executions_total[5m])
+//	  * on (pipeline_id) group_left (label_team) esync_pipeline_labels)
+type labelCollector struct {
+	mu     sync.RWMutex
+	keys   []string
+	desc   *prometheus.Desc
+	labels map[string]map[string]string
+}
+
+// ExposePipelineLabels registers the label info metric for the given label
+// keys. It must be called at most once.
+func (m *Monitor) ExposePipelineLabels(keys []string) error {
+	names := []string{"pipeline_id"}
+	for _, key := range keys {
+		names = append(names, "label_"+sanitizeLabel(key))
+	}
+
+	c := &labelCollector{
+		keys:   keys,
+		desc:   prometheus.NewDesc("esync_pipeline_labels", "Pipeline labels for metric aggregation", names, nil),
+		labels: make(map[string]map[string]string),
+	}
+	if err := prometheus.Register(c); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.labels = c
+	m.mu.Unlock()
+	return nil
+}
+
+// SetPipelineLabels updates the exported labels of a pipeline
+func (m *Monitor) SetPipelineLabels(pipelineID string, labels map[string]string) {
+	m.mu.RLock()
+	c := m.labels
+	m.mu.RUnlock()
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	c.labels[pipelineID] = labels
+	c.mu.Unlock()
+}
+
+// DeletePipelineLabels stops exporting the labels of a removed pipeline
+func (m *Monitor) DeletePipelineLabels(pipelineID string) {
+	m.mu.RLock()
+	c := m.labels
+	m.mu.RUnlock()
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	delete(c.labels, pipelineID)
+	c.mu.Unlock()
+}
+
+// Describe implements prometheus.Collector
+func (c *labelCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect implements prometheus.Collector
+func (c *labelCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for id, labels := range c.labels {
+		values := []string{id}
+		for _, key := range c.keys {
+			values = append(values, labels[key])
+		}
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, 1, values...)
+	}
+}
+
+// sanitizeLabel converts a label key into a valid Prometheus label name
+func sanitizeLabel(key string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, key)
+}
@@ -18,9 +18,13 @@ package monitoring
 
 import (
 	"log"
+	"net"
 	"net/http"
 	"sync"
+	"time"
 
+	"github.com/machine-native-ops/esync-platform/internal/listener"
+	"github.com/machine-native-ops/esync-platform/internal/problem"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
@@ -33,33 +37,383 @@ var (
 		},
 		[]string{"pipeline_id", "status"},
 	)
+
+	runTriggers = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "esync_run_triggers_total",
+			Help: "Total number of run triggers by lock outcome",
+		},
+		[]string{"pipeline_id", "outcome"},
+	)
+
+	runProgress = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "esync_run_progress_records",
+			Help: "Records applied by the in-flight run",
+		},
+		[]string{"pipeline_id"},
+	)
+
+	runEstimatedTotal = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "esync_run_estimated_records",
+			Help: "Estimated total records of the in-flight run",
+		},
+		[]string{"pipeline_id"},
+	)
+
+	runETA = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "esync_run_eta_seconds",
+			Help: "Estimated seconds until the in-flight run completes",
+		},
+		[]string{"pipeline_id"},
+	)
+
+	tableRecords = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "esync_table_records_total",
+			Help: "Records passed to the target per table of multi-table pipelines",
+		},
+		[]string{"pipeline_id", "table"},
+	)
+
+	tableErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "esync_table_errors_total",
+			Help: "Failed applies per table of multi-table pipelines",
+		},
+		[]string{"pipeline_id", "table"},
+	)
+
+	verificationScore = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "esync_verification_score",
+			Help: "Share of sampled records read back from the target unchanged in the last run",
+		},
+		[]string{"pipeline_id"},
+	)
+
+	retentionPruned = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "esync_retention_pruned_total",
+			Help: "Total number of items removed by retention pruning by kind",
+		},
+		[]string{"kind"},
+	)
+
+	retentionReclaimed = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "esync_retention_reclaimed_bytes_total",
+			Help: "Total bytes reclaimed by retention pruning by kind",
+		},
+		[]string{"kind"},
+	)
+
+	canaryLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "esync_canary_latency_seconds",
+			Help:    "Time from sending a canary record until it reached the target",
+			Buckets: prometheus.ExponentialBuckets(0.1, 2, 14),
+		},
+		[]string{"pipeline_id"},
+	)
+
+	canaryLastArrival = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "esync_canary_last_arrival_timestamp_seconds",
+			Help: "Unix time the last canary record reached the target",
+		},
+		[]string{"pipeline_id"},
+	)
+
+	canaryLost = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "esync_canary_lost_total",
+			Help: "Total number of canary records that failed to reach the target in time",
+		},
+		[]string{"pipeline_id"},
+	)
+
+	sloStaleness = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "esync_slo_staleness_seconds",
+			Help: "Seconds since the last successful run of pipelines with a freshness objective",
+		},
+		[]string{"pipeline_id"},
+	)
+
+	sloBoosted = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "esync_slo_boosted",
+			Help: "Whether a pipeline is boosted because its freshness objective is burning",
+		},
+		[]string{"pipeline_id"},
+	)
+
+	sourceLag = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "esync_source_lag_seconds",
+			Help: "Seconds since the source time of the newest change or heartbeat seen from a source",
+		},
+		[]string{"pipeline_id"},
+	)
+
+	sourceLastHeartbeat = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "esync_source_last_heartbeat_timestamp_seconds",
+			Help: "Unix source time of the last heartbeat seen from a CDC source",
+		},
+		[]string{"pipeline_id"},
+	)
+
+	sourceState = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "esync_source_state",
+			Help: "Whether a source is in a state (active, idle or stuck) as of the last sync pass",
+		},
+		[]string{"pipeline_id", "state"},
+	)
+
+	orphanedResources = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "esync_orphaned_resources",
+			Help: "Connector resources no registered pipeline owns, as of the last orphan check",
+		},
+		[]string{"connector", "kind"},
+	)
+
+	watchdogActions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "esync_watchdog_actions_total",
+			Help: "Total number of runs the watchdog acted on by action",
+		},
+		[]string{"pipeline_id", "action"},
+	)
+
+	namespaceRuns = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "esync_namespace_active_runs",
+			Help: "Runs of a namespace holding one of its concurrent run slots",
+		},
+		[]string{"namespace"},
+	)
+
+	namespaceWorkers = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "esync_namespace_busy_workers",
+			Help: "Apply workers of a namespace holding one of its worker slots",
+		},
+		[]string{"namespace"},
+	)
+
+	namespaceWaits = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "esync_namespace_quota_waits_total",
+			Help: "Total number of runs and apply workers that waited for a namespace quota by resource",
+		},
+		[]string{"namespace", "resource"},
+	)
+
+	fleetAgents = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "esync_fleet_agents",
+			Help: "Agents of the managed fleet by state (online, offline or outdated) as of their last beacons",
+		},
+		[]string{"state"},
+	)
+
+	fleetPipelineAgents = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "esync_fleet_pipeline_agents",
+			Help: "Agents of the managed fleet running a pipeline by the status of their last run",
+		},
+		[]string{"pipeline_id", "status"},
+	)
+
+	fleetSpooled = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "esync_fleet_spooled_records",
+			Help: "Records spooled by the agents of the managed fleet waiting for upload",
+		},
+		[]string{"pipeline_id"},
+	)
+
+	sourceClockSkew = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "esync_source_clock_skew_seconds",
+			Help: "How far the clock of a pipeline's source is ahead of the daemon clock, negative when behind",
+		},
+		[]string{"pipeline_id"},
+	)
+
+	sourceClockSkewExceeded = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "esync_source_clock_skew_exceeded",
+			Help: "Whether the clock skew of a pipeline's source is over its threshold (1) or not (0)",
+		},
+		[]string{"pipeline_id"},
+	)
+
+	bandwidthBytes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "esync_bandwidth_bytes_total",
+			Help: "Bytes of records read from sources and written to targets",
+		},
+		[]string{"pipeline_id", "direction"},
+	)
+
+	bandwidthWait = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "esync_bandwidth_wait_seconds_total",
+			Help: "Time writes waited for the bandwidth caps",
+		},
+		[]string{"pipeline_id"},
+	)
+
+	memoryPressure = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "esync_memory_pressure",
+			Help: "Whether the daemon is at a memory pressure level (normal, elevated or critical)",
+		},
+		[]string{"level"},
+	)
+
+	memoryUsed = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "esync_memory_used_bytes",
+			Help: "Memory the Go runtime holds from the OS, as counted against the memory limit",
+		},
+	)
+
+	memoryLimit = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "esync_memory_limit_bytes",
+			Help: "Memory limit of the daemon from GOMEMLIMIT or its cgroup, 0 when unbounded",
+		},
+	)
+
+	scalingLoad = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "esync_autoscaling_load",
+			Help: "Sum over the pipelines that are not paused of their lag over their lag target plus their waiting runs, for external scalers",
+		},
+	)
+
+	scalingPipelineLoad = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "esync_autoscaling_pipeline_load",
+			Help: "Average load of the pipelines that are not paused; 1 means a pipeline is at its lag target or has a run waiting",
+		},
+	)
+
+	scalingQueueDepth = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "esync_autoscaling_queue_depth",
+			Help: "Runs waiting for a run of the same pipeline to finish",
+		},
+	)
+
+	scalingScaleInSafe = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "esync_autoscaling_scale_in_safe",
+			Help: "Whether no run is in progress or waiting and no handoff holds the pipelines (1) or not (0)",
+		},
+	)
+
+	sharedReads = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "esync_shared_source_reads_total",
+			Help: "Total number of source reads of pipelines sharing their source, by whether they were served from a read of the group (hit) or read the source (miss)",
+		},
+		[]string{"pipeline_id", "result"},
+	)
+
+	conflictResolutions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "esync_conflict_resolutions_total",
+			Help: "Total number of conflict resolutions by outcome",
+		},
+		[]string{"pipeline_id", "outcome"},
+	)
 )
 
 func init() {
 	prometheus.MustRegister(pipelineExecutions)
+	prometheus.MustRegister(runTriggers)
+	prometheus.MustRegister(runProgress)
+	prometheus.MustRegister(runEstimatedTotal)
+	prometheus.MustRegister(runETA)
+	prometheus.MustRegister(conflictResolutions)
+	prometheus.MustRegister(verificationScore)
+	prometheus.MustRegister(tableRecords)
+	prometheus.MustRegister(tableErrors)
+	prometheus.MustRegister(retentionPruned)
+	prometheus.MustRegister(retentionReclaimed)
+	prometheus.MustRegister(canaryLatency)
+	prometheus.MustRegister(canaryLastArrival)
+	prometheus.MustRegister(canaryLost)
+	prometheus.MustRegister(sloStaleness)
+	prometheus.MustRegister(sloBoosted)
+	prometheus.MustRegister(watchdogActions)
+	prometheus.MustRegister(sourceLag)
+	prometheus.MustRegister(sourceLastHeartbeat)
+	prometheus.MustRegister(sourceState)
+	prometheus.MustRegister(orphanedResources)
+	prometheus.MustRegister(namespaceRuns)
+	prometheus.MustRegister(namespaceWorkers)
+	prometheus.MustRegister(namespaceWaits)
+	prometheus.MustRegister(fleetAgents)
+	prometheus.MustRegister(fleetPipelineAgents)
+	prometheus.MustRegister(fleetSpooled)
+	prometheus.MustRegister(bandwidthBytes)
+	prometheus.MustRegister(bandwidthWait)
+	prometheus.MustRegister(sourceClockSkew)
+	prometheus.MustRegister(sourceClockSkewExceeded)
+	prometheus.MustRegister(memoryPressure)
+	prometheus.MustRegister(memoryUsed)
+	prometheus.MustRegister(memoryLimit)
+	prometheus.MustRegister(sharedReads)
+	prometheus.MustRegister(scalingLoad)
+	prometheus.MustRegister(scalingPipelineLoad)
+	prometheus.MustRegister(scalingQueueDepth)
+	prometheus.MustRegister(scalingScaleInSafe)
 }
 
 // Monitor handles monitoring and metrics
 type Monitor struct {
 	mu     sync.RWMutex
 	health bool
+	labels *labelCollector
+	errors *logLimiter
 }
 
 // NewMonitor creates a new monitor
 func NewMonitor() *Monitor {
 	return &Monitor{
 		health: true,
+		errors: newLogLimiter(DefaultErrorLogBurst, DefaultErrorLogWindow),
 	}
 }
 
-// Start starts the monitoring server
+// Start starts the monitoring server on a listener address
 func (m *Monitor) Start(addr string) error {
+	listeners, err := listener.Listen(addr)
+	if err != nil {
+		return err
+	}
+	return m.Serve(listeners)
+}
+
+// Serve serves metrics and health checks on bound listeners
+func (m *Monitor) Serve(listeners []net.Listener) error {
 	mux := http.NewServeMux()
 	mux.Handle("/metrics", promhttp.Handler())
 	mux.HandleFunc("/health", m.healthHandler)
-	
-	log.Printf("[Monitoring] Starting monitoring server on %s", addr)
-	return http.ListenAndServe(addr, mux)
+	mux.HandleFunc("/", problem.NotFound)
+
+	log.Printf("[Monitoring] Starting monitoring server on %s", listener.Addrs(listeners))
+	return listener.Serve(listeners, problem.Middleware(mux))
 }
 
 // healthHandler handles health check requests
@@ -71,24 +425,239 @@ func (m *Monitor) healthHandler(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("OK"))
 	} else {
-		w.WriteHeader(http.StatusServiceUnavailable)
-		w.Write([]byte("Unhealthy"))
+		problem.Write(w, http.StatusServiceUnavailable, "Unhealthy")
 	}
 }
 
+// SetErrorLogLimit sets how many error lines each pipeline and error
+// type may log per window unless the pipeline sets its own limit; zero
+// values keep the defaults
+func (m *Monitor) SetErrorLogLimit(burst int, window time.Duration) {
+	m.errors.setDefault(burst, window)
+}
+
+// SetPipelineErrorLogLimit sets the error log limit of a pipeline; zero
+// values fall back to the daemon's
+func (m *Monitor) SetPipelineErrorLogLimit(pipelineID string, burst int, window time.Duration) {
+	m.errors.setPipeline(pipelineID, burst, window)
+}
+
 // RecordSuccess records a successful pipeline execution
 func (m *Monitor) RecordSuccess(pipelineID string, recordCount int) {
 	pipelineExecutions.WithLabelValues(pipelineID, "success").Inc()
 }
 
-// RecordError records a pipeline error
+// RecordError records a pipeline error. Log lines are rate-limited per
+// pipeline and error type; the metric counts every error.
 func (m *Monitor) RecordError(pipelineID, errorType string, err error) {
 	pipelineExecutions.WithLabelValues(pipelineID, "error").Inc()
-	log.Printf("[Monitoring] Error in pipeline %s [%s]: %v", pipelineID, errorType, err)
+	if m.errors.allow(pipelineID, pipelineID+" ["+errorType+"]") {
+		log.Printf("[Monitoring] Error in pipeline %s [%s]: %v", pipelineID, errorType, err)
+	}
 }
 
 // RecordSourceError records a source connector error
 func (m *Monitor) RecordSourceError(pipelineID string, err error) {
 	pipelineExecutions.WithLabelValues(pipelineID, "source_error").Inc()
-	log.Printf("[Monitoring] Source error in pipeline %s: %v", pipelineID, err)
+	if m.errors.allow(pipelineID, pipelineID+" [source]") {
+		log.Printf("[Monitoring] Source error in pipeline %s: %v", pipelineID, err)
+	}
+}
+
+// RecordConflict records the outcome of a conflict resolution
+func (m *Monitor) RecordConflict(pipelineID, outcome string) {
+	conflictResolutions.WithLabelValues(pipelineID, outcome).Inc()
+}
+
+// RecordVerification publishes the verification score of a run
+func (m *Monitor) RecordVerification(pipelineID string, score float64) {
+	verificationScore.WithLabelValues(pipelineID).Set(score)
+}
+
+// RecordTableRecords counts records applied for one table
+func (m *Monitor) RecordTableRecords(pipelineID, table string, n int) {
+	tableRecords.WithLabelValues(pipelineID, table).Add(float64(n))
+}
+
+// RecordTableError counts a failed apply of one table
+func (m *Monitor) RecordTableError(pipelineID, table string) {
+	tableErrors.WithLabelValues(pipelineID, table).Inc()
+}
+
+// RecordPruned counts items and bytes removed by a retention pass
+func (m *Monitor) RecordPruned(kind string, items int, bytes int64) {
+	retentionPruned.WithLabelValues(kind).Add(float64(items))
+	retentionReclaimed.WithLabelValues(kind).Add(float64(bytes))
+}
+
+// RecordCanary records the end-to-end latency of a canary that reached the
+// target
+func (m *Monitor) RecordCanary(pipelineID string, latency time.Duration) {
+	canaryLatency.WithLabelValues(pipelineID).Observe(latency.Seconds())
+	canaryLastArrival.WithLabelValues(pipelineID).SetToCurrentTime()
+}
+
+// RecordCanaryLost counts a canary that failed to reach the target
+func (m *Monitor) RecordCanaryLost(pipelineID string) {
+	canaryLost.WithLabelValues(pipelineID).Inc()
+}
+
+// RecordStaleness publishes the staleness of a pipeline with a freshness
+// objective
+func (m *Monitor) RecordStaleness(pipelineID string, staleness time.Duration) {
+	sloStaleness.WithLabelValues(pipelineID).Set(staleness.Seconds())
+}
+
+// RecordBoosted publishes whether a pipeline is boosted
+func (m *Monitor) RecordBoosted(pipelineID string, boosted bool) {
+	v := 0.0
+	if boosted {
+		v = 1
+	}
+	sloBoosted.WithLabelValues(pipelineID).Set(v)
+}
+
+// sourceStates are the states published by esync_source_state
+var sourceStates = []string{"active", "idle", "stuck"}
+
+// RecordSourceActivity publishes the state and lag of a pipeline's source
+// and the source time of a heartbeat seen in the last pass, if any
+func (m *Monitor) RecordSourceActivity(pipelineID, state string, lag time.Duration, heartbeat time.Time) {
+	sourceLag.WithLabelValues(pipelineID).Set(lag.Seconds())
+	if !heartbeat.IsZero() {
+		sourceLastHeartbeat.WithLabelValues(pipelineID).Set(float64(heartbeat.UnixNano()) / 1e9)
+	}
+	for _, s := range sourceStates {
+		v := 0.0
+		if s == state {
+			v = 1
+		}
+		sourceState.WithLabelValues(pipelineID, s).Set(v)
+	}
+}
+
+// RecordOrphans publishes the orphaned resources found by a check, counted
+// by connector type and resource kind; kinds missing from counts are reset
+func (m *Monitor) RecordOrphans(counts map[string]map[string]int) {
+	orphanedResources.Reset()
+	for connector, kinds := range counts {
+		for kind, n := range kinds {
+			orphanedResources.WithLabelValues(connector, kind).Set(float64(n))
+		}
+	}
+}
+
+// RecordFleet publishes the agents of a managed fleet by state, the agents
+// of each pipeline by run status and the records they spooled; series
+// missing from the counts are reset
+func (m *Monitor) RecordFleet(agents map[string]int, statuses map[string]map[string]int, spooled map[string]int) {
+	fleetAgents.Reset()
+	for state, n := range agents {
+		fleetAgents.WithLabelValues(state).Set(float64(n))
+	}
+	fleetPipelineAgents.Reset()
+	for pipelineID, counts := range statuses {
+		for status, n := range counts {
+			fleetPipelineAgents.WithLabelValues(pipelineID, status).Set(float64(n))
+		}
+	}
+	fleetSpooled.Reset()
+	for pipelineID, n := range spooled {
+		fleetSpooled.WithLabelValues(pipelineID).Set(float64(n))
+	}
+}
+
+// RecordWatchdog counts a run the watchdog acted on
+func (m *Monitor) RecordWatchdog(pipelineID, action string) {
+	watchdogActions.WithLabelValues(pipelineID, action).Inc()
+}
+
+// RecordNamespaceUsage publishes the run and worker slots a namespace holds
+func (m *Monitor) RecordNamespaceUsage(namespace string, runs, workers int) {
+	namespaceRuns.WithLabelValues(namespace).Set(float64(runs))
+	namespaceWorkers.WithLabelValues(namespace).Set(float64(workers))
+}
+
+// RecordNamespaceWait records a run or worker that waited for a namespace
+// quota
+func (m *Monitor) RecordNamespaceWait(namespace, resource string) {
+	namespaceWaits.WithLabelValues(namespace, resource).Inc()
+}
+
+// RecordTrigger records how the run lock handled a trigger
+func (m *Monitor) RecordTrigger(pipelineID, outcome string) {
+	runTriggers.WithLabelValues(pipelineID, outcome).Inc()
+}
+
+// RecordProgress publishes the progress of an in-flight run
+func (m *Monitor) RecordProgress(pipelineID string, done, total int64, eta time.Duration) {
+	runProgress.WithLabelValues(pipelineID).Set(float64(done))
+	runEstimatedTotal.WithLabelValues(pipelineID).Set(float64(total))
+	runETA.WithLabelValues(pipelineID).Set(eta.Seconds())
+}
+
+// ClearProgress removes progress series once a run finishes
+func (m *Monitor) ClearProgress(pipelineID string) {
+	runProgress.DeleteLabelValues(pipelineID)
+	runEstimatedTotal.DeleteLabelValues(pipelineID)
+	runETA.DeleteLabelValues(pipelineID)
+}
+
+// RecordBandwidth counts the bytes of records a pipeline read or wrote and
+// how long its writes waited for the bandwidth caps
+func (m *Monitor) RecordBandwidth(pipelineID, direction string, bytes int64, waited time.Duration) {
+	bandwidthBytes.WithLabelValues(pipelineID, direction).Add(float64(bytes))
+	if waited > 0 {
+		bandwidthWait.WithLabelValues(pipelineID).Add(waited.Seconds())
+	}
+}
+
+// RecordClockSkew publishes how far the clock of a pipeline's source is off
+// and whether that is over its threshold
+func (m *Monitor) RecordClockSkew(pipelineID string, skew time.Duration, exceeded bool) {
+	sourceClockSkew.WithLabelValues(pipelineID).Set(skew.Seconds())
+	value := 0.0
+	if exceeded {
+		value = 1
+	}
+	sourceClockSkewExceeded.WithLabelValues(pipelineID).Set(value)
+}
+
+// memoryLevels are the levels published by esync_memory_pressure
+var memoryLevels = []string{"normal", "elevated", "critical"}
+
+// RecordMemory publishes the memory pressure level, the memory in use and
+// the limit it is measured against
+func (m *Monitor) RecordMemory(level string, used, limit uint64) {
+	for _, l := range memoryLevels {
+		v := 0.0
+		if l == level {
+			v = 1
+		}
+		memoryPressure.WithLabelValues(l).Set(v)
+	}
+	memoryUsed.Set(float64(used))
+	memoryLimit.Set(float64(limit))
+}
+
+// RecordSharedRead counts a source read of a pipeline sharing its source,
+// served from a read of its group when hit
+func (m *Monitor) RecordSharedRead(pipelineID string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	sharedReads.WithLabelValues(pipelineID, result).Inc()
+}
+
+// RecordAutoscaling publishes the autoscaling signal of the daemon
+func (m *Monitor) RecordAutoscaling(load, pipelineLoad float64, queueDepth int, scaleInSafe bool) {
+	scalingLoad.Set(load)
+	scalingPipelineLoad.Set(pipelineLoad)
+	scalingQueueDepth.Set(float64(queueDepth))
+	safe := 0.0
+	if scaleInSafe {
+		safe = 1
+	}
+	scalingScaleInSafe.Set(safe)
 }
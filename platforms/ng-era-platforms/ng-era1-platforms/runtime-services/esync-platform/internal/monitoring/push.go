@@ -0,0 +1,346 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: metrics-push
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Metrics Push
+ */
+
+package monitoring
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/machine-native-ops/esync-platform/internal/egress"
+)
+
+// Metrics push formats
+const (
+	// PushGateway replaces the metrics of the job and labels on a
+	// Prometheus Pushgateway
+	PushGateway = "pushgateway"
+	// PushOTLP posts the metrics to an OTLP/HTTP collector as JSON, e.g.
+	// to http://collector:4318/v1/metrics
+	PushOTLP = "otlp"
+)
+
+// DefaultPushInterval is the interval of metrics pushes when none is set
+const DefaultPushInterval = 30 * time.Second
+
+// pushTimeout bounds a single push
+const pushTimeout = 10 * time.Second
+
+// PushConfig configures pushing metrics to environments that cannot scrape
+// the metrics endpoint
+type PushConfig struct {
+	// URL is the Pushgateway or OTLP metrics endpoint; pushing is off when
+	// empty
+	URL string
+	// Format is pushgateway (default) or otlp
+	Format string
+	// Interval is the time between pushes
+	Interval time.Duration
+	// Job is the Pushgateway job and OTLP service.name; it defaults to
+	// esync
+	Job string
+	// Labels are Pushgateway grouping labels and OTLP resource attributes
+	Labels map[string]string
+}
+
+// Validate checks the push format
+func (c PushConfig) Validate() error {
+	switch c.Format {
+	case "", PushGateway, PushOTLP:
+		return nil
+	default:
+		return fmt.Errorf("unknown metrics push format %q: use %s or %s", c.Format, PushGateway, PushOTLP)
+	}
+}
+
+// Push pushes every metric at the configured interval until ctx is done,
+// then once more so the last values of a stopping daemon are kept
+func (m *Monitor) Push(ctx context.Context, config PushConfig) {
+	interval := config.Interval
+	if interval <= 0 {
+		interval = DefaultPushInterval
+	}
+	if config.Job == "" {
+		config.Job = "esync"
+	}
+	started := time.Now()
+
+	log.Printf("[Monitoring] Pushing metrics to %s every %s (%s)", config.URL, interval, formatOf(config))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if err := pushOnce(context.Background(), config, started); err != nil {
+				log.Printf("[Monitoring] Failed to push metrics: %v", err)
+			}
+			return
+		case <-ticker.C:
+			if err := pushOnce(ctx, config, started); err != nil && m.errors.allow("", "metrics push") {
+				log.Printf("[Monitoring] Failed to push metrics: %v", err)
+			}
+		}
+	}
+}
+
+// formatOf returns the push format, defaulting to pushgateway
+func formatOf(config PushConfig) string {
+	if config.Format == "" {
+		return PushGateway
+	}
+	return config.Format
+}
+
+// pushOnce gathers the registered metrics and pushes them
+func pushOnce(ctx context.Context, config PushConfig, started time.Time) error {
+	client := egress.Client(pushTimeout)
+	if formatOf(config) == PushGateway {
+		pusher := push.New(config.URL, config.Job).Gatherer(prometheus.DefaultGatherer).Client(client)
+		keys := make([]string, 0, len(config.Labels))
+		for k := range config.Labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			pusher = pusher.Grouping(k, config.Labels[k])
+		}
+		return pusher.PushContext(ctx)
+	}
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		return fmt.Errorf("failed to gather metrics: %w", err)
+	}
+	body, err := json.Marshal(otlpRequest(families, config, started, time.Now()))
+	if err != nil {
+		return fmt.Errorf("failed to encode metrics: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, config.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("collector returned %s", resp.Status)
+	}
+	return nil
+}
+
+// OTLP/HTTP JSON encoding of metrics. 64-bit integers are strings as the
+// protobuf JSON mapping requires.
+type (
+	otlpExport struct {
+		ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+	}
+	otlpResourceMetrics struct {
+		Resource     otlpResource       `json:"resource"`
+		ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+	}
+	otlpResource struct {
+		Attributes []otlpAttribute `json:"attributes"`
+	}
+	otlpScopeMetrics struct {
+		Scope   otlpScope    `json:"scope"`
+		Metrics []otlpMetric `json:"metrics"`
+	}
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+	otlpAttribute struct {
+		Key   string    `json:"key"`
+		Value otlpValue `json:"value"`
+	}
+	otlpValue struct {
+		StringValue string `json:"stringValue"`
+	}
+	otlpMetric struct {
+		Name        string         `json:"name"`
+		Description string         `json:"description,omitempty"`
+		Sum         *otlpSum       `json:"sum,omitempty"`
+		Gauge       *otlpGauge     `json:"gauge,omitempty"`
+		Histogram   *otlpHistogram `json:"histogram,omitempty"`
+		Summary     *otlpSummary   `json:"summary,omitempty"`
+	}
+	otlpSum struct {
+		AggregationTemporality int             `json:"aggregationTemporality"`
+		IsMonotonic            bool            `json:"isMonotonic"`
+		DataPoints             []otlpDataPoint `json:"dataPoints"`
+	}
+	otlpGauge struct {
+		DataPoints []otlpDataPoint `json:"dataPoints"`
+	}
+	otlpHistogram struct {
+		AggregationTemporality int                      `json:"aggregationTemporality"`
+		DataPoints             []otlpHistogramDataPoint `json:"dataPoints"`
+	}
+	otlpSummary struct {
+		DataPoints []otlpSummaryDataPoint `json:"dataPoints"`
+	}
+	otlpDataPoint struct {
+		Attributes        []otlpAttribute `json:"attributes,omitempty"`
+		StartTimeUnixNano string          `json:"startTimeUnixNano,omitempty"`
+		TimeUnixNano      string          `json:"timeUnixNano"`
+		AsDouble          float64         `json:"asDouble"`
+	}
+	otlpHistogramDataPoint struct {
+		Attributes        []otlpAttribute `json:"attributes,omitempty"`
+		StartTimeUnixNano string          `json:"startTimeUnixNano"`
+		TimeUnixNano      string          `json:"timeUnixNano"`
+		Count             string          `json:"count"`
+		Sum               float64         `json:"sum"`
+		BucketCounts      []string        `json:"bucketCounts"`
+		ExplicitBounds    []float64       `json:"explicitBounds"`
+	}
+	otlpSummaryDataPoint struct {
+		Attributes        []otlpAttribute `json:"attributes,omitempty"`
+		StartTimeUnixNano string          `json:"startTimeUnixNano"`
+		TimeUnixNano      string          `json:"timeUnixNano"`
+		Count             string          `json:"count"`
+		Sum               float64         `json:"sum"`
+		QuantileValues    []otlpQuantile  `json:"quantileValues"`
+	}
+	otlpQuantile struct {
+		Quantile float64 `json:"quantile"`
+		Value    float64 `json:"value"`
+	}
+)
+
+// otlpCumulative is AGGREGATION_TEMPORALITY_CUMULATIVE
+const otlpCumulative = 2
+
+// otlpRequest converts gathered metric families to an OTLP export request
+func otlpRequest(families []*dto.MetricFamily, config PushConfig, started, now time.Time) otlpExport {
+	resource := []otlpAttribute{{Key: "service.name", Value: otlpValue{StringValue: config.Job}}}
+	resource = append(resource, otlpAttributes(config.Labels)...)
+	start := strconv.FormatInt(started.UnixNano(), 10)
+	at := strconv.FormatInt(now.UnixNano(), 10)
+
+	var metrics []otlpMetric
+	for _, f := range families {
+		metric := otlpMetric{Name: f.GetName(), Description: f.GetHelp()}
+		for _, sample := range f.GetMetric() {
+			attrs := otlpLabels(sample.GetLabel())
+			switch f.GetType() {
+			case dto.MetricType_COUNTER:
+				if metric.Sum == nil {
+					metric.Sum = &otlpSum{AggregationTemporality: otlpCumulative, IsMonotonic: true}
+				}
+				metric.Sum.DataPoints = append(metric.Sum.DataPoints, otlpDataPoint{
+					Attributes: attrs, StartTimeUnixNano: start, TimeUnixNano: at, AsDouble: sample.GetCounter().GetValue(),
+				})
+			case dto.MetricType_GAUGE, dto.MetricType_UNTYPED:
+				value := sample.GetGauge().GetValue()
+				if f.GetType() == dto.MetricType_UNTYPED {
+					value = sample.GetUntyped().GetValue()
+				}
+				if metric.Gauge == nil {
+					metric.Gauge = &otlpGauge{}
+				}
+				metric.Gauge.DataPoints = append(metric.Gauge.DataPoints, otlpDataPoint{
+					Attributes: attrs, TimeUnixNano: at, AsDouble: value,
+				})
+			case dto.MetricType_HISTOGRAM:
+				if metric.Histogram == nil {
+					metric.Histogram = &otlpHistogram{AggregationTemporality: otlpCumulative}
+				}
+				metric.Histogram.DataPoints = append(metric.Histogram.DataPoints, otlpHistogramPoint(sample.GetHistogram(), attrs, start, at))
+			case dto.MetricType_SUMMARY:
+				s := sample.GetSummary()
+				point := otlpSummaryDataPoint{
+					Attributes: attrs, StartTimeUnixNano: start, TimeUnixNano: at,
+					Count: strconv.FormatUint(s.GetSampleCount(), 10), Sum: s.GetSampleSum(),
+				}
+				for _, q := range s.GetQuantile() {
+					point.QuantileValues = append(point.QuantileValues, otlpQuantile{Quantile: q.GetQuantile(), Value: q.GetValue()})
+				}
+				if metric.Summary == nil {
+					metric.Summary = &otlpSummary{}
+				}
+				metric.Summary.DataPoints = append(metric.Summary.DataPoints, point)
+			}
+		}
+		if metric.Sum != nil || metric.Gauge != nil || metric.Histogram != nil || metric.Summary != nil {
+			metrics = append(metrics, metric)
+		}
+	}
+
+	return otlpExport{ResourceMetrics: []otlpResourceMetrics{{
+		Resource:     otlpResource{Attributes: resource},
+		ScopeMetrics: []otlpScopeMetrics{{Scope: otlpScope{Name: "esync-platform"}, Metrics: metrics}},
+	}}}
+}
+
+// otlpHistogramPoint converts cumulative Prometheus buckets to the
+// per-bucket counts of OTLP, whose last bucket holds values above every
+// bound
+func otlpHistogramPoint(h *dto.Histogram, attrs []otlpAttribute, start, at string) otlpHistogramDataPoint {
+	point := otlpHistogramDataPoint{
+		Attributes: attrs, StartTimeUnixNano: start, TimeUnixNano: at,
+		Count: strconv.FormatUint(h.GetSampleCount(), 10), Sum: h.GetSampleSum(),
+		BucketCounts: []string{}, ExplicitBounds: []float64{},
+	}
+	var previous uint64
+	for _, b := range h.GetBucket() {
+		if math.IsInf(b.GetUpperBound(), 1) {
+			continue
+		}
+		point.ExplicitBounds = append(point.ExplicitBounds, b.GetUpperBound())
+		point.BucketCounts = append(point.BucketCounts, strconv.FormatUint(b.GetCumulativeCount()-previous, 10))
+		previous = b.GetCumulativeCount()
+	}
+	point.BucketCounts = append(point.BucketCounts, strconv.FormatUint(h.GetSampleCount()-previous, 10))
+	return point
+}
+
+// otlpLabels converts metric labels to attributes
+func otlpLabels(labels []*dto.LabelPair) []otlpAttribute {
+	attrs := make([]otlpAttribute, 0, len(labels))
+	for _, l := range labels {
+		attrs = append(attrs, otlpAttribute{Key: l.GetName(), Value: otlpValue{StringValue: l.GetValue()}})
+	}
+	return attrs
+}
+
+// otlpAttributes converts configured labels to attributes in key order
+func otlpAttributes(labels map[string]string) []otlpAttribute {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	attrs := make([]otlpAttribute, 0, len(keys))
+	for _, k := range keys {
+		attrs = append(attrs, otlpAttribute{Key: k, Value: otlpValue{StringValue: labels[k]}})
+	}
+	return attrs
+}
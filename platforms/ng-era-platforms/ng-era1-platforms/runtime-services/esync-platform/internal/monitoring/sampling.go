@@ -0,0 +1,144 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: error-log-sampling
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Error Log Rate Limiting
+ */
+
+package monitoring
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// Each pipeline and error type may log DefaultErrorLogBurst lines per
+// DefaultErrorLogWindow unless the daemon or the pipeline's error_log
+// sets other limits; further errors are only counted and summarized when
+// the window closes. Metrics count every occurrence regardless.
+const (
+	DefaultErrorLogBurst  = 10
+	DefaultErrorLogWindow = time.Minute
+)
+
+// logLimit is the number of lines a key may log per window
+type logLimit struct {
+	burst  int
+	window time.Duration
+}
+
+// logLimiter rate-limits log lines per key
+type logLimiter struct {
+	mu  sync.Mutex
+	def logLimit
+	// pipelines holds the limits pipelines set for their own keys
+	pipelines map[string]logLimit
+	windows   map[string]*logWindow
+}
+
+// logWindow tracks one key within the current window
+type logWindow struct {
+	start      time.Time
+	window     time.Duration
+	logged     int
+	suppressed int
+}
+
+func newLogLimiter(burst int, window time.Duration) *logLimiter {
+	return &logLimiter{
+		def:       logLimit{burst: burst, window: window},
+		pipelines: make(map[string]logLimit),
+		windows:   make(map[string]*logWindow),
+	}
+}
+
+// setDefault replaces the limit of keys without one of their own; zero
+// values keep the current ones
+func (l *logLimiter) setDefault(burst int, window time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if burst > 0 {
+		l.def.burst = burst
+	}
+	if window > 0 {
+		l.def.window = window
+	}
+}
+
+// setPipeline sets the limit of a pipeline's keys; zero values fall back
+// to the default
+func (l *logLimiter) setPipeline(pipelineID string, burst int, window time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if burst <= 0 && window <= 0 {
+		delete(l.pipelines, pipelineID)
+		return
+	}
+	l.pipelines[pipelineID] = logLimit{burst: burst, window: window}
+}
+
+// limit returns the limit of a pipeline's keys, or the default for keys
+// of no pipeline
+func (l *logLimiter) limit(pipelineID string) logLimit {
+	limit := l.def
+	if own, exists := l.pipelines[pipelineID]; exists {
+		if own.burst > 0 {
+			limit.burst = own.burst
+		}
+		if own.window > 0 {
+			limit.window = own.window
+		}
+	}
+	return limit
+}
+
+// allow reports whether a line for key of a pipeline, or of none when
+// pipelineID is empty, may be logged. The first suppressed line of a
+// window schedules a summary for when the window closes.
+func (l *logLimiter) allow(pipelineID, key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	limit := l.limit(pipelineID)
+	w, exists := l.windows[key]
+	if !exists || now.Sub(w.start) >= w.window {
+		w = &logWindow{start: now, window: limit.window}
+		l.windows[key] = w
+	}
+
+	if w.logged < limit.burst {
+		w.logged++
+		return true
+	}
+
+	w.suppressed++
+	if w.suppressed == 1 {
+		time.AfterFunc(w.start.Add(w.window).Sub(now), func() { l.summarize(key, w) })
+	}
+	return false
+}
+
+// summarize logs how many lines of a closed window were suppressed
+func (l *logLimiter) summarize(key string, w *logWindow) {
+	l.mu.Lock()
+	suppressed := w.suppressed
+	if l.windows[key] == w {
+		delete(l.windows, key)
+	}
+	l.mu.Unlock()
+
+	log.Printf("[Monitoring] %d similar errors suppressed for %s in the last %s", suppressed, key, w.window)
+}
@@ -0,0 +1,205 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: statsd-backend
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * StatsD Metrics Backend
+ */
+
+package monitoring
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/machine-native-ops/esync-platform/internal/egress"
+)
+
+// defaultStatsDAddr is the address of the local StatsD daemon or agent
+const defaultStatsDAddr = "127.0.0.1:8125"
+
+// maxDatagram keeps packets below the common path MTU
+const maxDatagram = 1432
+
+// statsdBackend sends metrics over UDP. Counters are sent as counts of
+// their increase since the previous flush, gauges as gauges and histograms
+// and summaries as counts of their observations (.count) and their sum
+// (.sum).
+type statsdBackend struct {
+	conn   net.Conn
+	dog    bool
+	prefix string
+	tags   string
+	// last holds the counter values of the previous flush by series
+	last map[string]float64
+}
+
+func newStatsD(config BackendConfig) (*statsdBackend, error) {
+	addr := config.Addr
+	if addr == "" {
+		addr = defaultStatsDAddr
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid statsd address %q: %w", addr, err)
+	}
+	if !egress.Default().Allowed(host) {
+		return nil, fmt.Errorf("%s: %w", host, egress.ErrDenied)
+	}
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open statsd connection: %w", err)
+	}
+
+	b := &statsdBackend{
+		conn:   conn,
+		dog:    config.Kind == BackendDogStatsD,
+		prefix: config.Prefix,
+		last:   make(map[string]float64),
+	}
+	keys := make([]string, 0, len(config.Tags))
+	for k := range config.Tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		b.tags += "," + statsdEscape(k) + ":" + statsdEscape(config.Tags[k])
+	}
+	return b, nil
+}
+
+// Emit implements Backend
+func (b *statsdBackend) Emit(families []*dto.MetricFamily) error {
+	var packet bytes.Buffer
+	var sendErr error
+	send := func(line string) {
+		if packet.Len() > 0 && packet.Len()+1+len(line) > maxDatagram {
+			if _, err := b.conn.Write(packet.Bytes()); err != nil && sendErr == nil {
+				sendErr = err
+			}
+			packet.Reset()
+		}
+		if packet.Len() > 0 {
+			packet.WriteByte('\n')
+		}
+		packet.WriteString(line)
+	}
+
+	for _, f := range families {
+		for _, sample := range f.GetMetric() {
+			name, tags := b.series(f.GetName(), sample.GetLabel())
+			switch f.GetType() {
+			case dto.MetricType_COUNTER:
+				b.count(send, name, tags, sample.GetCounter().GetValue())
+			case dto.MetricType_GAUGE:
+				b.gauge(send, name, tags, sample.GetGauge().GetValue())
+			case dto.MetricType_UNTYPED:
+				b.gauge(send, name, tags, sample.GetUntyped().GetValue())
+			case dto.MetricType_HISTOGRAM:
+				h := sample.GetHistogram()
+				b.count(send, name+".count", tags, float64(h.GetSampleCount()))
+				b.count(send, name+".sum", tags, h.GetSampleSum())
+			case dto.MetricType_SUMMARY:
+				s := sample.GetSummary()
+				b.count(send, name+".count", tags, float64(s.GetSampleCount()))
+				b.count(send, name+".sum", tags, s.GetSampleSum())
+			}
+		}
+	}
+	if packet.Len() > 0 {
+		if _, err := b.conn.Write(packet.Bytes()); err != nil && sendErr == nil {
+			sendErr = err
+		}
+	}
+	if sendErr != nil {
+		return fmt.Errorf("failed to send statsd metrics: %w", sendErr)
+	}
+	return nil
+}
+
+// series returns the name and tag suffix of a sample. StatsD has no tags,
+// so label values become name segments in label order.
+func (b *statsdBackend) series(name string, labels []*dto.LabelPair) (string, string) {
+	name = b.prefix + name
+	if !b.dog {
+		for _, l := range labels {
+			if v := l.GetValue(); v != "" {
+				name += "." + strings.ReplaceAll(statsdEscape(v), ".", "_")
+			}
+		}
+		return name, ""
+	}
+
+	tags := b.tags
+	for _, l := range labels {
+		tags += "," + statsdEscape(l.GetName()) + ":" + statsdEscape(l.GetValue())
+	}
+	if tags == "" {
+		return name, ""
+	}
+	return name, "|#" + tags[1:]
+}
+
+// count sends the increase of a counter since the previous flush; a value
+// below the previous one means the series was reset
+func (b *statsdBackend) count(send func(string), name, tags string, value float64) {
+	key := name + tags
+	delta := value - b.last[key]
+	if delta < 0 {
+		delta = value
+	}
+	b.last[key] = value
+	if delta == 0 {
+		return
+	}
+	send(name + ":" + formatStatsD(delta) + "|c" + tags)
+}
+
+// gauge sends the value of a gauge, skipping values StatsD cannot carry.
+// StatsD reads a signed value as a change of the gauge, so a negative value
+// is set from zero.
+func (b *statsdBackend) gauge(send func(string), name, tags string, value float64) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return
+	}
+	if value < 0 && !b.dog {
+		send(name + ":0|g" + tags)
+	}
+	send(name + ":" + formatStatsD(value) + "|g" + tags)
+}
+
+// Close implements Backend
+func (b *statsdBackend) Close() error {
+	return b.conn.Close()
+}
+
+// formatStatsD formats a metric value
+func formatStatsD(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// statsdEscape replaces the characters of the StatsD line format
+func statsdEscape(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ':', '|', '@', '#', ',', '\n', ' ':
+			return '_'
+		}
+		return r
+	}, s)
+}
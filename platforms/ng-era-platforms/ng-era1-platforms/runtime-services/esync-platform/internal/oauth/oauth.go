@@ -0,0 +1,460 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: oauth-client
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * OAuth2 Token Client
+ */
+
+// Package oauth obtains access tokens for connectors of REST APIs with the
+// OAuth2 client credentials or refresh token grant. A TokenSource caches
+// the token and refreshes it in the background shortly before it expires,
+// so calls rarely wait for the token endpoint; Transport adds the token to
+// requests and retries once with a new token when one is rejected.
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/egress"
+)
+
+// DefaultRefreshBefore is how long before expiry tokens are refreshed when
+// a config sets no refresh_before
+const DefaultRefreshBefore = 60 * time.Second
+
+// tokenTimeout bounds one request to the token or discovery endpoint
+const tokenTimeout = 30 * time.Second
+
+// refreshRetry is how long a failed background refresh waits before the
+// next attempt while the current token is still valid
+const refreshRetry = 5 * time.Second
+
+// Client authentication styles at the token endpoint
+const (
+	// AuthHeader sends the client credentials as HTTP basic auth
+	AuthHeader = "header"
+	// AuthParams sends them as client_id and client_secret form fields
+	AuthParams = "params"
+)
+
+// ErrTokenRejected is returned when the token endpoint refuses the grant,
+// such as for revoked refresh tokens or wrong client credentials
+var ErrTokenRejected = errors.New("token request rejected")
+
+// Config describes an OAuth2 client
+//
+// Frozen: public as connectors.OAuthConfig
+type Config struct {
+	// TokenURL is the token endpoint; when empty it is discovered from the
+	// OpenID configuration of Issuer
+	TokenURL string
+	Issuer   string
+	ClientID string
+	// ClientSecret is usually resolved from a ${secret:NAME} reference
+	ClientSecret string
+	Scopes       []string
+	// Audience is sent by APIs that issue tokens per resource server
+	Audience string
+	// RefreshToken selects the refresh token grant instead of client
+	// credentials. Rotated refresh tokens are passed to OnRefreshToken so
+	// the connector can persist them.
+	RefreshToken   string
+	OnRefreshToken func(refreshToken string)
+	// AuthStyle is AuthHeader (default) or AuthParams
+	AuthStyle string
+	// RefreshBefore is how long before expiry the token is refreshed
+	// (default DefaultRefreshBefore)
+	RefreshBefore time.Duration
+}
+
+// FromConfig reads a config from the "oauth" block of a connector config
+func FromConfig(config map[string]interface{}) (*Config, error) {
+	str := func(key string) string {
+		s, _ := config[key].(string)
+		return s
+	}
+
+	c := &Config{
+		TokenURL:     str("token_url"),
+		Issuer:       str("issuer"),
+		ClientID:     str("client_id"),
+		ClientSecret: str("client_secret"),
+		Audience:     str("audience"),
+		RefreshToken: str("refresh_token"),
+		AuthStyle:    str("auth_style"),
+	}
+	switch scopes := config["scopes"].(type) {
+	case string:
+		c.Scopes = strings.Fields(strings.ReplaceAll(scopes, ",", " "))
+	case []interface{}:
+		for _, scope := range scopes {
+			c.Scopes = append(c.Scopes, fmt.Sprint(scope))
+		}
+	}
+	if v, ok := config["refresh_before"]; ok {
+		seconds, err := strconv.Atoi(fmt.Sprint(v))
+		if err != nil || seconds < 0 {
+			return nil, fmt.Errorf("invalid oauth refresh_before %v", v)
+		}
+		c.RefreshBefore = time.Duration(seconds) * time.Second
+	}
+	return c, c.Validate()
+}
+
+// Validate checks the config names an endpoint and a client
+func (c *Config) Validate() error {
+	if c.TokenURL == "" && c.Issuer == "" {
+		return fmt.Errorf("oauth requires token_url or issuer")
+	}
+	if c.ClientID == "" {
+		return fmt.Errorf("oauth requires client_id")
+	}
+	if c.RefreshToken == "" && c.ClientSecret == "" {
+		return fmt.Errorf("oauth client credentials require client_secret")
+	}
+	switch c.AuthStyle {
+	case "", AuthHeader, AuthParams:
+	default:
+		return fmt.Errorf("unknown oauth auth_style %q, expected %s or %s", c.AuthStyle, AuthHeader, AuthParams)
+	}
+	return nil
+}
+
+// Token is an access token
+//
+// Frozen: public as connectors.OAuthToken
+type Token struct {
+	AccessToken string
+	TokenType   string
+	// Expiry is zero for tokens that do not expire
+	Expiry time.Time
+}
+
+// expiresWithin reports whether the token expires in less than d
+func (t *Token) expiresWithin(d time.Duration) bool {
+	return !t.Expiry.IsZero() && time.Until(t.Expiry) < d
+}
+
+// TokenSource hands out a cached access token, refreshing it ahead of its
+// expiry. It is safe for concurrent use; concurrent callers share a single
+// request to the token endpoint.
+//
+// Frozen: public as connectors.TokenSource
+type TokenSource struct {
+	config Config
+	client *http.Client
+
+	mu           sync.Mutex
+	token        *Token
+	refreshToken string
+	endpoint     string
+	// refreshing is closed when the token request in flight ends; err is
+	// the outcome of the last request
+	refreshing chan struct{}
+	err        error
+	retryAt    time.Time
+}
+
+// NewTokenSource creates a token source; no token is requested until the
+// first call to Token
+func NewTokenSource(config *Config) (*TokenSource, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	s := &TokenSource{
+		config:       *config,
+		client:       egress.Client(tokenTimeout),
+		refreshToken: config.RefreshToken,
+		endpoint:     config.TokenURL,
+	}
+	if s.config.RefreshBefore <= 0 {
+		s.config.RefreshBefore = DefaultRefreshBefore
+	}
+	return s, nil
+}
+
+// Token returns a valid access token. A token close to its expiry is still
+// returned while a new one is requested in the background; callers only
+// wait when there is no token or it expired.
+func (s *TokenSource) Token(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	token := s.token
+	if token != nil && !token.expiresWithin(0) {
+		if token.expiresWithin(s.config.RefreshBefore) && time.Now().After(s.retryAt) {
+			s.refresh()
+		}
+		s.mu.Unlock()
+		return token, nil
+	}
+	done := s.refresh()
+	s.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-done:
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token == nil || s.token.expiresWithin(0) {
+		return nil, s.err
+	}
+	return s.token, nil
+}
+
+// Invalidate drops the cached token, such as after the API rejected it, so
+// the next call to Token requests a new one
+func (s *TokenSource) Invalidate(token *Token) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token == token {
+		s.token = nil
+	}
+}
+
+// refresh starts a token request unless one is in flight and returns the
+// channel closed when it ends. The caller holds s.mu.
+func (s *TokenSource) refresh() chan struct{} {
+	if s.refreshing != nil {
+		return s.refreshing
+	}
+	done := make(chan struct{})
+	s.refreshing = done
+
+	go func() {
+		defer close(done)
+
+		// The request outlives the caller that started it, so it is only
+		// bounded by the client timeout
+		token, err := s.fetch(context.Background())
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.refreshing, s.err = nil, err
+		if err != nil {
+			s.retryAt = time.Now().Add(refreshRetry)
+			log.Printf("[OAuth] Failed to refresh token of client %s: %v", s.config.ClientID, err)
+			return
+		}
+		s.token = token
+	}()
+	return done
+}
+
+// tokenResponse is the token endpoint response, successful or not
+type tokenResponse struct {
+	AccessToken      string      `json:"access_token"`
+	TokenType        string      `json:"token_type"`
+	ExpiresIn        json.Number `json:"expires_in"`
+	RefreshToken     string      `json:"refresh_token"`
+	Error            string      `json:"error"`
+	ErrorDescription string      `json:"error_description"`
+}
+
+// fetch requests a new token with the configured grant
+func (s *TokenSource) fetch(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	endpoint, refreshToken := s.endpoint, s.refreshToken
+	s.mu.Unlock()
+
+	if endpoint == "" {
+		discovered, err := s.discover(ctx)
+		if err != nil {
+			return nil, err
+		}
+		s.mu.Lock()
+		s.endpoint = discovered
+		s.mu.Unlock()
+		endpoint = discovered
+	}
+
+	form := url.Values{}
+	if refreshToken != "" {
+		form.Set("grant_type", "refresh_token")
+		form.Set("refresh_token", refreshToken)
+	} else {
+		form.Set("grant_type", "client_credentials")
+	}
+	if len(s.config.Scopes) > 0 {
+		form.Set("scope", strings.Join(s.config.Scopes, " "))
+	}
+	if s.config.Audience != "" {
+		form.Set("audience", s.config.Audience)
+	}
+	if s.config.AuthStyle == AuthParams {
+		form.Set("client_id", s.config.ClientID)
+		if s.config.ClientSecret != "" {
+			form.Set("client_secret", s.config.ClientSecret)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if s.config.AuthStyle != AuthParams {
+		req.SetBasicAuth(url.QueryEscape(s.config.ClientID), url.QueryEscape(s.config.ClientSecret))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body tokenResponse
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token response: %w", err)
+	}
+	if err := json.Unmarshal(data, &body); err != nil && resp.StatusCode < 300 {
+		return nil, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if resp.StatusCode >= 300 || body.Error != "" {
+		reason := body.Error
+		if body.ErrorDescription != "" {
+			reason += ": " + body.ErrorDescription
+		}
+		if reason == "" {
+			reason = strings.TrimSpace(string(data))
+		}
+		return nil, fmt.Errorf("%w (status %d): %s", ErrTokenRejected, resp.StatusCode, reason)
+	}
+	if body.AccessToken == "" {
+		return nil, fmt.Errorf("token response has no access_token")
+	}
+
+	token := &Token{AccessToken: body.AccessToken, TokenType: body.TokenType}
+	if token.TokenType == "" || strings.EqualFold(token.TokenType, "bearer") {
+		token.TokenType = "Bearer"
+	}
+	if seconds, err := body.ExpiresIn.Int64(); err == nil && seconds > 0 {
+		token.Expiry = time.Now().Add(time.Duration(seconds) * time.Second)
+	}
+	if body.RefreshToken != "" && body.RefreshToken != refreshToken {
+		s.mu.Lock()
+		s.refreshToken = body.RefreshToken
+		s.mu.Unlock()
+		if s.config.OnRefreshToken != nil {
+			s.config.OnRefreshToken(body.RefreshToken)
+		}
+	}
+	return token, nil
+}
+
+// discover reads the token endpoint from the OpenID configuration of the
+// issuer
+func (s *TokenSource) discover(ctx context.Context) (string, error) {
+	wellKnown := strings.TrimSuffix(s.config.Issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, wellKnown, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to discover token endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to discover token endpoint: %s returned %d", wellKnown, resp.StatusCode)
+	}
+	var config struct {
+		TokenEndpoint string `json:"token_endpoint"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&config); err != nil {
+		return "", fmt.Errorf("failed to decode OpenID configuration: %w", err)
+	}
+	if config.TokenEndpoint == "" {
+		return "", fmt.Errorf("OpenID configuration of %s has no token_endpoint", s.config.Issuer)
+	}
+	return config.TokenEndpoint, nil
+}
+
+// Client returns an HTTP client following the egress policy that
+// authenticates its requests with tokens of the source
+func (s *TokenSource) Client(timeout time.Duration) *http.Client {
+	client := egress.Client(timeout)
+	client.Transport = &Transport{Source: s, Base: client.Transport}
+	return client
+}
+
+// Transport adds the access token of Source to requests. A request the API
+// answers with 401 is retried once with a new token when its body can be
+// replayed.
+//
+// Frozen: public as connectors.OAuthRoundTripper
+type Transport struct {
+	Source *TokenSource
+	// Base sends the requests (default http.DefaultTransport)
+	Base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	token, err := t.Source.Token(req.Context())
+	if err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	resp, err := base.RoundTrip(authorize(req, token))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+
+	t.Source.Invalidate(token)
+	retry, err := t.Source.Token(req.Context())
+	if err != nil {
+		return resp, nil
+	}
+	next := authorize(req, retry)
+	if req.GetBody != nil {
+		if next.Body, err = req.GetBody(); err != nil {
+			return resp, nil
+		}
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return base.RoundTrip(next)
+}
+
+// authorize returns a copy of req carrying token
+func authorize(req *http.Request, token *Token) *http.Request {
+	out := req.Clone(req.Context())
+	out.Header.Set("Authorization", token.TokenType+" "+token.AccessToken)
+	return out
+}
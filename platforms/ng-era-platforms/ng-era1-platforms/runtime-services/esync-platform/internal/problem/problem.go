@@ -0,0 +1,286 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: problem-details
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Problem Details
+ */
+
+// Package problem writes the error responses of the daemon's HTTP
+// endpoints as RFC 7807 problem details. Every request is given a
+// correlation ID, taken from its X-Request-ID or X-Correlation-ID header or
+// the trace ID of its W3C traceparent when the client sent one, that is
+// echoed in the response, carried by the problem details and logged with
+// failed and mutating requests, so a request can be followed into the logs,
+// the runs it started and their audit entries.
+package problem
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// ContentType is the media type of problem details
+const ContentType = "application/problem+json"
+
+// HeaderCorrelationID and HeaderRequestID carry the correlation ID of a
+// request and its response; HeaderTraceparent carries the W3C trace
+// context of a request
+const (
+	HeaderCorrelationID = "X-Correlation-ID"
+	HeaderRequestID     = "X-Request-ID"
+	HeaderTraceparent   = "traceparent"
+)
+
+// maxCorrelationID bounds the correlation IDs accepted from clients
+const maxCorrelationID = 128
+
+// Error codes of problem details. They are stable: clients may branch on
+// them, unlike on the detail message.
+const (
+	CodeBadRequest       = "bad_request"
+	CodeUnauthorized     = "unauthorized"
+	CodeForbidden        = "forbidden"
+	CodeNotFound         = "not_found"
+	CodeMethodNotAllowed = "method_not_allowed"
+	CodeConflict         = "conflict"
+	CodePayloadTooLarge  = "payload_too_large"
+	CodeUnprocessable    = "unprocessable"
+	CodeTooManyRequests  = "too_many_requests"
+	CodeInternal         = "internal"
+	CodeBadGateway       = "bad_gateway"
+	CodeUnavailable      = "unavailable"
+	CodeGatewayTimeout   = "gateway_timeout"
+	// CodeClientError and CodeServerError are the codes of other statuses
+	CodeClientError = "client_error"
+	CodeServerError = "server_error"
+)
+
+// typePrefix prefixes the error code in the type URI of problem details
+const typePrefix = "urn:esync:problem:"
+
+// codes maps response statuses to their error codes
+var codes = map[int]string{
+	http.StatusBadRequest:            CodeBadRequest,
+	http.StatusUnauthorized:          CodeUnauthorized,
+	http.StatusForbidden:             CodeForbidden,
+	http.StatusNotFound:              CodeNotFound,
+	http.StatusMethodNotAllowed:      CodeMethodNotAllowed,
+	http.StatusConflict:              CodeConflict,
+	http.StatusRequestEntityTooLarge: CodePayloadTooLarge,
+	http.StatusUnprocessableEntity:   CodeUnprocessable,
+	http.StatusTooManyRequests:       CodeTooManyRequests,
+	http.StatusInternalServerError:   CodeInternal,
+	http.StatusBadGateway:            CodeBadGateway,
+	http.StatusServiceUnavailable:    CodeUnavailable,
+	http.StatusGatewayTimeout:        CodeGatewayTimeout,
+}
+
+// Details is an RFC 7807 problem details object. Code and CorrelationID
+// are extension members; Error repeats Detail for clients of the error
+// responses that predate problem details.
+type Details struct {
+	Type          string `json:"type"`
+	Title         string `json:"title"`
+	Status        int    `json:"status"`
+	Detail        string `json:"detail,omitempty"`
+	Instance      string `json:"instance,omitempty"`
+	Code          string `json:"code"`
+	CorrelationID string `json:"correlation_id,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// Code returns the error code of a response status
+func Code(status int) string {
+	if code, ok := codes[status]; ok {
+		return code
+	}
+	if status >= 500 {
+		return CodeServerError
+	}
+	return CodeClientError
+}
+
+// New returns the problem details of a response status
+func New(status int, detail string) *Details {
+	code := Code(status)
+	return &Details{
+		Type:   typePrefix + code,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+		Error:  detail,
+	}
+}
+
+// Write writes an error response as problem details, carrying the
+// correlation ID Middleware set on the response
+func Write(w http.ResponseWriter, status int, detail string) {
+	WriteDetails(w, New(status, detail))
+}
+
+// WriteDetails writes problem details as the error response
+func WriteDetails(w http.ResponseWriter, d *Details) {
+	if d.CorrelationID == "" {
+		d.CorrelationID = w.Header().Get(HeaderCorrelationID)
+	}
+	if rec, ok := w.(*recorder); ok {
+		rec.detail = d.Detail
+		if d.Instance == "" {
+			d.Instance = rec.path
+		}
+	}
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(d.Status)
+	if err := json.NewEncoder(w).Encode(d); err != nil {
+		log.Printf("[HTTP] Failed to encode problem details: %v", err)
+	}
+}
+
+// correlationKey and traceparentKey are the context keys of the
+// correlation ID and trace context of a request
+type (
+	correlationKey struct{}
+	traceparentKey struct{}
+)
+
+// CorrelationID returns the correlation ID of the request ctx belongs to
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationKey{}).(string)
+	return id
+}
+
+// Traceparent returns the W3C traceparent the request ctx belongs to was
+// sent with, if it was valid
+func Traceparent(ctx context.Context) string {
+	tp, _ := ctx.Value(traceparentKey{}).(string)
+	return tp
+}
+
+// Middleware gives every request a correlation ID, echoes it in the
+// response and logs failed and mutating requests with it
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		tp := r.Header.Get(HeaderTraceparent)
+		trace, ok := traceID(tp)
+		if ok {
+			ctx = context.WithValue(ctx, traceparentKey{}, tp)
+		}
+		id := r.Header.Get(HeaderRequestID)
+		if !validCorrelationID(id) {
+			id = r.Header.Get(HeaderCorrelationID)
+		}
+		switch {
+		case validCorrelationID(id):
+		case ok:
+			id = trace
+		default:
+			id = newCorrelationID()
+		}
+		w.Header().Set(HeaderCorrelationID, id)
+		w.Header().Set(HeaderRequestID, id)
+
+		rec := &recorder{ResponseWriter: w, path: r.URL.Path, status: http.StatusOK}
+		next.ServeHTTP(rec, r.WithContext(context.WithValue(ctx, correlationKey{}, id)))
+		switch {
+		case rec.status >= 400:
+			log.Printf("[HTTP] %s %s failed with %d %s (correlation %s)", r.Method, r.URL.Path, rec.status, rec.detail, id)
+		case r.Method != http.MethodGet && r.Method != http.MethodHead && r.Method != http.MethodOptions:
+			log.Printf("[HTTP] %s %s from %s returned %d (correlation %s)", r.Method, r.URL.Path, r.RemoteAddr, rec.status, id)
+		}
+	})
+}
+
+// NotFound answers requests for routes that do not exist
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	Write(w, http.StatusNotFound, "no route for "+r.URL.Path)
+}
+
+// recorder remembers the status and problem detail of a response
+type recorder struct {
+	http.ResponseWriter
+	path   string
+	status int
+	detail string
+	wrote  bool
+}
+
+// WriteHeader implements http.ResponseWriter
+func (r *recorder) WriteHeader(status int) {
+	if !r.wrote {
+		r.status, r.wrote = status, true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write implements http.ResponseWriter
+func (r *recorder) Write(b []byte) (int, error) {
+	r.wrote = true
+	return r.ResponseWriter.Write(b)
+}
+
+// Flush passes flushes through for streamed responses
+func (r *recorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the wrapped writer
+func (r *recorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// validCorrelationID reports whether a client's correlation ID can be
+// echoed and logged as is
+func validCorrelationID(id string) bool {
+	if id == "" || len(id) > maxCorrelationID {
+		return false
+	}
+	return strings.IndexFunc(id, func(r rune) bool {
+		return !(r == '-' || r == '_' || r == '.' || r == ':' ||
+			'0' <= r && r <= '9' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z')
+	}) < 0
+}
+
+// traceID returns the trace ID of a version 00 W3C traceparent,
+// version-traceid-parentid-flags in lowercase hex
+func traceID(traceparent string) (string, bool) {
+	parts := strings.Split(traceparent, "-")
+	if len(parts) != 4 || parts[0] != "00" || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return "", false
+	}
+	for _, part := range parts[1:] {
+		if strings.Trim(part, "0123456789abcdef") != "" {
+			return "", false
+		}
+	}
+	// All-zero trace and parent IDs are invalid
+	if strings.Trim(parts[1], "0") == "" || strings.Trim(parts[2], "0") == "" {
+		return "", false
+	}
+	return parts[1], true
+}
+
+// newCorrelationID returns a random correlation ID
+func newCorrelationID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,45 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: backfill-budget
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Backfill Budgets
+ */
+
+package registry
+
+import (
+	"fmt"
+	"time"
+)
+
+// Period returns the length of the budget period
+func (b *BackfillBudget) Period() time.Duration {
+	if b.Per == BudgetPerHour {
+		return time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// validateBackfill checks the budget of the backfill
+func (p *Pipeline) validateBackfill() error {
+	if p.Backfill == nil || p.Backfill.Budget == nil {
+		return nil
+	}
+	b := p.Backfill.Budget
+	if b.Records <= 0 {
+		return fmt.Errorf("pipeline %s has an invalid backfill budget: records must be positive", p.ID)
+	}
+	if b.Per != BudgetPerHour && b.Per != BudgetPerDay {
+		return fmt.Errorf("pipeline %s has an invalid backfill budget: per must be %s or %s", p.ID, BudgetPerHour, BudgetPerDay)
+	}
+	return nil
+}
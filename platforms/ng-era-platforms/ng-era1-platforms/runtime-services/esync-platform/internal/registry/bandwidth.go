@@ -0,0 +1,73 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: bandwidth-schedules
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Bandwidth Schedules
+ */
+
+package registry
+
+import (
+	"fmt"
+	"time"
+)
+
+// BandwidthSchedule is a parsed bandwidth spec
+type BandwidthSchedule struct {
+	rate     int64
+	profiles []bandwidthProfile
+}
+
+// bandwidthProfile is a parsed bandwidth profile
+type bandwidthProfile struct {
+	calendar *Calendar
+	rate     int64
+}
+
+// Schedule parses the spec
+func (s *BandwidthSpec) Schedule() (*BandwidthSchedule, error) {
+	if s.BytesPerSecond < 0 {
+		return nil, fmt.Errorf("bytes_per_second must not be negative")
+	}
+	schedule := &BandwidthSchedule{rate: s.BytesPerSecond}
+	for i, profile := range s.Profiles {
+		if profile.BytesPerSecond < 0 {
+			return nil, fmt.Errorf("profile %d: bytes_per_second must not be negative", i+1)
+		}
+		hours := ActiveHoursSpec{Timezone: s.Timezone, Windows: profile.Windows}
+		calendar, err := hours.Calendar()
+		if err != nil {
+			return nil, fmt.Errorf("profile %d: %w", i+1, err)
+		}
+		schedule.profiles = append(schedule.profiles, bandwidthProfile{calendar: calendar, rate: profile.BytesPerSecond})
+	}
+	return schedule, nil
+}
+
+// Calendar parses the read windows of the spec; nil without windows
+func (s *SourceLoadSpec) Calendar() (*Calendar, error) {
+	if len(s.Windows) == 0 {
+		return nil, nil
+	}
+	hours := ActiveHoursSpec{Timezone: s.Timezone, Windows: s.Windows}
+	return hours.Calendar()
+}
+
+// Rate returns the bytes per second allowed at t; zero means unlimited
+func (s *BandwidthSchedule) Rate(t time.Time) int64 {
+	for _, p := range s.profiles {
+		if p.calendar.Active(t) {
+			return p.rate
+		}
+	}
+	return s.rate
+}
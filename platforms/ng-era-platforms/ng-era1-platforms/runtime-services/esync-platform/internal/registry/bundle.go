@@ -0,0 +1,640 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: pipeline-bundles
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Pipeline Bundle Export and Import
+ */
+
+package registry
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+)
+
+// BundleFormat is the version of the bundle layout. Import refuses bundles
+// of a newer format.
+const BundleFormat = 1
+
+// bundleManifest is the first entry of every bundle
+const bundleManifest = "manifest.json"
+
+// maxBundleEntries bounds the entries read from a bundle
+const maxBundleEntries = 10000
+
+// Conflict policies deciding what importing a pipeline whose ID is taken does
+const (
+	// ConflictSkip keeps the registered pipeline
+	ConflictSkip = "skip"
+	// ConflictOverwrite replaces the definition file of the registered
+	// pipeline and its overlays
+	ConflictOverwrite = "overwrite"
+	// ConflictRename imports the pipeline under a free ID
+	ConflictRename = "rename"
+)
+
+// ErrBundleFormat is returned for bundles Import cannot read
+var ErrBundleFormat = errors.New("unsupported pipeline bundle")
+
+// BundleManifest describes a pipeline bundle
+type BundleManifest struct {
+	Format    int       `json:"format"`
+	CreatedAt time.Time `json:"created_at"`
+	// Selector is the label selector the pipelines were exported by
+	Selector    string   `json:"selector,omitempty"`
+	Pipelines   []string `json:"pipelines"`
+	Connections []string `json:"connections"`
+	// Redacted lists the connections whose literal credentials were left
+	// out; fill them in after importing
+	Redacted []string `json:"redacted,omitempty"`
+	// Digest identifies the content of the bundle; bundles of the same
+	// definitions share it
+	Digest string `json:"digest,omitempty"`
+}
+
+// Export writes a gzipped tar archive of the pipelines matching a label
+// selector to w: their base definition files and the overlays of every
+// environment, plus the connection profiles they reference. Pipelines added
+// in code are exported as definition files. Literal credentials of
+// connection profiles are redacted; references to secrets are kept.
+func (s *Service) Export(w io.Writer, selector string) (*BundleManifest, error) {
+	sel, err := ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	reg := s.Snapshot()
+	return s.export(w, reg, selector, reg.Select(sel))
+}
+
+// ExportMatching writes a bundle of the pipelines match accepts, as Export
+// does; selector describes the selection in the manifest
+func (s *Service) ExportMatching(w io.Writer, selector string, match func(*Pipeline) bool) (*BundleManifest, error) {
+	reg := s.Snapshot()
+	var pipelines []*Pipeline
+	for _, p := range reg.Select(nil) {
+		if match(p) {
+			pipelines = append(pipelines, p)
+		}
+	}
+	return s.export(w, reg, selector, pipelines)
+}
+
+// export writes a bundle of pipelines of a snapshot
+func (s *Service) export(w io.Writer, reg *Snapshot, selector string, pipelines []*Pipeline) (*BundleManifest, error) {
+	manifest := &BundleManifest{
+		Format:      BundleFormat,
+		CreatedAt:   time.Now().UTC(),
+		Selector:    selector,
+		Pipelines:   []string{},
+		Connections: []string{},
+	}
+	entries := make(map[string][]byte)
+	for _, p := range pipelines {
+		if err := s.exportPipeline(p, entries); err != nil {
+			return nil, fmt.Errorf("failed to export pipeline %s: %w", p.ID, err)
+		}
+		manifest.Pipelines = append(manifest.Pipelines, p.ID)
+	}
+	// Overlays of other environments may reference other connections
+	connections := make(map[string]bool)
+	for _, data := range entries {
+		var doc map[string]interface{}
+		if err := decodeYAML(data, &doc); err != nil {
+			continue
+		}
+		for _, name := range docConnections(doc) {
+			connections[name] = true
+		}
+	}
+	for name := range connections {
+		c, exists := reg.connections[name]
+		if !exists {
+			continue
+		}
+		redacted := c.Redacted()
+		if !connectionEqual(c, redacted) {
+			manifest.Redacted = append(manifest.Redacted, name)
+		}
+		data, err := yaml.Marshal(redacted)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode connection %s: %w", name, err)
+		}
+		entries["connections/"+name+".yaml"] = data
+		manifest.Connections = append(manifest.Connections, name)
+	}
+	sort.Strings(manifest.Connections)
+	sort.Strings(manifest.Redacted)
+
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	digest := sha256.New()
+	for _, name := range names {
+		fmt.Fprintf(digest, "%s\x00%d\x00", name, len(entries[name]))
+		digest.Write(entries[name])
+	}
+	manifest.Digest = hex.EncodeToString(digest.Sum(nil))
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	add := func(name string, data []byte) error {
+		hdr := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), ModTime: manifest.CreatedAt}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err == nil {
+		err = add(bundleManifest, data)
+	}
+	for _, name := range names {
+		if err != nil {
+			break
+		}
+		err = add(name, entries[name])
+	}
+	if err == nil {
+		err = tw.Close()
+	}
+	if err == nil {
+		err = gz.Close()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write pipeline bundle: %w", err)
+	}
+	return manifest, nil
+}
+
+// exportPipeline adds the definition and overlays of a pipeline to entries
+func (s *Service) exportPipeline(p *Pipeline, entries map[string][]byte) error {
+	if p.File == "" {
+		data, err := yaml.Marshal(p)
+		if err != nil {
+			return err
+		}
+		entries["pipelines/"+p.ID+".yaml"] = data
+		return nil
+	}
+
+	base := filepath.Base(p.File)
+	data, err := readFile(p.File)
+	if err != nil {
+		return err
+	}
+	entries["pipelines/"+base] = data
+	overlays, err := filepath.Glob(filepath.Join(s.pipelinesDir, "overlays", "*", base))
+	if err != nil {
+		return err
+	}
+	for _, overlay := range overlays {
+		data, err := readFile(overlay)
+		if err != nil {
+			return err
+		}
+		env := filepath.Base(filepath.Dir(overlay))
+		entries["overlays/"+env+"/"+base] = data
+	}
+	return nil
+}
+
+// docConnections returns the connection profiles a pipeline document or
+// overlay references
+func docConnections(doc map[string]interface{}) []string {
+	specs := []interface{}{doc["source"], doc["target"]}
+	routes, _ := doc["routes"].([]interface{})
+	for _, r := range routes {
+		if route, ok := r.(map[string]interface{}); ok {
+			specs = append(specs, route["target"])
+		}
+	}
+
+	var names []string
+	for _, spec := range specs {
+		if m, ok := spec.(map[string]interface{}); ok {
+			if name, ok := m["connection"].(string); ok && name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// connectionEqual reports whether redacting left a connection unchanged
+func connectionEqual(c, redacted *Connection) bool {
+	a, errA := yaml.Marshal(c)
+	b, errB := yaml.Marshal(redacted)
+	return errA == nil && errB == nil && string(a) == string(b)
+}
+
+// ImportReport is the outcome of importing a pipeline bundle
+type ImportReport struct {
+	Format     int    `json:"format"`
+	OnConflict string `json:"on_conflict"`
+	DryRun     bool   `json:"dry_run"`
+	// Pipelines lists what happened to each pipeline of the bundle
+	Pipelines []ImportedPipeline `json:"pipelines"`
+	// Connections lists what happened to each connection profile; profiles
+	// already defined here are never replaced
+	Connections []ImportedConnection `json:"connections"`
+}
+
+// ImportedPipeline is the outcome of importing one pipeline
+type ImportedPipeline struct {
+	ID string `json:"id"`
+	// Action is created, overwritten, renamed or skipped
+	Action string `json:"action"`
+	// ImportedAs is the ID of a renamed pipeline
+	ImportedAs string `json:"imported_as,omitempty"`
+	File       string `json:"file,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// ImportedConnection is the outcome of importing one connection profile
+type ImportedConnection struct {
+	Name string `json:"name"`
+	// Action is created or kept
+	Action string `json:"action"`
+}
+
+// bundle is a bundle read into memory
+type bundle struct {
+	manifest    *BundleManifest
+	pipelines   map[string][]byte
+	overlays    map[string]map[string][]byte
+	connections map[string][]byte
+}
+
+// fileChange records the content a file had before an import wrote it
+type fileChange struct {
+	path    string
+	old     []byte
+	existed bool
+}
+
+// Import registers the pipelines of a bundle written by Export, resolving
+// pipelines whose ID is already registered by the conflicts policy, and
+// adds the connection profiles not defined here. The bundle is read and
+// every pipeline validated before any file is written; when the registry
+// then fails to load, every file is restored. A dry run only reports what
+// an import would do.
+func (s *Service) Import(r io.Reader, conflicts string, dryRun bool) (*ImportReport, error) {
+	switch conflicts {
+	case "":
+		conflicts = ConflictSkip
+	case ConflictSkip, ConflictOverwrite, ConflictRename:
+	default:
+		return nil, fmt.Errorf("unknown conflict policy %q: use skip, overwrite or rename", conflicts)
+	}
+	if s.pipelinesDir == "" {
+		return nil, fmt.Errorf("importing needs a pipelines directory")
+	}
+	b, err := readBundle(r)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reg := s.Snapshot()
+	report := &ImportReport{
+		Format:      b.manifest.Format,
+		OnConflict:  conflicts,
+		DryRun:      dryRun,
+		Pipelines:   []ImportedPipeline{},
+		Connections: []ImportedConnection{},
+	}
+
+	// Connection profiles defined here win: they hold this deployment's
+	// endpoints and credentials
+	connections := make(map[string]bool, len(reg.connections))
+	for name := range reg.connections {
+		connections[name] = true
+	}
+	writes := make(map[string][]byte)
+	var removals []string
+	for _, name := range sortedKeys(b.connections) {
+		if connections[name] {
+			report.Connections = append(report.Connections, ImportedConnection{Name: name, Action: "kept"})
+			continue
+		}
+		connections[name] = true
+		writes[filepath.Join(s.connectionsDir(), name+".yaml")] = b.connections[name]
+		report.Connections = append(report.Connections, ImportedConnection{Name: name, Action: "created"})
+	}
+
+	taken := make(map[string]bool)
+	for id := range reg.pipelines {
+		taken[id] = true
+	}
+	for _, file := range sortedKeys(b.pipelines) {
+		var doc map[string]interface{}
+		if err := decodeYAML(b.pipelines[file], &doc); err != nil {
+			return nil, fmt.Errorf("%w: pipelines/%s: %v", ErrBundleFormat, file, err)
+		}
+		id, _ := doc["id"].(string)
+		if id == "" {
+			return nil, fmt.Errorf("%w: pipelines/%s has no id", ErrBundleFormat, file)
+		}
+		result := ImportedPipeline{ID: id, Action: "created"}
+		data := b.pipelines[file]
+		dst := filepath.Join(s.pipelinesDir, file)
+
+		if existing, exists := reg.pipelines[id]; exists {
+			switch {
+			case conflicts == ConflictSkip:
+				result.Action, result.Reason = "skipped", "pipeline already registered"
+			case conflicts == ConflictOverwrite && existing.File == "":
+				result.Action, result.Reason = "skipped", "pipeline is defined in code"
+			case conflicts == ConflictOverwrite:
+				result.Action, dst = "overwritten", existing.File
+				stale, _ := filepath.Glob(filepath.Join(s.pipelinesDir, "overlays", "*", filepath.Base(dst)))
+				removals = append(removals, stale...)
+			default:
+				result.Action, result.ImportedAs = "renamed", s.freeID(id, taken)
+				doc["id"] = result.ImportedAs
+				if data, err = yaml.Marshal(doc); err != nil {
+					return nil, fmt.Errorf("failed to encode pipeline %s: %w", id, err)
+				}
+				dst = filepath.Join(s.pipelinesDir, result.ImportedAs+".yaml")
+			}
+		} else if taken[id] {
+			return nil, fmt.Errorf("%w: pipeline %s is in the bundle twice", ErrBundleFormat, id)
+		}
+		if result.Action == "skipped" {
+			report.Pipelines = append(report.Pipelines, result)
+			continue
+		}
+		taken[importedID(result)] = true
+
+		// Another pipeline's file may already have the bundle's file name
+		if _, err := os.Stat(dst); err == nil && result.Action != "overwritten" {
+			dst = filepath.Join(s.pipelinesDir, importedID(result)+".yaml")
+		}
+		if _, pending := writes[dst]; pending || (result.Action != "overwritten" && fileExists(dst)) {
+			return nil, fmt.Errorf("cannot import pipeline %s: %s already exists", id, dst)
+		}
+		if err := s.validateImport(result, data, b.overlays[file], connections); err != nil {
+			return nil, err
+		}
+
+		writes[dst] = data
+		for env, overlay := range b.overlays[file] {
+			writes[filepath.Join(s.overlayDir(env), filepath.Base(dst))] = overlay
+		}
+		result.File = dst
+		report.Pipelines = append(report.Pipelines, result)
+	}
+	if dryRun {
+		return report, nil
+	}
+
+	changes, err := applyFiles(writes, removals)
+	if err == nil {
+		err = s.loadAll()
+	}
+	if err != nil {
+		if rollbackErr := rollbackFiles(changes); rollbackErr != nil {
+			return nil, fmt.Errorf("failed to import pipeline bundle: %w (restoring files failed: %v)", err, rollbackErr)
+		}
+		return nil, fmt.Errorf("failed to import pipeline bundle: %w", err)
+	}
+	return report, nil
+}
+
+// validateImport checks that a pipeline of a bundle loads in the active
+// environment with the connection profiles it will have
+func (s *Service) validateImport(result ImportedPipeline, data []byte, overlays map[string][]byte, connections map[string]bool) error {
+	if patch, exists := overlays[s.environment]; exists && s.environment != "" {
+		var baseDoc, patchDoc map[string]interface{}
+		if err := decodeYAML(data, &baseDoc); err != nil {
+			return fmt.Errorf("failed to parse pipeline %s: %w", result.ID, err)
+		}
+		if err := decodeYAML(patch, &patchDoc); err != nil {
+			return fmt.Errorf("failed to parse %s overlay of pipeline %s: %w", s.environment, result.ID, err)
+		}
+		merged, err := yaml.Marshal(mergePatch(baseDoc, patchDoc))
+		if err != nil {
+			return fmt.Errorf("failed to render overlay: %w", err)
+		}
+		data = merged
+	}
+
+	p, err := Parse(data)
+	if err != nil {
+		return fmt.Errorf("invalid pipeline %s: %w", result.ID, err)
+	}
+	p.Environment = s.environment
+	if err := p.validate(); err != nil {
+		return err
+	}
+	for _, spec := range p.connectorSpecs() {
+		if spec.Connection != "" && !connections[spec.Connection] {
+			return fmt.Errorf("pipeline %s references connection %s, which neither the bundle nor this deployment defines", result.ID, spec.Connection)
+		}
+		if spec.Connection == "" {
+			if err := connectors.ValidateConfig(spec.Type, spec.Config); err != nil {
+				return fmt.Errorf("pipeline %s: %w", result.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// readBundle reads a bundle written by Export into memory
+func readBundle(r io.Reader) (*bundle, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBundleFormat, err)
+	}
+	defer gz.Close()
+
+	b := &bundle{
+		pipelines:   make(map[string][]byte),
+		overlays:    make(map[string]map[string][]byte),
+		connections: make(map[string][]byte),
+	}
+	tr := tar.NewReader(gz)
+	for entries := 0; ; entries++ {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrBundleFormat, err)
+		}
+		if entries == maxBundleEntries {
+			return nil, fmt.Errorf("%w: more than %d entries", ErrBundleFormat, maxBundleEntries)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(io.LimitReader(tr, maxFileSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrBundleFormat, err)
+		}
+		if len(data) > maxFileSize {
+			return nil, fmt.Errorf("%w: entry %s is too large", ErrBundleFormat, hdr.Name)
+		}
+
+		parts := strings.Split(path.Clean(hdr.Name), "/")
+		for _, part := range parts[:len(parts)-1] {
+			if !fileID.MatchString(part) {
+				return nil, fmt.Errorf("%w: invalid entry %s", ErrBundleFormat, hdr.Name)
+			}
+		}
+		if name := parts[len(parts)-1]; name != bundleManifest && (!strings.HasSuffix(name, ".yaml") || !fileID.MatchString(name)) {
+			return nil, fmt.Errorf("%w: invalid entry %s", ErrBundleFormat, hdr.Name)
+		}
+		switch {
+		case len(parts) == 1 && parts[0] == bundleManifest:
+			b.manifest = &BundleManifest{}
+			if err := json.Unmarshal(data, b.manifest); err != nil {
+				return nil, fmt.Errorf("%w: invalid manifest: %v", ErrBundleFormat, err)
+			}
+		case len(parts) == 2 && parts[0] == "pipelines":
+			b.pipelines[parts[1]] = data
+		case len(parts) == 2 && parts[0] == "connections":
+			b.connections[strings.TrimSuffix(parts[1], ".yaml")] = data
+		case len(parts) == 3 && parts[0] == "overlays":
+			if b.overlays[parts[2]] == nil {
+				b.overlays[parts[2]] = make(map[string][]byte)
+			}
+			b.overlays[parts[2]][parts[1]] = data
+		default:
+			return nil, fmt.Errorf("%w: unexpected entry %s", ErrBundleFormat, hdr.Name)
+		}
+	}
+	if b.manifest == nil {
+		return nil, fmt.Errorf("%w: missing %s", ErrBundleFormat, bundleManifest)
+	}
+	if b.manifest.Format > BundleFormat {
+		return nil, fmt.Errorf("%w: format %d is newer than %d", ErrBundleFormat, b.manifest.Format, BundleFormat)
+	}
+	for file := range b.overlays {
+		if _, exists := b.pipelines[file]; !exists {
+			return nil, fmt.Errorf("%w: overlays of %s have no base pipeline", ErrBundleFormat, file)
+		}
+	}
+	return b, nil
+}
+
+// freeID returns the first of id-imported, id-imported-2, ... neither taken
+// nor naming an existing definition file
+func (s *Service) freeID(id string, taken map[string]bool) string {
+	candidate := id + "-imported"
+	for n := 2; taken[candidate] || fileExists(filepath.Join(s.pipelinesDir, candidate+".yaml")); n++ {
+		candidate = fmt.Sprintf("%s-imported-%d", id, n)
+	}
+	return candidate
+}
+
+// importedID returns the ID a pipeline is imported under
+func importedID(result ImportedPipeline) string {
+	if result.ImportedAs != "" {
+		return result.ImportedAs
+	}
+	return result.ID
+}
+
+// fileExists reports whether a file exists
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// applyFiles writes and removes files, returning what it changed so that
+// rollbackFiles can undo it
+func applyFiles(writes map[string][]byte, removals []string) ([]fileChange, error) {
+	var changes []fileChange
+	record := func(path string) error {
+		old, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		changes = append(changes, fileChange{path: path, old: old, existed: err == nil})
+		return nil
+	}
+
+	for _, path := range removals {
+		if _, replaced := writes[path]; replaced {
+			continue
+		}
+		if err := record(path); err != nil {
+			return changes, err
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return changes, fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+	}
+	for _, path := range sortedKeys(writes) {
+		if err := record(path); err != nil {
+			return changes, err
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return changes, fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, writes[path], 0o644); err != nil {
+			return changes, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	return changes, nil
+}
+
+// rollbackFiles restores the files changed by applyFiles
+func rollbackFiles(changes []fileChange) error {
+	var errs []error
+	for i := len(changes) - 1; i >= 0; i-- {
+		c := changes[i]
+		var err error
+		if c.existed {
+			err = os.WriteFile(c.path, c.old, 0o644)
+		} else if err = os.Remove(c.path); errors.Is(err, os.ErrNotExist) {
+			err = nil
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sortedKeys returns the keys of m in order
+func sortedKeys(m map[string][]byte) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
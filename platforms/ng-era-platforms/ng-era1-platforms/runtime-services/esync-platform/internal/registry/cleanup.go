@@ -0,0 +1,45 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: cleanup-pipelines
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Cleanup Pipeline Validation
+ */
+
+package registry
+
+import "fmt"
+
+// validateCleanup checks that cleanup pipelines say what to delete and
+// that only they carry a cleanup block
+func (p *Pipeline) validateCleanup() error {
+	if p.Mode != ModeCleanup {
+		if p.Cleanup != nil {
+			return fmt.Errorf("pipeline %s sets cleanup but its mode is not %s", p.ID, ModeCleanup)
+		}
+		return nil
+	}
+
+	c := p.Cleanup
+	switch {
+	case c == nil || (!c.Orphans && c.TTL == 0):
+		return fmt.Errorf("cleanup pipeline %s must enable cleanup.orphans or set cleanup.ttl", p.ID)
+	case c.TTL < 0:
+		return fmt.Errorf("cleanup pipeline %s has a negative ttl", p.ID)
+	case c.MaxDeletes < 0:
+		return fmt.Errorf("cleanup pipeline %s has a negative max_deletes", p.ID)
+	case c.AgeField != "" && c.TTL == 0:
+		return fmt.Errorf("cleanup pipeline %s sets age_field without ttl", p.ID)
+	case len(p.Routes) > 0:
+		return fmt.Errorf("cleanup pipeline %s cannot route tables to other targets", p.ID)
+	}
+	return nil
+}
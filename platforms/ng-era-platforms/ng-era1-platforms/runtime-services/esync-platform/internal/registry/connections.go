@@ -0,0 +1,325 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: connection-profiles
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Named Connection Profiles
+ */
+
+package registry
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// Connection is a named endpoint definition shared by pipelines. Connections
+// live in <pipelines>/connections/<name>.yaml and connector specs reference
+// them with connection: <name>; the connection's settings form the base of
+// the connector config, which the pipeline's own config block overrides.
+// Credentials should be ${secret:NAME} references so that rotating them
+// touches one file.
+//
+// Frozen: public as pipeline.Connection
+type Connection struct {
+	Name string `yaml:"name" json:"name"`
+	// Type is the connector type used when a spec names none
+	Type     string    `yaml:"type,omitempty" json:"type,omitempty"`
+	Host     string    `yaml:"host,omitempty" json:"host,omitempty"`
+	Port     int       `yaml:"port,omitempty" json:"port,omitempty"`
+	Database string    `yaml:"database,omitempty" json:"database,omitempty"`
+	Username string    `yaml:"username,omitempty" json:"username,omitempty"`
+	Password string    `yaml:"password,omitempty" json:"password,omitempty"`
+	TLS      *TLSSpec  `yaml:"tls,omitempty" json:"tls,omitempty"`
+	Pool     *PoolSpec `yaml:"pool,omitempty" json:"pool,omitempty"`
+	// Proxy is an http, https or socks5 URL overriding the daemon proxy
+	// for this endpoint
+	Proxy string `yaml:"proxy,omitempty" json:"proxy,omitempty"`
+	// Region is the residency region of the endpoint, such as eu-west
+	Region string `yaml:"region,omitempty" json:"region,omitempty"`
+	// Tunnel reaches the endpoint through an SSH bastion
+	Tunnel *TunnelSpec `yaml:"tunnel,omitempty" json:"tunnel,omitempty"`
+	// CloudAuth authenticates to a cloud API with the credentials of the
+	// workload instead of static keys
+	CloudAuth *CloudAuthSpec `yaml:"cloud_auth,omitempty" json:"cloud_auth,omitempty"`
+	// Limits caps the calls to the endpoint, shared by every pipeline using
+	// the connection
+	Limits *CallLimits `yaml:"limits,omitempty" json:"limits,omitempty"`
+	// Config holds further connector settings
+	Config map[string]interface{} `yaml:"config,omitempty" json:"config,omitempty"`
+}
+
+// TLSSpec configures TLS to an endpoint
+//
+// Frozen: public as pipeline.TLSSpec
+type TLSSpec struct {
+	Enabled            bool   `yaml:"enabled" json:"enabled"`
+	CAFile             string `yaml:"ca_file,omitempty" json:"ca_file,omitempty"`
+	CertFile           string `yaml:"cert_file,omitempty" json:"cert_file,omitempty"`
+	KeyFile            string `yaml:"key_file,omitempty" json:"key_file,omitempty"`
+	ServerName         string `yaml:"server_name,omitempty" json:"server_name,omitempty"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify,omitempty" json:"insecure_skip_verify,omitempty"`
+	// MinVersion is the lowest TLS version accepted, such as "1.2"
+	MinVersion string `yaml:"min_version,omitempty" json:"min_version,omitempty"`
+	// CipherSuites restricts TLS 1.2 suites by their IANA names
+	CipherSuites []string `yaml:"cipher_suites,omitempty" json:"cipher_suites,omitempty"`
+}
+
+// TunnelSpec describes the SSH bastion an endpoint is reached through
+//
+// Frozen: public as pipeline.TunnelSpec
+type TunnelSpec struct {
+	Host string `yaml:"host" json:"host"`
+	Port int    `yaml:"port,omitempty" json:"port,omitempty"`
+	User string `yaml:"user" json:"user"`
+	// PrivateKey authenticates to the bastion, normally a ${secret:NAME}
+	// reference
+	PrivateKey string `yaml:"private_key" json:"private_key"`
+	// KnownHosts is a known_hosts file verifying the bastion host key
+	KnownHosts string `yaml:"known_hosts,omitempty" json:"known_hosts,omitempty"`
+	// HostKey is the bastion public key ("ssh-ed25519 AAAA...") used
+	// instead of a known_hosts file
+	HostKey string `yaml:"host_key,omitempty" json:"host_key,omitempty"`
+}
+
+// CloudAuthSpec selects the cloud provider credentials connectors sign
+// their requests with: AWS SigV4 with web identity, container or instance
+// credentials, GCP metadata server tokens or Azure managed identity tokens
+//
+// Frozen: public as pipeline.CloudAuthSpec
+type CloudAuthSpec struct {
+	// Provider is aws, gcp or azure
+	Provider string `yaml:"provider" json:"provider"`
+	// Region and Service scope AWS signatures; RoleARN is a role assumed
+	// with the workload credentials
+	Region  string `yaml:"region,omitempty" json:"region,omitempty"`
+	Service string `yaml:"service,omitempty" json:"service,omitempty"`
+	RoleARN string `yaml:"role_arn,omitempty" json:"role_arn,omitempty"`
+	// Scopes of GCP access tokens; Audience requests a GCP identity token
+	// instead, Account selects a service account other than the default
+	Scopes   []string `yaml:"scopes,omitempty" json:"scopes,omitempty"`
+	Audience string   `yaml:"audience,omitempty" json:"audience,omitempty"`
+	Account  string   `yaml:"account,omitempty" json:"account,omitempty"`
+	// Resource is the Azure resource tokens are issued for; ClientID
+	// selects a user-assigned managed identity
+	Resource string `yaml:"resource,omitempty" json:"resource,omitempty"`
+	ClientID string `yaml:"client_id,omitempty" json:"client_id,omitempty"`
+}
+
+// PoolSpec limits the connections a connector opens to an endpoint
+//
+// Frozen: public as pipeline.PoolSpec
+type PoolSpec struct {
+	MaxOpen int `yaml:"max_open,omitempty" json:"max_open,omitempty"`
+	MaxIdle int `yaml:"max_idle,omitempty" json:"max_idle,omitempty"`
+	// MaxLifetime is a duration such as 30m
+	MaxLifetime string `yaml:"max_lifetime,omitempty" json:"max_lifetime,omitempty"`
+}
+
+// connectionsDir returns the directory holding connection profiles
+func (s *Service) connectionsDir() string {
+	return filepath.Join(s.pipelinesDir, "connections")
+}
+
+// loadConnections reads every connection profile
+func (s *Service) loadConnections() (map[string]*Connection, error) {
+	files, err := filepath.Glob(filepath.Join(s.connectionsDir(), "*.yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan connections directory: %w", err)
+	}
+
+	connections := make(map[string]*Connection, len(files))
+	for _, file := range files {
+		data, err := readFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read connection %s: %w", file, err)
+		}
+		var c Connection
+		if err := decodeYAML(data, &c); err != nil {
+			return nil, fmt.Errorf("failed to parse connection %s: %w", file, err)
+		}
+		if c.Name == "" {
+			c.Name = strings.TrimSuffix(filepath.Base(file), ".yaml")
+		}
+		if _, exists := connections[c.Name]; exists {
+			return nil, fmt.Errorf("connection %s defined twice", c.Name)
+		}
+		if c.CloudAuth != nil {
+			switch c.CloudAuth.Provider {
+			case "aws", "gcp", "azure":
+			default:
+				return nil, fmt.Errorf("connection %s has unknown cloud_auth provider %q, expected aws, gcp or azure", c.Name, c.CloudAuth.Provider)
+			}
+		}
+		if c.Limits != nil {
+			if err := c.Limits.validate(); err != nil {
+				return nil, fmt.Errorf("connection %s has invalid limits: %w", c.Name, err)
+			}
+		}
+		connections[c.Name] = &c
+	}
+
+	return connections, nil
+}
+
+// validate checks the limits are not negative
+func (l *CallLimits) validate() error {
+	if l.RatePerSecond < 0 || l.Burst < 0 || l.MaxConcurrent < 0 {
+		return fmt.Errorf("rate_per_second, burst and max_concurrent must not be negative")
+	}
+	return nil
+}
+
+// AddConnection registers a connection profile defined in code
+func (s *Service) AddConnection(c *Connection) error {
+	if c.Name == "" {
+		return fmt.Errorf("connection name is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := s.Snapshot().next()
+	if _, exists := next.connections[c.Name]; exists {
+		return fmt.Errorf("connection %s already registered", c.Name)
+	}
+	next.connections[c.Name] = c
+	s.publish(next)
+	return nil
+}
+
+// Connections returns all connection profiles of the current snapshot
+// sorted by name
+func (s *Service) Connections() []*Connection {
+	return s.Snapshot().Connections()
+}
+
+// Redacted returns a copy of the connection safe to display: a literal
+// password, proxy credentials and tunnel key are replaced, references to
+// secrets are kept
+func (c *Connection) Redacted() *Connection {
+	out := *c
+	if out.Password != "" && !strings.Contains(out.Password, "${") {
+		out.Password = "***"
+	}
+	if u, err := url.Parse(out.Proxy); err == nil {
+		out.Proxy = u.Redacted()
+	}
+	if out.Tunnel != nil && !strings.Contains(out.Tunnel.PrivateKey, "${") {
+		tunnel := *out.Tunnel
+		tunnel.PrivateKey = "***"
+		out.Tunnel = &tunnel
+	}
+	return &out
+}
+
+// ResolveConnector merges the connection profile a spec references into
+// the spec, using the current snapshot. Specs without a connection are
+// returned unchanged.
+func (s *Service) ResolveConnector(spec ConnectorSpec) (ConnectorSpec, error) {
+	return s.Snapshot().ResolveConnector(spec)
+}
+
+// config renders the connection as connector config
+func (c *Connection) config() map[string]interface{} {
+	config := copyConfig(c.Config)
+	set := func(key string, value interface{}, present bool) {
+		if present {
+			config[key] = value
+		}
+	}
+	set("host", c.Host, c.Host != "")
+	set("port", c.Port, c.Port != 0)
+	set("database", c.Database, c.Database != "")
+	set("username", c.Username, c.Username != "")
+	set("password", c.Password, c.Password != "")
+	set("proxy", c.Proxy, c.Proxy != "")
+	if c.TLS != nil {
+		config["tls"] = withoutZero(map[string]interface{}{
+			"enabled":              c.TLS.Enabled,
+			"ca_file":              c.TLS.CAFile,
+			"cert_file":            c.TLS.CertFile,
+			"key_file":             c.TLS.KeyFile,
+			"server_name":          c.TLS.ServerName,
+			"insecure_skip_verify": c.TLS.InsecureSkipVerify,
+			"min_version":          c.TLS.MinVersion,
+		})
+		if len(c.TLS.CipherSuites) > 0 {
+			suites := make([]interface{}, len(c.TLS.CipherSuites))
+			for i, suite := range c.TLS.CipherSuites {
+				suites[i] = suite
+			}
+			config["tls"].(map[string]interface{})["cipher_suites"] = suites
+		}
+	}
+	if c.Pool != nil {
+		config["pool"] = withoutZero(map[string]interface{}{
+			"max_open":     c.Pool.MaxOpen,
+			"max_idle":     c.Pool.MaxIdle,
+			"max_lifetime": c.Pool.MaxLifetime,
+		})
+	}
+	if c.Tunnel != nil {
+		config["tunnel"] = withoutZero(map[string]interface{}{
+			"host":        c.Tunnel.Host,
+			"port":        c.Tunnel.Port,
+			"user":        c.Tunnel.User,
+			"private_key": c.Tunnel.PrivateKey,
+			"known_hosts": c.Tunnel.KnownHosts,
+			"host_key":    c.Tunnel.HostKey,
+		})
+	}
+	if c.CloudAuth != nil {
+		config["cloud_auth"] = withoutZero(map[string]interface{}{
+			"provider":  c.CloudAuth.Provider,
+			"region":    c.CloudAuth.Region,
+			"service":   c.CloudAuth.Service,
+			"role_arn":  c.CloudAuth.RoleARN,
+			"audience":  c.CloudAuth.Audience,
+			"account":   c.CloudAuth.Account,
+			"resource":  c.CloudAuth.Resource,
+			"client_id": c.CloudAuth.ClientID,
+		})
+		if len(c.CloudAuth.Scopes) > 0 {
+			scopes := make([]interface{}, len(c.CloudAuth.Scopes))
+			for i, scope := range c.CloudAuth.Scopes {
+				scopes[i] = scope
+			}
+			config["cloud_auth"].(map[string]interface{})["scopes"] = scopes
+		}
+	}
+	return config
+}
+
+// withoutZero drops unset settings so connectors apply their own defaults
+func withoutZero(m map[string]interface{}) map[string]interface{} {
+	for k, v := range m {
+		if v == "" || v == 0 || v == false {
+			delete(m, k)
+		}
+	}
+	return m
+}
+
+// copyConfig deep-copies the maps of a config so merging never modifies
+// the original
+func copyConfig(config map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(config))
+	for k, v := range config {
+		if m, ok := v.(map[string]interface{}); ok {
+			v = copyConfig(m)
+		}
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,273 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: pipeline-defaults
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Effective Pipeline Defaults
+ */
+
+package registry
+
+// Defaults applied to settings a pipeline leaves unset
+const (
+	DefaultMode             = ModeSync
+	DefaultRunPolicy        = RunPolicyCoalesce
+	DefaultPriority         = PriorityNormal
+	DefaultMissingFields    = MissingFieldsIgnore
+	DefaultNewTables        = NewTablesInclude
+	DefaultResidency        = ResidencyEnforce
+	DefaultErasureAction    = ErasureDelete
+	DefaultWatchdogAction   = WatchdogAlert
+	DefaultBatchSize        = 1000
+	DefaultApplyWorkers     = 1
+	DefaultBackfillChunks   = 64
+	DefaultBackfillWorkers  = 4
+	DefaultMaxDrainPasses   = 100
+	DefaultIDGenerator      = IDGeneratorUUIDv7
+	DefaultMinFreeBytes     = 512 << 20
+	DefaultCanaryInterval   = 300
+	DefaultCanaryTimeout    = 600
+	DefaultHeartbeatTimeout = 300
+	DefaultCallBurst        = 1
+	DefaultTimezone         = "UTC"
+	DefaultSLOBoostAt       = 0.5
+	DefaultSLOMinInterval   = 10
+	DefaultSLOApplyWorkers  = 8
+	DefaultClockSkew        = 30
+	DefaultClockSkewAction  = ClockSkewCompensate
+	DefaultOnRecordLimit    = RecordLimitDLQ
+	DefaultRolloutPercent   = 10
+	DefaultRolloutSoak      = 3600
+	DefaultSharedSourceAge  = 60
+	DefaultMaxSourceBackoff = 300
+)
+
+// Effective returns a copy of the pipeline with every unset setting replaced
+// by its default, along with the names of the settings that were defaulted
+func (p *Pipeline) Effective() (*Pipeline, []string) {
+	out := *p
+	var defaulted []string
+
+	if out.Mode == "" {
+		out.Mode = DefaultMode
+		defaulted = append(defaulted, "mode")
+	}
+	if out.RunPolicy == "" {
+		out.RunPolicy = DefaultRunPolicy
+		defaulted = append(defaulted, "run_policy")
+	}
+	if out.Priority == "" {
+		out.Priority = DefaultPriority
+		defaulted = append(defaulted, "priority")
+	}
+	if out.BatchSize <= 0 {
+		out.BatchSize = DefaultBatchSize
+		defaulted = append(defaulted, "batch_size")
+	}
+	if out.ApplyWorkers <= 0 {
+		out.ApplyWorkers = DefaultApplyWorkers
+		defaulted = append(defaulted, "apply_workers")
+	}
+	if out.MissingFields == "" {
+		out.MissingFields = DefaultMissingFields
+		defaulted = append(defaulted, "missing_fields")
+	}
+
+	preflight := PreflightSpec{}
+	if p.Preflight != nil {
+		preflight = *p.Preflight
+	}
+	if preflight.MinFreeBytes <= 0 {
+		preflight.MinFreeBytes = DefaultMinFreeBytes
+		defaulted = append(defaulted, "preflight.min_free_bytes")
+	}
+	out.Preflight = &preflight
+
+	backfill := BackfillSpec{}
+	if p.Backfill != nil {
+		backfill = *p.Backfill
+	}
+	if backfill.Chunks <= 0 {
+		backfill.Chunks = DefaultBackfillChunks
+		defaulted = append(defaulted, "backfill.chunks")
+	}
+	if backfill.Workers <= 0 {
+		backfill.Workers = DefaultBackfillWorkers
+		defaulted = append(defaulted, "backfill.workers")
+	}
+	out.Backfill = &backfill
+
+	if p.Tables != nil && p.Tables.NewTables == "" {
+		tables := *p.Tables
+		tables.NewTables = DefaultNewTables
+		defaulted = append(defaulted, "tables.new_tables")
+		out.Tables = &tables
+	}
+
+	if p.Residency != nil && p.Residency.Policy == "" {
+		residency := *p.Residency
+		residency.Policy = DefaultResidency
+		defaulted = append(defaulted, "residency.policy")
+		out.Residency = &residency
+	}
+
+	if p.Erasure != nil && p.Erasure.Action == "" {
+		erasure := *p.Erasure
+		erasure.Action = DefaultErasureAction
+		defaulted = append(defaulted, "erasure.action")
+		out.Erasure = &erasure
+	}
+
+	if p.Canary != nil {
+		canary := *p.Canary
+		if canary.Interval <= 0 {
+			canary.Interval = DefaultCanaryInterval
+			defaulted = append(defaulted, "canary.interval")
+		}
+		if canary.Timeout <= 0 {
+			canary.Timeout = DefaultCanaryTimeout
+			defaulted = append(defaulted, "canary.timeout")
+		}
+		out.Canary = &canary
+	}
+
+	if p.ActiveHours != nil && p.ActiveHours.Timezone == "" {
+		hours := *p.ActiveHours
+		hours.Timezone = DefaultTimezone
+		defaulted = append(defaulted, "active_hours.timezone")
+		out.ActiveHours = &hours
+	}
+
+	if p.CallLimits != nil && p.CallLimits.RatePerSecond > 0 && p.CallLimits.Burst <= 0 {
+		limits := *p.CallLimits
+		limits.Burst = DefaultCallBurst
+		defaulted = append(defaulted, "call_limits.burst")
+		out.CallLimits = &limits
+	}
+
+	if p.Bandwidth != nil && p.Bandwidth.Timezone == "" && len(p.Bandwidth.Profiles) > 0 {
+		bandwidth := *p.Bandwidth
+		bandwidth.Timezone = DefaultTimezone
+		defaulted = append(defaulted, "bandwidth.timezone")
+		out.Bandwidth = &bandwidth
+	}
+
+	if p.Heartbeat != nil && p.Heartbeat.Timeout <= 0 {
+		heartbeat := *p.Heartbeat
+		heartbeat.Timeout = DefaultHeartbeatTimeout
+		defaulted = append(defaulted, "heartbeat.timeout")
+		out.Heartbeat = &heartbeat
+	}
+
+	if p.ClockSkew != nil {
+		skew := *p.ClockSkew
+		if skew.Threshold <= 0 {
+			skew.Threshold = DefaultClockSkew
+			defaulted = append(defaulted, "clock_skew.threshold")
+		}
+		if skew.OnExceeded == "" {
+			skew.OnExceeded = DefaultClockSkewAction
+			defaulted = append(defaulted, "clock_skew.on_exceeded")
+		}
+		out.ClockSkew = &skew
+	}
+
+	if p.RecordLimits != nil && p.RecordLimits.OnExceeded == "" {
+		limits := *p.RecordLimits
+		limits.OnExceeded = DefaultOnRecordLimit
+		defaulted = append(defaulted, "record_limits.on_exceeded")
+		out.RecordLimits = &limits
+	}
+
+	if p.SharedSource != nil && p.SharedSource.MaxAge <= 0 {
+		shared := *p.SharedSource
+		shared.MaxAge = DefaultSharedSourceAge
+		defaulted = append(defaulted, "shared_source.max_age")
+		out.SharedSource = &shared
+	}
+
+	if p.SourceLoad != nil && p.SourceLoad.LatencyThreshold > 0 && p.SourceLoad.MaxBackoff <= 0 {
+		load := *p.SourceLoad
+		load.MaxBackoff = DefaultMaxSourceBackoff
+		defaulted = append(defaulted, "source_load.max_backoff")
+		out.SourceLoad = &load
+	}
+
+	if p.Rollout != nil {
+		rollout := *p.Rollout
+		if rollout.Percent <= 0 {
+			rollout.Percent = DefaultRolloutPercent
+			defaulted = append(defaulted, "rollout.percent")
+		}
+		if rollout.SoakSeconds <= 0 {
+			rollout.SoakSeconds = DefaultRolloutSoak
+			defaulted = append(defaulted, "rollout.soak_seconds")
+		}
+		out.Rollout = &rollout
+	}
+
+	if p.SLO != nil {
+		slo := *p.SLO
+		if slo.BoostAt <= 0 {
+			slo.BoostAt = DefaultSLOBoostAt
+			defaulted = append(defaulted, "slo.boost_at")
+		}
+		if slo.MinInterval <= 0 {
+			slo.MinInterval = DefaultSLOMinInterval
+			defaulted = append(defaulted, "slo.min_interval")
+		}
+		if slo.MaxApplyWorkers <= 0 {
+			slo.MaxApplyWorkers = DefaultSLOApplyWorkers
+			defaulted = append(defaulted, "slo.max_apply_workers")
+		}
+		out.SLO = &slo
+	}
+
+	if p.Watchdog != nil && p.Watchdog.Action == "" {
+		watchdog := *p.Watchdog
+		watchdog.Action = DefaultWatchdogAction
+		defaulted = append(defaulted, "watchdog.action")
+		out.Watchdog = &watchdog
+	}
+
+	if p.KeyMapping != nil && p.KeyMapping.Generator == "" {
+		mapping := *p.KeyMapping
+		mapping.Generator = DefaultIDGenerator
+		defaulted = append(defaulted, "key_mapping.generator")
+		out.KeyMapping = &mapping
+	}
+
+	if out.Mode == ModeMigration {
+		cutover := CutoverSpec{}
+		if p.Cutover != nil {
+			cutover = *p.Cutover
+		}
+		if cutover.MaxDrainPasses <= 0 {
+			cutover.MaxDrainPasses = DefaultMaxDrainPasses
+			defaulted = append(defaulted, "cutover.max_drain_passes")
+		}
+		out.Cutover = &cutover
+	}
+
+	if out.Mode == ModeStandby {
+		standby := StandbySpec{}
+		if p.Standby != nil {
+			standby = *p.Standby
+		}
+		if standby.MaxDrainPasses <= 0 {
+			standby.MaxDrainPasses = DefaultMaxDrainPasses
+			defaulted = append(defaulted, "standby.max_drain_passes")
+		}
+		out.Standby = &standby
+	}
+
+	return &out, defaulted
+}
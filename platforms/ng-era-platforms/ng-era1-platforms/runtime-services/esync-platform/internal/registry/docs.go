@@ -0,0 +1,40 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: pipeline-docs
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Pipeline Ownership and Documentation
+ */
+
+package registry
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// ProdEnvironment is the environment in which every pipeline must name an
+// owner
+const ProdEnvironment = "prod"
+
+// validateDocs checks the ownership and documentation fields of a pipeline
+func (p *Pipeline) validateDocs() error {
+	if p.Owner == "" && p.Environment == ProdEnvironment {
+		return fmt.Errorf("pipeline %s has no owner: owner is required in the %s environment", p.ID, ProdEnvironment)
+	}
+	if p.Runbook != "" {
+		u, err := url.Parse(p.Runbook)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("pipeline %s has an invalid runbook %q: expected an http(s) URL", p.ID, p.Runbook)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: parser-fuzzing
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Pipeline Parser Fuzz Targets
+ */
+
+package registry
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FuzzParse feeds arbitrary documents through pipeline parsing, spec
+// migration, validation and defaulting. A parsed pipeline must encode to
+// a document that parses back to the same pipeline.
+func FuzzParse(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		p, err := Parse(data)
+		if err != nil {
+			return
+		}
+
+		encoded, err := yaml.Marshal(p)
+		if err != nil {
+			t.Fatalf("failed to encode parsed pipeline: %v", err)
+		}
+		again, err := Parse(encoded)
+		if errors.Is(err, ErrTooLarge) {
+			// Encoding expands aliases, which can take a document past
+			// the node limit
+			return
+		}
+		if err != nil {
+			t.Fatalf("failed to parse encoded pipeline: %v\n%s", err, encoded)
+		}
+		reencoded, err := yaml.Marshal(again)
+		if err != nil {
+			t.Fatalf("failed to encode reparsed pipeline: %v", err)
+		}
+		if !bytes.Equal(encoded, reencoded) {
+			t.Fatalf("round trip changed the pipeline:\n%s\nbecame\n%s", encoded, reencoded)
+		}
+
+		_ = p.validate()
+		p.Effective()
+	})
+}
+
+// FuzzDecodeLimits checks that decodeYAML enforces its limits on every
+// input it accepts
+func FuzzDecodeLimits(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var doc interface{}
+		err := decodeYAML(data, &doc)
+		if err != nil && !errors.Is(err, ErrTooLarge) {
+			return
+		}
+		if err == nil && len(data) > maxFileSize {
+			t.Fatalf("accepted a %d byte document", len(data))
+		}
+	})
+}
+
+// FuzzParseSelector checks label selectors parse or fail cleanly and that
+// parsed selectors can be matched
+func FuzzParseSelector(f *testing.F) {
+	f.Fuzz(func(t *testing.T, s string) {
+		sel, err := ParseSelector(s)
+		if err != nil {
+			return
+		}
+		sel.Matches(map[string]string{"env": "prod", "team": "data"})
+	})
+}
@@ -0,0 +1,140 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: active-hours
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Active Hours Calendars
+ */
+
+package registry
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// everyDay is the day mask of windows without days
+const everyDay = 1<<7 - 1
+
+// weekdays maps day names to their time.Weekday
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// Calendar is a parsed active hours spec
+type Calendar struct {
+	loc     *time.Location
+	windows []window
+}
+
+// window is a parsed hours window in minutes since local midnight
+type window struct {
+	days       uint8
+	start, end int
+}
+
+// Calendar parses the spec
+func (s *ActiveHoursSpec) Calendar() (*Calendar, error) {
+	name := s.Timezone
+	if name == "" {
+		name = DefaultTimezone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q", s.Timezone)
+	}
+	if len(s.Windows) == 0 {
+		return nil, fmt.Errorf("at least one window is required")
+	}
+
+	c := &Calendar{loc: loc}
+	for i, w := range s.Windows {
+		parsed := window{days: everyDay}
+		if parsed.start, err = parseClock(w.Start, false); err != nil {
+			return nil, fmt.Errorf("window %d: start: %w", i+1, err)
+		}
+		if parsed.end, err = parseClock(w.End, true); err != nil {
+			return nil, fmt.Errorf("window %d: end: %w", i+1, err)
+		}
+		if len(w.Days) > 0 {
+			parsed.days = 0
+		}
+		for _, day := range w.Days {
+			d, ok := weekdays[strings.ToLower(day)]
+			if !ok {
+				return nil, fmt.Errorf("window %d: unknown day %q, expected mon, tue, wed, thu, fri, sat or sun", i+1, day)
+			}
+			parsed.days |= 1 << d
+		}
+		c.windows = append(c.windows, parsed)
+	}
+	return c, nil
+}
+
+// parseClock parses HH:MM into minutes since midnight; 24:00 is allowed
+// for ends
+func parseClock(s string, end bool) (int, error) {
+	h, m, ok := strings.Cut(s, ":")
+	hour, errH := strconv.Atoi(h)
+	minute, errM := strconv.Atoi(m)
+	switch {
+	case !ok || len(m) != 2 || errH != nil || errM != nil:
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	case end && hour == 24 && minute == 0:
+		return 24 * 60, nil
+	case hour < 0 || hour > 23 || minute < 0 || minute > 59:
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	return hour*60 + minute, nil
+}
+
+// Location returns the time zone of the calendar
+func (c *Calendar) Location() *time.Location {
+	return c.loc
+}
+
+// Active reports whether t falls in one of the calendar's windows
+func (c *Calendar) Active(t time.Time) bool {
+	local := t.In(c.loc)
+	minute := local.Hour()*60 + local.Minute()
+	today := uint8(1) << local.Weekday()
+	yesterday := uint8(1) << ((local.Weekday() + 6) % 7)
+	for _, w := range c.windows {
+		if w.end > w.start {
+			if w.days&today != 0 && minute >= w.start && minute < w.end {
+				return true
+			}
+			continue
+		}
+		// The window crosses midnight
+		if (w.days&today != 0 && minute >= w.start) || (w.days&yesterday != 0 && minute < w.end) {
+			return true
+		}
+	}
+	return false
+}
+
+// Next returns the first minute after t at which the calendar opens or
+// closes, or the zero time when it never changes
+func (c *Calendar) Next(t time.Time) time.Time {
+	active := c.Active(t)
+	next := t.Truncate(time.Minute)
+	for i := 0; i < 8*24*60; i++ {
+		next = next.Add(time.Minute)
+		if c.Active(next) != active {
+			return next
+		}
+	}
+	return time.Time{}
+}
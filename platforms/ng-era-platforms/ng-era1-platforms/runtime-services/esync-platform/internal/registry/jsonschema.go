@@ -0,0 +1,141 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: pipeline-json-schema
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Pipeline JSON Schema
+ */
+
+package registry
+
+import (
+	_ "embed"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+//go:generate go run ../../cmd/schemagen -o pipeline.schema.json
+
+// SchemaID identifies the published pipeline schema
+const SchemaID = "https://machops.io/schemas/esync/pipeline.schema.json"
+
+// PipelineSchema is the JSON Schema of the pipeline YAML format embedded at
+// build time. Regenerate it with go generate after changing spec types.
+//
+//go:embed pipeline.schema.json
+var PipelineSchema []byte
+
+// schemaRequired lists the required YAML keys per spec type
+var schemaRequired = map[string][]string{
+	"Pipeline":          {"id", "source", "target"},
+	"TriggerSpec":       {"type"},
+	"TransformSpec":     {"type"},
+	"RouteSpec":         {"table", "target"},
+	"DDLSpec":           {"policy"},
+	"SLOSpec":           {"freshness"},
+	"BackfillBudget":    {"records", "per"},
+	"PostRunActionSpec": {"type"},
+}
+
+// schemaEnums lists the allowed values of enumerated fields
+var schemaEnums = map[string][]string{
+	"Pipeline.apiVersion":           {CurrentAPIVersion},
+	"Pipeline.mode":                 {ModeSync, ModeMigration, ModeStandby, ModeCleanup},
+	"Pipeline.run_policy":           {RunPolicyCoalesce, RunPolicyQueue, RunPolicyReject},
+	"Pipeline.priority":             {PriorityCritical, PriorityNormal, PriorityBulk},
+	"Pipeline.missing_fields":       {MissingFieldsIgnore, MissingFieldsNull},
+	"TriggerSpec.type":              {TriggerWebhook, TriggerKafka, TriggerPipeline},
+	"TriggerSpec.on":                {"success", "failure", "any"},
+	"CoercionSpec.mode":             {CoercionStrict, CoercionLenient, CoercionOff},
+	"FieldCoercion.mode":            {CoercionStrict, CoercionLenient, CoercionOff},
+	"DDLSpec.policy":                {DDLIgnore, DDLPropagate, DDLApprove},
+	"TableSelectionSpec.new_tables": {NewTablesInclude, NewTablesIgnore, NewTablesAlert},
+	"ResidencySpec.policy":          {ResidencyEnforce, ResidencyWarn},
+	"ErasureSpec.action":            {ErasureDelete, ErasurePatch},
+	"WatchdogSpec.action":           {WatchdogAlert, WatchdogCancel, WatchdogQuarantine},
+	"FieldCoercion.type":            {"string", "integer", "number", "boolean", "timestamp"},
+	"BackfillBudget.per":            {BudgetPerHour, BudgetPerDay},
+	"PostRunActionSpec.type":        {ActionDBTCloud, ActionAirflow, ActionHTTP},
+	"PostRunActionSpec.method":      {"POST", "PUT", "GET"},
+	"OutboxSpec.consume":            {OutboxDelete, OutboxMark},
+	"RecordLimitsSpec.on_exceeded":  {RecordLimitTruncate, RecordLimitDLQ, RecordLimitFail},
+	"PrimaryKeySpec.collation":      {CollationBinary, CollationCaseInsensitive, CollationUnicode},
+}
+
+// GenerateSchema derives the JSON Schema of the pipeline YAML format from
+// the spec types
+func GenerateSchema() ([]byte, error) {
+	schema := schemaFor(reflect.TypeOf(Pipeline{}), "")
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["$id"] = SchemaID
+	schema["title"] = "esync pipeline"
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// schemaFor builds the schema of a Go type; path is "Struct.field" for
+// struct fields so enums can be attached
+func schemaFor(t reflect.Type, path string) map[string]interface{} {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	s := map[string]interface{}{}
+	switch t.Kind() {
+	case reflect.String:
+		s["type"] = "string"
+		if values := schemaEnums[path]; len(values) > 0 {
+			s["enum"] = values
+		}
+	case reflect.Bool:
+		s["type"] = "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		s["type"] = "integer"
+	case reflect.Float32, reflect.Float64:
+		s["type"] = "number"
+	case reflect.Slice:
+		s["type"] = "array"
+		s["items"] = schemaFor(t.Elem(), "")
+	case reflect.Map:
+		s["type"] = "object"
+		if t.Elem().Kind() != reflect.Interface {
+			s["additionalProperties"] = schemaFor(t.Elem(), "")
+		}
+	case reflect.Struct:
+		s["type"] = "object"
+		properties := map[string]interface{}{}
+		additional := false
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, opts, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+			if name == "-" || !f.IsExported() {
+				continue
+			}
+			if strings.Contains(opts, "inline") {
+				additional = true
+				continue
+			}
+			if name == "" {
+				name = strings.ToLower(f.Name)
+			}
+			properties[name] = schemaFor(f.Type, t.Name()+"."+name)
+		}
+		s["properties"] = properties
+		s["additionalProperties"] = additional
+		if required := schemaRequired[t.Name()]; len(required) > 0 {
+			s["required"] = required
+		}
+	}
+
+	return s
+}
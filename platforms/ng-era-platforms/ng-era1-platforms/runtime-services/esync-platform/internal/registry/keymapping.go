@@ -0,0 +1,41 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: key-mapping
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Key Mapping Validation
+ */
+
+package registry
+
+import "fmt"
+
+// maxSnowflakeNode is the largest node ID a snowflake ID can hold
+const maxSnowflakeNode = 1023
+
+// validateKeyMapping checks the node ID of snowflake generators and that
+// cleanup pipelines, which compare target keys with source keys, map none
+func (p *Pipeline) validateKeyMapping() error {
+	m := p.KeyMapping
+	if m == nil {
+		return nil
+	}
+
+	switch {
+	case p.Mode == ModeCleanup:
+		return fmt.Errorf("cleanup pipeline %s cannot map keys", p.ID)
+	case m.NodeID < 0 || m.NodeID > maxSnowflakeNode:
+		return fmt.Errorf("pipeline %s has key_mapping.node_id %d outside 0-%d", p.ID, m.NodeID, maxSnowflakeNode)
+	case m.NodeID != 0 && m.Generator != "" && m.Generator != IDGeneratorSnowflake:
+		return fmt.Errorf("pipeline %s sets key_mapping.node_id for the %s generator", p.ID, m.Generator)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: registry-limits
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * YAML Loading Limits
+ */
+
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxFileSize bounds a pipeline, overlay or connection file
+const maxFileSize = 1 << 20
+
+// maxDepth bounds the nesting of maps and sequences in a document
+const maxDepth = 64
+
+// maxNodes bounds the nodes of a document, counting every expansion of an
+// alias so a few anchors cannot expand into millions of values
+const maxNodes = 100000
+
+// ErrTooLarge is returned for YAML documents beyond the loading limits
+var ErrTooLarge = errors.New("YAML document exceeds loading limits")
+
+// readFile reads a file of at most maxFileSize bytes
+func readFile(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if info, err := f.Stat(); err == nil && info.Size() > maxFileSize {
+		return nil, fmt.Errorf("%w: file is %d bytes, the limit is %d", ErrTooLarge, info.Size(), maxFileSize)
+	}
+	// The file may grow after Stat, or report no size at all
+	data, err := io.ReadAll(io.LimitReader(f, maxFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxFileSize {
+		return nil, fmt.Errorf("%w: file is larger than the limit of %d bytes", ErrTooLarge, maxFileSize)
+	}
+	return data, nil
+}
+
+// decodeYAML decodes a document into v, as yaml.Unmarshal does, after
+// checking it against the size, depth and node limits
+func decodeYAML(data []byte, v interface{}) error {
+	if len(data) > maxFileSize {
+		return fmt.Errorf("%w: document is %d bytes, the limit is %d", ErrTooLarge, len(data), maxFileSize)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return err
+	}
+	if root.Kind == 0 {
+		return nil
+	}
+	nodes := 0
+	if err := checkNode(&root, 0, &nodes); err != nil {
+		return err
+	}
+	return root.Decode(v)
+}
+
+// checkNode walks a node, following aliases, and fails once the document
+// nests deeper than maxDepth or holds more than maxNodes nodes. The depth
+// limit also stops aliases that refer to their own ancestors.
+func checkNode(n *yaml.Node, depth int, nodes *int) error {
+	*nodes++
+	if *nodes > maxNodes {
+		return fmt.Errorf("%w: more than %d nodes, counting alias expansions", ErrTooLarge, maxNodes)
+	}
+
+	switch n.Kind {
+	case yaml.AliasNode:
+		if n.Alias != nil {
+			return checkNode(n.Alias, depth, nodes)
+		}
+	case yaml.MappingNode, yaml.SequenceNode:
+		if depth++; depth > maxDepth {
+			return fmt.Errorf("%w: nested deeper than %d levels at line %d", ErrTooLarge, maxDepth, n.Line)
+		}
+	}
+	for _, c := range n.Content {
+		if err := checkNode(c, depth, nodes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
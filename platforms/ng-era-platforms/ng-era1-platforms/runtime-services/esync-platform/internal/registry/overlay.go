@@ -0,0 +1,113 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: pipeline-environment-overlays
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Environment Overlays for Pipeline Definitions
+ */
+
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// overlayDir returns the directory holding patches for an environment.
+// Overlays live in <pipelines>/overlays/<env>/ and patch the base file with the
+// same name.
+func (s *Service) overlayDir(env string) string {
+	return filepath.Join(s.pipelinesDir, "overlays", env)
+}
+
+// checkOverlays rejects overlay files that have no matching base definition
+func (s *Service) checkOverlays(baseFiles []string) error {
+	if s.environment == "" {
+		return nil
+	}
+
+	overlays, err := filepath.Glob(filepath.Join(s.overlayDir(s.environment), "*.yaml"))
+	if err != nil {
+		return fmt.Errorf("failed to scan overlay directory: %w", err)
+	}
+
+	bases := make(map[string]bool, len(baseFiles))
+	for _, file := range baseFiles {
+		bases[filepath.Base(file)] = true
+	}
+	for _, overlay := range overlays {
+		if !bases[filepath.Base(overlay)] {
+			return fmt.Errorf("overlay %s has no base pipeline", overlay)
+		}
+	}
+
+	return nil
+}
+
+// applyOverlay merges the patch of an environment for basePath, if any, into
+// the base document. Maps merge recursively, other values are replaced, and an
+// explicit null removes the key.
+func (s *Service) applyOverlay(env, basePath string, base []byte) ([]byte, error) {
+	if env == "" {
+		return base, nil
+	}
+
+	patch, err := readFile(filepath.Join(s.overlayDir(env), filepath.Base(basePath)))
+	if errors.Is(err, os.ErrNotExist) {
+		return base, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read overlay: %w", err)
+	}
+
+	var baseDoc, patchDoc map[string]interface{}
+	if err := decodeYAML(base, &baseDoc); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	if err := decodeYAML(patch, &patchDoc); err != nil {
+		return nil, fmt.Errorf("failed to parse overlay YAML: %w", err)
+	}
+
+	merged, err := yaml.Marshal(mergePatch(baseDoc, patchDoc))
+	if err != nil {
+		return nil, fmt.Errorf("failed to render overlay: %w", err)
+	}
+
+	return merged, nil
+}
+
+// mergePatch applies patch onto base following JSON merge patch semantics
+func mergePatch(base, patch map[string]interface{}) map[string]interface{} {
+	if base == nil {
+		base = make(map[string]interface{}, len(patch))
+	}
+
+	for key, value := range patch {
+		if value == nil {
+			delete(base, key)
+			continue
+		}
+
+		patchMap, isMap := value.(map[string]interface{})
+		baseMap, baseIsMap := base[key].(map[string]interface{})
+		if isMap && baseIsMap {
+			base[key] = mergePatch(baseMap, patchMap)
+			continue
+		}
+		base[key] = value
+	}
+
+	return base
+}
@@ -0,0 +1,58 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: post-run-actions
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Post-Run Actions
+ */
+
+package registry
+
+import "fmt"
+
+// validatePostRun checks that every post-run action names its job
+func (p *Pipeline) validatePostRun() error {
+	for i, a := range p.PostRun {
+		if err := a.validate(); err != nil {
+			return fmt.Errorf("pipeline %s has an invalid post-run action %d (%s): %w", p.ID, i+1, a.Type, err)
+		}
+	}
+	return nil
+}
+
+// validate checks the settings of one action type
+func (a *PostRunActionSpec) validate() error {
+	if a.MinRecords < 0 || a.Attempts < 0 || a.Timeout < 0 {
+		return fmt.Errorf("min_records, attempts and timeout must not be negative")
+	}
+	switch a.Type {
+	case ActionDBTCloud:
+		if a.AccountID <= 0 || a.JobID <= 0 || a.Token == "" {
+			return fmt.Errorf("account_id, job_id and token are required")
+		}
+	case ActionAirflow:
+		if a.URL == "" || a.DAG == "" {
+			return fmt.Errorf("url and dag are required")
+		}
+	case ActionHTTP:
+		if a.URL == "" {
+			return fmt.Errorf("url is required")
+		}
+		switch a.Method {
+		case "", "POST", "PUT", "GET":
+		default:
+			return fmt.Errorf("method must be POST, PUT or GET")
+		}
+	default:
+		return fmt.Errorf("unknown type: use %s, %s or %s", ActionDBTCloud, ActionAirflow, ActionHTTP)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: composite-keys
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Primary Key Validation
+ */
+
+package registry
+
+import "fmt"
+
+// KeyFields returns the primary key fields of a table, nil when the
+// pipeline names none
+func (s *PrimaryKeySpec) KeyFields(table string) []string {
+	if s == nil {
+		return nil
+	}
+	if fields, exists := s.Tables[table]; exists {
+		return fields
+	}
+	return s.Fields
+}
+
+// validatePrimaryKey checks that primary keys name distinct, non-empty
+// fields and a known collation
+func (p *Pipeline) validatePrimaryKey() error {
+	k := p.PrimaryKey
+	if k == nil {
+		return nil
+	}
+	switch k.Collation {
+	case "", CollationBinary, CollationCaseInsensitive, CollationUnicode:
+	default:
+		return fmt.Errorf("pipeline %s has an invalid primary_key collation %q, expected %s, %s or %s",
+			p.ID, k.Collation, CollationBinary, CollationCaseInsensitive, CollationUnicode)
+	}
+	if len(k.Fields) == 0 && len(k.Tables) == 0 && k.Collation == "" {
+		return fmt.Errorf("pipeline %s sets primary_key without fields", p.ID)
+	}
+
+	if err := validateKeyFields(k.Fields); err != nil {
+		return fmt.Errorf("pipeline %s has an invalid primary_key: %w", p.ID, err)
+	}
+	for table, fields := range k.Tables {
+		if len(fields) == 0 {
+			return fmt.Errorf("pipeline %s has no primary_key fields for table %s", p.ID, table)
+		}
+		if err := validateKeyFields(fields); err != nil {
+			return fmt.Errorf("pipeline %s has an invalid primary_key for table %s: %w", p.ID, table, err)
+		}
+	}
+	return nil
+}
+
+// validateKeyFields rejects empty and repeated key fields
+func validateKeyFields(fields []string) error {
+	seen := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		switch {
+		case field == "":
+			return fmt.Errorf("empty key field")
+		case seen[field]:
+			return fmt.Errorf("key field %s is listed twice", field)
+		}
+		seen[field] = true
+	}
+	return nil
+}
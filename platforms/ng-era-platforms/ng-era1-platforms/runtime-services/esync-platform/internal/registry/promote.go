@@ -0,0 +1,371 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: pipeline-promotion
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Pipeline Cloning and Promotion
+ */
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// fileID matches pipeline IDs and environment names usable as file names
+var fileID = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// CloneOptions sets what a clone changes of the original pipeline
+type CloneOptions struct {
+	// ID is the ID of the clone and names its definition file
+	ID          string
+	Description string
+	// SourceConnection and TargetConnection point the clone at other
+	// connection profiles
+	SourceConnection string
+	TargetConnection string
+	// Labels are set on the clone; an empty value removes the label
+	Labels map[string]string
+}
+
+// Clone copies a pipeline under a new ID into a definition file of its own
+// and registers it. The copy is the definition as loaded, with the overlay
+// of the active environment applied.
+func (s *Service) Clone(id string, opts CloneOptions) (*Pipeline, error) {
+	if !fileID.MatchString(opts.ID) {
+		return nil, fmt.Errorf("invalid pipeline id %q: use letters, digits, dots, dashes and underscores", opts.ID)
+	}
+	if s.pipelinesDir == "" {
+		return nil, fmt.Errorf("cloning needs a pipelines directory")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := s.Snapshot().next()
+	original, err := next.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if existing, exists := next.pipelines[opts.ID]; exists {
+		if existing.File != "" {
+			return nil, fmt.Errorf("%w: %s is already declared in %s", ErrDuplicateID, opts.ID, existing.File)
+		}
+		return nil, fmt.Errorf("%w: %s is already registered", ErrDuplicateID, opts.ID)
+	}
+
+	doc, err := s.document(original)
+	if err != nil {
+		return nil, err
+	}
+	doc["id"] = opts.ID
+	if opts.Description != "" {
+		doc["description"] = opts.Description
+	}
+	for role, connection := range map[string]string{"source": opts.SourceConnection, "target": opts.TargetConnection} {
+		if connection == "" {
+			continue
+		}
+		if _, exists := next.connections[connection]; !exists {
+			return nil, fmt.Errorf("connection %s not found", connection)
+		}
+		spec, _ := doc[role].(map[string]interface{})
+		if spec == nil {
+			spec = make(map[string]interface{})
+		}
+		spec["connection"] = connection
+		doc[role] = spec
+	}
+	if len(opts.Labels) > 0 {
+		labels, _ := doc["labels"].(map[string]interface{})
+		if labels == nil {
+			labels = make(map[string]interface{})
+		}
+		for key, value := range opts.Labels {
+			if value == "" {
+				delete(labels, key)
+			} else {
+				labels[key] = value
+			}
+		}
+		doc["labels"] = labels
+	}
+
+	data, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode clone: %w", err)
+	}
+	path := filepath.Join(s.pipelinesDir, opts.ID+".yaml")
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if errors.Is(err, os.ErrExist) {
+		return nil, fmt.Errorf("%w: %s already exists", ErrDuplicateID, path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		var clone *Pipeline
+		if clone, err = s.loadFromFile(path); err == nil {
+			next.pipelines[clone.ID] = clone
+			s.publish(next)
+			return clone, nil
+		}
+	}
+	os.Remove(path)
+	return nil, fmt.Errorf("failed to clone pipeline %s: %w", id, err)
+}
+
+// document returns the definition of a pipeline as a YAML document: the
+// file with the active overlay applied, or the encoded pipeline for
+// pipelines added in code
+func (s *Service) document(p *Pipeline) (map[string]interface{}, error) {
+	var data []byte
+	var err error
+	if p.File != "" {
+		if data, err = readFile(p.File); err == nil {
+			data, err = s.applyOverlay(s.environment, p.File, data)
+		}
+	} else {
+		data, err = yaml.Marshal(p)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var doc map[string]interface{}
+	if err := decodeYAML(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	if doc == nil {
+		doc = make(map[string]interface{})
+	}
+	return doc, nil
+}
+
+// Promotion is the outcome of promoting a pipeline between environments
+type Promotion struct {
+	PipelineID string `json:"pipeline_id"`
+	From       string `json:"from"`
+	To         string `json:"to"`
+	DryRun     bool   `json:"dry_run"`
+	// Overlay is the overlay file of the target environment written by the
+	// promotion
+	Overlay string `json:"overlay"`
+	// Changed lists the settings whose value changed in the target
+	// environment
+	Changed []string `json:"changed"`
+	// Pipeline is the definition in the target environment after the
+	// promotion
+	Pipeline *Pipeline `json:"pipeline"`
+}
+
+// Promote carries the definition a pipeline has in one environment into
+// another: transforms, schedule and every other setting come from the
+// source environment, while the connectors keep the settings of the target
+// environment, so connections are resolved per environment. An empty from
+// promotes the base definition when the daemon runs without an environment.
+// The result is written as the target environment's overlay of the base
+// file; a dry run only reports it. Promoting into the active environment reloads the
+// registry, and schedule changes take effect on restart.
+func (s *Service) Promote(ctx context.Context, id, from, to string, dryRun bool) (*Promotion, error) {
+	if from == "" {
+		from = s.environment
+	}
+	if to == "" {
+		return nil, fmt.Errorf("target environment is required")
+	}
+	for _, env := range []string{from, to} {
+		if env != "" && !fileID.MatchString(env) {
+			return nil, fmt.Errorf("invalid environment %q", env)
+		}
+	}
+	if from == to {
+		return nil, fmt.Errorf("cannot promote pipeline %s from %s to itself", id, from)
+	}
+
+	reg := s.Snapshot()
+	p, err := reg.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if p.File == "" {
+		return nil, fmt.Errorf("pipeline %s is defined in code and has no overlays to promote", id)
+	}
+
+	base, err := readFile(p.File)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	var docs [3]map[string]interface{}
+	for i, env := range []string{"", from, to} {
+		data, err := s.applyOverlay(env, p.File, base)
+		if err != nil {
+			return nil, err
+		}
+		if err := decodeYAML(data, &docs[i]); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML: %w", err)
+		}
+	}
+	baseDoc, promoted, current := docs[0], docs[1], docs[2]
+	keepConnectors(promoted, current)
+
+	data, err := yaml.Marshal(promoted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode promoted definition: %w", err)
+	}
+	pipeline, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	pipeline.Environment = to
+	pipeline.File = p.File
+	if err := pipeline.validate(); err != nil {
+		return nil, err
+	}
+	for _, spec := range pipeline.connectorSpecs() {
+		if _, err := reg.ResolveConnector(spec); err != nil {
+			return nil, err
+		}
+	}
+	if err := reg.validateConfigs(pipeline); err != nil {
+		return nil, err
+	}
+
+	result := &Promotion{
+		PipelineID: id,
+		From:       from,
+		To:         to,
+		DryRun:     dryRun,
+		Overlay:    filepath.Join(s.pipelinesDir, "overlays", to, filepath.Base(p.File)),
+		Changed:    []string{},
+		Pipeline:   pipeline,
+	}
+	for key := range diffPatch(current, promoted) {
+		result.Changed = append(result.Changed, key)
+	}
+	sort.Strings(result.Changed)
+	if dryRun {
+		return result, nil
+	}
+
+	if err := writeOverlay(result.Overlay, diffPatch(baseDoc, promoted)); err != nil {
+		return nil, err
+	}
+	if to == s.environment {
+		if err := s.LoadAll(ctx); err != nil {
+			return result, fmt.Errorf("promoted pipeline %s, but reloading the registry failed: %w", id, err)
+		}
+	}
+	return result, nil
+}
+
+// keepConnectors replaces the connector settings of a promoted document
+// with those of the target environment, matching routes by table
+func keepConnectors(promoted, current map[string]interface{}) {
+	for _, role := range []string{"source", "target"} {
+		if spec, exists := current[role]; exists {
+			promoted[role] = spec
+		}
+	}
+
+	targets := make(map[interface{}]interface{})
+	currentRoutes, _ := current["routes"].([]interface{})
+	for _, r := range currentRoutes {
+		if route, ok := r.(map[string]interface{}); ok {
+			targets[route["table"]] = route["target"]
+		}
+	}
+	promotedRoutes, _ := promoted["routes"].([]interface{})
+	for _, r := range promotedRoutes {
+		route, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if target, exists := targets[route["table"]]; exists {
+			route["target"] = target
+		}
+	}
+}
+
+// connectorSpecs returns the source, target and route target specs
+func (p *Pipeline) connectorSpecs() []ConnectorSpec {
+	specs := []ConnectorSpec{p.Source, p.Target}
+	for _, route := range p.Routes {
+		specs = append(specs, route.Target)
+	}
+	return specs
+}
+
+// diffPatch returns the merge patch turning base into target, the inverse
+// of mergePatch
+func diffPatch(base, target map[string]interface{}) map[string]interface{} {
+	patch := make(map[string]interface{})
+	for key, value := range target {
+		old, exists := base[key]
+		valueMap, isMap := value.(map[string]interface{})
+		oldMap, oldIsMap := old.(map[string]interface{})
+		switch {
+		case isMap && oldIsMap:
+			if sub := diffPatch(oldMap, valueMap); len(sub) > 0 {
+				patch[key] = sub
+			}
+		case !exists || !reflect.DeepEqual(old, value):
+			patch[key] = value
+		}
+	}
+	for key := range base {
+		if _, exists := target[key]; !exists {
+			patch[key] = nil
+		}
+	}
+	return patch
+}
+
+// writeOverlay replaces an overlay file, removing it when the patch is
+// empty
+func writeOverlay(path string, patch map[string]interface{}) error {
+	if len(patch) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove overlay: %w", err)
+		}
+		return nil
+	}
+
+	data, err := yaml.Marshal(patch)
+	if err != nil {
+		return fmt.Errorf("failed to encode overlay: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create overlay directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write overlay: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write overlay: %w", err)
+	}
+	return nil
+}
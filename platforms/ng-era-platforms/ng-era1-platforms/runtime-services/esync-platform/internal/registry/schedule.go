@@ -0,0 +1,45 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: schedule-timezone
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Schedule Time Zones
+ */
+
+package registry
+
+import (
+	"fmt"
+	"time"
+)
+
+// Location returns the time zone the schedule is evaluated in
+func (s *ScheduleSpec) Location() (*time.Location, error) {
+	if s.Timezone == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q", s.Timezone)
+	}
+	return loc, nil
+}
+
+// validateSchedule checks the time zone of the schedule
+func (p *Pipeline) validateSchedule() error {
+	if p.Schedule == nil {
+		return nil
+	}
+	if _, err := p.Schedule.Location(); err != nil {
+		return fmt.Errorf("pipeline %s has an invalid schedule: %w", p.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: registry-search
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Pipeline Metadata Search
+ */
+
+package registry
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SortKeys lists the keys search results can be sorted by; a key prefixed
+// with - sorts descending. Ties are broken by ID.
+var SortKeys = []string{"id", "priority", "source.type", "target.type", "mode"}
+
+// Query selects, orders and pages pipelines by their metadata. Zero fields
+// match every pipeline.
+type Query struct {
+	// Labels must all be set to the given values; an empty value only
+	// requires the label to be present
+	Labels map[string]string
+	// Selector is a label selector the pipelines must match
+	Selector   Selector
+	SourceType string
+	TargetType string
+	// Text matches pipelines whose ID, description, labels or connector
+	// types contain it, ignoring case
+	Text string
+	// Sort is one of SortKeys, optionally prefixed with -; the default is id
+	Sort   string
+	Offset int
+	// Limit bounds the pipelines returned; zero returns all
+	Limit int
+}
+
+// SearchResult is one page of search results
+type SearchResult struct {
+	Pipelines []*Pipeline
+	// Total counts the matching pipelines across all pages
+	Total int
+}
+
+// searchIndex maps metadata to the IDs of the pipelines carrying it. Every
+// list is sorted by ID.
+type searchIndex struct {
+	ids     []string
+	labels  map[string][]string
+	sources map[string][]string
+	targets map[string][]string
+	text    map[string]string
+}
+
+// Search returns the pipelines of the current snapshot matching q
+func (s *Service) Search(q Query) (*SearchResult, error) {
+	return s.Snapshot().Search(q)
+}
+
+// Search returns the pipelines matching q. Label and connector type terms
+// are answered from an index built once per snapshot; the remaining terms
+// filter the smallest candidate list.
+func (r *Snapshot) Search(q Query) (*SearchResult, error) {
+	less, err := sortFunc(q.Sort)
+	if err != nil {
+		return nil, err
+	}
+	if q.Offset < 0 || q.Limit < 0 {
+		return nil, fmt.Errorf("offset and limit must not be negative")
+	}
+
+	r.indexOnce.Do(r.buildIndex)
+	idx := r.index
+	candidates := idx.ids
+	narrow := func(ids []string) {
+		if len(ids) < len(candidates) {
+			candidates = ids
+		}
+	}
+	for key, value := range q.Labels {
+		narrow(idx.labels[labelTerm(key, value)])
+	}
+	if q.SourceType != "" {
+		narrow(idx.sources[q.SourceType])
+	}
+	if q.TargetType != "" {
+		narrow(idx.targets[q.TargetType])
+	}
+
+	text := strings.ToLower(q.Text)
+	var matches []*Pipeline
+	for _, id := range candidates {
+		p := r.pipelines[id]
+		if !q.matches(p) || !strings.Contains(idx.text[id], text) {
+			continue
+		}
+		matches = append(matches, p)
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return less(matches[i], matches[j]) })
+
+	result := &SearchResult{Total: len(matches), Pipelines: []*Pipeline{}}
+	if q.Offset >= len(matches) {
+		return result, nil
+	}
+	matches = matches[q.Offset:]
+	if q.Limit > 0 && q.Limit < len(matches) {
+		matches = matches[:q.Limit]
+	}
+	result.Pipelines = matches
+	return result, nil
+}
+
+// matches checks the label, selector and connector type terms of q
+func (q Query) matches(p *Pipeline) bool {
+	for key, value := range q.Labels {
+		v, ok := p.Labels[key]
+		if !ok || (value != "" && v != value) {
+			return false
+		}
+	}
+	if q.SourceType != "" && p.Source.Type != q.SourceType {
+		return false
+	}
+	if q.TargetType != "" && p.Target.Type != q.TargetType {
+		return false
+	}
+	return q.Selector.Matches(p.Labels)
+}
+
+// buildIndex indexes the pipelines of the snapshot
+func (r *Snapshot) buildIndex() {
+	idx := &searchIndex{
+		ids:     make([]string, 0, len(r.pipelines)),
+		labels:  make(map[string][]string),
+		sources: make(map[string][]string),
+		targets: make(map[string][]string),
+		text:    make(map[string]string, len(r.pipelines)),
+	}
+	for id := range r.pipelines {
+		idx.ids = append(idx.ids, id)
+	}
+	sort.Strings(idx.ids)
+
+	for _, id := range idx.ids {
+		p := r.pipelines[id]
+		terms := []string{p.ID, p.Description, p.Source.Type, p.Target.Type}
+		for key, value := range p.Labels {
+			idx.labels[labelTerm(key, "")] = append(idx.labels[labelTerm(key, "")], id)
+			idx.labels[labelTerm(key, value)] = append(idx.labels[labelTerm(key, value)], id)
+			terms = append(terms, key, value)
+		}
+		idx.sources[p.Source.Type] = append(idx.sources[p.Source.Type], id)
+		idx.targets[p.Target.Type] = append(idx.targets[p.Target.Type], id)
+		idx.text[id] = strings.ToLower(strings.Join(terms, "\n"))
+	}
+	r.index = idx
+}
+
+// labelTerm is the index key of a label value, or of the label's presence
+// when value is empty
+func labelTerm(key, value string) string {
+	if value == "" {
+		return key
+	}
+	return key + "=" + value
+}
+
+// sortFunc returns the ordering of a sort key
+func sortFunc(key string) (func(a, b *Pipeline) bool, error) {
+	desc := strings.HasPrefix(key, "-")
+	key = strings.TrimPrefix(key, "-")
+
+	var field func(p *Pipeline) string
+	switch key {
+	case "", "id":
+		field = func(p *Pipeline) string { return "" }
+	case "priority":
+		ranks := map[string]string{PriorityCritical: "0", PriorityBulk: "2"}
+		field = func(p *Pipeline) string {
+			if rank, ok := ranks[p.Priority]; ok {
+				return rank
+			}
+			return "1"
+		}
+	case "source.type":
+		field = func(p *Pipeline) string { return p.Source.Type }
+	case "target.type":
+		field = func(p *Pipeline) string { return p.Target.Type }
+	case "mode":
+		field = func(p *Pipeline) string { return p.Mode }
+	default:
+		return nil, fmt.Errorf("unknown sort key %q, expected one of %s", key, strings.Join(SortKeys, ", "))
+	}
+
+	return func(a, b *Pipeline) bool {
+		fa, fb := field(a), field(b)
+		if fa == fb {
+			fa, fb = a.ID, b.ID
+		}
+		if desc {
+			return fa > fb
+		}
+		return fa < fb
+	}, nil
+}
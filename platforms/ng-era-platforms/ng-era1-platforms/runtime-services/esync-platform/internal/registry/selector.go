@@ -0,0 +1,95 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: pipeline-label-selector
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Pipeline Label Selectors
+ */
+
+package registry
+
+import (
+	"fmt"
+	"strings"
+)
+
+// requirement is a single selector term
+type requirement struct {
+	key    string
+	value  string
+	negate bool
+	exists bool
+}
+
+// Selector matches pipelines by label, using the syntax
+// "team=payments,env!=prod,tier" (equality, inequality, existence)
+//
+// Frozen: public as pipeline.Selector
+type Selector []requirement
+
+// ParseSelector parses a comma-separated label selector. An empty string
+// selects every pipeline.
+func ParseSelector(s string) (Selector, error) {
+	var sel Selector
+	for _, term := range strings.Split(s, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+
+		var req requirement
+		switch {
+		case strings.Contains(term, "!="):
+			kv := strings.SplitN(term, "!=", 2)
+			req = requirement{key: kv[0], value: kv[1], negate: true}
+		case strings.Contains(term, "="):
+			kv := strings.SplitN(term, "=", 2)
+			req = requirement{key: kv[0], value: kv[1]}
+		case strings.HasPrefix(term, "!"):
+			req = requirement{key: term[1:], exists: true, negate: true}
+		default:
+			req = requirement{key: term, exists: true}
+		}
+
+		req.key = strings.TrimSpace(req.key)
+		req.value = strings.TrimSpace(req.value)
+		if req.key == "" {
+			return nil, fmt.Errorf("invalid selector term %q", term)
+		}
+		sel = append(sel, req)
+	}
+
+	return sel, nil
+}
+
+// Matches reports whether the labels satisfy every selector term
+func (sel Selector) Matches(labels map[string]string) bool {
+	for _, req := range sel {
+		value, ok := labels[req.key]
+		var match bool
+		if req.exists {
+			match = ok
+		} else {
+			match = ok && value == req.value
+		}
+		if match == req.negate {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Select returns the pipelines of the current snapshot matching the
+// selector, ordered by ID
+func (s *Service) Select(sel Selector) []*Pipeline {
+	return s.Snapshot().Select(sel)
+}
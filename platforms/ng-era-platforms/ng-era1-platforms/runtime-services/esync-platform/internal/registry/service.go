@@ -18,95 +18,456 @@ package registry
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"os"
 	"path/filepath"
 	"sync"
+	"sync/atomic"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/machine-native-ops/esync-platform/internal/logging"
 )
 
 // Pipeline represents a sync pipeline configuration
+//
+// Frozen: public as pipeline.Pipeline
 type Pipeline struct {
-	ID          string                 `yaml:"id"`
-	Version     string                 `yaml:"version"`
-	Description string                 `yaml:"description"`
-	GLMetadata  map[string]interface{} `yaml:",inline"`
+	APIVersion  string            `yaml:"apiVersion" json:"apiVersion"`
+	ID          string            `yaml:"id" json:"id"`
+	Version     string            `yaml:"version" json:"version"`
+	Description string            `yaml:"description" json:"description"`
+	Mode        string            `yaml:"mode" json:"mode,omitempty"`
+	Labels      map[string]string `yaml:"labels" json:"labels,omitempty"`
+	Source      ConnectorSpec     `yaml:"source" json:"source"`
+	Target      ConnectorSpec     `yaml:"target" json:"target"`
+	Schedule    *ScheduleSpec     `yaml:"schedule,omitempty" json:"schedule,omitempty"`
+	ActiveHours *ActiveHoursSpec  `yaml:"active_hours,omitempty" json:"active_hours,omitempty"`
+	Triggers    []TriggerSpec     `yaml:"triggers,omitempty" json:"triggers,omitempty"`
+	RunPolicy   string            `yaml:"run_policy,omitempty" json:"run_policy,omitempty"`
+	Priority    string            `yaml:"priority,omitempty" json:"priority,omitempty"`
+	BatchSize   int               `yaml:"batch_size,omitempty" json:"batch_size,omitempty"`
+	// ApplyWorkers applies independent records in parallel; records sharing
+	// an ordering key or linked by dependencies stay in order
+	ApplyWorkers int `yaml:"apply_workers,omitempty" json:"apply_workers,omitempty"`
+	// Bootstrap lets targets create missing tables, indexes, topics or
+	// indices before the first run of each pipeline version
+	Bootstrap bool `yaml:"bootstrap,omitempty" json:"bootstrap,omitempty"`
+	// Versioning stamps records with vector clocks and drops replays of
+	// versions older than the ones already applied
+	Versioning bool `yaml:"versioning,omitempty" json:"versioning,omitempty"`
+	// DDL captures source schema changes
+	DDL *DDLSpec `yaml:"ddl,omitempty" json:"ddl,omitempty"`
+	// Tables selects the tables of a multi-table source
+	Tables *TableSelectionSpec `yaml:"tables,omitempty" json:"tables,omitempty"`
+	// Routes send individual tables of a multi-table pipeline to their own
+	// targets
+	Routes []RouteSpec `yaml:"routes,omitempty" json:"routes,omitempty"`
+	// Residency restricts the regions data from the source may move to
+	Residency *ResidencySpec `yaml:"residency,omitempty" json:"residency,omitempty"`
+	// Erasure declares that the source carries personal data erasure
+	// requests must reach
+	Erasure *ErasureSpec `yaml:"erasure,omitempty" json:"erasure,omitempty"`
+	// ApplyOrder applies parent tables before child tables
+	ApplyOrder *ApplyOrderSpec `yaml:"apply_order,omitempty" json:"apply_order,omitempty"`
+	Preflight  *PreflightSpec  `yaml:"preflight,omitempty" json:"preflight,omitempty"`
+	Backfill   *BackfillSpec   `yaml:"backfill,omitempty" json:"backfill,omitempty"`
+	Cutover    *CutoverSpec    `yaml:"cutover,omitempty" json:"cutover,omitempty"`
+	Standby    *StandbySpec    `yaml:"standby,omitempty" json:"standby,omitempty"`
+	Cleanup    *CleanupSpec    `yaml:"cleanup,omitempty" json:"cleanup,omitempty"`
+	// PrimaryKey derives record IDs from composite keys
+	PrimaryKey *PrimaryKeySpec `yaml:"primary_key,omitempty" json:"primary_key,omitempty"`
+	// KeyMapping writes records under target IDs of their own
+	KeyMapping *KeyMappingSpec `yaml:"key_mapping,omitempty" json:"key_mapping,omitempty"`
+	Transforms []TransformSpec `yaml:"transforms,omitempty" json:"transforms,omitempty"`
+	Verify     *VerifySpec     `yaml:"verify,omitempty" json:"verify,omitempty"`
+	Canary     *CanarySpec     `yaml:"canary,omitempty" json:"canary,omitempty"`
+	SLO        *SLOSpec        `yaml:"slo,omitempty" json:"slo,omitempty"`
+	Watchdog   *WatchdogSpec   `yaml:"watchdog,omitempty" json:"watchdog,omitempty"`
+	Coercion   *CoercionSpec   `yaml:"coercion,omitempty" json:"coercion,omitempty"`
+	Heartbeat  *HeartbeatSpec  `yaml:"heartbeat,omitempty" json:"heartbeat,omitempty"`
+	// ErrorLog rate-limits the error log lines of the pipeline
+	ErrorLog *ErrorLogSpec `yaml:"error_log,omitempty" json:"error_log,omitempty"`
+	// Outbox reads the source as a transactional outbox table
+	Outbox *OutboxSpec `yaml:"outbox,omitempty" json:"outbox,omitempty"`
+	// FreshnessMarker writes a marker after every successful run for
+	// downstream consumers to gate on
+	FreshnessMarker *FreshnessMarkerSpec `yaml:"freshness_marker,omitempty" json:"freshness_marker,omitempty"`
+	// PostRun triggers downstream jobs after successful runs
+	PostRun []PostRunActionSpec `yaml:"post_run,omitempty" json:"post_run,omitempty"`
+	// CallLimits caps the connector calls of the pipeline's runs, across
+	// its source and targets
+	CallLimits *CallLimits `yaml:"call_limits,omitempty" json:"call_limits,omitempty"`
+	// Bandwidth caps the bytes the pipeline's runs read and write
+	Bandwidth *BandwidthSpec `yaml:"bandwidth,omitempty" json:"bandwidth,omitempty"`
+	// ClockSkew corrects the record timestamps of a source whose clock is
+	// off
+	ClockSkew *ClockSkewSpec `yaml:"clock_skew,omitempty" json:"clock_skew,omitempty"`
+	// RecordLimits bounds the size, field count and nesting depth of
+	// source records
+	RecordLimits *RecordLimitsSpec `yaml:"record_limits,omitempty" json:"record_limits,omitempty"`
+	// SharedSource reads the source once for a group of pipelines
+	SharedSource *SharedSourceSpec `yaml:"shared_source,omitempty" json:"shared_source,omitempty"`
+	// SourceLoad protects the source from the load of reading it
+	SourceLoad *SourceLoadSpec `yaml:"source_load,omitempty" json:"source_load,omitempty"`
+	// Rollout stages changes to the definition over a share of the keys
+	Rollout *RolloutSpec `yaml:"rollout,omitempty" json:"rollout,omitempty"`
+	// Namespace groups the pipelines of a tenant under the daemon's
+	// namespace quotas
+	Namespace string `yaml:"namespace,omitempty" json:"namespace,omitempty"`
+	// Owner is the team or person paged when the pipeline fails; it is
+	// required in the prod environment
+	Owner string `yaml:"owner,omitempty" json:"owner,omitempty"`
+	// Runbook is the http(s) URL of the pipeline's runbook
+	Runbook string `yaml:"runbook,omitempty" json:"runbook,omitempty"`
+	// Tier is the service tier of the pipeline, such as tier-1
+	Tier string `yaml:"tier,omitempty" json:"tier,omitempty"`
+	// Docs is free-text documentation for on-call
+	Docs string `yaml:"docs,omitempty" json:"docs,omitempty"`
+	// MissingFields selects how fields absent from update records are
+	// applied: ignore (default) or null
+	MissingFields string `yaml:"missing_fields,omitempty" json:"missing_fields,omitempty"`
+	Environment   string `yaml:"-" json:"environment,omitempty"`
+	// File is the definition file the pipeline was loaded from
+	File string `yaml:"-" json:"file,omitempty"`
+	// Warnings lists deprecations found while migrating the definition
+	Warnings   []string               `yaml:"-" json:"warnings,omitempty"`
+	GLMetadata map[string]interface{} `yaml:",inline" json:"metadata,omitempty"`
 }
 
+// ErrDuplicateID is returned when two definitions declare the same pipeline ID
+var ErrDuplicateID = errors.New("duplicate pipeline id")
+
 // Service manages pipeline lifecycle
 type Service struct {
-	pipelines     map[string]*Pipeline
-	mu            sync.RWMutex
-	pipelinesDir  string
+	// current is the snapshot readers see; writers hold mu while building
+	// the next one
+	current      atomic.Pointer[Snapshot]
+	mu           sync.Mutex
+	pipelinesDir string
+	environment  string
+	// changed holds the hooks notified of pipeline changes by
+	// subscription
+	changed     []changeHook
+	changeHooks int
+}
+
+// Option configures the registry service
+type Option func(*Service)
+
+// WithEnvironment selects the overlay directory applied on top of base
+// pipeline definitions
+func WithEnvironment(env string) Option {
+	return func(s *Service) {
+		s.environment = env
+	}
 }
 
 // NewService creates a new pipeline registry service
-func NewService(pipelinesDir string) *Service {
-	return &Service{
-		pipelines:     make(map[string]*Pipeline),
-		pipelinesDir:  pipelinesDir,
+func NewService(pipelinesDir string, opts ...Option) *Service {
+	s := &Service{pipelinesDir: pipelinesDir}
+	for _, opt := range opts {
+		opt(s)
 	}
+	s.current.Store(&Snapshot{
+		pipelines:   make(map[string]*Pipeline),
+		connections: make(map[string]*Connection),
+	})
+
+	return s
 }
 
-// LoadAll loads all pipeline definitions from directory
+// LoadAll loads all pipeline definitions from directory. Definitions from
+// files replace those of an earlier load, pipelines added in code are kept,
+// and nothing changes when loading fails.
 func (s *Service) LoadAll(ctx context.Context) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	return s.loadAll()
+}
+
+// loadAll reloads every definition file; the caller holds mu
+func (s *Service) loadAll() error {
 	files, err := filepath.Glob(filepath.Join(s.pipelinesDir, "*.yaml"))
 	if err != nil {
 		return fmt.Errorf("failed to scan pipelines directory: %w", err)
 	}
 
+	if err := s.checkOverlays(files); err != nil {
+		return err
+	}
+	connections, err := s.loadConnections()
+	if err != nil {
+		return err
+	}
+
+	next := s.Snapshot().next()
+	next.connections = connections
+	for id, p := range next.pipelines {
+		if p.File != "" {
+			delete(next.pipelines, id)
+		}
+	}
+	var duplicates []error
 	for _, file := range files {
 		pipeline, err := s.loadFromFile(file)
 		if err != nil {
 			return fmt.Errorf("failed to load pipeline from %s: %w", file, err)
 		}
-		s.pipelines[pipeline.ID] = pipeline
+		if existing, exists := next.pipelines[pipeline.ID]; exists {
+			duplicates = append(duplicates, duplicateError(pipeline.ID, existing.File, file))
+			continue
+		}
+		if err := next.validateConfigs(pipeline); err != nil {
+			return fmt.Errorf("failed to load pipeline from %s: %w", file, err)
+		}
+		next.pipelines[pipeline.ID] = pipeline
+		logging.Debugf(logging.Scope{Module: "registry", Pipeline: pipeline.ID}, "[Registry] Loaded pipeline %s from %s", pipeline.ID, file)
+	}
+	if err := errors.Join(duplicates...); err != nil {
+		return err
 	}
 
+	s.publish(next)
+	logging.Debugf(logging.Scope{Module: "registry"}, "[Registry] Loaded %d pipeline files and %d connection profiles", len(files), len(connections))
 	return nil
 }
 
+// duplicateError reports a pipeline ID declared twice, naming the file of
+// each declaration; pipelines added in code have no file
+func duplicateError(id, first, second string) error {
+	if first == "" {
+		first = "code"
+	}
+	return fmt.Errorf("%w: %s is declared in both %s and %s", ErrDuplicateID, id, first, second)
+}
+
 // loadFromFile loads a single pipeline from file
 func (s *Service) loadFromFile(filepath string) (*Pipeline, error) {
-	data, err := os.ReadFile(filepath)
+	data, err := readFile(filepath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
 
+	data, err = s.applyOverlay(s.environment, filepath, data)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	pipeline.Environment = s.environment
+	pipeline.File = filepath
+	if err := pipeline.validate(); err != nil {
+		return nil, err
+	}
+
+	return pipeline, nil
+}
+
+// Validate checks the settings of a parsed pipeline as loading it does.
+// Connector configs are checked against their schemas only once the
+// pipeline is registered.
+func (p *Pipeline) Validate() error {
+	return p.validate()
+}
+
+// validate checks the settings of a pipeline that loading cannot decode
+// into an invalid state
+func (p *Pipeline) validate() error {
+	if err := p.validateDocs(); err != nil {
+		return err
+	}
+	if p.ActiveHours != nil {
+		if _, err := p.ActiveHours.Calendar(); err != nil {
+			return fmt.Errorf("pipeline %s has invalid active hours: %w", p.ID, err)
+		}
+	}
+	if err := p.validateSchedule(); err != nil {
+		return err
+	}
+	if p.Namespace != "" && !fileID.MatchString(p.Namespace) {
+		return fmt.Errorf("pipeline %s has an invalid namespace %q", p.ID, p.Namespace)
+	}
+	if p.CallLimits != nil {
+		if err := p.CallLimits.validate(); err != nil {
+			return fmt.Errorf("pipeline %s has invalid call limits: %w", p.ID, err)
+		}
+	}
+	if p.Bandwidth != nil {
+		if _, err := p.Bandwidth.Schedule(); err != nil {
+			return fmt.Errorf("pipeline %s has invalid bandwidth: %w", p.ID, err)
+		}
+	}
+	if l := p.SourceLoad; l != nil {
+		if l.MaxRecordsPerSecond < 0 || l.LatencyThreshold < 0 || l.MaxBackoff < 0 {
+			return fmt.Errorf("pipeline %s has an invalid source_load: max_records_per_second, latency_threshold and max_backoff must not be negative", p.ID)
+		}
+		if _, err := l.Calendar(); err != nil {
+			return fmt.Errorf("pipeline %s has invalid source_load windows: %w", p.ID, err)
+		}
+	}
+	if c := p.ClockSkew; c != nil {
+		switch {
+		case c.Threshold < 0:
+			return fmt.Errorf("pipeline %s has an invalid clock skew threshold: must not be negative", p.ID)
+		case c.OnExceeded != "" && c.OnExceeded != ClockSkewReport && c.OnExceeded != ClockSkewCompensate && c.OnExceeded != ClockSkewLogical:
+			return fmt.Errorf("pipeline %s has an invalid clock skew on_exceeded %q: use %s, %s or %s", p.ID, c.OnExceeded, ClockSkewReport, ClockSkewCompensate, ClockSkewLogical)
+		}
+	}
+	if l := p.RecordLimits; l != nil {
+		switch {
+		case l.MaxBytes < 0 || l.MaxFields < 0 || l.MaxDepth < 0:
+			return fmt.Errorf("pipeline %s has invalid record limits: must not be negative", p.ID)
+		case l.OnExceeded != "" && l.OnExceeded != RecordLimitTruncate && l.OnExceeded != RecordLimitDLQ && l.OnExceeded != RecordLimitFail:
+			return fmt.Errorf("pipeline %s has an invalid record limits on_exceeded %q: use %s, %s or %s", p.ID, l.OnExceeded, RecordLimitTruncate, RecordLimitDLQ, RecordLimitFail)
+		}
+	}
+	if s := p.SharedSource; s != nil {
+		switch {
+		case !fileID.MatchString(s.Group):
+			return fmt.Errorf("pipeline %s has an invalid shared source group %q", p.ID, s.Group)
+		case s.MaxAge < 0:
+			return fmt.Errorf("pipeline %s has an invalid shared source max_age: must not be negative", p.ID)
+		}
+	}
+	if l := p.ErrorLog; l != nil && (l.Burst < 0 || l.Window < 0) {
+		return fmt.Errorf("pipeline %s has an invalid error log limit: must not be negative", p.ID)
+	}
+	if r := p.Rollout; r != nil {
+		switch {
+		case r.Percent < 0 || r.Percent > 99:
+			return fmt.Errorf("pipeline %s has an invalid rollout percent %d: use 1 to 99", p.ID, r.Percent)
+		case r.SoakSeconds < 0:
+			return fmt.Errorf("pipeline %s has an invalid rollout soak: must not be negative", p.ID)
+		}
+	}
+	if err := p.validateBackfill(); err != nil {
+		return err
+	}
+	if m := p.FreshnessMarker; m != nil && m.Target != nil && m.Target.Type == "" && m.Target.Connection == "" {
+		return fmt.Errorf("pipeline %s has an invalid freshness marker: target type or connection is required", p.ID)
+	}
+	if err := p.validatePostRun(); err != nil {
+		return err
+	}
+	if o := p.Outbox; o != nil && o.Consume != "" && o.Consume != OutboxDelete && o.Consume != OutboxMark {
+		return fmt.Errorf("pipeline %s has an invalid outbox consume mode %q: use %s or %s", p.ID, o.Consume, OutboxDelete, OutboxMark)
+	}
+	if err := p.validateCleanup(); err != nil {
+		return err
+	}
+	if err := p.validateKeyMapping(); err != nil {
+		return err
+	}
+	if err := p.validatePrimaryKey(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Parse decodes a pipeline definition, migrating older spec versions.
+// Documents beyond the loading limits fail with ErrTooLarge.
+func Parse(data []byte) (*Pipeline, error) {
+	var doc map[string]interface{}
+	if err := decodeYAML(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	if doc == nil {
+		return nil, fmt.Errorf("empty pipeline definition")
+	}
+
+	warnings, err := upgrade(doc)
+	if err != nil {
+		return nil, err
+	}
+	if data, err = yaml.Marshal(doc); err != nil {
+		return nil, fmt.Errorf("failed to encode migrated definition: %w", err)
+	}
+
 	var pipeline Pipeline
 	if err := yaml.Unmarshal(data, &pipeline); err != nil {
 		return nil, fmt.Errorf("failed to parse YAML: %w", err)
 	}
+	pipeline.Warnings = warnings
 
 	return &pipeline, nil
 }
 
-// GetByID returns a pipeline by ID
-func (s *Service) GetByID(id string) (*Pipeline, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+// Add registers a pipeline defined in code rather than in a file
+func (s *Service) Add(p *Pipeline) error {
+	if p.ID == "" {
+		return fmt.Errorf("pipeline id is required")
+	}
 
-	pipeline, exists := s.pipelines[id]
-	if !exists {
-		return nil, fmt.Errorf("pipeline %s not found", id)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := s.Snapshot().next()
+	if existing, exists := next.pipelines[p.ID]; exists {
+		if existing.File != "" {
+			return fmt.Errorf("%w: %s is already declared in %s", ErrDuplicateID, p.ID, existing.File)
+		}
+		return fmt.Errorf("pipeline %s already registered", p.ID)
+	}
+	if p.APIVersion == "" {
+		p.APIVersion = CurrentAPIVersion
+	}
+	if p.Environment == "" {
+		p.Environment = s.environment
+	}
+	if err := p.validate(); err != nil {
+		return err
+	}
+	if err := next.validateConfigs(p); err != nil {
+		return err
 	}
+	next.pipelines[p.ID] = p
+	s.publish(next)
 
-	return pipeline, nil
+	return nil
 }
 
-// GetAll returns all pipelines
-func (s *Service) GetAll() []*Pipeline {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+// Remove deletes the definition file of a pipeline and its overlays and
+// reloads the registry; the files are restored when the reload fails.
+// Pipelines added in code cannot be removed.
+func (s *Service) Remove(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	pipelines := make([]*Pipeline, 0, len(s.pipelines))
-	for _, p := range s.pipelines {
-		pipelines = append(pipelines, p)
+	p, err := s.Snapshot().GetByID(id)
+	if err != nil {
+		return err
 	}
+	if p.File == "" {
+		return fmt.Errorf("pipeline %s is defined in code", id)
+	}
+	removals := []string{p.File}
+	overlays, _ := filepath.Glob(filepath.Join(s.pipelinesDir, "overlays", "*", filepath.Base(p.File)))
+	removals = append(removals, overlays...)
+
+	changes, err := applyFiles(nil, removals)
+	if err == nil {
+		err = s.loadAll()
+	}
+	if err != nil {
+		if rollbackErr := rollbackFiles(changes); rollbackErr != nil {
+			return fmt.Errorf("failed to remove pipeline %s: %w (restoring files failed: %v)", id, err, rollbackErr)
+		}
+		return fmt.Errorf("failed to remove pipeline %s: %w", id, err)
+	}
+	return nil
+}
+
+// GetByID returns a pipeline by ID from the current snapshot
+func (s *Service) GetByID(id string) (*Pipeline, error) {
+	return s.Snapshot().GetByID(id)
+}
 
-	return pipelines
+// GetAll returns all pipelines of the current snapshot
+func (s *Service) GetAll() []*Pipeline {
+	return s.Snapshot().GetAll()
 }
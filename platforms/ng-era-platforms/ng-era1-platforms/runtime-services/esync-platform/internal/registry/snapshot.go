@@ -0,0 +1,250 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: registry-snapshots
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Immutable Registry Snapshots
+ */
+
+package registry
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+)
+
+// Snapshot is an immutable view of the registry. Loads and additions build
+// the next snapshot and swap it in, so a reader holding a snapshot sees the
+// pipelines and connections of one generation however the registry changes
+// meanwhile. Pipelines and connections are shared between snapshots and
+// must not be modified.
+type Snapshot struct {
+	generation  uint64
+	pipelines   map[string]*Pipeline
+	connections map[string]*Connection
+
+	// index is built on the first search
+	indexOnce sync.Once
+	index     *searchIndex
+}
+
+// Snapshot returns the current registry snapshot
+func (s *Service) Snapshot() *Snapshot {
+	return s.current.Load()
+}
+
+// Kinds of pipeline changes
+const (
+	ChangeAdded   = "added"
+	ChangeUpdated = "updated"
+	ChangeRemoved = "removed"
+)
+
+// Change is a pipeline a new snapshot added, updated or removed
+//
+// Frozen: public as pipeline.Change
+type Change struct {
+	Kind       string `json:"kind"`
+	PipelineID string `json:"pipeline_id"`
+	// Pipeline is the definition after the change, nil when removed
+	Pipeline *Pipeline `json:"pipeline,omitempty"`
+	// Generation is the generation of the snapshot that made the change
+	Generation uint64 `json:"generation"`
+}
+
+// changeHook is a subscription to pipeline changes
+type changeHook struct {
+	id int
+	fn func(Change)
+}
+
+// Subscribe registers a hook called with every pipeline a new snapshot
+// adds, updates or removes, ordered by pipeline ID within a snapshot.
+// Definitions a reload reads again unchanged are not reported. Hooks run
+// while the registry is locked and must neither modify it nor cancel
+// their subscription; the returned func cancels it.
+func (s *Service) Subscribe(fn func(Change)) (cancel func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.changeHooks++
+	id := s.changeHooks
+	s.changed = append(s.changed, changeHook{id: id, fn: fn})
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.changed = slices.DeleteFunc(s.changed, func(h changeHook) bool { return h.id == id })
+	}
+}
+
+// OnChange registers a hook called with every pipeline a new snapshot adds
+// or updates, and with a nil pipeline for every one it removes, under the
+// terms of Subscribe
+func (s *Service) OnChange(fn func(id string, p *Pipeline)) {
+	s.Subscribe(func(c Change) { fn(c.PipelineID, c.Pipeline) })
+}
+
+// publish swaps in the next snapshot and reports the pipelines that
+// changed to the subscribed hooks; the caller holds mu
+func (s *Service) publish(next *Snapshot) {
+	prev := s.current.Swap(next)
+	if len(s.changed) == 0 {
+		return
+	}
+
+	var changes []Change
+	for id, p := range next.pipelines {
+		old, existed := prev.pipelines[id]
+		switch {
+		case !existed:
+			changes = append(changes, Change{Kind: ChangeAdded, PipelineID: id, Pipeline: p})
+		case old != p && !sameDefinition(old, p):
+			changes = append(changes, Change{Kind: ChangeUpdated, PipelineID: id, Pipeline: p})
+		}
+	}
+	for id := range prev.pipelines {
+		if _, exists := next.pipelines[id]; !exists {
+			changes = append(changes, Change{Kind: ChangeRemoved, PipelineID: id})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].PipelineID < changes[j].PipelineID })
+	for _, c := range changes {
+		c.Generation = next.generation
+		for _, h := range s.changed {
+			h.fn(c)
+		}
+	}
+}
+
+// sameDefinition reports whether two pipelines encode alike
+func sameDefinition(a, b *Pipeline) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(ja, jb)
+}
+
+// Generation counts the changes made to the registry before this snapshot
+func (r *Snapshot) Generation() uint64 {
+	return r.generation
+}
+
+// GetByID returns a pipeline by ID
+func (r *Snapshot) GetByID(id string) (*Pipeline, error) {
+	pipeline, exists := r.pipelines[id]
+	if !exists {
+		return nil, fmt.Errorf("pipeline %s not found", id)
+	}
+
+	return pipeline, nil
+}
+
+// GetAll returns all pipelines
+func (r *Snapshot) GetAll() []*Pipeline {
+	pipelines := make([]*Pipeline, 0, len(r.pipelines))
+	for _, p := range r.pipelines {
+		pipelines = append(pipelines, p)
+	}
+
+	return pipelines
+}
+
+// Select returns the pipelines matching the selector, ordered by ID
+func (r *Snapshot) Select(sel Selector) []*Pipeline {
+	var pipelines []*Pipeline
+	for _, p := range r.pipelines {
+		if sel.Matches(p.Labels) {
+			pipelines = append(pipelines, p)
+		}
+	}
+	sort.Slice(pipelines, func(i, j int) bool { return pipelines[i].ID < pipelines[j].ID })
+
+	return pipelines
+}
+
+// Connections returns all connection profiles sorted by name
+func (r *Snapshot) Connections() []*Connection {
+	out := make([]*Connection, 0, len(r.connections))
+	for _, c := range r.connections {
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Connection returns a connection profile by name
+func (r *Snapshot) Connection(name string) (*Connection, bool) {
+	c, exists := r.connections[name]
+	return c, exists
+}
+
+// ResolveConnector merges the connection profile a spec references into
+// the spec. Specs without a connection are returned unchanged.
+func (r *Snapshot) ResolveConnector(spec ConnectorSpec) (ConnectorSpec, error) {
+	if spec.Connection == "" {
+		return spec, nil
+	}
+
+	c, exists := r.connections[spec.Connection]
+	if !exists {
+		return spec, fmt.Errorf("connection %s not found", spec.Connection)
+	}
+
+	if spec.Type == "" {
+		spec.Type = c.Type
+	}
+	if spec.Region == "" {
+		spec.Region = c.Region
+	}
+	if spec.Type == "" {
+		return spec, fmt.Errorf("connection %s names no connector type and the spec sets none", c.Name)
+	}
+	spec.Config = mergePatch(c.config(), copyConfig(spec.Config))
+	return spec, nil
+}
+
+// validateConfigs checks the connector config blocks of a pipeline, with
+// their connection profiles merged in, against the config schemas of their
+// types. Specs whose connection is missing are left to fail when used.
+func (r *Snapshot) validateConfigs(p *Pipeline) error {
+	for _, spec := range p.connectorSpecs() {
+		resolved, err := r.ResolveConnector(spec)
+		if err != nil {
+			continue
+		}
+		if err := connectors.ValidateConfig(resolved.Type, resolved.Config); err != nil {
+			return fmt.Errorf("pipeline %s: %w", p.ID, err)
+		}
+	}
+	return nil
+}
+
+// next copies the snapshot as the start of the next generation
+func (r *Snapshot) next() *Snapshot {
+	n := &Snapshot{
+		generation:  r.generation + 1,
+		pipelines:   make(map[string]*Pipeline, len(r.pipelines)),
+		connections: make(map[string]*Connection, len(r.connections)),
+	}
+	for id, p := range r.pipelines {
+		n.pipelines[id] = p
+	}
+	for name, c := range r.connections {
+		n.connections[name] = c
+	}
+	return n
+}
@@ -0,0 +1,756 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: pipeline-spec
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Pipeline Specification Types
+ */
+
+package registry
+
+// Pipeline modes
+const (
+	// ModeSync continuously synchronizes source changes to the target
+	ModeSync = "sync"
+	// ModeMigration moves data once and supports a guided cutover
+	ModeMigration = "migration"
+	// ModeStandby keeps a warm standby target primed and holds its final
+	// cutover until a failover releases the barrier
+	ModeStandby = "standby"
+	// ModeCleanup deletes target records whose source counterparts are gone
+	// or whose age exceeds a TTL instead of syncing changes
+	ModeCleanup = "cleanup"
+)
+
+// Run policies applied when a run is triggered while another is in progress
+const (
+	// RunPolicyCoalesce folds duplicate triggers into a single follow-up run
+	RunPolicyCoalesce = "coalesce"
+	// RunPolicyQueue runs every trigger in order, one at a time
+	RunPolicyQueue = "queue"
+	// RunPolicyReject refuses triggers while a run is in progress
+	RunPolicyReject = "reject"
+)
+
+// ConnectorSpec configures a source or target connector
+//
+// Frozen: public as pipeline.ConnectorSpec
+type ConnectorSpec struct {
+	Type string `yaml:"type" json:"type"`
+	// Connection names a connection profile providing the endpoint,
+	// credentials and, when Type is empty, the connector type
+	Connection string `yaml:"connection,omitempty" json:"connection,omitempty"`
+	// Region is the residency region of the endpoint, overriding the
+	// region of its connection profile
+	Region string                 `yaml:"region,omitempty" json:"region,omitempty"`
+	Config map[string]interface{} `yaml:"config" json:"config,omitempty"`
+}
+
+// CutoverSpec configures the quiesce-and-cutover workflow of a migration pipeline
+//
+// Frozen: public as pipeline.CutoverSpec
+type CutoverSpec struct {
+	// Webhook receives a POST once the target is ready for application cutover
+	Webhook string `yaml:"webhook" json:"webhook,omitempty"`
+	// MaxDrainPasses bounds how many sync passes are attempted while draining
+	MaxDrainPasses int `yaml:"max_drain_passes" json:"max_drain_passes,omitempty"`
+	// SkipReconcile disables the source/target key comparison before signaling
+	SkipReconcile bool `yaml:"skip_reconcile" json:"skip_reconcile,omitempty"`
+}
+
+// StandbySpec configures the failover of a standby pipeline
+//
+// Frozen: public as pipeline.StandbySpec
+type StandbySpec struct {
+	// MaxDrainPasses bounds how many sync passes the final delta may take
+	MaxDrainPasses int `yaml:"max_drain_passes" json:"max_drain_passes,omitempty"`
+}
+
+// CleanupSpec selects what the runs of a cleanup pipeline delete from the
+// target. Each run compares the live key sets of source and target.
+// Deleting runs need the definition confirmed by a second person or a
+// change ticket.
+//
+// Frozen: public as pipeline.CleanupSpec
+type CleanupSpec struct {
+	// Orphans deletes target records whose key the source no longer holds
+	Orphans bool `yaml:"orphans" json:"orphans,omitempty"`
+	// TTL deletes target records older than this many seconds
+	TTL int `yaml:"ttl" json:"ttl,omitempty"`
+	// AgeField is the record field holding the RFC 3339 time or Unix
+	// seconds the TTL counts from; records without it are kept. The
+	// record timestamp is used when empty.
+	AgeField string `yaml:"age_field" json:"age_field,omitempty"`
+	// MaxDeletes fails runs that would delete more records, guarding the
+	// target against a source that lost its data
+	MaxDeletes int `yaml:"max_deletes" json:"max_deletes,omitempty"`
+	// DryRun reports what runs would delete without deleting it
+	DryRun bool `yaml:"dry_run" json:"dry_run,omitempty"`
+	// ChangeTicket is the ID of a pre-approved change ticket confirming
+	// the definition; without one each new definition must be confirmed
+	// through the admin API before runs delete anything
+	ChangeTicket string `yaml:"change_ticket" json:"change_ticket,omitempty"`
+}
+
+// PrimaryKeySpec names the ordered fields forming the primary key of the
+// records of sources that do not report key fields themselves. Record IDs
+// are derived from the values of the fields.
+//
+// Frozen: public as pipeline.PrimaryKeySpec
+type PrimaryKeySpec struct {
+	Fields []string `yaml:"fields" json:"fields,omitempty"`
+	// Tables sets the key fields of individual tables of a multi-table
+	// source, overriding Fields
+	Tables map[string][]string `yaml:"tables" json:"tables,omitempty"`
+	// Collation is how the source compares string keys: binary (default),
+	// case_insensitive or unicode. Key-range chunking and the matching of
+	// records by key follow it, so sources whose keys ignore case get no
+	// overlapping or missed chunks.
+	Collation string `yaml:"collation" json:"collation,omitempty"`
+}
+
+// Key collations
+const (
+	// CollationBinary compares keys byte by byte
+	CollationBinary = "binary"
+	// CollationCaseInsensitive ignores the case of ASCII letters, as the
+	// _ci collations of MySQL and SQL Server do for ASCII keys
+	CollationCaseInsensitive = "case_insensitive"
+	// CollationUnicode ignores the case of all Unicode letters by simple
+	// case folding; it does not normalize composed and decomposed forms
+	CollationUnicode = "unicode"
+)
+
+// ID generators of key mappings; generators registered by embedding
+// services are selected by their name too
+const (
+	// IDGeneratorUUIDv7 creates time-ordered UUIDs
+	IDGeneratorUUIDv7 = "uuidv7"
+	// IDGeneratorSnowflake creates 64-bit time-ordered IDs unique per node
+	IDGeneratorSnowflake = "snowflake"
+	// IDGeneratorTarget lets the target assign the IDs of inserted records
+	IDGeneratorTarget = "target"
+)
+
+// KeyMappingSpec maps source keys to target keys when source and target
+// use different primary keys. The mapping of every record written is kept
+// in the state store, so updates and deletes reach the target record its
+// insert created.
+//
+// Frozen: public as pipeline.KeyMappingSpec
+type KeyMappingSpec struct {
+	// Generator creates the target IDs of new records
+	Generator string `yaml:"generator" json:"generator,omitempty"`
+	// NodeID tells apart the snowflake IDs of daemons writing one target
+	NodeID int `yaml:"node_id" json:"node_id,omitempty"`
+}
+
+// ScheduleSpec configures time-based runs of a pipeline
+//
+// Frozen: public as pipeline.ScheduleSpec
+type ScheduleSpec struct {
+	// Interval runs the pipeline every N seconds
+	Interval int `yaml:"interval" json:"interval,omitempty"`
+	// Cron runs the pipeline on a five-field cron expression
+	Cron string `yaml:"cron" json:"cron,omitempty"`
+	// Timezone is the IANA time zone the cron expression is evaluated in;
+	// the daemon's local time zone by default
+	Timezone string `yaml:"timezone,omitempty" json:"timezone,omitempty"`
+}
+
+// ActiveHoursSpec limits a pipeline to windows of local time, such as
+// 22:00-06:00 for heavy pipelines that should run off-peak. Outside its
+// windows the pipeline is paused; a run in flight when a window closes is
+// allowed to finish.
+//
+// Frozen: public as pipeline.ActiveHoursSpec
+type ActiveHoursSpec struct {
+	// Timezone is an IANA time zone such as Europe/Berlin
+	Timezone string        `yaml:"timezone" json:"timezone,omitempty"`
+	Windows  []HoursWindow `yaml:"windows" json:"windows"`
+}
+
+// HoursWindow is a daily span of local time. A window ending at or before
+// its start crosses midnight and belongs to the day it starts on.
+//
+// Frozen: public as pipeline.HoursWindow
+type HoursWindow struct {
+	// Days limits the window to days of the week (mon, tue, ...); every day
+	// by default
+	Days []string `yaml:"days" json:"days,omitempty"`
+	// Start and End are HH:MM; End may be 24:00
+	Start string `yaml:"start" json:"start"`
+	End   string `yaml:"end" json:"end"`
+}
+
+// Trigger types
+const (
+	TriggerWebhook  = "webhook"
+	TriggerKafka    = "kafka"
+	TriggerPipeline = "pipeline"
+)
+
+// TriggerSpec configures an event that starts a pipeline run
+//
+// Frozen: public as pipeline.TriggerSpec
+type TriggerSpec struct {
+	Type string `yaml:"type" json:"type"`
+
+	// Secret enables HMAC-SHA256 verification of webhook payloads
+	Secret string `yaml:"secret" json:"-"`
+
+	// RestProxy is the Kafka REST proxy URL used to consume Topic
+	RestProxy string `yaml:"rest_proxy" json:"rest_proxy,omitempty"`
+	Topic     string `yaml:"topic" json:"topic,omitempty"`
+	Group     string `yaml:"group" json:"group,omitempty"`
+
+	// Pipeline is the upstream pipeline whose completion triggers this one
+	Pipeline string `yaml:"pipeline" json:"pipeline,omitempty"`
+	// On selects which upstream outcomes trigger: success (default), failure or any
+	On string `yaml:"on" json:"on,omitempty"`
+}
+
+// PreflightSpec tunes the checks run before a pipeline starts
+//
+// Frozen: public as pipeline.PreflightSpec
+type PreflightSpec struct {
+	// MinFreeBytes is the free space required on the state volume
+	MinFreeBytes int64 `yaml:"min_free_bytes" json:"min_free_bytes,omitempty"`
+}
+
+// BackfillSpec configures chunked backfills of a pipeline
+//
+// Frozen: public as pipeline.BackfillSpec
+type BackfillSpec struct {
+	// Chunks is the number of key ranges the source is split into
+	Chunks int `yaml:"chunks" json:"chunks,omitempty"`
+	// Workers is the number of chunks copied in parallel
+	Workers int `yaml:"workers" json:"workers,omitempty"`
+	// Budget paces the backfill to a volume of records per hour or day
+	Budget *BackfillBudget `yaml:"budget,omitempty" json:"budget,omitempty"`
+}
+
+// Periods of a backfill budget
+const (
+	BudgetPerHour = "hour"
+	BudgetPerDay  = "day"
+)
+
+// BackfillBudget caps the records a backfill copies per period. Chunks
+// stop being started once the budget of the current period is spent, so
+// chunks in flight may exceed it.
+//
+// Frozen: public as pipeline.BackfillBudget
+type BackfillBudget struct {
+	// Records is the number of records copied per period
+	Records int `yaml:"records" json:"records"`
+	// Per is the period of the budget: hour or day (UTC)
+	Per string `yaml:"per" json:"per"`
+}
+
+// TransformSpec configures one stage of the record transform chain. Stages
+// run in declaration order; options other than type are passed to the stage.
+//
+// Frozen: public as pipeline.TransformSpec
+type TransformSpec struct {
+	Type    string                 `yaml:"type" json:"type"`
+	Options map[string]interface{} `yaml:",inline" json:"options,omitempty"`
+}
+
+// VerifySpec configures read-back verification of written records
+//
+// Frozen: public as pipeline.VerifySpec
+type VerifySpec struct {
+	// SampleSize is the number of written records read back per run
+	SampleSize int `yaml:"sample_size" json:"sample_size,omitempty"`
+	// MinScore reports a verification error when the share of matching
+	// records falls below it (0-1)
+	MinScore float64 `yaml:"min_score" json:"min_score,omitempty"`
+}
+
+// Pipeline priorities. Automatic triggers of bulk pipelines are shed while
+// the freshness objective of another pipeline is burning.
+const (
+	PriorityCritical = "critical"
+	PriorityNormal   = "normal"
+	PriorityBulk     = "bulk"
+)
+
+// SLOSpec sets the freshness objective of a pipeline and the bounds within
+// which the engine reprioritizes it while the objective is burning
+//
+// Frozen: public as pipeline.SLOSpec
+type SLOSpec struct {
+	// Freshness is how stale the target may get, in seconds since the last
+	// successful run
+	Freshness int `yaml:"freshness" json:"freshness"`
+	// BoostAt boosts the pipeline once staleness reaches this share of the
+	// objective (0-1)
+	BoostAt float64 `yaml:"boost_at" json:"boost_at,omitempty"`
+	// MinInterval is the schedule interval of a boosted pipeline, in
+	// seconds; it never slows down the regular schedule
+	MinInterval int `yaml:"min_interval" json:"min_interval,omitempty"`
+	// MaxApplyWorkers is the apply worker count of a boosted pipeline
+	MaxApplyWorkers int `yaml:"max_apply_workers" json:"max_apply_workers,omitempty"`
+}
+
+// Actions taken by the run watchdog
+const (
+	// WatchdogAlert reports the run and lets it continue
+	WatchdogAlert = "alert"
+	// WatchdogCancel reports and cancels the run
+	WatchdogCancel = "cancel"
+	// WatchdogQuarantine cancels the run and pauses the pipeline until an
+	// operator resumes it
+	WatchdogQuarantine = "cancel_and_quarantine"
+)
+
+// WatchdogSpec bounds how long runs of a pipeline may take
+//
+// Frozen: public as pipeline.WatchdogSpec
+type WatchdogSpec struct {
+	// MaxRunDuration is the time budget of a run, in seconds
+	MaxRunDuration int `yaml:"max_run_duration" json:"max_run_duration,omitempty"`
+	// StallTimeout is how long a run may go without progress, in seconds
+	StallTimeout int `yaml:"stall_timeout" json:"stall_timeout,omitempty"`
+	// Action is taken when a run exceeds either bound
+	Action string `yaml:"action" json:"action,omitempty"`
+}
+
+// CanarySpec sends synthetic canary records through the pipeline and
+// measures how long they take to reach the target
+//
+// Frozen: public as pipeline.CanarySpec
+type CanarySpec struct {
+	// Interval sends a canary every N seconds
+	Interval int `yaml:"interval" json:"interval,omitempty"`
+	// Timeout reports a canary as lost when it has not reached the target
+	// after N seconds
+	Timeout int `yaml:"timeout" json:"timeout,omitempty"`
+	// Table is the table canaries are written to in multi-table pipelines
+	Table string `yaml:"table" json:"table,omitempty"`
+}
+
+// HeartbeatSpec declares that the source emits heartbeat records or
+// advances its position while idle, so a source going silent is stuck
+// rather than idle
+//
+// Frozen: public as pipeline.HeartbeatSpec
+type HeartbeatSpec struct {
+	// Timeout reports the source as stuck after N seconds without a change,
+	// a heartbeat or a new position
+	Timeout int `yaml:"timeout" json:"timeout,omitempty"`
+}
+
+// ErrorLogSpec rate-limits the error log lines of a pipeline; unset fields
+// keep the daemon's limits. Errors beyond the burst are counted and
+// summarized when the window closes.
+//
+// Frozen: public as pipeline.ErrorLogSpec
+type ErrorLogSpec struct {
+	// Burst is how many lines each error type may log per window
+	Burst int `yaml:"burst" json:"burst,omitempty"`
+	// Window is the length of a window, in seconds
+	Window int `yaml:"window" json:"window,omitempty"`
+}
+
+// Clock skew corrections applied to the record timestamps of a source whose
+// clock is off by more than the threshold
+const (
+	// ClockSkewReport only reports the skew
+	ClockSkewReport = "report"
+	// ClockSkewCompensate shifts record timestamps by the measured skew
+	ClockSkewCompensate = "compensate"
+	// ClockSkewLogical replaces record timestamps with the order the
+	// records arrived in, so last-write-wins follows arrival
+	ClockSkewLogical = "logical"
+)
+
+// ClockSkewSpec configures how record timestamps of a source whose clock
+// is off are corrected before conflict resolution and lag reporting
+//
+// Frozen: public as pipeline.ClockSkewSpec
+type ClockSkewSpec struct {
+	// Offset is a known number of seconds the source clock is ahead of the
+	// daemon clock, negative when behind; it is always subtracted from
+	// record timestamps
+	Offset float64 `yaml:"offset" json:"offset,omitempty"`
+	// Threshold is how many seconds the source clock may be off, beyond
+	// Offset, before OnExceeded applies
+	Threshold int `yaml:"threshold" json:"threshold,omitempty"`
+	// OnExceeded is report, compensate (default) or logical
+	OnExceeded string `yaml:"on_exceeded" json:"on_exceeded,omitempty"`
+}
+
+// Actions on records exceeding the record limits of a pipeline
+const (
+	// RecordLimitTruncate cuts records down to the limits and applies them
+	RecordLimitTruncate = "truncate"
+	// RecordLimitDLQ sets records aside in the pipeline's dead-letter queue
+	// and applies the others
+	RecordLimitDLQ = "dlq"
+	// RecordLimitFail fails the run
+	RecordLimitFail = "fail"
+)
+
+// RecordLimitsSpec bounds the records read from the source so one
+// pathological row cannot destabilize the pipeline or its target. Zero
+// leaves a limit off.
+//
+// Frozen: public as pipeline.RecordLimitsSpec
+type RecordLimitsSpec struct {
+	// MaxBytes bounds the size of the record data encoded as JSON
+	MaxBytes int `yaml:"max_bytes" json:"max_bytes,omitempty"`
+	// MaxFields bounds the fields of a record, counting those of nested
+	// objects
+	MaxFields int `yaml:"max_fields" json:"max_fields,omitempty"`
+	// MaxDepth bounds how deep objects and arrays nest; top-level fields
+	// are at depth 1
+	MaxDepth int `yaml:"max_depth" json:"max_depth,omitempty"`
+	// OnExceeded is truncate, dlq (default) or fail
+	OnExceeded string `yaml:"on_exceeded" json:"on_exceeded,omitempty"`
+}
+
+// SharedSourceSpec lets the pipelines of a group read their source once:
+// a pipeline listing changes from the checkpoint another pipeline of the
+// group listed from within MaxAge seconds is served that read, and runs of
+// the others follow a read so they consume it. Only pipelines whose source
+// is the same share reads; each keeps its own checkpoint.
+//
+// Frozen: public as pipeline.SharedSourceSpec
+type SharedSourceSpec struct {
+	// Group names the pipelines sharing reads
+	Group string `yaml:"group" json:"group"`
+	// MaxAge is how many seconds a read is served to the group
+	MaxAge int `yaml:"max_age" json:"max_age,omitempty"`
+}
+
+// SourceLoadSpec keeps reads from degrading a production source, such as
+// the OLTP database behind a CDC pipeline. Reads are spaced to the record
+// rate, limited to read windows, and back off while the source answers
+// slowly. Passes that may not read yet end without reading; the next run
+// picks up from the same checkpoint.
+//
+// Frozen: public as pipeline.SourceLoadSpec
+type SourceLoadSpec struct {
+	// MaxRecordsPerSecond spaces reads so the records listed average at
+	// most this rate; zero means unlimited
+	MaxRecordsPerSecond float64 `yaml:"max_records_per_second" json:"max_records_per_second,omitempty"`
+	// Timezone and Windows limit reads to windows of local time, such as
+	// nights and weekends; reads are allowed at any time without windows
+	Timezone string        `yaml:"timezone,omitempty" json:"timezone,omitempty"`
+	Windows  []HoursWindow `yaml:"windows,omitempty" json:"windows,omitempty"`
+	// LatencyThreshold is how many seconds a read may take before the
+	// source counts as strained: the pause before the next read doubles
+	// with every slow read and halves with every fast one. Zero leaves
+	// backoff off.
+	LatencyThreshold float64 `yaml:"latency_threshold" json:"latency_threshold,omitempty"`
+	// MaxBackoff caps the pause between reads of a strained source, in
+	// seconds
+	MaxBackoff int `yaml:"max_backoff" json:"max_backoff,omitempty"`
+}
+
+// RolloutSpec stages changes to the definition of a pipeline: a changed
+// definition applies to Percent of the key space, chosen by key hash, while
+// the previous one applies to the rest, and is promoted to all keys once
+// it applied without failing for SoakSeconds. A failing pass rolls it back.
+// Changes to the source or target are not staged.
+//
+// Frozen: public as pipeline.RolloutSpec
+type RolloutSpec struct {
+	// Percent is the share of keys the changed definition applies to,
+	// from 1 to 99
+	Percent int `yaml:"percent" json:"percent,omitempty"`
+	// SoakSeconds is how long the changed definition must apply without
+	// failing before it is promoted
+	SoakSeconds int `yaml:"soak_seconds" json:"soak_seconds,omitempty"`
+}
+
+// DefaultFreshnessTable is the table freshness markers are written to
+const DefaultFreshnessTable = "_sync_watermark"
+
+// FreshnessMarkerSpec writes a marker record keyed by pipeline ID after
+// every successful run, carrying the run, its watermark and the source time
+// of the newest change, so downstream consumers such as dbt jobs can gate
+// on the freshness of the synced data
+//
+// Frozen: public as pipeline.FreshnessMarkerSpec
+type FreshnessMarkerSpec struct {
+	// Table is the table or object the marker is written to, by default
+	// _sync_watermark
+	Table string `yaml:"table" json:"table,omitempty"`
+	// Target writes the marker through another connector, such as a topic,
+	// instead of the pipeline target
+	Target *ConnectorSpec `yaml:"target,omitempty" json:"target,omitempty"`
+}
+
+// Post-run action types
+const (
+	// ActionDBTCloud runs a dbt Cloud job
+	ActionDBTCloud = "dbt_cloud"
+	// ActionAirflow triggers a run of an Airflow DAG
+	ActionAirflow = "airflow"
+	// ActionHTTP sends a request to a generic job endpoint
+	ActionHTTP = "http"
+)
+
+// PostRunActionSpec triggers a downstream job once successful runs moved
+// the watermark and applied at least MinRecords records since the action
+// last fired
+//
+// Frozen: public as pipeline.PostRunActionSpec
+type PostRunActionSpec struct {
+	Type string `yaml:"type" json:"type"`
+	// URL is the endpoint of http actions, the Airflow REST API base URL
+	// such as https://airflow.example.com/api/v1, or a dbt Cloud access
+	// URL other than https://cloud.getdbt.com
+	URL string `yaml:"url" json:"url,omitempty"`
+	// Method is the method of http actions, POST by default
+	Method string `yaml:"method" json:"method,omitempty"`
+	// Headers are added to http action requests and may reference secrets
+	Headers map[string]string `yaml:"headers" json:"-"`
+	// Token is the dbt Cloud API token, or a bearer token for Airflow and
+	// http actions; it may reference a secret
+	Token string `yaml:"token" json:"-"`
+	// Username and Password authenticate Airflow requests with basic auth
+	Username string `yaml:"username" json:"username,omitempty"`
+	Password string `yaml:"password" json:"-"`
+	// AccountID and JobID select the dbt Cloud job
+	AccountID int64 `yaml:"account_id" json:"account_id,omitempty"`
+	JobID     int64 `yaml:"job_id" json:"job_id,omitempty"`
+	// DAG is the ID of the Airflow DAG
+	DAG string `yaml:"dag" json:"dag,omitempty"`
+	// MinRecords is the number of records runs must apply before the
+	// action fires, 1 by default
+	MinRecords int `yaml:"min_records" json:"min_records,omitempty"`
+	// Attempts bounds the tries of a failing trigger, 3 by default, with
+	// exponential backoff between them
+	Attempts int `yaml:"attempts" json:"attempts,omitempty"`
+	// Timeout bounds each try, in seconds; 30 by default
+	Timeout int `yaml:"timeout" json:"timeout,omitempty"`
+}
+
+// Outbox consume modes
+const (
+	// OutboxDelete deletes consumed outbox rows
+	OutboxDelete = "delete"
+	// OutboxMark sets the mark field of consumed outbox rows and skips
+	// marked rows
+	OutboxMark = "mark"
+)
+
+// Defaults of outbox pipelines
+const (
+	DefaultOutboxPayloadField = "payload"
+	DefaultOutboxMarkField    = "processed_at"
+)
+
+// OutboxSpec reads the source as a transactional outbox table: every row
+// carries an event payload written in the same transaction as the business
+// change. Runs emit the payloads as records and, once the target applied
+// them, delete or mark the consumed rows through the source connector in
+// one batch, so events are delivered at least once without logical
+// replication.
+//
+// Frozen: public as pipeline.OutboxSpec
+type OutboxSpec struct {
+	// Table is the outbox table of multi-table sources; records of other
+	// tables pass through unchanged. Empty treats every record as a row.
+	Table string `yaml:"table" json:"table,omitempty"`
+	// PayloadField holds the event data as an object or JSON string,
+	// payload by default
+	PayloadField string `yaml:"payload_field" json:"payload_field,omitempty"`
+	// KeyField holds the ID of emitted records, such as aggregate_id; the
+	// outbox row ID is used when empty
+	KeyField string `yaml:"key_field" json:"key_field,omitempty"`
+	// TableField holds the table of emitted records, such as
+	// aggregate_type
+	TableField string `yaml:"table_field" json:"table_field,omitempty"`
+	// OperationField holds the operation of emitted records; they are
+	// inserts when empty
+	OperationField string `yaml:"operation_field" json:"operation_field,omitempty"`
+	// Consume is delete (default) or mark
+	Consume string `yaml:"consume" json:"consume,omitempty"`
+	// MarkField is the field mark sets to the consume time, processed_at
+	// by default
+	MarkField string `yaml:"mark_field" json:"mark_field,omitempty"`
+}
+
+// CallLimits caps the calls connectors make to their endpoints, such as a
+// SaaS API with an account-wide quota
+//
+// Frozen: public as pipeline.CallLimits
+type CallLimits struct {
+	// RatePerSecond is the sustained call rate; zero means unlimited
+	RatePerSecond float64 `yaml:"rate_per_second" json:"rate_per_second,omitempty"`
+	// Burst is how many calls may start at once when the rate allows
+	Burst int `yaml:"burst" json:"burst,omitempty"`
+	// MaxConcurrent caps the calls in flight; zero means unlimited
+	MaxConcurrent int `yaml:"max_concurrent" json:"max_concurrent,omitempty"`
+}
+
+// BandwidthSpec caps the bytes a pipeline or agent moves per second, such
+// as an edge site sharing a thin WAN link with point-of-sale traffic. The
+// first profile whose windows hold the current time sets the cap; outside
+// every profile BytesPerSecond applies.
+//
+// Frozen: public as pipeline.BandwidthSpec
+type BandwidthSpec struct {
+	// BytesPerSecond is the default cap; zero means unlimited
+	BytesPerSecond int64 `yaml:"bytes_per_second" json:"bytes_per_second,omitempty"`
+	// Timezone is the IANA time zone of the profile windows, UTC by
+	// default
+	Timezone string             `yaml:"timezone,omitempty" json:"timezone,omitempty"`
+	Profiles []BandwidthProfile `yaml:"profiles,omitempty" json:"profiles,omitempty"`
+}
+
+// BandwidthProfile is the cap applying during windows of local time, such
+// as a tight cap over store opening hours; zero means unlimited
+//
+// Frozen: public as pipeline.BandwidthProfile
+type BandwidthProfile struct {
+	Windows        []HoursWindow `yaml:"windows" json:"windows"`
+	BytesPerSecond int64         `yaml:"bytes_per_second" json:"bytes_per_second"`
+}
+
+// Coercion modes applied when a record value does not match the target type
+const (
+	// CoercionStrict rejects records with mismatching values
+	CoercionStrict = "strict"
+	// CoercionLenient converts values where possible and reports a warning
+	CoercionLenient = "lenient"
+	// CoercionOff passes values through unchanged
+	CoercionOff = "off"
+)
+
+// CoercionSpec configures how values not matching the target schema are
+// handled. Field types come from the target schema unless overridden.
+//
+// Frozen: public as pipeline.CoercionSpec
+type CoercionSpec struct {
+	Mode   string                   `yaml:"mode" json:"mode,omitempty"`
+	Fields map[string]FieldCoercion `yaml:"fields" json:"fields,omitempty"`
+}
+
+// FieldCoercion overrides the coercion of one field
+//
+// Frozen: public as pipeline.FieldCoercion
+type FieldCoercion struct {
+	// Type is the expected type: string, integer, number, boolean or timestamp
+	Type string `yaml:"type" json:"type,omitempty"`
+	Mode string `yaml:"mode" json:"mode,omitempty"`
+}
+
+// Handling of fields absent from update records
+const (
+	// MissingFieldsIgnore leaves absent fields unchanged at the target
+	MissingFieldsIgnore = "ignore"
+	// MissingFieldsNull sets absent target fields to null
+	MissingFieldsNull = "null"
+)
+
+// ApplyOrderSpec orders applies by table so child rows are never written
+// before their parents. Deletes are applied after inserts and updates, in
+// reverse order.
+//
+// Frozen: public as pipeline.ApplyOrderSpec
+type ApplyOrderSpec struct {
+	// Tables lists tables parents first
+	Tables []string `yaml:"tables" json:"tables,omitempty"`
+	// Infer derives the order from the target's foreign keys when Tables is
+	// empty
+	Infer bool `yaml:"infer" json:"infer,omitempty"`
+}
+
+// Policies for tables that appear in a multi-table source after the
+// pipeline first ran
+const (
+	NewTablesInclude = "include"
+	NewTablesIgnore  = "ignore"
+	NewTablesAlert   = "alert"
+)
+
+// TableSelectionSpec selects the tables of a multi-table source. Patterns
+// are globs ("orders_*"), or regular expressions when wrapped in slashes
+// ("/^orders_[0-9]+$/"). Exclude wins over include; an empty include list
+// selects every table.
+//
+// Frozen: public as pipeline.TableSelectionSpec
+type TableSelectionSpec struct {
+	Include []string `yaml:"include" json:"include,omitempty"`
+	Exclude []string `yaml:"exclude" json:"exclude,omitempty"`
+	// NewTables decides what happens to selected tables first seen after
+	// the pipeline's first pass: include syncs them, ignore skips them and
+	// alert skips them and reports them. Tables named literally in include
+	// or routes are always synced.
+	NewTables string `yaml:"new_tables" json:"new_tables,omitempty"`
+}
+
+// Policies for schema changes captured from CDC sources
+const (
+	DDLIgnore    = "ignore"
+	DDLPropagate = "propagate"
+	DDLApprove   = "approve"
+)
+
+// DDLSpec controls how captured source schema changes reach the target:
+// ignore only records them, propagate replays them on the target before
+// the data that follows, and approve pauses the pipeline until an operator
+// approves or rejects each change
+//
+// Frozen: public as pipeline.DDLSpec
+type DDLSpec struct {
+	Policy string `yaml:"policy" json:"policy"`
+}
+
+// Residency policies applied when a pipeline would move data out of the
+// region of its source
+const (
+	ResidencyEnforce = "enforce"
+	ResidencyWarn    = "warn"
+)
+
+// ResidencySpec controls where data from a region-tagged source may go.
+// Targets must be in the source region or in Allow; enforce refuses runs
+// that would break this, warn only logs and audits them.
+//
+// Frozen: public as pipeline.ResidencySpec
+type ResidencySpec struct {
+	Policy string   `yaml:"policy,omitempty" json:"policy,omitempty"`
+	Allow  []string `yaml:"allow,omitempty" json:"allow,omitempty"`
+}
+
+// Erasure actions applied to the records of a data subject
+const (
+	ErasureDelete = "delete"
+	ErasurePatch  = "patch"
+)
+
+// ErasureSpec tells erasure requests how to find a data subject's records
+// and remove them from the targets. SubjectField is the source field holding
+// the subject key, or empty when the record ID is the key. Delete removes the
+// records; patch keeps them with Fields set to null.
+//
+// Frozen: public as pipeline.ErasureSpec
+type ErasureSpec struct {
+	SubjectField string   `yaml:"subject_field,omitempty" json:"subject_field,omitempty"`
+	Action       string   `yaml:"action,omitempty" json:"action,omitempty"`
+	Fields       []string `yaml:"fields,omitempty" json:"fields,omitempty"`
+}
+
+// RouteSpec sends the records of one table of a multi-table pipeline to
+// its own target; other tables go to the pipeline target
+//
+// Frozen: public as pipeline.RouteSpec
+type RouteSpec struct {
+	Table  string        `yaml:"table" json:"table"`
+	Target ConnectorSpec `yaml:"target" json:"target"`
+}
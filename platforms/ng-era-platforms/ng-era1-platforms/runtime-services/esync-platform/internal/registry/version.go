@@ -0,0 +1,170 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: pipeline-spec-versions
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Pipeline Spec Versions and Migration
+ */
+
+package registry
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Pipeline spec versions
+const (
+	// APIVersionV1Alpha1 is the original format with the definition nested
+	// under a pipeline key and a list of sources
+	APIVersionV1Alpha1 = "esync.machops.io/v1alpha1"
+	// APIVersionV1 is the current flat format
+	APIVersionV1 = "esync.machops.io/v1"
+
+	CurrentAPIVersion = APIVersionV1
+)
+
+// migration upgrades a decoded document by one spec version, returning
+// deprecation warnings for anything it had to drop or reinterpret
+type migration struct {
+	from, to string
+	migrate  func(doc map[string]interface{}) []string
+}
+
+var migrations = []migration{
+	{from: APIVersionV1Alpha1, to: APIVersionV1, migrate: migrateV1Alpha1},
+}
+
+// deprecatedFields lists settings of the current version scheduled for
+// removal, keyed by dotted path
+var deprecatedFields = map[string]string{
+	"schedule.realtime": "ignored; declare a kafka or webhook trigger for event-driven runs",
+}
+
+// upgrade migrates a decoded document to the current spec version in place
+func upgrade(doc map[string]interface{}) ([]string, error) {
+	var warnings []string
+
+	version, _ := doc["apiVersion"].(string)
+	switch {
+	case version != "":
+	case doc["pipeline"] != nil:
+		version = APIVersionV1Alpha1
+	default:
+		version = CurrentAPIVersion
+		warnings = append(warnings, fmt.Sprintf("apiVersion not set; assuming %s", CurrentAPIVersion))
+	}
+
+	for _, m := range migrations {
+		if m.from != version {
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf("apiVersion %s is deprecated; migrated to %s", m.from, m.to))
+		warnings = append(warnings, m.migrate(doc)...)
+		version = m.to
+	}
+	if version != CurrentAPIVersion {
+		return nil, fmt.Errorf("unsupported apiVersion %q", version)
+	}
+	doc["apiVersion"] = version
+
+	paths := make([]string, 0, len(deprecatedFields))
+	for path := range deprecatedFields {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		if lookupPath(doc, path) {
+			warnings = append(warnings, fmt.Sprintf("%s is deprecated: %s", path, deprecatedFields[path]))
+		}
+	}
+
+	return warnings, nil
+}
+
+// migrateV1Alpha1 flattens the nested v1alpha1 layout
+func migrateV1Alpha1(doc map[string]interface{}) []string {
+	var warnings []string
+
+	if nested, ok := doc["pipeline"].(map[string]interface{}); ok {
+		for k, v := range nested {
+			if _, exists := doc[k]; !exists {
+				doc[k] = v
+			}
+		}
+		delete(doc, "pipeline")
+	}
+
+	if sources, ok := doc["sources"].([]interface{}); ok {
+		if len(sources) > 0 {
+			if first, ok := sources[0].(map[string]interface{}); ok {
+				delete(first, "id")
+				doc["source"] = first
+			}
+		}
+		if len(sources) > 1 {
+			warnings = append(warnings, fmt.Sprintf("sources: only the first of %d sources is synced; split the others into separate pipelines", len(sources)))
+		}
+		delete(doc, "sources")
+	}
+
+	if tags, ok := doc["tags"].(map[string]interface{}); ok {
+		labels, _ := doc["labels"].(map[string]interface{})
+		if labels == nil {
+			labels = make(map[string]interface{})
+		}
+		for k, v := range tags {
+			if _, exists := labels[k]; !exists {
+				labels[k] = fmt.Sprint(v)
+			}
+		}
+		doc["labels"] = labels
+		delete(doc, "tags")
+		warnings = append(warnings, "tags is deprecated; merged into labels")
+	}
+
+	if strategy, ok := doc["strategy"].(map[string]interface{}); ok {
+		if mode, _ := strategy["mode"].(string); mode == "full" {
+			doc["mode"] = ModeMigration
+		} else if _, exists := doc["mode"]; !exists {
+			doc["mode"] = ModeSync
+		}
+		if strategy["cursor_field"] != nil {
+			warnings = append(warnings, "strategy.cursor_field is no longer supported; sources track their own position")
+		}
+		delete(doc, "strategy")
+	}
+
+	for _, key := range []string{"validation", "monitoring"} {
+		if _, exists := doc[key]; exists {
+			delete(doc, key)
+			warnings = append(warnings, fmt.Sprintf("%s is no longer supported and was dropped", key))
+		}
+	}
+
+	return warnings
+}
+
+// lookupPath reports whether a dotted path is set in a decoded document
+func lookupPath(doc map[string]interface{}, path string) bool {
+	var cur interface{} = doc
+	for _, key := range strings.Split(path, ".") {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if cur, ok = m[key]; !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,215 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: replay-harness
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Deterministic Replay Harness
+ */
+
+// Package replay replays fixtures recorded from pipeline runs through the
+// engine against an in-memory target, so tests can assert that transform
+// and conflict policy changes keep producing the recorded output:
+//
+//	f, err := replay.Load("testdata/orders.json")
+//	...
+//	diff, err := replay.Check(ctx, f)
+//	if len(diff) > 0 {
+//		t.Errorf("replay diverged:\n%s", strings.Join(diff, "\n"))
+//	}
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/machine-native-ops/esync-platform/internal/conflict"
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/engine"
+	"github.com/machine-native-ops/esync-platform/internal/monitoring"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+	"github.com/machine-native-ops/esync-platform/internal/secrets"
+	"github.com/machine-native-ops/esync-platform/internal/state"
+)
+
+// Result is the outcome of replaying a fixture
+type Result struct {
+	// Written is the number of records that passed transforms and
+	// validation
+	Written int `json:"written"`
+	// Applied holds the records written to the target, in apply order
+	Applied []connectors.Record `json:"applied"`
+	// Records holds the target contents after the replay, ordered by ID
+	Records []connectors.Record `json:"records"`
+	// Outcomes counts the conflicts the target resolved by outcome
+	Outcomes map[conflict.Outcome]int `json:"outcomes,omitempty"`
+	// Errors groups the errors recorded while replaying, such as coercion
+	// failures and unpatchable records
+	Errors []engine.ErrorGroup `json:"errors,omitempty"`
+	// Error is the error the replay failed with
+	Error string `json:"error,omitempty"`
+}
+
+// Load reads a fixture file
+func Load(path string) (*engine.Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+
+	var f engine.Fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+	}
+	if f.Format > engine.FixtureFormat {
+		return nil, fmt.Errorf("fixture %s has format %d, newer than %d", path, f.Format, engine.FixtureFormat)
+	}
+	if f.Pipeline == nil {
+		return nil, fmt.Errorf("fixture %s has no pipeline definition", path)
+	}
+	return &f, nil
+}
+
+// Save writes a fixture file
+func Save(path string, f *engine.Fixture) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode fixture: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write fixture: %w", err)
+	}
+	return nil
+}
+
+// Run replays the input of a fixture through pipeline p, or the recorded
+// pipeline when p is nil, against a target seeded with the fixture's
+// existing records. The engine runs on a temporary state directory removed
+// afterwards. A failing replay is reported in the result, not as an error.
+func Run(ctx context.Context, f *engine.Fixture, p *registry.Pipeline) (*Result, error) {
+	if p == nil {
+		p = f.Pipeline
+	}
+	input, err := clone(f.Input)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := clone(f.Existing)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "esync-replay-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create replay state: %w", err)
+	}
+	defer os.RemoveAll(dir)
+	store, err := state.NewStore(dir)
+	if err != nil {
+		return nil, err
+	}
+	eng := engine.New(registry.NewService(dir), store, monitoring.NewMonitor(), secrets.NewResolver(dir))
+
+	target := NewTarget(existing, f.Schema)
+	written, err := eng.Replay(ctx, p, target, input)
+	result := &Result{
+		Written:  written,
+		Applied:  target.Applied(),
+		Records:  target.Records(),
+		Outcomes: target.Outcomes(),
+		Errors:   eng.ErrorGroups(p.ID),
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result, nil
+}
+
+// Check replays a fixture through its recorded pipeline and compares what
+// was applied with the recorded output
+func Check(ctx context.Context, f *engine.Fixture) ([]string, error) {
+	result, err := Run(ctx, f, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := Diff(f.Output, result.Applied)
+	if result.Error != f.Error {
+		diff = append(diff, fmt.Sprintf("error: want %q, got %q", f.Error, result.Error))
+	}
+	return diff, nil
+}
+
+// Diff compares the records applied for each ID, in order, describing
+// every difference. Records of different IDs may interleave differently, as
+// runs with several apply workers do not order them.
+func Diff(want, got []connectors.Record) []string {
+	wantByID, ids := byID(want, nil)
+	gotByID, ids := byID(got, ids)
+
+	var diff []string
+	for _, id := range ids {
+		w, g := wantByID[id], gotByID[id]
+		for i := 0; i < len(w) || i < len(g); i++ {
+			switch {
+			case i >= len(g):
+				diff = append(diff, fmt.Sprintf("record %s #%d: missing, want %s", id, i+1, w[i]))
+			case i >= len(w):
+				diff = append(diff, fmt.Sprintf("record %s #%d: unexpected %s", id, i+1, g[i]))
+			case w[i] != g[i]:
+				diff = append(diff, fmt.Sprintf("record %s #%d: want %s, got %s", id, i+1, w[i], g[i]))
+			}
+		}
+	}
+	return diff
+}
+
+// byID encodes records grouped by ID, adding IDs not yet in ids in order of
+// first appearance
+func byID(records []connectors.Record, ids []string) (map[string][]string, []string) {
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		seen[id] = true
+	}
+
+	out := make(map[string][]string)
+	for _, r := range records {
+		data, err := json.Marshal(r)
+		if err != nil {
+			data = []byte(fmt.Sprintf("%+v", r))
+		}
+		out[r.ID] = append(out[r.ID], string(data))
+		if !seen[r.ID] {
+			seen[r.ID] = true
+			ids = append(ids, r.ID)
+		}
+	}
+	return out, ids
+}
+
+// clone deep-copies records through their JSON encoding, so transforms
+// cannot change the fixture and values compare as recorded
+func clone(records []connectors.Record) ([]connectors.Record, error) {
+	if records == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(records)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy fixture records: %w", err)
+	}
+	var out []connectors.Record
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("failed to copy fixture records: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,173 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: pipeline-simulation
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Pipeline Simulation
+ */
+
+package replay
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/conflict"
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/engine"
+	"github.com/machine-native-ops/esync-platform/internal/monitoring"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+	"github.com/machine-native-ops/esync-platform/internal/secrets"
+	"github.com/machine-native-ops/esync-platform/internal/state"
+)
+
+// Simulation reports what a pipeline would have written for a series of
+// recorded runs
+type Simulation struct {
+	PipelineID string `json:"pipeline_id"`
+	// Speed is how many times faster than recorded the runs were replayed;
+	// zero replays them back to back
+	Speed   float64          `json:"speed"`
+	Batches []SimulatedBatch `json:"batches"`
+	Input   int              `json:"input"`
+	Written int              `json:"written"`
+	// Diverged counts the batches whose writes or error differ from the
+	// recorded ones
+	Diverged int `json:"diverged"`
+	// Records holds the target contents after the last batch, ordered by ID
+	Records  []connectors.Record      `json:"records"`
+	Outcomes map[conflict.Outcome]int `json:"outcomes,omitempty"`
+	// Errors groups the errors recorded over all batches
+	Errors          []engine.ErrorGroup `json:"errors,omitempty"`
+	StartedAt       time.Time           `json:"started_at"`
+	DurationSeconds float64             `json:"duration_seconds"`
+}
+
+// SimulatedBatch is the replay of one recorded run
+type SimulatedBatch struct {
+	RunID      string    `json:"run_id"`
+	RecordedAt time.Time `json:"recorded_at"`
+	Input      int       `json:"input"`
+	Written    int       `json:"written"`
+	// Applied holds the records the batch wrote to the target, in apply
+	// order
+	Applied []connectors.Record `json:"applied"`
+	// Diff describes how the writes differ from the recorded ones
+	Diff  []string `json:"diff,omitempty"`
+	Error string   `json:"error,omitempty"`
+}
+
+// Simulate replays the input of fixtures recorded from a pipeline through
+// p, or the pipeline recorded by the first fixture when p is nil, against
+// one in-memory target, in order of recording. The target starts with the
+// existing records of the fixtures and keeps what earlier batches wrote, so
+// the batches play out as the recorded runs did. With speed above zero the
+// time between recordings is replayed that many times faster. A failing
+// batch is reported and the next one replayed.
+func Simulate(ctx context.Context, fixtures []*engine.Fixture, p *registry.Pipeline, speed float64) (*Simulation, error) {
+	if len(fixtures) == 0 {
+		return nil, fmt.Errorf("no fixtures to simulate")
+	}
+	if speed < 0 {
+		return nil, fmt.Errorf("speed must not be negative")
+	}
+	fixtures = append([]*engine.Fixture{}, fixtures...)
+	sort.SliceStable(fixtures, func(i, j int) bool { return fixtures[i].RecordedAt.Before(fixtures[j].RecordedAt) })
+	if p == nil {
+		p = fixtures[0].Pipeline
+	}
+	if p == nil {
+		return nil, fmt.Errorf("fixtures have no pipeline definition")
+	}
+	for _, f := range fixtures {
+		if f.Format > engine.FixtureFormat {
+			return nil, fmt.Errorf("fixture of run %s has format %d, newer than %d", f.RunID, f.Format, engine.FixtureFormat)
+		}
+	}
+
+	dir, err := os.MkdirTemp("", "esync-simulate-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create simulation state: %w", err)
+	}
+	defer os.RemoveAll(dir)
+	store, err := state.NewStore(dir)
+	if err != nil {
+		return nil, err
+	}
+	eng := engine.New(registry.NewService(dir), store, monitoring.NewMonitor(), secrets.NewResolver(dir))
+
+	sim := &Simulation{PipelineID: p.ID, Speed: speed, Batches: []SimulatedBatch{}, StartedAt: time.Now().UTC()}
+	target := NewTarget(nil, fixtures[0].Schema)
+	for i, f := range fixtures {
+		if i > 0 && speed > 0 {
+			if err := wait(ctx, f.RecordedAt.Sub(fixtures[i-1].RecordedAt), speed); err != nil {
+				return nil, err
+			}
+		}
+		input, err := clone(f.Input)
+		if err != nil {
+			return nil, err
+		}
+		existing, err := clone(f.Existing)
+		if err != nil {
+			return nil, err
+		}
+		target.Seed(existing)
+
+		applied := len(target.Applied())
+		written, err := eng.Replay(ctx, p, target, input)
+		batch := SimulatedBatch{
+			RunID:      f.RunID,
+			RecordedAt: f.RecordedAt,
+			Input:      len(input),
+			Written:    written,
+			Applied:    target.Applied()[applied:],
+		}
+		if err != nil {
+			batch.Error = err.Error()
+		}
+		batch.Diff = Diff(f.Output, batch.Applied)
+		if batch.Error != f.Error {
+			batch.Diff = append(batch.Diff, fmt.Sprintf("error: want %q, got %q", f.Error, batch.Error))
+		}
+		if len(batch.Diff) > 0 {
+			sim.Diverged++
+		}
+		sim.Input += batch.Input
+		sim.Written += batch.Written
+		sim.Batches = append(sim.Batches, batch)
+	}
+
+	sim.Records = target.Records()
+	sim.Outcomes = target.Outcomes()
+	sim.Errors = eng.ErrorGroups(p.ID)
+	sim.DurationSeconds = time.Since(sim.StartedAt).Seconds()
+	return sim, nil
+}
+
+// wait sleeps for gap divided by speed
+func wait(ctx context.Context, gap time.Duration, speed float64) error {
+	d := time.Duration(float64(gap) / speed)
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,189 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: replay-target
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * In-Memory Replay Target
+ */
+
+package replay
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/machine-native-ops/esync-platform/internal/conflict"
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+)
+
+// Target is an in-memory target connector. It resolves conflicts with the
+// causality-aware last-write-wins resolver, as plugin targets without
+// their own resolution do, and accepts every record as valid.
+type Target struct {
+	mu       sync.Mutex
+	resolver *conflict.Resolver
+	records  map[connectors.Key]connectors.Record
+	schema   *connectors.Schema
+	applied  []connectors.Record
+	outcomes map[conflict.Outcome]int
+}
+
+// NewTarget creates a target holding existing records and reporting schema
+// when non-nil
+func NewTarget(existing []connectors.Record, schema *connectors.Schema) *Target {
+	t := &Target{
+		resolver: conflict.NewResolver(),
+		records:  make(map[connectors.Key]connectors.Record, len(existing)),
+		schema:   schema,
+		outcomes: make(map[conflict.Outcome]int),
+	}
+	for _, r := range existing {
+		t.records[r.Key()] = r
+	}
+	return t
+}
+
+// Seed adds existing records whose keys the target does not hold yet, such
+// as the target versions recorded by a later fixture
+func (t *Target) Seed(existing []connectors.Record) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, r := range existing {
+		if _, exists := t.records[r.Key()]; !exists {
+			t.records[r.Key()] = r
+		}
+	}
+}
+
+// ListChanges implements connectors.Connector; the target has no changes
+func (t *Target) ListChanges(ctx context.Context, checkpoint *connectors.Checkpoint) ([]connectors.Record, error) {
+	return nil, nil
+}
+
+// ApplyChanges stores records, resolving each against the stored version.
+// Records with composite keys get their key field values in their data, as
+// plugin targets receive them.
+func (t *Target) ApplyChanges(ctx context.Context, changes []connectors.Record) error {
+	changes, err := connectors.WithKeyData(changes)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, r := range changes {
+		t.applied = append(t.applied, r)
+		if r.Operation == connectors.OperationDelete {
+			delete(t.records, r.Key())
+			continue
+		}
+		existing, exists := t.records[r.Key()]
+		if !exists {
+			t.records[r.Key()] = r
+			continue
+		}
+		winner, outcome := t.resolver.Resolve(existing, r)
+		t.outcomes[outcome]++
+		t.records[r.Key()] = winner
+	}
+	return nil
+}
+
+// Validate implements connectors.Connector, accepting every record
+func (t *Target) Validate(ctx context.Context, record connectors.Record) connectors.ValidationResult {
+	return connectors.ValidationResult{IsValid: true}
+}
+
+// ResolveConflict implements connectors.Connector
+func (t *Target) ResolveConflict(ctx context.Context, existing, incoming connectors.Record) (connectors.Record, error) {
+	winner, _ := t.resolver.Resolve(existing, incoming)
+	return winner, nil
+}
+
+// GetLatestCheckpoint implements connectors.Connector
+func (t *Target) GetLatestCheckpoint(ctx context.Context) (*connectors.Checkpoint, error) {
+	return nil, nil
+}
+
+// ReadRecords implements connectors.RecordReader, returning the records
+// of every table with the given IDs
+func (t *Target) ReadRecords(ctx context.Context, ids []string) ([]connectors.Record, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	var out []connectors.Record
+	for key, r := range t.records {
+		if wanted[key.ID] {
+			out = append(out, r)
+		}
+	}
+	sortRecords(out)
+	return out, nil
+}
+
+// Schema implements connectors.SchemaProvider, failing when the target was
+// created without a schema so the engine treats it as unreported
+func (t *Target) Schema(ctx context.Context) (*connectors.Schema, error) {
+	if t.schema == nil {
+		return nil, connectors.ErrUnsupported
+	}
+	return t.schema, nil
+}
+
+// Applied returns the records written to the target, in apply order
+func (t *Target) Applied() []connectors.Record {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]connectors.Record{}, t.applied...)
+}
+
+// Records returns the stored records, ordered by ID and table
+func (t *Target) Records() []connectors.Record {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	out := make([]connectors.Record, 0, len(t.records))
+	for _, r := range t.records {
+		out = append(out, r)
+	}
+	sortRecords(out)
+	return out
+}
+
+// sortRecords orders records by ID and table
+func sortRecords(records []connectors.Record) {
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].ID != records[j].ID {
+			return records[i].ID < records[j].ID
+		}
+		return records[i].Table < records[j].Table
+	})
+}
+
+// Outcomes counts the conflicts resolved by outcome
+func (t *Target) Outcomes() map[conflict.Outcome]int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	out := make(map[conflict.Outcome]int, len(t.outcomes))
+	for outcome, n := range t.outcomes {
+		out[outcome] = n
+	}
+	return out
+}
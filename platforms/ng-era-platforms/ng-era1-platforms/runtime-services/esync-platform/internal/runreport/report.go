@@ -0,0 +1,237 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: run-reports
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Structured Run Reports
+ */
+
+// Package runreport archives a JSON report and a Markdown summary of every
+// finished run, for teams that keep job evidence
+package runreport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/egress"
+	"github.com/machine-native-ops/esync-platform/internal/engine"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// Report is the machine-readable record of one run
+type Report struct {
+	PipelineID      string            `json:"pipeline_id"`
+	PipelineVersion string            `json:"pipeline_version,omitempty"`
+	Environment     string            `json:"environment,omitempty"`
+	Labels          map[string]string `json:"labels,omitempty"`
+	Run             *engine.Run       `json:"run"`
+	DurationSeconds float64           `json:"duration_seconds"`
+	GeneratedAt     time.Time         `json:"generated_at"`
+}
+
+// NewReport builds the report of a finished run
+func NewReport(p *registry.Pipeline, run *engine.Run) *Report {
+	return &Report{
+		PipelineID:      p.ID,
+		PipelineVersion: p.Version,
+		Environment:     p.Environment,
+		Labels:          p.Labels,
+		Run:             run,
+		DurationSeconds: run.FinishedAt.Sub(run.StartedAt).Seconds(),
+		GeneratedAt:     time.Now().UTC(),
+	}
+}
+
+// Markdown renders the human-readable summary of the report
+func (r *Report) Markdown() string {
+	var b strings.Builder
+	run := r.Run
+
+	fmt.Fprintf(&b, "# Run %s\n\n", run.ID)
+	fmt.Fprintf(&b, "| | |\n|---|---|\n")
+	fmt.Fprintf(&b, "| Pipeline | %s |\n", r.PipelineID)
+	if r.PipelineVersion != "" {
+		fmt.Fprintf(&b, "| Version | %s |\n", r.PipelineVersion)
+	}
+	if r.Environment != "" {
+		fmt.Fprintf(&b, "| Environment | %s |\n", r.Environment)
+	}
+	fmt.Fprintf(&b, "| Status | %s |\n", run.Status)
+	fmt.Fprintf(&b, "| Trigger | %s |\n", run.Trigger.Type)
+	fmt.Fprintf(&b, "| Started | %s |\n", run.StartedAt.Format(time.RFC3339))
+	fmt.Fprintf(&b, "| Duration | %s |\n", time.Duration(r.DurationSeconds*float64(time.Second)).Round(time.Millisecond))
+
+	fmt.Fprintf(&b, "\n## Records\n\n")
+	fmt.Fprintf(&b, "| Listed | Filtered | Invalid | Applied |\n|---|---|---|---|\n")
+	fmt.Fprintf(&b, "| %d | %d | %d | %d |\n", run.Listed, run.Filtered, run.Invalid, run.Records)
+
+	if run.Checkpoint != nil {
+		from := run.Checkpoint.From
+		if from == "" {
+			from = "(none)"
+		}
+		fmt.Fprintf(&b, "\n## Checkpoint\n\n`%s` → `%s`\n", from, run.Checkpoint.To)
+	}
+
+	if len(run.Errors) > 0 {
+		fmt.Fprintf(&b, "\n## Errors\n\n| Type | Count | Message |\n|---|---|---|\n")
+		for _, g := range run.Errors {
+			fmt.Fprintf(&b, "| %s | %d | %s |\n", g.Type, g.Count, strings.ReplaceAll(g.Message, "|", "\\|"))
+		}
+	} else if run.Error != "" {
+		fmt.Fprintf(&b, "\n## Error\n\n%s\n", run.Error)
+	}
+
+	if len(r.Labels) > 0 {
+		keys := make([]string, 0, len(r.Labels))
+		for k := range r.Labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		fmt.Fprintf(&b, "\n## Labels\n\n")
+		for _, k := range keys {
+			fmt.Fprintf(&b, "- %s: %s\n", k, r.Labels[k])
+		}
+	}
+
+	return b.String()
+}
+
+// Writer stores reports under a destination: a local directory, or an
+// http(s) URL prefix of an object store accepting PUT uploads such as a
+// pre-authorized bucket endpoint. Reports are written as
+// <pipeline>/<run-id>.json and <pipeline>/<run-id>.md.
+type Writer struct {
+	dest   string
+	remote bool
+	client *http.Client
+}
+
+// NewWriter creates a report writer for dest
+func NewWriter(dest string) *Writer {
+	remote := strings.HasPrefix(dest, "http://") || strings.HasPrefix(dest, "https://")
+	return &Writer{
+		dest:   strings.TrimRight(dest, "/"),
+		remote: remote,
+		client: egress.Client(30 * time.Second),
+	}
+}
+
+// Write stores the JSON report and Markdown summary
+func (w *Writer) Write(ctx context.Context, r *Report) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode run report: %w", err)
+	}
+
+	base := path.Join(r.PipelineID, r.Run.ID)
+	if err := w.put(ctx, base+".json", "application/json", data); err != nil {
+		return err
+	}
+	return w.put(ctx, base+".md", "text/markdown; charset=utf-8", []byte(r.Markdown()))
+}
+
+// put stores one object under the destination
+func (w *Writer) put(ctx context.Context, name, contentType string, data []byte) error {
+	if !w.remote {
+		file := filepath.Join(w.dest, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			return fmt.Errorf("failed to create report directory: %w", err)
+		}
+		if err := os.WriteFile(file, data, 0o644); err != nil {
+			return fmt.Errorf("failed to write run report: %w", err)
+		}
+		return nil
+	}
+
+	parts := strings.Split(name, "/")
+	for i := range parts {
+		parts[i] = url.PathEscape(parts[i])
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, w.dest+"/"+strings.Join(parts, "/"), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload run report: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to upload run report: status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Prune removes reports written more than maxAge ago from a local
+// directory, returning the number of files removed and bytes reclaimed.
+// Object stores expire reports with their own lifecycle rules, so Prune
+// leaves remote destinations alone.
+func (w *Writer) Prune(now time.Time, maxAge time.Duration) (int, int64, error) {
+	if w.remote || maxAge <= 0 {
+		return 0, 0, nil
+	}
+
+	items, reclaimed := 0, int64(0)
+	var dirs []string
+	err := filepath.WalkDir(w.dest, func(file string, d os.DirEntry, err error) error {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if file != w.dest {
+				dirs = append(dirs, file)
+			}
+			return nil
+		}
+		if ext := filepath.Ext(file); ext != ".json" && ext != ".md" {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || now.Sub(info.ModTime()) <= maxAge {
+			return err
+		}
+		if err := os.Remove(file); err != nil {
+			return err
+		}
+		items++
+		reclaimed += info.Size()
+		return nil
+	})
+	if err != nil {
+		return items, reclaimed, fmt.Errorf("failed to prune run reports: %w", err)
+	}
+
+	// Remove pipeline directories left empty, deepest first
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if entries, err := os.ReadDir(dirs[i]); err == nil && len(entries) == 0 {
+			os.Remove(dirs[i])
+		}
+	}
+	return items, reclaimed, nil
+}
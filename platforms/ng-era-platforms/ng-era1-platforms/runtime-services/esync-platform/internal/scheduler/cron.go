@@ -0,0 +1,229 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: scheduler-cron
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Cron Expression Parser
+ */
+
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cron is a parsed five-field cron expression (minute hour dom month dow)
+type Cron struct {
+	minute, hour, dom, month, dow uint64
+	domStar, dowStar, hourStar    bool
+	// loc is the time zone expressions are evaluated in; that of the time
+	// passed to Next when nil
+	loc *time.Location
+}
+
+// fieldBounds holds the allowed range of each cron field
+var fieldBounds = [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 6}}
+
+// ParseCron parses a standard five-field cron expression
+func ParseCron(expr string) (*Cron, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields", expr)
+	}
+
+	var sets [5]uint64
+	for i, field := range fields {
+		set, err := parseField(field, fieldBounds[i][0], fieldBounds[i][1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron field %q: %w", field, err)
+		}
+		sets[i] = set
+	}
+
+	return &Cron{
+		minute:   sets[0],
+		hour:     sets[1],
+		dom:      sets[2],
+		month:    sets[3],
+		dow:      sets[4],
+		domStar:  fields[2] == "*",
+		dowStar:  fields[4] == "*",
+		hourStar: fields[1] == "*",
+	}, nil
+}
+
+// In returns a copy of the expression evaluated in the time zone loc
+func (c *Cron) In(loc *time.Location) *Cron {
+	out := *c
+	out.loc = loc
+	return &out
+}
+
+// parseField parses a comma-separated list of values, ranges and steps
+func parseField(field string, min, max int) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q", part[i+1:])
+			}
+			step = n
+			part = part[:i]
+		}
+
+		lo, hi := min, max
+		switch {
+		case part == "*":
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			if lo, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, err
+			}
+			if hi, err = strconv.Atoi(bounds[1]); err != nil {
+				return 0, err
+			}
+		default:
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return 0, err
+			}
+			lo, hi = n, n
+			if step > 1 {
+				hi = max
+			}
+		}
+
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("value out of range %d-%d", min, max)
+		}
+		// Stop before v += step can overflow for huge steps
+		for v := lo; v <= hi; v += step {
+			set |= 1 << uint(v)
+			if step > hi-v {
+				break
+			}
+		}
+	}
+
+	return set, nil
+}
+
+// Next returns the first activation time strictly after t. Expressions
+// match the wall clock of the cron's time zone. Across daylight saving
+// changes, expressions with a wildcard hour follow elapsed time, running in
+// both passes of a repeated hour. Other expressions run once per wall time:
+// times a change skips run shifted forward by the length of the gap and
+// repeated times run at their first occurrence.
+func (c *Cron) Next(t time.Time) time.Time {
+	if c.loc != nil {
+		t = t.In(c.loc)
+	}
+	if c.hourStar {
+		return c.next(t)
+	}
+
+	loc := t.Location()
+	after := t
+	wall := wallClock(t)
+	for {
+		if wall = c.next(wall); wall.IsZero() {
+			return time.Time{}
+		}
+		if at, ok := resolveWall(wall, loc, after); ok {
+			return at
+		}
+	}
+}
+
+// next returns the first time strictly after t whose wall clock in the
+// location of t matches the expression
+func (c *Cron) next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+// dayMatches applies the cron rule that day-of-month and day-of-week are
+// OR-ed when both are restricted
+func (c *Cron) dayMatches(t time.Time) bool {
+	domMatch := c.dom&(1<<uint(t.Day())) != 0
+	dowMatch := c.dow&(1<<uint(t.Weekday())) != 0
+
+	switch {
+	case c.domStar && c.dowStar:
+		return true
+	case c.domStar:
+		return dowMatch
+	case c.dowStar:
+		return domMatch
+	default:
+		return domMatch || dowMatch
+	}
+}
+
+// wallClock returns the wall clock reading of t as a UTC time, which has
+// no daylight saving changes
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+// resolveWall returns the first instant after after at which the clocks of
+// loc show wall. A wall time skipped by a daylight saving change resolves
+// to the instant it would have had under the offset before the change;
+// false is returned for a repeated wall time whose first occurrence is not
+// after after.
+func resolveWall(wall time.Time, loc *time.Location, after time.Time) (time.Time, bool) {
+	// Time zones change offset at most once within a day, so the offsets a
+	// day either side are the only candidates
+	var first time.Time
+	for _, probe := range []time.Time{wall.Add(-24 * time.Hour), wall.Add(24 * time.Hour)} {
+		_, offset := probe.In(loc).Zone()
+		at := wall.Add(-time.Duration(offset) * time.Second).In(loc)
+		if wallClock(at).Equal(wall) && (first.IsZero() || at.Before(first)) {
+			first = at
+		}
+	}
+	if first.IsZero() {
+		_, offset := wall.Add(-24 * time.Hour).In(loc).Zone()
+		first = wall.Add(-time.Duration(offset) * time.Second).In(loc)
+	}
+	if !first.After(after) {
+		return time.Time{}, false
+	}
+	return first, true
+}
@@ -0,0 +1,54 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: cron-fuzzing
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Cron Parser Fuzz Targets
+ */
+
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+// FuzzParseCron checks cron expressions parse or fail cleanly and that the
+// next activation of a parsed schedule is strictly later and matches every
+// field of the expression
+func FuzzParseCron(f *testing.F) {
+	from := time.Date(2024, 2, 28, 23, 59, 30, 0, time.UTC)
+	f.Fuzz(func(t *testing.T, expr string) {
+		c, err := ParseCron(expr)
+		if err != nil {
+			return
+		}
+		next := c.Next(from)
+		if next.IsZero() {
+			return
+		}
+		if !next.After(from) {
+			t.Fatalf("%q: next activation %s is not after %s", expr, next, from)
+		}
+		if !c.matches(next) {
+			t.Fatalf("%q: next activation %s does not match the expression", expr, next)
+		}
+	})
+}
+
+// matches reports whether t falls on a minute the expression selects
+func (c *Cron) matches(t time.Time) bool {
+	return t.Second() == 0 && t.Nanosecond() == 0 &&
+		c.minute&(1<<uint(t.Minute())) != 0 &&
+		c.hour&(1<<uint(t.Hour())) != 0 &&
+		c.month&(1<<uint(t.Month())) != 0 &&
+		c.dayMatches(t)
+}
@@ -0,0 +1,176 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: scheduler-kafka-trigger
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Kafka Topic Triggers via REST Proxy
+ */
+
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/engine"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+const (
+	kafkaContentType = "application/vnd.kafka.v2+json"
+	kafkaAccept      = "application/vnd.kafka.json.v2+json"
+	kafkaPollDelay   = 5 * time.Second
+)
+
+// kafkaMessage is a record returned by the REST proxy
+type kafkaMessage struct {
+	Topic     string          `json:"topic"`
+	Key       json.RawMessage `json:"key"`
+	Partition int             `json:"partition"`
+	Offset    int64           `json:"offset"`
+}
+
+// consumeKafka subscribes to the trigger topic through a Confluent-compatible
+// REST proxy and starts one run per non-empty poll, coalescing bursts of
+// messages into a single run
+func (s *Scheduler) consumeKafka(ctx context.Context, pipelineID string, spec registry.TriggerSpec) {
+	group := spec.Group
+	if group == "" {
+		group = "esync-" + pipelineID
+	}
+
+	for ctx.Err() == nil {
+		instance, err := s.createConsumer(ctx, spec, group)
+		if err != nil {
+			log.Printf("[Scheduler] Kafka trigger for pipeline %s: %v", pipelineID, err)
+			sleep(ctx, kafkaPollDelay)
+			continue
+		}
+
+		s.pollKafka(ctx, pipelineID, instance)
+		s.deleteConsumer(instance)
+	}
+}
+
+// pollKafka polls an existing consumer instance until an error occurs
+func (s *Scheduler) pollKafka(ctx context.Context, pipelineID, instance string) {
+	for ctx.Err() == nil {
+		var messages []kafkaMessage
+		if err := s.kafkaRequest(ctx, http.MethodGet, instance+"/records", nil, &messages); err != nil {
+			log.Printf("[Scheduler] Kafka poll for pipeline %s failed: %v", pipelineID, err)
+			return
+		}
+
+		if len(messages) > 0 {
+			last := messages[len(messages)-1]
+			s.fire(ctx, pipelineID, engine.Trigger{
+				Type: engine.TriggerKafka,
+				Metadata: map[string]string{
+					"topic":     last.Topic,
+					"partition": strconv.Itoa(last.Partition),
+					"offset":    strconv.FormatInt(last.Offset, 10),
+					"key":       strings.Trim(string(last.Key), `"`),
+					"messages":  strconv.Itoa(len(messages)),
+					// Batches polled again after a restart run once
+					engine.IdempotencyMetadata: fmt.Sprintf("%s/%d/%d", last.Topic, last.Partition, last.Offset),
+				},
+			})
+			continue
+		}
+
+		sleep(ctx, kafkaPollDelay)
+	}
+}
+
+// createConsumer registers a consumer instance subscribed to the topic and
+// returns its base URI
+func (s *Scheduler) createConsumer(ctx context.Context, spec registry.TriggerSpec, group string) (string, error) {
+	if spec.RestProxy == "" || spec.Topic == "" {
+		return "", fmt.Errorf("rest_proxy and topic are required")
+	}
+
+	var created struct {
+		BaseURI string `json:"base_uri"`
+	}
+	body := map[string]string{"format": "json", "auto.offset.reset": "latest"}
+	url := strings.TrimRight(spec.RestProxy, "/") + "/consumers/" + group
+	if err := s.kafkaRequest(ctx, http.MethodPost, url, body, &created); err != nil {
+		return "", fmt.Errorf("failed to create consumer: %w", err)
+	}
+
+	subscription := map[string][]string{"topics": {spec.Topic}}
+	if err := s.kafkaRequest(ctx, http.MethodPost, created.BaseURI+"/subscription", subscription, nil); err != nil {
+		s.deleteConsumer(created.BaseURI)
+		return "", fmt.Errorf("failed to subscribe to %s: %w", spec.Topic, err)
+	}
+
+	return created.BaseURI, nil
+}
+
+// deleteConsumer releases a consumer instance on the proxy
+func (s *Scheduler) deleteConsumer(instance string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.kafkaRequest(ctx, http.MethodDelete, instance, nil, nil); err != nil {
+		log.Printf("[Scheduler] Failed to delete Kafka consumer %s: %v", instance, err)
+	}
+}
+
+// kafkaRequest performs a REST proxy call, decoding the response into out
+func (s *Scheduler) kafkaRequest(ctx context.Context, method, url string, in, out interface{}) error {
+	var body bytes.Buffer
+	if in != nil {
+		if err := json.NewEncoder(&body).Encode(in); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", kafkaContentType)
+	req.Header.Set("Accept", kafkaAccept)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("rest proxy returned status %d", resp.StatusCode)
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// sleep waits for d or until ctx is cancelled
+func sleep(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
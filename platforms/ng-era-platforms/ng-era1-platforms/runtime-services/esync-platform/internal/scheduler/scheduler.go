@@ -0,0 +1,291 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: pipeline-scheduler
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Pipeline Scheduler - Cron and Event-Driven Triggers
+ */
+
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/egress"
+	"github.com/machine-native-ops/esync-platform/internal/engine"
+	"github.com/machine-native-ops/esync-platform/internal/logging"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// Scheduler starts pipeline runs from schedules and external events
+type Scheduler struct {
+	registry *registry.Service
+	engine   *engine.Engine
+	client   *http.Client
+
+	// ctx scopes runs started by inbound requests; it is set by Start
+	ctx context.Context
+
+	// nextRuns holds the next scheduled run of each pipeline and loops
+	// cancels its schedule loop and event consumers
+	mu       sync.Mutex
+	nextRuns map[string]time.Time
+	loops    map[string]context.CancelCauseFunc
+}
+
+// errRescheduled cancels the loops of a pipeline scheduled again
+var errRescheduled = errors.New("pipeline rescheduled")
+
+// New creates a new scheduler
+func New(reg *registry.Service, eng *engine.Engine) *Scheduler {
+	return &Scheduler{
+		registry: reg,
+		engine:   eng,
+		client:   egress.Client(60 * time.Second),
+		nextRuns: make(map[string]time.Time),
+		loops:    make(map[string]context.CancelCauseFunc),
+	}
+}
+
+// NextRun returns the next scheduled run of a pipeline, false when none is
+// scheduled
+func (s *Scheduler) NextRun(pipelineID string) (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	at, ok := s.nextRuns[pipelineID]
+	return at, ok
+}
+
+// setNextRun records the next scheduled run of a pipeline; a zero time
+// clears it
+func (s *Scheduler) setNextRun(pipelineID string, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if at.IsZero() {
+		delete(s.nextRuns, pipelineID)
+		return
+	}
+	s.nextRuns[pipelineID] = at
+}
+
+// Start launches schedule loops and event consumers for every pipeline.
+// They stop when ctx is cancelled.
+func (s *Scheduler) Start(ctx context.Context) error {
+	s.ctx = ctx
+
+	for _, p := range s.registry.GetAll() {
+		if err := s.schedule(ctx, p); err != nil {
+			return err
+		}
+	}
+
+	s.engine.OnRunComplete(func(run *engine.Run) {
+		s.fireDownstream(ctx, run)
+		s.fireSourcePeers(ctx, run)
+	})
+
+	return nil
+}
+
+// Schedule launches the schedule loop and event consumers of a pipeline
+// registered after Start, such as a clone, replacing those of a pipeline
+// with the same ID
+func (s *Scheduler) Schedule(p *registry.Pipeline) error {
+	return s.schedule(s.background(), p)
+}
+
+// Unschedule stops the schedule loop and event consumers of a pipeline
+func (s *Scheduler) Unschedule(pipelineID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cancel, ok := s.loops[pipelineID]; ok {
+		cancel(context.Canceled)
+		delete(s.loops, pipelineID)
+	}
+}
+
+// schedule launches the schedule loop and event consumers of a pipeline
+func (s *Scheduler) schedule(ctx context.Context, p *registry.Pipeline) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	s.mu.Lock()
+	if prev, ok := s.loops[p.ID]; ok {
+		prev(errRescheduled)
+	}
+	s.loops[p.ID] = cancel
+	s.mu.Unlock()
+
+	if p.Schedule != nil {
+		if err := s.startSchedule(ctx, p.ID, p.Schedule); err != nil {
+			return err
+		}
+	}
+
+	for _, trigger := range p.Triggers {
+		if trigger.Type == registry.TriggerKafka {
+			go s.consumeKafka(ctx, p.ID, trigger)
+		}
+	}
+	return nil
+}
+
+// startSchedule runs a pipeline on its interval or cron schedule
+func (s *Scheduler) startSchedule(ctx context.Context, pipelineID string, spec *registry.ScheduleSpec) error {
+	var cron *Cron
+	if spec.Cron != "" {
+		loc, err := spec.Location()
+		if err != nil {
+			return err
+		}
+		if cron, err = ParseCron(spec.Cron); err != nil {
+			return err
+		}
+		cron = cron.In(loc)
+	}
+
+	next := func(now time.Time) time.Time {
+		var at time.Time
+		if cron != nil {
+			at = cron.Next(now)
+		} else {
+			at = now.Add(time.Duration(spec.Interval) * time.Second)
+		}
+		// Boosted pipelines run more often, never less
+		if p, err := s.registry.GetByID(pipelineID); err == nil {
+			if boost := s.engine.BoostInterval(p); boost > 0 && (at.IsZero() || now.Add(boost).Before(at)) {
+				at = now.Add(boost)
+			}
+		}
+		return at
+	}
+	if cron == nil && spec.Interval <= 0 {
+		return nil
+	}
+
+	go func() {
+		for {
+			wake := s.engine.SLOChanged()
+			at := next(time.Now())
+			s.setNextRun(pipelineID, at)
+			if at.IsZero() {
+				return
+			}
+
+			timer := time.NewTimer(time.Until(at))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				// The loop replacing this one records the next run
+				if !errors.Is(context.Cause(ctx), errRescheduled) {
+					s.setNextRun(pipelineID, time.Time{})
+				}
+				return
+			case <-wake:
+				timer.Stop()
+				continue
+			case <-timer.C:
+			}
+
+			s.fire(ctx, pipelineID, engine.Trigger{
+				Type:     engine.TriggerSchedule,
+				Metadata: map[string]string{"scheduled_at": at.UTC().Format(time.RFC3339)},
+			})
+		}
+	}()
+
+	return nil
+}
+
+// fireDownstream triggers pipelines that depend on the completed run
+func (s *Scheduler) fireDownstream(ctx context.Context, run *engine.Run) {
+	for _, p := range s.registry.GetAll() {
+		for _, trigger := range p.Triggers {
+			if trigger.Type != registry.TriggerPipeline || trigger.Pipeline != run.PipelineID {
+				continue
+			}
+			if !outcomeMatches(trigger.On, run.Status) {
+				continue
+			}
+
+			go s.fire(ctx, p.ID, engine.Trigger{
+				Type: engine.TriggerPipeline,
+				Metadata: map[string]string{
+					"upstream_pipeline": run.PipelineID,
+					"upstream_run":      run.ID,
+					"upstream_status":   run.Status,
+				},
+			})
+		}
+	}
+}
+
+// fireSourcePeers runs the pipelines sharing the source of a run that read
+// it, so they consume the read while it is kept. Runs they start do not
+// fire peers in turn.
+func (s *Scheduler) fireSourcePeers(ctx context.Context, run *engine.Run) {
+	if !run.SharedRead || run.Status != engine.StatusSucceeded || run.Trigger.Type == engine.TriggerSharedSource {
+		return
+	}
+	for _, id := range s.engine.SourcePeers(run.PipelineID) {
+		go s.fire(ctx, id, engine.Trigger{
+			Type: engine.TriggerSharedSource,
+			Metadata: map[string]string{
+				"source_pipeline": run.PipelineID,
+				"source_run":      run.ID,
+			},
+		})
+	}
+}
+
+// outcomeMatches checks an upstream run status against a trigger's "on" filter
+func outcomeMatches(on, status string) bool {
+	switch on {
+	case "any":
+		return true
+	case "failure":
+		return status == engine.StatusFailed
+	default:
+		return status == engine.StatusSucceeded
+	}
+}
+
+// background returns the context for runs started outside Start
+func (s *Scheduler) background() context.Context {
+	if s.ctx == nil {
+		return context.Background()
+	}
+	return s.ctx
+}
+
+// fire executes a run and logs its failure
+func (s *Scheduler) fire(ctx context.Context, pipelineID string, trigger engine.Trigger) {
+	logging.Debugf(logging.Scope{Module: "scheduler", Pipeline: pipelineID}, "[Scheduler] Firing %s trigger of pipeline %s", trigger.Type, pipelineID)
+	_, err := s.engine.RunOnce(ctx, pipelineID, trigger)
+	switch {
+	case errors.Is(err, engine.ErrPaused):
+		log.Printf("[Scheduler] Skipping %s trigger for paused pipeline %s", trigger.Type, pipelineID)
+	case errors.Is(err, engine.ErrShed):
+		log.Printf("[Scheduler] Shedding %s trigger for bulk pipeline %s during an SLO incident", trigger.Type, pipelineID)
+	case errors.Is(err, engine.ErrHandoff):
+		log.Printf("[Scheduler] Skipping %s trigger for pipeline %s: handed off", trigger.Type, pipelineID)
+	case errors.Is(err, engine.ErrRunInProgress):
+		log.Printf("[Scheduler] Rejected %s trigger for pipeline %s: run in progress", trigger.Type, pipelineID)
+	case err != nil:
+		log.Printf("[Scheduler] %s-triggered run of pipeline %s failed: %v", trigger.Type, pipelineID, err)
+	}
+}
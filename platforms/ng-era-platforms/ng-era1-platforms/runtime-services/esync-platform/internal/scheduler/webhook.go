@@ -0,0 +1,133 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: scheduler-webhook-trigger
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Inbound Webhook Triggers
+ */
+
+package scheduler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/machine-native-ops/esync-platform/internal/engine"
+	"github.com/machine-native-ops/esync-platform/internal/fips"
+	"github.com/machine-native-ops/esync-platform/internal/problem"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+const (
+	// maxWebhookBody bounds inbound webhook payloads
+	maxWebhookBody = 1 << 20
+	// signatureHeader carries "sha256=<hex hmac>" of the payload
+	signatureHeader = "X-Esync-Signature"
+	// signatureMAC is the MAC of signatureHeader
+	signatureMAC = "hmac-sha256"
+)
+
+// WebhookHandler serves POST /hooks/{pipeline-id}. Pipelines accept webhooks
+// only when they declare a webhook trigger; the run starts in the background.
+func (s *Scheduler) WebhookHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			problem.Write(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+
+		id := strings.TrimPrefix(r.URL.Path, "/hooks/")
+		p, err := s.registry.GetByID(id)
+		if err != nil {
+			problem.Write(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		spec := webhookTrigger(p)
+		if spec == nil {
+			problem.Write(w, http.StatusNotFound, "pipeline has no webhook trigger")
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody))
+		if err != nil {
+			problem.Write(w, http.StatusBadRequest, "failed to read body")
+			return
+		}
+
+		if secret := os.ExpandEnv(spec.Secret); secret != "" {
+			if err := fips.Check(fips.KindMAC, signatureMAC); err != nil {
+				problem.Write(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			if !validSignature(secret, body, r.Header.Get(signatureHeader)) {
+				problem.Write(w, http.StatusUnauthorized, "invalid signature")
+				return
+			}
+		}
+
+		trigger := engine.Trigger{
+			Type: engine.TriggerWebhook,
+			Metadata: map[string]string{
+				"remote_addr":  r.RemoteAddr,
+				"content_size": strconv.Itoa(len(body)),
+			},
+		}
+		if id := problem.CorrelationID(r.Context()); id != "" {
+			trigger.Metadata[engine.CorrelationMetadata] = id
+		}
+		if tp := problem.Traceparent(r.Context()); tp != "" {
+			trigger.Metadata[engine.TraceparentMetadata] = tp
+		}
+		if event := r.Header.Get("X-Event-Type"); event != "" {
+			trigger.Metadata["event_type"] = event
+		}
+		// Redelivered webhooks carrying the key of a delivery run it once
+		if key := r.Header.Get("Idempotency-Key"); key != "" {
+			trigger.Metadata[engine.IdempotencyMetadata] = key
+		}
+
+		go s.fire(s.background(), p.ID, trigger)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]string{"pipeline_id": p.ID, "status": "triggered"})
+	})
+}
+
+// webhookTrigger returns the webhook trigger of a pipeline, if any
+func webhookTrigger(p *registry.Pipeline) *registry.TriggerSpec {
+	for i := range p.Triggers {
+		if p.Triggers[i].Type == registry.TriggerWebhook {
+			return &p.Triggers[i]
+		}
+	}
+	return nil
+}
+
+// validSignature checks the HMAC-SHA256 signature of a webhook payload
+func validSignature(secret string, body []byte, header string) bool {
+	sig, err := hex.DecodeString(strings.TrimPrefix(header, "sha256="))
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(sig, mac.Sum(nil))
+}
@@ -0,0 +1,153 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: secrets-resolver
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Config Templates and Secret References
+ */
+
+package secrets
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Redacted replaces secret values in rendered configs
+const Redacted = "***"
+
+// Reference records where a secret was referenced in a config
+type Reference struct {
+	Path string `json:"path"`
+	Ref  string `json:"ref"`
+}
+
+// Resolver expands connector config values. Plain ${VAR} references expand
+// from the environment; ${secret:NAME} reads NAME from the secrets directory
+// and ${file:/path} reads a file. Secret and file values can be redacted.
+type Resolver struct {
+	dir string
+}
+
+// NewResolver creates a resolver reading named secrets from dir
+func NewResolver(dir string) *Resolver {
+	return &Resolver{dir: dir}
+}
+
+// ExpandConfig returns a copy of config with every string value expanded,
+// along with the secret references encountered. When redact is set secret
+// values are never read and the whole value is replaced by Redacted.
+func (r *Resolver) ExpandConfig(config map[string]interface{}, redact bool) (map[string]interface{}, []Reference, error) {
+	var refs []Reference
+	out, err := r.expandValue("", config, redact, &refs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sort.Slice(refs, func(i, j int) bool { return refs[i].Path < refs[j].Path })
+	expanded, _ := out.(map[string]interface{})
+	return expanded, refs, nil
+}
+
+// expandValue walks maps and lists, expanding strings
+func (r *Resolver) expandValue(path string, v interface{}, redact bool, refs *[]Reference) (interface{}, error) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			expanded, err := r.expandValue(join(path, k), item, redact, refs)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = expanded
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			expanded, err := r.expandValue(fmt.Sprintf("%s[%d]", path, i), item, redact, refs)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = expanded
+		}
+		return out, nil
+	case string:
+		return r.expandString(path, val, redact, refs)
+	default:
+		return v, nil
+	}
+}
+
+// expandString expands the references within a single value
+func (r *Resolver) expandString(path, s string, redact bool, refs *[]Reference) (string, error) {
+	var firstErr error
+	secret := false
+	out := os.Expand(s, func(name string) string {
+		kind, ref, isRef := strings.Cut(name, ":")
+		if !isRef {
+			return os.Getenv(name)
+		}
+
+		secret = true
+		*refs = append(*refs, Reference{Path: path, Ref: name})
+		if redact {
+			return Redacted
+		}
+		value, err := r.lookup(kind, ref)
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("%s: %w", path, err)
+		}
+		return value
+	})
+
+	if firstErr != nil {
+		return "", firstErr
+	}
+	if secret && redact {
+		return Redacted, nil
+	}
+	return out, nil
+}
+
+// lookup resolves a single secret reference
+func (r *Resolver) lookup(kind, ref string) (string, error) {
+	var path string
+	switch kind {
+	case "secret":
+		if strings.ContainsAny(ref, `/\`) {
+			return "", fmt.Errorf("invalid secret name %q", ref)
+		}
+		path = filepath.Join(r.dir, ref)
+	case "file":
+		path = ref
+	default:
+		return "", fmt.Errorf("unknown reference type %q", kind)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s reference: %w", kind, err)
+	}
+
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// join builds a dotted config path
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
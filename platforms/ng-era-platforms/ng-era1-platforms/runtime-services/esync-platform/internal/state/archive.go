@@ -0,0 +1,227 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: state-backup
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * State Backup Archives
+ */
+
+package state
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ArchiveFormat is the version of the backup archive layout. Restore
+// refuses archives of a newer format.
+const ArchiveFormat = 1
+
+// manifestName is the first entry of every archive
+const manifestName = "manifest.json"
+
+// extraPrefix holds entries that are not store files, such as the
+// in-memory run history
+const extraPrefix = "extra/"
+
+// maxArchiveEntry bounds a single archive entry read by Restore
+const maxArchiveEntry = 256 << 20
+
+// ErrArchiveFormat is returned for archives Restore cannot read
+var ErrArchiveFormat = errors.New("unsupported backup archive")
+
+// Manifest describes a backup archive
+type Manifest struct {
+	Format    int       `json:"format"`
+	CreatedAt time.Time `json:"created_at"`
+	// Schema is the state schema version of the store files
+	Schema int `json:"schema"`
+	// Files lists the store files in the archive, relative to the state
+	// directory
+	Files []string `json:"files"`
+	// Extra lists the non-store entries, such as the run history
+	Extra []string `json:"extra,omitempty"`
+}
+
+// Backup writes a gzipped tar archive of every file in the store, plus the
+// extra entries given, to w
+func (s *Store) Backup(w io.Writer, extra map[string][]byte) (*Manifest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	manifest := &Manifest{Format: ArchiveFormat, CreatedAt: time.Now().UTC(), Schema: SchemaVersion}
+	files := make(map[string][]byte)
+	err := filepath.WalkDir(s.dir, func(file string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || strings.HasSuffix(file, ".tmp") {
+			return err
+		}
+		rel, err := filepath.Rel(s.dir, file)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		files[name] = data
+		manifest.Files = append(manifest.Files, name)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state for backup: %w", err)
+	}
+	for name := range extra {
+		manifest.Extra = append(manifest.Extra, name)
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	add := func(name string, data []byte) error {
+		hdr := &tar.Header{Name: name, Mode: 0o600, Size: int64(len(data)), ModTime: manifest.CreatedAt}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err == nil {
+		err = add(manifestName, data)
+	}
+	for _, name := range manifest.Files {
+		if err != nil {
+			break
+		}
+		err = add(name, files[name])
+	}
+	for _, name := range manifest.Extra {
+		if err != nil {
+			break
+		}
+		err = add(extraPrefix+name, extra[name])
+	}
+	if err == nil {
+		err = tw.Close()
+	}
+	if err == nil {
+		err = gz.Close()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write backup archive: %w", err)
+	}
+	return manifest, nil
+}
+
+// Restore replaces every file in the store with the contents of an archive
+// written by Backup, returning its manifest and extra entries. The archive
+// is read completely before any state is touched; state of an older schema
+// is migrated and state of a newer one refused.
+func (s *Store) Restore(r io.Reader) (*Manifest, map[string][]byte, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrArchiveFormat, err)
+	}
+	defer gz.Close()
+
+	var manifest *Manifest
+	files := make(map[string][]byte)
+	extra := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrArchiveFormat, err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(io.LimitReader(tr, maxArchiveEntry+1))
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrArchiveFormat, err)
+		}
+		if len(data) > maxArchiveEntry {
+			return nil, nil, fmt.Errorf("%w: entry %s is too large", ErrArchiveFormat, hdr.Name)
+		}
+
+		switch name := path.Clean(hdr.Name); {
+		case name == manifestName:
+			manifest = &Manifest{}
+			if err := json.Unmarshal(data, manifest); err != nil {
+				return nil, nil, fmt.Errorf("%w: invalid manifest: %v", ErrArchiveFormat, err)
+			}
+		case strings.HasPrefix(name, extraPrefix):
+			extra[strings.TrimPrefix(name, extraPrefix)] = data
+		default:
+			if _, err := s.path(strings.TrimSuffix(name, ".json")); err != nil || path.IsAbs(name) {
+				return nil, nil, fmt.Errorf("%w: invalid entry %s", ErrArchiveFormat, hdr.Name)
+			}
+			files[name] = data
+		}
+	}
+	if manifest == nil {
+		return nil, nil, fmt.Errorf("%w: missing %s", ErrArchiveFormat, manifestName)
+	}
+	if manifest.Format > ArchiveFormat {
+		return nil, nil, fmt.Errorf("%w: format %d is newer than %d", ErrArchiveFormat, manifest.Format, ArchiveFormat)
+	}
+	if manifest.Schema > SchemaVersion {
+		return nil, nil, fmt.Errorf("%w: archive holds state schema version %d, this daemon supports up to %d", ErrSchemaVersion, manifest.Schema, SchemaVersion)
+	}
+
+	if err := s.replace(files); err != nil {
+		return nil, nil, err
+	}
+	if _, err := s.migrate(); err != nil {
+		return nil, nil, err
+	}
+	return manifest, extra, nil
+}
+
+// replace removes every file in the store and writes files in their place
+func (s *Store) replace(files map[string][]byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := filepath.WalkDir(s.dir, func(file string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		return os.Remove(file)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to clear state for restore: %w", err)
+	}
+	for name, data := range files {
+		file := filepath.Join(s.dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0o750); err != nil {
+			return fmt.Errorf("failed to create state directory: %w", err)
+		}
+		if err := os.WriteFile(file, data, 0o600); err != nil {
+			return fmt.Errorf("failed to restore %s: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: daemon-state-store
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Daemon State Store
+ */
+
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+)
+
+// Store persists daemon state as JSON documents under a directory.
+// Keys are slash-separated paths such as "checkpoints/orders-sync".
+type Store struct {
+	dir string
+	mu  sync.Mutex
+	// writtenBy is the daemon version recorded by CheckVersion
+	writtenBy string
+}
+
+// NewStore creates a state store rooted at dir
+func NewStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	return &Store{dir: dir}, nil
+}
+
+// path maps a key to its file location
+func (s *Store) path(key string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(key))
+	if clean == "." || filepath.IsAbs(clean) || strings.HasPrefix(clean, "..") {
+		return "", fmt.Errorf("invalid state key %q", key)
+	}
+
+	return filepath.Join(s.dir, clean+".json"), nil
+}
+
+// Load reads the document stored under key into v. It reports false when no
+// document exists.
+func (s *Store) Load(key string, v interface{}) (bool, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read state %s: %w", key, err)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, fmt.Errorf("failed to decode state %s: %w", key, err)
+	}
+
+	return true, nil
+}
+
+// Save atomically writes v under key
+func (s *Store) Save(key string, v interface{}) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state %s: %w", key, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write state %s: %w", key, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to commit state %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// Delete removes the document stored under key
+func (s *Store) Delete(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete state %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// Size returns the size in bytes of the document stored under key, or zero
+// when none exists
+func (s *Store) Size(key string) (int64, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to stat state %s: %w", key, err)
+	}
+
+	return info.Size(), nil
+}
+
+// Keys lists the keys stored directly under prefix
+func (s *Store) Keys(prefix string) ([]string, error) {
+	dir, err := s.path(prefix)
+	if err != nil {
+		return nil, err
+	}
+	dir = strings.TrimSuffix(dir, ".json")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list state %s: %w", prefix, err)
+	}
+
+	keys := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		keys = append(keys, prefix+"/"+strings.TrimSuffix(name, ".json"))
+	}
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
+// LoadCheckpoint returns the stored checkpoint for a pipeline, or nil when
+// the pipeline has not synced yet
+func (s *Store) LoadCheckpoint(pipelineID string) (*connectors.Checkpoint, error) {
+	var checkpoint connectors.Checkpoint
+	found, err := s.Load("checkpoints/"+pipelineID, &checkpoint)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	return &checkpoint, nil
+}
+
+// SaveCheckpoint stores the checkpoint for a pipeline
+func (s *Store) SaveCheckpoint(pipelineID string, checkpoint *connectors.Checkpoint) error {
+	return s.Save("checkpoints/"+pipelineID, checkpoint)
+}
+
+// Dir returns the root directory of the store
+func (s *Store) Dir() string {
+	return s.dir
+}
@@ -0,0 +1,158 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: state-versioning
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * State Schema Versioning
+ */
+
+package state
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"path/filepath"
+	"time"
+)
+
+// versionKey holds the schema version of a state directory
+const versionKey = "version"
+
+// ErrSchemaVersion is returned for state written with a schema newer than
+// this daemon understands
+var ErrSchemaVersion = errors.New("unsupported state schema version")
+
+// Version is the schema marker of a state directory
+type Version struct {
+	Schema int `json:"schema"`
+	// WrittenBy is the daemon version that last opened the directory
+	WrittenBy  string    `json:"written_by,omitempty"`
+	MigratedAt time.Time `json:"migrated_at,omitempty"`
+}
+
+// migration upgrades state from schema version from to from+1
+type migration struct {
+	from int
+	name string
+	run  func(s *Store) error
+}
+
+// migrations upgrade older state in order. State written before schema
+// versions were recorded is version 0.
+var migrations = []migration{
+	{from: 0, name: "record the schema version", run: func(*Store) error { return nil }},
+}
+
+// SchemaVersion is the state schema version written by this daemon
+var SchemaVersion = len(migrations)
+
+// CheckVersion compares the schema version of the store with this
+// daemon's, migrating older state forward and refusing newer state, and
+// records writtenBy as the daemon version using the store
+func (s *Store) CheckVersion(writtenBy string) (*Version, error) {
+	s.mu.Lock()
+	s.writtenBy = writtenBy
+	s.mu.Unlock()
+
+	return s.migrate()
+}
+
+// migrate brings the store to SchemaVersion
+func (s *Store) migrate() (*Version, error) {
+	var v Version
+	found, err := s.Load(versionKey, &v)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		empty, err := s.empty()
+		if err != nil {
+			return nil, err
+		}
+		if empty {
+			v.Schema = SchemaVersion
+		}
+	}
+	if err := s.supported(v); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	writtenBy := s.writtenBy
+	s.mu.Unlock()
+
+	migrated := v.Schema < SchemaVersion
+	for _, m := range migrations[v.Schema:] {
+		log.Printf("[State] Migrating state schema from version %d to %d: %s", m.from, m.from+1, m.name)
+		if err := m.run(s); err != nil {
+			return nil, fmt.Errorf("failed to migrate state schema from version %d: %w", m.from, err)
+		}
+		v.Schema = m.from + 1
+		v.MigratedAt = time.Now().UTC()
+		if err := s.Save(versionKey, v); err != nil {
+			return nil, err
+		}
+	}
+	if !found || migrated || v.WrittenBy != writtenBy {
+		v.WrittenBy = writtenBy
+		if err := s.Save(versionKey, v); err != nil {
+			return nil, err
+		}
+	}
+	return &v, nil
+}
+
+// ReadVersion returns the schema version of the store without migrating
+// or recording anything, refusing state newer than this daemon's like
+// CheckVersion
+func (s *Store) ReadVersion() (*Version, error) {
+	var v Version
+	if _, err := s.Load(versionKey, &v); err != nil {
+		return nil, err
+	}
+	if err := s.supported(v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// supported refuses state written with a schema newer than SchemaVersion
+func (s *Store) supported(v Version) error {
+	if v.Schema <= SchemaVersion {
+		return nil
+	}
+	by := ""
+	if v.WrittenBy != "" {
+		by = " by daemon version " + v.WrittenBy
+	}
+	return fmt.Errorf("%w: state in %s was written%s with schema version %d, this daemon supports up to %d; upgrade the daemon or restore a backup taken by this version", ErrSchemaVersion, s.dir, by, v.Schema, SchemaVersion)
+}
+
+// empty reports whether the store holds no documents
+func (s *Store) empty() (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	empty := true
+	err := filepath.WalkDir(s.dir, func(file string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		empty = false
+		return fs.SkipAll
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to read state directory: %w", err)
+	}
+	return empty, nil
+}
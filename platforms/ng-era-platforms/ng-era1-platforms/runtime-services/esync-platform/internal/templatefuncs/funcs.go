@@ -0,0 +1,423 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: template-functions
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Template Function Library
+ */
+
+// Package templatefuncs is the function library of the Go templates
+// pipelines declare: the template transform and the subject, body and
+// routing key templates of the notify connector. Templates pick a version
+// of the library with a functions option:
+//
+//	transforms:
+//	  - type: template
+//	    options:
+//	      functions: 2
+//	      fields:
+//	        email_hash: '{{sha256 (lower (trim .Data.email))}}'
+//	        city: '{{jsonPath "address.city" .Data.profile}}'
+//
+// The functions of a version never change. A function changing its
+// behaviour is added again under a new version, and the old form stays
+// available to templates pinned to an earlier one, so existing pipelines
+// render the same output after upgrades. Templates without a functions
+// option get version 1, the json function templates always had.
+//
+// Functions take their subject last so they chain in pipelines, as in
+// {{.Data.name | trim | upper}}.
+package templatefuncs
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/machine-native-ops/esync-platform/internal/fips"
+)
+
+// Versions of the library
+const (
+	// Version1 has the json function only
+	Version1 = 1
+	// Version2 adds the string, time, hash, JSON path and regex functions
+	Version2 = 2
+	// Latest is the newest version
+	Latest = Version2
+)
+
+// Categories of functions
+const (
+	CategoryString = "string"
+	CategoryTime   = "time"
+	CategoryHash   = "hash"
+	CategoryJSON   = "json"
+	CategoryRegex  = "regex"
+)
+
+// Function documents a function of the library
+type Function struct {
+	Name        string `json:"name"`
+	Category    string `json:"category"`
+	Usage       string `json:"usage"`
+	Description string `json:"description"`
+	// Since is the first version offering the function in this form and
+	// Until, when set, the first version no longer offering it
+	Since int `json:"since"`
+	Until int `json:"until,omitempty"`
+}
+
+// entry is a function of the library and its implementation
+type entry struct {
+	Function
+	fn interface{}
+}
+
+// library holds every form of every function; a version offers the forms
+// whose range covers it
+var library = []entry{
+	{Function{"json", CategoryJSON, "json VALUE", "Encodes a value as JSON", Version1, 0}, toJSON},
+
+	{Function{"upper", CategoryString, "upper S", "Converts to upper case", Version2, 0}, func(s interface{}) string { return strings.ToUpper(str(s)) }},
+	{Function{"lower", CategoryString, "lower S", "Converts to lower case", Version2, 0}, func(s interface{}) string { return strings.ToLower(str(s)) }},
+	{Function{"trim", CategoryString, "trim S", "Removes leading and trailing white space", Version2, 0}, func(s interface{}) string { return strings.TrimSpace(str(s)) }},
+	{Function{"trimPrefix", CategoryString, "trimPrefix PREFIX S", "Removes a leading prefix", Version2, 0}, func(prefix string, s interface{}) string { return strings.TrimPrefix(str(s), prefix) }},
+	{Function{"trimSuffix", CategoryString, "trimSuffix SUFFIX S", "Removes a trailing suffix", Version2, 0}, func(suffix string, s interface{}) string { return strings.TrimSuffix(str(s), suffix) }},
+	{Function{"replace", CategoryString, "replace OLD NEW S", "Replaces every occurrence of OLD by NEW", Version2, 0}, func(old, new string, s interface{}) string { return strings.ReplaceAll(str(s), old, new) }},
+	{Function{"contains", CategoryString, "contains SUBSTR S", "Reports whether S contains SUBSTR", Version2, 0}, func(substr string, s interface{}) bool { return strings.Contains(str(s), substr) }},
+	{Function{"hasPrefix", CategoryString, "hasPrefix PREFIX S", "Reports whether S starts with PREFIX", Version2, 0}, func(prefix string, s interface{}) bool { return strings.HasPrefix(str(s), prefix) }},
+	{Function{"hasSuffix", CategoryString, "hasSuffix SUFFIX S", "Reports whether S ends with SUFFIX", Version2, 0}, func(suffix string, s interface{}) bool { return strings.HasSuffix(str(s), suffix) }},
+	{Function{"split", CategoryString, "split SEP S", "Splits S around SEP into a list", Version2, 0}, func(sep string, s interface{}) []string { return strings.Split(str(s), sep) }},
+	{Function{"join", CategoryString, "join SEP LIST", "Joins the items of a list with SEP", Version2, 0}, join},
+	{Function{"truncate", CategoryString, "truncate N S", "Keeps the first N characters", Version2, 0}, truncate},
+	{Function{"default", CategoryString, "default DEFAULT VALUE", "Returns DEFAULT when VALUE is missing, empty or zero", Version2, 0}, defaultValue},
+
+	{Function{"now", CategoryTime, "now", "Returns the current time in UTC", Version2, 0}, func() time.Time { return time.Now().UTC() }},
+	{Function{"toTime", CategoryTime, "toTime VALUE", "Reads a time from an RFC 3339 string or Unix seconds", Version2, 0}, toTime},
+	{Function{"formatTime", CategoryTime, "formatTime LAYOUT VALUE", "Formats a time in UTC with a Go layout such as 2006-01-02", Version2, 0}, formatTime},
+	{Function{"unixTime", CategoryTime, "unixTime VALUE", "Returns a time as Unix seconds", Version2, 0}, unixTime},
+
+	{Function{"sha256", CategoryHash, "sha256 S", "Returns the hex SHA-256 digest", Version2, 0}, func(s interface{}) string { sum := sha256.Sum256([]byte(str(s))); return hex.EncodeToString(sum[:]) }},
+	{Function{"sha512", CategoryHash, "sha512 S", "Returns the hex SHA-512 digest", Version2, 0}, func(s interface{}) string { sum := sha512.Sum512([]byte(str(s))); return hex.EncodeToString(sum[:]) }},
+	{Function{"hmacSHA256", CategoryHash, "hmacSHA256 KEY S", "Returns the hex HMAC-SHA256 of S under KEY", Version2, 0}, hmacSHA256},
+	{Function{"base64", CategoryHash, "base64 S", "Encodes in standard base64", Version2, 0}, func(s interface{}) string { return base64.StdEncoding.EncodeToString([]byte(str(s))) }},
+	{Function{"base64Decode", CategoryHash, "base64Decode S", "Decodes standard base64", Version2, 0}, base64Decode},
+
+	{Function{"fromJson", CategoryJSON, "fromJson S", "Decodes a JSON document", Version2, 0}, fromJSON},
+	{Function{"jsonPath", CategoryJSON, "jsonPath PATH VALUE", "Returns the value at a path such as $.items[0].sku in a map, list or JSON string; nothing when absent", Version2, 0}, jsonPath},
+
+	{Function{"regexMatch", CategoryRegex, "regexMatch PATTERN S", "Reports whether S matches the RE2 pattern", Version2, 0}, regexMatch},
+	{Function{"regexFind", CategoryRegex, "regexFind PATTERN S", "Returns the first match, or its first group when the pattern has groups", Version2, 0}, regexFind},
+	{Function{"regexFindAll", CategoryRegex, "regexFindAll PATTERN S", "Returns every match", Version2, 0}, regexFindAll},
+	{Function{"regexReplace", CategoryRegex, "regexReplace PATTERN REPL S", "Replaces the matches; REPL may refer to groups as $1", Version2, 0}, regexReplace},
+}
+
+// Funcs returns the functions of a version for template.Funcs
+func Funcs(version int) (map[string]interface{}, error) {
+	if err := check(version); err != nil {
+		return nil, err
+	}
+	funcs := make(map[string]interface{})
+	for _, e := range library {
+		if e.offered(version) {
+			funcs[e.Name] = e.fn
+		}
+	}
+	return funcs, nil
+}
+
+// Docs returns the documentation of the functions of a version by
+// category and name
+func Docs(version int) ([]Function, error) {
+	if err := check(version); err != nil {
+		return nil, err
+	}
+	out := []Function{}
+	for _, e := range library {
+		if e.offered(version) {
+			out = append(out, e.Function)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Category != out[j].Category {
+			return out[i].Category < out[j].Category
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out, nil
+}
+
+// Version reads the functions option of a template block; Version1 when
+// absent
+func Version(options map[string]interface{}) (int, error) {
+	v, exists := options["functions"]
+	if !exists {
+		return Version1, nil
+	}
+	version, err := strconv.Atoi(fmt.Sprint(v))
+	if err != nil {
+		return 0, fmt.Errorf("functions must be a version from %d to %d", Version1, Latest)
+	}
+	return version, check(version)
+}
+
+// offered reports whether a version offers the form of a function
+func (e entry) offered(version int) bool {
+	return e.Since <= version && (e.Until == 0 || version < e.Until)
+}
+
+// check rejects unknown versions
+func check(version int) error {
+	if version < Version1 || version > Latest {
+		return fmt.Errorf("unknown template functions version %d, expected %d to %d", version, Version1, Latest)
+	}
+	return nil
+}
+
+// str renders a value as functions read it: nil as empty and anything
+// else as printed
+func str(v interface{}) string {
+	switch s := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return s
+	case []byte:
+		return string(s)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func toJSON(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	return string(b), err
+}
+
+func fromJSON(s interface{}) (interface{}, error) {
+	var v interface{}
+	if err := json.Unmarshal([]byte(str(s)), &v); err != nil {
+		return nil, fmt.Errorf("fromJson: %w", err)
+	}
+	return v, nil
+}
+
+func join(sep string, list interface{}) string {
+	rv := reflect.ValueOf(list)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return str(list)
+	}
+	parts := make([]string, rv.Len())
+	for i := range parts {
+		parts[i] = str(rv.Index(i).Interface())
+	}
+	return strings.Join(parts, sep)
+}
+
+func truncate(n int, s interface{}) string {
+	text := str(s)
+	if n < 0 || utf8.RuneCountInString(text) <= n {
+		return text
+	}
+	return string([]rune(text)[:n])
+}
+
+func defaultValue(def, v interface{}) interface{} {
+	if v == nil {
+		return def
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Map, reflect.String:
+		if rv.Len() == 0 {
+			return def
+		}
+	default:
+		if rv.IsZero() {
+			return def
+		}
+	}
+	return v
+}
+
+func toTime(v interface{}) (time.Time, error) {
+	switch t := v.(type) {
+	case time.Time:
+		return t.UTC(), nil
+	case *time.Time:
+		if t != nil {
+			return t.UTC(), nil
+		}
+	case int, int64, float64, json.Number:
+		seconds, err := strconv.ParseFloat(str(t), 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("toTime: %w", err)
+		}
+		return time.Unix(0, int64(seconds*float64(time.Second))).UTC(), nil
+	case string:
+		parsed, err := time.Parse(time.RFC3339Nano, t)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("toTime: %w", err)
+		}
+		return parsed.UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("toTime: cannot read a time from %T", v)
+}
+
+func formatTime(layout string, v interface{}) (string, error) {
+	t, err := toTime(v)
+	if err != nil {
+		return "", err
+	}
+	return t.Format(layout), nil
+}
+
+func unixTime(v interface{}) (int64, error) {
+	t, err := toTime(v)
+	if err != nil {
+		return 0, err
+	}
+	return t.Unix(), nil
+}
+
+func hmacSHA256(key string, s interface{}) (string, error) {
+	if err := fips.Check(fips.KindMAC, "hmac-sha256"); err != nil {
+		return "", fmt.Errorf("hmacSHA256: %w", err)
+	}
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(str(s)))
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+func base64Decode(s interface{}) (string, error) {
+	b, err := base64.StdEncoding.DecodeString(str(s))
+	if err != nil {
+		return "", fmt.Errorf("base64Decode: %w", err)
+	}
+	return string(b), nil
+}
+
+// jsonPath walks a dotted path with [n] list indexes, optionally starting
+// at $, through decoded JSON; strings are decoded first
+func jsonPath(path string, v interface{}) (interface{}, error) {
+	if s, ok := v.(string); ok {
+		if err := json.Unmarshal([]byte(s), &v); err != nil {
+			return nil, fmt.Errorf("jsonPath: %w", err)
+		}
+	}
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	if path == "" {
+		return v, nil
+	}
+	for _, part := range strings.Split(strings.ReplaceAll(path, "[", ".["), ".") {
+		switch {
+		case part == "":
+			continue
+		case strings.HasPrefix(part, "[") && strings.HasSuffix(part, "]"):
+			i, err := strconv.Atoi(part[1 : len(part)-1])
+			if err != nil {
+				return nil, fmt.Errorf("jsonPath: invalid index %s in %s", part, path)
+			}
+			list, ok := v.([]interface{})
+			if !ok || i < 0 || i >= len(list) {
+				return nil, nil
+			}
+			v = list[i]
+		default:
+			m, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, nil
+			}
+			if v, ok = m[part]; !ok {
+				return nil, nil
+			}
+		}
+	}
+	return v, nil
+}
+
+// maxPatterns bounds the compiled patterns kept for reuse
+const maxPatterns = 256
+
+var (
+	patternsMu sync.Mutex
+	patterns   = make(map[string]*regexp.Regexp)
+)
+
+// compile returns a compiled pattern, reusing earlier compilations
+func compile(pattern string) (*regexp.Regexp, error) {
+	patternsMu.Lock()
+	defer patternsMu.Unlock()
+	if re, ok := patterns[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	if len(patterns) < maxPatterns {
+		patterns[pattern] = re
+	}
+	return re, nil
+}
+
+func regexMatch(pattern string, s interface{}) (bool, error) {
+	re, err := compile(pattern)
+	if err != nil {
+		return false, err
+	}
+	return re.MatchString(str(s)), nil
+}
+
+func regexFind(pattern string, s interface{}) (string, error) {
+	re, err := compile(pattern)
+	if err != nil {
+		return "", err
+	}
+	m := re.FindStringSubmatch(str(s))
+	switch {
+	case m == nil:
+		return "", nil
+	case len(m) > 1:
+		return m[1], nil
+	default:
+		return m[0], nil
+	}
+}
+
+func regexFindAll(pattern string, s interface{}) ([]string, error) {
+	re, err := compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return re.FindAllString(str(s), -1), nil
+}
+
+func regexReplace(pattern, repl string, s interface{}) (string, error) {
+	re, err := compile(pattern)
+	if err != nil {
+		return "", err
+	}
+	return re.ReplaceAllString(str(s), repl), nil
+}
@@ -0,0 +1,540 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: avro-codec
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Avro Binary Codec
+ */
+
+package transform
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// errAvroShort is returned when Avro data ends inside a value
+var errAvroShort = errors.New("avro data is truncated")
+
+// avroSchema is a parsed Avro schema. Logical types are read and written as
+// their underlying type.
+type avroSchema struct {
+	kind      string
+	name      string
+	fields    []avroField
+	symbols   []string
+	items     *avroSchema
+	values    *avroSchema
+	size      int
+	branches  []*avroSchema
+	namespace string
+}
+
+// avroField is a field of a record schema
+type avroField struct {
+	name       string
+	schema     *avroSchema
+	def        interface{}
+	hasDefault bool
+}
+
+// parseAvroSchema parses an Avro schema in its JSON form
+func parseAvroSchema(text string) (*avroSchema, error) {
+	var raw interface{}
+	if err := json.Unmarshal([]byte(text), &raw); err != nil {
+		// A bare primitive type name is a valid schema too
+		raw = strings.Trim(strings.TrimSpace(text), `"`)
+	}
+	return (&avroParser{named: make(map[string]*avroSchema)}).parse(raw, "")
+}
+
+// avroParser resolves named type references while parsing
+type avroParser struct {
+	named map[string]*avroSchema
+}
+
+// fullName qualifies a type name with the enclosing namespace
+func fullName(name, namespace string) string {
+	if strings.Contains(name, ".") || namespace == "" {
+		return name
+	}
+	return namespace + "." + name
+}
+
+func (p *avroParser) parse(raw interface{}, namespace string) (*avroSchema, error) {
+	switch v := raw.(type) {
+	case string:
+		switch v {
+		case "null", "boolean", "int", "long", "float", "double", "bytes", "string":
+			return &avroSchema{kind: v}, nil
+		}
+		if s, ok := p.named[fullName(v, namespace)]; ok {
+			return s, nil
+		}
+		if s, ok := p.named[v]; ok {
+			return s, nil
+		}
+		return nil, fmt.Errorf("unknown avro type %q", v)
+	case []interface{}:
+		s := &avroSchema{kind: "union"}
+		for _, b := range v {
+			branch, err := p.parse(b, namespace)
+			if err != nil {
+				return nil, err
+			}
+			s.branches = append(s.branches, branch)
+		}
+		return s, nil
+	case map[string]interface{}:
+		return p.parseComplex(v, namespace)
+	default:
+		return nil, fmt.Errorf("invalid avro schema %v", raw)
+	}
+}
+
+func (p *avroParser) parseComplex(v map[string]interface{}, namespace string) (*avroSchema, error) {
+	kind, _ := v["type"].(string)
+	if ns, ok := v["namespace"].(string); ok {
+		namespace = ns
+	}
+	name, _ := v["name"].(string)
+	s := &avroSchema{kind: kind, name: fullName(name, namespace)}
+	if i := strings.LastIndex(s.name, "."); i >= 0 {
+		s.namespace = s.name[:i]
+	}
+
+	switch kind {
+	case "record", "error", "enum", "fixed":
+		if name == "" {
+			return nil, fmt.Errorf("avro %s without a name", kind)
+		}
+		p.named[s.name] = s
+	}
+	switch kind {
+	case "record", "error":
+		s.kind = "record"
+		fields, _ := v["fields"].([]interface{})
+		for _, f := range fields {
+			fm, ok := f.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("invalid field in avro record %s", s.name)
+			}
+			fieldName, _ := fm["name"].(string)
+			schema, err := p.parse(fm["type"], s.namespace)
+			if err != nil {
+				return nil, fmt.Errorf("field %s.%s: %w", s.name, fieldName, err)
+			}
+			def, hasDefault := fm["default"]
+			s.fields = append(s.fields, avroField{name: fieldName, schema: schema, def: def, hasDefault: hasDefault})
+		}
+	case "enum":
+		symbols, _ := v["symbols"].([]interface{})
+		for _, sym := range symbols {
+			s.symbols = append(s.symbols, fmt.Sprint(sym))
+		}
+	case "fixed":
+		size, ok := toFloat(v["size"])
+		if !ok || size < 0 {
+			return nil, fmt.Errorf("avro fixed %s without a size", s.name)
+		}
+		s.size = int(size)
+	case "array":
+		items, err := p.parse(v["items"], namespace)
+		if err != nil {
+			return nil, err
+		}
+		s.items = items
+	case "map":
+		values, err := p.parse(v["values"], namespace)
+		if err != nil {
+			return nil, err
+		}
+		s.values = values
+	default:
+		// A primitive, possibly annotated with a logical type
+		return p.parse(v["type"], namespace)
+	}
+	return s, nil
+}
+
+// avroReader decodes Avro binary data
+type avroReader struct {
+	data []byte
+}
+
+func (r *avroReader) long() (int64, error) {
+	v, n := binary.Varint(r.data)
+	if n <= 0 {
+		return 0, errAvroShort
+	}
+	r.data = r.data[n:]
+	return v, nil
+}
+
+func (r *avroReader) bytes(n int) ([]byte, error) {
+	if n < 0 || n > len(r.data) {
+		return nil, errAvroShort
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b, nil
+}
+
+// decode reads one value of the schema. Bytes and fixed values are read as
+// strings of code points 0-255, as in the Avro JSON encoding.
+func (r *avroReader) decode(s *avroSchema) (interface{}, error) {
+	switch s.kind {
+	case "null":
+		return nil, nil
+	case "boolean":
+		b, err := r.bytes(1)
+		if err != nil {
+			return nil, err
+		}
+		return b[0] != 0, nil
+	case "int", "long":
+		return r.long()
+	case "float":
+		b, err := r.bytes(4)
+		if err != nil {
+			return nil, err
+		}
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(b))), nil
+	case "double":
+		b, err := r.bytes(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(b)), nil
+	case "bytes", "string":
+		n, err := r.long()
+		if err != nil {
+			return nil, err
+		}
+		b, err := r.bytes(int(n))
+		if err != nil {
+			return nil, err
+		}
+		if s.kind == "bytes" {
+			return latin1(b), nil
+		}
+		return string(b), nil
+	case "fixed":
+		b, err := r.bytes(s.size)
+		if err != nil {
+			return nil, err
+		}
+		return latin1(b), nil
+	case "enum":
+		i, err := r.long()
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 || int(i) >= len(s.symbols) {
+			return nil, fmt.Errorf("avro enum %s has no symbol %d", s.name, i)
+		}
+		return s.symbols[i], nil
+	case "union":
+		i, err := r.long()
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 || int(i) >= len(s.branches) {
+			return nil, fmt.Errorf("avro union has no branch %d", i)
+		}
+		return r.decode(s.branches[i])
+	case "record":
+		out := make(map[string]interface{}, len(s.fields))
+		for _, f := range s.fields {
+			v, err := r.decode(f.schema)
+			if err != nil {
+				return nil, err
+			}
+			out[f.name] = v
+		}
+		return out, nil
+	case "array":
+		out := []interface{}{}
+		err := r.blocks(func() error {
+			v, err := r.decode(s.items)
+			out = append(out, v)
+			return err
+		})
+		return out, err
+	case "map":
+		out := map[string]interface{}{}
+		err := r.blocks(func() error {
+			key, err := r.decode(&avroSchema{kind: "string"})
+			if err != nil {
+				return err
+			}
+			out[key.(string)], err = r.decode(s.values)
+			return err
+		})
+		return out, err
+	default:
+		return nil, fmt.Errorf("unsupported avro type %s", s.kind)
+	}
+}
+
+// blocks reads the blocks of an array or map
+func (r *avroReader) blocks(item func() error) error {
+	for {
+		n, err := r.long()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return nil
+		}
+		if n < 0 {
+			// A negative count is followed by the block size in bytes
+			n = -n
+			if _, err := r.long(); err != nil {
+				return err
+			}
+		}
+		if n > int64(len(r.data)) {
+			return errAvroShort
+		}
+		for ; n > 0; n-- {
+			if err := item(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// avroWriter encodes Avro binary data
+type avroWriter struct {
+	buf []byte
+}
+
+func (w *avroWriter) long(v int64) {
+	w.buf = binary.AppendVarint(w.buf, v)
+}
+
+// encode writes one value of the schema
+func (w *avroWriter) encode(s *avroSchema, v interface{}) error {
+	switch s.kind {
+	case "null":
+		if v != nil {
+			return fmt.Errorf("expected null, got %T", v)
+		}
+	case "boolean":
+		b, ok := v.(bool)
+		if !ok {
+			return fmt.Errorf("expected boolean, got %T", v)
+		}
+		if b {
+			w.buf = append(w.buf, 1)
+		} else {
+			w.buf = append(w.buf, 0)
+		}
+	case "int", "long":
+		n, ok := integral(v)
+		if !ok {
+			return fmt.Errorf("expected %s, got %v", s.kind, v)
+		}
+		w.long(n)
+	case "float", "double":
+		f, ok := number(v)
+		if !ok {
+			return fmt.Errorf("expected %s, got %T", s.kind, v)
+		}
+		if s.kind == "float" {
+			w.buf = binary.LittleEndian.AppendUint32(w.buf, math.Float32bits(float32(f)))
+		} else {
+			w.buf = binary.LittleEndian.AppendUint64(w.buf, math.Float64bits(f))
+		}
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("expected string, got %T", v)
+		}
+		w.long(int64(len(str)))
+		w.buf = append(w.buf, str...)
+	case "bytes", "fixed":
+		b, err := avroBytes(v)
+		if err != nil {
+			return err
+		}
+		if s.kind == "fixed" && len(b) != s.size {
+			return fmt.Errorf("expected %d bytes for fixed %s, got %d", s.size, s.name, len(b))
+		}
+		if s.kind == "bytes" {
+			w.long(int64(len(b)))
+		}
+		w.buf = append(w.buf, b...)
+	case "enum":
+		str, _ := v.(string)
+		for i, sym := range s.symbols {
+			if sym == str {
+				w.long(int64(i))
+				return nil
+			}
+		}
+		return fmt.Errorf("%v is not a symbol of enum %s", v, s.name)
+	case "union":
+		for i, b := range s.branches {
+			if avroMatches(b, v) {
+				w.long(int64(i))
+				return w.encode(b, v)
+			}
+		}
+		return fmt.Errorf("%v matches no branch of the union", v)
+	case "record":
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("expected record %s, got %T", s.name, v)
+		}
+		for _, f := range s.fields {
+			fv, exists := m[f.name]
+			if !exists && f.hasDefault {
+				fv = f.def
+			}
+			if err := w.encode(f.schema, fv); err != nil {
+				return fmt.Errorf("field %s: %w", f.name, err)
+			}
+		}
+	case "array":
+		items, ok := v.([]interface{})
+		if !ok {
+			return fmt.Errorf("expected array, got %T", v)
+		}
+		if len(items) > 0 {
+			w.long(int64(len(items)))
+			for _, item := range items {
+				if err := w.encode(s.items, item); err != nil {
+					return err
+				}
+			}
+		}
+		w.long(0)
+	case "map":
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("expected map, got %T", v)
+		}
+		if len(m) > 0 {
+			w.long(int64(len(m)))
+			for k, item := range m {
+				w.long(int64(len(k)))
+				w.buf = append(w.buf, k...)
+				if err := w.encode(s.values, item); err != nil {
+					return err
+				}
+			}
+		}
+		w.long(0)
+	default:
+		return fmt.Errorf("unsupported avro type %s", s.kind)
+	}
+	return nil
+}
+
+// avroMatches reports whether a value fits a union branch
+func avroMatches(s *avroSchema, v interface{}) bool {
+	switch s.kind {
+	case "null":
+		return v == nil
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "int", "long":
+		_, ok := integral(v)
+		return ok
+	case "float", "double":
+		_, ok := number(v)
+		return ok
+	case "string", "bytes", "fixed":
+		_, ok := v.(string)
+		if !ok {
+			_, ok = v.([]byte)
+		}
+		return ok
+	case "enum":
+		str, _ := v.(string)
+		for _, sym := range s.symbols {
+			if sym == str {
+				return true
+			}
+		}
+		return false
+	case "record", "map":
+		_, ok := v.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := v.([]interface{})
+		return ok
+	default:
+		return false
+	}
+}
+
+// integral converts a whole number value
+func integral(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return n, true
+	case int:
+		return int64(n), true
+	case json.Number:
+		i, err := n.Int64()
+		return i, err == nil
+	}
+	f, ok := toFloat(v)
+	if !ok || f != math.Trunc(f) || math.Abs(f) > 1<<63 {
+		return 0, false
+	}
+	return int64(f), true
+}
+
+// number converts a numeric value
+func number(v interface{}) (float64, bool) {
+	if n, ok := v.(json.Number); ok {
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return toFloat(v)
+}
+
+// avroBytes reads a bytes value written as a string of code points 0-255
+func avroBytes(v interface{}) ([]byte, error) {
+	switch b := v.(type) {
+	case []byte:
+		return b, nil
+	case string:
+		out := make([]byte, 0, len(b))
+		for _, c := range b {
+			if c > 0xff {
+				return nil, fmt.Errorf("bytes value has code point %U above 0xFF", c)
+			}
+			out = append(out, byte(c))
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("expected bytes, got %T", v)
+	}
+}
+
+// latin1 returns bytes as a string of code points 0-255
+func latin1(b []byte) string {
+	runes := make([]rune, len(b))
+	for i, c := range b {
+		runes[i] = rune(c)
+	}
+	return string(runes)
+}
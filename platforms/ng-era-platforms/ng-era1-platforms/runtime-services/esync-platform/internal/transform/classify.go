@@ -0,0 +1,210 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: pii-classifier
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * PII Classification Stage
+ */
+
+package transform
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+)
+
+// PII categories detected by the classify_pii stage
+const (
+	PIIEmail      = "email"
+	PIIPhone      = "phone"
+	PIINationalID = "national_id"
+	PIICardNumber = "card_number"
+)
+
+// piiPatterns match whole string values of each category
+var piiPatterns = map[string]*regexp.Regexp{
+	PIIEmail: regexp.MustCompile(`^[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}$`),
+	PIIPhone: regexp.MustCompile(`^\+?[0-9]{0,3}[ .-]?\(?[0-9]{2,4}\)?[ .-]?[0-9]{3,4}[ .-]?[0-9]{3,4}$`),
+	// US social security and UK national insurance numbers
+	PIINationalID: regexp.MustCompile(`^(?:[0-9]{3}-[0-9]{2}-[0-9]{4}|[A-CEGHJ-PR-TW-Z]{2} ?[0-9]{2} ?[0-9]{2} ?[0-9]{2} ?[A-D])$`),
+	PIICardNumber: regexp.MustCompile(`^[0-9](?:[ -]?[0-9]){12,18}$`),
+}
+
+// Findings receives the fields classify_pii stages sampled and the PII
+// categories their values matched. A Metrics passed to Build that also
+// implements Findings receives them.
+type Findings interface {
+	Observe(field string, categories []string)
+}
+
+// Classifying is implemented by stages reporting PII findings
+type Classifying interface {
+	Classify(findings Findings)
+}
+
+// noopFindings discards findings, e.g. when a chain is only validated
+type noopFindings struct{}
+
+func (noopFindings) Observe(string, []string) {}
+
+// classifyStage samples records and flags string fields whose values look
+// like personal data, without changing them: {type: classify_pii,
+// sample_rate: 0.1, fields: [email, phone], categories: [email], fail: false}.
+// With fail set a matching value fails the run, so a classifier placed
+// after masking transforms enforces that they cover every PII field.
+type classifyStage struct {
+	rate       float64
+	fields     map[string]bool
+	categories []string
+	fail       bool
+	findings   Findings
+}
+
+func newClassify(options map[string]interface{}) (Stage, error) {
+	s := &classifyStage{rate: 0.1, findings: noopFindings{}}
+	if v, ok := options["sample_rate"]; ok {
+		rate, ok := toFloat(v)
+		if !ok || rate <= 0 || rate > 1 {
+			return nil, fmt.Errorf("sample_rate must be a number in (0, 1]")
+		}
+		s.rate = rate
+	}
+	if _, ok := options["fields"]; ok {
+		fields, err := stringList(options, "fields")
+		if err != nil {
+			return nil, err
+		}
+		s.fields = make(map[string]bool, len(fields))
+		for _, field := range fields {
+			s.fields[field] = true
+		}
+	}
+	if _, ok := options["categories"]; ok {
+		categories, err := stringList(options, "categories")
+		if err != nil {
+			return nil, err
+		}
+		for _, category := range categories {
+			if piiPatterns[category] == nil {
+				return nil, fmt.Errorf("unknown category %s; use email, phone, national_id or card_number", category)
+			}
+		}
+		s.categories = categories
+	} else {
+		for category := range piiPatterns {
+			s.categories = append(s.categories, category)
+		}
+		sort.Strings(s.categories)
+	}
+	s.fail, _ = options["fail"].(bool)
+	return s, nil
+}
+
+// Classify implements Classifying
+func (s *classifyStage) Classify(findings Findings) {
+	s.findings = findings
+}
+
+// Apply classifies the string fields of sampled records and passes every
+// record through
+func (s *classifyStage) Apply(r connectors.Record) (connectors.Record, bool, error) {
+	if r.Operation == connectors.OperationDelete || !s.sampled(r.ID) {
+		return r, true, nil
+	}
+
+	for field, v := range r.Data {
+		value, ok := v.(string)
+		if !ok || value == "" || (s.fields != nil && !s.fields[field]) {
+			continue
+		}
+		matched := s.classify(strings.TrimSpace(value))
+		s.findings.Observe(field, matched)
+		if s.fail && len(matched) > 0 {
+			return r, false, fmt.Errorf("field %s holds unmasked %s", field, strings.Join(matched, ", "))
+		}
+	}
+	return r, true, nil
+}
+
+// sampled selects records by a hash of their ID, so repeated runs sample
+// the same records
+func (s *classifyStage) sampled(id string) bool {
+	if s.rate >= 1 {
+		return true
+	}
+	h := fnv.New32a()
+	h.Write([]byte(id))
+	return float64(h.Sum32()%10000) < s.rate*10000
+}
+
+// classify returns the categories a value matches
+func (s *classifyStage) classify(value string) []string {
+	var matched []string
+	for _, category := range s.categories {
+		if !piiPatterns[category].MatchString(value) {
+			continue
+		}
+		if category == PIICardNumber && !luhn(value) {
+			continue
+		}
+		if category == PIIPhone && (digits(value) < 7 || contains(matched, PIICardNumber)) {
+			continue
+		}
+		matched = append(matched, category)
+	}
+	return matched
+}
+
+// luhn validates the check digit of a card number
+func luhn(value string) bool {
+	sum, double := 0, false
+	for i := len(value) - 1; i >= 0; i-- {
+		c := value[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// contains reports whether list holds s
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// digits counts the digits of a value
+func digits(value string) int {
+	n := 0
+	for _, c := range value {
+		if c >= '0' && c <= '9' {
+			n++
+		}
+	}
+	return n
+}
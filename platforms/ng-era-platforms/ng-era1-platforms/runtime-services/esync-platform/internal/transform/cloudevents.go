@@ -0,0 +1,95 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: cloudevents-envelope
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * CloudEvents Envelope Stage
+ */
+
+package transform
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+)
+
+// Defaults of the cloudevents stage
+const (
+	defaultEventType   = "io.esync.record.{operation}"
+	defaultEventSource = "/esync"
+)
+
+// cloudEventsStage wraps records in CloudEvents 1.0 envelopes in the JSON
+// structured format, for webhook, Kafka and event bus targets: {type:
+// cloudevents, event_type: com.example.order.{operation}, source:
+// /shop/orders}. {operation} and {table} in event_type and source are
+// replaced by the record operation and table.
+//
+// The record data becomes the event data, the record ID its subject and
+// the record timestamp its time; the esyncoperation and esynctable
+// extension attributes carry the change. Event IDs derive from the record
+// ID and timestamp, so a retried write repeats the event ID and consumers
+// can deduplicate it. Wrapped records are inserts.
+type cloudEventsStage struct {
+	eventType string
+	source    string
+}
+
+func newCloudEvents(options map[string]interface{}) (Stage, error) {
+	s := &cloudEventsStage{eventType: defaultEventType, source: defaultEventSource}
+	for key, field := range map[string]*string{"event_type": &s.eventType, "source": &s.source} {
+		v, exists := options[key]
+		if !exists {
+			continue
+		}
+		value, ok := v.(string)
+		if !ok || value == "" {
+			return nil, fmt.Errorf("%s must be a non-empty string", key)
+		}
+		*field = value
+	}
+	return s, nil
+}
+
+// Apply wraps the record in an event
+func (s *cloudEventsStage) Apply(r connectors.Record) (connectors.Record, bool, error) {
+	expand := strings.NewReplacer("{operation}", r.Operation, "{table}", r.Table)
+	timestamp := r.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	id := fmt.Sprintf("%s-%d", r.ID, timestamp.UnixNano())
+	if r.Table != "" {
+		id = r.Table + "/" + id
+	}
+	event := map[string]interface{}{
+		"specversion":     "1.0",
+		"id":              id,
+		"source":          expand.Replace(s.source),
+		"type":            expand.Replace(s.eventType),
+		"subject":         r.ID,
+		"time":            timestamp.UTC().Format(time.RFC3339Nano),
+		"datacontenttype": "application/json",
+		"data":            copyData(r.Data),
+		"esyncoperation":  r.Operation,
+	}
+	if r.Table != "" {
+		event["esynctable"] = r.Table
+	}
+
+	r.Operation = connectors.OperationInsert
+	r.Data = event
+	return r, true, nil
+}
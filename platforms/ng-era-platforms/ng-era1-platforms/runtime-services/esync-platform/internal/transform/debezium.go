@@ -0,0 +1,165 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: debezium-envelope
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Debezium Envelope Stage
+ */
+
+package transform
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/buildinfo"
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+)
+
+// Directions of the debezium stage
+const (
+	// DebeziumEmit wraps records in Debezium change event envelopes
+	DebeziumEmit = "emit"
+	// DebeziumConsume unwraps Debezium change events into records
+	DebeziumConsume = "consume"
+)
+
+// debeziumOps maps Debezium operation codes to record operations; snapshot
+// reads are inserts
+var debeziumOps = map[string]string{
+	"c": connectors.OperationInsert,
+	"r": connectors.OperationInsert,
+	"u": connectors.OperationUpdate,
+	"d": connectors.OperationDelete,
+}
+
+// debeziumStage converts between records and Debezium change events, the
+// before/after/op/ts_ms/source envelope Debezium connectors publish to
+// Kafka: {type: debezium, direction: consume} as the first stage reads
+// events from a Debezium topic, and {type: debezium, direction: emit,
+// name: orders} as the last stage writes events Debezium consumers read.
+//
+// Emitted events are inserts keyed by record ID, carrying the record as
+// after (before for deletes) and name as the logical server name of the
+// source block. Updates carry no before image, and patches carry only the
+// changed fields as after. Consumed events take their operation from op,
+// their data from after (before for deletes) and their timestamp from the
+// source block; truncate and message events and Kafka tombstones are
+// dropped. Events wrapped in a {schema, payload} JSON converter envelope
+// are read too.
+type debeziumStage struct {
+	direction string
+	name      string
+}
+
+func newDebezium(options map[string]interface{}) (Stage, error) {
+	direction, _ := options["direction"].(string)
+	if direction != DebeziumEmit && direction != DebeziumConsume {
+		return nil, fmt.Errorf("direction must be %s or %s", DebeziumEmit, DebeziumConsume)
+	}
+	name, _ := options["name"].(string)
+	if name == "" {
+		name = "esync"
+	}
+	return &debeziumStage{direction: direction, name: name}, nil
+}
+
+// Apply wraps or unwraps the record
+func (s *debeziumStage) Apply(r connectors.Record) (connectors.Record, bool, error) {
+	if s.direction == DebeziumEmit {
+		return s.emit(r), true, nil
+	}
+	return consumeDebezium(r)
+}
+
+// emit wraps a record in a change event
+func (s *debeziumStage) emit(r connectors.Record) connectors.Record {
+	op := "c"
+	var before, after interface{}
+	after = copyData(r.Data)
+	switch r.Operation {
+	case connectors.OperationUpdate, connectors.OperationPatch:
+		op = "u"
+	case connectors.OperationDelete:
+		op = "d"
+		before, after = after, nil
+	}
+
+	now := time.Now()
+	source := map[string]interface{}{
+		"version":   buildinfo.Version,
+		"connector": "esync",
+		"name":      s.name,
+		"ts_ms":     r.Timestamp.UnixMilli(),
+		"snapshot":  "false",
+	}
+	if r.Timestamp.IsZero() {
+		source["ts_ms"] = now.UnixMilli()
+	}
+	if r.Table != "" {
+		source["table"] = r.Table
+	}
+
+	r.Operation = connectors.OperationInsert
+	r.Data = map[string]interface{}{
+		"before": before,
+		"after":  after,
+		"op":     op,
+		"ts_ms":  now.UnixMilli(),
+		"source": source,
+	}
+	return r
+}
+
+// consumeDebezium unwraps a change event
+func consumeDebezium(r connectors.Record) (connectors.Record, bool, error) {
+	event := r.Data
+	if payload, ok := event["payload"].(map[string]interface{}); ok && event["schema"] != nil {
+		event = payload
+	}
+	if len(event) == 0 {
+		return r, false, nil
+	}
+
+	op, _ := event["op"].(string)
+	if op == "t" || op == "m" {
+		return r, false, nil
+	}
+	operation, ok := debeziumOps[op]
+	if !ok {
+		return r, false, fmt.Errorf("not a Debezium change event: unknown op %q", event["op"])
+	}
+
+	image := "after"
+	if operation == connectors.OperationDelete {
+		image = "before"
+	}
+	data, _ := event[image].(map[string]interface{})
+	if data == nil && operation != connectors.OperationDelete {
+		return r, false, fmt.Errorf("Debezium %s event has no %s image", op, image)
+	}
+
+	source, _ := event["source"].(map[string]interface{})
+	ts, ok := toFloat(source["ts_ms"])
+	if !ok {
+		ts, ok = toFloat(event["ts_ms"])
+	}
+	if ok {
+		r.Timestamp = time.UnixMilli(int64(ts)).UTC()
+	}
+	if table, _ := source["table"].(string); r.Table == "" && table != "" {
+		r.Table = table
+	}
+
+	r.Operation = operation
+	r.Data = copyData(data)
+	return r, true, nil
+}
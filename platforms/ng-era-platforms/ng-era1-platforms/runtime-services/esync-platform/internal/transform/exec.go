@@ -0,0 +1,459 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: exec-transform
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * External Process Transforms
+ */
+
+package transform
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+)
+
+// Defaults of exec stages
+const (
+	defaultExecTimeout   = 30 * time.Second
+	defaultExecBatchSize = 500
+	defaultExecMaxLine   = 16 << 20
+	// execIdleTimeout stops processes no batch reached for this long
+	execIdleTimeout = 10 * time.Minute
+)
+
+// Restart policies of exec stages
+const (
+	ExecRestartOnFailure = "on_failure"
+	ExecRestartNever     = "never"
+)
+
+// errExecStopped is returned once a process failed and may not restart
+var errExecStopped = errors.New("process failed and the restart policy is never")
+
+// execStage pipes records through a long-running external process:
+//
+//	{type: exec, command: /opt/transforms/enrich, args: [--strict],
+//	 timeout: 30, batch_size: 500, restart: on_failure, max_restarts: 5,
+//	 env: {MODE: prod}, inherit_env: false, dir: /var/lib/enrich,
+//	 max_line_bytes: 16777216}
+//
+// Records are written to the process stdin as NDJSON, one record per line.
+// For every line the process writes one line to stdout: the transformed
+// record, null to drop the record, or an array of records to replace it.
+// A batch failing to finish within timeout seconds, an exit or an invalid
+// line fails the batch and stops the process. With restart on_failure the
+// next batch starts it again, up to max_restarts times in a row without a
+// successful batch (0 is unlimited). With never, or once max_restarts is
+// reached, the stage keeps failing until the pipeline definition changes.
+//
+// The process runs with only PATH and env in its environment unless
+// inherit_env is set, in dir or else a private temporary directory, and
+// its stderr goes to the daemon log. Stages with the same options share
+// one process, which is stopped after being idle for ten minutes.
+type execStage struct {
+	proc      *execProcess
+	batchSize int
+}
+
+// execProcess is a running transform process shared by exec stages with the
+// same options. Batches are serialized.
+type execProcess struct {
+	command     string
+	args        []string
+	env         map[string]string
+	inheritEnv  bool
+	dir         string
+	timeout     time.Duration
+	restart     string
+	maxRestarts int
+	maxLine     int
+
+	mu       sync.Mutex
+	cmd      *exec.Cmd
+	stdin    io.WriteCloser
+	stdout   *bufio.Reader
+	tempDir  string
+	failures int
+	stopped  bool
+	idle     *time.Timer
+}
+
+var (
+	execProcessesMu sync.Mutex
+	execProcesses   = make(map[string]*execProcess)
+)
+
+func newExec(options map[string]interface{}) (Stage, error) {
+	command, _ := options["command"].(string)
+	if command == "" {
+		return nil, fmt.Errorf("command is required")
+	}
+
+	proc := &execProcess{
+		command: command,
+		timeout: defaultExecTimeout,
+		restart: ExecRestartOnFailure,
+		maxLine: defaultExecMaxLine,
+	}
+	stage := &execStage{batchSize: defaultExecBatchSize}
+	var err error
+	if _, ok := options["args"]; ok {
+		if proc.args, err = stringList(options, "args"); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := options["env"]; ok {
+		if proc.env, err = stringMap(options, "env"); err != nil {
+			return nil, err
+		}
+	}
+	if v, ok := options["inherit_env"]; ok {
+		if proc.inheritEnv, ok = v.(bool); !ok {
+			return nil, fmt.Errorf("inherit_env must be a boolean")
+		}
+	}
+	if v, ok := options["dir"]; ok {
+		if proc.dir, ok = v.(string); !ok || proc.dir == "" {
+			return nil, fmt.Errorf("dir must be a path")
+		}
+	}
+	if v, ok := options["restart"]; ok {
+		proc.restart, _ = v.(string)
+		if proc.restart != ExecRestartOnFailure && proc.restart != ExecRestartNever {
+			return nil, fmt.Errorf("restart must be %s or %s", ExecRestartOnFailure, ExecRestartNever)
+		}
+	}
+	seconds, err := nonNegativeInt(options, "timeout")
+	if err != nil {
+		return nil, err
+	}
+	if seconds > 0 {
+		proc.timeout = time.Duration(seconds) * time.Second
+	}
+	if proc.maxRestarts, err = nonNegativeInt(options, "max_restarts"); err != nil {
+		return nil, err
+	}
+	if n, err := nonNegativeInt(options, "batch_size"); err != nil {
+		return nil, err
+	} else if n > 0 {
+		stage.batchSize = n
+	}
+	if n, err := nonNegativeInt(options, "max_line_bytes"); err != nil {
+		return nil, err
+	} else if n > 0 {
+		proc.maxLine = n
+	}
+
+	key, err := json.Marshal([]interface{}{command, proc.args, proc.env, proc.inheritEnv, proc.dir,
+		proc.timeout, proc.restart, proc.maxRestarts, proc.maxLine})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode exec options: %w", err)
+	}
+	execProcessesMu.Lock()
+	defer execProcessesMu.Unlock()
+	if existing, exists := execProcesses[string(key)]; exists {
+		proc = existing
+	} else {
+		execProcesses[string(key)] = proc
+	}
+	stage.proc = proc
+	return stage, nil
+}
+
+// nonNegativeInt reads an optional non-negative integer option; absent
+// options are zero
+func nonNegativeInt(options map[string]interface{}, key string) (int, error) {
+	v, ok := options[key]
+	if !ok {
+		return 0, nil
+	}
+	n, ok := toFloat(v)
+	if !ok || n < 0 || n != float64(int(n)) {
+		return 0, fmt.Errorf("%s must be a non-negative integer", key)
+	}
+	return int(n), nil
+}
+
+// Apply transforms a single record through the process
+func (s *execStage) Apply(r connectors.Record) (connectors.Record, bool, error) {
+	out, err := s.ApplyBatch(context.Background(), []connectors.Record{r})
+	if err != nil || len(out) == 0 {
+		return r, false, err
+	}
+	if len(out) > 1 {
+		return r, false, fmt.Errorf("exec transform returned %d records for one", len(out))
+	}
+	return out[0], true, nil
+}
+
+// ApplyBatch implements BatchStage, sending the records in batches of the
+// configured size
+func (s *execStage) ApplyBatch(ctx context.Context, records []connectors.Record) ([]connectors.Record, error) {
+	out := make([]connectors.Record, 0, len(records))
+	for start := 0; start < len(records); start += s.batchSize {
+		end := start + s.batchSize
+		if end > len(records) {
+			end = len(records)
+		}
+		transformed, err := s.proc.transform(ctx, records[start:end])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, transformed...)
+	}
+	return out, nil
+}
+
+// transform exchanges one batch with the process, starting it first when
+// needed
+func (p *execProcess) transform(ctx context.Context, records []connectors.Record) ([]connectors.Record, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.ensureStarted(); err != nil {
+		return nil, err
+	}
+	out, err := p.exchange(ctx, records)
+	if err != nil {
+		p.failures++
+		p.stop()
+		if p.restart == ExecRestartNever {
+			p.stopped = true
+		}
+		return nil, fmt.Errorf("exec transform %s: %w", p.command, err)
+	}
+	p.failures = 0
+	p.idle.Reset(execIdleTimeout)
+	return out, nil
+}
+
+// ensureStarted starts the process unless it is running or its restart
+// policy forbids it. Callers hold p.mu.
+func (p *execProcess) ensureStarted() error {
+	if p.cmd != nil {
+		return nil
+	}
+	if p.stopped {
+		return fmt.Errorf("exec transform %s: %w", p.command, errExecStopped)
+	}
+	if p.maxRestarts > 0 && p.failures > p.maxRestarts {
+		return fmt.Errorf("exec transform %s failed %d times in a row, giving up", p.command, p.failures)
+	}
+
+	dir := p.dir
+	if dir == "" {
+		tempDir, err := os.MkdirTemp("", "esync-exec-")
+		if err != nil {
+			return fmt.Errorf("failed to create exec transform directory: %w", err)
+		}
+		p.tempDir, dir = tempDir, tempDir
+	}
+
+	cmd := exec.Command(p.command, p.args...)
+	cmd.Dir = dir
+	cmd.Env = p.environ()
+	cmd.Stderr = log.Writer()
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		p.removeTempDir()
+		return fmt.Errorf("failed to open exec transform stdin: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		p.removeTempDir()
+		return fmt.Errorf("failed to open exec transform stdout: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		p.removeTempDir()
+		p.failures++
+		return fmt.Errorf("failed to start exec transform %s: %w", p.command, err)
+	}
+	p.cmd, p.stdin, p.stdout = cmd, stdin, bufio.NewReader(stdout)
+	if p.idle == nil {
+		p.idle = time.AfterFunc(execIdleTimeout, p.stopIdle)
+	} else {
+		p.idle.Reset(execIdleTimeout)
+	}
+
+	log.Printf("[Transform] Started exec transform %s", p.command)
+	return nil
+}
+
+// environ returns the environment of the process
+func (p *execProcess) environ() []string {
+	var env []string
+	if p.inheritEnv {
+		env = os.Environ()
+	} else if path, ok := os.LookupEnv("PATH"); ok {
+		env = []string{"PATH=" + path}
+	}
+	keys := make([]string, 0, len(p.env))
+	for k := range p.env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		env = append(env, k+"="+p.env[k])
+	}
+	return env
+}
+
+// exchange writes a batch and reads one line back per record. Callers hold
+// p.mu.
+func (p *execProcess) exchange(ctx context.Context, records []connectors.Record) ([]connectors.Record, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			return nil, fmt.Errorf("failed to encode record %s: %w", r.ID, err)
+		}
+	}
+
+	type reply struct {
+		lines [][]byte
+		err   error
+	}
+	stdin, stdout := p.stdin, p.stdout
+	done := make(chan reply, 1)
+	go func() {
+		writeErr := make(chan error, 1)
+		go func() {
+			_, err := stdin.Write(buf.Bytes())
+			writeErr <- err
+		}()
+		lines := make([][]byte, 0, len(records))
+		for range records {
+			line, err := p.readLine(stdout)
+			if err != nil {
+				done <- reply{err: err}
+				return
+			}
+			lines = append(lines, line)
+		}
+		done <- reply{lines: lines, err: <-writeErr}
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	var r reply
+	select {
+	case r = <-done:
+	case <-ctx.Done():
+		p.stop()
+		<-done
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("batch of %d records timed out after %s", len(records), p.timeout)
+		}
+		return nil, ctx.Err()
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	out := make([]connectors.Record, 0, len(records))
+	for i, line := range r.lines {
+		transformed, err := decodeOutputLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid output for record %s: %w", records[i].ID, err)
+		}
+		out = append(out, transformed...)
+	}
+	return out, nil
+}
+
+// readLine reads one output line, rejecting lines longer than the limit
+func (p *execProcess) readLine(r *bufio.Reader) ([]byte, error) {
+	var line []byte
+	for {
+		chunk, isPrefix, err := r.ReadLine()
+		if err != nil {
+			return nil, err
+		}
+		line = append(line, chunk...)
+		if len(line) > p.maxLine {
+			return nil, fmt.Errorf("output line longer than %d bytes", p.maxLine)
+		}
+		if !isPrefix {
+			return line, nil
+		}
+	}
+}
+
+// decodeOutputLine decodes one output line of an exec or wasm transform: a
+// record, null or an array of records
+func decodeOutputLine(line []byte) ([]connectors.Record, error) {
+	line = bytes.TrimSpace(line)
+	switch {
+	case bytes.Equal(line, []byte("null")):
+		return nil, nil
+	case len(line) > 0 && line[0] == '[':
+		var records []connectors.Record
+		if err := json.Unmarshal(line, &records); err != nil {
+			return nil, err
+		}
+		return records, nil
+	default:
+		var record connectors.Record
+		if err := json.Unmarshal(line, &record); err != nil {
+			return nil, err
+		}
+		return []connectors.Record{record}, nil
+	}
+}
+
+// stopIdle stops a process no batch reached within the idle timeout
+func (p *execProcess) stopIdle() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cmd != nil {
+		log.Printf("[Transform] Stopping idle exec transform %s", p.command)
+		p.stop()
+	}
+}
+
+// stop kills the process and removes its temporary directory. Callers hold
+// p.mu.
+func (p *execProcess) stop() {
+	if p.cmd == nil {
+		return
+	}
+	p.stdin.Close()
+	if p.cmd.Process != nil {
+		p.cmd.Process.Kill()
+	}
+	p.cmd.Wait()
+	p.cmd, p.stdin, p.stdout = nil, nil, nil
+	p.removeTempDir()
+}
+
+// removeTempDir removes the private directory of the process. Callers hold
+// p.mu.
+func (p *execProcess) removeTempDir() {
+	if p.tempDir != "" {
+		os.RemoveAll(p.tempDir)
+		p.tempDir = ""
+	}
+}
@@ -0,0 +1,55 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: transform-fuzzing
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Transform Fuzz Targets
+ */
+
+package transform
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// FuzzTransform builds transform chains from arbitrary JSON specs and
+// applies them to arbitrary records; exec and wasm stages are skipped
+func FuzzTransform(f *testing.F) {
+	f.Fuzz(func(t *testing.T, specs, record []byte) {
+		var s []registry.TransformSpec
+		if json.Unmarshal(specs, &s) != nil {
+			return
+		}
+		for _, spec := range s {
+			// exec and wasm stages would run arbitrary programs and
+			// schema_registry stages call a registry
+			if spec.Type == "exec" || spec.Type == "wasm" || spec.Type == "schema_registry" {
+				return
+			}
+		}
+		chain, err := Build(s, nil)
+		if err != nil {
+			return
+		}
+		var r connectors.Record
+		if json.Unmarshal(record, &r) != nil {
+			return
+		}
+		out, err := chain.Apply([]connectors.Record{r})
+		if err == nil && len(out) > 1 {
+			t.Fatalf("chain turned one record into %d", len(out))
+		}
+	})
+}
@@ -0,0 +1,201 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: protobuf-codec
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Protobuf Registry Codec
+ */
+
+package transform
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// protoFiles resolves the imports of registry schemas, falling back to the
+// well-known types linked into the binary
+type protoFiles struct {
+	*protoregistry.Files
+}
+
+func newProtoFiles() protoFiles {
+	return protoFiles{new(protoregistry.Files)}
+}
+
+// FindFileByPath implements protodesc.Resolver
+func (f protoFiles) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
+	if fd, err := f.Files.FindFileByPath(path); err == nil {
+		return fd, nil
+	}
+	return protoregistry.GlobalFiles.FindFileByPath(path)
+}
+
+// FindDescriptorByName implements protodesc.Resolver
+func (f protoFiles) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	if d, err := f.Files.FindDescriptorByName(name); err == nil {
+		return d, nil
+	}
+	return protoregistry.GlobalFiles.FindDescriptorByName(name)
+}
+
+// protoFile builds the file descriptor of a serialized registry schema,
+// fetching the schemas it references first
+func (c *registryClient) protoFile(ctx context.Context, name string, resp registryResponse, files protoFiles) (protoreflect.FileDescriptor, error) {
+	for _, ref := range resp.References {
+		if _, err := files.FindFileByPath(ref.Name); err == nil {
+			continue
+		}
+		var dep registryResponse
+		path := fmt.Sprintf("/subjects/%s/versions/%d?format=serialized", url.PathEscape(ref.Subject), ref.Version)
+		if err := c.call(ctx, http.MethodGet, path, nil, &dep); err != nil {
+			return nil, err
+		}
+		if _, err := c.protoFile(ctx, ref.Name, dep, files); err != nil {
+			return nil, fmt.Errorf("reference %s: %w", ref.Name, err)
+		}
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(resp.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("serialized schema is not base64: %w", err)
+	}
+	var fdp descriptorpb.FileDescriptorProto
+	if err := proto.Unmarshal(raw, &fdp); err != nil {
+		return nil, err
+	}
+	if fdp.GetName() == "" {
+		fdp.Name = proto.String(name)
+	}
+	fd, err := protodesc.NewFile(&fdp, files)
+	if err != nil {
+		return nil, err
+	}
+	if err := files.RegisterFile(fd); err != nil {
+		return nil, err
+	}
+	return fd, nil
+}
+
+// decodeProtobuf decodes a message following the message indexes of the
+// wire format into its JSON mapping
+func decodeProtobuf(fd protoreflect.FileDescriptor, data []byte) (interface{}, error) {
+	count, n := binary.Varint(data)
+	if n <= 0 {
+		return nil, fmt.Errorf("message indexes are truncated")
+	}
+	data = data[n:]
+	indexes := []int64{0}
+	if count > 0 {
+		indexes = indexes[:0]
+		for i := int64(0); i < count; i++ {
+			index, n := binary.Varint(data)
+			if n <= 0 {
+				return nil, fmt.Errorf("message indexes are truncated")
+			}
+			data = data[n:]
+			indexes = append(indexes, index)
+		}
+	}
+
+	messages := fd.Messages()
+	var md protoreflect.MessageDescriptor
+	for _, index := range indexes {
+		if index < 0 || int(index) >= messages.Len() {
+			return nil, fmt.Errorf("schema has no message at index %v", indexes)
+		}
+		md = messages.Get(int(index))
+		messages = md.Messages()
+	}
+
+	msg := dynamicpb.NewMessage(md)
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return nil, err
+	}
+	text, err := protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	var out map[string]interface{}
+	if err := json.Unmarshal(text, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// encodeProtobuf appends the message indexes and encoding of data as the
+// named message, or the first message of the schema
+func encodeProtobuf(buf []byte, fd protoreflect.FileDescriptor, name string, data map[string]interface{}) ([]byte, error) {
+	md := findMessage(fd.Messages(), protoreflect.FullName(name))
+	if md == nil {
+		return nil, fmt.Errorf("schema %s has no message %s", fd.Path(), name)
+	}
+
+	text, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	msg := dynamicpb.NewMessage(md)
+	if err := protojson.Unmarshal(text, msg); err != nil {
+		return nil, err
+	}
+
+	var indexes []int
+	for d := protoreflect.Descriptor(md); ; d = d.Parent() {
+		if _, ok := d.(protoreflect.FileDescriptor); ok {
+			break
+		}
+		indexes = append([]int{d.Index()}, indexes...)
+	}
+	if len(indexes) == 1 && indexes[0] == 0 {
+		buf = append(buf, 0)
+	} else {
+		buf = binary.AppendVarint(buf, int64(len(indexes)))
+		for _, index := range indexes {
+			buf = binary.AppendVarint(buf, int64(index))
+		}
+	}
+	return proto.MarshalOptions{}.MarshalAppend(buf, msg)
+}
+
+// findMessage looks a message up by full or short name, returning the
+// first message for an empty name
+func findMessage(messages protoreflect.MessageDescriptors, name protoreflect.FullName) protoreflect.MessageDescriptor {
+	if name == "" {
+		if messages.Len() == 0 {
+			return nil
+		}
+		return messages.Get(0)
+	}
+	for i := 0; i < messages.Len(); i++ {
+		md := messages.Get(i)
+		if md.FullName() == name || md.Name() == protoreflect.Name(name) {
+			return md
+		}
+		if nested := findMessage(md.Messages(), name); nested != nil {
+			return nested
+		}
+	}
+	return nil
+}
@@ -0,0 +1,486 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: schema-registry
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Schema Registry Serialization Stage
+ */
+
+package transform
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/egress"
+)
+
+// Serialization formats of the schema_registry stage
+const (
+	FormatAvro     = "avro"
+	FormatProtobuf = "protobuf"
+)
+
+// Subject naming strategies of the schema_registry stage, as in the
+// Confluent serializers
+const (
+	SubjectTopic       = "topic"
+	SubjectRecord      = "record"
+	SubjectTopicRecord = "topic_record"
+)
+
+const (
+	defaultRegistryTimeout = 10 * time.Second
+	// latestSchemaTTL is how long the latest schema of a subject is reused
+	latestSchemaTTL     = 5 * time.Minute
+	registryContentType = "application/vnd.schemaregistry.v1+json"
+)
+
+// schemaRegistryStage decodes and encodes messages in the Confluent wire
+// format, a magic byte and schema ID followed by Avro or Protobuf data:
+//
+//	{type: schema_registry, direction: decode, url: http://registry:8081}
+//	{type: schema_registry, direction: encode, url: http://registry:8081,
+//	 format: avro, schema: '{"type": "record", ...}', auto_register: true,
+//	 subject_strategy: topic, topic: orders, field: value,
+//	 username: esync, password_env: REGISTRY_PASSWORD, timeout: 10}
+//
+// Kafka connectors carry messages as records whose field (value by default)
+// holds the message bytes, base64 encoded in JSON. Decoding looks up the
+// writer schema by ID and replaces the record data with the decoded
+// message; a null field, a tombstone, becomes a delete. Encoding writes the
+// record data as a message under field; deletes become tombstones.
+//
+// Subjects follow the naming strategy: topic uses <topic>-value (-key when
+// field is key), record the record or message name and topic_record both;
+// topic defaults to the record table. Encoding registers schema under the
+// subject with auto_register, looks it up otherwise, and uses the latest
+// version of the subject when no schema is set. Protobuf schemas are .proto
+// sources and message names the message to encode, by default the first.
+// Protobuf messages are mapped to and from records with the protobuf JSON
+// mapping.
+type schemaRegistryStage struct {
+	client       *registryClient
+	encode       bool
+	format       string
+	field        string
+	strategy     string
+	topic        string
+	schema       string
+	avro         *avroSchema
+	message      string
+	autoRegister bool
+}
+
+var (
+	registryClientsMu sync.Mutex
+	registryClients   = make(map[string]*registryClient)
+)
+
+func newSchemaRegistry(options map[string]interface{}) (Stage, error) {
+	s := &schemaRegistryStage{field: "value", strategy: SubjectTopic}
+	direction, _ := options["direction"].(string)
+	switch direction {
+	case "decode":
+	case "encode":
+		s.encode = true
+	default:
+		return nil, fmt.Errorf("direction must be decode or encode")
+	}
+
+	base, _ := options["url"].(string)
+	if u, err := url.Parse(base); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("url must be an http(s) URL")
+	}
+	if field, ok := options["field"].(string); ok && field != "" {
+		s.field = field
+	}
+	s.format, _ = options["format"].(string)
+	s.topic, _ = options["topic"].(string)
+	s.schema, _ = options["schema"].(string)
+	s.message, _ = options["message"].(string)
+	s.autoRegister, _ = options["auto_register"].(bool)
+	if strategy, ok := options["subject_strategy"].(string); ok && strategy != "" {
+		s.strategy = strategy
+	}
+
+	if s.encode {
+		if s.format != FormatAvro && s.format != FormatProtobuf {
+			return nil, fmt.Errorf("format must be %s or %s", FormatAvro, FormatProtobuf)
+		}
+		switch s.strategy {
+		case SubjectTopic, SubjectRecord, SubjectTopicRecord:
+		default:
+			return nil, fmt.Errorf("subject_strategy must be %s, %s or %s", SubjectTopic, SubjectRecord, SubjectTopicRecord)
+		}
+		if s.autoRegister && s.schema == "" {
+			return nil, fmt.Errorf("auto_register requires schema")
+		}
+		if s.format == FormatAvro && s.schema != "" {
+			schema, err := parseAvroSchema(s.schema)
+			if err != nil {
+				return nil, fmt.Errorf("invalid schema: %w", err)
+			}
+			s.avro = schema
+		}
+		if s.strategy != SubjectTopic && s.recordName() == "" {
+			return nil, fmt.Errorf("subject_strategy %s requires a named avro schema or a protobuf message", s.strategy)
+		}
+	}
+
+	timeout := defaultRegistryTimeout
+	if v, ok := toFloat(options["timeout"]); ok && v > 0 {
+		timeout = time.Duration(v * float64(time.Second))
+	}
+	username, _ := options["username"].(string)
+	passwordEnv, _ := options["password_env"].(string)
+
+	key := strings.Join([]string{strings.TrimRight(base, "/"), username, passwordEnv, timeout.String()}, "\x00")
+	registryClientsMu.Lock()
+	defer registryClientsMu.Unlock()
+	s.client = registryClients[key]
+	if s.client == nil {
+		s.client = &registryClient{
+			base:        strings.TrimRight(base, "/"),
+			username:    username,
+			passwordEnv: passwordEnv,
+			http:        egress.Client(timeout),
+			byID:        make(map[int]*registrySchema),
+			ids:         make(map[string]int),
+			latest:      make(map[string]latestSchema),
+		}
+		registryClients[key] = s.client
+	}
+	return s, nil
+}
+
+// Apply decodes or encodes one record
+func (s *schemaRegistryStage) Apply(r connectors.Record) (connectors.Record, bool, error) {
+	out, err := s.ApplyBatch(context.Background(), []connectors.Record{r})
+	if err != nil || len(out) == 0 {
+		return r, false, err
+	}
+	return out[0], true, nil
+}
+
+// ApplyBatch decodes or encodes the records, looking schemas up in the
+// registry as needed
+func (s *schemaRegistryStage) ApplyBatch(ctx context.Context, records []connectors.Record) ([]connectors.Record, error) {
+	out := make([]connectors.Record, 0, len(records))
+	for _, r := range records {
+		var err error
+		if s.encode {
+			r, err = s.encodeRecord(ctx, r)
+		} else {
+			r, err = s.decodeRecord(ctx, r)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("record %s: %w", r.ID, err)
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+// decodeRecord replaces a message with its decoded data
+func (s *schemaRegistryStage) decodeRecord(ctx context.Context, r connectors.Record) (connectors.Record, error) {
+	var raw []byte
+	switch v := r.Data[s.field].(type) {
+	case nil:
+		r.Operation = connectors.OperationDelete
+		r.Data = copyData(r.Data)
+		delete(r.Data, s.field)
+		return r, nil
+	case []byte:
+		raw = v
+	case string:
+		var err error
+		if raw, err = base64.StdEncoding.DecodeString(v); err != nil {
+			return r, fmt.Errorf("field %s is not base64: %w", s.field, err)
+		}
+	default:
+		return r, fmt.Errorf("field %s holds %T, not message bytes", s.field, v)
+	}
+
+	if len(raw) < 5 || raw[0] != 0 {
+		return r, fmt.Errorf("field %s is not in the schema registry wire format", s.field)
+	}
+	schema, err := s.client.schemaByID(ctx, int(binary.BigEndian.Uint32(raw[1:5])))
+	if err != nil {
+		return r, err
+	}
+
+	var value interface{}
+	if schema.proto != nil {
+		value, err = decodeProtobuf(schema.proto, raw[5:])
+	} else {
+		value, err = (&avroReader{data: raw[5:]}).decode(schema.avro)
+	}
+	if err != nil {
+		return r, fmt.Errorf("failed to decode schema %d message: %w", schema.id, err)
+	}
+	if data, ok := value.(map[string]interface{}); ok {
+		r.Data = data
+	} else {
+		r.Data = map[string]interface{}{s.field: value}
+	}
+	return r, nil
+}
+
+// encodeRecord replaces the record data with its encoded message
+func (s *schemaRegistryStage) encodeRecord(ctx context.Context, r connectors.Record) (connectors.Record, error) {
+	if r.Operation == connectors.OperationDelete {
+		r.Data = map[string]interface{}{s.field: nil}
+		return r, nil
+	}
+
+	subject, err := s.subject(r)
+	if err != nil {
+		return r, err
+	}
+	schema, err := s.client.encodeSchema(ctx, subject, s.format, s.schema, s.autoRegister)
+	if err != nil {
+		return r, err
+	}
+
+	buf := []byte{0, 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(buf[1:], uint32(schema.id))
+	if schema.proto != nil {
+		buf, err = encodeProtobuf(buf, schema.proto, s.message, r.Data)
+	} else {
+		w := &avroWriter{buf: buf}
+		err = w.encode(schema.avro, r.Data)
+		buf = w.buf
+	}
+	if err != nil {
+		return r, fmt.Errorf("failed to encode with schema %d of %s: %w", schema.id, subject, err)
+	}
+	r.Data = map[string]interface{}{s.field: buf}
+	return r, nil
+}
+
+// subject names the subject of a record under the naming strategy
+func (s *schemaRegistryStage) subject(r connectors.Record) (string, error) {
+	if s.strategy == SubjectRecord {
+		return s.recordName(), nil
+	}
+	topic := s.topic
+	if topic == "" {
+		topic = r.Table
+	}
+	if topic == "" {
+		return "", fmt.Errorf("subject_strategy %s requires topic or a record table", s.strategy)
+	}
+	if s.strategy == SubjectTopicRecord {
+		return topic + "-" + s.recordName(), nil
+	}
+	if s.field == "key" {
+		return topic + "-key", nil
+	}
+	return topic + "-value", nil
+}
+
+// recordName is the fully qualified name of the encoded record or message
+func (s *schemaRegistryStage) recordName() string {
+	if s.format == FormatProtobuf {
+		return s.message
+	}
+	if s.avro != nil {
+		return s.avro.name
+	}
+	return ""
+}
+
+// registrySchema is a schema registered under an ID
+type registrySchema struct {
+	id    int
+	avro  *avroSchema
+	proto protoreflect.FileDescriptor
+}
+
+// latestSchema is the cached latest schema of a subject
+type latestSchema struct {
+	schema  *registrySchema
+	expires time.Time
+}
+
+// registryReference is a schema imported by a Protobuf schema
+type registryReference struct {
+	Name    string `json:"name"`
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+}
+
+// registryResponse is a schema returned by the registry
+type registryResponse struct {
+	ID         int                 `json:"id"`
+	Schema     string              `json:"schema"`
+	SchemaType string              `json:"schemaType"`
+	References []registryReference `json:"references"`
+}
+
+// registryClient talks to a Confluent-compatible schema registry, caching
+// schemas by ID and subject
+type registryClient struct {
+	base        string
+	username    string
+	passwordEnv string
+	http        *http.Client
+
+	mu     sync.Mutex
+	byID   map[int]*registrySchema
+	ids    map[string]int
+	latest map[string]latestSchema
+}
+
+// schemaByID returns the schema registered under id
+func (c *registryClient) schemaByID(ctx context.Context, id int) (*registrySchema, error) {
+	c.mu.Lock()
+	schema, exists := c.byID[id]
+	c.mu.Unlock()
+	if exists {
+		return schema, nil
+	}
+
+	path := fmt.Sprintf("/schemas/ids/%d", id)
+	var resp registryResponse
+	if err := c.call(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return nil, err
+	}
+	schema = &registrySchema{id: id}
+	switch strings.ToUpper(resp.SchemaType) {
+	case "", "AVRO":
+		avro, err := parseAvroSchema(resp.Schema)
+		if err != nil {
+			return nil, fmt.Errorf("invalid avro schema %d: %w", id, err)
+		}
+		schema.avro = avro
+	case "PROTOBUF":
+		// The serialized format returns the file descriptor instead of the
+		// .proto source
+		if err := c.call(ctx, http.MethodGet, path+"?format=serialized", nil, &resp); err != nil {
+			return nil, err
+		}
+		fd, err := c.protoFile(ctx, fmt.Sprintf("schema-%d.proto", id), resp, newProtoFiles())
+		if err != nil {
+			return nil, fmt.Errorf("invalid protobuf schema %d: %w", id, err)
+		}
+		schema.proto = fd
+	default:
+		return nil, fmt.Errorf("schema %d has unsupported type %s", id, resp.SchemaType)
+	}
+
+	c.mu.Lock()
+	c.byID[id] = schema
+	c.mu.Unlock()
+	return schema, nil
+}
+
+// encodeSchema returns the schema to encode messages of a subject with:
+// the given schema, registered or looked up, or else the latest version
+func (c *registryClient) encodeSchema(ctx context.Context, subject, format, text string, register bool) (*registrySchema, error) {
+	path := "/subjects/" + url.PathEscape(subject)
+	if text == "" {
+		c.mu.Lock()
+		latest, exists := c.latest[subject]
+		c.mu.Unlock()
+		if exists && time.Now().Before(latest.expires) {
+			return latest.schema, nil
+		}
+
+		var resp registryResponse
+		if err := c.call(ctx, http.MethodGet, path+"/versions/latest", nil, &resp); err != nil {
+			return nil, err
+		}
+		schema, err := c.schemaByID(ctx, resp.ID)
+		if err != nil {
+			return nil, err
+		}
+		c.mu.Lock()
+		c.latest[subject] = latestSchema{schema: schema, expires: time.Now().Add(latestSchemaTTL)}
+		c.mu.Unlock()
+		return schema, nil
+	}
+
+	key := subject + "\x00" + text
+	c.mu.Lock()
+	id, exists := c.ids[key]
+	c.mu.Unlock()
+	if !exists {
+		req := map[string]interface{}{"schema": text}
+		if format == FormatProtobuf {
+			req["schemaType"] = "PROTOBUF"
+		}
+		if register {
+			path += "/versions"
+		}
+		var resp registryResponse
+		if err := c.call(ctx, http.MethodPost, path, req, &resp); err != nil {
+			return nil, err
+		}
+		id = resp.ID
+		c.mu.Lock()
+		c.ids[key] = id
+		c.mu.Unlock()
+	}
+	return c.schemaByID(ctx, id)
+}
+
+// call sends a registry request, decoding the response into out
+func (c *registryClient) call(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.base+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", registryContentType)
+	if in != nil {
+		req.Header.Set("Content-Type", registryContentType)
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, os.Getenv(c.passwordEnv))
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("schema registry request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("schema registry %s %s returned %s: %s", method, path, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode schema registry response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,286 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: builtin-transforms
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Built-in Transform Stages
+ */
+
+package transform
+
+import (
+	"fmt"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+)
+
+// renameStage renames fields: {type: rename_fields, fields: {old: new}}
+type renameStage struct {
+	fields map[string]string
+}
+
+func newRename(options map[string]interface{}) (Stage, error) {
+	fields, err := stringMap(options, "fields")
+	if err != nil {
+		return nil, err
+	}
+	return &renameStage{fields: fields}, nil
+}
+
+// Apply renames the configured fields
+func (s *renameStage) Apply(r connectors.Record) (connectors.Record, bool, error) {
+	data := copyData(r.Data)
+	for from, to := range s.fields {
+		if v, exists := data[from]; exists {
+			delete(data, from)
+			data[to] = v
+		}
+	}
+	r.Data = data
+	return r, true, nil
+}
+
+// dropStage removes fields: {type: drop_fields, fields: [a, b]}
+type dropStage struct {
+	fields []string
+}
+
+func newDrop(options map[string]interface{}) (Stage, error) {
+	fields, err := stringList(options, "fields")
+	if err != nil {
+		return nil, err
+	}
+	return &dropStage{fields: fields}, nil
+}
+
+// Apply removes the configured fields
+func (s *dropStage) Apply(r connectors.Record) (connectors.Record, bool, error) {
+	data := copyData(r.Data)
+	for _, f := range s.fields {
+		delete(data, f)
+	}
+	r.Data = data
+	return r, true, nil
+}
+
+// setStage assigns constant values: {type: set_fields, values: {k: v}}
+type setStage struct {
+	values map[string]interface{}
+}
+
+func newSet(options map[string]interface{}) (Stage, error) {
+	values, ok := options["values"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("values must be a map")
+	}
+	return &setStage{values: values}, nil
+}
+
+// Apply assigns the configured values
+func (s *setStage) Apply(r connectors.Record) (connectors.Record, bool, error) {
+	data := copyData(r.Data)
+	for k, v := range s.values {
+		data[k] = v
+	}
+	r.Data = data
+	return r, true, nil
+}
+
+// filterStage keeps only records whose field equals one of the given values:
+// {type: filter, field: region, in: [eu, us]}. Deletes, and patches not
+// carrying the field, always pass so that changes are never lost. With count_dropped set, dropped records are
+// counted in that custom metric.
+type filterStage struct {
+	field        string
+	values       []interface{}
+	countDropped string
+	metrics      Metrics
+}
+
+func newFilter(options map[string]interface{}) (Stage, error) {
+	field, ok := options["field"].(string)
+	if !ok || field == "" {
+		return nil, fmt.Errorf("field is required")
+	}
+	values, ok := options["in"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("in must be a list")
+	}
+	countDropped, _ := options["count_dropped"].(string)
+	if countDropped != "" && !validMetricName(countDropped) {
+		return nil, fmt.Errorf("count_dropped must match [a-zA-Z_][a-zA-Z0-9_]*")
+	}
+	return &filterStage{field: field, values: values, countDropped: countDropped, metrics: noopMetrics{}}, nil
+}
+
+// Instrument implements Instrumented
+func (s *filterStage) Instrument(metrics Metrics) {
+	s.metrics = metrics
+}
+
+// Apply drops records that do not match
+func (s *filterStage) Apply(r connectors.Record) (connectors.Record, bool, error) {
+	unknown := r.Operation == connectors.OperationPatch && !r.Has(s.field)
+	if r.Operation == connectors.OperationDelete || unknown || s.matches(r) {
+		return r, true, nil
+	}
+
+	if s.countDropped != "" {
+		s.metrics.Count(s.countDropped, 1)
+	}
+	return r, false, nil
+}
+
+// matches reports whether the record field holds one of the values
+func (s *filterStage) matches(r connectors.Record) bool {
+	v := fmt.Sprint(r.Data[s.field])
+	for _, want := range s.values {
+		if fmt.Sprint(want) == v {
+			return true
+		}
+	}
+	return false
+}
+
+// stringMap reads a string-to-string map option
+func stringMap(options map[string]interface{}, key string) (map[string]string, error) {
+	raw, ok := options[key].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must be a map", key)
+	}
+
+	out := make(map[string]string, len(raw))
+	for k, v := range raw {
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s.%s must be a string", key, k)
+		}
+		out[k] = s
+	}
+	return out, nil
+}
+
+// stringList reads a list-of-strings option
+func stringList(options map[string]interface{}, key string) ([]string, error) {
+	raw, ok := options[key].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must be a list", key)
+	}
+
+	out := make([]string, 0, len(raw))
+	for i, v := range raw {
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s[%d] must be a string", key, i)
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// metricStage emits a custom metric for matching records without changing
+// them: {type: metric, name: orders_high_value, kind: counter, field: amount,
+// when: {field: amount_tier, in: [high]}}. Counters add field (or 1) per
+// record; gauges are set to field.
+type metricStage struct {
+	name    string
+	gauge   bool
+	field   string
+	when    *filterStage
+	metrics Metrics
+}
+
+func newMetric(options map[string]interface{}) (Stage, error) {
+	name, _ := options["name"].(string)
+	if !validMetricName(name) {
+		return nil, fmt.Errorf("name must match [a-zA-Z_][a-zA-Z0-9_]*")
+	}
+
+	s := &metricStage{name: name, metrics: noopMetrics{}}
+	switch kind, _ := options["kind"].(string); kind {
+	case "", "counter":
+	case "gauge":
+		s.gauge = true
+	default:
+		return nil, fmt.Errorf("kind must be counter or gauge")
+	}
+	s.field, _ = options["field"].(string)
+	if s.gauge && s.field == "" {
+		return nil, fmt.Errorf("gauges require a field")
+	}
+
+	if when, ok := options["when"].(map[string]interface{}); ok {
+		stage, err := newFilter(when)
+		if err != nil {
+			return nil, fmt.Errorf("when: %w", err)
+		}
+		s.when = stage.(*filterStage)
+	}
+	return s, nil
+}
+
+// Instrument implements Instrumented
+func (s *metricStage) Instrument(metrics Metrics) {
+	s.metrics = metrics
+}
+
+// Apply records the metric and passes the record through
+func (s *metricStage) Apply(r connectors.Record) (connectors.Record, bool, error) {
+	if s.when != nil && !s.when.matches(r) {
+		return r, true, nil
+	}
+
+	value := 1.0
+	if s.field != "" {
+		v, ok := toFloat(r.Data[s.field])
+		if !ok {
+			return r, true, nil
+		}
+		value = v
+	}
+
+	if s.gauge {
+		s.metrics.Gauge(s.name, value)
+	} else {
+		s.metrics.Count(s.name, value)
+	}
+	return r, true, nil
+}
+
+// validMetricName reports whether name is a valid Prometheus metric name
+func validMetricName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		letter := r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '_'
+		if !letter && (i == 0 || r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// toFloat converts a numeric record value
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
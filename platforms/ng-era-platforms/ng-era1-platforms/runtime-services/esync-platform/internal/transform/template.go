@@ -0,0 +1,87 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: template-stage
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Template Stage
+ */
+
+package transform
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"text/template"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/templatefuncs"
+)
+
+// templateStage sets fields to Go templates rendered over the record:
+// {type: template, functions: 2, fields: {full_name: '{{.Data.first}}
+// {{.Data.last}}'}}. Templates see .Table, .ID, .Operation, .Timestamp and
+// .Data as the record arrived, and the functions of the version of the
+// template function library functions names, json only when absent.
+// Deletes pass unchanged, as do patches, whose partial data could render
+// a wrong value.
+type templateStage struct {
+	fields []string
+	tmpls  map[string]*template.Template
+}
+
+func newTemplate(options map[string]interface{}) (Stage, error) {
+	fields, err := stringMap(options, "fields")
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("fields must not be empty")
+	}
+	version, err := templatefuncs.Version(options)
+	if err != nil {
+		return nil, err
+	}
+	funcs, err := templatefuncs.Funcs(version)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &templateStage{tmpls: make(map[string]*template.Template, len(fields))}
+	for field, text := range fields {
+		t, err := template.New(field).Funcs(funcs).Option("missingkey=zero").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template of %s: %w", field, err)
+		}
+		s.fields = append(s.fields, field)
+		s.tmpls[field] = t
+	}
+	sort.Strings(s.fields)
+	return s, nil
+}
+
+// Apply renders the templates into their fields
+func (s *templateStage) Apply(r connectors.Record) (connectors.Record, bool, error) {
+	if r.Operation == connectors.OperationDelete || r.Operation == connectors.OperationPatch {
+		return r, true, nil
+	}
+	data := copyData(r.Data)
+	var b bytes.Buffer
+	for _, field := range s.fields {
+		b.Reset()
+		if err := s.tmpls[field].Execute(&b, r); err != nil {
+			return r, false, fmt.Errorf("failed to render %s: %w", field, err)
+		}
+		data[field] = b.String()
+	}
+	r.Data = data
+	return r, true, nil
+}
@@ -0,0 +1,203 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: record-transforms
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Record Transform Chain
+ */
+
+package transform
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// Stage transforms a single record. Returning keep=false drops the record.
+type Stage interface {
+	Apply(record connectors.Record) (out connectors.Record, keep bool, err error)
+}
+
+// BatchStage is implemented by stages transforming a whole batch at once,
+// such as stages handing records to an external process. The chain passes
+// it the records every earlier stage kept.
+type BatchStage interface {
+	Stage
+	ApplyBatch(ctx context.Context, records []connectors.Record) ([]connectors.Record, error)
+}
+
+// Metrics receives named business metrics emitted by stages; the engine
+// exports them as Prometheus metrics labelled with the pipeline ID
+type Metrics interface {
+	Count(name string, delta float64)
+	Gauge(name string, value float64)
+}
+
+// Instrumented is implemented by stages that emit custom metrics
+type Instrumented interface {
+	Instrument(metrics Metrics)
+}
+
+// noopMetrics discards metrics, e.g. when a chain is only validated
+type noopMetrics struct{}
+
+func (noopMetrics) Count(string, float64) {}
+func (noopMetrics) Gauge(string, float64) {}
+
+// Factory builds a stage from its options in the pipeline spec
+type Factory func(options map[string]interface{}) (Stage, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = map[string]Factory{
+		"rename_fields":   newRename,
+		"drop_fields":     newDrop,
+		"set_fields":      newSet,
+		"filter":          newFilter,
+		"metric":          newMetric,
+		"classify_pii":    newClassify,
+		"exec":            newExec,
+		"wasm":            newWasm,
+		"debezium":        newDebezium,
+		"cloudevents":     newCloudEvents,
+		"schema_registry": newSchemaRegistry,
+		"template":        newTemplate,
+	}
+)
+
+// Register makes a transform type available to pipelines
+func Register(transformType string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	factories[transformType] = factory
+}
+
+// Types returns all registered transform types
+func Types() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	types := make([]string, 0, len(factories))
+	for t := range factories {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Chain applies stages in declaration order
+type Chain []Stage
+
+// Build creates the transform chain declared by a pipeline. Stages emitting
+// custom metrics report them to metrics, which may be nil; classifier
+// findings go to metrics too when it implements Findings.
+func Build(specs []registry.TransformSpec, metrics Metrics) (Chain, error) {
+	if metrics == nil {
+		metrics = noopMetrics{}
+	}
+
+	chain := make(Chain, 0, len(specs))
+	for i, spec := range specs {
+		factoriesMu.RLock()
+		factory, exists := factories[spec.Type]
+		factoriesMu.RUnlock()
+		if !exists {
+			return nil, fmt.Errorf("transform %d: unknown type %s", i+1, spec.Type)
+		}
+
+		stage, err := factory(spec.Options)
+		if err != nil {
+			return nil, fmt.Errorf("transform %d (%s): %w", i+1, spec.Type, err)
+		}
+		if instrumented, ok := stage.(Instrumented); ok {
+			instrumented.Instrument(metrics)
+		}
+		if classifying, ok := stage.(Classifying); ok {
+			if findings, ok := metrics.(Findings); ok {
+				classifying.Classify(findings)
+			}
+		}
+		chain = append(chain, stage)
+	}
+
+	return chain, nil
+}
+
+// Apply runs every record through the chain, dropping filtered records
+func (c Chain) Apply(records []connectors.Record) ([]connectors.Record, error) {
+	return c.ApplyContext(context.Background(), records)
+}
+
+// ApplyContext runs every record through the chain, dropping filtered
+// records. Batch stages receive the records kept by the stages before them;
+// ctx bounds their work.
+func (c Chain) ApplyContext(ctx context.Context, records []connectors.Record) ([]connectors.Record, error) {
+	start := 0
+	for i, stage := range c {
+		batch, ok := stage.(BatchStage)
+		if !ok {
+			continue
+		}
+		var err error
+		if records, err = c[start:i].applyRecords(records); err != nil {
+			return nil, err
+		}
+		if len(records) > 0 {
+			if records, err = batch.ApplyBatch(ctx, records); err != nil {
+				return nil, fmt.Errorf("transform %d failed: %w", i+1, err)
+			}
+		}
+		start = i + 1
+	}
+	return c[start:].applyRecords(records)
+}
+
+// applyRecords runs every record through the stages one record at a time
+func (c Chain) applyRecords(records []connectors.Record) ([]connectors.Record, error) {
+	if len(c) == 0 {
+		return records, nil
+	}
+
+	out := make([]connectors.Record, 0, len(records))
+next:
+	for _, record := range records {
+		for _, stage := range c {
+			var keep bool
+			var err error
+			record, keep, err = stage.Apply(record)
+			if err != nil {
+				return nil, fmt.Errorf("transform failed on record %s: %w", record.ID, err)
+			}
+			if !keep {
+				continue next
+			}
+		}
+		out = append(out, record)
+	}
+
+	return out, nil
+}
+
+// copyData returns a shallow copy of record data so stages never mutate the
+// caller's records
+func copyData(data map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		out[k] = v
+	}
+	return out
+}
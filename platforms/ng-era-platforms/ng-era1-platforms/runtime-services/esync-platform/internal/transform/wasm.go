@@ -0,0 +1,269 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: wasm-transform
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * WebAssembly Transforms
+ */
+
+package transform
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"github.com/tetratelabs/wazero/sys"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+)
+
+// Defaults of wasm stages
+const (
+	defaultWasmTimeout     = 10 * time.Second
+	defaultWasmBatchSize   = 500
+	defaultWasmMemoryPages = 256
+)
+
+// wasmStage runs records through a WebAssembly module:
+//
+//	{type: wasm, module: /opt/transforms/enrich.wasm, args: [--strict],
+//	 env: {MODE: prod}, timeout: 10, batch_size: 500, max_memory_mb: 16}
+//
+// The module is a WASI command speaking the protocol of exec stages: every
+// batch starts a fresh instance that reads the records as NDJSON from stdin
+// and writes one line per record to stdout, the transformed record, null to
+// drop the record or an array of records to replace it, before exiting.
+// Instances get no filesystem or network access, only args and env, and
+// their stderr goes to the daemon log. A batch not finished within timeout
+// seconds is aborted, and memory beyond max_memory_mb fails it.
+//
+// The module is compiled once and again whenever its file changes.
+type wasmStage struct {
+	path      string
+	args      []string
+	env       map[string]string
+	timeout   time.Duration
+	batchSize int
+	pages     uint32
+}
+
+// wasmModule is a compiled module with the runtime that owns it
+type wasmModule struct {
+	runtime  wazero.Runtime
+	compiled wazero.CompiledModule
+}
+
+var (
+	wasmModulesMu sync.Mutex
+	wasmModules   = make(map[string]*wasmModule)
+)
+
+func newWasm(options map[string]interface{}) (Stage, error) {
+	path, _ := options["module"].(string)
+	if path == "" {
+		return nil, fmt.Errorf("module is required")
+	}
+
+	s := &wasmStage{
+		path:      path,
+		timeout:   defaultWasmTimeout,
+		batchSize: defaultWasmBatchSize,
+		pages:     defaultWasmMemoryPages,
+	}
+	var err error
+	if _, ok := options["args"]; ok {
+		if s.args, err = stringList(options, "args"); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := options["env"]; ok {
+		if s.env, err = stringMap(options, "env"); err != nil {
+			return nil, err
+		}
+	}
+	if n, err := nonNegativeInt(options, "timeout"); err != nil {
+		return nil, err
+	} else if n > 0 {
+		s.timeout = time.Duration(n) * time.Second
+	}
+	if n, err := nonNegativeInt(options, "batch_size"); err != nil {
+		return nil, err
+	} else if n > 0 {
+		s.batchSize = n
+	}
+	if n, err := nonNegativeInt(options, "max_memory_mb"); err != nil {
+		return nil, err
+	} else if n > 0 {
+		// A page is 64 KiB and a module addresses at most 4 GiB
+		if n > 4096 {
+			return nil, fmt.Errorf("max_memory_mb must not exceed 4096")
+		}
+		s.pages = uint32(n * 16)
+	}
+	return s, nil
+}
+
+// module returns the compiled module, compiling it when it was not yet or
+// its file changed
+func (s *wasmStage) module(ctx context.Context) (*wasmModule, error) {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wasm module: %w", err)
+	}
+	key, err := json.Marshal([]interface{}{s.path, info.Size(), info.ModTime().UnixNano(), s.pages})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode wasm options: %w", err)
+	}
+
+	wasmModulesMu.Lock()
+	defer wasmModulesMu.Unlock()
+
+	if m, exists := wasmModules[string(key)]; exists {
+		return m, nil
+	}
+	code, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wasm module: %w", err)
+	}
+
+	config := wazero.NewRuntimeConfig().
+		WithMemoryLimitPages(s.pages).
+		WithCloseOnContextDone(true)
+	runtime := wazero.NewRuntimeWithConfig(context.Background(), config)
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, runtime); err != nil {
+		runtime.Close(context.Background())
+		return nil, fmt.Errorf("failed to provide WASI to wasm module: %w", err)
+	}
+	compiled, err := runtime.CompileModule(ctx, code)
+	if err != nil {
+		runtime.Close(context.Background())
+		return nil, fmt.Errorf("failed to compile wasm module %s: %w", s.path, err)
+	}
+
+	m := &wasmModule{runtime: runtime, compiled: compiled}
+	wasmModules[string(key)] = m
+	log.Printf("[Transform] Compiled wasm transform %s", s.path)
+	return m, nil
+}
+
+// Apply transforms a single record through the module
+func (s *wasmStage) Apply(r connectors.Record) (connectors.Record, bool, error) {
+	out, err := s.ApplyBatch(context.Background(), []connectors.Record{r})
+	if err != nil || len(out) == 0 {
+		return r, false, err
+	}
+	if len(out) > 1 {
+		return r, false, fmt.Errorf("wasm transform returned %d records for one", len(out))
+	}
+	return out[0], true, nil
+}
+
+// ApplyBatch implements BatchStage, running an instance per batch of the
+// configured size
+func (s *wasmStage) ApplyBatch(ctx context.Context, records []connectors.Record) ([]connectors.Record, error) {
+	m, err := s.module(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]connectors.Record, 0, len(records))
+	for start := 0; start < len(records); start += s.batchSize {
+		end := start + s.batchSize
+		if end > len(records) {
+			end = len(records)
+		}
+		transformed, err := s.run(ctx, m, records[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("wasm transform %s: %w", s.path, err)
+		}
+		out = append(out, transformed...)
+	}
+	return out, nil
+}
+
+// run transforms one batch in a fresh instance of the module
+func (s *wasmStage) run(ctx context.Context, m *wasmModule, records []connectors.Record) ([]connectors.Record, error) {
+	var stdin, stdout bytes.Buffer
+	enc := json.NewEncoder(&stdin)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			return nil, fmt.Errorf("failed to encode record %s: %w", r.ID, err)
+		}
+	}
+
+	config := wazero.NewModuleConfig().
+		WithName("").
+		WithArgs(append([]string{s.path}, s.args...)...).
+		WithStdin(&stdin).
+		WithStdout(&stdout).
+		WithStderr(log.Writer()).
+		WithSysWalltime().
+		WithSysNanotime()
+	keys := make([]string, 0, len(s.env))
+	for k := range s.env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		config = config.WithEnv(k, s.env[k])
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	instance, err := m.runtime.InstantiateModule(ctx, m.compiled, config)
+	if instance != nil {
+		instance.Close(context.Background())
+	}
+	if err != nil {
+		var exit *sys.ExitError
+		switch {
+		case errors.Is(ctx.Err(), context.DeadlineExceeded):
+			return nil, fmt.Errorf("batch of %d records timed out after %s", len(records), s.timeout)
+		case ctx.Err() != nil:
+			return nil, ctx.Err()
+		case errors.As(err, &exit):
+			return nil, fmt.Errorf("module exited with code %d", exit.ExitCode())
+		default:
+			return nil, err
+		}
+	}
+
+	out := make([]connectors.Record, 0, len(records))
+	scanner := bufio.NewScanner(&stdout)
+	scanner.Buffer(nil, stdout.Len()+1)
+	lines := 0
+	for scanner.Scan() {
+		if lines == len(records) {
+			return nil, fmt.Errorf("module wrote more than %d lines", len(records))
+		}
+		transformed, err := decodeOutputLine(scanner.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("invalid output for record %s: %w", records[lines].ID, err)
+		}
+		out = append(out, transformed...)
+		lines++
+	}
+	if lines < len(records) {
+		return nil, fmt.Errorf("module wrote %d lines for %d records", lines, len(records))
+	}
+	return out, nil
+}
@@ -0,0 +1,280 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: ssh-tunnel
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * SSH Bastion Tunnels
+ */
+
+// Package tunnel reaches endpoints behind an SSH bastion. A tunnel listens
+// on a loopback port and forwards every accepted connection through the
+// system ssh client (ssh -W), so connectors only ever see a local address.
+package tunnel
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/machine-native-ops/esync-platform/internal/fips"
+)
+
+// DefaultPort is the bastion SSH port when a spec sets none
+const DefaultPort = 22
+
+// Spec describes an SSH bastion
+type Spec struct {
+	Host string
+	Port int
+	User string
+	// PrivateKey is the PEM or OpenSSH private key authenticating to the
+	// bastion, usually resolved from a ${secret:NAME} reference
+	PrivateKey string
+	// KnownHosts is a known_hosts file verifying the bastion; HostKey is a
+	// single "type base64" public key used instead of a file
+	KnownHosts string
+	HostKey    string
+}
+
+// FromConfig reads a spec from the "tunnel" block of a connector config
+func FromConfig(config map[string]interface{}) (*Spec, error) {
+	str := func(key string) string {
+		s, _ := config[key].(string)
+		return s
+	}
+
+	spec := &Spec{
+		Host:       str("host"),
+		User:       str("user"),
+		PrivateKey: str("private_key"),
+		KnownHosts: str("known_hosts"),
+		HostKey:    str("host_key"),
+		Port:       DefaultPort,
+	}
+	if v, ok := config["port"]; ok {
+		port, err := strconv.Atoi(fmt.Sprint(v))
+		if err != nil {
+			return nil, fmt.Errorf("invalid tunnel port %v", v)
+		}
+		spec.Port = port
+	}
+	if spec.Host == "" || spec.User == "" {
+		return nil, fmt.Errorf("tunnel requires host and user")
+	}
+	if spec.PrivateKey == "" {
+		return nil, fmt.Errorf("tunnel requires private_key")
+	}
+	return spec, nil
+}
+
+// Tunnel forwards loopback connections to a target through a bastion
+type Tunnel struct {
+	spec     *Spec
+	target   string
+	listener net.Listener
+	files    []string
+
+	mu     sync.Mutex
+	procs  map[*exec.Cmd]struct{}
+	closed bool
+}
+
+// Open starts a tunnel to target (host:port) through the bastion of spec
+func Open(spec *Spec, target string) (*Tunnel, error) {
+	if _, err := exec.LookPath("ssh"); err != nil {
+		return nil, fmt.Errorf("ssh tunnels need an ssh client: %w", err)
+	}
+
+	t := &Tunnel{spec: spec, target: target, procs: make(map[*exec.Cmd]struct{})}
+	key := spec.PrivateKey
+	if !strings.HasSuffix(key, "\n") {
+		key += "\n"
+	}
+	if _, err := t.writeFile("key", key); err != nil {
+		return nil, err
+	}
+	if spec.HostKey != "" {
+		entry := fmt.Sprintf("[%s]:%d %s\n", spec.Host, spec.Port, spec.HostKey)
+		if spec.Port == DefaultPort {
+			entry = spec.Host + " " + spec.HostKey + "\n"
+		}
+		if _, err := t.writeFile("known_hosts", entry); err != nil {
+			t.removeFiles()
+			return nil, err
+		}
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.removeFiles()
+		return nil, fmt.Errorf("failed to listen for tunnel: %w", err)
+	}
+	t.listener = l
+
+	log.Printf("[Tunnel] Forwarding %s to %s via %s@%s:%d", l.Addr(), target, spec.User, spec.Host, spec.Port)
+	go t.serve()
+	return t, nil
+}
+
+// writeFile stores key material in a private temporary file
+func (t *Tunnel) writeFile(kind, content string) (string, error) {
+	f, err := os.CreateTemp("", "esync-tunnel-*."+kind)
+	if err != nil {
+		return "", fmt.Errorf("failed to write tunnel %s: %w", kind, err)
+	}
+	t.files = append(t.files, f.Name())
+	_, err = f.WriteString(content)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to write tunnel %s: %w", kind, err)
+	}
+	return f.Name(), nil
+}
+
+// Addr returns the loopback host and port connectors dial
+func (t *Tunnel) Addr() (string, int) {
+	addr := t.listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port
+}
+
+// Rewrite returns a copy of a connector config pointing host and port at
+// the tunnel, without the tunnel block
+func (t *Tunnel) Rewrite(config map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(config))
+	for k, v := range config {
+		out[k] = v
+	}
+	delete(out, "tunnel")
+	out["host"], out["port"] = t.Addr()
+	return out
+}
+
+// serve accepts loopback connections until the tunnel is closed
+func (t *Tunnel) serve() {
+	for {
+		conn, err := t.listener.Accept()
+		if err != nil {
+			return
+		}
+		go t.forward(conn)
+	}
+}
+
+// forward carries one connection over an ssh -W session
+func (t *Tunnel) forward(conn net.Conn) {
+	defer conn.Close()
+
+	cmd := exec.Command("ssh", t.args()...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdin, err := cmd.StdinPipe()
+	if err == nil {
+		var stdout io.ReadCloser
+		if stdout, err = cmd.StdoutPipe(); err == nil {
+			err = t.run(cmd, conn, stdin, stdout)
+		}
+	}
+	if err != nil && !t.isClosed() {
+		log.Printf("[Tunnel] Forwarding to %s via %s failed: %v %s", t.target, t.spec.Host, err, strings.TrimSpace(stderr.String()))
+	}
+}
+
+// run starts ssh and copies both directions until either side closes
+func (t *Tunnel) run(cmd *exec.Cmd, conn net.Conn, stdin io.WriteCloser, stdout io.Reader) error {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return nil
+	}
+	if err := cmd.Start(); err != nil {
+		t.mu.Unlock()
+		return err
+	}
+	t.procs[cmd] = struct{}{}
+	t.mu.Unlock()
+
+	go func() {
+		io.Copy(stdin, conn)
+		stdin.Close()
+	}()
+	io.Copy(conn, stdout)
+	err := cmd.Wait()
+
+	t.mu.Lock()
+	delete(t.procs, cmd)
+	t.mu.Unlock()
+	return err
+}
+
+// args builds the ssh command line of one forwarded connection
+func (t *Tunnel) args() []string {
+	args := []string{
+		"-F", "/dev/null",
+		"-i", t.files[0],
+		"-p", strconv.Itoa(t.spec.Port),
+		"-l", t.spec.User,
+		"-o", "BatchMode=yes",
+		"-o", "IdentitiesOnly=yes",
+		"-o", "StrictHostKeyChecking=yes",
+		"-o", "ExitOnForwardFailure=yes",
+		"-o", "ServerAliveInterval=30",
+	}
+	args = append(args, fips.SSHOptions()...)
+	switch {
+	case t.spec.HostKey != "":
+		args = append(args, "-o", "UserKnownHostsFile="+t.files[1])
+	case t.spec.KnownHosts != "":
+		args = append(args, "-o", "UserKnownHostsFile="+t.spec.KnownHosts)
+	}
+	return append(args, "-W", t.target, t.spec.Host)
+}
+
+// isClosed reports whether Close was called
+func (t *Tunnel) isClosed() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.closed
+}
+
+// Close stops accepting connections, ends open sessions and removes the
+// key material
+func (t *Tunnel) Close() error {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return nil
+	}
+	t.closed = true
+	for cmd := range t.procs {
+		cmd.Process.Kill()
+	}
+	t.mu.Unlock()
+
+	err := t.listener.Close()
+	t.removeFiles()
+	return err
+}
+
+// removeFiles deletes the temporary key and known_hosts files
+func (t *Tunnel) removeFiles() {
+	for _, name := range t.files {
+		os.Remove(name)
+	}
+}
@@ -0,0 +1,1077 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: admin-api-client
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Admin API Go Client
+ */
+
+// Package client is a Go client for the esync admin API described by the
+// daemon's /openapi.json document
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Error is a non-success response of the admin API. Code is the stable
+// error code of the problem details, such as not_found or conflict, and
+// CorrelationID identifies the request in the daemon's logs and traces.
+type Error struct {
+	StatusCode    int
+	Code          string
+	Message       string
+	CorrelationID string
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	if e.CorrelationID != "" {
+		return fmt.Sprintf("esync API: %d %s (correlation %s)", e.StatusCode, e.Message, e.CorrelationID)
+	}
+	return fmt.Sprintf("esync API: %d %s", e.StatusCode, e.Message)
+}
+
+// requestIDKey is the context key of the request ID set by WithRequestID
+type requestIDKey struct{}
+
+// WithRequestID returns a context whose API calls send id as their
+// X-Request-ID. The daemon logs it and records it in the metadata and audit
+// entries of the runs the calls trigger.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// Client calls the admin API of an esync daemon
+type Client struct {
+	baseURL string
+	http    *http.Client
+}
+
+// New creates a client for the admin API at baseURL. A unix:///path base
+// URL reaches an admin API bound to a unix socket.
+func New(baseURL string) *Client {
+	hc := &http.Client{Timeout: 60 * time.Second}
+	if socket, ok := strings.CutPrefix(baseURL, "unix://"); ok {
+		hc.Transport = &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socket)
+			},
+		}
+		baseURL = "http://unix"
+	}
+	return &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		http:    hc,
+	}
+}
+
+// WithHTTPClient replaces the underlying HTTP client
+func (c *Client) WithHTTPClient(hc *http.Client) *Client {
+	c.http = hc
+	return c
+}
+
+// ListPipelines lists pipelines matching an optional label selector
+func (c *Client) ListPipelines(ctx context.Context, selector string) ([]Pipeline, error) {
+	var out []Pipeline
+	return out, c.do(ctx, http.MethodGet, "/pipelines", query("selector", selector), &out)
+}
+
+// SearchPipelines returns one page of the pipelines matching q
+func (c *Client) SearchPipelines(ctx context.Context, q PipelineQuery) (*PipelinePage, error) {
+	v := url.Values{}
+	set := func(key, value string) {
+		if value != "" {
+			v.Set(key, value)
+		}
+	}
+	set("selector", q.Selector)
+	set("source.type", q.SourceType)
+	set("target.type", q.TargetType)
+	set("text", q.Text)
+	set("sort", q.Sort)
+	for _, label := range q.Labels {
+		v.Add("label", label)
+	}
+	if q.Offset > 0 {
+		v.Set("offset", strconv.Itoa(q.Offset))
+	}
+	if q.Limit > 0 {
+		v.Set("limit", strconv.Itoa(q.Limit))
+	}
+
+	resp, err := c.send(ctx, http.MethodGet, "/pipelines", v, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	out := &PipelinePage{}
+	if err := json.NewDecoder(resp.Body).Decode(&out.Pipelines); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	out.Total, err = strconv.Atoi(resp.Header.Get("Esync-Total-Count"))
+	if err != nil {
+		out.Total = len(out.Pipelines)
+	}
+	return out, nil
+}
+
+// ListConnections lists the connection profiles
+func (c *Client) ListConnections(ctx context.Context) ([]Connection, error) {
+	var out []Connection
+	return out, c.do(ctx, http.MethodGet, "/connections", nil, &out)
+}
+
+// ListAuditEntries lists up to limit policy decisions, newest first,
+// optionally of one pipeline
+func (c *Client) ListAuditEntries(ctx context.Context, pipelineID string, limit int) ([]AuditEntry, error) {
+	q := url.Values{}
+	if pipelineID != "" {
+		q.Set("pipeline", pipelineID)
+	}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	var out []AuditEntry
+	return out, c.do(ctx, http.MethodGet, "/audit", q, &out)
+}
+
+// OrphanReport returns the last report of connector resources no
+// registered pipeline owns
+func (c *Client) OrphanReport(ctx context.Context) (*OrphanReport, error) {
+	var out OrphanReport
+	return &out, c.do(ctx, http.MethodGet, "/orphans", nil, &out)
+}
+
+// CheckOrphans runs an orphaned resource check now
+func (c *Client) CheckOrphans(ctx context.Context) (*OrphanReport, error) {
+	var out OrphanReport
+	return &out, c.do(ctx, http.MethodPost, "/orphans", nil, &out)
+}
+
+// ListErasures lists the erasure requests, newest first
+func (c *Client) ListErasures(ctx context.Context) ([]ErasureRequest, error) {
+	var out []ErasureRequest
+	return out, c.do(ctx, http.MethodGet, "/erasures", nil, &out)
+}
+
+// StartErasure erases a data subject from every pipeline declaring erasure,
+// optionally only those matching selector
+func (c *Client) StartErasure(ctx context.Context, subject, reason, selector string) (*ErasureRequest, error) {
+	q := url.Values{"subject": {subject}}
+	if reason != "" {
+		q.Set("reason", reason)
+	}
+	if selector != "" {
+		q.Set("selector", selector)
+	}
+	var out ErasureRequest
+	return &out, c.do(ctx, http.MethodPost, "/erasures", q, &out)
+}
+
+// GetErasure returns an erasure request and its per-target progress
+func (c *Client) GetErasure(ctx context.Context, id string) (*ErasureRequest, error) {
+	var out ErasureRequest
+	return &out, c.do(ctx, http.MethodGet, "/erasures/"+url.PathEscape(id), nil, &out)
+}
+
+// RetryErasure retries the failed targets of an erasure request
+func (c *Client) RetryErasure(ctx context.Context, id string) (*ErasureRequest, error) {
+	var out ErasureRequest
+	return &out, c.do(ctx, http.MethodPost, "/erasures/"+url.PathEscape(id)+"/retry", nil, &out)
+}
+
+// ErasureReport returns the compliance report of an erasure request
+func (c *Client) ErasureReport(ctx context.Context, id string) (*ErasureReport, error) {
+	var out ErasureReport
+	return &out, c.do(ctx, http.MethodGet, "/erasures/"+url.PathEscape(id)+"/report", nil, &out)
+}
+
+// Backup writes a gzipped tar archive of the daemon state to w
+func (c *Client) Backup(ctx context.Context, w io.Writer) error {
+	resp, err := c.send(ctx, http.MethodGet, "/backup", nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("failed to read backup: %w", err)
+	}
+	return nil
+}
+
+// Restore replaces the daemon state with an archive written by Backup
+func (c *Client) Restore(ctx context.Context, r io.Reader) (*BackupManifest, error) {
+	resp, err := c.send(ctx, http.MethodPost, "/restore", nil, r)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var out BackupManifest
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &out, nil
+}
+
+// ExportPipelines writes a bundle of the pipelines matching an optional
+// label selector, their overlays and connection profiles to w
+func (c *Client) ExportPipelines(ctx context.Context, selector string, w io.Writer) error {
+	resp, err := c.send(ctx, http.MethodGet, "/pipelines:export", query("selector", selector), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("failed to read pipeline bundle: %w", err)
+	}
+	return nil
+}
+
+// ImportPipelines registers the pipelines of a bundle written by
+// ExportPipelines. onConflict is skip (the default), overwrite or rename;
+// a dry run only reports what would happen.
+func (c *Client) ImportPipelines(ctx context.Context, r io.Reader, onConflict string, dryRun bool) (*ImportReport, error) {
+	q := url.Values{}
+	if onConflict != "" {
+		q.Set("on_conflict", onConflict)
+	}
+	if dryRun {
+		q.Set("dry_run", "true")
+	}
+	resp, err := c.send(ctx, http.MethodPost, "/pipelines:import", q, r)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var out ImportReport
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &out, nil
+}
+
+// GetHandoff returns the last handoff of the daemon
+func (c *Client) GetHandoff(ctx context.Context) (*Handoff, error) {
+	var out Handoff
+	return &out, c.do(ctx, http.MethodGet, "/handoff", nil, &out)
+}
+
+// BeginHandoff drains every pipeline of the daemon and leases them to the
+// caller, writing the state archive to w. Restore it and call
+// CompleteHandoff before the lease expires.
+func (c *Client) BeginHandoff(ctx context.Context, lease time.Duration, w io.Writer) (*Handoff, error) {
+	var q url.Values
+	if lease > 0 {
+		q = url.Values{"lease": {lease.String()}}
+	}
+	resp, err := c.send(ctx, http.MethodPost, "/handoff", q, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	h := &Handoff{ID: resp.Header.Get("Esync-Handoff-Id"), Status: "leased"}
+	h.LeaseExpires, _ = time.Parse(time.RFC3339, resp.Header.Get("Esync-Handoff-Lease-Expires"))
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return nil, fmt.Errorf("failed to read handoff state: %w", err)
+	}
+	return h, nil
+}
+
+// CompleteHandoff confirms that the caller has taken over the pipelines
+func (c *Client) CompleteHandoff(ctx context.Context, id string) (*Handoff, error) {
+	var out Handoff
+	return &out, c.do(ctx, http.MethodPost, "/handoff/"+url.PathEscape(id)+"/complete", nil, &out)
+}
+
+// AbortHandoff releases a leased handoff so the daemon resumes its pipelines
+func (c *Client) AbortHandoff(ctx context.Context, id string) (*Handoff, error) {
+	var out Handoff
+	return &out, c.do(ctx, http.MethodPost, "/handoff/"+url.PathEscape(id)+"/abort", nil, &out)
+}
+
+// AgentBeacon returns the beacon the daemon posts when running as an edge
+// agent
+func (c *Client) AgentBeacon(ctx context.Context) (*AgentBeacon, error) {
+	var out AgentBeacon
+	return &out, c.do(ctx, http.MethodGet, "/agent", nil, &out)
+}
+
+// ListAgents returns the edge agents reporting to the daemon
+func (c *Client) ListAgents(ctx context.Context) ([]AgentStatus, error) {
+	var out []AgentStatus
+	return out, c.do(ctx, http.MethodGet, "/agents", nil, &out)
+}
+
+// GetAgent returns the last beacon of an edge agent
+func (c *Client) GetAgent(ctx context.Context, id string) (*AgentStatus, error) {
+	var out AgentStatus
+	return &out, c.do(ctx, http.MethodGet, "/agents/"+url.PathEscape(id), nil, &out)
+}
+
+// PutBeacon posts the beacon of an edge agent, gzipped, to the daemon
+func (c *Client) PutBeacon(ctx context.Context, b *AgentBeacon) (*AgentStatus, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(zw).Encode(b); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	resp, err := c.send(ctx, http.MethodPut, "/agents/"+url.PathEscape(b.AgentID), nil, &buf)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var out AgentStatus
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &out, nil
+}
+
+// FleetStatus returns the status of the fleet managed by the daemon
+func (c *Client) FleetStatus(ctx context.Context) (*FleetStatus, error) {
+	var out FleetStatus
+	return &out, c.do(ctx, http.MethodGet, "/fleet", nil, &out)
+}
+
+// FleetPipelines lists the pipelines the daemon distributes to its fleet
+func (c *Client) FleetPipelines(ctx context.Context) ([]FleetPipeline, error) {
+	var out []FleetPipeline
+	return out, c.do(ctx, http.MethodGet, "/fleet/pipelines", nil, &out)
+}
+
+// ReloadFleet makes the daemon read its fleet file and pipelines again
+func (c *Client) ReloadFleet(ctx context.Context) (*FleetStatus, error) {
+	var out FleetStatus
+	return &out, c.do(ctx, http.MethodPost, "/fleet:reload", nil, &out)
+}
+
+// FleetDelta returns the delta taking an agent from the pipeline versions
+// of its last beacon to the pipelines assigned to it
+func (c *Client) FleetDelta(ctx context.Context, agentID string) (*FleetDelta, error) {
+	var out FleetDelta
+	return &out, c.do(ctx, http.MethodGet, "/fleet/agents/"+url.PathEscape(agentID)+"/delta", nil, &out)
+}
+
+// FleetBundle writes the bundle of the pipelines assigned to an agent to w
+// and returns its revision
+func (c *Client) FleetBundle(ctx context.Context, agentID string, w io.Writer) (string, error) {
+	resp, err := c.send(ctx, http.MethodGet, "/fleet/agents/"+url.PathEscape(agentID)+"/pipelines", nil, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return "", fmt.Errorf("failed to read pipeline bundle: %w", err)
+	}
+	return resp.Header.Get("Esync-Fleet-Revision"), nil
+}
+
+// Bandwidth returns the bandwidth caps of the agent and the pipelines and
+// the traffic metered over the last days; zero reports the default 7 days
+func (c *Client) Bandwidth(ctx context.Context, days int) (*BandwidthReport, error) {
+	path := "/bandwidth"
+	if days > 0 {
+		path += "?days=" + strconv.Itoa(days)
+	}
+	var out BandwidthReport
+	return &out, c.do(ctx, http.MethodGet, path, nil, &out)
+}
+
+// Info returns the build, enabled features, connector types and runtime
+// flags of the daemon
+func (c *Client) Info(ctx context.Context) (*Info, error) {
+	var out Info
+	return &out, c.do(ctx, http.MethodGet, "/info", nil, &out)
+}
+
+// ReloadConfig makes the daemon reload its config as SIGHUP does
+func (c *Client) ReloadConfig(ctx context.Context) (*ReloadReport, error) {
+	var out ReloadReport
+	return &out, c.do(ctx, http.MethodPost, "/config:reload", nil, &out)
+}
+
+// ConnectorSchema returns the JSON Schema of the config block of a
+// connector type
+func (c *Client) ConnectorSchema(ctx context.Context, connectorType string) (json.RawMessage, error) {
+	var out json.RawMessage
+	return out, c.do(ctx, http.MethodGet, "/schemas/connectors/"+url.PathEscape(connectorType)+".json", nil, &out)
+}
+
+// ListFlags lists the runtime diagnostic flags
+func (c *Client) ListFlags(ctx context.Context) ([]Flag, error) {
+	var out []Flag
+	return out, c.do(ctx, http.MethodGet, "/flags", nil, &out)
+}
+
+// SetFlag changes a runtime diagnostic flag until the daemon restarts
+func (c *Client) SetFlag(ctx context.Context, name, value string) (*Flag, error) {
+	var out Flag
+	return &out, c.do(ctx, http.MethodPost, "/flags/"+url.PathEscape(name), url.Values{"value": {value}}, &out)
+}
+
+// LogLevels returns the log level of the daemon and its overrides
+func (c *Client) LogLevels(ctx context.Context) (*LogLevels, error) {
+	var out LogLevels
+	return &out, c.do(ctx, http.MethodGet, "/log-levels", nil, &out)
+}
+
+// SetLogLevel overrides the log level of a scope: a module such as engine
+// or registry, connector:<type> or pipeline:<id>. The override expires
+// after expires when positive.
+func (c *Client) SetLogLevel(ctx context.Context, scope, level string, expires time.Duration) (*LogLevelOverride, error) {
+	q := query("level", level)
+	if expires > 0 {
+		q.Set("expires", expires.String())
+	}
+	var out LogLevelOverride
+	return &out, c.do(ctx, http.MethodPost, "/log-levels/"+url.PathEscape(scope), q, &out)
+}
+
+// RemoveLogLevel removes the log level override of a scope
+func (c *Client) RemoveLogLevel(ctx context.Context, scope string) (*LogLevelOverride, error) {
+	var out LogLevelOverride
+	return &out, c.do(ctx, http.MethodDelete, "/log-levels/"+url.PathEscape(scope), nil, &out)
+}
+
+// PipelineHealth returns the health score of a pipeline
+func (c *Client) PipelineHealth(ctx context.Context, id string) (*PipelineHealth, error) {
+	var out PipelineHealth
+	return &out, c.do(ctx, http.MethodGet, pipelinePath(id, "health"), nil, &out)
+}
+
+// Overview returns the health of every pipeline, worst first, optionally
+// only those at the given levels and the limit worst ones
+func (c *Client) Overview(ctx context.Context, levels []string, limit int) ([]PipelineHealth, error) {
+	q := url.Values{}
+	if len(levels) > 0 {
+		q.Set("level", strings.Join(levels, ","))
+	}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	var out []PipelineHealth
+	return out, c.do(ctx, http.MethodGet, "/overview", q, &out)
+}
+
+// Autoscaling returns the load of the daemon for external scalers
+func (c *Client) Autoscaling(ctx context.Context) (*Autoscaling, error) {
+	var out Autoscaling
+	return &out, c.do(ctx, http.MethodGet, "/autoscaling", nil, &out)
+}
+
+// TemplateFunctions documents a version of the template function library;
+// version 0 means the latest
+func (c *Client) TemplateFunctions(ctx context.Context, version int) (*TemplateFunctions, error) {
+	q := url.Values{}
+	if version > 0 {
+		q.Set("version", strconv.Itoa(version))
+	}
+	var out TemplateFunctions
+	return &out, c.do(ctx, http.MethodGet, "/template-functions", q, &out)
+}
+
+// Memory returns the memory use of the daemon and its memory pressure
+func (c *Client) Memory(ctx context.Context) (*MemoryStatus, error) {
+	var out MemoryStatus
+	return &out, c.do(ctx, http.MethodGet, "/memory", nil, &out)
+}
+
+// Digests lists the scheduled digests and when they are next sent
+func (c *Client) Digests(ctx context.Context) ([]DigestStatus, error) {
+	var out []DigestStatus
+	return out, c.do(ctx, http.MethodGet, "/digests", nil, &out)
+}
+
+// PreviewDigest returns a digest as it would be sent now
+func (c *Client) PreviewDigest(ctx context.Context, name string) (*Digest, error) {
+	var out Digest
+	return &out, c.do(ctx, http.MethodGet, "/digests/"+url.PathEscape(name), nil, &out)
+}
+
+// SendDigest sends a digest to its target now without starting a new
+// period
+func (c *Client) SendDigest(ctx context.Context, name string) (*Digest, error) {
+	var out Digest
+	return &out, c.do(ctx, http.MethodPost, "/digests/"+url.PathEscape(name)+"/send", nil, &out)
+}
+
+// GetPipeline returns a pipeline definition
+func (c *Client) GetPipeline(ctx context.Context, id string) (*Pipeline, error) {
+	var out Pipeline
+	return &out, c.do(ctx, http.MethodGet, pipelinePath(id, ""), nil, &out)
+}
+
+// ClonePipeline copies a pipeline under a new ID into a definition file of
+// its own and schedules the copy
+func (c *Client) ClonePipeline(ctx context.Context, id string, opts CloneOptions) (*Pipeline, error) {
+	q := url.Values{"id": {opts.ID}}
+	set := func(key, value string) {
+		if value != "" {
+			q.Set(key, value)
+		}
+	}
+	set("description", opts.Description)
+	set("source_connection", opts.SourceConnection)
+	set("target_connection", opts.TargetConnection)
+	for key, value := range opts.Labels {
+		q.Add("label", key+":"+value)
+	}
+	var out Pipeline
+	return &out, c.do(ctx, http.MethodPost, pipelinePath(id, "")+":clone", q, &out)
+}
+
+// PromotePipeline carries the settings a pipeline has in environment from,
+// the daemon's when empty, into the overlay of environment to, keeping the
+// connections of to; a dry run only reports the outcome
+func (c *Client) PromotePipeline(ctx context.Context, id, from, to string, dryRun bool) (*Promotion, error) {
+	q := url.Values{"to": {to}}
+	if from != "" {
+		q.Set("from", from)
+	}
+	if dryRun {
+		q.Set("dry_run", "true")
+	}
+	var out Promotion
+	return &out, c.do(ctx, http.MethodPost, pipelinePath(id, "")+":promote", q, &out)
+}
+
+// Explain returns the fully resolved form of a pipeline
+func (c *Client) Explain(ctx context.Context, id string) (*Explanation, error) {
+	var out Explanation
+	return &out, c.do(ctx, http.MethodGet, pipelinePath(id, "explain"), nil, &out)
+}
+
+// Status returns the runtime state of a pipeline
+func (c *Client) Status(ctx context.Context, id string) (*PipelineStatus, error) {
+	var out PipelineStatus
+	return &out, c.do(ctx, http.MethodGet, pipelinePath(id, "status"), nil, &out)
+}
+
+// ListTables returns the per-table progress of a multi-table pipeline
+func (c *Client) ListTables(ctx context.Context, id string) ([]TableState, error) {
+	var out []TableState
+	return out, c.do(ctx, http.MethodGet, pipelinePath(id, "tables"), nil, &out)
+}
+
+// CheckSchemas compares the source and target schemas of a pipeline
+// without running it
+func (c *Client) CheckSchemas(ctx context.Context, id string) (*CompatibilityReport, error) {
+	var out CompatibilityReport
+	return &out, c.do(ctx, http.MethodGet, pipelinePath(id, "compatibility"), nil, &out)
+}
+
+// ListDDLChanges returns the captured schema changes of a pipeline
+func (c *Client) ListDDLChanges(ctx context.Context, id string) ([]DDLChange, error) {
+	var out []DDLChange
+	return out, c.do(ctx, http.MethodGet, pipelinePath(id, "ddl"), nil, &out)
+}
+
+// ApproveDDLChange applies a pending schema change to the target
+func (c *Client) ApproveDDLChange(ctx context.Context, id, change string) (*DDLChange, error) {
+	var out DDLChange
+	return &out, c.do(ctx, http.MethodPost, pipelinePath(id, "ddl/"+url.PathEscape(change)+"/approve"), nil, &out)
+}
+
+// RejectDDLChange skips a pending schema change
+func (c *Client) RejectDDLChange(ctx context.Context, id, change string) (*DDLChange, error) {
+	var out DDLChange
+	return &out, c.do(ctx, http.MethodPost, pipelinePath(id, "ddl/"+url.PathEscape(change)+"/reject"), nil, &out)
+}
+
+// ListRuns returns the recent runs of a pipeline, newest first
+func (c *Client) ListRuns(ctx context.Context, id string) ([]Run, error) {
+	var out []Run
+	return out, c.do(ctx, http.MethodGet, pipelinePath(id, "runs"), nil, &out)
+}
+
+// DiffRuns compares run b of a pipeline against run a, each a run ID,
+// RunLatest or RunLastGood
+func (c *Client) DiffRuns(ctx context.Context, id, a, b string) (*RunDiff, error) {
+	var out RunDiff
+	return &out, c.do(ctx, http.MethodGet, pipelinePath(id, "runs/"+url.PathEscape(a)+"/diff/"+url.PathEscape(b)), nil, &out)
+}
+
+// TriggerRun runs a sync pass and waits for its result
+func (c *Client) TriggerRun(ctx context.Context, id string) (*Run, error) {
+	var out Run
+	return &out, c.do(ctx, http.MethodPost, pipelinePath(id, "runs"), nil, &out)
+}
+
+// StartTrigger runs a pipeline once per idempotency key, waiting up to wait
+// for the run to finish. Retrying with the same key returns the state of
+// the first run instead of starting another; a rejected trigger is returned
+// with the Rejected state.
+func (c *Client) StartTrigger(ctx context.Context, id, key string, wait time.Duration) (*TriggerStatus, error) {
+	q := url.Values{"wait": {wait.String()}}
+	if key != "" {
+		q.Set("idempotency_key", key)
+	}
+	var out TriggerStatus
+	return &out, c.do(ctx, http.MethodPost, pipelinePath(id, "triggers"), q, &out, http.StatusConflict)
+}
+
+// GetTrigger returns the state of a trigger, waiting up to wait for its run
+// to finish
+func (c *Client) GetTrigger(ctx context.Context, id, key string, wait time.Duration) (*TriggerStatus, error) {
+	var out TriggerStatus
+	return &out, c.do(ctx, http.MethodGet, pipelinePath(id, "triggers/"+url.PathEscape(key)), url.Values{"wait": {wait.String()}}, &out, http.StatusConflict)
+}
+
+// RecordRun runs a sync pass, saving its input as the replay fixture of
+// the pipeline
+func (c *Client) RecordRun(ctx context.Context, id string) (*Run, error) {
+	var out Run
+	return &out, c.do(ctx, http.MethodPost, pipelinePath(id, "runs"), query("record", "true"), &out)
+}
+
+// Fixture returns the replay fixture last recorded for a pipeline
+func (c *Client) Fixture(ctx context.Context, id string) (*Fixture, error) {
+	var out Fixture
+	return &out, c.do(ctx, http.MethodGet, pipelinePath(id, "fixture"), nil, &out)
+}
+
+// DeadLetters lists the records set aside for exceeding the record limits
+// of a pipeline, oldest first
+func (c *Client) DeadLetters(ctx context.Context, id string) ([]DeadLetter, error) {
+	var out []DeadLetter
+	return out, c.do(ctx, http.MethodGet, pipelinePath(id, "dead-letters"), nil, &out)
+}
+
+// DrainDeadLetters empties the dead-letter queue of a pipeline and returns
+// the letters removed
+func (c *Client) DrainDeadLetters(ctx context.Context, id string) ([]DeadLetter, error) {
+	var out []DeadLetter
+	return out, c.do(ctx, http.MethodDelete, pipelinePath(id, "dead-letters"), nil, &out)
+}
+
+// SimulatePipeline replays recorded runs through a pipeline, or the
+// candidate definition of req, against an in-memory target and reports
+// what it would have written. A nil req replays the fixture last recorded
+// for the pipeline; speed above zero replays the time between runs that
+// many times faster.
+func (c *Client) SimulatePipeline(ctx context.Context, id string, req *SimulationRequest, speed float64) (*Simulation, error) {
+	var q url.Values
+	if speed > 0 {
+		q = query("speed", strconv.FormatFloat(speed, 'f', -1, 64))
+	}
+	var body io.Reader
+	if req != nil {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		if err := json.NewEncoder(zw).Encode(req); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+		body = &buf
+	}
+	resp, err := c.send(ctx, http.MethodPost, pipelinePath(id, "")+":simulate", q, body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var out Simulation
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &out, nil
+}
+
+// Pause pauses a pipeline
+func (c *Client) Pause(ctx context.Context, id string) (*PauseState, error) {
+	var out PauseState
+	return &out, c.do(ctx, http.MethodPost, pipelinePath(id, "pause"), nil, &out)
+}
+
+// Resume resumes a pipeline
+func (c *Client) Resume(ctx context.Context, id string) (*PauseState, error) {
+	var out PauseState
+	return &out, c.do(ctx, http.MethodPost, pipelinePath(id, "resume"), nil, &out)
+}
+
+// Preflight returns the last pre-flight report of a pipeline
+func (c *Client) Preflight(ctx context.Context, id string) (*PreflightReport, error) {
+	var out PreflightReport
+	return &out, c.do(ctx, http.MethodGet, pipelinePath(id, "preflight"), nil, &out)
+}
+
+// RunPreflight re-runs the pre-flight checks of a pipeline. A failed check
+// is reported through the returned report, not as an error.
+func (c *Client) RunPreflight(ctx context.Context, id string) (*PreflightReport, error) {
+	var out PreflightReport
+	err := c.do(ctx, http.MethodPost, pipelinePath(id, "preflight"), nil, &out, http.StatusUnprocessableEntity)
+	return &out, err
+}
+
+// Backfill returns the backfill state of a pipeline
+func (c *Client) Backfill(ctx context.Context, id string) (*BackfillState, error) {
+	var out BackfillState
+	return &out, c.do(ctx, http.MethodGet, pipelinePath(id, "backfill"), nil, &out)
+}
+
+// StartBackfill starts or resumes a chunked backfill
+func (c *Client) StartBackfill(ctx context.Context, id string) (*BackfillState, error) {
+	var out BackfillState
+	return &out, c.do(ctx, http.MethodPost, pipelinePath(id, "backfill"), nil, &out)
+}
+
+// Cutover returns the cutover state of a pipeline
+func (c *Client) Cutover(ctx context.Context, id string) (*CutoverState, error) {
+	var out CutoverState
+	return &out, c.do(ctx, http.MethodGet, pipelinePath(id, "cutover"), nil, &out)
+}
+
+// StartCutover starts or resumes the cutover workflow
+func (c *Client) StartCutover(ctx context.Context, id string) (*CutoverState, error) {
+	var out CutoverState
+	return &out, c.do(ctx, http.MethodPost, pipelinePath(id, "cutover"), nil, &out)
+}
+
+// Teardown releases the source and target resources of a paused pipeline,
+// such as replication slots, or only lists them when dryRun is set
+func (c *Client) Teardown(ctx context.Context, id string, dryRun bool) (*TeardownReport, error) {
+	var q url.Values
+	if dryRun {
+		q = query("dry_run", "true")
+	}
+	var out TeardownReport
+	return &out, c.do(ctx, http.MethodPost, pipelinePath(id, "teardown"), q, &out)
+}
+
+// Standby returns the failover barrier of a standby pipeline
+func (c *Client) Standby(ctx context.Context, id string) (*StandbyState, error) {
+	var out StandbyState
+	return &out, c.do(ctx, http.MethodGet, pipelinePath(id, "standby"), nil, &out)
+}
+
+// Failover releases the barrier of a standby pipeline. The final delta is
+// applied in the background; poll Standby until the phase is promoted or
+// failed. With force the target is promoted even if the delta fails.
+func (c *Client) Failover(ctx context.Context, id string, force bool) (*StandbyState, error) {
+	q := url.Values{}
+	if force {
+		q.Set("force", "true")
+	}
+	var out StandbyState
+	return &out, c.do(ctx, http.MethodPost, pipelinePath(id, "standby"), q, &out)
+}
+
+// RearmStandby holds the barrier of a standby pipeline again after a
+// failover and resumes priming
+func (c *Client) RearmStandby(ctx context.Context, id string) (*StandbyState, error) {
+	var out StandbyState
+	return &out, c.do(ctx, http.MethodDelete, pipelinePath(id, "standby"), nil, &out)
+}
+
+// Confirmation returns the digest of the current definition of a
+// destructive pipeline, such as a cleanup pipeline, and whether it is
+// confirmed
+func (c *Client) Confirmation(ctx context.Context, id string) (*ConfirmationState, error) {
+	var out ConfirmationState
+	return &out, c.do(ctx, http.MethodGet, pipelinePath(id, "confirmation"), nil, &out)
+}
+
+// Confirm confirms the definition of a destructive pipeline with the
+// reviewed digest, for a change ticket and by a person when given, and
+// resumes the pipeline. It fails when the definition changed since.
+func (c *Client) Confirm(ctx context.Context, id, definition, ticket, by string) (*Confirmation, error) {
+	q := query("definition", definition)
+	if ticket != "" {
+		q.Set("ticket", ticket)
+	}
+	if by != "" {
+		q.Set("by", by)
+	}
+	var out Confirmation
+	return &out, c.do(ctx, http.MethodPost, pipelinePath(id, "confirmation"), q, &out)
+}
+
+// Rollout returns the staged rollout of a pipeline's definition
+func (c *Client) Rollout(ctx context.Context, id string) (*Rollout, error) {
+	var out Rollout
+	return &out, c.do(ctx, http.MethodGet, pipelinePath(id, "rollout"), nil, &out)
+}
+
+// PromoteRollout applies the changed definition of a staged rollout to all
+// keys without waiting for the soak to end
+func (c *Client) PromoteRollout(ctx context.Context, id string) (*Rollout, error) {
+	var out Rollout
+	return &out, c.do(ctx, http.MethodPost, pipelinePath(id, "rollout"), nil, &out)
+}
+
+// RollBack applies the stable definition of a staged rollout to all keys
+// until the definition changes again
+func (c *Client) RollBack(ctx context.Context, id string) (*Rollout, error) {
+	var out Rollout
+	return &out, c.do(ctx, http.MethodDelete, pipelinePath(id, "rollout"), nil, &out)
+}
+
+// LookupCaches returns the lookup caches of a pipeline's connectors
+func (c *Client) LookupCaches(ctx context.Context, id string) ([]LookupCacheState, error) {
+	var out []LookupCacheState
+	return out, c.do(ctx, http.MethodGet, pipelinePath(id, "lookup-cache"), nil, &out)
+}
+
+// ClearLookupCaches clears the lookup caches of a pipeline's connectors
+func (c *Client) ClearLookupCaches(ctx context.Context, id string) ([]LookupCacheState, error) {
+	var out []LookupCacheState
+	return out, c.do(ctx, http.MethodDelete, pipelinePath(id, "lookup-cache"), nil, &out)
+}
+
+// Holdback returns the holdback of a pipeline's target
+func (c *Client) Holdback(ctx context.Context, id string) (*Holdback, error) {
+	var out Holdback
+	return &out, c.do(ctx, http.MethodGet, pipelinePath(id, "holdback"), nil, &out)
+}
+
+// SetHoldback holds back writes of a pipeline to its target until released
+// or, when not zero, until the given time; reason and by describe who asked
+func (c *Client) SetHoldback(ctx context.Context, id, reason, by string, until time.Time) (*Holdback, error) {
+	q := url.Values{}
+	if reason != "" {
+		q.Set("reason", reason)
+	}
+	if by != "" {
+		q.Set("by", by)
+	}
+	if !until.IsZero() {
+		q.Set("until", until.UTC().Format(time.RFC3339))
+	}
+	var out Holdback
+	return &out, c.do(ctx, http.MethodPost, pipelinePath(id, "holdback"), q, &out)
+}
+
+// ReleaseHoldback releases the holdback of a pipeline's target; the
+// spooled records are applied in the background
+func (c *Client) ReleaseHoldback(ctx context.Context, id string) (*Holdback, error) {
+	var out Holdback
+	return &out, c.do(ctx, http.MethodDelete, pipelinePath(id, "holdback"), nil, &out)
+}
+
+// Trace returns the active verbose trace of a pipeline
+func (c *Client) Trace(ctx context.Context, id string) (*Trace, error) {
+	var out Trace
+	return &out, c.do(ctx, http.MethodGet, pipelinePath(id, "trace"), nil, &out)
+}
+
+// StartTrace traces a pipeline verbosely for d, or the server default when
+// zero, extending an active trace
+func (c *Client) StartTrace(ctx context.Context, id string, d time.Duration) (*Trace, error) {
+	var q url.Values
+	if d > 0 {
+		q = query("duration", d.String())
+	}
+	var out Trace
+	return &out, c.do(ctx, http.MethodPost, pipelinePath(id, "trace"), q, &out)
+}
+
+// StopTrace stops the verbose trace of a pipeline
+func (c *Client) StopTrace(ctx context.Context, id string) (*Trace, error) {
+	var out Trace
+	return &out, c.do(ctx, http.MethodDelete, pipelinePath(id, "trace"), nil, &out)
+}
+
+// Bulk applies pause, resume or trigger to every pipeline matching selector
+func (c *Client) Bulk(ctx context.Context, action, selector string) ([]BulkResult, error) {
+	var out []BulkResult
+	return out, c.do(ctx, http.MethodPost, "/bulk/"+url.PathEscape(action), query("selector", selector), &out)
+}
+
+// do performs a request and decodes the JSON response into out. Responses
+// with a 2xx status or one of accept are decoded; others become *Error.
+func (c *Client) do(ctx context.Context, method, path string, q url.Values, out interface{}, accept ...int) error {
+	resp, err := c.send(ctx, method, path, q, nil, accept...)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// send performs a request with an optional gzip body, returning the response
+// when its status is 2xx or one of accept and an *Error otherwise
+func (c *Client) send(ctx context.Context, method, path string, q url.Values, body io.Reader, accept ...int) (*http.Response, error) {
+	u := c.baseURL + path
+	if len(q) > 0 {
+		u += "?" + q.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if id, _ := ctx.Value(requestIDKey{}).(string); id != "" {
+		req.Header.Set("X-Request-ID", id)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/gzip")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	ok := resp.StatusCode >= 200 && resp.StatusCode < 300
+	for _, code := range accept {
+		ok = ok || resp.StatusCode == code
+	}
+	if ok {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	var apiErr struct {
+		Detail        string `json:"detail"`
+		Code          string `json:"code"`
+		CorrelationID string `json:"correlation_id"`
+		Error         string `json:"error"`
+	}
+	json.Unmarshal(data, &apiErr)
+	message := apiErr.Detail
+	if message == "" {
+		message = apiErr.Error
+	}
+	if message == "" {
+		message = strings.TrimSpace(string(data))
+	}
+	if apiErr.CorrelationID == "" {
+		apiErr.CorrelationID = resp.Header.Get("X-Correlation-ID")
+	}
+	return nil, &Error{StatusCode: resp.StatusCode, Code: apiErr.Code, Message: message, CorrelationID: apiErr.CorrelationID}
+}
+
+// Snapshot returns the last incremental snapshot of a pipeline
+func (c *Client) Snapshot(ctx context.Context, id string) (*IncrementalSnapshot, error) {
+	var out IncrementalSnapshot
+	return &out, c.do(ctx, http.MethodGet, pipelinePath(id, "snapshot"), nil, &out)
+}
+
+// RequestSnapshot re-snapshots the given tables and key range, all when
+// empty, in chunks interleaved with the pipeline's incremental stream
+func (c *Client) RequestSnapshot(ctx context.Context, id string, tables []string, keys KeyRange) (*IncrementalSnapshot, error) {
+	q := url.Values{}
+	if len(tables) > 0 {
+		q.Set("table", strings.Join(tables, ","))
+	}
+	if keys.Start != "" {
+		q.Set("start", keys.Start)
+	}
+	if keys.End != "" {
+		q.Set("end", keys.End)
+	}
+	var out IncrementalSnapshot
+	return &out, c.do(ctx, http.MethodPost, pipelinePath(id, "snapshot"), q, &out)
+}
+
+// CancelSnapshot stops the unfinished incremental snapshot of a pipeline
+func (c *Client) CancelSnapshot(ctx context.Context, id string) (*IncrementalSnapshot, error) {
+	var out IncrementalSnapshot
+	return &out, c.do(ctx, http.MethodDelete, pipelinePath(id, "snapshot"), nil, &out)
+}
+
+// Watch calls fn with the status transitions and run events of the
+// pipelines matching selector, starting with a status event per pipeline,
+// until ctx is cancelled, fn returns an error or the daemon ends the stream.
+// A stream ended by the daemon returns io.EOF; watch again to resume.
+func (c *Client) Watch(ctx context.Context, selector string, fn func(PipelineEvent) error) error {
+	stream := *c
+	hc := *c.http
+	hc.Timeout = 0
+	stream.http = &hc
+
+	resp, err := stream.send(ctx, http.MethodGet, "/pipelines/watch", query("selector", selector), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64<<10), 4<<20)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		var event PipelineEvent
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			return fmt.Errorf("failed to decode event: %w", err)
+		}
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return io.EOF
+}
+
+// pipelinePath builds /pipelines/{id}[/{resource}]
+func pipelinePath(id, resource string) string {
+	path := "/pipelines/" + url.PathEscape(id)
+	if resource != "" {
+		path += "/" + resource
+	}
+	return path
+}
+
+// query builds a query with a single optional parameter
+func query(key, value string) url.Values {
+	if value == "" {
+		return nil
+	}
+	return url.Values{key: {value}}
+}
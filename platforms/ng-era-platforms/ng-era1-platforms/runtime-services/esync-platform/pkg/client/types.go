@@ -0,0 +1,137 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: admin-api-client-options
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Admin API Client Options and Constants
+ */
+
+package client
+
+//go:generate go run ../../cmd/clientgen -root ../.. -go types_gen.go -ts ../../clients/typescript/src/types.ts
+
+// Memory pressure levels
+const (
+	MemoryNormal   = "normal"
+	MemoryElevated = "elevated"
+	MemoryCritical = "critical"
+)
+
+// PipelineQuery selects, orders and pages pipelines. Zero fields match
+// every pipeline.
+type PipelineQuery struct {
+	// Selector is a label selector such as team=payments,env!=prod
+	Selector string
+	// Labels are key:value terms that must all match; a bare key only
+	// requires the label to be present
+	Labels     []string
+	SourceType string
+	TargetType string
+	// Text matches IDs, descriptions, labels and connector types,
+	// ignoring case
+	Text string
+	// Sort is id, priority, source.type, target.type or mode, prefixed
+	// with - to sort descending
+	Sort   string
+	Offset int
+	Limit  int
+}
+
+// PipelinePage is one page of a pipeline search
+type PipelinePage struct {
+	Pipelines []Pipeline
+	// Total counts the matching pipelines across all pages
+	Total int
+}
+
+// CloneOptions sets what a clone changes of the original pipeline
+type CloneOptions struct {
+	// ID is the ID of the clone and names its definition file
+	ID               string
+	Description      string
+	SourceConnection string
+	TargetConnection string
+	// Labels are set on the clone; an empty value removes the label
+	Labels map[string]string
+}
+
+// Run references accepted by DiffRuns besides run IDs
+const (
+	RunLatest   = "latest"
+	RunLastGood = "last-good"
+)
+
+// Trigger states
+const (
+	TriggerRunning   = "running"
+	TriggerSucceeded = "succeeded"
+	TriggerFailed    = "failed"
+	TriggerRejected  = "rejected"
+)
+
+// Health levels of a pipeline by score
+const (
+	HealthHealthy   = "healthy"
+	HealthDegraded  = "degraded"
+	HealthUnhealthy = "unhealthy"
+	HealthPaused    = "paused"
+)
+
+// Event types streamed by Watch
+const (
+	EventStatus      = "status"
+	EventRunStarted  = "run_started"
+	EventRunFinished = "run_finished"
+	EventPaused      = "paused"
+	EventResumed     = "resumed"
+	// EventFailoverStarted and EventPromoted mark the failover of a
+	// standby pipeline
+	EventFailoverStarted = "failover_started"
+	EventPromoted        = "promoted"
+	// EventMemoryPressure marks a change of the daemon's memory pressure
+	// level; it has no pipeline
+	EventMemoryPressure = "memory_pressure"
+	// EventRollout marks a staged rollout starting, being promoted or
+	// rolled back; Reason is its phase
+	EventRollout = "rollout"
+	// EventHeldBack and EventReleased mark a holdback of a pipeline's target
+	// being set and released; Reason is the reason given
+	EventHeldBack = "held_back"
+	EventReleased = "released"
+)
+
+// Standby phases
+const (
+	StandbyPriming  = "priming"
+	StandbyApplying = "applying"
+	StandbyPromoted = "promoted"
+	StandbyFailed   = "failed"
+)
+
+// Rollout phases
+const (
+	RolloutStable     = "stable"
+	RolloutSoaking    = "soaking"
+	RolloutRolledBack = "rolled_back"
+)
+
+// Former names of generated types, which now carry the names of the admin
+// API schemas
+type (
+	// Deprecated: use PipelineHealth
+	Health = PipelineHealth
+	// Deprecated: use LookupCacheState
+	LookupCache = LookupCacheState
+	// Deprecated: use PipelineEvent
+	Event = PipelineEvent
+	// Deprecated: use SyncRecord
+	Record = SyncRecord
+)
@@ -0,0 +1,249 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: public-connector-api
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Public Connector API
+ */
+
+// Package connectors is the stable API for building connectors outside
+// this repository. A connector implements Connector, plus any of the
+// optional interfaces the engine detects, and registers its type with the
+// JSON Schema of its config block:
+//
+//	func init() {
+//		connectors.Register("crm", func(config map[string]interface{}) (connectors.Connector, error) {
+//			return newCRM(config)
+//		}, crmSchema)
+//	}
+//
+// The package follows semantic versioning with the module, as described in
+// docs/adr/0002-public-go-api.md: within a major version its identifiers
+// are neither removed nor changed incompatibly, and new optional
+// interfaces never become required. The built-in connector types are
+// registered by importing pkg/engine.
+package connectors
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/machine-native-ops/esync-platform/internal/cloudauth"
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/oauth"
+)
+
+// Core connector types
+type (
+	Connector        = connectors.Connector
+	Factory          = connectors.Factory
+	Type             = connectors.Type
+	Record           = connectors.Record
+	Checkpoint       = connectors.Checkpoint
+	ValidationResult = connectors.ValidationResult
+	VectorClock      = connectors.VectorClock
+	Ordering         = connectors.Ordering
+)
+
+// Record operations
+const (
+	OperationInsert    = connectors.OperationInsert
+	OperationUpdate    = connectors.OperationUpdate
+	OperationDelete    = connectors.OperationDelete
+	OperationPatch     = connectors.OperationPatch
+	OperationHeartbeat = connectors.OperationHeartbeat
+)
+
+// Orderings of vector clocks
+const (
+	OrderEqual      = connectors.OrderEqual
+	OrderBefore     = connectors.OrderBefore
+	OrderAfter      = connectors.OrderAfter
+	OrderConcurrent = connectors.OrderConcurrent
+)
+
+// Optional interfaces the engine detects on connectors, and their types
+type (
+	Estimator        = connectors.Estimator
+	KeyRange         = connectors.KeyRange
+	RangeReader      = connectors.RangeReader
+	CheckResult      = connectors.CheckResult
+	Preflighter      = connectors.Preflighter
+	Field            = connectors.Field
+	Schema           = connectors.Schema
+	SchemaProvider   = connectors.SchemaProvider
+	IdempotentWriter = connectors.IdempotentWriter
+	IDAssigner       = connectors.IDAssigner
+	RecordReader     = connectors.RecordReader
+	PatchWriter      = connectors.PatchWriter
+	TableReferencer  = connectors.TableReferencer
+	DDLEvent         = connectors.DDLEvent
+	DDLSource        = connectors.DDLSource
+	DDLApplier       = connectors.DDLApplier
+	BootstrapRequest = connectors.BootstrapRequest
+	Bootstrapper     = connectors.Bootstrapper
+	TeardownRequest  = connectors.TeardownRequest
+	Teardowner       = connectors.Teardowner
+	Resource         = connectors.Resource
+	ResourceLister   = connectors.ResourceLister
+	Credentials      = connectors.Credentials
+	Reconfigurer     = connectors.Reconfigurer
+	CanaryWriter     = connectors.CanaryWriter
+	Acknowledger     = connectors.Acknowledger
+	SourceClock      = connectors.SourceClock
+	MetricsReporter  = connectors.MetricsReporter
+	Instrumented     = connectors.Instrumented
+	LookupCacheStats = connectors.LookupCacheStats
+	LookupCache      = connectors.LookupCache
+	LookupCacher     = connectors.LookupCacher
+	Limiter          = connectors.Limiter
+	Limited          = connectors.Limited
+)
+
+// Check statuses reported by pre-flight checks
+const (
+	CheckPassed  = connectors.CheckPassed
+	CheckFailed  = connectors.CheckFailed
+	CheckSkipped = connectors.CheckSkipped
+)
+
+// Kinds of captured schema changes
+const (
+	DDLAddColumn   = connectors.DDLAddColumn
+	DDLAlterColumn = connectors.DDLAlterColumn
+	DDLDropColumn  = connectors.DDLDropColumn
+	DDLOther       = connectors.DDLOther
+)
+
+// Key digests for connectors that compare key sets without full dumps
+type (
+	Key         = connectors.Key
+	KeyDigest   = connectors.KeyDigest
+	KeyDigester = connectors.KeyDigester
+)
+
+// OAuth2 helpers for connectors of REST APIs
+type (
+	OAuthConfig       = oauth.Config
+	OAuthToken        = oauth.Token
+	TokenSource       = oauth.TokenSource
+	OAuthRoundTripper = oauth.Transport
+)
+
+// Cloud provider authentication for connectors of cloud APIs
+type (
+	CloudAuthSpec      = cloudauth.Spec
+	CloudAuthenticator = cloudauth.Authenticator
+	CloudAuthTransport = cloudauth.Transport
+	AWSCredentials     = cloudauth.AWSCredentials
+)
+
+// ErrUnsupported is returned by optional methods a connector implements only
+// conditionally, such as plugins lacking a capability
+var ErrUnsupported = connectors.ErrUnsupported
+
+// ErrRejected is wrapped by the errors of targets that rejected a batch of
+// changes without writing any of it, so retrying it unchanged cannot succeed
+var ErrRejected = connectors.ErrRejected
+
+// Register makes a connector type available to pipelines. configSchema is
+// the JSON Schema of the connector's config block, which pipelines are
+// validated against and synctl connector describe renders. Registering a
+// type twice or without a valid schema panics.
+func Register(connectorType string, factory Factory, configSchema []byte) {
+	connectors.Register(connectorType, connectors.WithSchema(factory, configSchema))
+}
+
+// New creates a connector of a registered type
+func New(connectorType string, config map[string]interface{}) (Connector, error) {
+	return connectors.New(connectorType, config)
+}
+
+// Types returns the registered connector types
+func Types() []string {
+	return connectors.Types()
+}
+
+// ConfigSchema returns the config schema of a registered connector type
+func ConfigSchema(connectorType string) ([]byte, bool) {
+	return connectors.ConfigSchema(connectorType)
+}
+
+// ValidateConfig checks a connector config block against the schema of its
+// type. Unregistered types pass; creating their connector fails instead.
+func ValidateConfig(connectorType string, config map[string]interface{}) error {
+	return connectors.ValidateConfig(connectorType, config)
+}
+
+// EncodeKey encodes the ordered values of a composite primary key as a
+// record ID, as the engine does for records naming their key fields
+func EncodeKey(values []interface{}) (string, error) {
+	return connectors.EncodeKey(values)
+}
+
+// DecodeKey returns the n values a record ID encodes
+func DecodeKey(id string, n int) ([]interface{}, error) {
+	return connectors.DecodeKey(id, n)
+}
+
+// KeyHash returns the hash of a key that key digests are built from
+func KeyHash(k Key) string {
+	return connectors.KeyHash(k)
+}
+
+// DigestKeys computes the digests of hash prefixes over a key set, for
+// implementing KeyDigester over keys a connector can list cheaply
+func DigestKeys(keys []Key, prefixes []string) []KeyDigest {
+	return connectors.DigestKeys(keys, prefixes)
+}
+
+// KeysWithPrefix returns the keys whose hash starts with prefix, for
+// implementing KeyDigester.HashKeys
+func KeysWithPrefix(keys []Key, prefix string) []Key {
+	return connectors.KeysWithPrefix(keys, prefix)
+}
+
+// Acquire waits for the limiters the engine attached to ctx and then for
+// own, which may be nil. Connectors call it before each call to their
+// endpoint and the returned func once the call ends.
+func Acquire(ctx context.Context, own Limiter) (func(), error) {
+	return connectors.Acquire(ctx, own)
+}
+
+// NewTokenSource creates a cached, proactively refreshed OAuth2 token
+// source for a connector; Client of the source returns an HTTP client
+// authenticating with it
+func NewTokenSource(config *OAuthConfig) (*TokenSource, error) {
+	return oauth.NewTokenSource(config)
+}
+
+// OAuthFromConfig reads an OAuthConfig from the "oauth" block of a
+// connector config
+func OAuthFromConfig(config map[string]interface{}) (*OAuthConfig, error) {
+	return oauth.FromConfig(config)
+}
+
+// NewCloudAuth creates the authenticator of the "cloud_auth" block of a
+// connector config, which connection profiles fill from their cloud_auth
+// setting; wrap it in a CloudAuthTransport to authenticate HTTP clients
+func NewCloudAuth(config map[string]interface{}) (CloudAuthenticator, error) {
+	spec, err := cloudauth.FromConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return cloudauth.New(spec)
+}
+
+// SignV4 signs an AWS request with Signature Version 4
+func SignV4(req *http.Request, body []byte, creds *AWSCredentials, region, service string) {
+	cloudauth.SignV4(req, body, creds, region, service, time.Now())
+}
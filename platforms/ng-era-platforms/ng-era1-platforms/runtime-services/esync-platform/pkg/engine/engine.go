@@ -0,0 +1,115 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: public-engine-api
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Public Engine API
+ */
+
+// Package engine is the stable API for embedding the sync engine and
+// inspecting its runs. Pipelines come from pkg/pipeline and connectors
+// from pkg/connectors; importing the package registers the built-in
+// connector types.
+//
+//	eng := engine.New(engine.Options{StateDir: "/var/lib/orders"}).
+//		AddPipeline(&pipeline.Pipeline{ID: "orders", Source: src, Target: dst})
+//	err := eng.Run(ctx)
+//
+// The package follows semantic versioning with the module, as described in
+// docs/adr/0002-public-go-api.md. Within a major version Options only
+// gains fields whose zero value keeps the current behavior, and the
+// methods of Engine and the fields of Run are neither removed nor changed
+// incompatibly. It is the only public entry point to the engine; syncd
+// runs the same one.
+package engine
+
+import (
+	"github.com/machine-native-ops/esync-platform/internal/engine"
+	"github.com/machine-native-ops/esync-platform/internal/esync"
+	"github.com/machine-native-ops/esync-platform/internal/idgen"
+)
+
+// Engine is an embeddable sync engine. Configuration errors from the
+// chaining methods are reported by Start or Run.
+type Engine = esync.Engine
+
+// Options configures an embedded engine. Zero values disable the optional
+// servers, so an embedding service only gets what it asks for.
+type Options = esync.Options
+
+// Retention bounds the run history, audit log, erasure requests and
+// fixtures kept
+type Retention = esync.Retention
+
+// Run results
+type (
+	Run            = engine.Run
+	Trigger        = engine.Trigger
+	Progress       = engine.Progress
+	CheckpointMove = engine.CheckpointMove
+	Verification   = engine.Verification
+	ErrorGroup     = engine.ErrorGroup
+	CleanupResult  = engine.CleanupResult
+	RunSchema      = engine.RunSchema
+)
+
+// Run statuses
+const (
+	StatusRunning   = engine.StatusRunning
+	StatusSucceeded = engine.StatusSucceeded
+	StatusFailed    = engine.StatusFailed
+)
+
+// Trigger types
+const (
+	TriggerManual       = engine.TriggerManual
+	TriggerSchedule     = engine.TriggerSchedule
+	TriggerWebhook      = engine.TriggerWebhook
+	TriggerKafka        = engine.TriggerKafka
+	TriggerPipeline     = engine.TriggerPipeline
+	TriggerBackfill     = engine.TriggerBackfill
+	TriggerReconnect    = engine.TriggerReconnect
+	TriggerSharedSource = engine.TriggerSharedSource
+	TriggerHoldback     = engine.TriggerHoldback
+)
+
+// Self-test results
+type (
+	SelfTestReport   = esync.SelfTestReport
+	PipelineSelfTest = esync.PipelineSelfTest
+)
+
+// DefaultHandoffLease is the HandoffLease used when none is set
+const DefaultHandoffLease = esync.DefaultHandoffLease
+
+// DefaultErrorLogBurst and DefaultErrorLogWindow are the error log limits
+// used when ErrorLogBurst and ErrorLogWindow are not set
+const (
+	DefaultErrorLogBurst  = esync.DefaultErrorLogBurst
+	DefaultErrorLogWindow = esync.DefaultErrorLogWindow
+)
+
+// ErrNotStarted is returned by calls that need a started engine
+var ErrNotStarted = esync.ErrNotStarted
+
+// New creates an embedded engine
+func New(opts Options) *Engine {
+	return esync.New(opts)
+}
+
+// IDGenerator creates the target IDs of key-mapped pipelines
+type IDGenerator = idgen.Generator
+
+// RegisterIDGenerator makes an ID generator available to the key_mapping
+// blocks of pipelines; factory receives the key_mapping node ID
+func RegisterIDGenerator(name string, factory func(node int) (IDGenerator, error)) {
+	idgen.Register(name, factory)
+}
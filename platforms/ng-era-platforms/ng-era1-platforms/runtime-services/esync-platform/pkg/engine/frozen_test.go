@@ -0,0 +1,117 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: public-api-tests
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Public Go API Tests
+ */
+
+package engine_test
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// module is the import path of the module
+const module = "github.com/machine-native-ops/esync-platform/"
+
+// declaration is a type declared by a package
+type declaration struct {
+	doc string
+	// target is the import path and name of the type an alias refers to
+	target [2]string
+}
+
+// TestAliasesFrozen fails when a public package aliases an internal type
+// whose doc comment does not mark it frozen under the public name, as
+// docs/adr/0002-public-go-api.md requires
+func TestAliasesFrozen(t *testing.T) {
+	decls := make(map[string]map[string]declaration)
+	load := func(path string) map[string]declaration {
+		if _, ok := decls[path]; !ok {
+			d, err := declarations(filepath.Join("..", "..", filepath.FromSlash(strings.TrimPrefix(path, module))))
+			if err != nil {
+				t.Fatal(err)
+			}
+			decls[path] = d
+		}
+		return decls[path]
+	}
+
+	for _, public := range []string{"pkg/connectors", "pkg/pipeline", "pkg/engine"} {
+		name := filepath.Base(public)
+		for alias, decl := range load(module + public) {
+			target := decl.target
+			for target[0] != "" {
+				d, ok := load(target[0])[target[1]]
+				if !ok {
+					t.Fatalf("%s.%s aliases %s.%s, which does not exist", name, alias, target[0], target[1])
+				}
+				if d.target[0] == "" {
+					if !strings.Contains(d.doc, "Frozen: public as ") || !strings.Contains(d.doc, name+"."+alias) {
+						t.Errorf("%s.%s aliases %s.%s, whose doc comment lacks \"Frozen: public as %s.%s\"", name, alias, target[0], target[1], name, alias)
+					}
+				}
+				target = d.target
+			}
+		}
+	}
+}
+
+// declarations reads the type declarations of a package directory
+func declarations(dir string) (map[string]declaration, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	decls := make(map[string]declaration)
+	for _, p := range pkgs {
+		for _, f := range p.Files {
+			imports := make(map[string]string)
+			for _, im := range f.Imports {
+				path, _ := strconv.Unquote(im.Path.Value)
+				imports[filepath.Base(path)] = path
+			}
+			for _, d := range f.Decls {
+				gd, ok := d.(*ast.GenDecl)
+				if !ok || gd.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range gd.Specs {
+					ts := spec.(*ast.TypeSpec)
+					doc := ts.Doc
+					if doc == nil && len(gd.Specs) == 1 {
+						doc = gd.Doc
+					}
+					decl := declaration{doc: doc.Text()}
+					if sel, ok := ts.Type.(*ast.SelectorExpr); ok && ts.Assign.IsValid() {
+						if x, ok := sel.X.(*ast.Ident); ok && strings.HasPrefix(imports[x.Name], module+"internal/") {
+							decl.target = [2]string{imports[x.Name], sel.Sel.Name}
+						}
+					}
+					decls[ts.Name.Name] = decl
+				}
+			}
+		}
+	}
+	return decls, nil
+}
@@ -0,0 +1,258 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: golden-tests
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Golden-File Pipeline Tests
+ */
+
+// Package esynctest runs golden-file tests of pipeline definitions, so
+// teams can ship tests next to their pipelines. Every directory holding a
+// pipeline.yaml is a test case:
+//
+//	pipeline.yaml   the pipeline definition
+//	input.json      the records listed from the source, or a fixture
+//	                recorded with synctl record
+//	existing.json   optional records already at the target
+//	schema.json     optional target schema
+//	expected.json   the golden output
+//
+// The input is replayed through the pipeline's transforms, coercion and
+// apply path against an in-memory target, without connecting. A test
+// discovers every case below a directory:
+//
+//	func TestPipelines(t *testing.T) {
+//		esynctest.Run(t, "pipelines")
+//	}
+//
+// Run the tests with ESYNC_UPDATE_GOLDEN=1 to write expected.json from the
+// current output instead of comparing against it.
+package esynctest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/machine-native-ops/esync-platform/internal/connectors"
+	"github.com/machine-native-ops/esync-platform/internal/engine"
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+	"github.com/machine-native-ops/esync-platform/internal/replay"
+)
+
+// Files of a test case
+const (
+	PipelineFile = "pipeline.yaml"
+	InputFile    = "input.json"
+	ExistingFile = "existing.json"
+	SchemaFile   = "schema.json"
+	ExpectedFile = "expected.json"
+)
+
+// UpdateEnv is the environment variable that, when set, rewrites the
+// expected output of every case
+const UpdateEnv = "ESYNC_UPDATE_GOLDEN"
+
+// Golden is the expected output of a test case
+type Golden struct {
+	// Written is the number of records that passed transforms and
+	// validation
+	Written int `json:"written"`
+	// Applied holds the records written to the target, in apply order
+	Applied []connectors.Record `json:"applied"`
+	// Records holds the target contents afterwards, ordered by ID
+	Records []connectors.Record `json:"records"`
+	// Errors lists the record errors raised, as "type: message"
+	Errors []string `json:"errors,omitempty"`
+	// Error is the error the pass failed with
+	Error string `json:"error,omitempty"`
+}
+
+// Run runs every test case below dir as a subtest named by its path
+func Run(t *testing.T, dir string) {
+	t.Helper()
+
+	var cases []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && d.Name() == PipelineFile {
+			cases = append(cases, filepath.Dir(path))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to discover test cases: %v", err)
+	}
+	if len(cases) == 0 {
+		t.Fatalf("no test cases below %s: a case is a directory holding %s", dir, PipelineFile)
+	}
+
+	for _, c := range cases {
+		name, err := filepath.Rel(dir, c)
+		if err != nil {
+			name = c
+		}
+		c := c
+		t.Run(filepath.ToSlash(name), func(t *testing.T) {
+			RunCase(t, c)
+		})
+	}
+}
+
+// RunCase runs the test case in dir
+func RunCase(t *testing.T, dir string) {
+	t.Helper()
+
+	f, err := load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := replay.Run(context.Background(), f, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := golden(result)
+
+	expected := filepath.Join(dir, ExpectedFile)
+	if os.Getenv(UpdateEnv) != "" {
+		data, err := json.MarshalIndent(got, "", "  ")
+		if err == nil {
+			err = os.WriteFile(expected, append(data, '\n'), 0o644)
+		}
+		if err != nil {
+			t.Fatalf("failed to write %s: %v", expected, err)
+		}
+		t.Logf("wrote %s", expected)
+		return
+	}
+
+	var want Golden
+	if err := readJSON(expected, &want); errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("missing %s; run with %s=1 to create it", expected, UpdateEnv)
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range compare(&want, got) {
+		t.Error(d)
+	}
+}
+
+// load builds the fixture of a test case from its files
+func load(dir string) (*engine.Fixture, error) {
+	data, err := os.ReadFile(filepath.Join(dir, PipelineFile))
+	if err != nil {
+		return nil, err
+	}
+	p, err := registry.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", PipelineFile, err)
+	}
+
+	f := &engine.Fixture{Format: engine.FixtureFormat, PipelineID: p.ID}
+	input, err := os.ReadFile(filepath.Join(dir, InputFile))
+	if err != nil {
+		return nil, err
+	}
+	if trimmed := bytes.TrimSpace(input); len(trimmed) > 0 && trimmed[0] == '{' {
+		// A recorded fixture brings its input, existing records and schema;
+		// the case's pipeline replaces the recorded definition
+		if err := json.Unmarshal(input, f); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", InputFile, err)
+		}
+	} else if err := json.Unmarshal(input, &f.Input); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", InputFile, err)
+	}
+	f.Pipeline = p
+
+	if err := readJSON(filepath.Join(dir, ExistingFile), &f.Existing); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	if err := readJSON(filepath.Join(dir, SchemaFile), &f.Schema); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	return f, nil
+}
+
+// golden reduces a replay result to its deterministic parts
+func golden(result *replay.Result) *Golden {
+	g := &Golden{
+		Written: result.Written,
+		Applied: result.Applied,
+		Records: result.Records,
+		Error:   result.Error,
+	}
+	for _, group := range result.Errors {
+		g.Errors = append(g.Errors, group.Type+": "+group.Message)
+	}
+	sort.Strings(g.Errors)
+	return g
+}
+
+// compare describes every difference between the expected and actual output
+func compare(want, got *Golden) []string {
+	var diff []string
+	if want.Written != got.Written {
+		diff = append(diff, fmt.Sprintf("written: want %d, got %d", want.Written, got.Written))
+	}
+	for _, d := range replay.Diff(want.Applied, got.Applied) {
+		diff = append(diff, "applied "+d)
+	}
+	for _, d := range replay.Diff(want.Records, got.Records) {
+		diff = append(diff, "target "+d)
+	}
+
+	errs := make(map[string]int)
+	for _, e := range want.Errors {
+		errs[e]++
+	}
+	for _, e := range got.Errors {
+		errs[e]--
+	}
+	keys := make([]string, 0, len(errs))
+	for e := range errs {
+		keys = append(keys, e)
+	}
+	sort.Strings(keys)
+	for _, e := range keys {
+		switch n := errs[e]; {
+		case n > 0:
+			diff = append(diff, "missing error "+e)
+		case n < 0:
+			diff = append(diff, "unexpected error "+e)
+		}
+	}
+
+	if want.Error != got.Error {
+		diff = append(diff, fmt.Sprintf("error: want %q, got %q", want.Error, got.Error))
+	}
+	return diff
+}
+
+// readJSON decodes a JSON file into v
+func readJSON(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("invalid %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
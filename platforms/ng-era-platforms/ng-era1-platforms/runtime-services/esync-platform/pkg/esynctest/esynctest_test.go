@@ -0,0 +1,27 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: golden-tests-suite
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Golden-File Pipeline Test Cases
+ */
+
+package esynctest_test
+
+import (
+	"testing"
+
+	"github.com/machine-native-ops/esync-platform/pkg/esynctest"
+)
+
+func TestPipelines(t *testing.T) {
+	esynctest.Run(t, "testdata")
+}
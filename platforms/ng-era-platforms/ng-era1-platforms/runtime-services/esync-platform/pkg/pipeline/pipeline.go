@@ -0,0 +1,149 @@
+// @GL-governed
+// @GL-layer: GL10-29
+// @GL-semantic: esync-platform-source
+// @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+//
+// GL Unified Charter Activated
+/**
+ * @GL-governed
+ * @GL-layer: esync-platform
+ * @GL-semantic: public-pipeline-api
+ * @GL-audit-trail: ../../engine/governance/GL_SEMANTIC_ANCHOR.json
+ *
+ * GL Unified Charter Activated
+ * Public Pipeline API
+ */
+
+// Package pipeline is the stable API for reading, checking and building
+// pipeline definitions in tooling, such as linters and generators of
+// pipeline files:
+//
+//	p, err := pipeline.Parse(data)
+//	if err == nil {
+//		err = p.Validate()
+//	}
+//
+// The Go types follow semantic versioning with the module, as described in
+// docs/adr/0002-public-go-api.md. The YAML format they decode is versioned
+// separately by its apiVersion; Parse migrates older versions.
+package pipeline
+
+import (
+	"github.com/machine-native-ops/esync-platform/internal/registry"
+)
+
+// Versions of the pipeline YAML format
+const (
+	APIVersionV1Alpha1 = registry.APIVersionV1Alpha1
+	APIVersionV1       = registry.APIVersionV1
+	CurrentAPIVersion  = registry.CurrentAPIVersion
+)
+
+// SchemaID identifies the published JSON Schema of the pipeline format
+const SchemaID = registry.SchemaID
+
+// Pipeline modes
+const (
+	ModeSync      = registry.ModeSync
+	ModeMigration = registry.ModeMigration
+	ModeStandby   = registry.ModeStandby
+	ModeCleanup   = registry.ModeCleanup
+)
+
+// Run policies applied when a run is triggered while another is in progress
+const (
+	RunPolicyCoalesce = registry.RunPolicyCoalesce
+	RunPolicyQueue    = registry.RunPolicyQueue
+	RunPolicyReject   = registry.RunPolicyReject
+)
+
+// Pipeline is a pipeline definition
+type Pipeline = registry.Pipeline
+
+// Spec types of the sections of a pipeline definition
+type (
+	ConnectorSpec       = registry.ConnectorSpec
+	CutoverSpec         = registry.CutoverSpec
+	StandbySpec         = registry.StandbySpec
+	CleanupSpec         = registry.CleanupSpec
+	PrimaryKeySpec      = registry.PrimaryKeySpec
+	KeyMappingSpec      = registry.KeyMappingSpec
+	ScheduleSpec        = registry.ScheduleSpec
+	ActiveHoursSpec     = registry.ActiveHoursSpec
+	HoursWindow         = registry.HoursWindow
+	TriggerSpec         = registry.TriggerSpec
+	PreflightSpec       = registry.PreflightSpec
+	BackfillSpec        = registry.BackfillSpec
+	BackfillBudget      = registry.BackfillBudget
+	TransformSpec       = registry.TransformSpec
+	VerifySpec          = registry.VerifySpec
+	SLOSpec             = registry.SLOSpec
+	WatchdogSpec        = registry.WatchdogSpec
+	CanarySpec          = registry.CanarySpec
+	HeartbeatSpec       = registry.HeartbeatSpec
+	ErrorLogSpec        = registry.ErrorLogSpec
+	ClockSkewSpec       = registry.ClockSkewSpec
+	RecordLimitsSpec    = registry.RecordLimitsSpec
+	SharedSourceSpec    = registry.SharedSourceSpec
+	SourceLoadSpec      = registry.SourceLoadSpec
+	RolloutSpec         = registry.RolloutSpec
+	FreshnessMarkerSpec = registry.FreshnessMarkerSpec
+	PostRunActionSpec   = registry.PostRunActionSpec
+	OutboxSpec          = registry.OutboxSpec
+	CallLimits          = registry.CallLimits
+	BandwidthSpec       = registry.BandwidthSpec
+	BandwidthProfile    = registry.BandwidthProfile
+	CoercionSpec        = registry.CoercionSpec
+	FieldCoercion       = registry.FieldCoercion
+	ApplyOrderSpec      = registry.ApplyOrderSpec
+	TableSelectionSpec  = registry.TableSelectionSpec
+	DDLSpec             = registry.DDLSpec
+	ResidencySpec       = registry.ResidencySpec
+	ErasureSpec         = registry.ErasureSpec
+	RouteSpec           = registry.RouteSpec
+)
+
+// Connection profiles shared by pipelines
+type (
+	Connection    = registry.Connection
+	TLSSpec       = registry.TLSSpec
+	TunnelSpec    = registry.TunnelSpec
+	CloudAuthSpec = registry.CloudAuthSpec
+	PoolSpec      = registry.PoolSpec
+)
+
+// Change is a pipeline added, updated or removed from a registry; Pipeline
+// is nil when it was removed
+type Change = registry.Change
+
+// Kinds of pipeline changes
+const (
+	ChangeAdded   = registry.ChangeAdded
+	ChangeUpdated = registry.ChangeUpdated
+	ChangeRemoved = registry.ChangeRemoved
+)
+
+// Selector matches pipelines by label
+type Selector = registry.Selector
+
+// ErrTooLarge is returned for definitions beyond the loading limits
+var ErrTooLarge = registry.ErrTooLarge
+
+// Parse decodes a pipeline definition, migrating older versions of the
+// format; the pipeline's Warnings list what the migration dropped or
+// reinterpreted
+func Parse(data []byte) (*Pipeline, error) {
+	return registry.Parse(data)
+}
+
+// ParseSelector parses a comma-separated label selector such as
+// "team=payments,env!=prod,tier". An empty string selects every pipeline.
+func ParseSelector(s string) (Selector, error) {
+	return registry.ParseSelector(s)
+}
+
+// JSONSchema returns the JSON Schema of the pipeline YAML format, for
+// editors and linters
+func JSONSchema() ([]byte, error) {
+	return registry.GenerateSchema()
+}